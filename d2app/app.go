@@ -16,21 +16,25 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2logger"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2gui"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapstamp"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2screen"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
 	"github.com/OpenDiablo2/OpenDiablo2/d2game/d2gamescreen"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2client"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2client/d2clientconnectiontype"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2server"
 	"github.com/OpenDiablo2/OpenDiablo2/d2script"
 	"github.com/pkg/profile"
 	"golang.org/x/image/colornames"
@@ -92,9 +96,35 @@ func Create(gitBranch, gitCommit string,
 
 // Run executes the application and kicks off the entire game process
 func (p *App) Run() error {
+	defer p.recoverFromPanic()
+
 	profileOption := kingpin.Flag("profile", "Profiles the program, one of (cpu, mem, block, goroutine, trace, thread, mutex)").String()
+	seedOption := kingpin.Flag("seed",
+		"Generates the maps of every game with the seed, to lay out the levels the same on each run").Int64()
+	presetOption := kingpin.Flag("preset",
+		"Forces the file picked for a level preset, as <preset id>=<file index>").StringMap()
+	hotReloadOption := kingpin.Flag("hotreload",
+		"Reloads the files of the override directory when they change, in local builds").Bool()
+	recordInputOption := kingpin.Flag("recordinput",
+		"Records the input and the map seed to the file, to reproduce a bug with --playinput").String()
+	playInputOption := kingpin.Flag("playinput",
+		"Plays the input recorded to the file with --recordinput back, with the map seed it was recorded with").String()
+	verifyOption := kingpin.Flag("verify",
+		"Checks the MPQs of the installation and reports the missing or broken files, then exits").Bool()
 	kingpin.Parse()
 
+	if *verifyOption {
+		return verifyInstallation()
+	}
+
+	d2server.PinSeed(p.setUpInputRecording(*recordInputOption, *playInputOption, *seedOption))
+
+	for preset, file := range *presetOption {
+		if err := forcePresetFile(preset, file); err != nil {
+			log.Printf("invalid preset option %s=%s: %v", preset, file, err)
+		}
+	}
+
 	if len(*profileOption) > 0 {
 		profiler := enableProfiler(*profileOption)
 		if profiler != nil {
@@ -112,6 +142,15 @@ func (p *App) Run() error {
 		return err
 	}
 
+	// Release builds are stamped with their branch
+	if *hotReloadOption && p.gitBranch == "" {
+		if err := d2asset.WatchOverrides(); err != nil {
+			log.Printf("failed to watch the override directory: %v", err)
+		}
+	} else if *hotReloadOption {
+		log.Println("hot reload is only available in local builds")
+	}
+
 	p.ToMainMenu()
 
 	if p.gitBranch == "" {
@@ -127,6 +166,52 @@ func (p *App) Run() error {
 	return nil
 }
 
+// verifyInstallation prints which archives and required files of the installation are missing or broken, and returns
+// an error if any is.
+func verifyInstallation() error {
+	report := d2asset.VerifyInstallation(d2config.Config)
+	fmt.Print(report.Summary())
+
+	if !report.OK() {
+		return errors.New("the installation is incomplete")
+	}
+
+	return nil
+}
+
+// setUpInputRecording starts recording the input to the record file, or playing back the input of the play file, and
+// returns the map seed to pin. A recording pins a seed so it can be played back on the same maps.
+func (p *App) setUpInputRecording(record, play string, seed int64) int64 {
+	if play != "" {
+		recordedSeed, err := p.inputManager.PlayInput(play)
+		if err != nil {
+			log.Printf("failed to play the input recording %s: %v", play, err)
+			return seed
+		}
+
+		log.Printf("playing the input recording %s back, with the map seed %d", play, recordedSeed)
+
+		return recordedSeed
+	}
+
+	if record == "" {
+		return seed
+	}
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	if err := p.inputManager.RecordInput(record, seed); err != nil {
+		log.Printf("failed to record the input to %s: %v", record, err)
+		return seed
+	}
+
+	log.Printf("recording the input to %s, with the map seed %d", record, seed)
+
+	return seed
+}
+
 func (p *App) initialize() error {
 	p.timeScale = 1.0
 	p.lastTime = d2common.Now()
@@ -145,10 +230,29 @@ func (p *App) initialize() error {
 		{"capgifstart", "captures an animation (start)", p.startAnimationCapture},
 		{"capgifstop", "captures an animation (stop)", p.stopAnimationCapture},
 		{"vsync", "toggles vsync", p.toggleVsync},
+		{"fpscap", "limits the frames drawn per second, 0 for no limit", p.setFrameCap},
 		{"fps", "toggle fps counter", p.toggleFpsCounter},
+		{"players", "scales the monsters as if <n> players (1 to 8) were in the game", p.setPlayers},
+		{"density", "multiplies the number of monsters spawned", p.setMonsterDensity},
+		{"autopickup", "toggles picking up gold and the configured item types by walking near them", p.toggleAutoPickup},
+		{"lootmode", "set who can pick up drops in hosted games (freeforall, allocated)", p.setLootMode},
+		{"itemlabels", "set whether Alt shows the item labels while held or toggles them (hold, toggle)", p.setItemLabels},
+		{"movemode", "set how clicks move the hero (classic, responsive)", p.setMovementMode},
+		{"wallslide", "toggles sliding along walls when moving the hero directly into them", p.toggleWallSliding},
+		{"filtering", "set how the map is sampled when zoomed out (nearest, linear)", p.setFilteringMode},
+		{"deathpush", "set how corpses slide as monsters die (off, facing, away)", p.setDeathPushMode},
+		{"hitflash", "toggles monsters and players flashing when hit", p.toggleHitFlash},
+		{"screenflash", "toggles the full screen flashes, such as being blinded", p.toggleScreenFlash},
+		{"flashintensity", "set the strength of the flashing effects, from 0 for none to 1 for full", p.setFlashIntensity},
+		{"splitgold", "toggles sharing the gold picked up among the players in hosted games", p.toggleSplitGold},
+		{"seed", "pins the map seed of the next games to lay out the levels the same, 0 for a new seed each game", p.pinSeed},
+		{"forcepreset", "forces the file of a level preset: forcepreset <preset id> <file index>", p.forcePreset},
+		{"clearpresets", "lets every level preset pick a random file again", p.clearPresets},
 		{"timescale", "set scalar for elapsed time", p.setTimeScale},
+		{"loglevel", "set the log level (debug, info, warning, error)", p.setLogLevel},
 		{"quit", "exits the game", p.quitGame},
 		{"screen-gui", "enters the gui playground screen", p.enterGuiPlayground},
+		{"benchmark", "runs the stress scene: benchmark <monsters> <missiles> <seconds>", p.runBenchmark},
 		{"js", "eval JS scripts", p.evalJS},
 	}
 
@@ -169,6 +273,7 @@ func (p *App) initialize() error {
 	}
 
 	p.audio.SetVolumes(config.BgmVolume, config.SfxVolume)
+	p.audio.SetDucking(config.Ducking.GetAmount(), config.Ducking.GetAttack(), config.Ducking.GetRelease())
 
 	if err := p.loadDataDict(); err != nil {
 		return err
@@ -240,6 +345,7 @@ func (p *App) loadDataDict() error {
 		{d2resource.SuperUniques, d2datadict.LoadSuperUniques},
 		{d2resource.Inventory, d2datadict.LoadInventory},
 		{d2resource.Skills, d2datadict.LoadSkills},
+		{d2resource.SkillDesc, d2datadict.LoadSkillDescriptions},
 		{d2resource.Properties, d2datadict.LoadProperties},
 	}
 
@@ -254,6 +360,16 @@ func (p *App) loadDataDict() error {
 		entry.loader(data)
 	}
 
+	// mods may add the ambient light of areas, the game doesn't have it
+	data, err := d2asset.LoadFile(d2resource.AreaLighting)
+
+	switch {
+	case err == nil:
+		d2datadict.LoadAreaLighting(data)
+	case !errors.Is(err, d2common.ErrNotFound):
+		return err
+	}
+
 	return nil
 }
 
@@ -266,8 +382,14 @@ func (p *App) renderDebug(target d2interface.Surface) error {
 	fps := p.renderer.CurrentFPS()
 	cx, cy := p.renderer.GetCursorPos()
 
+	frameCap := "off"
+	if limit := p.renderer.GetFrameCap(); limit > 0 {
+		frameCap = strconv.Itoa(limit)
+	}
+
 	target.PushTranslation(5, 565)
-	target.DrawText("vsync:" + strconv.FormatBool(vsyncEnabled) + "\nFPS:" + strconv.Itoa(int(fps)))
+	target.DrawText("vsync:" + strconv.FormatBool(vsyncEnabled) + " cap:" + frameCap +
+		"\nFPS:" + strconv.Itoa(int(fps)) + " draws:" + strconv.Itoa(p.renderer.CurrentDrawCalls()))
 	target.Pop()
 
 	var m runtime.MemStats
@@ -407,6 +529,8 @@ func (p *App) render(target d2interface.Surface) error {
 	return nil
 }
 
+// advance moves the screens forward at most once every defaultFPS seconds, by the time elapsed since the last screen
+// advance, so vsync and the frame cap don't change the game speed.
 func (p *App) advance(elapsed, current float64) error {
 	elapsedLastScreenAdvance := (current - p.lastScreenAdvance) * p.timeScale
 
@@ -419,6 +543,7 @@ func (p *App) advance(elapsed, current float64) error {
 	}
 
 	d2ui.Advance(elapsed)
+	p.audio.Advance(elapsed)
 
 	if err := p.inputManager.Advance(elapsed, current); err != nil {
 		return err
@@ -436,6 +561,9 @@ func (p *App) advance(elapsed, current float64) error {
 }
 
 func (p *App) update(target d2interface.Surface) error {
+	// the renderer may call update from its own goroutine, where a panic would not reach the recover in Run
+	defer p.recoverFromPanic()
+
 	currentTime := d2common.Now()
 	elapsedTime := (currentTime - p.lastTime) * p.timeScale
 	p.lastTime = currentTime
@@ -515,6 +643,201 @@ func (p *App) toggleVsync() {
 	vsync := !p.renderer.GetVSyncEnabled()
 	p.renderer.SetVSyncEnabled(vsync)
 	p.terminal.OutputInfof("vsync is now: %v", vsync)
+
+	d2config.Config.VsyncEnabled = vsync
+	p.saveConfig()
+}
+
+func (p *App) setFrameCap(fps int) {
+	if fps < 0 {
+		p.terminal.OutputErrorf("invalid frame cap: %d", fps)
+		return
+	}
+
+	p.renderer.SetFrameCap(fps)
+	p.terminal.OutputInfof("frame cap is now: %d", fps)
+
+	d2config.Config.FpsCap = fps
+	p.saveConfig()
+}
+
+func (p *App) setPlayers(players int) {
+	players = d2config.Config.Monsters.SetPlayers(players)
+	p.saveConfig()
+
+	handler, inGame := d2screen.CurrentScreen().(d2screen.ScreenPlayersHandler)
+
+	switch {
+	case !inGame:
+		p.terminal.OutputInfof("players set to %d, effective on the next level load", players)
+	case handler.SetPlayers(players):
+		p.terminal.OutputInfof("players set to %d", players)
+	default:
+		p.terminal.OutputInfof("players set to %d for hosted games, the host's setting applies to this game", players)
+	}
+}
+
+func (p *App) setMonsterDensity(density float64) {
+	if err := d2config.Config.Monsters.SetDensity(density); err != nil {
+		p.terminal.OutputErrorf("%s", err)
+		return
+	}
+
+	p.terminal.OutputInfof("monster density set to %v, effective on the next level load", density)
+	p.saveConfig()
+}
+
+func (p *App) toggleAutoPickup() {
+	settings := &d2config.Config.AutoPickup
+	settings.Enabled = !settings.Enabled
+	p.terminal.OutputInfof("auto-pickup is now: %v, item types: %s", settings.Enabled,
+		strings.Join(settings.GetItemTypes(), " "))
+
+	p.saveConfig()
+}
+
+func (p *App) setLootMode(mode string) {
+	if err := d2config.Config.Loot.SetMode(mode); err != nil {
+		p.terminal.OutputErrorf("%s", err)
+		return
+	}
+
+	p.terminal.OutputInfof("loot mode set to %s", mode)
+	p.saveConfig()
+}
+
+func (p *App) setItemLabels(mode string) {
+	if err := d2config.Config.ItemLabels.SetMode(mode); err != nil {
+		p.terminal.OutputErrorf("%s", err)
+		return
+	}
+
+	p.terminal.OutputInfof("item labels mode set to %s", mode)
+	p.saveConfig()
+}
+
+func (p *App) setMovementMode(mode string) {
+	if err := d2config.Config.Movement.SetMode(mode); err != nil {
+		p.terminal.OutputErrorf("%s", err)
+		return
+	}
+
+	p.terminal.OutputInfof("movement mode set to %s", mode)
+	p.saveConfig()
+}
+
+func (p *App) setFilteringMode(mode string) {
+	if err := d2config.Config.Filtering.SetMode(mode); err != nil {
+		p.terminal.OutputErrorf("%s", err)
+		return
+	}
+
+	p.terminal.OutputInfof("filtering mode set to %s", mode)
+	p.saveConfig()
+}
+
+func (p *App) setDeathPushMode(mode string) {
+	if err := d2config.Config.DeathPush.SetMode(mode); err != nil {
+		p.terminal.OutputErrorf("%s", err)
+		return
+	}
+
+	p.terminal.OutputInfof("death push mode set to %s", mode)
+	p.saveConfig()
+}
+
+func (p *App) toggleHitFlash() {
+	settings := &d2config.Config.HitFlash
+	settings.Disabled = !settings.Disabled
+	p.terminal.OutputInfof("hit flash is now: %v", settings.IsEnabled())
+
+	p.saveConfig()
+}
+
+func (p *App) toggleScreenFlash() {
+	settings := &d2config.Config.Flashing
+	settings.NoScreenFlashes = !settings.NoScreenFlashes
+	p.terminal.OutputInfof("screen flashes are now: %v", !settings.NoScreenFlashes)
+
+	p.saveConfig()
+}
+
+func (p *App) setFlashIntensity(intensity float64) {
+	if err := d2config.Config.Flashing.SetIntensity(intensity); err != nil {
+		p.terminal.OutputErrorf("%s", err)
+		return
+	}
+
+	p.terminal.OutputInfof("flash intensity set to %v", intensity)
+	p.saveConfig()
+}
+
+func (p *App) toggleWallSliding() {
+	settings := &d2config.Config.Movement
+	settings.WallSliding = !settings.WallSliding
+	p.terminal.OutputInfof("wall sliding is now: %v", settings.WallSliding)
+
+	p.saveConfig()
+}
+
+func (p *App) toggleSplitGold() {
+	settings := &d2config.Config.Loot
+	settings.SplitGold = !settings.SplitGold
+	p.terminal.OutputInfof("gold splitting is now: %v", settings.SplitGold)
+
+	p.saveConfig()
+}
+
+func (p *App) pinSeed(seed int) {
+	d2server.PinSeed(int64(seed))
+
+	if seed == 0 {
+		p.terminal.OutputInfof("each game now rolls a new map seed")
+		return
+	}
+
+	p.terminal.OutputInfof("map seed pinned to %d, effective on the next game", seed)
+}
+
+func (p *App) forcePreset(preset, file int) {
+	if err := forcePresetFile(strconv.Itoa(preset), strconv.Itoa(file)); err != nil {
+		p.terminal.OutputErrorf("%s", err)
+		return
+	}
+
+	p.terminal.OutputInfof("level preset %d now loads file %d, effective on the next level load", preset, file)
+}
+
+func (p *App) clearPresets() {
+	d2mapstamp.ClearForcedPresetFiles()
+	p.terminal.OutputInfof("level presets pick a random file again")
+}
+
+// forcePresetFile makes the level preset with the ID always load the file with the index.
+func forcePresetFile(preset, file string) error {
+	id, err := strconv.Atoi(preset)
+	if err != nil {
+		return fmt.Errorf("invalid level preset %s", preset)
+	}
+
+	if _, ok := d2datadict.LevelPresets[id]; !ok && len(d2datadict.LevelPresets) > 0 {
+		return fmt.Errorf("unknown level preset %d", id)
+	}
+
+	index, err := strconv.Atoi(file)
+	if err != nil || index < 0 {
+		return fmt.Errorf("invalid file index %s", file)
+	}
+
+	d2mapstamp.ForcePresetFile(id, index)
+
+	return nil
+}
+
+func (p *App) saveConfig() {
+	if err := d2config.Config.Save(); err != nil {
+		p.terminal.OutputErrorf("failed to save the configuration: %v", err)
+	}
 }
 
 func (p *App) toggleFpsCounter() {
@@ -531,6 +854,17 @@ func (p *App) setTimeScale(timeScale float64) {
 	}
 }
 
+func (p *App) setLogLevel(name string) {
+	level, err := d2logger.ParseLevel(name)
+	if err != nil {
+		p.terminal.OutputErrorf("%s", err)
+		return
+	}
+
+	d2logger.SetLevel(level)
+	p.terminal.OutputInfof("log level is now: %s", level)
+}
+
 func (p *App) quitGame() {
 	os.Exit(0)
 }
@@ -539,6 +873,15 @@ func (p *App) enterGuiPlayground() {
 	d2screen.SetNextScreen(d2gamescreen.CreateGuiTestMain(p.renderer))
 }
 
+func (p *App) runBenchmark(monsters, missiles int, seconds float64) {
+	if monsters < 0 || missiles < 0 || seconds <= 0 {
+		p.terminal.OutputErrorf("invalid benchmark parameters")
+		return
+	}
+
+	d2screen.SetNextScreen(d2gamescreen.CreateBenchmark(monsters, missiles, seconds, p.renderer, p.terminal))
+}
+
 func createZeroedRing(n int) *ring.Ring {
 	r := ring.New(n)
 	for i := 0; i < n; i++ {
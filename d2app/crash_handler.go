@@ -0,0 +1,119 @@
+package d2app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2logger"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2screen"
+)
+
+const crashExitCode = 2
+
+// recoverFromPanic must be deferred. If the game panics, it writes a crash report, attempts an emergency save of the
+// current character and exits instead of leaving the window hanging.
+func (p *App) recoverFromPanic() {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	stack := debug.Stack()
+
+	handler, hasGameState := d2screen.CurrentScreen().(d2screen.ScreenCrashHandler)
+
+	saveResult := "no game in progress"
+
+	if hasGameState {
+		saveResult = "saved"
+
+		if err := emergencySave(handler); err != nil {
+			saveResult = fmt.Sprintf("failed: %v", err)
+		}
+	}
+
+	report := p.crashReport(recovered, stack, handler, saveResult)
+
+	reportPath, err := writeCrashReport(report)
+	if err != nil {
+		log.Printf("failed to write crash report: %s", err)
+		fmt.Fprint(os.Stderr, report)
+	} else {
+		log.Printf("the game has crashed, a crash report was written to %s", reportPath)
+	}
+
+	os.Exit(crashExitCode)
+}
+
+// emergencySave saves the character, recovering from any panic caused by the state that crashed the game.
+func emergencySave(handler d2screen.ScreenCrashHandler) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic while saving: %v", recovered)
+		}
+	}()
+
+	return handler.EmergencySave()
+}
+
+func (p *App) crashReport(recovered interface{}, stack []byte, handler d2screen.ScreenCrashHandler,
+	saveResult string) string {
+	var report strings.Builder
+
+	fmt.Fprintf(&report, "OpenDiablo2 crash report\n")
+	fmt.Fprintf(&report, "Time: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&report, "Build: %s %s\n", d2common.BuildInfo.Branch, d2common.BuildInfo.Commit)
+	fmt.Fprintf(&report, "Panic: %v\n\n", recovered)
+	fmt.Fprintf(&report, "Stack:\n%s\n", stack)
+
+	report.WriteString("Game state:\n")
+
+	if handler != nil {
+		report.WriteString(crashState(handler))
+	}
+
+	fmt.Fprintf(&report, "Emergency save: %s\n\n", saveResult)
+
+	report.WriteString("Recent log:\n")
+
+	for _, line := range d2logger.RecentLines() {
+		report.WriteString(line)
+		report.WriteString("\n")
+	}
+
+	return report.String()
+}
+
+// crashState describes the game state, recovering from any panic caused by the state that crashed the game.
+func crashState(handler d2screen.ScreenCrashHandler) (state string) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			state = fmt.Sprintf("unavailable: %v\n", recovered)
+		}
+	}()
+
+	return handler.CrashState()
+}
+
+func writeCrashReport(report string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = path.Dir(os.Args[0])
+	}
+
+	crashDir := path.Join(configDir, "OpenDiablo2", "crashes")
+	if err := os.MkdirAll(crashDir, 0750); err != nil {
+		return "", err
+	}
+
+	reportPath := path.Join(crashDir, fmt.Sprintf("crash-%s.txt", time.Now().Format("20060102-150405")))
+
+	return reportPath, ioutil.WriteFile(reportPath, []byte(report), 0640)
+}
@@ -0,0 +1,50 @@
+package d2common
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of asset loading failures, matched with errors.Is
+var (
+	// ErrNotFound is returned when an asset isn't in any archive or override directory
+	ErrNotFound = errors.New("not found")
+
+	// ErrCorrupt is returned when the data of an asset doesn't decode
+	ErrCorrupt = errors.New("corrupt")
+
+	// ErrUnsupportedVersion is returned when an asset is of a version of its format which can't be decoded
+	ErrUnsupportedVersion = errors.New("unsupported version")
+)
+
+// AssetError is an error loading the asset at a path. The kind of failure is one of ErrNotFound, ErrCorrupt or
+// ErrUnsupportedVersion when known, and is matched with errors.Is; the path is recovered with errors.As.
+type AssetError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the path of the asset followed by the error
+func (e *AssetError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the error loading the asset
+func (e *AssetError) Unwrap() error {
+	return e.Err
+}
+
+// WrapAssetError adds the path of the asset to an error loading it. Returns nil for a nil error, and the error as is
+// if it already has the path of an asset.
+func WrapAssetError(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var assetErr *AssetError
+	if errors.As(err, &assetErr) {
+		return err
+	}
+
+	return &AssetError{Path: path, Err: err}
+}
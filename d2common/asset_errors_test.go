@@ -0,0 +1,34 @@
+package d2common
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWrapAssetError(t *testing.T) {
+	if WrapAssetError("/data/missing.dc6", nil) != nil {
+		t.Error("wrapping no error should return nil")
+	}
+
+	err := WrapAssetError("/data/broken.dc6", fmt.Errorf("%w: bad header", ErrCorrupt))
+
+	if !errors.Is(err, ErrCorrupt) {
+		t.Error("expected the error to be ErrCorrupt")
+	}
+
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrUnsupportedVersion) {
+		t.Error("expected the error to be of no other kind")
+	}
+
+	var assetErr *AssetError
+	if !errors.As(err, &assetErr) || assetErr.Path != "/data/broken.dc6" {
+		t.Fatalf("expected the path of the asset, got %v", err)
+	}
+
+	// the innermost path is kept
+	rewrapped := WrapAssetError("/data/other.dc6", fmt.Errorf("loading animation: %w", err))
+	if !errors.As(rewrapped, &assetErr) || assetErr.Path != "/data/broken.dc6" {
+		t.Errorf("expected the path of the first asset, got %v", rewrapped)
+	}
+}
@@ -0,0 +1,37 @@
+package d2common
+
+import (
+	"testing"
+)
+
+func TestCacheRemove(t *testing.T) {
+	cache := CreateCache(10)
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := cache.Insert(key, key, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cache.Remove("b")
+	cache.Remove("missing")
+
+	if _, found := cache.Retrieve("b"); found {
+		t.Error("a removed object should not be retrieved")
+	}
+
+	if cache.GetWeight() != 4 {
+		t.Errorf("expected a weight of 4, got %d", cache.GetWeight())
+	}
+
+	cache.Remove("a")
+	cache.Remove("c")
+
+	if err := cache.Insert("b", "new", 2); err != nil {
+		t.Errorf("a removed key should be insertable again: %v", err)
+	}
+
+	if value, found := cache.Retrieve("b"); !found || value != "new" {
+		t.Error("expected the new object to be retrieved")
+	}
+}
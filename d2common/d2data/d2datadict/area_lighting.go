@@ -0,0 +1,63 @@
+package d2datadict
+
+import (
+	"image/color"
+	"log"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+const maxLightIntensity = 100
+
+// AreaLightingRecord is a row of AreaLighting.txt, the ambient light a mod sets for an area. It isn't a file of the
+// game: mods add it to the override directory to make areas eerie green or dim blue.
+type AreaLightingRecord struct {
+	// The area the light is set for, the ID of its row in Levels.txt
+	Level int // Level
+
+	// The color of the ambient light
+	Color color.RGBA // Red, Green, Blue
+
+	// How strongly the area is tinted by the color, from 0 for the lighting of the game to 100 for the color alone
+	Intensity int // Intensity
+}
+
+// Tint returns the color the area is drawn with, the color of the light blended with white by the intensity.
+func (r *AreaLightingRecord) Tint() color.RGBA {
+	intensity := d2common.MaxInt(0, d2common.MinInt(maxLightIntensity, r.Intensity))
+	blend := func(channel uint8) uint8 {
+		return uint8(255 - (255-int(channel))*intensity/maxLightIntensity)
+	}
+
+	return color.RGBA{R: blend(r.Color.R), G: blend(r.Color.G), B: blend(r.Color.B), A: 255}
+}
+
+// AreaLighting stores the AreaLightingRecords by level, empty unless a mod sets the ambient light of areas
+//nolint:gochecknoglobals // Currently global by design
+var AreaLighting = make(map[int]*AreaLightingRecord)
+
+// LoadAreaLighting loads AreaLighting.txt and parses into records
+func LoadAreaLighting(file []byte) {
+	AreaLighting = make(map[int]*AreaLightingRecord)
+
+	d := d2common.LoadDataDictionary(file)
+	for d.Next() {
+		record := &AreaLightingRecord{
+			Level: d.Number("Level"),
+			Color: color.RGBA{
+				R: uint8(d.Number("Red")),
+				G: uint8(d.Number("Green")),
+				B: uint8(d.Number("Blue")),
+				A: 255,
+			},
+			Intensity: d.Number("Intensity"),
+		}
+		AreaLighting[record.Level] = record
+	}
+
+	if d.Err != nil {
+		panic(d.Err)
+	}
+
+	log.Printf("Loaded %d AreaLighting records", len(AreaLighting))
+}
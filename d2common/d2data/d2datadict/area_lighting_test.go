@@ -0,0 +1,37 @@
+package d2datadict
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestLoadAreaLighting(t *testing.T) {
+	LoadAreaLighting([]byte("Level\tRed\tGreen\tBlue\tIntensity\r\n8\t0\t255\t0\t50\r\n"))
+
+	record, ok := AreaLighting[8]
+	if !ok {
+		t.Fatal("the lighting of level 8 wasn't loaded")
+	}
+
+	if want := (color.RGBA{R: 128, G: 255, B: 128, A: 255}); record.Tint() != want {
+		t.Errorf("got tint %v, want %v", record.Tint(), want)
+	}
+}
+
+func TestAreaLightingTint(t *testing.T) {
+	tests := []struct {
+		intensity int
+		want      color.RGBA
+	}{
+		{0, color.RGBA{R: 255, G: 255, B: 255, A: 255}},
+		{100, color.RGBA{R: 40, G: 60, B: 200, A: 255}},
+		{150, color.RGBA{R: 40, G: 60, B: 200, A: 255}},
+	}
+
+	for _, test := range tests {
+		record := &AreaLightingRecord{Color: color.RGBA{R: 40, G: 60, B: 200, A: 255}, Intensity: test.intensity}
+		if got := record.Tint(); got != test.want {
+			t.Errorf("intensity %d: got tint %v, want %v", test.intensity, got, test.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 )
 
 // DifficultyLevels contain the difficulty records for each difficulty
@@ -92,6 +93,17 @@ type DifficultyLevelRecord struct {
 
 }
 
+// GetDifficultyLevel returns the record of the difficulty, nil if difficultylevels.txt has none.
+func GetDifficultyLevel(difficulty d2enum.Difficulty) *DifficultyLevelRecord {
+	names := map[d2enum.Difficulty]string{
+		d2enum.DifficultyNormal:    "Normal",
+		d2enum.DifficultyNightmare: "Nightmare",
+		d2enum.DifficultyHell:      "Hell",
+	}
+
+	return DifficultyLevels[names[difficulty]]
+}
+
 // LoadDifficultyLevels is a loader for difficultylevels.txt
 func LoadDifficultyLevels(file []byte) {
 	DifficultyLevels = make(map[string]*DifficultyLevelRecord)
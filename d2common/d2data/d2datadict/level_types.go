@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 )
 
 // LevelTypeRecord is a representation of a row from lvltype.txt
@@ -18,6 +19,25 @@ type LevelTypeRecord struct {
 	Expansion bool
 }
 
+// actPalettes are the palettes the levels of each act are drawn with, indexed by the Act column of lvltype.txt
+//nolint:gochecknoglobals // constant lookup table
+var actPalettes = [...]string{
+	1: d2resource.PaletteAct1,
+	2: d2resource.PaletteAct2,
+	3: d2resource.PaletteAct3,
+	4: d2resource.PaletteAct4,
+	5: d2resource.PaletteAct5,
+}
+
+// PalettePath returns the path of the palette the tiles and units of levels of this type are drawn with.
+func (r LevelTypeRecord) PalettePath() string {
+	if r.Act < 1 || r.Act >= len(actPalettes) {
+		return d2resource.PaletteAct1
+	}
+
+	return actPalettes[r.Act]
+}
+
 // LevelTypes stores all of the LevelTypeRecords
 var LevelTypes []LevelTypeRecord //nolint:gochecknoglobals // Currently global by design,
 
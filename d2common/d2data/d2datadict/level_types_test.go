@@ -0,0 +1,26 @@
+package d2datadict
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+)
+
+func TestLevelTypePalettePath(t *testing.T) {
+	tests := []struct {
+		act  int
+		want string
+	}{
+		{1, d2resource.PaletteAct1},
+		{3, d2resource.PaletteAct3},
+		{5, d2resource.PaletteAct5},
+		{0, d2resource.PaletteAct1},
+		{6, d2resource.PaletteAct1},
+	}
+
+	for _, test := range tests {
+		if got := (LevelTypeRecord{Act: test.act}).PalettePath(); got != test.want {
+			t.Errorf("act %d: got palette %s, want %s", test.act, got, test.want)
+		}
+	}
+}
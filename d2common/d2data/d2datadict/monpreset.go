@@ -30,3 +30,14 @@ func LoadMonPresets(file []byte) {
 
 	log.Printf("Loaded %d MonPreset records", len(MonPresets))
 }
+
+// MonPresetPlace returns the Place of the monster preset with the given act and DS1 object id, or an empty string if
+// there is no such preset. Place is either a monstats.txt Id, a superuniques.txt Superunique or a monplace.txt code.
+func MonPresetPlace(act int32, index int) string {
+	places := MonPresets[act]
+	if index < 0 || index >= len(places) {
+		return ""
+	}
+
+	return places[index]
+}
@@ -952,3 +952,44 @@ func LoadMonStats(file []byte) { // nolint:funlen // Makes no sense to split
 
 	log.Printf("Loaded %d MonStats records", len(MonStats))
 }
+
+// Resistance returns the monster's base resistance to the damage element on the difficulty, in percent.
+func (m *MonStatsRecord) Resistance(element d2enum.DamageElement, difficulty d2enum.Difficulty) int {
+	var resistances [3]int
+
+	switch element {
+	case d2enum.DamagePhysical:
+		resistances = [3]int{m.ResistancePhysicalNormal, m.ResistancePhysicalNightmare, m.ResistancePhysicalHell}
+	case d2enum.DamageMagic:
+		resistances = [3]int{m.ResistanceMagicNormal, m.ResistanceMagicNightmare, m.ResistanceMagicHell}
+	case d2enum.DamageFire:
+		resistances = [3]int{m.ResistanceFireNormal, m.ResistanceFireNightmare, m.ResistanceFireHell}
+	case d2enum.DamageLightning:
+		resistances = [3]int{m.ResistanceLightningNormal, m.ResistanceLightningNightmare, m.ResistanceLightningHell}
+	case d2enum.DamageCold:
+		resistances = [3]int{m.ResistanceColdNormal, m.ResistanceColdNightmare, m.ResistanceColdHell}
+	case d2enum.DamagePoison:
+		resistances = [3]int{m.ResistancePoisonNormal, m.ResistancePoisonNightmare, m.ResistancePoisonHell}
+	default:
+		return 0
+	}
+
+	if difficulty < d2enum.DifficultyNormal || difficulty > d2enum.DifficultyHell {
+		return resistances[d2enum.DifficultyNormal]
+	}
+
+	return resistances[difficulty]
+}
+
+// LeechSensitivity returns the percent of the life and mana steal of its attackers which applies to the monster on the
+// difficulty.
+func (m *MonStatsRecord) LeechSensitivity(difficulty d2enum.Difficulty) int {
+	switch difficulty {
+	case d2enum.DifficultyNightmare:
+		return m.LeechSensitivityNightmare
+	case d2enum.DifficultyHell:
+		return m.LeechSensitivityHell
+	default:
+		return m.LeechSensitivityNormal
+	}
+}
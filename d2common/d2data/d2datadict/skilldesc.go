@@ -0,0 +1,51 @@
+package d2datadict
+
+import (
+	"log"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+// SkillDescriptionRecord is a row of skilldesc.txt, describing how a skill is shown in the skill tree
+type SkillDescriptionRecord struct {
+	Name        string // skilldesc, the key skills.txt refers to in its skilldesc column
+	SkillPage   int    // skill tree tab, 1 to 3, or 0 if the skill isn't in the skill tree
+	SkillRow    int    // row of the skill in the tab, 1 to 6
+	SkillColumn int    // column of the skill in the tab, 1 to 3
+	ListRow     int
+	IconCel     int    // frame of the class skill icon file
+	NameKey     string // string table key of the skill name
+	ShortKey    string // string table key of the short description
+	LongKey     string // string table key of the long description
+}
+
+// SkillDescriptions stores all of the SkillDescriptionRecords, by skilldesc
+//nolint:gochecknoglobals // Currently global by design, only written once
+var SkillDescriptions map[string]*SkillDescriptionRecord
+
+// LoadSkillDescriptions loads skilldesc.txt file contents into a skill description record map
+func LoadSkillDescriptions(file []byte) {
+	SkillDescriptions = make(map[string]*SkillDescriptionRecord)
+
+	d := d2common.LoadDataDictionary(file)
+	for d.Next() {
+		record := &SkillDescriptionRecord{
+			Name:        d.String("skilldesc"),
+			SkillPage:   d.Number("SkillPage"),
+			SkillRow:    d.Number("SkillRow"),
+			SkillColumn: d.Number("SkillColumn"),
+			ListRow:     d.Number("ListRow"),
+			IconCel:     d.Number("IconCel"),
+			NameKey:     d.String("str name"),
+			ShortKey:    d.String("str short"),
+			LongKey:     d.String("str long"),
+		}
+		SkillDescriptions[record.Name] = record
+	}
+
+	if d.Err != nil {
+		panic(d.Err)
+	}
+
+	log.Printf("Loaded %d SkillDescription records", len(SkillDescriptions))
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 )
 
 // SkillDetails has all of the SkillRecords
@@ -546,3 +547,84 @@ func LoadSkills(file []byte) {
 
 	log.Printf("Loaded %d Skill records", len(SkillDetails))
 }
+
+const (
+	// manaCostScale is the fixed-point scale of the mana columns, which are in 256ths of a point before manashift.
+	manaCostScale = 256
+
+	// skillFramesPerSecond is the rate the delay column is counted in.
+	skillFramesPerSecond = 25.0
+)
+
+// ManaCost returns the mana it costs to use the skill at the given skill level, using the same formula as
+// Diablo II: ((mana + lvlmana * (level - 1)) << manashift) / 256, but never less than minmana.
+func (s *SkillRecord) ManaCost(level int) int {
+	if level < 1 {
+		level = 1
+	}
+
+	cost := ((s.Mana + s.Lvlmana*(level-1)) << uint(s.Manashift)) / manaCostScale
+
+	if cost < s.Minmana {
+		return s.Minmana
+	}
+
+	return cost
+}
+
+// Cooldown returns the cast delay of the skill in seconds, or 0 if the skill can be used again right away.
+func (s *SkillRecord) Cooldown() float64 {
+	if s.Delay <= 0 {
+		return 0
+	}
+
+	return float64(s.Delay) / skillFramesPerSecond
+}
+
+// GetSkillByMissile returns the skill which fires the missile with the given name, or nil if no skill does. When
+// several skills fire the same missile the one with the lowest ID is returned.
+func GetSkillByMissile(missileName string) *SkillRecord {
+	var result *SkillRecord
+
+	for _, skill := range SkillDetails {
+		if skill.Srvmissile != missileName && skill.Cltmissile != missileName {
+			continue
+		}
+
+		if result == nil || skill.ID < result.ID {
+			result = skill
+		}
+	}
+
+	return result
+}
+
+// ActivationMode returns how the skill is used from a skill slot. Auras are toggled, skills which repeat while the
+// mouse button is held down are channeled, and everything else is used once per press.
+func (s *SkillRecord) ActivationMode() d2enum.SkillActivationMode {
+	switch {
+	case s.Aura:
+		return d2enum.SkillActivationToggle
+	case s.Repeat:
+		return d2enum.SkillActivationChanneled
+	default:
+		return d2enum.SkillActivationInstant
+	}
+}
+
+// StateName returns the name of the state a toggled skill keeps on its user while it is active.
+func (s *SkillRecord) StateName() string {
+	switch {
+	case s.Aurastate != "":
+		return s.Aurastate
+	case s.State1 != "":
+		return s.State1
+	default:
+		return s.Skill
+	}
+}
+
+// IsGroundTargeted returns true if the skill is cast at a location on the ground rather than at a unit.
+func (s *SkillRecord) IsGroundTargeted() bool {
+	return s.Range == "loc"
+}
@@ -0,0 +1,14 @@
+package d2enum
+
+// DamageElement is the element of damage, which the defender's resistance to that element reduces
+type DamageElement int
+
+// Damage elements
+const (
+	DamagePhysical DamageElement = iota
+	DamageMagic
+	DamageFire
+	DamageLightning
+	DamageCold
+	DamagePoison
+)
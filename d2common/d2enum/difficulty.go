@@ -0,0 +1,11 @@
+package d2enum
+
+// Difficulty is the difficulty a game is played on
+type Difficulty int
+
+// Difficulties of the game
+const (
+	DifficultyNormal Difficulty = iota
+	DifficultyNightmare
+	DifficultyHell
+)
@@ -0,0 +1,45 @@
+package d2enum
+
+import "strings"
+
+// ItemQuality is the quality of an item, which decides the color of its name
+type ItemQuality int
+
+// Item qualities, numbered as in Diablo II
+const (
+	ItemQualityLow ItemQuality = iota + 1
+	ItemQualityNormal
+	ItemQualitySuperior
+	ItemQualityMagic
+	ItemQualitySet
+	ItemQualityRare
+	ItemQualityUnique
+	ItemQualityCrafted
+)
+
+//nolint:gochecknoglobals // constant lookup table
+var itemQualityNames = map[ItemQuality]string{
+	ItemQualityLow:      "low",
+	ItemQualityNormal:   "normal",
+	ItemQualitySuperior: "superior",
+	ItemQualityMagic:    "magic",
+	ItemQualitySet:      "set",
+	ItemQualityRare:     "rare",
+	ItemQualityUnique:   "unique",
+	ItemQualityCrafted:  "crafted",
+}
+
+func (q ItemQuality) String() string {
+	return itemQualityNames[q]
+}
+
+// ItemQualityFromString returns the item quality with the name, such as "unique", false if there is none.
+func ItemQualityFromString(name string) (ItemQuality, bool) {
+	for quality, qualityName := range itemQualityNames {
+		if strings.EqualFold(name, qualityName) {
+			return quality, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,17 @@
+package d2enum
+
+// SkillActivationMode is the way a skill is used from a skill slot
+type SkillActivationMode int
+
+const (
+	// SkillActivationInstant skills are used once each time the skill slot is pressed
+	SkillActivationInstant SkillActivationMode = iota
+
+	// SkillActivationChanneled skills stay active while the skill slot is held down, draining mana, and stop when it
+	// is released
+	SkillActivationChanneled
+
+	// SkillActivationToggle skills are turned on by one press of the skill slot and off by the next, keeping their
+	// state on the user in between
+	SkillActivationToggle
+)
@@ -0,0 +1,25 @@
+package d2enum
+
+// TradeAction is a step of a trade between two players
+type TradeAction int
+
+// Trade actions
+const (
+	TradeRequest TradeAction = iota // A player asks another player to trade
+	TradeAccept                     // The player asked agrees, which opens the trade
+	TradeOffer                      // A player changes the items and gold they give
+	TradeConfirm                    // A player agrees to the trade as offered
+	TradeCancel                     // A player leaves the trade, or it can't be done
+)
+
+func (t TradeAction) String() string {
+	strings := map[TradeAction]string{
+		TradeRequest: "request",
+		TradeAccept:  "accept",
+		TradeOffer:   "offer",
+		TradeConfirm: "confirm",
+		TradeCancel:  "cancel",
+	}
+
+	return strings[t]
+}
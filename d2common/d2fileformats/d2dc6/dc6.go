@@ -1,9 +1,16 @@
 package d2dc6
 
 import (
+	"fmt"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 )
 
+const (
+	dc6Version    = 6
+	dc6HeaderSize = 24
+)
+
 // DC6 represents a DC6 file.
 type DC6 struct {
 	Version            int32
@@ -23,10 +30,20 @@ func Load(data []byte) (*DC6, error) {
 		terminatorSize  = 3
 	)
 
+	if len(data) < dc6HeaderSize {
+		return nil, fmt.Errorf("%w: %d bytes is too short for a DC6 header", d2common.ErrCorrupt, len(data))
+	}
+
 	r := d2common.CreateStreamReader(data)
 
 	var dc DC6
 	dc.Version = r.GetInt32()
+
+	if dc.Version != dc6Version {
+		return nil, fmt.Errorf("%w: expected a DC6 version of %d, but got %d", d2common.ErrUnsupportedVersion,
+			dc6Version, dc.Version)
+	}
+
 	dc.Flags = r.GetUInt32()
 	dc.Encoding = r.GetUInt32()
 	dc.Termination = r.ReadBytes(terminationSize)
@@ -35,6 +52,10 @@ func Load(data []byte) (*DC6, error) {
 
 	frameCount := int(dc.Directions * dc.FramesPerDirection)
 
+	if uint64(frameCount)*4 > r.GetSize()-r.GetPosition() {
+		return nil, fmt.Errorf("%w: %d frames don't fit in %d bytes", d2common.ErrCorrupt, frameCount, len(data))
+	}
+
 	dc.FramePointers = make([]uint32, frameCount)
 	for i := 0; i < frameCount; i++ {
 		dc.FramePointers[i] = r.GetUInt32()
@@ -1,12 +1,13 @@
 package d2dcc
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 )
 
 const dccFileSignature = 0x74
+const dccHeaderSize = 15
 const directionOffsetMultiplier = 8
 
 // DCC represents a DCC file.
@@ -16,11 +17,16 @@ type DCC struct {
 	NumberOfDirections int
 	FramesPerDirection int
 	directionOffsets   []int
+	frameCounts        []int
 	fileData           []byte
 }
 
 // Load loads a DCC file.
 func Load(fileData []byte) (*DCC, error) {
+	if len(fileData) < dccHeaderSize {
+		return nil, fmt.Errorf("%w: %d bytes is too short for a DCC header", d2common.ErrCorrupt, len(fileData))
+	}
+
 	result := &DCC{
 		fileData: fileData,
 	}
@@ -30,7 +36,7 @@ func Load(fileData []byte) (*DCC, error) {
 	result.Signature = int(bm.GetByte())
 
 	if result.Signature != dccFileSignature {
-		return nil, errors.New("signature expected to be 0x74 but it is not")
+		return nil, fmt.Errorf("%w: signature expected to be 0x74 but it is not", d2common.ErrCorrupt)
 	}
 
 	result.Version = int(bm.GetByte())
@@ -38,7 +44,7 @@ func Load(fileData []byte) (*DCC, error) {
 	result.FramesPerDirection = int(bm.GetInt32())
 
 	if bm.GetInt32() != 1 {
-		return nil, errors.New("this value isn't 1. It has to be 1")
+		return nil, fmt.Errorf("%w: this value isn't 1. It has to be 1", d2common.ErrCorrupt)
 	}
 
 	bm.GetInt32() // TotalSizeCoded
@@ -49,11 +55,45 @@ func Load(fileData []byte) (*DCC, error) {
 		result.directionOffsets[i] = int(bm.GetInt32())
 	}
 
+	result.frameCounts = make([]int, result.NumberOfDirections)
+
+	for i := range result.frameCounts {
+		if result.hasDirectionData(i) {
+			result.frameCounts[i] = result.FramesPerDirection
+		}
+	}
+
 	return result, nil
 }
 
-// DecodeDirection decodes and returns the given direction
+// hasDirectionData returns true if the data of the direction lies within the file and isn't empty. Some files have
+// directions without any frames.
+func (dcc *DCC) hasDirectionData(direction int) bool {
+	start := dcc.directionOffsets[direction]
+	if start <= 0 || start >= len(dcc.fileData) {
+		return false
+	}
+
+	// an empty direction starts where the next one does
+	return direction+1 >= len(dcc.directionOffsets) || dcc.directionOffsets[direction+1] != start
+}
+
+// FrameCount returns the number of frames of the direction, which is 0 for directions without any data.
+func (dcc *DCC) FrameCount(direction int) int {
+	if direction < 0 || direction >= len(dcc.frameCounts) {
+		return 0
+	}
+
+	return dcc.frameCounts[direction]
+}
+
+// DecodeDirection decodes and returns the given direction. Directions without any frames decode to a direction
+// without frames.
 func (dcc *DCC) DecodeDirection(direction int) *DCCDirection {
+	if dcc.FrameCount(direction) == 0 {
+		return &DCCDirection{}
+	}
+
 	return CreateDCCDirection(d2common.CreateBitMuncher(dcc.fileData,
 		dcc.directionOffsets[direction]*directionOffsetMultiplier), dcc)
 }
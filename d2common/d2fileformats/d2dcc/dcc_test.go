@@ -0,0 +1,61 @@
+package d2dcc
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+// dccHeader returns the header of a DCC file with the direction offsets, followed by padding up to size bytes.
+func dccHeader(framesPerDirection int, offsets []int, size int) []byte {
+	data := []byte{dccFileSignature, 6, byte(len(offsets))}
+	data = append(data, make([]byte, 12)...)
+	binary.LittleEndian.PutUint32(data[3:], uint32(framesPerDirection))
+	binary.LittleEndian.PutUint32(data[7:], 1)
+
+	for _, offset := range offsets {
+		data = append(data, make([]byte, 4)...)
+		binary.LittleEndian.PutUint32(data[len(data)-4:], uint32(offset))
+	}
+
+	return append(data, make([]byte, size-len(data))...)
+}
+
+func TestFrameCountPerDirection(t *testing.T) {
+	const framesPerDirection = 4
+
+	// the second direction has no data, and the third starts past the end of the file
+	dcc, err := Load(dccHeader(framesPerDirection, []int{27, 0, 40, 31}, 35))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []int{framesPerDirection, 0, 0, framesPerDirection}
+
+	for direction, count := range expected {
+		if dcc.FrameCount(direction) != count {
+			t.Errorf("direction %d: expected %d frames, got %d", direction, count, dcc.FrameCount(direction))
+		}
+	}
+
+	if dcc.FrameCount(-1) != 0 || dcc.FrameCount(len(expected)) != 0 {
+		t.Error("there are no frames in directions outside of the file")
+	}
+
+	if frames := dcc.DecodeDirection(1).Frames; len(frames) != 0 {
+		t.Errorf("an empty direction decoded to %d frames", len(frames))
+	}
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	data := dccHeader(1, []int{19}, 24)
+	data[0] = 0
+
+	for _, data := range [][]byte{data, data[:4]} {
+		if _, err := Load(data); !errors.Is(err, d2common.ErrCorrupt) {
+			t.Errorf("expected a corrupt file error, got %v", err)
+		}
+	}
+}
@@ -1,12 +1,26 @@
 package d2ds1
 
 import (
+	"fmt"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 )
 
-const maxActNumber = 5
+const (
+	maxActNumber = 5
+	maxVersion   = 18 // the latest version of the format, used by the files of the game
+	headerSize   = 12
+)
+
+// dirLookup maps the wall orientations of files older than version 7 to the current ones.
+//nolint:gochecknoglobals // constant lookup table
+var dirLookup = []int32{
+	0x00, 0x01, 0x02, 0x01, 0x02, 0x03, 0x03, 0x05, 0x05, 0x06,
+	0x06, 0x07, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E,
+	0x0F, 0x10, 0x11, 0x12, 0x14,
+}
 
 // DS1 represents the "stamp" data that is used to build up maps.
 type DS1 struct {
@@ -24,6 +38,9 @@ type DS1 struct {
 	NumberOfShadowLayers       int32               // ShadowNum number of shadow layer used
 	NumberOfSubstitutionLayers int32               // SubstitutionNum number of substitution layer used
 	SubstitutionGroupsNum      int32               // SubstitutionGroupsNum number of substitution groups, datas between objects & NPC paths
+
+	unknown1 []byte // Two dwords of unknown meaning in versions 9 to 13, kept to write them back
+	unknown2 uint32 // A dword of unknown meaning before the substitution groups from version 18
 }
 
 // LoadDS1 loads the specified DS1 file
@@ -35,11 +52,24 @@ func LoadDS1(fileData []byte) (*DS1, error) {
 		NumberOfShadowLayers:       1,
 		NumberOfSubstitutionLayers: 0,
 	}
+	if len(fileData) < headerSize {
+		return nil, fmt.Errorf("%w: %d bytes is too short for a DS1 header", d2common.ErrCorrupt, len(fileData))
+	}
+
 	br := d2common.CreateStreamReader(fileData)
 	ds1.Version = br.GetInt32()
 	ds1.Width = br.GetInt32() + 1
 	ds1.Height = br.GetInt32() + 1
 
+	if ds1.Version < 1 || ds1.Version > maxVersion {
+		return nil, fmt.Errorf("%w: expected a DS1 version from 1 to %d, but got %d", d2common.ErrUnsupportedVersion,
+			maxVersion, ds1.Version)
+	}
+
+	if ds1.Width < 1 || ds1.Height < 1 {
+		return nil, fmt.Errorf("%w: invalid size of %dx%d tiles", d2common.ErrCorrupt, ds1.Width, ds1.Height)
+	}
+
 	if ds1.Version >= 8 { //nolint:gomnd // Version number
 		ds1.Act = d2common.MinInt32(maxActNumber, br.GetInt32()+1)
 	}
@@ -71,8 +101,8 @@ func LoadDS1(fileData []byte) (*DS1, error) {
 	}
 
 	if ds1.Version >= 9 && ds1.Version <= 13 {
-		// Skipping two dwords because they are "meaningless"?
-		br.SkipBytes(8) //nolint:gomnd // We don't know what's here
+		// Two dwords which seem "meaningless"?
+		ds1.unknown1 = br.ReadBytes(8) //nolint:gomnd // We don't know what's here
 	}
 
 	if ds1.Version >= 4 { //nolint:gomnd // Version number
@@ -129,7 +159,7 @@ func (ds1 *DS1) loadObjects(br *d2common.StreamReader) {
 func (ds1 *DS1) loadSubstitutions(br *d2common.StreamReader) {
 	if ds1.Version >= 12 && (ds1.SubstitutionType == 1 || ds1.SubstitutionType == 2) {
 		if ds1.Version >= 18 { //nolint:gomnd // Version number
-			br.GetUInt32()
+			ds1.unknown2 = br.GetUInt32()
 		}
 
 		numberOfSubGroups := br.GetInt32()
@@ -207,9 +237,9 @@ func (ds1 *DS1) loadNPCs(br *d2common.StreamReader) {
 				ds1.loadNpcPaths(br, objIdx, int(numPaths))
 			} else {
 				if ds1.Version >= 15 { //nolint:gomnd // Version number
-					br.SkipBytes(int(numPaths) * 3) //nolint:gomnd // Unknown data
+					br.SkipBytes(int(numPaths) * 3 * 4) //nolint:gomnd // Unknown data, 3 dwords per path
 				} else {
-					br.SkipBytes(int(numPaths) * 2) //nolint:gomnd // Unknown data
+					br.SkipBytes(int(numPaths) * 2 * 4) //nolint:gomnd // Unknown data, 2 dwords per path
 				}
 			}
 		}
@@ -235,12 +265,6 @@ func (ds1 *DS1) loadNpcPaths(br *d2common.StreamReader, objIdx, numPaths int) {
 }
 
 func (ds1 *DS1) loadLayerStreams(br *d2common.StreamReader, layerStream []d2enum.LayerStreamType) {
-	var dirLookup = []int32{
-		0x00, 0x01, 0x02, 0x01, 0x02, 0x03, 0x03, 0x05, 0x05, 0x06,
-		0x06, 0x07, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E,
-		0x0F, 0x10, 0x11, 0x12, 0x14,
-	}
-
 	for lIdx := range layerStream {
 		layerStreamType := layerStream[lIdx]
 
@@ -0,0 +1,197 @@
+package d2ds1
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// Marshal encodes the DS1 in the layout of its version, so an edited DS1 can be written back. A DS1 loaded and
+// marshaled without changes gives back the same bytes, except for the NPC paths of objects missing from the file,
+// which aren't loaded.
+func (ds1 *DS1) Marshal() []byte {
+	sw := d2common.CreateStreamWriter()
+
+	sw.PushUint32(uint32(ds1.Version))
+	sw.PushUint32(uint32(ds1.Width - 1))
+	sw.PushUint32(uint32(ds1.Height - 1))
+
+	if ds1.Version >= 8 { //nolint:gomnd // Version number
+		sw.PushUint32(uint32(ds1.Act - 1))
+	}
+
+	if ds1.Version >= 10 { //nolint:gomnd // Version number
+		sw.PushUint32(uint32(ds1.SubstitutionType))
+	}
+
+	if ds1.Version >= 3 { //nolint:gomnd // Version number
+		sw.PushUint32(uint32(len(ds1.Files)))
+
+		for _, file := range ds1.Files {
+			for idx := 0; idx < len(file); idx++ {
+				sw.PushByte(file[idx])
+			}
+
+			sw.PushByte(0)
+		}
+	}
+
+	if ds1.Version >= 9 && ds1.Version <= 13 {
+		unknown := make([]byte, 8) //nolint:gomnd // We don't know what's here
+		copy(unknown, ds1.unknown1)
+
+		for _, b := range unknown {
+			sw.PushByte(b)
+		}
+	}
+
+	if ds1.Version >= 4 { //nolint:gomnd // Version number
+		sw.PushUint32(uint32(ds1.NumberOfWalls))
+
+		if ds1.Version >= 16 { //nolint:gomnd // Version number
+			sw.PushUint32(uint32(ds1.NumberOfFloors))
+		}
+	}
+
+	ds1.writeLayerStreams(sw, ds1.setupStreamLayerTypes())
+	ds1.writeObjects(sw)
+	ds1.writeSubstitutions(sw)
+	ds1.writeNPCs(sw)
+
+	return sw.GetBytes()
+}
+
+func (ds1 *DS1) writeLayerStreams(sw *d2common.StreamWriter, layerStream []d2enum.LayerStreamType) {
+	for _, layerStreamType := range layerStream {
+		for y := 0; y < int(ds1.Height); y++ {
+			for x := 0; x < int(ds1.Width); x++ {
+				sw.PushUint32(ds1.encodeLayerTile(&ds1.Tiles[y][x], layerStreamType))
+			}
+		}
+	}
+}
+
+// encodeLayerTile returns the dword of the tile in the layer stream.
+func (ds1 *DS1) encodeLayerTile(tile *TileRecord, layerStreamType d2enum.LayerStreamType) uint32 {
+	switch layerStreamType {
+	case d2enum.LayerStreamWall1, d2enum.LayerStreamWall2, d2enum.LayerStreamWall3, d2enum.LayerStreamWall4:
+		wall := &tile.Walls[int(layerStreamType)-int(d2enum.LayerStreamWall1)]
+		return encodeTile(wall.Prop1, wall.Sequence, wall.Unknown1, wall.Style, wall.Unknown2, wall.Hidden)
+	case d2enum.LayerStreamOrientation1, d2enum.LayerStreamOrientation2,
+		d2enum.LayerStreamOrientation3, d2enum.LayerStreamOrientation4:
+		wall := &tile.Walls[int(layerStreamType)-int(d2enum.LayerStreamOrientation1)]
+		return uint32(ds1.encodeOrientation(wall.Type)) | uint32(wall.Zero)<<8 //nolint:gomnd // Bitmask
+	case d2enum.LayerStreamFloor1, d2enum.LayerStreamFloor2:
+		floor := &tile.Floors[int(layerStreamType)-int(d2enum.LayerStreamFloor1)]
+		return encodeTile(floor.Prop1, floor.Sequence, floor.Unknown1, floor.Style, floor.Unknown2, floor.Hidden)
+	case d2enum.LayerStreamShadow:
+		if len(tile.Shadows) > 0 {
+			shadow := &tile.Shadows[0]
+			return encodeTile(shadow.Prop1, shadow.Sequence, shadow.Unknown1, shadow.Style, shadow.Unknown2, shadow.Hidden)
+		}
+	case d2enum.LayerStreamSubstitute:
+		if len(tile.Substitutions) > 0 {
+			return tile.Substitutions[0].Unknown
+		}
+	}
+
+	return 0
+}
+
+// encodeOrientation returns the wall orientation as stored in files of the DS1's version.
+func (ds1 *DS1) encodeOrientation(tileType d2enum.TileType) byte {
+	if ds1.Version < 7 { //nolint:gomnd // Version number
+		for idx, orientation := range dirLookup {
+			if orientation == int32(tileType) {
+				return byte(idx)
+			}
+		}
+	}
+
+	return byte(tileType)
+}
+
+// encodeTile packs the fields of a wall, floor or shadow into its layer stream dword.
+func encodeTile(prop1, sequence, unknown1, style, unknown2 byte, hidden bool) uint32 {
+	dw := uint32(prop1) |
+		uint32(sequence&0x3F)<<8 | //nolint:gomnd // Bitmask
+		uint32(unknown1&0x3F)<<14 | //nolint:gomnd // Bitmask
+		uint32(style&0x3F)<<20 | //nolint:gomnd // Bitmask
+		uint32(unknown2&0x1F)<<26 //nolint:gomnd // Bitmask
+
+	if hidden {
+		dw |= 0x80000000 //nolint:gomnd // Bitmask
+	}
+
+	return dw
+}
+
+func (ds1 *DS1) writeObjects(sw *d2common.StreamWriter) {
+	if ds1.Version < 2 { //nolint:gomnd // Version number
+		return
+	}
+
+	sw.PushUint32(uint32(len(ds1.Objects)))
+
+	for _, object := range ds1.Objects {
+		sw.PushUint32(uint32(object.Type))
+		sw.PushUint32(uint32(object.Id))
+		sw.PushUint32(uint32(object.X))
+		sw.PushUint32(uint32(object.Y))
+		sw.PushUint32(uint32(object.Flags))
+	}
+}
+
+func (ds1 *DS1) writeSubstitutions(sw *d2common.StreamWriter) {
+	if ds1.Version < 12 || (ds1.SubstitutionType != 1 && ds1.SubstitutionType != 2) {
+		return
+	}
+
+	if ds1.Version >= 18 { //nolint:gomnd // Version number
+		sw.PushUint32(ds1.unknown2)
+	}
+
+	sw.PushUint32(uint32(len(ds1.SubstitutionGroups)))
+
+	for _, group := range ds1.SubstitutionGroups {
+		sw.PushUint32(uint32(group.TileX))
+		sw.PushUint32(uint32(group.TileY))
+		sw.PushUint32(uint32(group.WidthInTiles))
+		sw.PushUint32(uint32(group.HeightInTiles))
+		sw.PushUint32(uint32(group.Unknown))
+	}
+}
+
+func (ds1 *DS1) writeNPCs(sw *d2common.StreamWriter) {
+	if ds1.Version < 14 { //nolint:gomnd // Version number
+		return
+	}
+
+	numberOfNpcs := 0
+
+	for idx := range ds1.Objects {
+		if len(ds1.Objects[idx].Paths) > 0 {
+			numberOfNpcs++
+		}
+	}
+
+	sw.PushUint32(uint32(numberOfNpcs))
+
+	for _, object := range ds1.Objects {
+		if len(object.Paths) == 0 {
+			continue
+		}
+
+		sw.PushUint32(uint32(len(object.Paths)))
+		sw.PushUint32(uint32(object.X))
+		sw.PushUint32(uint32(object.Y))
+
+		for _, path := range object.Paths {
+			sw.PushUint32(uint32(path.X))
+			sw.PushUint32(uint32(path.Y))
+
+			if ds1.Version >= 15 { //nolint:gomnd // Version number
+				sw.PushUint32(uint32(path.Action))
+			}
+		}
+	}
+}
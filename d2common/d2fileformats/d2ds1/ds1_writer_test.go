@@ -0,0 +1,111 @@
+package d2ds1
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+// ds1Fixture returns a 2x1 DS1 of the version, with one wall and one floor layer, a shadow and a substitution layer,
+// two objects, one of them with NPC paths, and a substitution group.
+func ds1Fixture(version uint32) []byte {
+	sw := d2common.CreateStreamWriter()
+	push := func(values ...uint32) {
+		for _, value := range values {
+			sw.PushUint32(value)
+		}
+	}
+
+	push(version, 1, 0, 2, 2)
+
+	push(2)
+
+	for _, file := range []string{`C:\Diablo II\tiles\act1\town\floor.dt1`, "wall.tg1"} {
+		for idx := 0; idx < len(file); idx++ {
+			sw.PushByte(file[idx])
+		}
+
+		sw.PushByte(0)
+	}
+
+	if version <= 13 {
+		push(0xDEADBEEF, 7)
+	}
+
+	push(1)
+
+	if version >= 16 {
+		push(1)
+	}
+
+	// wall, orientation, floor, shadow and substitution layers of both tiles
+	push(0x8123A405, 0x0251C0FF, 0x0000000D, 0x00000004, 0x00100201, 0x7FFFFFFF, 0x00000000, 0x80000000, 3, 0)
+
+	push(2)
+	push(1, 5, 10, 12, 0, 2, 7, 30, 40, 1)
+
+	if version >= 18 {
+		push(42)
+	}
+
+	push(1, 0, 0, 2, 1, 0)
+
+	if version >= 14 {
+		push(1, 2, 30, 40)
+
+		for _, path := range [][]uint32{{31, 41, 1}, {32, 42, 2}} {
+			if version < 15 {
+				path = path[:2]
+			}
+
+			push(path...)
+		}
+	}
+
+	return sw.GetBytes()
+}
+
+func TestDS1RoundTrip(t *testing.T) {
+	for _, version := range []uint32{12, 14, 18} {
+		data := ds1Fixture(version)
+
+		ds1, err := LoadDS1(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if marshaled := ds1.Marshal(); !bytes.Equal(data, marshaled) {
+			t.Errorf("version %d: an unchanged DS1 should be written back as it was loaded", version)
+		}
+	}
+}
+
+func TestDS1WriteEdits(t *testing.T) {
+	ds1, err := LoadDS1(ds1Fixture(18))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds1.Tiles[0][1].Floors[0].Style = 9
+	ds1.Tiles[0][0].Walls[0].Hidden = false
+	ds1.Objects[0].X = 3
+	ds1.Objects[1].Paths = ds1.Objects[1].Paths[:1]
+
+	edited, err := LoadDS1(ds1.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if edited.Tiles[0][1].Floors[0].Style != 9 || edited.Tiles[0][0].Walls[0].Hidden {
+		t.Error("expected the tile edits to be written")
+	}
+
+	if edited.Objects[0].X != 3 || len(edited.Objects[1].Paths) != 1 || edited.Objects[1].Paths[0].Action != 1 {
+		t.Error("expected the object edits to be written")
+	}
+
+	if edited.Tiles[0][0].Walls[0] != ds1.Tiles[0][0].Walls[0] {
+		t.Error("expected the rest of the wall to be unchanged")
+	}
+}
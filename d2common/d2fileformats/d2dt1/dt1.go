@@ -25,13 +25,20 @@ const (
 // LoadDT1 loads a DT1 record
 //nolint:funlen Can't reduce
 func LoadDT1(fileData []byte) (*DT1, error) {
+	const headerSize = 276
+
+	if len(fileData) < headerSize {
+		return nil, fmt.Errorf("%w: %d bytes is too short for a DT1 header", d2common.ErrCorrupt, len(fileData))
+	}
+
 	result := &DT1{}
 	br := d2common.CreateStreamReader(fileData)
 	ver1 := br.GetInt32()
 	ver2 := br.GetInt32()
 
 	if ver1 != 7 || ver2 != 6 {
-		return nil, fmt.Errorf("expected to have a version of 7.6, but got %d.%d instead", ver1, ver2)
+		return nil, fmt.Errorf("%w: expected to have a version of 7.6, but got %d.%d instead",
+			d2common.ErrUnsupportedVersion, ver1, ver2)
 	}
 
 	br.SkipBytes(260) //nolint:gomnd // Unknown data
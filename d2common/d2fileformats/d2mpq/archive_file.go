@@ -0,0 +1,39 @@
+package d2mpq
+
+import (
+	"io"
+)
+
+// archiveFile is the data of an archive, read at offsets so concurrent reads don't share a file position.
+type archiveFile interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// mappedFile is an archive file mapped in memory, read without copying it all in memory first.
+type mappedFile struct {
+	data  []byte
+	unmap func([]byte) error
+}
+
+// ReadAt copies the bytes of the mapping at the offset into the buffer.
+func (m *mappedFile) ReadAt(buffer []byte, offset int64) (int, error) {
+	if offset < 0 || offset >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+
+	read := copy(buffer, m.data[offset:])
+	if read < len(buffer) {
+		return read, io.EOF
+	}
+
+	return read, nil
+}
+
+// Close unmaps the file.
+func (m *mappedFile) Close() error {
+	data := m.data
+	m.data = nil
+
+	return m.unmap(data)
+}
@@ -0,0 +1,78 @@
+package d2mpq
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+func TestLoadMappedReadsLikeLoad(t *testing.T) {
+	files := testArchiveFiles()
+	path := writeTestArchive(t, files)
+
+	read, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer read.(*MPQ).Close()
+
+	mapped, err := LoadMapped(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer mapped.(*MPQ).Close()
+
+	if _, isMapped := mapped.(*MPQ).file.(*mappedFile); !isMapped && runtime.GOOS == "linux" {
+		t.Fatal("LoadMapped didn't map the archive")
+	}
+
+	for _, file := range files {
+		fromFile := readTestFile(t, read, file.name)
+		fromMapping := readTestFile(t, mapped, file.name)
+
+		if !bytes.Equal(fromFile, file.data) {
+			t.Errorf("%s: read %d bytes from the file, not the %d written", file.name, len(fromFile), len(file.data))
+		}
+
+		if !bytes.Equal(fromMapping, fromFile) {
+			t.Errorf("%s: read different bytes from the mapping and the file", file.name)
+		}
+	}
+}
+
+func TestMappedFileReadAt(t *testing.T) {
+	file := &mappedFile{data: []byte("archive"), unmap: func([]byte) error { return nil }}
+	buffer := make([]byte, 4)
+
+	if read, err := file.ReadAt(buffer, 0); read != 4 || err != nil || string(buffer) != "arch" {
+		t.Errorf("ReadAt(0) = %d, %v, %q", read, err, buffer)
+	}
+
+	if read, err := file.ReadAt(buffer, 5); read != 2 || err != io.EOF || string(buffer[:read]) != "ve" {
+		t.Errorf("ReadAt past the end = %d, %v, %q", read, err, buffer[:read])
+	}
+
+	if read, err := file.ReadAt(buffer, 7); read != 0 || err != io.EOF {
+		t.Errorf("ReadAt(7) = %d, %v", read, err)
+	}
+
+	if err := file.Close(); err != nil || file.data != nil {
+		t.Errorf("Close = %v, with %d bytes still mapped", err, len(file.data))
+	}
+}
+
+func readTestFile(t *testing.T, archive d2interface.Archive, name string) []byte {
+	t.Helper()
+
+	data, err := archive.ReadFile(name)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+
+	return data
+}
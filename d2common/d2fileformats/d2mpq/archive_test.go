@@ -0,0 +1,153 @@
+package d2mpq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testSectorSizeShift makes the archives built by writeTestArchive split files in sectors of 0x200 << 3 bytes.
+const testSectorSizeShift = 3
+
+// testArchiveFile is a file written to the archives built by writeTestArchive.
+type testArchiveFile struct {
+	name       string
+	data       []byte
+	compressed bool // whether the sectors of the file are compressed with zlib
+}
+
+// testArchiveFiles returns a palette and a sprite spanning several sectors, compressed, and an uncompressed text file.
+func testArchiveFiles() []testArchiveFile {
+	palette := make([]byte, 768)
+	for index := range palette {
+		palette[index] = byte(index / 96 * 32) // a ramp of 8 colors
+	}
+
+	sprite := make([]byte, 10000)
+	for index := range sprite {
+		sprite[index] = byte(index % 251)
+	}
+
+	return []testArchiveFile{
+		{name: testPalettePath, data: palette, compressed: true},
+		{name: `data\global\ui\cursor\ohand.dc6`, data: sprite, compressed: true},
+		{name: `data\global\excel\levels.txt`, data: bytes.Repeat([]byte("Name\tId\tAct\n"), 100)},
+	}
+}
+
+// writeTestArchive writes an MPQ archive of the files in a temporary directory, removed once the test ends, and
+// returns its path.
+func writeTestArchive(tb testing.TB, files []testArchiveFile) string {
+	tb.Helper()
+
+	header := Data{
+		Magic:             [4]byte{'M', 'P', 'Q', 0x1A},
+		HeaderSize:        uint32(binary.Size(Data{})),
+		FormatVersion:     0,
+		BlockSize:         testSectorSizeShift,
+		HashTableEntries:  uint32(len(files)),
+		BlockTableEntries: uint32(len(files)),
+	}
+
+	var body bytes.Buffer
+
+	hashTable := make([]uint32, 0, len(files)*4)
+	blockTable := make([]uint32, 0, len(files)*4)
+
+	for index, file := range files {
+		position := header.HeaderSize + uint32(body.Len())
+		flags := FileExists
+		stored := file.data
+
+		if file.compressed {
+			flags |= FileCompress
+			stored = compressSectors(tb, file.data, 0x200<<testSectorSizeShift)
+		}
+
+		body.Write(stored)
+
+		hashTable = append(hashTable, hashString(file.name, 1), hashString(file.name, 2), 0, uint32(index))
+		blockTable = append(blockTable, position, uint32(len(stored)), uint32(len(file.data)), uint32(flags))
+	}
+
+	header.HashTableOffset = header.HeaderSize + uint32(body.Len())
+	header.BlockTableOffset = header.HashTableOffset + uint32(len(hashTable)*4)
+	header.ArchiveSize = header.BlockTableOffset + uint32(len(blockTable)*4)
+
+	encrypt(hashTable, hashString("(hash table)", 3))
+	encrypt(blockTable, hashString("(block table)", 3))
+
+	var archive bytes.Buffer
+
+	for _, part := range []interface{}{header, body.Bytes(), hashTable, blockTable} {
+		if err := binary.Write(&archive, binary.LittleEndian, part); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "d2mpq")
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	tb.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "test.mpq")
+	if err := ioutil.WriteFile(path, archive.Bytes(), 0o600); err != nil {
+		tb.Fatal(err)
+	}
+
+	return path
+}
+
+// compressSectors splits the data in sectors compressed with zlib, preceded by the table of their offsets. Sectors
+// which don't get smaller are stored as they are, like archivers do.
+func compressSectors(tb testing.TB, data []byte, sectorSize int) []byte {
+	sectorCount := (len(data) + sectorSize - 1) / sectorSize
+	offsets := make([]uint32, sectorCount+1)
+
+	var sectors bytes.Buffer
+
+	for index := 0; index < sectorCount; index++ {
+		end := (index + 1) * sectorSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		sector := data[index*sectorSize : end]
+		compressed := append([]byte{compressionZlib}, zlibCompress(tb, sector)...)
+
+		if len(compressed) >= len(sector) {
+			compressed = sector
+		}
+
+		offsets[index] = uint32(len(offsets)*4 + sectors.Len())
+		sectors.Write(compressed)
+	}
+
+	offsets[sectorCount] = uint32(len(offsets)*4 + sectors.Len())
+
+	var stored bytes.Buffer
+
+	_ = binary.Write(&stored, binary.LittleEndian, offsets)
+	stored.Write(sectors.Bytes())
+
+	return stored.Bytes()
+}
+
+// encrypt is the inverse of decrypt, encrypting the tables of the archives built by writeTestArchive.
+func encrypt(data []uint32, seed uint32) {
+	seed2 := uint32(0xeeeeeeee)
+
+	for i := range data {
+		seed2 += cryptoLookup(0x400 + (seed & 0xff))
+		plain := data[i]
+		data[i] ^= seed + seed2
+
+		seed = ((^seed << 21) + 0x11111111) | (seed >> 11)
+		seed2 = plain + seed2 + (seed2 << 5) + 3
+	}
+}
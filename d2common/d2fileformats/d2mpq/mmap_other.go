@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly
+
+package d2mpq
+
+import (
+	"os"
+)
+
+// mapFile returns the file as it is, as memory mapping isn't available on this platform.
+func mapFile(file *os.File) (archiveFile, error) {
+	return file, nil
+}
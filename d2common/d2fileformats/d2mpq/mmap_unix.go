@@ -0,0 +1,36 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package d2mpq
+
+import (
+	"log"
+	"os"
+	"syscall"
+)
+
+// mapFile maps the whole file in memory, read only, and closes the file, which the mapping doesn't need. An empty
+// file, or one the file system can't map, is read as it is.
+func mapFile(file *os.File) (archiveFile, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() == 0 {
+		return file, nil
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		log.Printf("failed to map %s in memory, reading it instead: %v", file.Name(), err)
+		return file, nil
+	}
+
+	if err := file.Close(); err != nil {
+		_ = syscall.Munmap(data)
+		return nil, err
+	}
+
+	return &mappedFile{data: data, unmap: syscall.Munmap}, nil
+}
@@ -3,7 +3,8 @@ package d2mpq
 import (
 	"bufio"
 	"encoding/binary"
-	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 )
 
@@ -20,10 +22,11 @@ var _ d2interface.Archive = &MPQ{} // Static check to confirm struct conforms to
 // MPQ represents an MPQ archive
 type MPQ struct {
 	filePath          string
-	file              *os.File
+	file              archiveFile
 	hashEntryMap      HashEntryMap
 	blockTableEntries []BlockTableEntry
 	data              Data
+	sectors           d2interface.Cache // decompressed sectors of the files, by file position and sector index
 }
 
 // Data Represents a MPQ file
@@ -100,21 +103,48 @@ func (v BlockTableEntry) HasFlag(flag FileFlag) bool {
 
 // Load loads an MPQ file and returns a MPQ structure
 func Load(fileName string) (d2interface.Archive, error) {
-	result := &MPQ{filePath: fileName}
+	return load(fileName, false)
+}
+
+// LoadMapped loads an MPQ file like Load, but maps the file in memory and reads the sectors of the files in the
+// archive from the mapping on demand, leaving it to the operating system to page the archive in and out. This keeps
+// the resident memory of large archives low. Where memory mapping isn't available, the file is read like Load does.
+func LoadMapped(fileName string) (d2interface.Archive, error) {
+	return load(fileName, true)
+}
+
+func load(fileName string, mapped bool) (d2interface.Archive, error) {
+	result := &MPQ{filePath: fileName, sectors: d2common.CreateCache(sectorCacheBudget)}
+
+	var (
+		file *os.File
+		err  error
+	)
 
-	var err error
 	if runtime.GOOS == "linux" {
-		result.file, err = openIgnoreCase(fileName)
+		file, err = openIgnoreCase(fileName)
 	} else {
-		result.file, err = os.Open(fileName) //nolint:gosec Will fix later
+		file, err = os.Open(fileName) //nolint:gosec Will fix later
 	}
 
-	if err != nil {
-		return nil, err
+	if os.IsNotExist(err) {
+		return nil, d2common.WrapAssetError(fileName, fmt.Errorf("%w: %v", d2common.ErrNotFound, err))
+	} else if err != nil {
+		return nil, d2common.WrapAssetError(fileName, err)
+	}
+
+	result.file = file
+
+	if mapped {
+		if result.file, err = mapFile(file); err != nil {
+			_ = file.Close()
+			return nil, d2common.WrapAssetError(fileName, err)
+		}
 	}
 
 	if err := result.readHeader(); err != nil {
-		return nil, err
+		_ = result.file.Close()
+		return nil, d2common.WrapAssetError(fileName, err)
 	}
 
 	return result, nil
@@ -148,14 +178,15 @@ func openIgnoreCase(mpqPath string) (*os.File, error) {
 }
 
 func (v *MPQ) readHeader() error {
-	err := binary.Read(v.file, binary.LittleEndian, &v.data)
+	header := io.NewSectionReader(v.file, 0, int64(binary.Size(v.data)))
+	err := binary.Read(header, binary.LittleEndian, &v.data)
 
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %v", d2common.ErrCorrupt, err)
 	}
 
 	if string(v.data.Magic[:]) != "MPQ\x1A" {
-		return errors.New("invalid mpq header")
+		return fmt.Errorf("%w: invalid mpq header", d2common.ErrCorrupt)
 	}
 
 	v.loadHashTable()
@@ -165,18 +196,8 @@ func (v *MPQ) readHeader() error {
 }
 
 func (v *MPQ) loadHashTable() {
-	_, err := v.file.Seek(int64(v.data.HashTableOffset), 0)
-	if err != nil {
-		log.Panic(err)
-	}
-
 	hashData := make([]uint32, v.data.HashTableEntries*4) //nolint:gomnd Decryption magic
-	hash := make([]byte, 4)
-
-	for i := range hashData {
-		_, _ = v.file.Read(hash)
-		hashData[i] = binary.LittleEndian.Uint32(hash)
-	}
+	v.readTable(int64(v.data.HashTableOffset), hashData)
 
 	decrypt(hashData, hashString("(hash table)", 3))
 
@@ -193,18 +214,8 @@ func (v *MPQ) loadHashTable() {
 }
 
 func (v *MPQ) loadBlockTable() {
-	_, err := v.file.Seek(int64(v.data.BlockTableOffset), 0)
-	if err != nil {
-		log.Panic(err)
-	}
-
 	blockData := make([]uint32, v.data.BlockTableEntries*4) //nolint:gomnd binary data
-	hash := make([]byte, 4)
-
-	for i := range blockData {
-		_, _ = v.file.Read(hash[:]) //nolint:errcheck Will fix later
-		blockData[i] = binary.LittleEndian.Uint32(hash)
-	}
+	v.readTable(int64(v.data.BlockTableOffset), blockData)
 
 	decrypt(blockData, hashString("(block table)", 3))
 
@@ -218,6 +229,17 @@ func (v *MPQ) loadBlockTable() {
 	}
 }
 
+// readTable reads the dwords of a table of the archive starting at the offset. The dwords past the end of the file
+// are left at 0.
+func (v *MPQ) readTable(offset int64, table []uint32) {
+	tableBytes := make([]byte, len(table)*4) //nolint:gomnd binary data
+	_, _ = v.file.ReadAt(tableBytes, offset)
+
+	for i := range table {
+		table[i] = binary.LittleEndian.Uint32(tableBytes[i*4:]) //nolint:gomnd binary data
+	}
+}
+
 func decrypt(data []uint32, seed uint32) {
 	seed2 := uint32(0xeeeeeeee) //nolint:gomnd Decryption magic
 
@@ -266,7 +288,7 @@ func (v *MPQ) getFileBlockData(fileName string) (BlockTableEntry, error) {
 	fileEntry, found := v.hashEntryMap.Find(fileName)
 
 	if !found || fileEntry.BlockIndex >= uint32(len(v.blockTableEntries)) {
-		return BlockTableEntry{}, errors.New("file not found")
+		return BlockTableEntry{}, d2common.WrapAssetError(fileName, d2common.ErrNotFound)
 	}
 
 	return v.blockTableEntries[fileEntry.BlockIndex], nil
@@ -2,9 +2,9 @@ package d2mpq
 
 import (
 	"bytes"
+	"compress/bzip2"
 	"compress/zlib"
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -55,18 +55,16 @@ func CreateStream(mpq *MPQ, blockTableEntry BlockTableEntry, fileName string) (*
 		err = result.loadBlockOffsets()
 	}
 
-	return result, err
+	return result, d2common.WrapAssetError(fileName, err)
 }
 
 func (v *Stream) loadBlockOffsets() error {
 	blockPositionCount := ((v.BlockTableEntry.UncompressedFileSize + v.BlockSize - 1) / v.BlockSize) + 1
 	v.BlockPositions = make([]uint32, blockPositionCount)
 
-	_, _ = v.MPQData.file.Seek(int64(v.BlockTableEntry.FilePosition), 0)
-
 	mpqBytes := make([]byte, blockPositionCount*4) //nolint:gomnd MPQ magic
 
-	_, _ = v.MPQData.file.Read(mpqBytes)
+	_, _ = v.MPQData.file.ReadAt(mpqBytes, int64(v.BlockTableEntry.FilePosition))
 
 	for i := range v.BlockPositions {
 		idx := i * 4 //nolint:gomnd MPQ magic
@@ -80,12 +78,12 @@ func (v *Stream) loadBlockOffsets() error {
 
 		if v.BlockPositions[0] != blockPosSize {
 			log.Println("Decryption of MPQ failed!")
-			return errors.New("decryption of MPQ failed")
+			return fmt.Errorf("%w: decryption of MPQ failed", d2common.ErrCorrupt)
 		}
 
 		if v.BlockPositions[1] > v.BlockSize+blockPosSize {
 			log.Println("Decryption of MPQ failed!")
-			return errors.New("decryption of MPQ failed")
+			return fmt.Errorf("%w: decryption of MPQ failed", d2common.ErrCorrupt)
 		}
 	}
 
@@ -160,8 +158,7 @@ func (v *Stream) bufferData() {
 
 func (v *Stream) loadSingleUnit() {
 	fileData := make([]byte, v.BlockSize)
-	_, _ = v.MPQData.file.Seek(int64(v.MPQData.data.HeaderSize), 0)
-	_, _ = v.MPQData.file.Read(fileData)
+	_, _ = v.MPQData.file.ReadAt(fileData, int64(v.MPQData.data.HeaderSize))
 
 	if v.BlockSize == v.BlockTableEntry.UncompressedFileSize {
 		v.CurrentData = fileData
@@ -172,9 +169,15 @@ func (v *Stream) loadSingleUnit() {
 }
 
 func (v *Stream) loadBlock(blockIndex, expectedLength uint32) []byte {
+	key := sectorKey(v.BlockTableEntry, blockIndex)
+	if data, found := v.MPQData.cachedSector(key); found {
+		return data
+	}
+
 	var (
-		offset uint32
-		toRead uint32
+		offset       uint32
+		toRead       uint32
+		decompressed bool
 	)
 
 	if v.BlockTableEntry.HasFlag(FileCompress) || v.BlockTableEntry.HasFlag(FileImplode) {
@@ -188,8 +191,7 @@ func (v *Stream) loadBlock(blockIndex, expectedLength uint32) []byte {
 	offset += v.BlockTableEntry.FilePosition
 	data := make([]byte, toRead)
 
-	_, _ = v.MPQData.file.Seek(int64(offset), 0)
-	_, _ = v.MPQData.file.Read(data)
+	_, _ = v.MPQData.file.ReadAt(data, int64(offset))
 
 	if v.BlockTableEntry.HasFlag(FileEncrypted) && v.BlockTableEntry.UncompressedFileSize > 3 {
 		if v.EncryptionSeed == 0 {
@@ -205,66 +207,90 @@ func (v *Stream) loadBlock(blockIndex, expectedLength uint32) []byte {
 		} else {
 			data = pkDecompress(data)
 		}
+
+		decompressed = true
 	}
 
 	if v.BlockTableEntry.HasFlag(FileImplode) && (toRead != expectedLength) {
 		data = pkDecompress(data)
+		decompressed = true
+	}
+
+	if decompressed {
+		v.MPQData.cacheSector(key, data)
 	}
 
 	return data
 }
 
-//nolint:gomnd Will fix enum values later
+// Compression methods of a sector, combined in its first byte
+const (
+	compressionHuffman     = 0x01
+	compressionZlib        = 0x02
+	compressionPKWare      = 0x08
+	compressionBZip2       = 0x10
+	compressionSparse      = 0x20
+	compressionADPCMMono   = 0x40
+	compressionADPCMStereo = 0x80
+
+	// compressionLZMA is LZMA alone, and isn't combined with the other methods
+	compressionLZMA = 0x12
+)
+
+// sectorDecompressor undoes one of the compression methods of a sector.
+type sectorDecompressor struct {
+	method     byte
+	decompress func([]byte) []byte
+}
+
+// sectorDecompressors are the compression methods in the order they are undone, which is the reverse of the order
+// they are applied in when a sector is compressed with more than one.
+//nolint:gochecknoglobals // constant lookup table
+var sectorDecompressors = []sectorDecompressor{
+	{compressionBZip2, bzip2Decompress},
+	{compressionPKWare, pkDecompress},
+	{compressionZlib, deflate},
+	{compressionHuffman, d2compression.HuffmanDecompress},
+	{compressionADPCMStereo, func(data []byte) []byte { return d2compression.WavDecompress(data, 2) }},
+	{compressionADPCMMono, func(data []byte) []byte { return d2compression.WavDecompress(data, 1) }},
+}
+
+// decompressMulti decompresses a sector compressed with the methods its first byte combines, undoing each in turn.
 func decompressMulti(data []byte /*expectedLength*/, _ uint32) []byte {
 	compressionType := data[0]
 
-	switch compressionType {
-	case 1: // Huffman
-		panic("huffman decompression not supported")
-	case 2: // ZLib/Deflate
-		return deflate(data[1:])
-	case 8: // PKLib/Impode
-		return pkDecompress(data[1:])
-	case 0x10: // BZip2
-		panic("bzip2 decompression not supported")
-	case 0x80: // IMA ADPCM Stereo
-		return d2compression.WavDecompress(data[1:], 2)
-	case 0x40: // IMA ADPCM Mono
-		return d2compression.WavDecompress(data[1:], 1)
-	case 0x12:
+	switch {
+	case compressionType == compressionLZMA:
 		panic("lzma decompression not supported")
-	// Combos
-	case 0x22:
-		// sparse then zlib
-		panic("sparse decompression + deflate decompression not supported")
-	case 0x30:
-		// sparse then bzip2
-		panic("sparse decompression + bzip2 decompression not supported")
-	case 0x41:
-		sinput := d2compression.HuffmanDecompress(data[1:])
-		sinput = d2compression.WavDecompress(sinput, 1)
-		tmp := make([]byte, len(sinput))
-
-		copy(tmp, sinput)
-
-		return tmp
-	case 0x48:
-		//byte[] result = PKDecompress(sinput, outputLength);
-		//return MpqWavCompression.Decompress(new MemoryStream(result), 1);
-		panic("pk + mpqwav decompression not supported")
-	case 0x81:
-		sinput := d2compression.HuffmanDecompress(data[1:])
-		sinput = d2compression.WavDecompress(sinput, 2)
-		tmp := make([]byte, len(sinput))
-		copy(tmp, sinput)
-		return tmp
-	case 0x88:
-		//byte[] result = PKDecompress(sinput, outputLength);
-		//return MpqWavCompression.Decompress(new MemoryStream(result), 2);
-		panic("pk + wav decompression not supported")
-	default:
+	case compressionType&compressionSparse != 0:
+		panic(fmt.Sprintf("sparse decompression not supported for compression type %X", compressionType))
+	}
+
+	remaining := compressionType
+	data = data[1:]
+
+	for _, decompressor := range sectorDecompressors {
+		if compressionType&decompressor.method != 0 {
+			data = decompressor.decompress(data)
+			remaining &^= decompressor.method
+		}
+	}
+
+	if remaining != 0 {
 		panic(fmt.Sprintf("decompression not supported for unknown compression type %X", compressionType))
 	}
+
+	return data
+}
+
+func bzip2Decompress(data []byte) []byte {
+	buffer := new(bytes.Buffer)
+
+	if _, err := buffer.ReadFrom(bzip2.NewReader(bytes.NewReader(data))); err != nil {
+		panic(err)
+	}
+
+	return buffer.Bytes()
 }
 
 func deflate(data []byte) []byte {
@@ -0,0 +1,138 @@
+package d2mpq
+
+import (
+	"bytes"
+	"compress/zlib"
+	"testing"
+
+	"github.com/JoshVarga/blast"
+)
+
+// bzip2Fixture is "a sector compressed with bzip2, " four times, compressed with bzip2.
+//nolint:gochecknoglobals // test fixture
+var bzip2Fixture = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x1b, 0x14, 0x99, 0x9e, 0x00, 0x00,
+	0x0f, 0x99, 0x80, 0x40, 0x04, 0x10, 0x00, 0x3e, 0x62, 0xdc, 0x90, 0x20, 0x00, 0x50, 0xa6, 0x99,
+	0x18, 0x98, 0x98, 0x81, 0x55, 0x41, 0xea, 0x62, 0x64, 0xd3, 0x4f, 0x53, 0x72, 0xc6, 0x4e, 0x8b,
+	0x14, 0x32, 0x54, 0xda, 0xe3, 0xd1, 0x53, 0x44, 0x92, 0x49, 0xa2, 0x0f, 0x89, 0x20, 0xc1, 0x82,
+	0xe6, 0x0a, 0x16, 0x20, 0x93, 0xf1, 0x77, 0x24, 0x53, 0x85, 0x09, 0x01, 0xb1, 0x49, 0x99, 0xe0,
+}
+
+// huffmanFixture is "a sector compressed with huffman, a sector compressed with huffman", compressed with Huffman
+// compression type 1.
+//nolint:gochecknoglobals // test fixture
+var huffmanFixture = []byte{
+	0x01, 0x2f, 0x49, 0xa5, 0x88, 0xf7, 0x49, 0xd5, 0x52, 0x9c, 0x34, 0x77, 0xa5, 0x5a, 0xa1, 0x52,
+	0x29, 0x50, 0x12, 0x79, 0x7d, 0xbf, 0x46, 0xaa, 0xa1, 0xd6, 0xd6, 0xe6, 0x7e, 0x71, 0xb1, 0x4b,
+	0x2f, 0x49, 0xa5, 0x88, 0xf7, 0x49, 0xd5, 0x52, 0x9c, 0x34, 0x77, 0xa5, 0x5a, 0xa1, 0x52, 0x29,
+	0x50, 0x12, 0x79, 0x7d, 0xbf, 0x46, 0xaa, 0xa1, 0xd6, 0xd6, 0xe6, 0x7e, 0x71, 0x35, 0x07,
+}
+
+// adpcmMonoFixture is the samples 1000, 1000, 1112 and 602 compressed with ADPCM: a shift of 2, the first sample,
+// then a repeat of the previous sample, an increase and a decrease.
+//nolint:gochecknoglobals // test fixture
+var adpcmMonoFixture = []byte{0x00, 0x02, 0xe8, 0x03, 0x80, 0x00, 0x41}
+
+// adpcmStereoFixture is the samples 1000, -1000, 1123 and -1123 of two interleaved channels compressed with ADPCM: a
+// shift of 2, the first sample of each channel, then an increase of the first channel and a decrease of the second.
+//nolint:gochecknoglobals // test fixture
+var adpcmStereoFixture = []byte{0x00, 0x02, 0xe8, 0x03, 0x18, 0xfc, 0x00, 0x40}
+
+// huffmanADPCMMonoFixture is adpcmMonoFixture compressed with Huffman compression type 1, as wav sectors are.
+//nolint:gochecknoglobals // test fixture
+var huffmanADPCMMonoFixture = []byte{0x01, 0xf7, 0xd3, 0xc8, 0xdb, 0x63, 0x6a, 0x0e}
+
+// huffmanADPCMStereoFixture is adpcmStereoFixture compressed with Huffman compression type 1.
+//nolint:gochecknoglobals // test fixture
+var huffmanADPCMStereoFixture = []byte{0x01, 0xf7, 0xd3, 0xc8, 0xfb, 0xf6, 0x2e, 0xda, 0x1c}
+
+func zlibCompress(t testing.TB, data []byte) []byte {
+	var buffer bytes.Buffer
+
+	writer := zlib.NewWriter(&buffer)
+
+	if _, err := writer.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+// pcm returns the 16 bit little endian samples.
+func pcm(samples ...int16) []byte {
+	data := make([]byte, 0, len(samples)*2)
+
+	for _, sample := range samples {
+		data = append(data, byte(sample), byte(sample>>8))
+	}
+
+	return data
+}
+
+func pkCompress(t *testing.T, data []byte) []byte {
+	var buffer bytes.Buffer
+
+	writer := blast.NewWriter(&buffer, blast.Binary, blast.DictionarySize4096)
+
+	if _, err := writer.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestDecompressMulti(t *testing.T) {
+	sector := bytes.Repeat([]byte("a sector compressed with bzip2, "), 4)
+	huffmanSector := []byte("a sector compressed with huffman, a sector compressed with huffman")
+	text := bytes.Repeat([]byte("Data\\Global\\Palette\\ACT1\\pal.dat "), 64)
+	monoSamples := pcm(1000, 1000, 1112, 602)
+	stereoSamples := pcm(1000, -1000, 1123, -1123)
+
+	tests := []struct {
+		name        string
+		compression byte
+		data        []byte
+		expected    []byte
+	}{
+		{"zlib", compressionZlib, zlibCompress(t, text), text},
+		{"pkware", compressionPKWare, pkCompress(t, text), text},
+		{"bzip2", compressionBZip2, bzip2Fixture, sector},
+		{"pkware after zlib", compressionPKWare | compressionZlib, pkCompress(t, zlibCompress(t, text)), text},
+		{"huffman", compressionHuffman, huffmanFixture, huffmanSector},
+		{"adpcm mono", compressionADPCMMono, adpcmMonoFixture, monoSamples},
+		{"adpcm stereo", compressionADPCMStereo, adpcmStereoFixture, stereoSamples},
+		{"adpcm mono after huffman", compressionADPCMMono | compressionHuffman, huffmanADPCMMonoFixture, monoSamples},
+		{"adpcm stereo after huffman", compressionADPCMStereo | compressionHuffman, huffmanADPCMStereoFixture,
+			stereoSamples},
+	}
+
+	for _, test := range tests {
+		data := append([]byte{test.compression}, test.data...)
+
+		if decompressed := decompressMulti(data, uint32(len(test.expected))); !bytes.Equal(decompressed, test.expected) {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, decompressed)
+		}
+	}
+}
+
+func TestDecompressMultiUnsupported(t *testing.T) {
+	for _, compression := range []byte{compressionLZMA, compressionSparse | compressionZlib, 0x04} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected compression type %X to be unsupported", compression)
+				}
+			}()
+
+			decompressMulti([]byte{compression, 0}, 1)
+		}()
+	}
+}
@@ -0,0 +1,40 @@
+package d2mpq
+
+import (
+	"strconv"
+)
+
+// sectorCacheBudget is the most bytes of decompressed sectors kept for each archive, so the files read again and
+// again, such as palettes and common sprites, aren't decompressed every time.
+const sectorCacheBudget = 1024 * 1024 * 4
+
+// sectorKey returns the key of a sector of a file in the sector cache. Files are told apart by their position in the
+// archive.
+func sectorKey(entry BlockTableEntry, blockIndex uint32) string {
+	return strconv.FormatUint(uint64(entry.FilePosition)<<32|uint64(blockIndex), 16) //nolint:gomnd high dword
+}
+
+// cachedSector returns the decompressed data of a sector, if it is cached.
+func (v *MPQ) cachedSector(key string) ([]byte, bool) {
+	if v.sectors == nil {
+		return nil, false
+	}
+
+	data, found := v.sectors.Retrieve(key)
+	if !found {
+		return nil, false
+	}
+
+	return data.([]byte), true
+}
+
+// cacheSector keeps the decompressed data of a sector. The data is shared by the streams reading it, which only copy
+// from it.
+func (v *MPQ) cacheSector(key string, data []byte) {
+	if v.sectors == nil {
+		return
+	}
+
+	// another stream may have cached the sector since it was looked up, keeping the same data
+	_ = v.sectors.Insert(key, data, len(data))
+}
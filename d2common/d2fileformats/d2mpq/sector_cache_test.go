@@ -0,0 +1,67 @@
+package d2mpq
+
+import (
+	"bytes"
+	"testing"
+)
+
+const testPalettePath = `data\global\palette\act1\pal.dat`
+
+func TestSectorCacheReadsIdenticalBytes(t *testing.T) {
+	files := testArchiveFiles()
+
+	archive, err := Load(writeTestArchive(t, files))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer archive.(*MPQ).Close()
+
+	readTestFile(t, archive, testPalettePath)
+
+	if archive.(*MPQ).sectors.GetWeight() == 0 {
+		t.Fatal("the decompressed palette wasn't cached")
+	}
+
+	for _, file := range files {
+		first := readTestFile(t, archive, file.name)
+
+		// the cached sectors are shared, changing what was read mustn't change what is read next
+		for index := range first {
+			first[index] ^= 0xff
+		}
+
+		if second := readTestFile(t, archive, file.name); !bytes.Equal(second, file.data) {
+			t.Errorf("%s: read different bytes from the cached sectors", file.name)
+		}
+	}
+}
+
+func BenchmarkPaletteReadCached(b *testing.B) {
+	benchmarkPaletteRead(b, true)
+}
+
+func BenchmarkPaletteReadUncached(b *testing.B) {
+	benchmarkPaletteRead(b, false)
+}
+
+func benchmarkPaletteRead(b *testing.B, cached bool) {
+	archive, err := Load(writeTestArchive(b, testArchiveFiles()))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	defer archive.(*MPQ).Close()
+
+	if !cached {
+		archive.(*MPQ).sectors = nil
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := archive.ReadFile(testPalettePath); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
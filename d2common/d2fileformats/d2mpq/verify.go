@@ -0,0 +1,68 @@
+package d2mpq
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+const (
+	hashTableEntrySize  = 16
+	blockTableEntrySize = 16
+
+	// hash table entries which are empty, or whose file was deleted, have no block
+	hashEntryDeleted = 0xFFFFFFFE
+)
+
+// Verify checks the integrity of the archive: that its hash and block tables are within the file, that the hash
+// table only points to entries of the block table, and that the files of the block table are within the archive.
+// The errors are ErrCorrupt.
+func (v *MPQ) Verify() error {
+	if v.data.HeaderSize < uint32(binary.Size(v.data)) {
+		return v.corrupt("a header size of %d bytes is too small", v.data.HeaderSize)
+	}
+
+	hashTableEnd := int64(v.data.HashTableOffset) + int64(v.data.HashTableEntries)*hashTableEntrySize
+	if !v.withinFile(hashTableEnd) {
+		return v.corrupt("the hash table ends at %d, past the end of the file", hashTableEnd)
+	}
+
+	blockTableEnd := int64(v.data.BlockTableOffset) + int64(v.data.BlockTableEntries)*blockTableEntrySize
+	if !v.withinFile(blockTableEnd) {
+		return v.corrupt("the block table ends at %d, past the end of the file", blockTableEnd)
+	}
+
+	for _, entry := range v.hashEntryMap.entries {
+		if entry.BlockIndex < hashEntryDeleted && entry.BlockIndex >= uint32(len(v.blockTableEntries)) {
+			return v.corrupt("a hash table entry points to block %d of %d", entry.BlockIndex, len(v.blockTableEntries))
+		}
+	}
+
+	for index, entry := range v.blockTableEntries {
+		if !entry.HasFlag(FileExists) {
+			continue
+		}
+
+		if fileEnd := int64(entry.FilePosition) + int64(entry.CompressedFileSize); !v.withinFile(fileEnd) {
+			return v.corrupt("the file of block %d ends at %d, past the end of the file", index, fileEnd)
+		}
+	}
+
+	return nil
+}
+
+// withinFile returns true if the archive file is at least as long as the offset.
+func (v *MPQ) withinFile(offset int64) bool {
+	if offset == 0 {
+		return true
+	}
+
+	_, err := v.file.ReadAt(make([]byte, 1), offset-1)
+
+	return err == nil
+}
+
+func (v *MPQ) corrupt(format string, args ...interface{}) error {
+	return d2common.WrapAssetError(v.filePath, fmt.Errorf("%w: %s", d2common.ErrCorrupt, fmt.Sprintf(format, args...)))
+}
@@ -14,9 +14,11 @@ type Animation interface {
 	Advance(elapsed float64) error
 	Render(target Surface) error
 	RenderFromOrigin(target Surface) error
+	RenderFromOriginBatch(target Surface, offsets []image.Point) error
 	RenderSection(sfc Surface, bound image.Rectangle) error
 	GetFrameSize(frameIndex int) (int, int, error)
 	GetCurrentFrameSize() (int, int)
+	GetCurrentFrameRect() image.Rectangle
 	GetFrameBounds() (int, int)
 	GetCurrentFrame() int
 	GetFrameCount() int
@@ -32,6 +34,7 @@ type Animation interface {
 	Pause()
 	SetPlayLoop(loop bool)
 	SetPlaySpeed(playSpeed float64)
+	SetPlayFPS(fps float64)
 	SetPlayLength(playLength float64)
 	SetPlayLengthMs(playLengthMs int)
 	SetColorMod(colorMod color.Color)
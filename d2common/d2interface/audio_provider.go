@@ -4,6 +4,9 @@ package d2interface
 // by the asset manager, and set the game engine's volume levels
 type AudioProvider interface {
 	PlayBGM(song string)
+	PlayStinger(stinger string)
 	LoadSoundEffect(sfx string) (SoundEffect, error)
 	SetVolumes(bgmVolume, sfxVolume float64)
+	SetDucking(amount, attack, release float64)
+	Advance(elapsed float64)
 }
@@ -13,6 +13,12 @@ type KeyRepeatHandler interface {
 	OnKeyRepeat(event KeyEvent) bool
 }
 
+// KeyAutoRepeatHandler represents a handler for a held-down keyboard key repeating, as when typing: once the key has
+// been held for the repeat delay, then at every repeat interval.
+type KeyAutoRepeatHandler interface {
+	OnKeyAutoRepeat(event KeyEvent) bool
+}
+
 // KeyUpHandler represents a handler for a keyboard key release event
 type KeyUpHandler interface {
 	OnKeyUp(event KeyEvent) bool
@@ -33,6 +39,12 @@ type MouseButtonRepeatHandler interface {
 	OnMouseButtonRepeat(event MouseEvent) bool
 }
 
+// MouseButtonDoubleClickHandler represents a handler for a mouse button pressed a second time, within the double
+// click interval and close to the first press.
+type MouseButtonDoubleClickHandler interface {
+	OnMouseButtonDoubleClick(event MouseEvent) bool
+}
+
 // MouseButtonUpHandler represents a handler for a mouse button release event
 type MouseButtonUpHandler interface {
 	OnMouseButtonUp(event MouseEvent) bool
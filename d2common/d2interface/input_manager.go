@@ -8,4 +8,6 @@ type InputManager interface {
 	BindHandlerWithPriority(InputEventHandler, d2enum.Priority) error
 	BindHandler(h InputEventHandler) error
 	UnbindHandler(handler InputEventHandler) error
+	RecordInput(path string, seed int64) error
+	PlayInput(path string) (seed int64, err error)
 }
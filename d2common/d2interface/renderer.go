@@ -14,6 +14,9 @@ type Renderer interface {
 	SetFullScreen(fullScreen bool)
 	SetVSyncEnabled(vsync bool)
 	GetVSyncEnabled() bool
+	SetFrameCap(fps int)
+	GetFrameCap() int
 	GetCursorPos() (int, int)
 	CurrentFPS() float64
+	CurrentDrawCalls() int
 }
@@ -4,4 +4,6 @@ package d2interface
 type SoundEffect interface {
 	Play()
 	Stop()
+	SetVolume(volume float64)
+	SetImportant(important bool)
 }
@@ -5,6 +5,7 @@ import (
 	"image/color"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math"
 )
 
 // Surface represents a renderable surface.
@@ -22,7 +23,15 @@ type Surface interface {
 	PushFilter(filter d2enum.Filter)
 	PushTranslation(x, y int)
 	PushBrightness(brightness float64)
+	// Scales everything drawn, and the translations pushed after it, by the given factor
+	PushScale(scale float64)
+	// Draws everything as a solid shape of the given color, keeping only its alpha
+	PushSilhouette(color color.Color)
+	// Transforms the colors of everything drawn, after the color, brightness, silhouette and effect
+	PushColorMatrix(matrix d2math.ColorMatrix)
 	Render(surface Surface) error
+	// Renders the surface at each of the offsets from the current translation, in one draw call where the backend allows
+	RenderBatch(surface Surface, offsets []image.Point) error
 	// Renders a section of the surface enclosed by bounds
 	RenderSection(surface Surface, bound image.Rectangle) error
 	ReplacePixels(pixels []byte) error
@@ -0,0 +1,81 @@
+package d2logger
+
+import (
+	"io"
+	"log"
+	"os"
+)
+
+//nolint:gochecknoglobals // the default logger is global like the standard logger
+var (
+	defaultLogger  = New(nil)
+	defaultHistory = NewHistory(DefaultHistorySize)
+)
+
+// Init configures the default logger with the given level and categories, and tees the standard logger output to the
+// recent history and, if filePath is not empty, to a rotating file at filePath. The returned closer closes the log file.
+func Init(level Level, categories []Category, filePath string) (io.Closer, error) {
+	SetLevel(level)
+	SetCategories(categories...)
+
+	if filePath == "" {
+		log.SetOutput(io.MultiWriter(os.Stderr, defaultHistory))
+		return nopCloser{}, nil
+	}
+
+	file, err := NewRotatingFile(filePath, DefaultMaxFileSize, DefaultMaxBackups)
+	if err != nil {
+		log.SetOutput(io.MultiWriter(os.Stderr, defaultHistory))
+		return nil, err
+	}
+
+	log.SetOutput(io.MultiWriter(os.Stderr, defaultHistory, file))
+
+	return file, nil
+}
+
+// RecentLines returns the most recent lines written by the standard logger since Init, oldest first.
+func RecentLines() []string {
+	return defaultHistory.Lines()
+}
+
+// SetLevel sets the minimum level of the default logger.
+func SetLevel(level Level) {
+	defaultLogger.SetLevel(level)
+}
+
+// SetCategories restricts the default logger to the given categories. Passing no categories enables all of them.
+func SetCategories(categories ...Category) {
+	defaultLogger.SetCategories(categories...)
+}
+
+// Enabled returns true if the default logger would write a message with the given level and category.
+func Enabled(level Level, category Category) bool {
+	return defaultLogger.Enabled(level, category)
+}
+
+// Debugf writes a debug message with the default logger.
+func Debugf(category Category, format string, args ...interface{}) {
+	defaultLogger.logf(LevelDebug, category, format, args...)
+}
+
+// Infof writes an informational message with the default logger.
+func Infof(category Category, format string, args ...interface{}) {
+	defaultLogger.logf(LevelInfo, category, format, args...)
+}
+
+// Warningf writes a warning message with the default logger.
+func Warningf(category Category, format string, args ...interface{}) {
+	defaultLogger.logf(LevelWarning, category, format, args...)
+}
+
+// Errorf writes an error message with the default logger.
+func Errorf(category Category, format string, args ...interface{}) {
+	defaultLogger.logf(LevelError, category, format, args...)
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
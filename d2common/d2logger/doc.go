@@ -0,0 +1,3 @@
+// Package d2logger provides leveled, categorized logging on top of the standard log package, with optional output to
+// a rotating log file.
+package d2logger
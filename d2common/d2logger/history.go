@@ -0,0 +1,57 @@
+package d2logger
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultHistorySize is the number of recent log lines kept for crash reports
+const DefaultHistorySize = 200
+
+// History is a writer keeping the most recent lines written to it
+type History struct {
+	lines   []string
+	next    int
+	full    bool
+	partial string
+	mutex   sync.Mutex
+}
+
+// NewHistory creates a History keeping up to size lines.
+func NewHistory(size int) *History {
+	return &History{lines: make([]string, size)}
+}
+
+// Write splits p into lines and records them, dropping the oldest lines once the history is full.
+func (h *History) Write(p []byte) (int, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if len(h.lines) == 0 {
+		return len(p), nil
+	}
+
+	text := h.partial + string(p)
+	lines := strings.Split(text, "\n")
+	h.partial = lines[len(lines)-1]
+
+	for _, line := range lines[:len(lines)-1] {
+		h.lines[h.next] = line
+		h.next = (h.next + 1) % len(h.lines)
+		h.full = h.full || h.next == 0
+	}
+
+	return len(p), nil
+}
+
+// Lines returns the recorded lines, oldest first.
+func (h *History) Lines() []string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if !h.full {
+		return append([]string(nil), h.lines[:h.next]...)
+	}
+
+	return append(append([]string(nil), h.lines[h.next:]...), h.lines[:h.next]...)
+}
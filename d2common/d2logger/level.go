@@ -0,0 +1,60 @@
+package d2logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is the severity of a log message
+type Level int32
+
+// Log levels, from the most to the least verbose
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarning
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	}
+
+	return fmt.Sprintf("level(%d)", int32(l))
+}
+
+// ParseLevel returns the level with the given name, one of debug, info, warning (or warn) and error.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warning", "warn":
+		return LevelWarning, nil
+	case "error":
+		return LevelError, nil
+	}
+
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+// Category tags a log message with the engine subsystem it came from
+type Category string
+
+// Log categories
+const (
+	CategoryGeneral Category = "general"
+	CategoryNet     Category = "net"
+	CategoryAsset   Category = "asset"
+	CategoryRender  Category = "render"
+	CategoryAI      Category = "ai"
+)
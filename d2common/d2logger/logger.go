@@ -0,0 +1,100 @@
+package d2logger
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const callDepth = 3 // log.Output <- logf <- Debugf etc. <- caller
+
+// Logger writes leveled, categorized messages to a standard logger. Messages below the minimum level, or in a
+// disabled category, are dropped.
+type Logger struct {
+	level      int32 // accessed atomically so the level check is cheap on hot paths
+	output     *log.Logger
+	mutex      sync.RWMutex
+	categories map[Category]bool // nil enables every category
+}
+
+// New creates a Logger writing to output at the info level with every category enabled. If output is nil, messages
+// are written with the standard logger.
+func New(output *log.Logger) *Logger {
+	return &Logger{level: int32(LevelInfo), output: output}
+}
+
+// SetLevel sets the minimum level of the messages that are written.
+func (l *Logger) SetLevel(level Level) {
+	atomic.StoreInt32(&l.level, int32(level))
+}
+
+// GetLevel returns the minimum level of the messages that are written.
+func (l *Logger) GetLevel() Level {
+	return Level(atomic.LoadInt32(&l.level))
+}
+
+// SetCategories restricts logging to the given categories. Passing no categories enables all of them.
+func (l *Logger) SetCategories(categories ...Category) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(categories) == 0 {
+		l.categories = nil
+		return
+	}
+
+	l.categories = make(map[Category]bool, len(categories))
+	for _, category := range categories {
+		l.categories[category] = true
+	}
+}
+
+// Enabled returns true if a message with the given level and category would be written. Use it to guard expensive
+// debug logging on hot paths.
+func (l *Logger) Enabled(level Level, category Category) bool {
+	if level < l.GetLevel() {
+		return false
+	}
+
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return l.categories == nil || l.categories[category]
+}
+
+// Debugf writes a debug message.
+func (l *Logger) Debugf(category Category, format string, args ...interface{}) {
+	l.logf(LevelDebug, category, format, args...)
+}
+
+// Infof writes an informational message.
+func (l *Logger) Infof(category Category, format string, args ...interface{}) {
+	l.logf(LevelInfo, category, format, args...)
+}
+
+// Warningf writes a warning message.
+func (l *Logger) Warningf(category Category, format string, args ...interface{}) {
+	l.logf(LevelWarning, category, format, args...)
+}
+
+// Errorf writes an error message.
+func (l *Logger) Errorf(category Category, format string, args ...interface{}) {
+	l.logf(LevelError, category, format, args...)
+}
+
+func (l *Logger) logf(level Level, category Category, format string, args ...interface{}) {
+	if !l.Enabled(level, category) {
+		return
+	}
+
+	message := fmt.Sprintf("[%s] [%s] %s", strings.ToUpper(level.String()), category, fmt.Sprintf(format, args...))
+
+	if l.output == nil {
+		_ = log.Output(callDepth, message)
+		return
+	}
+
+	_ = l.output.Output(callDepth, message)
+}
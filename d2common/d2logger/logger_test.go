@@ -0,0 +1,103 @@
+package d2logger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestLoggerFiltersLevelsAndCategories(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := New(log.New(buffer, "", 0))
+
+	logger.Debugf(CategoryNet, "hidden")
+	logger.Infof(CategoryNet, "shown %d", 1)
+
+	logger.SetLevel(LevelDebug)
+	logger.SetCategories(CategoryAI)
+	logger.Debugf(CategoryNet, "hidden")
+	logger.Debugf(CategoryAI, "shown %d", 2)
+
+	expected := "[INFO] [net] shown 1\n[DEBUG] [ai] shown 2\n"
+	if buffer.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buffer.String())
+	}
+
+	if logger.Enabled(LevelError, CategoryRender) {
+		t.Error("expected disabled category to be filtered")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, "warn": LevelWarning, "error": LevelError}
+
+	for name, expected := range tests {
+		level, err := ParseLevel(name)
+		if err != nil || level != expected {
+			t.Errorf("ParseLevel(%q): expected %s, got %s (%v)", name, expected, level, err)
+		}
+	}
+
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "d2logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	filePath := path.Join(dir, "test.log")
+
+	file, err := NewRotatingFile(filePath, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{filePath: "dddddddd", filePath + ".1": "cccccccc", filePath + ".2": "bbbbbbbb"}
+
+	for name, content := range expected {
+		data, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.TrimSpace(string(data)) != content {
+			t.Errorf("%s: expected %q, got %q", name, content, data)
+		}
+	}
+
+	if _, err := os.Stat(filePath + ".3"); !os.IsNotExist(err) {
+		t.Error("expected the oldest backup to be removed")
+	}
+}
+
+func TestHistoryKeepsRecentLines(t *testing.T) {
+	history := NewHistory(2)
+
+	_, _ = history.Write([]byte("one\ntwo\nth"))
+	_, _ = history.Write([]byte("ree\n"))
+
+	lines := history.Lines()
+	if len(lines) != 2 || lines[0] != "two" || lines[1] != "three" {
+		t.Errorf("expected [two three], got %v", lines)
+	}
+}
@@ -0,0 +1,128 @@
+package d2logger
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sync"
+)
+
+const (
+	// DefaultMaxFileSize is the size in bytes at which the log file is rotated
+	DefaultMaxFileSize = 5 * 1024 * 1024
+
+	// DefaultMaxBackups is the number of rotated log files that are kept
+	DefaultMaxBackups = 3
+)
+
+// RotatingFile is a log file that is renamed to path.1 once it grows past a maximum size, shifting older backups
+// to path.2, path.3 and so on. Backups beyond the maximum count are deleted.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	mutex      sync.Mutex
+}
+
+// NewRotatingFile opens or creates the log file at filePath for appending.
+func NewRotatingFile(filePath string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(path.Dir(filePath), 0750); err != nil {
+		return nil, err
+	}
+
+	result := &RotatingFile{path: filePath, maxSize: maxSize, maxBackups: maxBackups}
+
+	if err := result.open(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Write writes p to the log file, rotating it first if p would make it larger than the maximum size.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the log file.
+func (r *RotatingFile) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+
+	return err
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	r.file = file
+	r.size = info.Size()
+
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	r.file = nil
+
+	if r.maxBackups > 0 {
+		if err := os.Remove(r.backupPath(r.maxBackups)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		for i := r.maxBackups - 1; i > 0; i-- {
+			if err := os.Rename(r.backupPath(i), r.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		if err := os.Rename(r.path, r.backupPath(1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return err
+	}
+
+	return r.open()
+}
+
+func (r *RotatingFile) backupPath(index int) string {
+	return fmt.Sprintf("%s.%d", r.path, index)
+}
@@ -0,0 +1,111 @@
+package d2math
+
+import "image/color"
+
+// The weights of the red, green and blue of a color in its luminance.
+const (
+	lumaRed   = 0.299
+	lumaGreen = 0.587
+	lumaBlue  = 0.114
+)
+
+// ColorMatrix transforms colors. Each row gives the red, green, blue and alpha of the transformed color as a weighted
+// sum of the red, green, blue and alpha of the color, between 0 and 1, plus the constant of the last column.
+type ColorMatrix [4][5]float64
+
+// IdentityColorMatrix returns the matrix leaving colors unchanged.
+func IdentityColorMatrix() ColorMatrix {
+	return ColorMatrix{
+		{1, 0, 0, 0, 0},
+		{0, 1, 0, 0, 0},
+		{0, 0, 1, 0, 0},
+		{0, 0, 0, 1, 0},
+	}
+}
+
+// InvertColorMatrix returns the matrix inverting the red, green and blue of colors, keeping their alpha.
+func InvertColorMatrix() ColorMatrix {
+	return ColorMatrix{
+		{-1, 0, 0, 0, 1},
+		{0, -1, 0, 0, 1},
+		{0, 0, -1, 0, 1},
+		{0, 0, 0, 1, 0},
+	}
+}
+
+// DesaturateColorMatrix returns the matrix moving colors toward their shade of gray by the given amount, from 0 for
+// unchanged colors to 1 for grays.
+func DesaturateColorMatrix(amount float64) ColorMatrix {
+	amount = ClampFloat64(amount, 0, 1)
+	m := IdentityColorMatrix()
+
+	for row := 0; row < 3; row++ {
+		for col, luma := range [3]float64{lumaRed, lumaGreen, lumaBlue} {
+			m[row][col] += (luma - m[row][col]) * amount
+		}
+	}
+
+	return m
+}
+
+// TintColorMatrix returns the matrix multiplying colors by the given color, alpha included.
+func TintColorMatrix(tint color.Color) ColorMatrix {
+	r, g, b, a := tint.RGBA()
+	m := ColorMatrix{}
+
+	for idx, component := range [4]uint32{r, g, b, a} {
+		m[idx][idx] = float64(component) / 0xffff
+	}
+
+	return m
+}
+
+// Concat returns the matrix applying this matrix, then the other one.
+func (m ColorMatrix) Concat(other ColorMatrix) ColorMatrix {
+	var result ColorMatrix
+
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 5; col++ {
+			for idx := 0; idx < 4; idx++ {
+				result[row][col] += other[row][idx] * m[idx][col]
+			}
+		}
+
+		result[row][4] += other[row][4]
+	}
+
+	return result
+}
+
+// Lerp returns the matrix the given amount of the way from this matrix, at 0, to the other one, at 1. Lerping from the
+// identity matrix weakens the other matrix.
+func (m ColorMatrix) Lerp(other ColorMatrix, amount float64) ColorMatrix {
+	var result ColorMatrix
+
+	for row := range result {
+		for col := range result[row] {
+			result[row][col] = m[row][col] + (other[row][col]-m[row][col])*amount
+		}
+	}
+
+	return result
+}
+
+// Apply returns the color transformed by the matrix, each component clamped between 0 and 255.
+func (m ColorMatrix) Apply(c color.NRGBA) color.NRGBA {
+	in := [5]float64{float64(c.R) / 0xff, float64(c.G) / 0xff, float64(c.B) / 0xff, float64(c.A) / 0xff, 1}
+
+	var out [4]uint8
+
+	for row := range out {
+		sum := 0.0
+
+		for col, value := range in {
+			sum += m[row][col] * value
+		}
+
+		out[row] = uint8(ClampFloat64(sum, 0, 1)*0xff + 0.5)
+	}
+
+	return color.NRGBA{R: out[0], G: out[1], B: out[2], A: out[3]}
+}
@@ -0,0 +1,34 @@
+package d2math
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestColorMatrixApply(t *testing.T) {
+	in := color.NRGBA{R: 200, G: 100, B: 0, A: 255}
+
+	tests := []struct {
+		name   string
+		matrix ColorMatrix
+		want   color.NRGBA
+	}{
+		{"identity", IdentityColorMatrix(), in},
+		{"invert", InvertColorMatrix(), color.NRGBA{R: 55, G: 155, B: 255, A: 255}},
+		{"no desaturation", DesaturateColorMatrix(0), in},
+		{"full desaturation", DesaturateColorMatrix(1), color.NRGBA{R: 118, G: 118, B: 118, A: 255}},
+		{"tint", TintColorMatrix(color.NRGBA{R: 255, G: 0, B: 255, A: 255}), color.NRGBA{R: 200, A: 255}},
+		{"invert twice", InvertColorMatrix().Concat(InvertColorMatrix()), in},
+		{
+			"tint then invert",
+			TintColorMatrix(color.NRGBA{R: 255, G: 0, B: 255, A: 255}).Concat(InvertColorMatrix()),
+			color.NRGBA{R: 55, G: 255, B: 255, A: 255},
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.matrix.Apply(in); got != test.want {
+			t.Errorf("%s: wanted %v: got %v", test.name, test.want, got)
+		}
+	}
+}
@@ -179,6 +179,9 @@ const (
 	LevelMaze          = "/data/global/excel/LvlMaze.txt"
 	LevelSubstitutions = "/data/global/excel/LvlSub.txt"
 
+	// AreaLighting isn't a file of the game, mods add it to the override directory to tint areas
+	AreaLighting = "/data/global/excel/AreaLighting.txt"
+
 	ObjectDetails    = "/data/global/excel/Objects.txt"
 	SoundSettings    = "/data/global/excel/Sounds.txt"
 	ItemStatCost     = "/data/global/excel/ItemStatCost.txt"
@@ -188,6 +191,7 @@ const (
 	AutoMap          = "/data/global/excel/AutoMap.txt"
 	CubeRecipes      = "/data/global/excel/cubemain.txt"
 	Skills           = "/data/global/excel/skills.txt"
+	SkillDesc        = "/data/global/excel/skilldesc.txt"
 
 	// --- Animations ---
 
@@ -62,3 +62,22 @@ func GetMusicDef(regionType d2enum.RegionIdType) *MusicDef {
 
 	return &musicDefs[0]
 }
+
+// GetBossStinger returns the stinger played over the music of the given region when a boss engages the player, or an
+// empty string if the act of the region has none
+func GetBossStinger(regionType d2enum.RegionIdType) string {
+	switch {
+	case regionType >= d2enum.RegonAct5Town:
+		return ""
+	case regionType >= d2enum.RegionAct4Town:
+		return "/data/global/music/Act4/izualaction.wav"
+	case regionType >= d2enum.RegionAct3Town:
+		return "/data/global/music/Act3/orbaction.wav"
+	case regionType >= d2enum.RegionAct2Town:
+		return "/data/global/music/Act2/horadricaction.wav"
+	case regionType >= d2enum.RegionAct1Town:
+		return "/data/global/music/Act1/denofevilaction.wav"
+	}
+
+	return ""
+}
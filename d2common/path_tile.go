@@ -1,6 +1,12 @@
 package d2common
 
-import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
+)
+
+const subTilesPerTile = 5
 
 // PathTile represents a node in path finding
 type PathTile struct {
@@ -47,27 +53,26 @@ func (t *PathTile) PathNeighbors() []d2astar.Pather {
 	return result
 }
 
-// PathNeighborCost calculates the exact movement cost to neighbor nodes
+// PathNeighborCost calculates the exact movement cost to neighbor nodes. A straight step costs 1 and a diagonal step
+// its length, the square root of 2, so paths walk straight and only cut diagonally where it makes them shorter.
 func (t *PathTile) PathNeighborCost(to d2astar.Pather) float64 {
-	return 1 // No cost specifics currently...
-}
-
-// PathEstimatedCost is a heuristic method for estimating movement costs between non-adjacent nodes
-func (t *PathTile) PathEstimatedCost(to d2astar.Pather) float64 {
 	toT := to.(*PathTile)
-	absX := toT.X - t.X
 
-	if absX < 0 {
-		absX = -absX
+	if toT.X != t.X && toT.Y != t.Y {
+		return math.Sqrt2
 	}
 
-	absY := toT.Y - t.Y
+	return 1
+}
 
-	if absY < 0 {
-		absY = -absY
-	}
+// PathEstimatedCost is a heuristic method for estimating movement costs between non-adjacent nodes. It is the cost of
+// walking diagonally until level with the other node, then straight to it, which is never more than the actual cost.
+func (t *PathTile) PathEstimatedCost(to d2astar.Pather) float64 {
+	toT := to.(*PathTile)
 
-	r := absX + absY
+	// in sub tiles, which cost 1 to step across
+	absX := math.Abs(toT.X-t.X) * subTilesPerTile
+	absY := math.Abs(toT.Y-t.Y) * subTilesPerTile
 
-	return r
+	return math.Max(absX, absY) + (math.Sqrt2-1)*math.Min(absX, absY)
 }
@@ -0,0 +1,56 @@
+package d2common
+
+import "math"
+
+// Vector2 is a position or offset with float coordinates, passed by value. See d2vector.Vector for a vector with
+// more math, which is changed in place.
+type Vector2 struct {
+	X float64
+	Y float64
+}
+
+// Add returns the sum of the two vectors.
+func (v Vector2) Add(o Vector2) Vector2 {
+	return Vector2{v.X + o.X, v.Y + o.Y}
+}
+
+// Sub returns the difference of the two vectors.
+func (v Vector2) Sub(o Vector2) Vector2 {
+	return Vector2{v.X - o.X, v.Y - o.Y}
+}
+
+// Scale returns the vector multiplied by the scalar.
+func (v Vector2) Scale(s float64) Vector2 {
+	return Vector2{v.X * s, v.Y * s}
+}
+
+// Floor returns the vector rounded down to whole coordinates.
+func (v Vector2) Floor() Vector2Int {
+	return Vector2Int{int(math.Floor(v.X)), int(math.Floor(v.Y))}
+}
+
+// Vector2Int is a position or offset with whole coordinates, passed by value.
+type Vector2Int struct {
+	X int
+	Y int
+}
+
+// Add returns the sum of the two vectors.
+func (v Vector2Int) Add(o Vector2Int) Vector2Int {
+	return Vector2Int{v.X + o.X, v.Y + o.Y}
+}
+
+// Sub returns the difference of the two vectors.
+func (v Vector2Int) Sub(o Vector2Int) Vector2Int {
+	return Vector2Int{v.X - o.X, v.Y - o.Y}
+}
+
+// Scale returns the vector multiplied by the scalar.
+func (v Vector2Int) Scale(s int) Vector2Int {
+	return Vector2Int{v.X * s, v.Y * s}
+}
+
+// Vector2 returns the vector with float coordinates.
+func (v Vector2Int) Vector2() Vector2 {
+	return Vector2{float64(v.X), float64(v.Y)}
+}
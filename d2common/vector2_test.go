@@ -0,0 +1,45 @@
+package d2common
+
+import (
+	"testing"
+)
+
+func TestVector2(t *testing.T) {
+	a, b := Vector2{1.5, -2}, Vector2{0.25, 3}
+
+	if sum := a.Add(b); sum != (Vector2{1.75, 1}) {
+		t.Errorf("expected 1.75,1 got %v", sum)
+	}
+
+	if difference := a.Sub(b); difference != (Vector2{1.25, -5}) {
+		t.Errorf("expected 1.25,-5 got %v", difference)
+	}
+
+	if scaled := a.Scale(2); scaled != (Vector2{3, -4}) {
+		t.Errorf("expected 3,-4 got %v", scaled)
+	}
+
+	if floored := (Vector2{1.5, -0.5}).Floor(); floored != (Vector2Int{1, -1}) {
+		t.Errorf("expected 1,-1 got %v", floored)
+	}
+}
+
+func TestVector2Int(t *testing.T) {
+	a, b := Vector2Int{3, -2}, Vector2Int{1, 4}
+
+	if sum := a.Add(b); sum != (Vector2Int{4, 2}) {
+		t.Errorf("expected 4,2 got %v", sum)
+	}
+
+	if difference := a.Sub(b); difference != (Vector2Int{2, -6}) {
+		t.Errorf("expected 2,-6 got %v", difference)
+	}
+
+	if scaled := a.Scale(-2); scaled != (Vector2Int{-6, 4}) {
+		t.Errorf("expected -6,4 got %v", scaled)
+	}
+
+	if converted := a.Vector2(); converted != (Vector2{3, -2}) {
+		t.Errorf("expected 3,-2 got %v", converted)
+	}
+}
@@ -55,6 +55,21 @@ type animation struct {
 	hasSubLoop       bool // runs after first animation ends
 }
 
+// currentFrame returns the current frame, nil if the direction has no frames. Directions don't all have as many
+// frames, so the frame index is clamped to the frames of the current direction.
+func (a *animation) currentFrame() *animationFrame {
+	frames := a.directions[a.directionIndex].frames
+	if len(frames) == 0 {
+		return nil
+	}
+
+	if a.frameIndex >= len(frames) {
+		a.frameIndex = len(frames) - 1
+	}
+
+	return frames[a.frameIndex]
+}
+
 // SetSubLoop sets a sub loop for the animation
 func (a *animation) SetSubLoop(startFrame, endFrame int) {
 	a.subStartingFrame = startFrame
@@ -69,6 +84,10 @@ func (a *animation) Advance(elapsed float64) error {
 	}
 
 	frameCount := a.GetFrameCount()
+	if frameCount == 0 {
+		return nil
+	}
+
 	frameLength := a.playLength / float64(frameCount)
 	a.lastFrameTime += elapsed
 	framesAdvanced := int(a.lastFrameTime / frameLength)
@@ -79,8 +98,8 @@ func (a *animation) Advance(elapsed float64) error {
 		endIndex := frameCount
 
 		if a.hasSubLoop && a.playedCount > 0 {
-			startIndex = a.subStartingFrame
-			endIndex = a.subEndingFrame
+			startIndex = d2common.MinInt(a.subStartingFrame, frameCount-1)
+			endIndex = d2common.MinInt(a.subEndingFrame, frameCount)
 		}
 
 		switch a.playMode {
@@ -114,8 +133,10 @@ func (a *animation) Advance(elapsed float64) error {
 
 // Render renders the animation to the given surface
 func (a *animation) Render(target d2iface.Surface) error {
-	direction := a.directions[a.directionIndex]
-	frame := direction.frames[a.frameIndex]
+	frame := a.currentFrame()
+	if frame == nil {
+		return nil
+	}
 
 	target.PushTranslation(frame.offsetX, frame.offsetY)
 	defer target.Pop()
@@ -131,9 +152,7 @@ func (a *animation) Render(target d2iface.Surface) error {
 
 // RenderFromOrigin renders the animation from the animation origin
 func (a *animation) RenderFromOrigin(target d2iface.Surface) error {
-	if a.originAtBottom {
-		direction := a.directions[a.directionIndex]
-		frame := direction.frames[a.frameIndex]
+	if frame := a.currentFrame(); frame != nil && a.originAtBottom {
 		target.PushTranslation(0, -frame.height)
 
 		defer target.Pop()
@@ -142,10 +161,30 @@ func (a *animation) RenderFromOrigin(target d2iface.Surface) error {
 	return a.Render(target)
 }
 
+// RenderFromOriginBatch renders the current frame from the animation origin at each of the offsets
+func (a *animation) RenderFromOriginBatch(target d2iface.Surface, offsets []image.Point) error {
+	frame := a.currentFrame()
+	if frame == nil {
+		return nil
+	}
+
+	rect := a.GetCurrentFrameRect()
+
+	target.PushTranslation(rect.Min.X, rect.Min.Y)
+	target.PushEffect(a.effect)
+	target.PushColor(a.colorMod)
+
+	defer target.PopN(3)
+
+	return target.RenderBatch(frame.image, offsets)
+}
+
 // RenderSection renders the section of the animation frame enclosed by bounds
 func (a *animation) RenderSection(sfc d2iface.Surface, bound image.Rectangle) error {
-	direction := a.directions[a.directionIndex]
-	frame := direction.frames[a.frameIndex]
+	frame := a.currentFrame()
+	if frame == nil {
+		return nil
+	}
 
 	sfc.PushTranslation(frame.offsetX, frame.offsetY)
 	sfc.PushEffect(a.effect)
@@ -159,7 +198,7 @@ func (a *animation) RenderSection(sfc d2iface.Surface, bound image.Rectangle) er
 // GetFrameSize gets the Size(width, height) of a indexed frame.
 func (a *animation) GetFrameSize(frameIndex int) (width, height int, err error) {
 	direction := a.directions[a.directionIndex]
-	if frameIndex >= len(direction.frames) {
+	if frameIndex < 0 || frameIndex >= len(direction.frames) {
 		return 0, 0, errors.New("invalid frame index")
 	}
 
@@ -174,6 +213,22 @@ func (a *animation) GetCurrentFrameSize() (width, height int) {
 	return width, height
 }
 
+// GetCurrentFrameRect gets the area the current frame is drawn in by RenderFromOrigin, relative to the origin.
+func (a *animation) GetCurrentFrameRect() image.Rectangle {
+	frame := a.currentFrame()
+	if frame == nil {
+		return image.Rectangle{}
+	}
+
+	rect := image.Rect(0, 0, frame.width, frame.height).Add(image.Pt(frame.offsetX, frame.offsetY))
+
+	if a.originAtBottom {
+		rect = rect.Sub(image.Pt(0, frame.height))
+	}
+
+	return rect
+}
+
 // GetFrameBounds gets maximum Size(width, height) of all frame.
 func (a *animation) GetFrameBounds() (maxWidth, maxHeight int) {
 	maxWidth, maxHeight = 0, 0
@@ -231,12 +286,24 @@ func (a *animation) GetDirection() int {
 	return a.directionIndex
 }
 
-// SetCurrentFrame sets animation at a specific frame
+// SetCurrentFrame sets animation at a specific frame. Directions may have fewer frames than others, so an index past
+// the last frame of the current direction wraps around if the animation loops, and is clamped to the last frame if not.
 func (a *animation) SetCurrentFrame(frameIndex int) error {
-	if frameIndex >= a.GetFrameCount() {
+	if frameIndex < 0 {
 		return errors.New("invalid frame index")
 	}
 
+	frameCount := a.GetFrameCount()
+
+	switch {
+	case frameCount == 0:
+		frameIndex = 0
+	case frameIndex >= frameCount && a.playLoop:
+		frameIndex %= frameCount
+	case frameIndex >= frameCount:
+		frameIndex = frameCount - 1
+	}
+
 	a.frameIndex = frameIndex
 	a.lastFrameTime = 0
 
@@ -276,6 +343,15 @@ func (a *animation) SetPlaySpeed(playSpeed float64) {
 	a.SetPlayLength(playSpeed * float64(a.GetFrameCount()))
 }
 
+// SetPlayFPS sets the play speed of the animation in frames per second. Speeds of 0 or less are ignored.
+func (a *animation) SetPlayFPS(fps float64) {
+	if fps <= 0 {
+		return
+	}
+
+	a.SetPlaySpeed(1 / fps)
+}
+
 // SetPlayLength sets the Animation's play length in seconds
 func (a *animation) SetPlayLength(playLength float64) {
 	// TODO refactor to use time.Duration instead of float64
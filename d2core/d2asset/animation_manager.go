@@ -11,7 +11,7 @@ import (
 )
 
 const (
-	animationBudget = 64
+	animationBudget = 512 // room for the monsters and missiles preloaded for a level
 )
 
 // Static checks to confirm struct conforms to interface
@@ -0,0 +1,119 @@
+package d2asset
+
+import (
+	"testing"
+)
+
+// asymmetricAnimation returns an animation whose directions have 2, 5 and 0 frames.
+func asymmetricAnimation() *animation {
+	frameCounts := []int{2, 5, 0}
+	directions := make([]animationDirection, len(frameCounts))
+
+	for idx, count := range frameCounts {
+		directions[idx].decoded = true
+
+		for frame := 0; frame < count; frame++ {
+			directions[idx].frames = append(directions[idx].frames, &animationFrame{width: frame + 1, height: 1})
+		}
+	}
+
+	return &animation{directions: directions, playLength: defaultPlayLength}
+}
+
+func TestAnimationFrameCountPerDirection(t *testing.T) {
+	anim := asymmetricAnimation()
+	anim.directionIndex = 1
+
+	if err := anim.SetCurrentFrame(4); err != nil {
+		t.Fatal(err)
+	}
+
+	// switching to a direction with fewer frames must not index past its frames
+	anim.directionIndex = 0
+
+	if width, _ := anim.GetCurrentFrameSize(); width != 0 {
+		t.Errorf("frame 4 doesn't exist in direction 0, got a frame %d wide", width)
+	}
+
+	if rect := anim.GetCurrentFrameRect(); rect.Dx() != 2 {
+		t.Errorf("expected the current frame to be clamped to the last frame, got a rect %d wide", rect.Dx())
+	}
+
+	if anim.GetCurrentFrame() != 1 {
+		t.Errorf("expected frame 1, got %d", anim.GetCurrentFrame())
+	}
+
+	if err := anim.SetCurrentFrame(-1); err == nil {
+		t.Error("negative frame indexes are invalid")
+	}
+
+	if err := anim.SetCurrentFrame(4); err != nil || anim.GetCurrentFrame() != 1 {
+		t.Errorf("expected frame 4 to be clamped to frame 1, got %d", anim.GetCurrentFrame())
+	}
+
+	anim.SetPlayLoop(true)
+
+	if err := anim.SetCurrentFrame(5); err != nil || anim.GetCurrentFrame() != 1 {
+		t.Errorf("expected frame 5 to loop to frame 1, got %d", anim.GetCurrentFrame())
+	}
+}
+
+func TestAnimationAdvanceAsymmetricDirections(t *testing.T) {
+	anim := asymmetricAnimation()
+	anim.SetPlayLoop(true)
+	anim.SetSubLoop(1, 4)
+	anim.PlayForward()
+
+	for direction := range anim.directions {
+		anim.directionIndex = direction
+
+		for step := 0; step < 20; step++ {
+			if err := anim.Advance(defaultPlayLength / 3); err != nil {
+				t.Fatal(err)
+			}
+
+			count := anim.GetFrameCount()
+			if count > 0 && (anim.GetCurrentFrame() < 0 || anim.GetCurrentFrame() >= count) {
+				t.Fatalf("direction %d: frame %d out of range of %d frames", direction, anim.GetCurrentFrame(), count)
+			}
+
+			anim.GetCurrentFrameRect()
+		}
+	}
+
+	anim.directionIndex = 2
+
+	if err := anim.SetCurrentFrame(3); err != nil || anim.GetCurrentFrame() != 0 {
+		t.Error("a direction without frames stays on frame 0")
+	}
+
+	if err := anim.Render(nil); err != nil {
+		t.Error("a direction without frames renders nothing")
+	}
+}
+
+func TestAnimationSetPlayFPS(t *testing.T) {
+	anim := asymmetricAnimation()
+	anim.directionIndex = 1
+	anim.SetPlayFPS(10)
+
+	if anim.playLength != 0.5 {
+		t.Errorf("5 frames at 10 frames per second play in 0.5s, got %fs", anim.playLength)
+	}
+
+	anim.SetPlayFPS(0)
+
+	if anim.playLength != 0.5 {
+		t.Error("a speed of 0 frames per second should be ignored")
+	}
+
+	anim.PlayForward()
+
+	if err := anim.Advance(0.25); err != nil {
+		t.Fatal(err)
+	}
+
+	if anim.GetCurrentFrame() != 2 {
+		t.Errorf("expected frame 2 after 0.25s, got %d", anim.GetCurrentFrame())
+	}
+}
@@ -1,7 +1,6 @@
 package d2asset
 
 import (
-	"errors"
 	"path"
 	"sync"
 
@@ -48,7 +47,7 @@ func (am *archiveManager) LoadArchiveForFile(filePath string) (d2interface.Archi
 		}
 	}
 
-	return nil, errors.New("file not found")
+	return nil, d2common.WrapAssetError(filePath, d2common.ErrNotFound)
 }
 
 // FileExistsInArchive checks if a file exists in an archive
@@ -75,7 +74,12 @@ func (am *archiveManager) LoadArchive(archivePath string) (d2interface.Archive,
 		return archive.(d2interface.Archive), nil
 	}
 
-	archive, err := d2mpq.Load(archivePath)
+	load := d2mpq.Load
+	if am.config.MapMpqs {
+		load = d2mpq.LoadMapped
+	}
+
+	archive, err := load(archivePath)
 	if err != nil {
 		return nil, err
 	}
@@ -21,6 +21,7 @@ type fileManager struct {
 	cache          d2interface.Cache
 	archiveManager d2interface.ArchiveManager
 	config         *d2config.Configuration
+	overrides      *overrideSource
 }
 
 func createFileManager(config *d2config.Configuration,
@@ -29,6 +30,7 @@ func createFileManager(config *d2config.Configuration,
 		d2common.CreateCache(fileBudget),
 		archiveManager,
 		config,
+		createOverrideSource(config.OverridePath),
 	}
 }
 
@@ -36,12 +38,20 @@ func createFileManager(config *d2config.Configuration,
 func (fm *fileManager) LoadFileStream(filePath string) (d2interface.ArchiveDataStream, error) {
 	filePath = fm.fixupFilePath(filePath)
 
+	if fm.overrides != nil {
+		if stream, found, err := fm.overrides.openFile(filePath); found || err != nil {
+			return stream, d2common.WrapAssetError(filePath, err)
+		}
+	}
+
 	archive, err := fm.archiveManager.LoadArchiveForFile(filePath)
 	if err != nil {
-		return nil, err
+		return nil, d2common.WrapAssetError(filePath, err)
 	}
 
-	return archive.ReadFileStream(filePath)
+	stream, err := archive.ReadFileStream(filePath)
+
+	return stream, d2common.WrapAssetError(filePath, err)
 }
 
 // LoadFile loads a file automatically from a managed archive
@@ -51,12 +61,7 @@ func (fm *fileManager) LoadFile(filePath string) ([]byte, error) {
 		return value.([]byte), nil
 	}
 
-	archive, err := fm.archiveManager.LoadArchiveForFile(filePath)
-	if err != nil {
-		return nil, err
-	}
-
-	data, err := archive.ReadFile(filePath)
+	data, err := fm.readFile(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -68,9 +73,34 @@ func (fm *fileManager) LoadFile(filePath string) ([]byte, error) {
 	return data, nil
 }
 
-// FileExists checks if a file exists in an archive
+// readFile reads a file from the override directory, or else from the archive containing it
+func (fm *fileManager) readFile(filePath string) ([]byte, error) {
+	if fm.overrides != nil {
+		if data, found, err := fm.overrides.readFile(filePath); found || err != nil {
+			return data, d2common.WrapAssetError(filePath, err)
+		}
+	}
+
+	archive, err := fm.archiveManager.LoadArchiveForFile(filePath)
+	if err != nil {
+		return nil, d2common.WrapAssetError(filePath, err)
+	}
+
+	data, err := archive.ReadFile(filePath)
+
+	return data, d2common.WrapAssetError(filePath, err)
+}
+
+// FileExists checks if a file exists in the override directory or in an archive
 func (fm *fileManager) FileExists(filePath string) (bool, error) {
 	filePath = fm.fixupFilePath(filePath)
+
+	if fm.overrides != nil {
+		if _, found, err := fm.overrides.resolve(filePath); found || err != nil {
+			return found, err
+		}
+	}
+
 	return fm.archiveManager.FileExistsInArchive(filePath)
 }
 
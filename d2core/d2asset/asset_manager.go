@@ -1,6 +1,7 @@
 package d2asset
 
 import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2cof"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dcc"
@@ -14,6 +15,7 @@ type assetManager struct {
 	paletteTransformManager *paletteTransformManager
 	animationManager        d2interface.ArchivedAnimationManager
 	fontManager             d2interface.ArchivedFontManager
+	paletteEffects          *paletteEffects
 }
 
 func loadDC6(dc6Path string) (*d2dc6.DC6, error) {
@@ -24,7 +26,7 @@ func loadDC6(dc6Path string) (*d2dc6.DC6, error) {
 
 	dc6, err := d2dc6.Load(dc6Data)
 	if err != nil {
-		return nil, err
+		return nil, d2common.WrapAssetError(dc6Path, err)
 	}
 
 	return dc6, nil
@@ -36,7 +38,12 @@ func loadDCC(dccPath string) (*d2dcc.DCC, error) {
 		return nil, err
 	}
 
-	return d2dcc.Load(dccData)
+	dcc, err := d2dcc.Load(dccData)
+	if err != nil {
+		return nil, d2common.WrapAssetError(dccPath, err)
+	}
+
+	return dcc, nil
 }
 
 func loadCOF(cofPath string) (*d2cof.COF, error) {
@@ -45,7 +52,21 @@ func loadCOF(cofPath string) (*d2cof.COF, error) {
 		return nil, err
 	}
 
-	return d2cof.Load(cofData)
+	cof, err := d2cof.Load(cofData)
+	if err != nil {
+		return nil, d2common.WrapAssetError(cofPath, err)
+	}
+
+	return cof, nil
+}
+
+// clearDecodedCaches clears the caches of the assets decoded from files, so they are decoded again from the files
+// which changed.
+func (am *assetManager) clearDecodedCaches() {
+	am.paletteManager.ClearCache()
+	am.paletteTransformManager.cache.Clear()
+	am.animationManager.ClearCache()
+	am.fontManager.ClearCache()
 }
 
 func (am *assetManager) BindTerminalCommands(term d2interface.Terminal) error {
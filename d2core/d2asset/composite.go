@@ -3,8 +3,11 @@ package d2asset
 import (
 	"errors"
 	"fmt"
+	"image"
+	"image/color"
 	"strings"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2cof"
@@ -20,8 +23,13 @@ type Composite struct {
 	direction   int
 	equipment   [d2enum.CompositeTypeMax]string
 	mode        *compositeMode
+	colorMod    color.Color
 }
 
+// outlineOffsets are the offsets at which the silhouette is drawn to outline a Composite
+//nolint:gochecknoglobals // constant lookup table
+var outlineOffsets = [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+
 // CreateComposite creates a Composite from a given ObjectLookupRecord and palettePath.
 func CreateComposite(baseType d2enum.ObjectType, token, palettePath string) *Composite {
 	return &Composite{baseType: baseType, basePath: baseString(baseType),
@@ -71,6 +79,96 @@ func (c *Composite) Render(target d2interface.Surface) error {
 	return nil
 }
 
+// RenderBatch draws the Composite's current frame at each of the offsets, in one draw call per layer where the
+// renderer allows it. Composites with the same frame, as reported by SameFrame, can be drawn in one batch.
+func (c *Composite) RenderBatch(target d2interface.Surface, offsets []image.Point) error {
+	if c.mode == nil {
+		return nil
+	}
+
+	direction := d2cof.Dir64ToCof(c.direction, c.mode.cof.NumberOfDirections)
+	for _, layerIndex := range c.mode.cof.Priority[direction][c.mode.frameIndex] {
+		layer := c.mode.layers[layerIndex]
+		if layer != nil {
+			if err := layer.RenderFromOriginBatch(target, offsets); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SameFrame returns true if both Composites draw the same images, from the same files, in the same direction and
+// frame, and neither of them is tinted.
+func (c *Composite) SameFrame(other *Composite) bool {
+	if c.mode == nil || other.mode == nil || c.colorMod != nil || other.colorMod != nil {
+		return false
+	}
+
+	if c.basePath != other.basePath || c.token != other.token || c.palettePath != other.palettePath ||
+		c.equipment != other.equipment || c.direction != other.direction {
+		return false
+	}
+
+	if c.mode.animationMode.String() != other.mode.animationMode.String() ||
+		c.mode.weaponClass != other.mode.weaponClass || c.mode.frameIndex != other.mode.frameIndex {
+		return false
+	}
+
+	for layerIndex, layer := range c.mode.layers {
+		otherLayer := other.mode.layers[layerIndex]
+
+		switch {
+		case layer == nil && otherLayer == nil:
+			continue
+		case layer == nil || otherLayer == nil:
+			return false
+		case layer.GetDirection() != otherLayer.GetDirection() ||
+			layer.GetCurrentFrame() != otherLayer.GetCurrentFrame():
+			return false
+		}
+	}
+
+	return true
+}
+
+// Bounds returns the area the Composite's current frame is drawn in, relative to its origin.
+func (c *Composite) Bounds() image.Rectangle {
+	var bounds image.Rectangle
+
+	if c.mode == nil {
+		return bounds
+	}
+
+	for _, layer := range c.mode.layers {
+		if layer != nil {
+			bounds = bounds.Union(layer.GetCurrentFrameRect())
+		}
+	}
+
+	return bounds
+}
+
+// RenderOutline draws a one pixel outline of the given color around the Composite's current frame, derived from the
+// alpha edges of its layers. Render the Composite afterwards to fill in the outline.
+func (c *Composite) RenderOutline(target d2interface.Surface, outlineColor color.Color) error {
+	target.PushSilhouette(outlineColor)
+	defer target.Pop()
+
+	for _, offset := range outlineOffsets {
+		target.PushTranslation(offset[0], offset[1])
+		err := c.Render(target)
+		target.Pop()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ObjectAnimationMode returns the object animation mode
 func (c *Composite) ObjectAnimationMode() d2enum.ObjectAnimationMode {
 	return c.mode.animationMode.(d2enum.ObjectAnimationMode)
@@ -121,9 +219,40 @@ func (c *Composite) Equip(equipment *[d2enum.CompositeTypeMax]string) error {
 	return nil
 }
 
+// SetPalette reloads the layers of the current animation mode with the given palette
+func (c *Composite) SetPalette(palettePath string) error {
+	if c.palettePath == palettePath {
+		return nil
+	}
+
+	c.palettePath = palettePath
+	if c.mode == nil {
+		return nil
+	}
+
+	mode, err := c.createMode(c.mode.animationMode, c.mode.weaponClass)
+	if err != nil {
+		return err
+	}
+
+	c.mode = mode
+
+	return nil
+}
+
 // SetAnimSpeed sets the speed at which the Composite's animation should advance through its frames
 func (c *Composite) SetAnimSpeed(speed int) {
-	c.mode.animationSpeed = 1.0 / ((float64(speed) * 25.0) / 256.0)
+	c.SetPlayFPS((float64(speed) * 25.0) / 256.0)
+}
+
+// SetPlayFPS sets the speed of the Composite's animation in frames per second, to sync it with gameplay such as the
+// attack rate. Speeds of 0 or less are ignored.
+func (c *Composite) SetPlayFPS(fps float64) {
+	if c.mode == nil || fps <= 0 {
+		return
+	}
+
+	c.mode.animationSpeed = 1.0 / fps
 	for layerIdx := range c.mode.layers {
 		layer := c.mode.layers[layerIdx]
 		if layer != nil {
@@ -143,11 +272,57 @@ func (c *Composite) SetDirection(direction int) {
 	}
 }
 
+// SetColorMod sets the color modulation of every layer. The color mod is kept when the animation mode or equipment
+// changes, pass nil to remove it.
+func (c *Composite) SetColorMod(colorMod color.Color) {
+	c.colorMod = colorMod
+
+	if c.mode == nil {
+		return
+	}
+
+	for layerIdx := range c.mode.layers {
+		layer := c.mode.layers[layerIdx]
+		if layer != nil {
+			layer.SetColorMod(colorMod)
+		}
+	}
+}
+
 // GetDirection returns the current direction the composite is facing
 func (c *Composite) GetDirection() int {
 	return c.direction
 }
 
+// GetFrameCount returns the number of frames per direction of the current animation mode
+func (c *Composite) GetFrameCount() int {
+	if c.mode == nil {
+		return 0
+	}
+
+	return c.mode.frameCount
+}
+
+// GetActionFrame returns the frame the current animation mode triggers its action on, such as an attack hitting or a
+// missile being fired
+func (c *Composite) GetActionFrame() int {
+	if c.mode == nil {
+		return 0
+	}
+
+	return c.mode.actionFrame
+}
+
+// GetBaseAnimationSpeed returns the speed of the current animation mode from the animation data, in 256ths of 25
+// frames per second
+func (c *Composite) GetBaseAnimationSpeed() int {
+	if c.mode == nil {
+		return 0
+	}
+
+	return c.mode.baseSpeed
+}
+
 // GetPlayedCount returns the number of times the current animation mode has completed all its distinct frames
 func (c *Composite) GetPlayedCount() int {
 	if c.mode == nil {
@@ -177,8 +352,25 @@ func (c *Composite) SetSubLoop(startFrame, endFrame int) {
 	}
 }
 
-// SetCurrentFrame sets the current frame index of the animation
+// GetCurrentFrame returns the current frame index of the animation
+func (c *Composite) GetCurrentFrame() int {
+	if c.mode == nil {
+		return 0
+	}
+
+	return c.mode.frameIndex
+}
+
+// SetCurrentFrame sets the current frame index of the animation, clamped to the frames of the animation mode
 func (c *Composite) SetCurrentFrame(frame int) {
+	if c.mode == nil {
+		return
+	}
+
+	c.mode.frameIndex = d2common.MaxInt(0, d2common.MinInt(frame, c.mode.frameCount-1))
+	c.mode.lastFrameTime = 0
+
+	frame = c.mode.frameIndex
 	for layerIdx := range c.mode.layers {
 		layer := c.mode.layers[layerIdx]
 		if layer != nil {
@@ -207,6 +399,8 @@ type compositeMode struct {
 
 	frameCount     int
 	frameIndex     int
+	actionFrame    int
+	baseSpeed      int
 	animationSpeed float64
 	lastFrameTime  float64
 }
@@ -235,6 +429,8 @@ func (c *Composite) createMode(animationMode animationMode, weaponClass string)
 		weaponClass:    weaponClass,
 		layers:         make([]d2interface.Animation, d2enum.CompositeTypeMax),
 		frameCount:     animationData[0].FramesPerDirection,
+		actionFrame:    actionFrame(animationData[0]),
+		baseSpeed:      animationData[0].AnimationSpeed,
 		animationSpeed: 1.0 / ((float64(animationData[0].AnimationSpeed) * 25.0) / 256.0),
 	}
 
@@ -254,6 +450,7 @@ func (c *Composite) createMode(animationMode animationMode, weaponClass string)
 			cofLayer.WeaponClass.String(), c.palettePath, drawEffect)
 		if err == nil {
 			layer.SetPlaySpeed(mode.animationSpeed)
+			layer.SetColorMod(c.colorMod)
 			layer.PlayForward()
 
 			if err := layer.SetDirection(c.direction); err != nil {
@@ -267,6 +464,18 @@ func (c *Composite) createMode(animationMode animationMode, weaponClass string)
 	return mode, nil
 }
 
+// actionFrame returns the frame the animation triggers its action on, such as an attack hitting or a missile being
+// fired, or its last frame if it has no trigger.
+func actionFrame(record *d2data.AnimationDataRecord) int {
+	for idx := 0; idx < record.FramesPerDirection && idx < len(record.Flags); idx++ {
+		if record.Flags[idx] != 0 {
+			return idx
+		}
+	}
+
+	return d2common.MaxInt(0, record.FramesPerDirection-1)
+}
+
 func (c *Composite) loadCompositeLayer(layerKey, layerValue, animationMode, weaponClass,
 	palettePath string, drawEffect d2enum.DrawEffect) (d2interface.Animation, error) {
 	animationPaths := []string{
@@ -1,10 +1,12 @@
 package d2asset
 
 import (
+	"errors"
 	"log"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
 )
 
@@ -29,6 +31,7 @@ func Initialize(renderer d2interface.Renderer,
 		paletteTransformManager,
 		animationManager,
 		fontManager,
+		&paletteEffects{},
 	}
 
 	if term != nil {
@@ -38,6 +41,20 @@ func Initialize(renderer d2interface.Renderer,
 	return nil
 }
 
+// WatchOverrides reloads the loose files of the override directory when they change, so modders see their changes
+// without restarting the game. Assets loaded after a change are decoded from the new files, and a changed file which
+// fails to decode keeps its previous version. Meant for development only.
+func WatchOverrides() error {
+	fm, ok := singleton.archivedFileManager.(*fileManager)
+	if !ok || fm.overrides == nil {
+		return errors.New("there is no override directory to watch")
+	}
+
+	go createHotReloader(fm, singleton.clearDecodedCaches).run()
+
+	return nil
+}
+
 // LoadFileStream streams an MPQ file from a source file path
 func LoadFileStream(filePath string) (d2interface.ArchiveDataStream, error) {
 	data, err := singleton.archivedFileManager.LoadFileStream(filePath)
@@ -88,3 +105,27 @@ func LoadFont(tablePath, spritePath, palettePath string) (d2interface.Font, erro
 func LoadPalette(palettePath string) (d2interface.Palette, error) {
 	return singleton.paletteManager.LoadPalette(palettePath)
 }
+
+// PushPaletteEffect transforms the colors of the whole screen, as when blinded or flashing on a hit, for the given
+// number of seconds, or until the returned function pops it if the duration is 0. Effects pushed later apply on top
+// of the earlier ones.
+func PushPaletteEffect(transform d2math.ColorMatrix, duration float64) (pop func()) {
+	return singleton.paletteEffects.push(transform, duration)
+}
+
+// AdvancePaletteEffects counts down the timed palette effects, restoring the palette as they end.
+func AdvancePaletteEffects(tickTime float64) {
+	singleton.paletteEffects.advance(tickTime)
+}
+
+// PaletteEffect returns the combined transform of the palette effects, toned down by the accessibility settings of
+// the screen flashes, and false if there are none or the screen flashes are turned off.
+func PaletteEffect() (d2math.ColorMatrix, bool) {
+	return singleton.paletteEffects.transform(d2config.Config.Flashing.ScreenFlashIntensity())
+}
+
+// ApplyPaletteEffects returns the colors the palette shows with the palette effects applied, as PaletteEffect draws
+// them.
+func ApplyPaletteEffects(palette d2interface.Palette) d2interface.Palette {
+	return singleton.paletteEffects.apply(palette, d2config.Config.Flashing.ScreenFlashIntensity())
+}
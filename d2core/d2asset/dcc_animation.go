@@ -85,6 +85,7 @@ func (a *DCCAnimation) decodeDirection(directionIndex int) error {
 	}
 
 	direction := dcc.DecodeDirection(directionIndex)
+	a.directions[directionIndex].decoded = true
 
 	minX, minY := math.MaxInt32, math.MaxInt32
 	maxX, maxY := math.MinInt32, math.MinInt32
@@ -111,7 +112,6 @@ func (a *DCCAnimation) decodeDirection(directionIndex int) error {
 			return err
 		}
 
-		a.directions[directionIndex].decoded = true
 		a.directions[directionIndex].frames = append(a.directions[directionIndex].frames, &animationFrame{
 			width:   dccFrame.Width,
 			height:  dccFrame.Height,
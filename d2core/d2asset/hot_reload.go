@@ -0,0 +1,154 @@
+package d2asset
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2cof"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dat"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dc6"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dcc"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2pl2"
+)
+
+// hotReloadInterval is how often the override directory is checked for changed files.
+const hotReloadInterval = time.Second
+
+// hotReloadDecoders check the loose files of each format decode before they replace the previous version.
+//nolint:gochecknoglobals // constant lookup table
+var hotReloadDecoders = map[string]func([]byte) error{
+	".cof": func(data []byte) error { _, err := d2cof.Load(data); return err },
+	".dat": func(data []byte) error { _, err := d2dat.Load(data); return err },
+	".dc6": func(data []byte) error { _, err := d2dc6.Load(data); return err },
+	".dcc": func(data []byte) error { _, err := d2dcc.Load(data); return err },
+	".ds1": func(data []byte) error { _, err := d2ds1.LoadDS1(data); return err },
+	".dt1": func(data []byte) error { _, err := d2dt1.LoadDT1(data); return err },
+	".pl2": func(data []byte) error { _, err := d2pl2.Load(data); return err },
+}
+
+// hotReloader watches the loose files of the override directory, and evicts those which changed from the caches so
+// they are loaded again. A changed file which fails to decode is ignored, keeping the previous version.
+type hotReloader struct {
+	fileManager *fileManager
+	clearCaches func() // clears the caches of the assets decoded from files
+	modTimes    map[string]time.Time
+}
+
+func createHotReloader(fm *fileManager, clearCaches func()) *hotReloader {
+	reloader := &hotReloader{fileManager: fm, clearCaches: clearCaches, modTimes: make(map[string]time.Time)}
+	reloader.changedFiles()
+
+	return reloader
+}
+
+// run checks the override directory for changed files at every interval, forever.
+func (h *hotReloader) run() {
+	for range time.Tick(hotReloadInterval) {
+		h.reloadChanged()
+	}
+}
+
+// reloadChanged evicts the files which changed since the last check from the caches.
+func (h *hotReloader) reloadChanged() {
+	changed := h.changedFiles()
+	reloaded := false
+
+	if len(changed) > 0 {
+		h.fileManager.overrides.invalidate()
+	}
+
+	for _, filePath := range changed {
+		if err := h.reload(filePath); err != nil {
+			log.Printf("hot reload: keeping the previous version of %s: %v", filePath, err)
+			continue
+		}
+
+		log.Printf("hot reload: reloaded %s", filePath)
+
+		reloaded = true
+	}
+
+	if reloaded && h.clearCaches != nil {
+		h.clearCaches()
+	}
+}
+
+// reload checks the changed file decodes, then evicts it from the file cache.
+func (h *hotReloader) reload(filePath string) error {
+	data, found, err := h.fileManager.overrides.readFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		if err := decodeLooseFile(filePath, data); err != nil {
+			return err
+		}
+	}
+
+	h.fileManager.cache.Remove(filePath)
+
+	return nil
+}
+
+// decodeLooseFile returns an error if the file doesn't decode, for the formats it can be checked for.
+func decodeLooseFile(filePath string, data []byte) (err error) {
+	decode, ok := hotReloadDecoders[strings.ToLower(filepath.Ext(filePath))]
+	if !ok {
+		return nil
+	}
+
+	// the decoders don't all check their input, and may panic on a broken file
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = d2common.WrapAssetError(filePath, fmt.Errorf("%w: failed to decode: %v", d2common.ErrCorrupt, recovered))
+		}
+	}()
+
+	return d2common.WrapAssetError(filePath, decode(data))
+}
+
+// changedFiles returns the in-archive paths of the loose files which were added, modified or deleted since the last
+// call.
+func (h *hotReloader) changedFiles() []string {
+	root := h.fileManager.overrides.root
+	seen := make(map[string]bool)
+	changed := make([]string, 0)
+
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+
+		filePath := h.fileManager.fixupFilePath(filepath.ToSlash(relative))
+		seen[filePath] = true
+
+		if modTime, ok := h.modTimes[filePath]; !ok || !modTime.Equal(info.ModTime()) {
+			h.modTimes[filePath] = info.ModTime()
+			changed = append(changed, filePath)
+		}
+
+		return nil
+	})
+
+	for filePath := range h.modTimes {
+		if !seen[filePath] {
+			delete(h.modTimes, filePath)
+			changed = append(changed, filePath)
+		}
+	}
+
+	return changed
+}
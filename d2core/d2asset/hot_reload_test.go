@@ -0,0 +1,73 @@
+package d2asset
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+)
+
+func TestHotReloadEvictsChangedFiles(t *testing.T) {
+	root, err := ioutil.TempDir("", "overrides")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	write := func(name, content string, modTime time.Time) {
+		path := filepath.Join(root, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now().Add(-time.Hour)
+	write("Notes.txt", "old", start)
+	write("sprite.dc6", "old", start)
+
+	fm := &fileManager{
+		cache:     d2common.CreateCache(fileBudget),
+		config:    &d2config.Configuration{},
+		overrides: createOverrideSource(root),
+	}
+
+	cleared := 0
+	reloader := createHotReloader(fm, func() { cleared++ })
+
+	for _, key := range []string{"notes.txt", "sprite.dc6"} {
+		if err := fm.cache.Insert(key, []byte("old"), 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reloader.reloadChanged()
+
+	if cleared != 0 {
+		t.Error("nothing changed yet")
+	}
+
+	write("Notes.txt", "new", start.Add(time.Minute))
+	write("sprite.dc6", "not a dc6", start.Add(time.Minute))
+	reloader.reloadChanged()
+
+	if _, found := fm.cache.Retrieve("notes.txt"); found {
+		t.Error("a changed file should be evicted from the cache")
+	}
+
+	if _, found := fm.cache.Retrieve("sprite.dc6"); !found {
+		t.Error("a changed file which fails to decode should keep its previous version")
+	}
+
+	if cleared != 1 {
+		t.Errorf("expected the decoded assets to be cleared once, got %d", cleared)
+	}
+}
@@ -0,0 +1,159 @@
+package d2asset
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// errOutsideOverrideRoot is returned for file paths which would resolve outside of the override directory.
+var errOutsideOverrideRoot = errors.New("file path is outside of the override directory")
+
+// overrideSource resolves in-archive file paths to loose files in a directory, which take priority over the MPQs. This
+// lets modders change single files without repacking the archives. File names are matched ignoring case, like in the
+// archives. The listings of the directories are cached, so resolving a path doesn't read every directory on the way.
+type overrideSource struct {
+	root     string
+	realRoot string // the root with its symbolic links evaluated
+
+	mutex    sync.Mutex
+	listings map[string][]os.FileInfo
+}
+
+// createOverrideSource returns the override source for the directory, nil if the directory is empty.
+func createOverrideSource(root string) *overrideSource {
+	if root == "" {
+		return nil
+	}
+
+	root = filepath.Clean(root)
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		realRoot = root
+	}
+
+	return &overrideSource{root: root, realRoot: realRoot, listings: make(map[string][]os.FileInfo)}
+}
+
+// invalidate forgets the cached directory listings, once files were added to or removed from the override directory.
+func (o *overrideSource) invalidate() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.listings = make(map[string][]os.FileInfo)
+}
+
+// readDir returns the entries of the directory, nil if it can't be read.
+func (o *overrideSource) readDir(dir string) []os.FileInfo {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	entries, found := o.listings[dir]
+	if !found {
+		entries, _ = ioutil.ReadDir(dir)
+		o.listings[dir] = entries
+	}
+
+	return entries
+}
+
+// resolve returns the path of the loose file overriding the in-archive file path, false if there is none. Paths
+// escaping the override directory, including through symbolic links, are rejected.
+func (o *overrideSource) resolve(filePath string) (string, bool, error) {
+	resolved := o.root
+	linked := false
+
+	var info os.FileInfo
+
+	for _, element := range strings.FieldsFunc(filePath, isPathSeparator) {
+		if element == "." || element == ".." || filepath.IsAbs(element) || strings.ContainsRune(element, ':') {
+			return "", false, errOutsideOverrideRoot
+		}
+
+		info = nil
+
+		for _, entry := range o.readDir(resolved) {
+			if strings.EqualFold(entry.Name(), element) {
+				info = entry
+				break
+			}
+		}
+
+		if info == nil {
+			return "", false, nil
+		}
+
+		resolved = filepath.Join(resolved, info.Name())
+		linked = linked || info.Mode()&os.ModeSymlink != 0
+	}
+
+	if linked {
+		return o.resolveLink(resolved)
+	}
+
+	if info == nil || info.IsDir() {
+		return "", false, nil
+	}
+
+	return resolved, true, nil
+}
+
+// resolveLink returns the loose file at a path going through symbolic links, which must stay in the override
+// directory.
+func (o *overrideSource) resolveLink(resolved string) (string, bool, error) {
+	target, err := filepath.EvalSymlinks(resolved)
+	if err != nil {
+		return "", false, nil
+	}
+
+	if relative, err := filepath.Rel(o.realRoot, target); err != nil || relative == ".." ||
+		strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", false, errOutsideOverrideRoot
+	}
+
+	if info, err := os.Stat(target); err != nil || info.IsDir() {
+		return "", false, nil
+	}
+
+	return resolved, true, nil
+}
+
+// readFile returns the contents of the loose file overriding the in-archive file path, false if there is none.
+func (o *overrideSource) readFile(filePath string) ([]byte, bool, error) {
+	resolved, found, err := o.resolve(filePath)
+	if !found {
+		return nil, false, err
+	}
+
+	data, err := ioutil.ReadFile(filepath.Clean(resolved))
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+// openFile opens a stream of the loose file overriding the in-archive file path, false if there is none.
+func (o *overrideSource) openFile(filePath string) (d2interface.ArchiveDataStream, bool, error) {
+	resolved, found, err := o.resolve(filePath)
+	if !found {
+		return nil, false, err
+	}
+
+	file, err := os.Open(filepath.Clean(resolved))
+	if err != nil {
+		return nil, false, err
+	}
+
+	return file, true, nil
+}
+
+func isPathSeparator(r rune) bool {
+	return r == '\\' || r == '/'
+}
@@ -0,0 +1,124 @@
+package d2asset
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOverrideSource(t *testing.T) {
+	root, err := ioutil.TempDir("", "overrides")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	if err := os.MkdirAll(filepath.Join(root, "Data", "Global"), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(root, "Data", "Global", "Excel.txt"), []byte("mod"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides := createOverrideSource(root)
+
+	data, found, err := overrides.readFile(`data\global\excel.txt`)
+	if err != nil || !found || string(data) != "mod" {
+		t.Errorf("expected the loose file to be found ignoring case, got %q, %v, %v", data, found, err)
+	}
+
+	if _, found, err := overrides.readFile(`data\global\missing.txt`); found || err != nil {
+		t.Error("files missing from the override directory should fall back to the archives")
+	}
+
+	if _, found, _ := overrides.resolve(`data\global`); found {
+		t.Error("directories don't override files")
+	}
+
+	for _, filePath := range []string{`data\..\..\secret.txt`, `..\secret.txt`, `data/./global/excel.txt`, `c:\secret.txt`} {
+		if _, found, err := overrides.readFile(filePath); found || err != errOutsideOverrideRoot {
+			t.Errorf("%s should be rejected, got %v", filePath, err)
+		}
+	}
+
+	if createOverrideSource("") != nil {
+		t.Error("there are no overrides without an override directory")
+	}
+}
+
+func TestOverrideSourceCachesListings(t *testing.T) {
+	root, err := ioutil.TempDir("", "overrides")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	overrides := createOverrideSource(root)
+
+	if _, found, _ := overrides.resolve("new.txt"); found {
+		t.Fatal("the file doesn't exist yet")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(root, "New.txt"), []byte("mod"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found, _ := overrides.resolve("new.txt"); found {
+		t.Error("the listing of the override directory should be cached")
+	}
+
+	overrides.invalidate()
+
+	if _, found, _ := overrides.resolve("new.txt"); !found {
+		t.Error("added files should be found once the listings are invalidated")
+	}
+}
+
+func TestOverrideSourceSymlinks(t *testing.T) {
+	outside, err := ioutil.TempDir("", "outside")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(outside)
+
+	root := filepath.Join(outside, "overrides")
+
+	for _, dir := range []string{filepath.Join(root, "data"), filepath.Join(outside, "secrets")} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, path := range []string{filepath.Join(root, "data", "excel.txt"), filepath.Join(outside, "secrets", "key.txt")} {
+		if err := ioutil.WriteFile(path, []byte("mod"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Symlink(filepath.Join(outside, "secrets"), filepath.Join(root, "secrets")); err != nil {
+		t.Skip("symbolic links aren't supported:", err)
+	}
+
+	if err := os.Symlink(filepath.Join(root, "data"), filepath.Join(root, "global")); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides := createOverrideSource(root)
+
+	if _, found, err := overrides.readFile(`secrets\key.txt`); found || err != errOutsideOverrideRoot {
+		t.Errorf("links out of the override directory should be rejected, got %v", err)
+	}
+
+	if data, found, err := overrides.readFile(`global\excel.txt`); !found || err != nil || string(data) != "mod" {
+		t.Errorf("links in the override directory should be followed, got %q, %v, %v", data, found, err)
+	}
+
+	if _, found, _ := overrides.resolve(`global`); found {
+		t.Error("linked directories don't override files")
+	}
+}
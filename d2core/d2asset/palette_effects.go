@@ -0,0 +1,130 @@
+package d2asset
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dat"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math"
+)
+
+// paletteEffect is a full screen transform of the palette, for a number of seconds or until popped.
+type paletteEffect struct {
+	id        int
+	transform d2math.ColorMatrix
+	remaining float64 // seconds until the effect ends, 0 for an effect lasting until popped
+}
+
+// paletteEffects is the stack of the full screen palette transforms in effect, applied from the bottom up.
+type paletteEffects struct {
+	effects []paletteEffect
+	nextID  int
+}
+
+// push adds the transform to the top of the stack, and returns a function removing it.
+func (p *paletteEffects) push(transform d2math.ColorMatrix, duration float64) (pop func()) {
+	p.nextID++
+	id := p.nextID
+	p.effects = append(p.effects, paletteEffect{id: id, transform: transform, remaining: duration})
+
+	return func() {
+		p.remove(id)
+	}
+}
+
+// remove takes the effect off the stack, if it is still on it.
+func (p *paletteEffects) remove(id int) {
+	for idx := range p.effects {
+		if p.effects[idx].id == id {
+			p.effects = append(p.effects[:idx], p.effects[idx+1:]...)
+			return
+		}
+	}
+}
+
+// advance counts down the timed effects, removing the ones which ended.
+func (p *paletteEffects) advance(tickTime float64) {
+	kept := p.effects[:0]
+
+	for _, effect := range p.effects {
+		if effect.remaining > 0 {
+			effect.remaining -= tickTime
+
+			if effect.remaining <= 0 {
+				continue
+			}
+		}
+
+		kept = append(kept, effect)
+	}
+
+	p.effects = kept
+}
+
+// transform returns the combined transform of the effects weakened to the intensity, from 0 for none to 1 for full
+// strength, and false if there are no effects or the intensity is 0.
+func (p *paletteEffects) transform(intensity float64) (d2math.ColorMatrix, bool) {
+	if len(p.effects) == 0 || intensity <= 0 {
+		return d2math.IdentityColorMatrix(), false
+	}
+
+	transform := p.effects[0].transform
+	for _, effect := range p.effects[1:] {
+		transform = transform.Concat(effect.transform)
+	}
+
+	if intensity < 1 {
+		transform = d2math.IdentityColorMatrix().Lerp(transform, intensity)
+	}
+
+	return transform, true
+}
+
+// apply returns a copy of the palette with the combined transform of the effects, weakened to the intensity, applied
+// to its colors, or the palette itself if there are no effects.
+func (p *paletteEffects) apply(palette d2interface.Palette, intensity float64) d2interface.Palette {
+	transform, ok := p.transform(intensity)
+	if !ok {
+		return palette
+	}
+
+	transformed := &transformedPalette{}
+
+	for idx, c := range palette.GetColors() {
+		if c == nil {
+			continue
+		}
+
+		rgba := transform.Apply(color.NRGBA{R: c.R(), G: c.G(), B: c.B(), A: c.A()})
+		transformedColor := &d2dat.DATColor{}
+		transformedColor.SetRGBA(uint32(rgba.R)<<24 | uint32(rgba.G)<<16 | uint32(rgba.B)<<8 | uint32(rgba.A))
+		transformed.colors[idx] = transformedColor
+	}
+
+	return transformed
+}
+
+// transformedPalette is a palette whose colors went through the palette effects.
+type transformedPalette struct {
+	colors [256]d2interface.Color
+}
+
+// NumColors returns the number of colors in the palette.
+func (p *transformedPalette) NumColors() int {
+	return len(p.colors)
+}
+
+// GetColors returns the colors of the palette.
+func (p *transformedPalette) GetColors() [256]d2interface.Color {
+	return p.colors
+}
+
+// GetColor returns the color at the index of the palette.
+func (p *transformedPalette) GetColor(idx int) (d2interface.Color, error) {
+	if p.colors[idx] == nil {
+		return nil, fmt.Errorf("cannot find color index %d in palette", idx)
+	}
+
+	return p.colors[idx], nil
+}
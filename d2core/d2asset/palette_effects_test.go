@@ -0,0 +1,51 @@
+package d2asset
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math"
+)
+
+func TestPaletteEffects(t *testing.T) {
+	effects := &paletteEffects{}
+
+	if _, ok := effects.transform(1); ok {
+		t.Fatal("wanted no transform without effects")
+	}
+
+	popInvert := effects.push(d2math.InvertColorMatrix(), 0)
+	effects.push(d2math.TintColorMatrix(color.NRGBA{R: 255, A: 255}), 0.5)
+
+	transform, ok := effects.transform(1)
+	if !ok {
+		t.Fatal("wanted a transform with effects")
+	}
+
+	// inverted, then tinted red
+	if got, want := transform.Apply(color.NRGBA{R: 55, G: 100, A: 255}), (color.NRGBA{R: 200, A: 255}); got != want {
+		t.Errorf("wanted %v: got %v", want, got)
+	}
+
+	weakened, _ := effects.transform(0.5)
+	if got, want := weakened.Apply(color.NRGBA{R: 55, G: 100, A: 255}), (color.NRGBA{R: 128, G: 50, A: 255}); got != want {
+		t.Errorf("wanted half the transform at half intensity %v: got %v", want, got)
+	}
+
+	if _, ok := effects.transform(0); ok {
+		t.Error("wanted no transform at no intensity")
+	}
+
+	effects.advance(0.6)
+
+	if len(effects.effects) != 1 {
+		t.Errorf("wanted the timed effect to end: got %d effects", len(effects.effects))
+	}
+
+	popInvert()
+	popInvert()
+
+	if _, ok := effects.transform(1); ok {
+		t.Error("wanted popping to restore the palette")
+	}
+}
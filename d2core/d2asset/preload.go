@@ -0,0 +1,53 @@
+package d2asset
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// directionsToPreload are enough 64-direction indexes to reach every direction of an animation.
+const directionsToPreload = 64
+
+// PreloadAnimation loads the animation and decodes all of its directions, so it doesn't hitch the first time it is
+// drawn. Loaded animations share their decoded directions with the animations cloned from the cache.
+func PreloadAnimation(animationPath, palettePath string) error {
+	animation, err := LoadAnimation(animationPath, palettePath)
+	if err != nil {
+		return err
+	}
+
+	return preloadDirections(animation)
+}
+
+// PreloadModes loads the layers of the composite in each of the animation modes with its current equipment, and
+// decodes all of their directions. Modes the composite has no animation for are skipped.
+func (c *Composite) PreloadModes(weaponClass string, modes ...animationMode) error {
+	for _, animationMode := range modes {
+		mode, err := c.createMode(animationMode, weaponClass)
+		if err != nil {
+			continue
+		}
+
+		for _, layer := range mode.layers {
+			if layer == nil {
+				continue
+			}
+
+			if err := preloadDirections(layer); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// preloadDirections decodes every direction of the animation, then turns it back to the first one.
+func preloadDirections(animation d2interface.Animation) error {
+	for direction := 0; direction < directionsToPreload; direction++ {
+		if err := animation.SetDirection(direction); err != nil {
+			return err
+		}
+	}
+
+	return animation.SetDirection(0)
+}
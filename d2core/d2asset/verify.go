@@ -0,0 +1,160 @@
+package d2asset
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2mpq"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+)
+
+// requiredFiles are files the game can't start without, one or more from each of the archives of the game.
+//nolint:gochecknoglobals // constant list
+var requiredFiles = []string{
+	d2resource.PaletteAct1,
+	d2resource.PaletteLoading,
+	d2resource.LoadingScreen,
+	d2resource.TrademarkScreen,
+	d2resource.StringTable,
+	d2resource.ExpansionStringTable,
+	d2resource.PatchStringTable,
+	d2resource.BGMTitle,
+}
+
+// ArchiveCheck is the result of checking one archive of the installation.
+type ArchiveCheck struct {
+	Name string
+	Path string
+	Err  error // nil if the archive opened and its tables are sound
+}
+
+// FileCheck is the result of reading one of the files the game requires.
+type FileCheck struct {
+	Path string
+	Err  error // ErrNotFound if no archive has the file, nil if it was read
+}
+
+// InstallReport is the result of verifying the installation of the game.
+type InstallReport struct {
+	MpqPath  string
+	Archives []ArchiveCheck
+	Files    []FileCheck
+}
+
+// VerifyInstallation opens the archives of the configured MPQ chain, checks their integrity, and reads the files the
+// game requires from them, without loading any asset. It doesn't use the override directory.
+func VerifyInstallation(config *d2config.Configuration) *InstallReport {
+	report := &InstallReport{MpqPath: config.MpqPath}
+	archives := make([]d2interface.Archive, 0, len(config.MpqLoadOrder))
+
+	for _, archiveName := range config.MpqLoadOrder {
+		check := ArchiveCheck{Name: archiveName, Path: path.Join(config.MpqPath, archiveName)}
+
+		archive, err := d2mpq.Load(check.Path)
+		if err == nil {
+			err = archive.(*d2mpq.MPQ).Verify()
+			archives = append(archives, archive)
+		}
+
+		check.Err = err
+		report.Archives = append(report.Archives, check)
+	}
+
+	fm := &fileManager{config: config}
+
+	for _, filePath := range requiredFiles {
+		report.Files = append(report.Files, verifyFile(archives, fm.fixupFilePath(filePath)))
+	}
+
+	for _, archive := range archives {
+		archive.Close()
+	}
+
+	return report
+}
+
+// verifyFile reads the file from the first archive which has it.
+func verifyFile(archives []d2interface.Archive, filePath string) (check FileCheck) {
+	check.Path = filePath
+
+	// the archives may still be broken past the checks of their tables
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			check.Err = d2common.WrapAssetError(filePath, fmt.Errorf("%w: %v", d2common.ErrCorrupt, recovered))
+		}
+	}()
+
+	for _, archive := range archives {
+		if archive.Contains(filePath) {
+			_, err := archive.ReadFile(filePath)
+			check.Err = d2common.WrapAssetError(filePath, err)
+
+			return check
+		}
+	}
+
+	check.Err = d2common.WrapAssetError(filePath, d2common.ErrNotFound)
+
+	return check
+}
+
+// OK returns true if every archive and required file was read.
+func (r *InstallReport) OK() bool {
+	for _, archive := range r.Archives {
+		if archive.Err != nil {
+			return false
+		}
+	}
+
+	for _, file := range r.Files {
+		if file.Err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Summary describes what is wrong with the installation, and what to do about it.
+func (r *InstallReport) Summary() string {
+	var summary strings.Builder
+
+	fmt.Fprintf(&summary, "verifying the installation in %s\n", r.MpqPath)
+
+	for _, archive := range r.Archives {
+		switch {
+		case archive.Err == nil:
+			fmt.Fprintf(&summary, "  ok       %s\n", archive.Name)
+		case errors.Is(archive.Err, d2common.ErrNotFound):
+			fmt.Fprintf(&summary, "  MISSING  %s: copy it from your Diablo II installation to %s\n", archive.Name,
+				r.MpqPath)
+		default:
+			fmt.Fprintf(&summary, "  BROKEN   %s: %v; copy it again from your Diablo II installation\n", archive.Name,
+				archive.Err)
+		}
+	}
+
+	for _, file := range r.Files {
+		switch {
+		case file.Err == nil:
+			continue
+		case errors.Is(file.Err, d2common.ErrNotFound):
+			fmt.Fprintf(&summary, "  MISSING  %s is in none of the archives; check the MPQ load order\n", file.Path)
+		default:
+			fmt.Fprintf(&summary, "  BROKEN   %v\n", file.Err)
+		}
+	}
+
+	if r.OK() {
+		summary.WriteString("the installation is complete\n")
+	} else {
+		summary.WriteString("the installation is incomplete, the game will fail to start until the above is fixed\n")
+	}
+
+	return summary.String()
+}
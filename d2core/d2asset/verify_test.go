@@ -0,0 +1,58 @@
+package d2asset
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+)
+
+func TestVerifyInstallation(t *testing.T) {
+	mpqPath, err := ioutil.TempDir("", "install")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(mpqPath)
+
+	if err := ioutil.WriteFile(filepath.Join(mpqPath, "d2data.mpq"), []byte("not an archive"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	report := VerifyInstallation(&d2config.Configuration{
+		MpqPath:      mpqPath,
+		MpqLoadOrder: []string{"Patch_D2.mpq", "d2data.mpq"},
+		Language:     "ENG",
+	})
+
+	if report.OK() {
+		t.Fatal("an installation without archives should not be complete")
+	}
+
+	if !errors.Is(report.Archives[0].Err, d2common.ErrNotFound) {
+		t.Errorf("expected the missing archive to be not found, got %v", report.Archives[0].Err)
+	}
+
+	if !errors.Is(report.Archives[1].Err, d2common.ErrCorrupt) {
+		t.Errorf("expected the broken archive to be corrupt, got %v", report.Archives[1].Err)
+	}
+
+	for _, file := range report.Files {
+		if !errors.Is(file.Err, d2common.ErrNotFound) {
+			t.Errorf("expected %s to be not found, got %v", file.Path, file.Err)
+		}
+	}
+
+	summary := report.Summary()
+
+	for _, expected := range []string{"MISSING  Patch_D2.mpq", "BROKEN   d2data.mpq", `data\local\lng\eng\string.tbl`} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("expected the summary to report %q:\n%s", expected, summary)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Package d2audio holds the audio logic shared by the audio providers.
+package d2audio
+
+import "math"
+
+// Ducker lowers the volume of the music and other sounds while important sounds play, easing the volume down over
+// the attack and back up over the release.
+type Ducker struct {
+	amount    float64 // fraction of the volume taken off
+	attack    float64 // seconds to lower the volume
+	release   float64 // seconds to restore the volume
+	remaining float64 // seconds the important sounds keep playing
+	level     float64 // how far the volume is lowered, from 0 to 1
+}
+
+// CreateDucker creates a ducker taking the fraction amount off the volume, over attack seconds, and restoring it over
+// release seconds.
+func CreateDucker(amount, attack, release float64) *Ducker {
+	return &Ducker{amount: amount, attack: attack, release: release}
+}
+
+// Duck lowers the volume for an important sound lasting duration seconds. Overlapping sounds keep the volume lowered
+// until the last one ends.
+func (d *Ducker) Duck(duration float64) {
+	d.remaining = math.Max(d.remaining, duration)
+}
+
+// Advance moves the volume towards lowered while important sounds play, and towards restored otherwise.
+func (d *Ducker) Advance(elapsed float64) {
+	if d.remaining > 0 {
+		d.remaining -= elapsed
+		d.level = math.Min(1, d.level+rate(elapsed, d.attack))
+
+		return
+	}
+
+	d.level = math.Max(0, d.level-rate(elapsed, d.release))
+}
+
+// Volume returns the factor to multiply the volume of the other sounds by.
+func (d *Ducker) Volume() float64 {
+	return 1 - d.amount*d.level
+}
+
+// IsDucking returns true while the volume isn't fully restored.
+func (d *Ducker) IsDucking() bool {
+	return d.level > 0
+}
+
+// rate returns the fraction of a ramp lasting duration seconds covered in the elapsed seconds.
+func rate(elapsed, duration float64) float64 {
+	if duration <= 0 {
+		return 1
+	}
+
+	return elapsed / duration
+}
@@ -0,0 +1,34 @@
+package d2audio
+
+import (
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestDucker(t *testing.T) {
+	assert := testify.New(t)
+
+	ducker := CreateDucker(0.5, 0.25, 0.5)
+	assert.Equal(1.0, ducker.Volume())
+
+	ducker.Duck(1)
+	ducker.Advance(0.125)
+	assert.Equal(0.75, ducker.Volume(), "halfway through the attack")
+
+	ducker.Advance(0.125)
+	assert.Equal(0.5, ducker.Volume())
+
+	ducker.Duck(0.5)
+	ducker.Advance(0.5)
+	assert.Equal(0.5, ducker.Volume(), "a shorter sound doesn't cut the longer one short")
+
+	ducker.Advance(0.25)
+	ducker.Advance(0.25)
+	assert.Equal(0.75, ducker.Volume(), "halfway through the release")
+	assert.True(ducker.IsDucking())
+
+	ducker.Advance(0.25)
+	assert.Equal(1.0, ducker.Volume())
+	assert.False(ducker.IsDucking())
+}
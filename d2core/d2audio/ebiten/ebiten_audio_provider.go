@@ -6,12 +6,16 @@ import (
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2audio"
 
 	"github.com/hajimehoshi/ebiten/audio"
 	"github.com/hajimehoshi/ebiten/audio/wav"
 )
 
-const sampleRate = 44100
+const (
+	sampleRate     = 44100
+	bytesPerSecond = sampleRate * 4 // ebiten decodes to 16 bit stereo
+)
 
 var _ d2interface.AudioProvider = &AudioProvider{} // Static check to confirm struct conforms to interface
 
@@ -19,14 +23,16 @@ var _ d2interface.AudioProvider = &AudioProvider{} // Static check to confirm st
 type AudioProvider struct {
 	audioContext *audio.Context // The Audio context
 	bgmAudio     *audio.Player  // The audio player
+	stinger      *audio.Player  // The stinger played over the music, nil if none
 	lastBgm      string
 	sfxVolume    float64
 	bgmVolume    float64
+	ducker       *d2audio.Ducker // Lowers the music and other sounds while important sounds play
 }
 
 // CreateAudio creates an instance of ebiten's audio provider
 func CreateAudio() (*AudioProvider, error) {
-	result := &AudioProvider{}
+	result := &AudioProvider{ducker: d2audio.CreateDucker(0, 0, 0)}
 
 	var err error
 	result.audioContext, err = audio.NewContext(sampleRate)
@@ -46,6 +52,7 @@ func (eap *AudioProvider) PlayBGM(song string) {
 	}
 
 	eap.lastBgm = song
+	eap.stopStinger()
 
 	if song == "" && eap.bgmAudio != nil && eap.bgmAudio.IsPlaying() {
 		_ = eap.bgmAudio.Pause()
@@ -80,7 +87,7 @@ func (eap *AudioProvider) PlayBGM(song string) {
 		log.Fatal(err)
 	}
 
-	eap.bgmAudio.SetVolume(eap.bgmVolume)
+	eap.bgmAudio.SetVolume(eap.bgmVolume * eap.ducker.Volume())
 
 	// Play the infinite-length stream. This never ends.
 	err = eap.bgmAudio.Rewind()
@@ -96,13 +103,74 @@ func (eap *AudioProvider) PlayBGM(song string) {
 	}
 }
 
+// PlayStinger plays a short piece of music once over the music playing, such as when a boss appears, lowering the
+// music while it plays
+func (eap *AudioProvider) PlayStinger(stinger string) {
+	eap.stopStinger()
+
+	audioStream, err := d2asset.LoadFileStream(stinger)
+	if err != nil {
+		log.Printf("failed to load the stinger %s: %v", stinger, err)
+		return
+	}
+
+	d, err := wav.Decode(eap.audioContext, audioStream)
+	if err != nil {
+		log.Printf("failed to decode the stinger %s: %v", stinger, err)
+		return
+	}
+
+	eap.stinger, err = audio.NewPlayer(eap.audioContext, d)
+	if err != nil {
+		log.Printf("failed to play the stinger %s: %v", stinger, err)
+		return
+	}
+
+	eap.stinger.SetVolume(eap.bgmVolume)
+	eap.ducker.Duck(float64(d.Length()) / bytesPerSecond)
+
+	if err := eap.stinger.Play(); err != nil {
+		log.Printf("failed to play the stinger %s: %v", stinger, err)
+	}
+}
+
+func (eap *AudioProvider) stopStinger() {
+	if eap.stinger == nil {
+		return
+	}
+
+	if err := eap.stinger.Close(); err != nil {
+		log.Print(err)
+	}
+
+	eap.stinger = nil
+}
+
 // LoadSoundEffect loads a sound affect so that it canb e played
 func (eap *AudioProvider) LoadSoundEffect(sfx string) (d2interface.SoundEffect, error) {
 	result := CreateSoundEffect(sfx, eap.audioContext, eap.sfxVolume) // TODO: Split
+	result.ducker = eap.ducker
 
 	return result, nil
 }
 
+// SetDucking sets the fraction of the volume taken off the music and other sounds while important sounds play, and
+// the seconds taken to lower and restore it
+func (eap *AudioProvider) SetDucking(amount, attack, release float64) {
+	eap.ducker = d2audio.CreateDucker(amount, attack, release)
+}
+
+// Advance lowers or restores the volume of the music, depending on whether important sounds are playing
+func (eap *AudioProvider) Advance(elapsed float64) {
+	wasDucking := eap.ducker.IsDucking()
+
+	eap.ducker.Advance(elapsed)
+
+	if eap.bgmAudio != nil && (wasDucking || eap.ducker.IsDucking()) {
+		eap.bgmAudio.SetVolume(eap.bgmVolume * eap.ducker.Volume())
+	}
+}
+
 // SetVolumes sets the volumes of the audio provider
 func (eap *AudioProvider) SetVolumes(bgmVolume, sfxVolume float64) {
 	eap.sfxVolume = sfxVolume
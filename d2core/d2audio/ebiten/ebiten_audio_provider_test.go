@@ -0,0 +1,87 @@
+package ebiten
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+)
+
+// writeSilence writes a wave file of silence lasting the seconds, in the format ebiten decodes to.
+func writeSilence(t *testing.T, path string, seconds float64) {
+	dataSize := uint32(seconds * bytesPerSecond)
+
+	var buf bytes.Buffer
+
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+
+	// 16 bit stereo PCM at the sample rate of the context
+	for _, field := range []interface{}{
+		uint32(16), uint16(1), uint16(2), uint32(sampleRate), uint32(bytesPerSecond), uint16(4), uint16(16),
+	} {
+		_ = binary.Write(&buf, binary.LittleEndian, field)
+	}
+
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, dataSize)
+	buf.Write(make([]byte, dataSize))
+
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPlayStingerDucksMusic(t *testing.T) {
+	assert := testify.New(t)
+
+	dir, err := ioutil.TempDir("", "d2audio")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	writeSilence(t, filepath.Join(dir, "music.wav"), 1)
+	writeSilence(t, filepath.Join(dir, "stinger.wav"), 0.5)
+
+	config := d2config.Config
+	defer func() { d2config.Config = config }()
+
+	// the sounds are loaded from the override directory
+	d2config.Config = &d2config.Configuration{OverridePath: dir}
+	if err := d2asset.Initialize(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := CreateAudio()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider.SetVolumes(1, 1)
+	provider.SetDucking(0.5, 0.1, 0.1)
+
+	provider.PlayBGM("/music.wav")
+	provider.Advance(0.1)
+	assert.Equal(1.0, provider.bgmAudio.Volume())
+
+	provider.PlayStinger("/stinger.wav")
+	provider.Advance(0.1)
+	assert.InDelta(0.5, provider.bgmAudio.Volume(), 0.001, "the music is lowered while the stinger plays")
+	assert.True(provider.bgmAudio.IsPlaying(), "the stinger plays over the music without interrupting it")
+
+	provider.Advance(0.4)
+	assert.InDelta(0.5, provider.bgmAudio.Volume(), 0.001)
+
+	provider.Advance(0.1)
+	assert.Equal(1.0, provider.bgmAudio.Volume(), "the music is restored once the stinger ends")
+}
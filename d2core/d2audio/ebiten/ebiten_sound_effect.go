@@ -5,18 +5,24 @@ import (
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2audio"
 	"github.com/hajimehoshi/ebiten/audio"
 	"github.com/hajimehoshi/ebiten/audio/wav"
 )
 
 // SoundEffect represents an ebiten implementation of a sound effect
 type SoundEffect struct {
-	player *audio.Player
+	player    *audio.Player
+	volume    float64         // the sound effects volume
+	scale     float64         // fraction of the sound effects volume played at
+	duration  float64         // length in seconds
+	important bool            // lowers the other sounds while playing
+	ducker    *d2audio.Ducker // the ducker of the audio provider, nil if none
 }
 
 // CreateSoundEffect creates a new instance of ebiten's sound effect implementation.
 func CreateSoundEffect(sfx string, context *audio.Context, volume float64) *SoundEffect {
-	result := &SoundEffect{}
+	result := &SoundEffect{volume: volume, scale: 1}
 
 	var soundFile string
 
@@ -48,12 +54,21 @@ func CreateSoundEffect(sfx string, context *audio.Context, volume float64) *Soun
 	player.SetVolume(volume)
 
 	result.player = player
+	result.duration = float64(d.Length()) / bytesPerSecond
 
 	return result
 }
 
 // Play plays the sound effect
 func (v *SoundEffect) Play() {
+	if v.ducker != nil {
+		if v.important {
+			v.ducker.Duck(v.duration)
+		} else {
+			v.player.SetVolume(v.volume * v.scale * v.ducker.Volume())
+		}
+	}
+
 	err := v.player.Rewind()
 
 	if err != nil {
@@ -67,6 +82,19 @@ func (v *SoundEffect) Play() {
 	}
 }
 
+// SetVolume sets the volume of the sound effect, as a fraction of the sound effects volume, such as to muffle sounds
+// behind walls
+func (v *SoundEffect) SetVolume(volume float64) {
+	v.scale = volume
+	v.player.SetVolume(v.volume * volume)
+}
+
+// SetImportant makes the sound effect lower the volume of the music and other sounds while it plays, such as for boss
+// roars
+func (v *SoundEffect) SetImportant(important bool) {
+	v.important = important
+}
+
 // Stop stops the sound effect
 func (v *SoundEffect) Stop() {
 	err := v.player.Pause()
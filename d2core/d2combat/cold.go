@@ -0,0 +1,63 @@
+package d2combat
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// framesPerSecond converts the lengths in frames of the txt files to seconds
+const framesPerSecond = 25
+
+// Chillable is a defender which cold damage slows down and freezing hits stop
+type Chillable interface {
+	// Chill slows the defender for the duration in seconds, returning false if it can't be chilled.
+	Chill(duration float64) bool
+	// Freeze stops the defender for the duration in seconds, returning false if it can't be frozen.
+	Freeze(duration float64) bool
+}
+
+// Freezer is an attacker whose hits freeze their targets, as with the "Freezes Target" item stat
+type Freezer interface {
+	// FreezeLength returns how long the attacker's hits freeze their targets in frames, 0 if they don't.
+	FreezeLength() int
+}
+
+// Chill chills the defender for the cold length in frames when the hit dealt cold damage, returning true if it was
+// chilled. Monsters are chilled for shorter on higher difficulties, divided by the difficulty's monster cold divisor.
+func (a Attack) Chill(hit Hit, length int, difficulty d2enum.Difficulty) bool {
+	chillable, ok := a.Defender.(Chillable)
+	if !ok || a.Element != d2enum.DamageCold || hit.Damage <= 0 || length <= 0 {
+		return false
+	}
+
+	divisor := 1
+	if record := d2datadict.GetDifficultyLevel(difficulty); record != nil && a.monsterDefender() {
+		divisor = positiveDivisor(record.MonsterColdDivisor)
+	}
+
+	return chillable.Chill(float64(length) / framesPerSecond / float64(divisor))
+}
+
+// Freeze freezes the defender for the attacker's freeze length when the hit dealt damage, returning true if it was
+// frozen. Monsters are frozen for shorter on higher difficulties, divided by the difficulty's monster freeze divisor.
+func (a Attack) Freeze(hit Hit, difficulty d2enum.Difficulty) bool {
+	freezer, isFreezer := a.Attacker.(Freezer)
+	chillable, isChillable := a.Defender.(Chillable)
+
+	if !isFreezer || !isChillable || a.Reflected || hit.Damage <= 0 || freezer.FreezeLength() <= 0 {
+		return false
+	}
+
+	divisor := 1
+	if record := d2datadict.GetDifficultyLevel(difficulty); record != nil && a.monsterDefender() {
+		divisor = positiveDivisor(record.MonsterFreezeDivisor)
+	}
+
+	return chillable.Freeze(float64(freezer.FreezeLength()) / framesPerSecond / float64(divisor))
+}
+
+func (a Attack) monsterDefender() bool {
+	target, ok := a.Defender.(Target)
+
+	return !ok || target.Kind() != DefenderPlayer
+}
@@ -0,0 +1,102 @@
+package d2combat
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+type chillTestDefender struct {
+	testDefender
+	immune          bool
+	chilled, frozen float64
+}
+
+func (d *chillTestDefender) Chill(duration float64) bool {
+	if d.immune {
+		return false
+	}
+
+	d.chilled = duration
+
+	return true
+}
+
+func (d *chillTestDefender) Freeze(duration float64) bool {
+	if !d.Chill(duration) {
+		return false
+	}
+
+	d.frozen = duration
+
+	return true
+}
+
+type freezeTestAttacker struct {
+	testDefender
+	length int
+}
+
+func (a freezeTestAttacker) FreezeLength() int {
+	return a.length
+}
+
+func TestAttackChill(t *testing.T) {
+	assert := testify.New(t)
+
+	defender := &chillTestDefender{}
+	attack := Attack{Defender: defender, Element: d2enum.DamageCold, Damage: 10}
+
+	assert.True(attack.Chill(attack.Resolve(), 50, d2enum.DifficultyNormal))
+	assert.InDelta(2, defender.chilled, 1e-9, "cold length is in frames")
+	assert.Zero(defender.frozen, "cold damage chills without freezing")
+
+	defender.chilled = 0
+	attack.Element = d2enum.DamageFire
+	assert.False(attack.Chill(attack.Resolve(), 50, d2enum.DifficultyNormal))
+	assert.Zero(defender.chilled, "only cold damage chills")
+
+	attack.Element, defender.resistance = d2enum.DamageCold, 100
+	assert.False(attack.Chill(attack.Resolve(), 50, d2enum.DifficultyNormal), "immune defenders take no cold damage")
+
+	defender.resistance, defender.immune = 0, true
+	assert.False(attack.Chill(attack.Resolve(), 50, d2enum.DifficultyNormal), "a 0 cold effect can't be chilled")
+}
+
+func TestAttackFreeze(t *testing.T) {
+	assert := testify.New(t)
+
+	defender := &chillTestDefender{}
+	attack := Attack{Attacker: freezeTestAttacker{length: 25}, Defender: defender, Damage: 10, Melee: true}
+
+	assert.True(attack.Freeze(attack.Resolve(), d2enum.DifficultyNormal))
+	assert.InDelta(1, defender.frozen, 1e-9)
+	assert.InDelta(1, defender.chilled, 1e-9, "frozen defenders are chilled as long")
+
+	defender.frozen = 0
+	attack.Attacker = freezeTestAttacker{}
+	assert.False(attack.Freeze(attack.Resolve(), d2enum.DifficultyNormal))
+	assert.Zero(defender.frozen, "attackers without freeze length don't freeze")
+}
+
+func TestAttackColdDifficulty(t *testing.T) {
+	levels := d2datadict.DifficultyLevels
+
+	defer func() { d2datadict.DifficultyLevels = levels }()
+
+	d2datadict.DifficultyLevels = map[string]*d2datadict.DifficultyLevelRecord{
+		"Hell": {Name: "Hell", MonsterColdDivisor: 2, MonsterFreezeDivisor: 4},
+	}
+
+	defender := &chillTestDefender{}
+	attack := Attack{Attacker: freezeTestAttacker{length: 100}, Defender: defender, Element: d2enum.DamageCold, Damage: 10}
+
+	attack.Chill(attack.Resolve(), 100, d2enum.DifficultyHell)
+	testify.InDelta(t, 2, defender.chilled, 1e-9)
+
+	attack.Freeze(attack.Resolve(), d2enum.DifficultyHell)
+	testify.InDelta(t, 1, defender.frozen, 1e-9)
+}
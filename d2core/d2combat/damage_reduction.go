@@ -0,0 +1,81 @@
+package d2combat
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+// Defenses are the damage reductions a defender gets from its stats and items, besides its resistances
+type Defenses struct {
+	PhysicalReduction int                          // Flat physical damage reduction, "Damage Reduced by"
+	MagicReduction    int                          // Flat magic damage reduction, "Magic Damage Reduced by"
+	Absorb            map[d2enum.DamageElement]int // Flat elemental damage absorbed, "Fire Absorb"
+	AbsorbPercent     map[d2enum.DamageElement]int // Percent of the elemental damage absorbed, "Fire Absorb %"
+	DamageToMana      int                          // Percent of the damage taken given back as mana
+}
+
+// ArmoredDefender is a defender with damage reductions beyond its resistances, such as a hero wearing items
+type ArmoredDefender interface {
+	Defender
+	Defenses() Defenses
+}
+
+// Hit is the outcome of damage dealt to a defender
+type Hit struct {
+	Damage int // Life the defender loses
+	Heal   int // Life the defender gains from the damage it absorbed
+	Mana   int // Mana the defender gains from the damage it took
+}
+
+// Resolve returns the outcome of damage of the element dealt to the defender, in the order of Diablo II:
+//  1. the resistance, percent damage reduction for physical damage, applies first and may make the defender immune,
+//  2. physical and magic damage are then reduced by the flat reductions,
+//  3. elemental damage is absorbed by the percent absorb, then by the flat absorb, healing the defender as much,
+//  4. a percent of the damage left to take is given back as mana, without lowering it.
+func Resolve(defender Defender, element d2enum.DamageElement, damage int) Hit {
+	hit := Hit{Damage: DamageTaken(defender, element, damage)}
+
+	armored, ok := defender.(ArmoredDefender)
+	if !ok || hit.Damage <= 0 {
+		return hit
+	}
+
+	defenses := armored.Defenses()
+
+	switch element {
+	case d2enum.DamagePhysical:
+		hit.Damage = reduceFlat(hit.Damage, defenses.PhysicalReduction)
+	case d2enum.DamageMagic:
+		hit.Damage = reduceFlat(hit.Damage, defenses.MagicReduction)
+	case d2enum.DamageFire, d2enum.DamageLightning, d2enum.DamageCold:
+		left := hit.Damage - hit.Damage*clampPercent(defenses.AbsorbPercent[element])/percent
+		left = reduceFlat(left, defenses.Absorb[element])
+		hit.Heal = hit.Damage - left
+		hit.Damage = left
+	}
+
+	hit.Mana = hit.Damage * clampPercent(defenses.DamageToMana) / percent
+
+	return hit
+}
+
+// reduceFlat returns the damage lowered by the flat reduction, never below 0.
+func reduceFlat(damage, reduction int) int {
+	if reduction <= 0 {
+		return damage
+	}
+
+	if reduction >= damage {
+		return 0
+	}
+
+	return damage - reduction
+}
+
+func clampPercent(value int) int {
+	switch {
+	case value < 0:
+		return 0
+	case value > percent:
+		return percent
+	default:
+		return value
+	}
+}
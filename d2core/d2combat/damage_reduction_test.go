@@ -0,0 +1,74 @@
+package d2combat
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+type armoredTestDefender struct {
+	testDefender
+	defenses Defenses
+}
+
+func (d armoredTestDefender) Defenses() Defenses {
+	return d.defenses
+}
+
+func TestResolvePhysicalPercentBeforeFlat(t *testing.T) {
+	assert := testify.New(t)
+
+	defender := armoredTestDefender{testDefender{resistance: 50}, Defenses{PhysicalReduction: 10}}
+
+	// 100 halved to 50, then 10 off, not (100 - 10) / 2
+	assert.Equal(Hit{Damage: 40}, Resolve(defender, d2enum.DamagePhysical, 100))
+
+	defender.defenses.PhysicalReduction = 80
+	assert.Equal(Hit{}, Resolve(defender, d2enum.DamagePhysical, 100), "damage never drops below 0")
+}
+
+func TestResolveMagicReduction(t *testing.T) {
+	defender := armoredTestDefender{defenses: Defenses{MagicReduction: 5, PhysicalReduction: 50}}
+
+	testify.Equal(t, Hit{Damage: 15}, Resolve(defender, d2enum.DamageMagic, 20))
+}
+
+func TestResolveAbsorbAfterResistance(t *testing.T) {
+	assert := testify.New(t)
+
+	defender := armoredTestDefender{testDefender{resistance: 50}, Defenses{
+		Absorb:        map[d2enum.DamageElement]int{d2enum.DamageFire: 10},
+		AbsorbPercent: map[d2enum.DamageElement]int{d2enum.DamageFire: 20},
+	}}
+
+	// 200 fire resisted to 100, 20% absorbed leaves 80, 10 more absorbed leaves 70
+	assert.Equal(Hit{Damage: 70, Heal: 30}, Resolve(defender, d2enum.DamageFire, 200))
+
+	// Absorbing more than the damage heals only as much as the damage
+	assert.Equal(Hit{Heal: 10}, Resolve(defender, d2enum.DamageFire, 20))
+
+	// Other elements aren't absorbed
+	assert.Equal(Hit{Damage: 100}, Resolve(defender, d2enum.DamageCold, 200))
+}
+
+func TestResolveImmunityAbsorbsNothing(t *testing.T) {
+	defender := armoredTestDefender{testDefender{resistance: 100}, Defenses{
+		Absorb: map[d2enum.DamageElement]int{d2enum.DamageLightning: 10},
+	}}
+
+	testify.Equal(t, Hit{}, Resolve(defender, d2enum.DamageLightning, 50))
+}
+
+func TestResolveDamageToMana(t *testing.T) {
+	assert := testify.New(t)
+
+	defender := armoredTestDefender{defenses: Defenses{PhysicalReduction: 20, DamageToMana: 25}}
+
+	// The mana is a share of the damage left after the reductions, which it doesn't lower
+	assert.Equal(Hit{Damage: 80, Mana: 20}, Resolve(defender, d2enum.DamagePhysical, 100))
+
+	assert.Equal(Hit{Damage: 30}, Resolve(testDefender{resistance: 70}, d2enum.DamagePhysical, 100),
+		"defenders without defenses only resist")
+}
@@ -0,0 +1,2 @@
+// Package d2combat resolves the damage dealt in combat.
+package d2combat
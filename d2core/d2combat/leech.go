@@ -0,0 +1,52 @@
+package d2combat
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// Leecher is an attacker stealing life and mana with the physical damage it deals
+type Leecher interface {
+	// Leech returns the percent of the physical damage dealt stolen as life, and as mana.
+	Leech() (life, mana int)
+}
+
+// LeechSensitive is a defender which life and mana steal don't fully apply to, as monsters with a monstats.txt Drain
+type LeechSensitive interface {
+	// LeechSensitivity returns the percent of the attackers' life and mana steal which applies, 0 for none.
+	LeechSensitivity() int
+}
+
+// Leech returns the life and mana the attacker steals with the hit on the difficulty. Only physical damage is leeched,
+// reduced by the defender's leech sensitivity and divided by the difficulty's steal divisors. The attacker must cap
+// what it gains at its maximum life and mana.
+func (a Attack) Leech(hit Hit, difficulty d2enum.Difficulty) (life, mana int) {
+	leecher, ok := a.Attacker.(Leecher)
+	if !ok || a.Element != d2enum.DamagePhysical || hit.Damage <= 0 {
+		return 0, 0
+	}
+
+	sensitivity := percent
+	if sensitive, ok := a.Defender.(LeechSensitive); ok {
+		sensitivity = clampPercent(sensitive.LeechSensitivity())
+	}
+
+	lifeDivisor, manaDivisor := 1, 1
+	if record := d2datadict.GetDifficultyLevel(difficulty); record != nil {
+		lifeDivisor, manaDivisor = positiveDivisor(record.LifeStealDivisor), positiveDivisor(record.ManaStealDivisor)
+	}
+
+	lifeSteal, manaSteal := leecher.Leech()
+	life = hit.Damage * clampPercent(lifeSteal) / percent * sensitivity / percent / lifeDivisor
+	mana = hit.Damage * clampPercent(manaSteal) / percent * sensitivity / percent / manaDivisor
+
+	return life, mana
+}
+
+func positiveDivisor(divisor int) int {
+	if divisor < 1 {
+		return 1
+	}
+
+	return divisor
+}
@@ -0,0 +1,68 @@
+package d2combat
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+type leechTestAttacker struct {
+	testDefender
+	life, mana int
+}
+
+func (a leechTestAttacker) Leech() (life, mana int) {
+	return a.life, a.mana
+}
+
+type sensitiveTestDefender struct {
+	testDefender
+	sensitivity int
+}
+
+func (d sensitiveTestDefender) LeechSensitivity() int {
+	return d.sensitivity
+}
+
+func TestAttackLeech(t *testing.T) {
+	assert := testify.New(t)
+
+	attacker := leechTestAttacker{life: 10, mana: 5}
+	attack := Attack{Attacker: attacker, Defender: testDefender{resistance: 50}, Damage: 400, Melee: true}
+
+	life, mana := attack.Leech(attack.Resolve(), d2enum.DifficultyNormal)
+	assert.Equal(20, life, "leech applies to the damage dealt after resistances")
+	assert.Equal(10, mana)
+
+	attack.Defender = sensitiveTestDefender{sensitivity: 50}
+	life, mana = attack.Leech(attack.Resolve(), d2enum.DifficultyNormal)
+	assert.Equal(20, life)
+	assert.Equal(10, mana)
+
+	attack.Defender = sensitiveTestDefender{}
+	life, mana = attack.Leech(attack.Resolve(), d2enum.DifficultyNormal)
+	assert.Zero(life+mana, "monsters with a 0 drain can't be leeched")
+
+	attack.Defender, attack.Element = testDefender{}, d2enum.DamageFire
+	life, mana = attack.Leech(attack.Resolve(), d2enum.DifficultyNormal)
+	assert.Zero(life+mana, "elemental damage isn't leeched")
+}
+
+func TestAttackLeechDifficulty(t *testing.T) {
+	levels := d2datadict.DifficultyLevels
+
+	defer func() { d2datadict.DifficultyLevels = levels }()
+
+	d2datadict.DifficultyLevels = map[string]*d2datadict.DifficultyLevelRecord{
+		"Hell": {Name: "Hell", LifeStealDivisor: 3, ManaStealDivisor: 2},
+	}
+
+	attack := Attack{Attacker: leechTestAttacker{life: 10, mana: 10}, Defender: testDefender{}, Damage: 300}
+
+	life, mana := attack.Leech(attack.Resolve(), d2enum.DifficultyHell)
+	testify.Equal(t, 10, life)
+	testify.Equal(t, 15, mana)
+}
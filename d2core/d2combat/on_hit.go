@@ -0,0 +1,131 @@
+package d2combat
+
+import (
+	"math/rand"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// DefenderKind is the kind of defender, on-hit effects are weaker against bosses and players
+type DefenderKind int
+
+// Kinds of defenders
+const (
+	DefenderMonster DefenderKind = iota
+	DefenderBoss
+	DefenderPlayer
+)
+
+// OpenWoundsDuration is how long open wounds bleed, in seconds.
+const OpenWoundsDuration = 8.0
+
+// Striker is an attacker whose hits can trigger crushing blow and open wounds, with chances from its items
+type Striker interface {
+	// OnHitChances returns the percent chances of crushing blow and of open wounds.
+	OnHitChances() (crushingBlow, openWounds int)
+	// Level returns the attacker's character level, which open wounds damage grows with.
+	Level() int
+}
+
+// Target is a defender with life which on-hit effects depend on
+type Target interface {
+	Defender
+	Life() int
+	Kind() DefenderKind
+}
+
+// crushingBlowDivisors are the shares of their current life defenders lose to a melee crushing blow, as in Diablo II
+// since 1.10. Ranged crushing blows take half as much.
+//nolint:gochecknoglobals // constant lookup table
+var crushingBlowDivisors = map[DefenderKind]int{
+	DefenderMonster: 4,
+	DefenderBoss:    8,
+	DefenderPlayer:  10,
+}
+
+// CrushingBlowDamage returns the damage of a crushing blow on a defender with the given current life: a quarter of it
+// for monsters, an eighth for bosses and a tenth for players, halved for ranged attacks, then reduced by the physical
+// resistance.
+func CrushingBlowDamage(life int, kind DefenderKind, ranged bool, physicalResistance int) int {
+	divisor := crushingBlowDivisors[kind]
+	if divisor == 0 {
+		divisor = crushingBlowDivisors[DefenderMonster]
+	}
+
+	if ranged {
+		divisor *= 2
+	}
+
+	return ResistDamage(life/divisor, physicalResistance)
+}
+
+// OpenWoundsRate returns the damage per second open wounds inflicted by an attacker of the level deal, following
+// Diablo II's formula in 256ths of life per frame. Players bleed a quarter as much.
+func OpenWoundsRate(level int, kind DefenderKind) float64 {
+	var perFrame int
+
+	switch {
+	case level < 16:
+		perFrame = 9*level + 31
+	case level < 31:
+		perFrame = 18*level - 104
+	case level < 46:
+		perFrame = 27*level - 374
+	default:
+		perFrame = 36*level - 779
+	}
+
+	rate := float64(perFrame) * 25 / 256 //nolint:gomnd // 256ths of life per frame at 25 frames per second
+
+	if kind == DefenderPlayer {
+		rate /= 4
+	}
+
+	return rate
+}
+
+// CrushingBlow rolls the attacker's crushing blow chance on a hit and returns the damage it adds, 0 if it didn't
+// trigger. Only physical hits on defenders with life can crush.
+func (a Attack) CrushingBlow(hit Hit) int {
+	striker, target, ok := a.onHitParties(hit)
+	if !ok {
+		return 0
+	}
+
+	chance, _ := striker.OnHitChances()
+	if rand.Intn(percent) >= chance { //nolint:gosec // combat rolls don't need crypto rand
+		return 0
+	}
+
+	resistance := EffectiveResistance(target.Resistance(d2enum.DamagePhysical),
+		target.ResistanceReduction(d2enum.DamagePhysical))
+
+	return CrushingBlowDamage(target.Life(), target.Kind(), !a.Melee, resistance)
+}
+
+// OpenWounds rolls the attacker's open wounds chance on a hit and returns the bleeding damage per second it inflicts
+// for OpenWoundsDuration, false if it didn't trigger.
+func (a Attack) OpenWounds(hit Hit) (float64, bool) {
+	striker, target, ok := a.onHitParties(hit)
+	if !ok {
+		return 0, false
+	}
+
+	_, chance := striker.OnHitChances()
+	if rand.Intn(percent) >= chance { //nolint:gosec // combat rolls don't need crypto rand
+		return 0, false
+	}
+
+	return OpenWoundsRate(striker.Level(), target.Kind()), true
+}
+
+func (a Attack) onHitParties(hit Hit) (Striker, Target, bool) {
+	striker, isStriker := a.Attacker.(Striker)
+	target, isTarget := a.Defender.(Target)
+
+	if !isStriker || !isTarget || a.Reflected || a.Element != d2enum.DamagePhysical || hit.Damage <= 0 {
+		return nil, nil, false
+	}
+
+	return striker, target, true
+}
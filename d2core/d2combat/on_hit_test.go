@@ -0,0 +1,80 @@
+package d2combat
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+type onHitTestAttacker struct {
+	testDefender
+	crushingBlow, openWounds, level int
+}
+
+func (a onHitTestAttacker) OnHitChances() (crushingBlow, openWounds int) {
+	return a.crushingBlow, a.openWounds
+}
+
+func (a onHitTestAttacker) Level() int {
+	return a.level
+}
+
+type onHitTestTarget struct {
+	testDefender
+	life int
+	kind DefenderKind
+}
+
+func (t onHitTestTarget) Life() int {
+	return t.life
+}
+
+func (t onHitTestTarget) Kind() DefenderKind {
+	return t.kind
+}
+
+func TestCrushingBlowDamage(t *testing.T) {
+	assert := testify.New(t)
+
+	assert.Equal(250, CrushingBlowDamage(1000, DefenderMonster, false, 0))
+	assert.Equal(125, CrushingBlowDamage(1000, DefenderMonster, true, 0))
+	assert.Equal(125, CrushingBlowDamage(1000, DefenderBoss, false, 0))
+	assert.Equal(62, CrushingBlowDamage(1000, DefenderBoss, true, 0))
+	assert.Equal(100, CrushingBlowDamage(1000, DefenderPlayer, false, 0))
+	assert.Equal(50, CrushingBlowDamage(1000, DefenderPlayer, true, 0))
+	assert.Equal(125, CrushingBlowDamage(1000, DefenderMonster, false, 50), "physical resistance reduces it")
+	assert.Equal(0, CrushingBlowDamage(1000, DefenderMonster, false, 100), "physical immunity stops it")
+}
+
+func TestOpenWoundsRate(t *testing.T) {
+	assert := testify.New(t)
+
+	assert.InDelta(3.90625, OpenWoundsRate(1, DefenderMonster), 1e-9)
+	assert.InDelta(42.578125, OpenWoundsRate(30, DefenderMonster), 1e-9)
+	assert.InDelta(10.64453125, OpenWoundsRate(30, DefenderPlayer), 1e-9)
+	assert.InDelta(271.97265625, OpenWoundsRate(99, DefenderMonster), 1e-9)
+}
+
+func TestAttackOnHitEffects(t *testing.T) {
+	assert := testify.New(t)
+
+	attacker := onHitTestAttacker{crushingBlow: 100, openWounds: 100, level: 1}
+	target := onHitTestTarget{life: 400, kind: DefenderMonster}
+	attack := Attack{Attacker: attacker, Defender: target, Element: d2enum.DamagePhysical, Damage: 10, Melee: true}
+
+	hit := attack.Resolve()
+	assert.Equal(100, attack.CrushingBlow(hit))
+
+	rate, ok := attack.OpenWounds(hit)
+	assert.True(ok)
+	assert.InDelta(3.90625, rate, 1e-9)
+
+	attacker.crushingBlow, attacker.openWounds = 0, 0
+	attack.Attacker = attacker
+	assert.Zero(attack.CrushingBlow(hit))
+
+	_, ok = attack.OpenWounds(hit)
+	assert.False(ok)
+}
@@ -0,0 +1,51 @@
+package d2combat
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+// Reflector is a defender returning part of the melee damage it takes to its attacker, as with the Thorns aura or
+// "Attacker Takes Damage" items
+type Reflector interface {
+	// Reflection returns the percent of the physical melee damage taken, and the flat damage, the attacker takes.
+	Reflection() (percent, flat int)
+}
+
+// Attack is damage of one element an attacker deals to a defender, a single combat event
+type Attack struct {
+	Attacker  Defender
+	Defender  Defender
+	Element   d2enum.DamageElement
+	Damage    int
+	Melee     bool
+	Reflected bool // The damage was returned by a Reflector, it is never reflected again
+}
+
+// Resolve returns the outcome of the attack on the defender.
+func (a Attack) Resolve() Hit {
+	return Resolve(a.Defender, a.Element, a.Damage)
+}
+
+// Reflection returns the attack returning damage to the attacker once the hit landed, false if nothing is reflected.
+// Only physical melee damage is reflected. Reflected damage is never reflected again, so two reflecting entities
+// attacking each other don't bounce it forever.
+func (a Attack) Reflection(hit Hit) (Attack, bool) {
+	reflector, ok := a.Defender.(Reflector)
+	if !ok || a.Attacker == nil || !a.Melee || a.Reflected || a.Element != d2enum.DamagePhysical {
+		return Attack{}, false
+	}
+
+	// Thorns returns more than the damage taken at high levels, so the percent isn't capped
+	reflectPercent, flat := reflector.Reflection()
+	damage := hit.Damage*reflectPercent/percent + flat
+
+	if damage <= 0 {
+		return Attack{}, false
+	}
+
+	return Attack{
+		Attacker:  a.Defender,
+		Defender:  a.Attacker,
+		Element:   d2enum.DamagePhysical,
+		Damage:    damage,
+		Reflected: true,
+	}, true
+}
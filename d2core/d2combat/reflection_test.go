@@ -0,0 +1,68 @@
+package d2combat
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+type thornsTestDefender struct {
+	testDefender
+	percent, flat int
+}
+
+func (d thornsTestDefender) Reflection() (percent, flat int) {
+	return d.percent, d.flat
+}
+
+func TestAttackReflection(t *testing.T) {
+	assert := testify.New(t)
+
+	attacker := testDefender{resistance: 50}
+	defender := thornsTestDefender{testDefender{resistance: 20}, 200, 5}
+	attack := Attack{Attacker: attacker, Defender: defender, Element: d2enum.DamagePhysical, Damage: 50, Melee: true}
+
+	hit := attack.Resolve()
+	assert.Equal(40, hit.Damage)
+
+	reflected, ok := attack.Reflection(hit)
+	assert.True(ok)
+	assert.Equal(Attack{Attacker: defender, Defender: attacker, Element: d2enum.DamagePhysical, Damage: 85,
+		Reflected: true}, reflected)
+
+	// The reflected damage is resolved against the attacker's own resistances
+	assert.Equal(42, reflected.Resolve().Damage)
+}
+
+func TestAttackReflectionLoop(t *testing.T) {
+	first := thornsTestDefender{percent: 100}
+	second := thornsTestDefender{percent: 100}
+	attack := Attack{Attacker: first, Defender: second, Element: d2enum.DamagePhysical, Damage: 10, Melee: true}
+
+	reflected, ok := attack.Reflection(attack.Resolve())
+	testify.True(t, ok)
+
+	_, ok = reflected.Reflection(reflected.Resolve())
+	testify.False(t, ok, "reflected damage is never reflected again")
+}
+
+func TestAttackReflectionIgnored(t *testing.T) {
+	assert := testify.New(t)
+
+	thorns := thornsTestDefender{percent: 100, flat: 10}
+	attacker := testDefender{}
+
+	ranged := Attack{Attacker: attacker, Defender: thorns, Element: d2enum.DamagePhysical, Damage: 10}
+	_, ok := ranged.Reflection(ranged.Resolve())
+	assert.False(ok, "ranged attacks aren't reflected")
+
+	fire := Attack{Attacker: attacker, Defender: thorns, Element: d2enum.DamageFire, Damage: 10, Melee: true}
+	_, ok = fire.Reflection(fire.Resolve())
+	assert.False(ok, "elemental damage isn't reflected")
+
+	plain := Attack{Attacker: attacker, Defender: testDefender{}, Element: d2enum.DamagePhysical, Damage: 10, Melee: true}
+	_, ok = plain.Reflection(plain.Resolve())
+	assert.False(ok, "defenders without reflection reflect nothing")
+}
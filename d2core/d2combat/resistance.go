@@ -0,0 +1,78 @@
+package d2combat
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+const (
+	// ImmunityThreshold is the resistance from which a defender takes no damage of an element
+	ImmunityThreshold = 100
+
+	// MinResistance is the lowest resistance reductions can bring a defender to
+	MinResistance = -100
+
+	// immuneReductionDivisor divides the resistance reductions, such as Lower Resist and Conviction, applied to an
+	// immune defender. An immunity is broken when the reduced resistance drops below the threshold.
+	immuneReductionDivisor = 5
+
+	percent = 100
+)
+
+// Defender is hit by damage it resists
+type Defender interface {
+	// Resistance returns the resistance to the element in percent: base, difficulty and modifiers included.
+	Resistance(element d2enum.DamageElement) int
+	// ResistanceReduction returns how much curses and auras such as Lower Resist lower the resistance, in percent.
+	ResistanceReduction(element d2enum.DamageElement) int
+}
+
+// EffectiveResistance returns the resistance once the reduction applies. The reduction only has a fifth of its effect
+// on immune defenders, as in Diablo II since 1.10, and the result never drops below MinResistance.
+func EffectiveResistance(resistance, reduction int) int {
+	if resistance >= ImmunityThreshold {
+		reduction /= immuneReductionDivisor
+	}
+
+	resistance -= reduction
+
+	if resistance < MinResistance {
+		return MinResistance
+	}
+
+	return resistance
+}
+
+// ResistDamage returns the damage left once the resistance reduced it, nothing if the resistance is an immunity.
+// Negative resistances increase the damage.
+func ResistDamage(damage, resistance int) int {
+	if resistance >= ImmunityThreshold {
+		return 0
+	}
+
+	return damage * (percent - resistance) / percent
+}
+
+// DamageTaken returns the damage the defender takes from damage of the element.
+func DamageTaken(defender Defender, element d2enum.DamageElement, damage int) int {
+	resistance := EffectiveResistance(defender.Resistance(element), defender.ResistanceReduction(element))
+	return ResistDamage(damage, resistance)
+}
+
+// SkillElement returns the element of a skill's elemental damage from its EType, false if the skill deals none.
+func SkillElement(skill *d2datadict.SkillRecord) (d2enum.DamageElement, bool) {
+	switch skill.EType {
+	case "mag":
+		return d2enum.DamageMagic, true
+	case "fire":
+		return d2enum.DamageFire, true
+	case "ltng":
+		return d2enum.DamageLightning, true
+	case "cold":
+		return d2enum.DamageCold, true
+	case "pois":
+		return d2enum.DamagePoison, true
+	default:
+		return d2enum.DamagePhysical, false
+	}
+}
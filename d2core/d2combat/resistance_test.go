@@ -0,0 +1,39 @@
+package d2combat
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+type testDefender struct {
+	resistance, reduction int
+}
+
+func (d testDefender) Resistance(d2enum.DamageElement) int {
+	return d.resistance
+}
+
+func (d testDefender) ResistanceReduction(d2enum.DamageElement) int {
+	return d.reduction
+}
+
+func TestEffectiveResistance(t *testing.T) {
+	assert := testify.New(t)
+
+	assert.Equal(10, EffectiveResistance(50, 40))
+	assert.Equal(-100, EffectiveResistance(20, 200))
+	assert.Equal(110, EffectiveResistance(120, 50), "reductions have a fifth of their effect on immunities")
+	assert.Equal(95, EffectiveResistance(110, 75), "immunity broken")
+}
+
+func TestDamageTaken(t *testing.T) {
+	assert := testify.New(t)
+
+	assert.Equal(50, DamageTaken(testDefender{resistance: 50}, d2enum.DamageFire, 100))
+	assert.Equal(150, DamageTaken(testDefender{resistance: -50}, d2enum.DamageFire, 100))
+	assert.Equal(0, DamageTaken(testDefender{resistance: 100, reduction: 4}, d2enum.DamageFire, 100))
+	assert.Equal(5, DamageTaken(testDefender{resistance: 100, reduction: 25}, d2enum.DamageFire, 100))
+}
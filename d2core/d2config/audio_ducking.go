@@ -0,0 +1,49 @@
+package d2config
+
+// The default ducking of the music while important sounds play.
+const (
+	DefaultDuckingAmount  = 0.6 // Fraction of the volume taken off
+	DefaultDuckingAttack  = 0.1 // Seconds to lower the volume
+	DefaultDuckingRelease = 0.5 // Seconds to restore the volume
+)
+
+// AudioDucking holds how much and how fast the music and other sounds are lowered while important sounds, such as boss
+// roars, play.
+type AudioDucking struct {
+	Disabled bool    // The other sounds keep their volume
+	Amount   float64 // Fraction of the volume taken off the other sounds, from 0 to 1
+	Attack   float64 // Seconds to lower the volume of the other sounds
+	Release  float64 // Seconds to restore the volume of the other sounds once the important sounds end
+}
+
+// GetAmount returns the fraction of the volume taken off the other sounds, 0 if ducking is disabled.
+func (d *AudioDucking) GetAmount() float64 {
+	switch {
+	case d.Disabled:
+		return 0
+	case d.Amount <= 0:
+		return DefaultDuckingAmount
+	case d.Amount > 1:
+		return 1
+	}
+
+	return d.Amount
+}
+
+// GetAttack returns the seconds to lower the volume of the other sounds.
+func (d *AudioDucking) GetAttack() float64 {
+	if d.Attack <= 0 {
+		return DefaultDuckingAttack
+	}
+
+	return d.Attack
+}
+
+// GetRelease returns the seconds to restore the volume of the other sounds.
+func (d *AudioDucking) GetRelease() float64 {
+	if d.Release <= 0 {
+		return DefaultDuckingRelease
+	}
+
+	return d.Release
+}
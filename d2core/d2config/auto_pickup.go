@@ -0,0 +1,21 @@
+package d2config
+
+// DefaultAutoPickupTypes are the itemtypes.txt codes of the items picked up by default: scrolls and gems.
+//nolint:gochecknoglobals // constant list
+var DefaultAutoPickupTypes = []string{"scro", "gema", "gemd", "geme", "gemr", "gems", "gemt", "gemz"}
+
+// AutoPickup holds the settings of picking up items by walking near them, off by default as in Diablo II. Gold is
+// always picked up, other items only if their type is listed. Equipment is never picked up.
+type AutoPickup struct {
+	Enabled   bool
+	ItemTypes []string // itemtypes.txt codes of the items picked up, nil for DefaultAutoPickupTypes
+}
+
+// GetItemTypes returns the itemtypes.txt codes of the items picked up.
+func (a *AutoPickup) GetItemTypes() []string {
+	if a.ItemTypes == nil {
+		return DefaultAutoPickupTypes
+	}
+
+	return a.ItemTypes
+}
@@ -0,0 +1,8 @@
+package d2config
+
+// CameraFollow holds where the camera is placed relative to the hero, centered on the hero by default.
+type CameraFollow struct {
+	OffsetX float64 // Screen pixels the view is moved right of the hero, left if negative
+	OffsetY float64 // Screen pixels the view is moved below the hero, above if negative
+	Lead    float64 // Seconds of movement the view looks ahead of the hero, 0 not to lead
+}
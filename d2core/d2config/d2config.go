@@ -10,19 +10,42 @@ import (
 // Config holds the configuration from config.json
 var Config *Configuration //nolint:gochecknoglobals // Currently global by design
 
-// Configuration defines the configuration for the engine, loaded from config.json
+// Configuration defines the configuration for the engine, loaded from config.json. Settings left at their zero
+// values, as in configuration files saved before the settings existed, fall back to their defaults.
 type Configuration struct {
 	MpqLoadOrder    []string
 	Language        string
 	MpqPath         string
+	MapMpqs         bool   // Maps the MPQs in memory and reads their files on demand, lowering the resident memory
+	OverridePath    string // Folder of loose files taking priority over the MPQs, for mods, none if empty
 	TicksPerSecond  int
 	FpsCap          int
 	SfxVolume       float64
 	BgmVolume       float64
+	Ducking         AudioDucking // Lowering of the music while important sounds play
 	FullScreen      bool
 	RunInBackground bool
 	VsyncEnabled    bool
-	Backend         string
+	Backend         string   // Renderer backend, Ebiten or Headless to run without a GPU
+	LogLevel        string   // One of debug, info, warning or error
+	LogCategories   []string // Categories to log, all of them if empty
+	LogFile         string   // Path of the rotating log file, logs only to the console if empty
+	Cheats          bool     // Enables debug cheats such as no-clip in single player games
+	UIScale         UIScale  // Scale factors of the HUD, menus and tooltips
+	Monsters        MonsterScaling
+	AutoPickup      AutoPickup
+	Loot            Loot
+	EntityLimits    EntityLimits
+	Input           InputTiming  // Timing of double clicks and repeated keys
+	ItemLabels      ItemLabels   // Whether Alt shows the labels of the items on the ground while held or toggles them
+	Camera          CameraFollow // Offset of the view from the hero, and how far it looks ahead of the movement
+	Simulation      Simulation   // How far from the players monsters think and move
+	Movement        Movement     // Whether clicks move the hero like Diablo II or more responsively
+
+	Filtering TextureFiltering // Sampling of the map when zoomed out, crisp or smooth
+	DeathPush DeathPush        // Whether the corpses of the monsters slide a short distance as they die
+	HitFlash  HitFlash         // Whether monsters and players flash when hit
+	Flashing  Flashing         // Accessibility limits of the full screen flashes and of the flashing effects
 }
 
 // Load loads a configuration object from disk
@@ -95,6 +118,15 @@ func (c *Configuration) Save() error {
 	return configFile.Close()
 }
 
+// DefaultLogFilePath returns the path of the log file in the user's config directory
+func DefaultLogFilePath() string {
+	if configDir, err := os.UserConfigDir(); err == nil {
+		return path.Join(configDir, "OpenDiablo2", "logs", "OpenDiablo2.log")
+	}
+
+	return path.Join(path.Dir(os.Args[0]), "OpenDiablo2.log")
+}
+
 func defaultConfigPath() string {
 	if configDir, err := os.UserConfigDir(); err == nil {
 		return path.Join(configDir, "OpenDiablo2", "config.json")
@@ -0,0 +1,41 @@
+package d2config
+
+import "fmt"
+
+// The modes of pushing the corpses of the monsters killed by the hero.
+const (
+	// DeathPushOff leaves the corpses where the monsters died, as Diablo II does.
+	DeathPushOff = "off"
+
+	// DeathPushFacing slides the corpses a short distance in the direction the monsters faced as they died.
+	DeathPushFacing = "facing"
+
+	// DeathPushAway slides the corpses a short distance away from the hero who dealt the killing blow.
+	DeathPushAway = "away"
+)
+
+// DeathPush holds whether the corpses of the monsters killed by the hero slide before settling.
+type DeathPush struct {
+	Mode string // DeathPushOff, DeathPushFacing or DeathPushAway
+}
+
+// GetMode returns the death push mode, off unless it was set to facing or away.
+func (d *DeathPush) GetMode() string {
+	if d.Mode == DeathPushFacing || d.Mode == DeathPushAway {
+		return d.Mode
+	}
+
+	return DeathPushOff
+}
+
+// SetMode changes the death push mode, which must be DeathPushOff, DeathPushFacing or DeathPushAway.
+func (d *DeathPush) SetMode(mode string) error {
+	if mode != DeathPushOff && mode != DeathPushFacing && mode != DeathPushAway {
+		return fmt.Errorf("unknown death push mode %s, expected %s, %s or %s", mode, DeathPushOff, DeathPushFacing,
+			DeathPushAway)
+	}
+
+	d.Mode = mode
+
+	return nil
+}
@@ -22,6 +22,30 @@ func defaultConfig() *Configuration {
 		BgmVolume:       defaultBgmVolume,
 		MpqPath:         "C:/Program Files (x86)/Diablo II",
 		Backend:         "Ebiten",
+		LogLevel:        "info",
+		LogFile:         DefaultLogFilePath(),
+		UIScale:         UIScale{HUD: 1, Menus: 1, Tooltips: 1},
+		Monsters:        MonsterScaling{Players: 1, Density: 1},
+		AutoPickup:      AutoPickup{Enabled: false, ItemTypes: DefaultAutoPickupTypes},
+		Loot:            Loot{Mode: LootModeFreeForAll, Allocation: DefaultLootAllocation},
+		EntityLimits:    EntityLimits{Throttle: SpawnThrottleQueue},
+		ItemLabels:      ItemLabels{Mode: ItemLabelsHold},
+		Simulation:      Simulation{ActiveRadius: DefaultActiveRadius},
+		Movement:        Movement{Mode: MovementClassic},
+		Filtering:       TextureFiltering{Mode: FilteringNearest},
+		DeathPush:       DeathPush{Mode: DeathPushOff},
+		HitFlash:        HitFlash{Disabled: false},
+		Flashing:        Flashing{NoScreenFlashes: false, Reduction: 0},
+		Ducking: AudioDucking{
+			Amount:  DefaultDuckingAmount,
+			Attack:  DefaultDuckingAttack,
+			Release: DefaultDuckingRelease,
+		},
+		Input: InputTiming{
+			DoubleClickInterval: DefaultDoubleClickInterval,
+			KeyRepeatDelay:      DefaultKeyRepeatDelay,
+			KeyRepeatInterval:   DefaultKeyRepeatInterval,
+		},
 		MpqLoadOrder: []string{
 			"Patch_D2.mpq",
 			"d2exp.mpq",
@@ -0,0 +1,87 @@
+package d2config
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// The default caps on the number of entities spawned while playing, on normal difficulty.
+const (
+	DefaultSpawnedEntityLimit  = 1024
+	DefaultSpawnedMonsterLimit = 256
+	DefaultSpawnedMissileLimit = 512
+)
+
+// What happens to entities spawned past a cap.
+const (
+	SpawnThrottleQueue         = "queue"         // The spawn waits until spawned entities are removed
+	SpawnThrottleDespawnOldest = "despawnoldest" // The oldest spawned entity is removed to make room
+)
+
+// Higher difficulties spawn larger packs and more minions, so they are allowed more entities, in percent.
+var difficultyLimitScale = map[d2enum.Difficulty]int{ //nolint:gochecknoglobals // constant lookup table
+	d2enum.DifficultyNormal:    100,
+	d2enum.DifficultyNightmare: 125,
+	d2enum.DifficultyHell:      150,
+}
+
+// EntityLimits holds the caps on the number of entities spawned while playing, such as summons, reanimated monsters
+// and missiles, protecting the game from runaway spawns. Entities placed with the level aren't counted. The caps are
+// for normal difficulty and scale up with it.
+type EntityLimits struct {
+	Total    int    // Cap on all spawned entities, 0 for DefaultSpawnedEntityLimit
+	Monsters int    // Cap on spawned monsters, 0 for DefaultSpawnedMonsterLimit
+	Missiles int    // Cap on spawned missiles, 0 for DefaultSpawnedMissileLimit
+	Throttle string // SpawnThrottleQueue or SpawnThrottleDespawnOldest
+}
+
+// GetTotal returns the cap on all spawned entities on the difficulty.
+func (l *EntityLimits) GetTotal(difficulty d2enum.Difficulty) int {
+	return scaleLimit(l.Total, DefaultSpawnedEntityLimit, difficulty)
+}
+
+// GetMonsters returns the cap on spawned monsters on the difficulty.
+func (l *EntityLimits) GetMonsters(difficulty d2enum.Difficulty) int {
+	return scaleLimit(l.Monsters, DefaultSpawnedMonsterLimit, difficulty)
+}
+
+// GetMissiles returns the cap on spawned missiles on the difficulty.
+func (l *EntityLimits) GetMissiles(difficulty d2enum.Difficulty) int {
+	return scaleLimit(l.Missiles, DefaultSpawnedMissileLimit, difficulty)
+}
+
+// GetThrottle returns what happens to entities spawned past a cap, queued unless set to despawn the oldest.
+func (l *EntityLimits) GetThrottle() string {
+	if l.Throttle == SpawnThrottleDespawnOldest {
+		return SpawnThrottleDespawnOldest
+	}
+
+	return SpawnThrottleQueue
+}
+
+// SetThrottle changes what happens to entities spawned past a cap, which must be SpawnThrottleQueue or
+// SpawnThrottleDespawnOldest.
+func (l *EntityLimits) SetThrottle(throttle string) error {
+	if throttle != SpawnThrottleQueue && throttle != SpawnThrottleDespawnOldest {
+		return fmt.Errorf("unknown spawn throttle %s, expected %s or %s", throttle, SpawnThrottleQueue,
+			SpawnThrottleDespawnOldest)
+	}
+
+	l.Throttle = throttle
+
+	return nil
+}
+
+func scaleLimit(limit, defaultLimit int, difficulty d2enum.Difficulty) int {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	scale, ok := difficultyLimitScale[difficulty]
+	if !ok {
+		scale = difficultyLimitScale[d2enum.DifficultyNormal]
+	}
+
+	return limit * scale / 100 //nolint:gomnd // percent
+}
@@ -0,0 +1,41 @@
+package d2config
+
+import "fmt"
+
+// Flashing holds the accessibility settings of the flashing effects, for players sensitive to flashing lights.
+type Flashing struct {
+	NoScreenFlashes bool    // Whether the full screen palette effects, such as being blinded, are turned off
+	Reduction       float64 // How much the flashing effects are toned down, from 0 for full strength to 1 for none
+}
+
+// GetIntensity returns the strength of the flashing effects, from 0 for none to 1 for full strength.
+func (f *Flashing) GetIntensity() float64 {
+	switch {
+	case f.Reduction <= 0:
+		return 1
+	case f.Reduction >= 1:
+		return 0
+	default:
+		return 1 - f.Reduction
+	}
+}
+
+// SetIntensity changes the strength of the flashing effects, which must be between 0 for none and 1 for full strength.
+func (f *Flashing) SetIntensity(intensity float64) error {
+	if intensity < 0 || intensity > 1 {
+		return fmt.Errorf("invalid flash intensity %v, expected a value between 0 and 1", intensity)
+	}
+
+	f.Reduction = 1 - intensity
+
+	return nil
+}
+
+// ScreenFlashIntensity returns the strength of the full screen palette effects, 0 if they are turned off.
+func (f *Flashing) ScreenFlashIntensity() float64 {
+	if f.NoScreenFlashes {
+		return 0
+	}
+
+	return f.GetIntensity()
+}
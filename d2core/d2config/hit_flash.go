@@ -0,0 +1,11 @@
+package d2config
+
+// HitFlash holds whether the monsters and players flash red for a few frames when hit.
+type HitFlash struct {
+	Disabled bool // Whether hits don't flash, for players sensitive to flashing lights
+}
+
+// IsEnabled returns true if hits flash.
+func (h *HitFlash) IsEnabled() bool {
+	return !h.Disabled
+}
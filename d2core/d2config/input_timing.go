@@ -0,0 +1,43 @@
+package d2config
+
+// The default timing of the input gestures, in seconds.
+const (
+	DefaultDoubleClickInterval = 0.5
+	DefaultKeyRepeatDelay      = 0.5
+	DefaultKeyRepeatInterval   = 0.05
+)
+
+// InputTiming holds the timing of the input gestures built on the raw input: double clicks and the repeats of held
+// keys.
+type InputTiming struct {
+	DoubleClickInterval float64 // Most seconds between the two clicks of a double click
+	KeyRepeatDelay      float64 // Seconds a key is held before it starts repeating
+	KeyRepeatInterval   float64 // Seconds between the repeats of a held key
+}
+
+// GetDoubleClickInterval returns the most seconds between the two clicks of a double click.
+func (t *InputTiming) GetDoubleClickInterval() float64 {
+	if t.DoubleClickInterval <= 0 {
+		return DefaultDoubleClickInterval
+	}
+
+	return t.DoubleClickInterval
+}
+
+// GetKeyRepeatDelay returns the seconds a key is held before it starts repeating.
+func (t *InputTiming) GetKeyRepeatDelay() float64 {
+	if t.KeyRepeatDelay <= 0 {
+		return DefaultKeyRepeatDelay
+	}
+
+	return t.KeyRepeatDelay
+}
+
+// GetKeyRepeatInterval returns the seconds between the repeats of a held key.
+func (t *InputTiming) GetKeyRepeatInterval() float64 {
+	if t.KeyRepeatInterval <= 0 {
+		return DefaultKeyRepeatInterval
+	}
+
+	return t.KeyRepeatInterval
+}
@@ -0,0 +1,34 @@
+package d2config
+
+import "fmt"
+
+// The modes of the key showing the labels of the items on the ground.
+const (
+	ItemLabelsHold   = "hold"   // The labels show while the key is held, as in Diablo II
+	ItemLabelsToggle = "toggle" // A press of the key shows the labels, the next one hides them
+)
+
+// ItemLabels holds how the labels of the items on the ground are shown with the Alt key.
+type ItemLabels struct {
+	Mode string // ItemLabelsHold or ItemLabelsToggle
+}
+
+// GetMode returns the mode of the key showing the labels, hold unless it was set to toggle.
+func (l *ItemLabels) GetMode() string {
+	if l.Mode == ItemLabelsToggle {
+		return ItemLabelsToggle
+	}
+
+	return ItemLabelsHold
+}
+
+// SetMode changes the mode of the key showing the labels, which must be ItemLabelsHold or ItemLabelsToggle.
+func (l *ItemLabels) SetMode(mode string) error {
+	if mode != ItemLabelsHold && mode != ItemLabelsToggle {
+		return fmt.Errorf("unknown item label mode %s, expected %s or %s", mode, ItemLabelsHold, ItemLabelsToggle)
+	}
+
+	l.Mode = mode
+
+	return nil
+}
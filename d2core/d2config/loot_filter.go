@@ -0,0 +1,162 @@
+package d2config
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"strings"
+)
+
+// Built-in loot filter presets
+const (
+	LootFilterDefault = "default" // Shows every item in the color of its quality
+	LootFilterMinimal = "minimal" // Hides low quality items and normal equipment
+	LootFilterStrict  = "strict"  // Only shows gold, runes, gems, and rare, set and unique items
+)
+
+// LootFilterPresets are the rules of the built-in loot filters.
+//nolint:gochecknoglobals // constant lookup table
+var LootFilterPresets = map[string][]LootRule{
+	LootFilterDefault: nil,
+	LootFilterMinimal: {
+		{Qualities: []string{"low"}, Hide: true},
+		{Categories: []string{"weapon", "armor"}, Qualities: []string{"normal"}, Hide: true},
+	},
+	LootFilterStrict: {
+		{ItemTypes: []string{"gold"}},
+		{ItemTypes: []string{"rune"}, Color: "#ffa800", Large: true},
+		{ItemTypes: []string{"gema", "gemd", "geme", "gemr", "gems", "gemt", "gemz"}},
+		{Qualities: []string{"unique", "set"}, Large: true},
+		{Qualities: []string{"rare"}},
+		{Hide: true},
+	},
+}
+
+// LootRule gives the labels of the ground items matching all of its criteria a style. Empty criteria match any item.
+type LootRule struct {
+	ItemTypes  []string // itemtypes.txt types or item codes, such as "rune" or "r01"
+	Categories []string // weapon, armor or misc
+	Qualities  []string // low, normal, superior, magic, set, rare, unique or crafted
+	MinLevel   int      // Lowest item level
+	MaxLevel   int      // Highest item level, 0 for no maximum
+	Color      string   // Label color as #rrggbb, the color of the item's quality if empty
+	Large      bool     // Draws the label with a larger font
+	Hide       bool     // Hides the label
+}
+
+// LootItem is what loot rules match items on
+type LootItem struct {
+	Codes    []string // The item code and its itemtypes.txt types
+	Category string
+	Quality  string
+	Level    int
+}
+
+// Matches returns true if the item matches all of the rule's criteria.
+func (r *LootRule) Matches(item *LootItem) bool {
+	if len(r.ItemTypes) > 0 && !containsAny(r.ItemTypes, item.Codes...) {
+		return false
+	}
+
+	if len(r.Categories) > 0 && !containsAny(r.Categories, item.Category) {
+		return false
+	}
+
+	if len(r.Qualities) > 0 && !containsAny(r.Qualities, item.Quality) {
+		return false
+	}
+
+	return item.Level >= r.MinLevel && (r.MaxLevel == 0 || item.Level <= r.MaxLevel)
+}
+
+// LabelColor returns the color of the rule, false if it has none or it isn't a valid #rrggbb color.
+func (r *LootRule) LabelColor() (color.Color, bool) {
+	var red, green, blue uint8
+
+	if _, err := fmt.Sscanf(r.Color, "#%02x%02x%02x", &red, &green, &blue); err != nil {
+		return nil, false
+	}
+
+	return color.RGBA{R: red, G: green, B: blue, A: 0xff}, true
+}
+
+func containsAny(list []string, values ...string) bool {
+	for _, entry := range list {
+		for _, value := range values {
+			if value != "" && strings.EqualFold(entry, value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// LootFilter holds the rules styling the labels of the items on the ground, loaded from lootfilter.json next to
+// config.json
+type LootFilter struct {
+	Preset string     // Built-in rules checked after the user's rules
+	Rules  []LootRule // The user's rules, checked in order
+}
+
+// Match returns the first rule matching the item, the user's rules first, nil if none does.
+func (f *LootFilter) Match(item *LootItem) *LootRule {
+	for _, rules := range [][]LootRule{f.Rules, LootFilterPresets[f.Preset]} {
+		for idx := range rules {
+			if rules[idx].Matches(item) {
+				return &rules[idx]
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadLootFilter reads the loot filter from lootfilter.json, which is created with the default preset if it doesn't
+// exist yet.
+func LoadLootFilter() (*LootFilter, error) {
+	filter := &LootFilter{Preset: LootFilterDefault}
+
+	data, err := ioutil.ReadFile(lootFilterPath())
+	if os.IsNotExist(err) {
+		log.Println("no loot filter found, saving the default loot filter...")
+		return filter, filter.Save()
+	}
+
+	if err != nil {
+		return filter, err
+	}
+
+	if err := json.Unmarshal(data, filter); err != nil {
+		return &LootFilter{Preset: LootFilterDefault}, err
+	}
+
+	if _, found := LootFilterPresets[filter.Preset]; !found && filter.Preset != "" {
+		return filter, fmt.Errorf("unknown loot filter preset %s", filter.Preset)
+	}
+
+	return filter, nil
+}
+
+// Save writes the loot filter to lootfilter.json.
+func (f *LootFilter) Save() error {
+	buf, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	filterPath := lootFilterPath()
+	if err := os.MkdirAll(path.Dir(filterPath), 0750); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filterPath, buf, 0600)
+}
+
+func lootFilterPath() string {
+	return path.Join(path.Dir(defaultConfigPath()), "lootfilter.json")
+}
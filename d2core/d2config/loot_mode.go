@@ -0,0 +1,48 @@
+package d2config
+
+import "fmt"
+
+// The loot modes of multiplayer games.
+const (
+	LootModeFreeForAll = "freeforall" // Anyone can pick up any drop
+	LootModeAllocated  = "allocated"  // Drops belong to the player they dropped for until the allocation ends
+)
+
+// DefaultLootAllocation is the number of seconds allocated drops belong to their owner.
+const DefaultLootAllocation = 10
+
+// Loot holds the settings of who can pick up the drops in multiplayer games, read by the host's server.
+type Loot struct {
+	Mode       string  // LootModeFreeForAll or LootModeAllocated
+	Allocation float64 // Seconds allocated drops belong to their owner, 0 for DefaultLootAllocation
+	SplitGold  bool    // Gold picked up is shared evenly among the players in the game
+}
+
+// GetMode returns the loot mode, free-for-all unless it was set to allocated.
+func (l *Loot) GetMode() string {
+	if l.Mode == LootModeAllocated {
+		return LootModeAllocated
+	}
+
+	return LootModeFreeForAll
+}
+
+// SetMode changes the loot mode, which must be LootModeFreeForAll or LootModeAllocated.
+func (l *Loot) SetMode(mode string) error {
+	if mode != LootModeFreeForAll && mode != LootModeAllocated {
+		return fmt.Errorf("unknown loot mode %s, expected %s or %s", mode, LootModeFreeForAll, LootModeAllocated)
+	}
+
+	l.Mode = mode
+
+	return nil
+}
+
+// GetAllocation returns the number of seconds allocated drops belong to their owner.
+func (l *Loot) GetAllocation() float64 {
+	if l.Allocation <= 0 {
+		return DefaultLootAllocation
+	}
+
+	return l.Allocation
+}
@@ -0,0 +1,64 @@
+package d2config
+
+import "fmt"
+
+// The range of the players count and the monster density multiplier.
+const (
+	MinPlayers        = 1
+	MaxPlayers        = 8
+	MinMonsterDensity = 0.25
+	MaxMonsterDensity = 4.0
+)
+
+// MonsterScaling holds the settings monsters are spawned with, taking effect on the next level load. The defaults are
+// those of a solo game.
+type MonsterScaling struct {
+	Players int     // Number of players the monster life, experience and drops are scaled for, as with /players
+	Density float64 // Multiplier of the number of monsters spawned
+}
+
+// GetPlayers returns the number of players monsters are scaled for.
+func (s *MonsterScaling) GetPlayers() int {
+	if s.Players < MinPlayers {
+		return MinPlayers
+	}
+
+	return s.Players
+}
+
+// SetPlayers changes the number of players monsters are scaled for, clamped between MinPlayers and MaxPlayers, and
+// returns the number set.
+func (s *MonsterScaling) SetPlayers(players int) int {
+	switch {
+	case players < MinPlayers:
+		players = MinPlayers
+	case players > MaxPlayers:
+		players = MaxPlayers
+	}
+
+	s.Players = players
+
+	return players
+}
+
+// GetDensity returns the multiplier of the number of monsters spawned.
+func (s *MonsterScaling) GetDensity() float64 {
+	if s.Density <= 0 {
+		return 1
+	}
+
+	return s.Density
+}
+
+// SetDensity changes the multiplier of the number of monsters spawned, which must be between MinMonsterDensity and
+// MaxMonsterDensity.
+func (s *MonsterScaling) SetDensity(density float64) error {
+	if density < MinMonsterDensity || density > MaxMonsterDensity {
+		return fmt.Errorf("monster density %v is out of range, expected %v to %v", density, MinMonsterDensity,
+			MaxMonsterDensity)
+	}
+
+	s.Density = density
+
+	return nil
+}
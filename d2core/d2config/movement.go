@@ -0,0 +1,40 @@
+package d2config
+
+import "fmt"
+
+// The modes of click-to-move movement.
+const (
+	// MovementClassic moves the hero like Diablo II does: while the button is held, the destination is sent four
+	// times a second, and clicking an obstacle walks the hero as close to it as the path gets before stopping.
+	MovementClassic = "classic"
+
+	// MovementResponsive sends the destination twenty times a second while the button is held, so the hero turns
+	// with the cursor right away, and clicking an obstacle walks the hero to the walkable spot nearest to the click.
+	MovementResponsive = "responsive"
+)
+
+// Movement holds how the hero moves when the player clicks the map.
+type Movement struct {
+	Mode        string // MovementClassic or MovementResponsive
+	WallSliding bool   // Whether moving the hero directly into a wall slides along it rather than stopping
+}
+
+// GetMode returns the movement mode, classic unless it was set to responsive.
+func (m *Movement) GetMode() string {
+	if m.Mode == MovementResponsive {
+		return MovementResponsive
+	}
+
+	return MovementClassic
+}
+
+// SetMode changes the movement mode, which must be MovementClassic or MovementResponsive.
+func (m *Movement) SetMode(mode string) error {
+	if mode != MovementClassic && mode != MovementResponsive {
+		return fmt.Errorf("unknown movement mode %s, expected %s or %s", mode, MovementClassic, MovementResponsive)
+	}
+
+	m.Mode = mode
+
+	return nil
+}
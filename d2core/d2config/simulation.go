@@ -0,0 +1,21 @@
+package d2config
+
+// DefaultActiveRadius is how far from the players, in tiles, monsters think and move by default.
+const DefaultActiveRadius = 40.0
+
+// Simulation holds how much of the map is simulated.
+type Simulation struct {
+	// ActiveRadius is how far from the players, in tiles, monsters think and move. A smaller radius saves CPU on
+	// crowded levels, a larger one keeps monsters off screen acting as they would on screen. Monsters on screen
+	// always act, whatever the radius.
+	ActiveRadius float64
+}
+
+// GetActiveRadius returns how far from the players, in tiles, monsters think and move.
+func (s *Simulation) GetActiveRadius() float64 {
+	if s.ActiveRadius <= 0 {
+		return DefaultActiveRadius
+	}
+
+	return s.ActiveRadius
+}
@@ -0,0 +1,51 @@
+package d2config
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// The modes of sampling the tiles and sprites of the map when the camera is zoomed out.
+const (
+	// FilteringNearest keeps the crisp nearest-neighbor sampling of the original game at every zoom.
+	FilteringNearest = "nearest"
+
+	// FilteringLinear samples the map with mipmapped bilinear filtering below 1x zoom, which keeps the tiles from
+	// shimmering as the camera moves. At 1x and above the map stays crisp.
+	FilteringLinear = "linear"
+)
+
+// TextureFiltering holds how the map is sampled when zoomed out.
+type TextureFiltering struct {
+	Mode string // FilteringNearest or FilteringLinear
+}
+
+// GetMode returns the filtering mode, nearest unless it was set to linear.
+func (f *TextureFiltering) GetMode() string {
+	if f.Mode == FilteringLinear {
+		return FilteringLinear
+	}
+
+	return FilteringNearest
+}
+
+// SetMode changes the filtering mode, which must be FilteringNearest or FilteringLinear.
+func (f *TextureFiltering) SetMode(mode string) error {
+	if mode != FilteringNearest && mode != FilteringLinear {
+		return fmt.Errorf("unknown filtering mode %s, expected %s or %s", mode, FilteringNearest, FilteringLinear)
+	}
+
+	f.Mode = mode
+
+	return nil
+}
+
+// ZoomedOutFilter returns the filter of the map below 1x zoom.
+func (f *TextureFiltering) ZoomedOutFilter() d2enum.Filter {
+	if f.GetMode() == FilteringLinear {
+		return d2enum.FilterLinear
+	}
+
+	return d2enum.FilterNearest
+}
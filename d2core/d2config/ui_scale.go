@@ -0,0 +1,74 @@
+package d2config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The range of the UI scale factors, 1 is the original size.
+const (
+	MinUIScale = 0.5
+	MaxUIScale = 3.0
+)
+
+// UIScaleGroup is a group of user interface elements sharing a scale factor
+type UIScaleGroup int
+
+// UI element groups
+const (
+	UIScaleHUD      UIScaleGroup = iota // the bottom bar with the globes and skills
+	UIScaleMenus                        // the inventory, character, quest and skill panels
+	UIScaleTooltips                     // the hover descriptions of items, stats and skills
+)
+
+// UIScale holds the scale factor of each group of user interface elements.
+type UIScale struct {
+	HUD      float64
+	Menus    float64
+	Tooltips float64
+}
+
+// ParseUIScaleGroup returns the group with the given name: hud, menus or tooltips.
+func ParseUIScaleGroup(name string) (UIScaleGroup, error) {
+	switch strings.ToLower(name) {
+	case "hud":
+		return UIScaleHUD, nil
+	case "menus":
+		return UIScaleMenus, nil
+	case "tooltips":
+		return UIScaleTooltips, nil
+	}
+
+	return 0, fmt.Errorf("unknown UI scale group %q, expected hud, menus or tooltips", name)
+}
+
+// Get returns the scale factor of the group.
+func (s *UIScale) Get(group UIScaleGroup) float64 {
+	if factor := *s.factor(group); factor > 0 {
+		return factor
+	}
+
+	return 1
+}
+
+// Set changes the scale factor of the group, which must be between MinUIScale and MaxUIScale.
+func (s *UIScale) Set(group UIScaleGroup, factor float64) error {
+	if factor < MinUIScale || factor > MaxUIScale {
+		return fmt.Errorf("UI scale %v is out of range, expected %v to %v", factor, MinUIScale, MaxUIScale)
+	}
+
+	*s.factor(group) = factor
+
+	return nil
+}
+
+func (s *UIScale) factor(group UIScaleGroup) *float64 {
+	switch group {
+	case UIScaleMenus:
+		return &s.Menus
+	case UIScaleTooltips:
+		return &s.Tooltips
+	default:
+		return &s.HUD
+	}
+}
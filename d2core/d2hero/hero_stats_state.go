@@ -15,6 +15,9 @@ type HeroStatsState struct {
 	Strength  int `json:"strength"`
 	Dexterity int `json:"dexterity"`
 
+	StatPoints int `json:"statPoints"` // unspent attribute points
+	Gold       int `json:"gold"`       // gold carried in the inventory
+
 	AttackRating  int `json:"attackRating"`
 	DefenseRating int `json:"defenseRating"`
 
@@ -32,6 +35,16 @@ type HeroStatsState struct {
 	// values which are not saved/loaded(computed)
 	Stamina      int // only MaxStamina is saved, Stamina gets reset on entering world
 	NextLevelExp int
+	LifeSteal    int // percent of the physical damage dealt stolen as life, from items
+	ManaSteal    int // percent of the physical damage dealt stolen as mana, from items
+	LifeRegen    int // "Replenish Life" from items, in 256ths of life per frame
+	ManaRegen    int // "Regenerate Mana" percent bonus from items
+	CrushingBlow int // percent chance of crushing blow, from items
+	OpenWounds   int // percent chance of open wounds, from items
+	AttackSpeed  int // "Increased Attack Speed" percent, from items
+	CastSpeed    int // "Faster Cast Rate" percent, from items
+	FreezeLength int // "Freezes Target" length in frames, from items
+	HitRecovery  int // "Faster Hit Recovery" percent, from items
 }
 
 // CreateHeroStatsState generates a running state from a hero stats.
@@ -0,0 +1,113 @@
+package d2hero
+
+import "fmt"
+
+// QuestStatus is the progress of the hero in a quest
+type QuestStatus int
+
+const (
+	// QuestNotStarted quests aren't shown in the quest log
+	QuestNotStarted QuestStatus = iota
+
+	// QuestActive quests have been given to the hero and aren't done yet
+	QuestActive
+
+	// QuestCompleted quests are done
+	QuestCompleted
+)
+
+// NumActs is the number of acts in the game.
+const NumActs = 5
+
+// questsPerAct is the number of quests in each act, act IV only has three.
+//nolint:gochecknoglobals // constant lookup table
+var questsPerAct = [NumActs]int{6, 6, 6, 3, 6}
+
+// QuestState is a serializable state of the hero's progress in one quest.
+type QuestState struct {
+	Act       int         `json:"act"`   // 1 based act number
+	Quest     int         `json:"quest"` // 1 based quest number within the act
+	Status    QuestStatus `json:"status"`
+	Objective int         `json:"objective"` // current step of the quest, indexing its objective strings
+}
+
+// TitleKey returns the string table key of the quest name.
+func (q *QuestState) TitleKey() string {
+	return fmt.Sprintf("qstsa%dq%d", q.Act, q.Quest)
+}
+
+// ObjectiveKey returns the string table key of the text describing the current objective of the quest.
+func (q *QuestState) ObjectiveKey() string {
+	return fmt.Sprintf("qstsa%dq%d%d", q.Act, q.Quest, q.Objective)
+}
+
+// QuestLog is a serializable state of the hero's progress in every quest.
+type QuestLog struct {
+	Quests []QuestState `json:"quests"`
+}
+
+// NewQuestLog creates a quest log with every quest of the game not started.
+func NewQuestLog() *QuestLog {
+	log := &QuestLog{}
+
+	for act := 1; act <= NumActs; act++ {
+		for quest := 1; quest <= questsPerAct[act-1]; quest++ {
+			log.Quests = append(log.Quests, QuestState{Act: act, Quest: quest})
+		}
+	}
+
+	return log
+}
+
+// Quest returns the state of the given quest, or nil if there is no such quest.
+func (l *QuestLog) Quest(act, quest int) *QuestState {
+	for i := range l.Quests {
+		if l.Quests[i].Act == act && l.Quests[i].Quest == quest {
+			return &l.Quests[i]
+		}
+	}
+
+	return nil
+}
+
+// SetObjective starts the quest if needed and moves it to the given objective. Completed quests are left alone.
+func (l *QuestLog) SetObjective(act, quest, objective int) {
+	state := l.Quest(act, quest)
+	if state == nil || state.Status == QuestCompleted {
+		return
+	}
+
+	state.Status = QuestActive
+	state.Objective = objective
+}
+
+// Complete marks the quest as done.
+func (l *QuestLog) Complete(act, quest int) {
+	if state := l.Quest(act, quest); state != nil {
+		state.Status = QuestCompleted
+	}
+}
+
+// Visible returns the quests the hero has started or completed, in act order.
+func (l *QuestLog) Visible() []*QuestState {
+	var visible []*QuestState
+
+	for i := range l.Quests {
+		if l.Quests[i].Status != QuestNotStarted {
+			visible = append(visible, &l.Quests[i])
+		}
+	}
+
+	return visible
+}
+
+// ActCompleted returns true if the last quest of the act, which opens the way to the next act, is done.
+func (l *QuestLog) ActCompleted(act int) bool {
+	if act < 1 || act > NumActs {
+		return false
+	}
+
+	state := l.Quest(act, questsPerAct[act-1])
+
+	return state != nil && state.Status == QuestCompleted
+}
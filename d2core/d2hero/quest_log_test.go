@@ -0,0 +1,53 @@
+package d2hero
+
+import "testing"
+
+func TestQuestLogVisibleQuests(t *testing.T) {
+	log := NewQuestLog()
+
+	if len(log.Visible()) != 0 {
+		t.Fatal("a new quest log should not show any quests")
+	}
+
+	log.SetObjective(1, 2, 3)
+	log.Complete(4, 1)
+	log.SetObjective(4, 1, 2)
+
+	visible := log.Visible()
+	if len(visible) != 2 {
+		t.Fatalf("expected 2 visible quests, got %d", len(visible))
+	}
+
+	if visible[0].ObjectiveKey() != "qstsa1q23" {
+		t.Errorf("unexpected objective key %s", visible[0].ObjectiveKey())
+	}
+
+	if visible[1].Status != QuestCompleted || visible[1].Objective != 0 {
+		t.Error("a completed quest should not be restarted")
+	}
+
+	if log.Quest(4, 4) != nil {
+		t.Error("act IV only has three quests")
+	}
+}
+
+func TestQuestLogActCompleted(t *testing.T) {
+	log := NewQuestLog()
+
+	log.Complete(1, 5)
+
+	if log.ActCompleted(1) {
+		t.Error("act I isn't completed until its last quest is")
+	}
+
+	log.Complete(1, 6)
+	log.Complete(4, 3)
+
+	if !log.ActCompleted(1) || !log.ActCompleted(4) {
+		t.Error("an act is completed with its last quest")
+	}
+
+	if log.ActCompleted(0) || log.ActCompleted(NumActs+1) {
+		t.Error("there is no such act")
+	}
+}
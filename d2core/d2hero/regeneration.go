@@ -0,0 +1,29 @@
+package d2hero
+
+const (
+	// manaRegenSeconds is the time it takes to regenerate all mana without bonuses, as in Diablo II.
+	manaRegenSeconds = 120
+
+	// lifeRegenUnitsPerSecond converts LifeRegen, in 256ths of life per frame at 25 frames per second, to life per
+	// second.
+	lifeRegenUnitsPerSecond = 25.0 / 256.0
+)
+
+// LifeRegenRate returns the life regenerated per second. Heroes don't regenerate life by themselves, only with items.
+func (s *HeroStatsState) LifeRegenRate() float64 {
+	if s.LifeRegen <= 0 {
+		return 0
+	}
+
+	return float64(s.LifeRegen) * lifeRegenUnitsPerSecond
+}
+
+// ManaRegenRate returns the mana regenerated per second. All mana comes back in 120 seconds, faster with the
+// ManaRegen percent bonus.
+func (s *HeroStatsState) ManaRegenRate() float64 {
+	if s.MaxMana <= 0 {
+		return 0
+	}
+
+	return float64(s.MaxMana) / manaRegenSeconds * float64(100+s.ManaRegen) / 100 //nolint:gomnd // percent
+}
@@ -0,0 +1,29 @@
+package d2hero
+
+import (
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestManaRegenRate(t *testing.T) {
+	assert := testify.New(t)
+
+	stats := HeroStatsState{MaxMana: 240}
+	assert.Equal(2.0, stats.ManaRegenRate())
+
+	stats.ManaRegen = 50
+	assert.Equal(3.0, stats.ManaRegenRate())
+
+	assert.Zero((&HeroStatsState{}).ManaRegenRate())
+}
+
+func TestLifeRegenRate(t *testing.T) {
+	assert := testify.New(t)
+
+	stats := HeroStatsState{MaxHealth: 100}
+	assert.Zero(stats.LifeRegenRate(), "heroes only regenerate life with items")
+
+	stats.LifeRegen = 512
+	assert.Equal(50.0, stats.LifeRegenRate())
+}
@@ -0,0 +1,63 @@
+package d2hero
+
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+)
+
+// sharedCastDelayGroup is the cooldown group of every skill with a cast delay. Like Diablo II, using one of them
+// puts all of them on cooldown.
+const sharedCastDelayGroup = "castdelay"
+
+// SkillCooldowns tracks the remaining cooldown of each cooldown group.
+type SkillCooldowns struct {
+	remaining map[string]float64
+}
+
+// NewSkillCooldowns creates a cooldown tracker with nothing on cooldown.
+func NewSkillCooldowns() *SkillCooldowns {
+	return &SkillCooldowns{remaining: make(map[string]float64)}
+}
+
+// SkillCooldownGroup returns the cooldown group of the skill, or an empty string if the skill has no cast delay.
+func SkillCooldownGroup(skill *d2datadict.SkillRecord) string {
+	if skill.Cooldown() <= 0 {
+		return ""
+	}
+
+	return sharedCastDelayGroup
+}
+
+// Start puts the skill's cooldown group on cooldown for the skill's cast delay. A shorter delay never cuts the
+// cooldown of the group short.
+func (c *SkillCooldowns) Start(skill *d2datadict.SkillRecord) {
+	group := SkillCooldownGroup(skill)
+	if group == "" {
+		return
+	}
+
+	c.remaining[group] = math.Max(c.remaining[group], skill.Cooldown())
+}
+
+// Remaining returns the seconds left until the skill can be used again.
+func (c *SkillCooldowns) Remaining(skill *d2datadict.SkillRecord) float64 {
+	return c.remaining[SkillCooldownGroup(skill)]
+}
+
+// IsReady returns true if the skill isn't on cooldown.
+func (c *SkillCooldowns) IsReady(skill *d2datadict.SkillRecord) bool {
+	return c.Remaining(skill) <= 0
+}
+
+// Advance counts down all cooldowns by the elapsed seconds.
+func (c *SkillCooldowns) Advance(elapsed float64) {
+	for group, remaining := range c.remaining {
+		if remaining <= elapsed {
+			delete(c.remaining, group)
+			continue
+		}
+
+		c.remaining[group] = remaining - elapsed
+	}
+}
@@ -0,0 +1,49 @@
+package d2hero
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+)
+
+func TestSkillCooldownsShareCastDelay(t *testing.T) {
+	meteor := &d2datadict.SkillRecord{Skill: "Meteor", Delay: 25}
+	blizzard := &d2datadict.SkillRecord{Skill: "Blizzard", Delay: 50}
+	fireBolt := &d2datadict.SkillRecord{Skill: "Fire Bolt"}
+
+	cooldowns := NewSkillCooldowns()
+	cooldowns.Start(blizzard)
+	cooldowns.Start(meteor)
+
+	if cooldowns.IsReady(meteor) {
+		t.Error("a skill with a cast delay should be on cooldown after another one is used")
+	}
+
+	if !cooldowns.IsReady(fireBolt) {
+		t.Error("a skill without a cast delay should never be on cooldown")
+	}
+
+	cooldowns.Advance(1.5)
+
+	if remaining := cooldowns.Remaining(meteor); remaining != 0.5 {
+		t.Errorf("expected the longest delay to be kept, got %f seconds remaining", remaining)
+	}
+
+	cooldowns.Advance(0.5)
+
+	if !cooldowns.IsReady(blizzard) {
+		t.Error("the cooldown should have worn off")
+	}
+}
+
+func TestSkillManaCost(t *testing.T) {
+	skill := &d2datadict.SkillRecord{Mana: 3, Lvlmana: 1, Manashift: 8, Minmana: 4}
+
+	if cost := skill.ManaCost(1); cost != 4 {
+		t.Errorf("expected the minimum mana cost, got %d", cost)
+	}
+
+	if cost := skill.ManaCost(3); cost != 5 {
+		t.Errorf("expected a mana cost of 5 at level 3, got %d", cost)
+	}
+}
@@ -0,0 +1,133 @@
+package d2hero
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// NumSkillTabs is the number of skill tree tabs of every class.
+const NumSkillTabs = 3
+
+// Reasons a skill point can't be spent on a skill.
+var (
+	ErrNoSkillPoints       = errors.New("no skill points left")
+	ErrSkillMaxLevel       = errors.New("skill is at its maximum level")
+	ErrSkillLevelTooLow    = errors.New("character level too low")
+	ErrSkillMissingPrereqs = errors.New("required skills not learned")
+	ErrSkillNotInTree      = errors.New("skill is not in the class skill tree")
+)
+
+// classSkillTokens are the charclass values of skills.txt for each hero class.
+//nolint:gochecknoglobals // constant lookup table
+var classSkillTokens = map[d2enum.Hero]string{
+	d2enum.HeroAmazon:      "ama",
+	d2enum.HeroAssassin:    "ass",
+	d2enum.HeroBarbarian:   "bar",
+	d2enum.HeroDruid:       "dru",
+	d2enum.HeroNecromancer: "nec",
+	d2enum.HeroPaladin:     "pal",
+	d2enum.HeroSorceress:   "sor",
+}
+
+// SkillTree is a serializable state of the skill points a hero spent on their class skills.
+type SkillTree struct {
+	Class       d2enum.Hero `json:"class"`
+	SkillPoints int         `json:"skillPoints"` // unspent skill points
+	Levels      map[int]int `json:"levels"`      // skill level by skills.txt Id
+}
+
+// NewSkillTree creates a skill tree without any skills learned.
+func NewSkillTree(class d2enum.Hero) *SkillTree {
+	return &SkillTree{Class: class, Levels: make(map[int]int)}
+}
+
+// Skills returns the class skills shown in the given skill tree tab, 1 to 3, sorted by row and column.
+func (t *SkillTree) Skills(tab int) []*d2datadict.SkillRecord {
+	var skills []*d2datadict.SkillRecord
+
+	for _, skill := range d2datadict.SkillDetails {
+		if skill.Charclass != classSkillTokens[t.Class] {
+			continue
+		}
+
+		if desc := SkillDescription(skill); desc != nil && desc.SkillPage == tab {
+			skills = append(skills, skill)
+		}
+	}
+
+	sort.Slice(skills, func(i, j int) bool {
+		a, b := SkillDescription(skills[i]), SkillDescription(skills[j])
+		if a.SkillRow != b.SkillRow {
+			return a.SkillRow < b.SkillRow
+		}
+
+		return a.SkillColumn < b.SkillColumn
+	})
+
+	return skills
+}
+
+// SkillDescription returns the skill tree description of the skill, or nil if it has none.
+func SkillDescription(skill *d2datadict.SkillRecord) *d2datadict.SkillDescriptionRecord {
+	return d2datadict.SkillDescriptions[skill.Skilldesc]
+}
+
+// Level returns the level of the skill, 0 if it isn't learned.
+func (t *SkillTree) Level(skill *d2datadict.SkillRecord) int {
+	return t.Levels[skill.ID]
+}
+
+// CanAllocate returns nil if a skill point can be spent on the skill by a hero of the given level, or the reason it
+// can't: the skill must be a class skill below its maximum level, the hero must meet its level requirement and have
+// learned its required skills.
+func (t *SkillTree) CanAllocate(skill *d2datadict.SkillRecord, heroLevel int) error {
+	if t.SkillPoints <= 0 {
+		return ErrNoSkillPoints
+	}
+
+	if skill.Charclass != classSkillTokens[t.Class] {
+		return ErrSkillNotInTree
+	}
+
+	if skill.Maxlvl > 0 && t.Level(skill) >= skill.Maxlvl {
+		return ErrSkillMaxLevel
+	}
+
+	if heroLevel < skill.Reqlevel {
+		return ErrSkillLevelTooLow
+	}
+
+	for _, required := range []string{skill.Reqskill1, skill.Reqskill2, skill.Reqskill3} {
+		if required != "" && !t.hasLearned(required) {
+			return ErrSkillMissingPrereqs
+		}
+	}
+
+	return nil
+}
+
+// Allocate spends a skill point on the skill, returning the reason if it can't be done.
+func (t *SkillTree) Allocate(skill *d2datadict.SkillRecord, heroLevel int) error {
+	if err := t.CanAllocate(skill, heroLevel); err != nil {
+		return err
+	}
+
+	t.Levels[skill.ID]++
+	t.SkillPoints--
+
+	return nil
+}
+
+// hasLearned returns true if at least one point has been spent on the skill with the given skills.txt name.
+func (t *SkillTree) hasLearned(skillName string) bool {
+	for id, level := range t.Levels {
+		if skill := d2datadict.SkillDetails[id]; skill != nil && skill.Skill == skillName && level > 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,43 @@
+package d2hero
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+func TestSkillTreeAllocate(t *testing.T) {
+	fireBolt := &d2datadict.SkillRecord{ID: 36, Skill: "Fire Bolt", Charclass: "sor", Maxlvl: 20, Reqlevel: 1}
+	fireBall := &d2datadict.SkillRecord{ID: 47, Skill: "Fire Ball", Charclass: "sor", Maxlvl: 20, Reqlevel: 12,
+		Reqskill1: "Fire Bolt"}
+	d2datadict.SkillDetails = map[int]*d2datadict.SkillRecord{fireBolt.ID: fireBolt, fireBall.ID: fireBall}
+
+	tree := NewSkillTree(d2enum.HeroSorceress)
+
+	if err := tree.Allocate(fireBolt, 1); err != ErrNoSkillPoints {
+		t.Errorf("expected no skill points error, got %v", err)
+	}
+
+	tree.SkillPoints = 2
+
+	if err := tree.Allocate(fireBall, 12); err != ErrSkillMissingPrereqs {
+		t.Errorf("expected missing prerequisites error, got %v", err)
+	}
+
+	if err := tree.Allocate(fireBolt, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tree.Allocate(fireBall, 11); err != ErrSkillLevelTooLow {
+		t.Errorf("expected level too low error, got %v", err)
+	}
+
+	if err := tree.Allocate(fireBall, 12); err != nil {
+		t.Fatal(err)
+	}
+
+	if tree.Level(fireBolt) != 1 || tree.Level(fireBall) != 1 || tree.SkillPoints != 0 {
+		t.Error("skill points were not spent")
+	}
+}
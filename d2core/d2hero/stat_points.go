@@ -0,0 +1,119 @@
+package d2hero
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+)
+
+// HeroAttribute is one of the four attributes stat points can be spent on
+type HeroAttribute int
+
+const (
+	// AttributeStrength is the strength attribute
+	AttributeStrength HeroAttribute = iota
+	// AttributeDexterity is the dexterity attribute
+	AttributeDexterity
+	// AttributeVitality is the vitality attribute
+	AttributeVitality
+	// AttributeEnergy is the energy attribute
+	AttributeEnergy
+)
+
+// StatContribution is one source of a stat's value, such as the class base value or allocated points.
+type StatContribution struct {
+	Source string
+	Value  int
+}
+
+// AllocateStatPoint spends one unspent stat point on the attribute. Vitality raises life and stamina, and energy
+// raises mana, by the class' charstats.txt rates. Returns false if there are no stat points left.
+func (s *HeroStatsState) AllocateStatPoint(attribute HeroAttribute, classStats *d2datadict.CharStatsRecord) bool {
+	if s.StatPoints <= 0 {
+		return false
+	}
+
+	switch attribute {
+	case AttributeStrength:
+		s.Strength++
+	case AttributeDexterity:
+		s.Dexterity++
+	case AttributeVitality:
+		s.Vitality++
+		s.MaxHealth += classStats.LifePerVit
+		s.Health += classStats.LifePerVit
+		s.MaxStamina += classStats.StaminaPerVit
+		s.Stamina += classStats.StaminaPerVit
+	case AttributeEnergy:
+		s.Energy++
+		s.MaxMana += classStats.ManaPerEne
+		s.Mana += classStats.ManaPerEne
+	default:
+		return false
+	}
+
+	s.StatPoints--
+
+	return true
+}
+
+// Attribute returns the current value of the attribute.
+func (s *HeroStatsState) Attribute(attribute HeroAttribute) int {
+	switch attribute {
+	case AttributeStrength:
+		return s.Strength
+	case AttributeDexterity:
+		return s.Dexterity
+	case AttributeVitality:
+		return s.Vitality
+	case AttributeEnergy:
+		return s.Energy
+	default:
+		return 0
+	}
+}
+
+// AttributeBreakdown returns where the value of the attribute comes from: the class base value and the points spent on
+// it. Items and skills don't modify attributes yet.
+func (s *HeroStatsState) AttributeBreakdown(attribute HeroAttribute,
+	classStats *d2datadict.CharStatsRecord) []StatContribution {
+	base := 0
+
+	switch attribute {
+	case AttributeStrength:
+		base = classStats.InitStr
+	case AttributeDexterity:
+		base = classStats.InitDex
+	case AttributeVitality:
+		base = classStats.InitVit
+	case AttributeEnergy:
+		base = classStats.InitEne
+	}
+
+	return []StatContribution{
+		{Source: "Base", Value: base},
+		{Source: "Stat points", Value: s.Attribute(attribute) - base},
+	}
+}
+
+// LifeBreakdown returns where the maximum life comes from: the class base life, vitality and everything else.
+func (s *HeroStatsState) LifeBreakdown(classStats *d2datadict.CharStatsRecord) []StatContribution {
+	base := classStats.InitVit * classStats.LifePerVit
+	vitality := (s.Vitality - classStats.InitVit) * classStats.LifePerVit
+
+	return []StatContribution{
+		{Source: "Base", Value: base},
+		{Source: "Vitality", Value: vitality},
+		{Source: "Other", Value: s.MaxHealth - base - vitality},
+	}
+}
+
+// ManaBreakdown returns where the maximum mana comes from: the class base mana, energy and everything else.
+func (s *HeroStatsState) ManaBreakdown(classStats *d2datadict.CharStatsRecord) []StatContribution {
+	base := classStats.InitEne * classStats.ManaPerEne
+	energy := (s.Energy - classStats.InitEne) * classStats.ManaPerEne
+
+	return []StatContribution{
+		{Source: "Base", Value: base},
+		{Source: "Energy", Value: energy},
+		{Source: "Other", Value: s.MaxMana - base - energy},
+	}
+}
@@ -0,0 +1,103 @@
+package d2input
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+)
+
+// doubleClickDistance is how far, in pixels, the cursor can move between the two clicks of a double click.
+const doubleClickDistance = 4
+
+// buttonClick is a press of a mouse button, kept to detect double clicks.
+type buttonClick struct {
+	time float64
+	x, y int
+}
+
+// inputTiming returns the configured timing of double clicks and repeated keys.
+func inputTiming() *d2config.InputTiming {
+	if d2config.Config == nil {
+		return &d2config.InputTiming{}
+	}
+
+	return &d2config.Config.Input
+}
+
+// updateDoubleClick sends a double click event if the button was pressed a second time within the double click
+// interval, close enough to the first press. A third press starts a new double click.
+func (im *inputManager) updateDoubleClick(b d2enum.MouseButton, e HandlerEvent) {
+	if im.clicks == nil {
+		im.clicks = make(map[d2enum.MouseButton]buttonClick)
+	}
+
+	last, ok := im.clicks[b]
+	near := abs(e.x-last.x) <= doubleClickDistance && abs(e.y-last.y) <= doubleClickDistance
+
+	if !ok || !near || im.now-last.time > inputTiming().GetDoubleClickInterval() {
+		im.clicks[b] = buttonClick{time: im.now, x: e.x, y: e.y}
+		return
+	}
+
+	delete(im.clicks, b)
+
+	event := MouseEvent{e, b}
+
+	fn := func(handler d2interface.InputEventHandler) bool {
+		if l, ok := handler.(d2interface.MouseButtonDoubleClickHandler); ok {
+			return l.OnMouseButtonDoubleClick(&event)
+		}
+
+		return false
+	}
+	im.propagate(fn)
+}
+
+// startKeyRepeat schedules the first repeat of a key just pressed.
+func (im *inputManager) startKeyRepeat(k d2enum.Key) {
+	if im.keyRepeats == nil {
+		im.keyRepeats = make(map[d2enum.Key]float64)
+	}
+
+	im.keyRepeats[k] = im.now + inputTiming().GetKeyRepeatDelay()
+}
+
+// stopKeyRepeat stops repeating a key just released.
+func (im *inputManager) stopKeyRepeat(k d2enum.Key) {
+	delete(im.keyRepeats, k)
+}
+
+// updateKeyRepeat sends an auto repeat event if a held key is due to repeat. A slow frame repeats the key once, not
+// once for every interval it missed.
+func (im *inputManager) updateKeyRepeat(k d2enum.Key, e KeyEvent) {
+	next, ok := im.keyRepeats[k]
+	if !ok || im.now < next {
+		return
+	}
+
+	interval := inputTiming().GetKeyRepeatInterval()
+
+	next += interval
+	if next <= im.now {
+		next = im.now + interval
+	}
+
+	im.keyRepeats[k] = next
+
+	fn := func(handler d2interface.InputEventHandler) bool {
+		if l, ok := handler.(d2interface.KeyAutoRepeatHandler); ok {
+			return l.OnKeyAutoRepeat(&e)
+		}
+
+		return false
+	}
+	im.propagate(fn)
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
@@ -0,0 +1,113 @@
+package d2input
+
+import (
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+)
+
+// gestureHandler counts the double clicks and key repeats it is sent.
+type gestureHandler struct {
+	doubleClicks int
+	keyRepeats   int
+}
+
+func (h *gestureHandler) OnMouseButtonDoubleClick(event d2interface.MouseEvent) bool {
+	h.doubleClicks++
+	return true
+}
+
+func (h *gestureHandler) OnKeyAutoRepeat(event d2interface.KeyEvent) bool {
+	h.keyRepeats++
+	return true
+}
+
+// testInputTiming sets the input timing of the configuration until the test ends.
+func testInputTiming(t *testing.T, timing d2config.InputTiming) {
+	config := d2config.Config
+	t.Cleanup(func() { d2config.Config = config })
+
+	d2config.Config = &d2config.Configuration{Input: timing}
+}
+
+// advanceFrame advances the input manager to the time, in seconds, with the input devices in the state of the frame.
+func advanceFrame(im *inputManager, now float64, frame inputFrame) {
+	im.inputService = frameInputService{&frame}
+	_ = im.Advance(0, now)
+}
+
+// click returns a frame where the left button was just pressed at the position.
+func click(x, y int) inputFrame {
+	return inputFrame{CursorX: x, CursorY: y, Buttons: map[d2enum.MouseButton]inputState{
+		d2enum.MouseButtonLeft: {Pressed: true, JustPressed: true},
+	}}
+}
+
+func TestDoubleClick(t *testing.T) {
+	assert := testify.New(t)
+
+	testInputTiming(t, d2config.InputTiming{DoubleClickInterval: 0.5})
+
+	im := &inputManager{}
+	handler := &gestureHandler{}
+	assert.NoError(im.BindHandler(handler))
+
+	advanceFrame(im, 0, click(100, 100))
+	advanceFrame(im, 0.1, inputFrame{CursorX: 100, CursorY: 100})
+	advanceFrame(im, 0.5, click(102, 97))
+	assert.Equal(1, handler.doubleClicks, "a second click within the interval and distance is a double click")
+
+	advanceFrame(im, 0.6, click(102, 97))
+	assert.Equal(1, handler.doubleClicks, "a third click starts a new double click")
+
+	advanceFrame(im, 1.2, click(102, 97))
+	assert.Equal(1, handler.doubleClicks, "a click after the interval starts a new double click")
+
+	advanceFrame(im, 1.3, click(110, 97))
+	assert.Equal(1, handler.doubleClicks, "a click too far from the first starts a new double click")
+
+	advanceFrame(im, 1.4, click(110, 97))
+	assert.Equal(2, handler.doubleClicks)
+}
+
+func TestKeyAutoRepeat(t *testing.T) {
+	assert := testify.New(t)
+
+	testInputTiming(t, d2config.InputTiming{KeyRepeatDelay: 0.4, KeyRepeatInterval: 0.1})
+
+	im := &inputManager{}
+	handler := &gestureHandler{}
+	assert.NoError(im.BindHandler(handler))
+
+	held := inputFrame{Keys: map[d2enum.Key]inputState{d2enum.KeyA: {Pressed: true}}}
+
+	advanceFrame(im, 1, inputFrame{Keys: map[d2enum.Key]inputState{d2enum.KeyA: {Pressed: true, JustPressed: true}}})
+	advanceFrame(im, 1.39, held)
+	assert.Equal(0, handler.keyRepeats, "the key doesn't repeat before the delay")
+
+	advanceFrame(im, 1.4, held)
+	assert.Equal(1, handler.keyRepeats, "the key repeats once held for the delay")
+
+	advanceFrame(im, 1.45, held)
+	assert.Equal(1, handler.keyRepeats)
+
+	advanceFrame(im, 1.5, held)
+	assert.Equal(2, handler.keyRepeats, "the key repeats at every interval")
+
+	advanceFrame(im, 2, held)
+	assert.Equal(3, handler.keyRepeats, "a slow frame repeats the key once")
+
+	advanceFrame(im, 2.05, held)
+	assert.Equal(3, handler.keyRepeats, "the interval starts over after a slow frame")
+
+	advanceFrame(im, 2.1, held)
+	assert.Equal(4, handler.keyRepeats)
+
+	advanceFrame(im, 2.15, inputFrame{Keys: map[d2enum.Key]inputState{d2enum.KeyA: {JustReleased: true}}})
+	advanceFrame(im, 3, held)
+	assert.Equal(4, handler.keyRepeats, "a released key stops repeating")
+}
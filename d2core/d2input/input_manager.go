@@ -9,24 +9,34 @@ import (
 )
 
 type inputManager struct {
-	inputService d2interface.InputService
+	inputService d2interface.InputService // Input read this frame
+	devices      d2interface.InputService // Input devices
+	recorder     *inputRecorder           // Records the input of every frame, nil unless recording
+	player       *inputPlayer             // Plays recorded input back, nil unless playing
 	cursorX      int
 	cursorY      int
 
 	buttonMod d2enum.MouseButtonMod
 	keyMod    d2enum.KeyMod
 
+	now        float64                            // Time of the current frame, in seconds
+	clicks     map[d2enum.MouseButton]buttonClick // Last press of each button, for double clicks
+	keyRepeats map[d2enum.Key]float64             // Time each held key repeats next
+
 	entries handlerEntryList
 }
 
 func New() d2interface.InputManager {
 	return &inputManager{
 		inputService: ebiten_input.InputService{},
+		devices:      ebiten_input.InputService{},
 	}
 }
 
 // Advance advances the inputManager
-func (im *inputManager) Advance(_, _ float64) error {
+func (im *inputManager) Advance(_, currentTime float64) error {
+	im.now = currentTime
+	im.advanceRecording()
 	im.updateKeyMod()
 	im.updateButtonMod()
 
@@ -100,6 +110,7 @@ func (im *inputManager) updateJustPressedKey(k d2enum.Key, e HandlerEvent) {
 		}
 
 		im.propagate(fn)
+		im.startKeyRepeat(k)
 	}
 }
 
@@ -115,6 +126,7 @@ func (im *inputManager) updateJustReleasedKey(k d2enum.Key, e HandlerEvent) {
 			return false
 		}
 		im.propagate(fn)
+		im.stopKeyRepeat(k)
 	}
 }
 
@@ -134,6 +146,7 @@ func (im *inputManager) updatePressedKey(k d2enum.Key, e HandlerEvent) {
 			return false
 		}
 		im.propagate(fn)
+		im.updateKeyRepeat(k, event)
 	}
 }
 
@@ -164,6 +177,7 @@ func (im *inputManager) updateJustPressedButton(b d2enum.MouseButton, e HandlerE
 			return false
 		}
 		im.propagate(fn)
+		im.updateDoubleClick(b, e)
 	}
 }
 
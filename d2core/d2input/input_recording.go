@@ -0,0 +1,296 @@
+package d2input
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// maxRecordingLine is the longest line of an input recording, in bytes.
+const maxRecordingLine = 1024 * 1024
+
+// inputRecordingHeader is the first line of an input recording.
+type inputRecordingHeader struct {
+	Seed int64 `json:"seed"`
+}
+
+// inputFrame is the state of the input devices during one frame of an input recording. Only the keys and buttons
+// which are down, or were just released, are kept.
+type inputFrame struct {
+	Frame   int                               `json:"frame"`
+	Time    time.Duration                     `json:"time"` // Since the recording started
+	CursorX int                               `json:"x"`
+	CursorY int                               `json:"y"`
+	Chars   string                            `json:"chars,omitempty"`
+	Keys    map[d2enum.Key]inputState         `json:"keys,omitempty"`
+	Buttons map[d2enum.MouseButton]inputState `json:"buttons,omitempty"`
+}
+
+// inputState is the state of a key or mouse button during a frame.
+type inputState struct {
+	Pressed      bool `json:"p,omitempty"`
+	JustPressed  bool `json:"jp,omitempty"`
+	JustReleased bool `json:"jr,omitempty"`
+	Duration     int  `json:"d,omitempty"` // Frames a key has been down for
+}
+
+// snapshotInput reads the state of the input devices from the service.
+func snapshotInput(service d2interface.InputService) *inputFrame {
+	frame := &inputFrame{
+		Chars:   string(service.InputChars()),
+		Keys:    make(map[d2enum.Key]inputState),
+		Buttons: make(map[d2enum.MouseButton]inputState),
+	}
+
+	frame.CursorX, frame.CursorY = service.CursorPosition()
+
+	for key := d2enum.KeyMin; key <= d2enum.KeyMax; key++ {
+		state := inputState{
+			Pressed:      service.IsKeyPressed(key),
+			JustPressed:  service.IsKeyJustPressed(key),
+			JustReleased: service.IsKeyJustReleased(key),
+		}
+
+		if state.Pressed {
+			state.Duration = service.KeyPressDuration(key)
+		}
+
+		if state != (inputState{}) {
+			frame.Keys[key] = state
+		}
+	}
+
+	for button := d2enum.MouseButtonMin; button <= d2enum.MouseButtonMax; button++ {
+		state := inputState{
+			Pressed:      service.IsMouseButtonPressed(button),
+			JustPressed:  service.IsMouseButtonJustPressed(button),
+			JustReleased: service.IsMouseButtonJustReleased(button),
+		}
+
+		if state != (inputState{}) {
+			frame.Buttons[button] = state
+		}
+	}
+
+	return frame
+}
+
+// idle returns true if no key or button is down or was just released, and no character was typed.
+func (f *inputFrame) idle() bool {
+	return len(f.Keys) == 0 && len(f.Buttons) == 0 && f.Chars == ""
+}
+
+// frameInputService serves the state of the input devices during a frame, recorded or just read.
+type frameInputService struct {
+	frame *inputFrame
+}
+
+func (s frameInputService) CursorPosition() (x, y int) {
+	return s.frame.CursorX, s.frame.CursorY
+}
+
+func (s frameInputService) InputChars() []rune {
+	return []rune(s.frame.Chars)
+}
+
+func (s frameInputService) IsKeyPressed(key d2enum.Key) bool {
+	return s.frame.Keys[key].Pressed
+}
+
+func (s frameInputService) IsKeyJustPressed(key d2enum.Key) bool {
+	return s.frame.Keys[key].JustPressed
+}
+
+func (s frameInputService) IsKeyJustReleased(key d2enum.Key) bool {
+	return s.frame.Keys[key].JustReleased
+}
+
+func (s frameInputService) IsMouseButtonPressed(button d2enum.MouseButton) bool {
+	return s.frame.Buttons[button].Pressed
+}
+
+func (s frameInputService) IsMouseButtonJustPressed(button d2enum.MouseButton) bool {
+	return s.frame.Buttons[button].JustPressed
+}
+
+func (s frameInputService) IsMouseButtonJustReleased(button d2enum.MouseButton) bool {
+	return s.frame.Buttons[button].JustReleased
+}
+
+func (s frameInputService) KeyPressDuration(key d2enum.Key) int {
+	return s.frame.Keys[key].Duration
+}
+
+// inputRecorder writes the input of every frame to a file, one JSON line per frame. Idle frames where the cursor
+// didn't move are skipped. Each frame is written as soon as it is read, so the recording survives a crash.
+type inputRecorder struct {
+	file    *os.File
+	encoder *json.Encoder
+	start   time.Time
+	frame   int
+	cursorX int
+	cursorY int
+}
+
+// createInputRecorder creates the recording file, starting with the world seed.
+func createInputRecorder(path string, seed int64) (*inputRecorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := &inputRecorder{file: file, encoder: json.NewEncoder(file), start: time.Now(), cursorX: -1}
+
+	if err := recorder.encoder.Encode(inputRecordingHeader{Seed: seed}); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return recorder, nil
+}
+
+// record writes the frame, unless it is idle and the cursor didn't move.
+func (r *inputRecorder) record(frame *inputFrame) error {
+	frame.Frame = r.frame
+	frame.Time = time.Since(r.start)
+	r.frame++
+
+	if frame.idle() && frame.CursorX == r.cursorX && frame.CursorY == r.cursorY {
+		return nil
+	}
+
+	r.cursorX, r.cursorY = frame.CursorX, frame.CursorY
+
+	return r.encoder.Encode(frame)
+}
+
+// inputPlayer plays the frames of an input recording back, one frame at a time.
+type inputPlayer struct {
+	frames []*inputFrame
+	next   int // Index of the next recorded frame to play
+	frame  int
+	idle   inputFrame // Frame played between the recorded frames, with the cursor where it last was
+}
+
+// loadInputPlayer reads the input recording, and returns the world seed it was recorded with.
+func loadInputPlayer(path string) (*inputPlayer, int64, error) {
+	file, err := os.Open(path) //nolint:gosec // the recording to play is picked by the user
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxRecordingLine)
+
+	if !scanner.Scan() {
+		return nil, 0, errors.New("the input recording is empty")
+	}
+
+	var header inputRecordingHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return nil, 0, fmt.Errorf("invalid input recording header: %w", err)
+	}
+
+	player := &inputPlayer{}
+
+	for scanner.Scan() {
+		frame := &inputFrame{}
+		if err := json.Unmarshal(scanner.Bytes(), frame); err != nil {
+			return nil, 0, fmt.Errorf("invalid input recording frame %d: %w", len(player.frames), err)
+		}
+
+		player.frames = append(player.frames, frame)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return player, header.Seed, nil
+}
+
+// nextFrame returns the state of the input devices during the next frame, or false once the recording ended.
+func (p *inputPlayer) nextFrame() (*inputFrame, bool) {
+	if p.next >= len(p.frames) {
+		return nil, false
+	}
+
+	frame := &p.idle
+
+	if p.frames[p.next].Frame == p.frame {
+		frame = p.frames[p.next]
+		p.idle.CursorX, p.idle.CursorY = frame.CursorX, frame.CursorY
+		p.next++
+	}
+
+	p.frame++
+
+	return frame, true
+}
+
+// RecordInput records the state of the input devices during every frame to the file, along with the world seed, so
+// the game can be played back with PlayInput.
+func (im *inputManager) RecordInput(path string, seed int64) error {
+	recorder, err := createInputRecorder(path, seed)
+	if err != nil {
+		return err
+	}
+
+	im.recorder = recorder
+
+	return nil
+}
+
+// PlayInput plays the input recorded in the file back in place of the input devices, frame by frame, and returns the
+// world seed it was recorded with. The game plays the same as when recording as long as it runs with the same seed
+// and data. The input devices take over again once the recording ends.
+func (im *inputManager) PlayInput(path string) (int64, error) {
+	player, seed, err := loadInputPlayer(path)
+	if err != nil {
+		return 0, err
+	}
+
+	im.player = player
+
+	return seed, nil
+}
+
+// advanceRecording reads the state of the input devices for the frame from the recording being played back, or
+// records it.
+func (im *inputManager) advanceRecording() {
+	switch {
+	case im.player != nil:
+		frame, ok := im.player.nextFrame()
+		if !ok {
+			log.Print("input playback finished")
+
+			im.player = nil
+			im.inputService = im.devices
+
+			return
+		}
+
+		im.inputService = frameInputService{frame}
+	case im.recorder != nil:
+		frame := snapshotInput(im.devices)
+		im.inputService = frameInputService{frame}
+
+		if err := im.recorder.record(frame); err != nil {
+			log.Printf("failed to record the input, recording stopped: %v", err)
+
+			_ = im.recorder.file.Close()
+			im.recorder = nil
+		}
+	}
+}
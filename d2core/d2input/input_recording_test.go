@@ -0,0 +1,96 @@
+package d2input
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// inputLog logs the key presses, clicks and typed characters it is sent.
+type inputLog struct {
+	events []string
+}
+
+func (l *inputLog) OnKeyDown(event d2interface.KeyEvent) bool {
+	l.events = append(l.events, fmt.Sprintf("key %d down", event.Key()))
+	return false
+}
+
+func (l *inputLog) OnMouseButtonDown(event d2interface.MouseEvent) bool {
+	l.events = append(l.events, fmt.Sprintf("button %d down at %d, %d", event.Button(), event.X(), event.Y()))
+	return false
+}
+
+func (l *inputLog) OnKeyChars(event d2interface.KeyCharsEvent) bool {
+	l.events = append(l.events, "typed "+string(event.Chars()))
+	return false
+}
+
+func TestInputRecordingRoundTrip(t *testing.T) {
+	assert := testify.New(t)
+
+	dir, err := ioutil.TempDir("", "d2input")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "input.jsonl")
+
+	// the devices the recorder reads, one frame after another, with idle frames in between
+	frames := []inputFrame{
+		{CursorX: 100, CursorY: 200},
+		{CursorX: 100, CursorY: 200},
+		{CursorX: 100, CursorY: 200, Keys: map[d2enum.Key]inputState{d2enum.KeyA: {Pressed: true, JustPressed: true}}},
+		{CursorX: 100, CursorY: 200, Keys: map[d2enum.Key]inputState{d2enum.KeyA: {JustReleased: true}}},
+		{CursorX: 300, CursorY: 400},
+		{CursorX: 300, CursorY: 400, Buttons: map[d2enum.MouseButton]inputState{
+			d2enum.MouseButtonLeft: {Pressed: true, JustPressed: true},
+		}},
+		{CursorX: 300, CursorY: 400},
+		{CursorX: 300, CursorY: 400, Chars: "go"},
+	}
+
+	recording := &inputManager{}
+	recorded := &inputLog{}
+	assert.NoError(recording.BindHandler(recorded))
+	assert.NoError(recording.RecordInput(path, 42))
+
+	for index := range frames {
+		recording.devices = frameInputService{&frames[index]}
+		assert.NoError(recording.Advance(0, float64(index)))
+	}
+
+	assert.NoError(recording.recorder.file.Close())
+
+	// nothing is pressed on the devices during the playback, the recording drives the input
+	playing := &inputManager{devices: frameInputService{&inputFrame{}}}
+	played := &inputLog{}
+	assert.NoError(playing.BindHandler(played))
+
+	seed, err := playing.PlayInput(path)
+	assert.NoError(err)
+	assert.Equal(int64(42), seed, "the recording keeps the world seed")
+
+	for index := range frames {
+		assert.NoError(playing.Advance(0, float64(index)))
+	}
+
+	assert.Equal([]string{
+		fmt.Sprintf("key %d down", d2enum.KeyA),
+		fmt.Sprintf("button %d down at 300, 400", d2enum.MouseButtonLeft),
+		"typed go",
+	}, recorded.events)
+	assert.Equal(recorded.events, played.events, "the playback sends the recorded events")
+
+	assert.NoError(playing.Advance(0, float64(len(frames))))
+	assert.Nil(playing.player, "the devices take over once the recording ends")
+}
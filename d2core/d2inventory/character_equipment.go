@@ -11,4 +11,12 @@ type CharacterEquipment struct {
 	RightHand *InventoryItemWeapon `json:"rightHand"` // RH
 	Shield    *InventoryItemArmor  `json:"shield"`    // SH
 	// S1-S8?
+
+	// Items without a composite layer
+	Neck      *InventoryItemMisc  `json:"neck"`
+	Gloves    *InventoryItemArmor `json:"gloves"`
+	Belt      *InventoryItemArmor `json:"belt"`
+	Feet      *InventoryItemArmor `json:"feet"`
+	LeftRing  *InventoryItemMisc  `json:"leftRing"`
+	RightRing *InventoryItemMisc  `json:"rightRing"`
 }
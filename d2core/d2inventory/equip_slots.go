@@ -0,0 +1,58 @@
+package d2inventory
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// armorSlots are the paper-doll slots of each armor and misc item type of ItemTypes.txt.
+//nolint:gochecknoglobals // constant lookup table
+var armorSlots = map[string][]d2enum.EquippedSlot{
+	"helm": {d2enum.EquippedSlotHead},
+	"circ": {d2enum.EquippedSlotHead},
+	"pelt": {d2enum.EquippedSlotHead},
+	"phlm": {d2enum.EquippedSlotHead},
+	"tors": {d2enum.EquippedSlotTorso},
+	"shie": {d2enum.EquippedSlotRightArm},
+	"ashd": {d2enum.EquippedSlotRightArm},
+	"head": {d2enum.EquippedSlotRightArm},
+	"glov": {d2enum.EquippedSlotGloves},
+	"boot": {d2enum.EquippedSlotLegs},
+	"belt": {d2enum.EquippedSlotBelt},
+	"amul": {d2enum.EquippedSlotNeck},
+	"ring": {d2enum.EquippedSlotLeftHand, d2enum.EquippedSlotRightHand},
+}
+
+// EquipSlots returns the paper-doll slots the item with the given code can be put in. Weapons go in the weapon slot,
+// one handed weapons can also be held in the shield slot. Items which can't be equipped have no slots.
+func EquipSlots(code string) []d2enum.EquippedSlot {
+	if weapon, ok := d2datadict.Weapons[code]; ok {
+		if weapon.UsesTwoHands {
+			return []d2enum.EquippedSlot{d2enum.EquippedSlotLeftArm}
+		}
+
+		return []d2enum.EquippedSlot{d2enum.EquippedSlotLeftArm, d2enum.EquippedSlotRightArm}
+	}
+
+	record, ok := d2datadict.Armors[code]
+	if !ok {
+		record, ok = d2datadict.MiscItems[code]
+	}
+
+	if !ok {
+		return nil
+	}
+
+	return armorSlots[record.Type]
+}
+
+// CanEquip returns true if the item with the given code can be put in the given paper-doll slot.
+func CanEquip(code string, slot d2enum.EquippedSlot) bool {
+	for _, allowed := range EquipSlots(code) {
+		if allowed == slot {
+			return true
+		}
+	}
+
+	return false
+}
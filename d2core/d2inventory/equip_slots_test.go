@@ -0,0 +1,46 @@
+package d2inventory
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+func TestCanEquip(t *testing.T) {
+	d2datadict.Weapons = map[string]*d2datadict.ItemCommonRecord{
+		"hax": {Code: "hax"},
+		"lbw": {Code: "lbw", UsesTwoHands: true},
+	}
+	d2datadict.Armors = map[string]*d2datadict.ItemCommonRecord{
+		"cap": {Code: "cap", Type: "helm"},
+		"buc": {Code: "buc", Type: "shie"},
+	}
+	d2datadict.MiscItems = map[string]*d2datadict.ItemCommonRecord{
+		"rin": {Code: "rin", Type: "ring"},
+		"hp1": {Code: "hp1", Type: "hpot"},
+	}
+
+	tests := []struct {
+		code string
+		slot d2enum.EquippedSlot
+		want bool
+	}{
+		{"hax", d2enum.EquippedSlotLeftArm, true},
+		{"hax", d2enum.EquippedSlotRightArm, true},
+		{"lbw", d2enum.EquippedSlotRightArm, false},
+		{"cap", d2enum.EquippedSlotHead, true},
+		{"cap", d2enum.EquippedSlotTorso, false},
+		{"buc", d2enum.EquippedSlotRightArm, true},
+		{"buc", d2enum.EquippedSlotLeftArm, false},
+		{"rin", d2enum.EquippedSlotRightHand, true},
+		{"hp1", d2enum.EquippedSlotNeck, false},
+		{"xyz", d2enum.EquippedSlotHead, false},
+	}
+
+	for _, test := range tests {
+		if got := CanEquip(test.code, test.slot); got != test.want {
+			t.Errorf("CanEquip(%q, %d) = %v, want %v", test.code, test.slot, got, test.want)
+		}
+	}
+}
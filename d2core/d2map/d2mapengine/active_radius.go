@@ -0,0 +1,71 @@
+package d2mapengine
+
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+// SetActiveRadius sets how far from the players, in tiles, monsters are advanced. Monsters further away stand still
+// until a player comes close. A radius of 0 advances every monster on the map.
+func (m *MapEngine) SetActiveRadius(radius float64) {
+	m.activeRadius = radius
+}
+
+// SetVisibleRect sets the tiles on screen, where monsters are advanced whatever the active radius. The camera may be
+// away from the players, so monsters on screen can be out of their radius.
+func (m *MapEngine) SetVisibleRect(rect d2common.Rectangle) {
+	m.visibleRect = rect
+}
+
+// ActiveRadius returns how far from the players, in tiles, monsters are advanced, 0 if every monster is.
+func (m *MapEngine) ActiveRadius() float64 {
+	return m.activeRadius
+}
+
+// playerPositions returns the world positions of the players on the map, none if every monster is advanced.
+func (m *MapEngine) playerPositions() [][2]float64 {
+	var positions [][2]float64
+
+	if m.activeRadius <= 0 {
+		return nil
+	}
+
+	for _, entity := range m.entities {
+		if player, ok := entity.(*d2mapentity.Player); ok {
+			x, y := player.GetPositionF()
+			positions = append(positions, [2]float64{x, y})
+		}
+	}
+
+	return positions
+}
+
+// isActive returns true if the entity is advanced: anything but a monster, or a monster on screen or within the active
+// radius of one of the players.
+func (m *MapEngine) isActive(entity d2interface.MapEntity, players [][2]float64) bool {
+	if m.activeRadius <= 0 {
+		return true
+	}
+
+	if _, ok := entity.(*d2mapentity.NPC); !ok {
+		return true
+	}
+
+	x, y := entity.GetPositionF()
+
+	if x >= float64(m.visibleRect.Left) && x < float64(m.visibleRect.Right()) &&
+		y >= float64(m.visibleRect.Top) && y < float64(m.visibleRect.Bottom()) {
+		return true
+	}
+
+	for _, player := range players {
+		if math.Hypot(x-player[0], y-player[1]) <= m.activeRadius {
+			return true
+		}
+	}
+
+	return false
+}
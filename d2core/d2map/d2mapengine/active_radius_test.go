@@ -0,0 +1,35 @@
+package d2mapengine
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestActiveRadiusCountsMonstersOnScreen(t *testing.T) {
+	assert := testify.New(t)
+
+	m := &MapEngine{}
+	players := [][2]float64{{0, 0}}
+
+	monster := &d2mapentity.NPC{}
+	monster.Position = d2vector.NewPosition(20*5, 0) // 20 tiles away
+
+	assert.True(m.isActive(monster, players), "every monster is active without a radius")
+
+	m.SetActiveRadius(10)
+	assert.False(m.isActive(monster, players), "monsters out of the radius stand still")
+
+	m.SetVisibleRect(d2common.Rectangle{Left: 15, Top: -5, Width: 10, Height: 10})
+	assert.True(m.isActive(monster, players), "monsters on screen act, away from the players")
+
+	m.SetVisibleRect(d2common.Rectangle{Left: 21, Top: -5, Width: 10, Height: 10})
+	assert.False(m.isActive(monster, players))
+
+	monster.Position = d2vector.NewPosition(5*5, 0)
+	assert.True(m.isActive(monster, players), "monsters near the players act off screen")
+}
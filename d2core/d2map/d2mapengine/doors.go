@@ -0,0 +1,156 @@
+package d2mapengine
+
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2object"
+)
+
+// doorApproachRadius is the distance in tiles at which monsters open the doors they walk up to.
+const doorApproachRadius = 1.5
+
+// collider is an entity standing on sub tiles of the walk mesh, such as an object, which may block walking on them and
+// seeing through them.
+type collider interface {
+	BlocksWalk() bool
+	BlocksSight() bool
+	Footprint() d2common.Rectangle
+}
+
+// door is a collider opened and closed while playing, changing what it blocks.
+type door interface {
+	collider
+	GetPositionF() (float64, float64)
+	IsDoor() bool
+	IsOpened() bool
+	MonstersOperate() bool
+	Operate()
+	Close()
+}
+
+var _ door = &d2object.Object{}
+
+// OpenDoor opens the door, playing its opening animation, and lets paths through it.
+func (m *MapEngine) OpenDoor(object *d2object.Object) {
+	m.setDoorOpen(object, true)
+}
+
+// CloseDoor closes the door, playing its closing animation, and blocks paths through it again.
+func (m *MapEngine) CloseDoor(object *d2object.Object) {
+	m.setDoorOpen(object, false)
+}
+
+func (m *MapEngine) setDoorOpen(d door, open bool) {
+	if !d.IsDoor() || d.IsOpened() == open {
+		return
+	}
+
+	if open {
+		d.Operate()
+	} else {
+		d.Close()
+	}
+
+	m.refreshWalkMesh(d.Footprint())
+}
+
+// advanceDoors opens the closed doors monsters walk up to.
+func (m *MapEngine) advanceDoors() {
+	for _, entity := range m.entities {
+		d, ok := entity.(door)
+		if !ok || !d.IsDoor() || d.IsOpened() || !d.MonstersOperate() {
+			continue
+		}
+
+		doorX, doorY := d.GetPositionF()
+
+		for _, other := range m.entities {
+			if _, ok := other.(*d2mapentity.NPC); !ok {
+				continue
+			}
+
+			if x, y := other.GetPositionF(); math.Hypot(x-doorX, y-doorY) <= doorApproachRadius {
+				m.setDoorOpen(d, true)
+				break
+			}
+		}
+	}
+}
+
+// applyColliders blocks the sub tiles of the walk mesh under the colliders blocking them, within the sub tile rect.
+func (m *MapEngine) applyColliders(rect d2common.Rectangle) {
+	for _, entity := range m.entities {
+		c, ok := entity.(collider)
+		if !ok {
+			continue
+		}
+
+		blocksWalk, blocksSight := c.BlocksWalk(), c.BlocksSight()
+		if !blocksWalk && !blocksSight {
+			continue
+		}
+
+		footprint := c.Footprint()
+
+		for subTileY := d2common.MaxInt(footprint.Top, rect.Top); subTileY < d2common.MinInt(footprint.Bottom(),
+			rect.Bottom()); subTileY++ {
+			for subTileX := d2common.MaxInt(footprint.Left, rect.Left); subTileX < d2common.MinInt(footprint.Right(),
+				rect.Right()); subTileX++ {
+				index, ok := m.subTileIndex(subTileX, subTileY)
+				if !ok {
+					continue
+				}
+
+				if blocksWalk {
+					m.walkMesh[index].Walkable = false
+				}
+
+				if blocksSight && m.sightBlocked != nil {
+					m.sightBlocked[index] = true
+				}
+			}
+		}
+	}
+}
+
+// refreshWalkMesh works out again which sub tiles of the rect block walking and sight, from the tiles and the
+// colliders, and links the walk mesh again.
+func (m *MapEngine) refreshWalkMesh(rect d2common.Rectangle) {
+	if m.walkMesh == nil {
+		return
+	}
+
+	for subTileY := rect.Top; subTileY < rect.Bottom(); subTileY++ {
+		for subTileX := rect.Left; subTileX < rect.Right(); subTileX++ {
+			index, ok := m.subTileIndex(subTileX, subTileY)
+			if !ok {
+				continue
+			}
+
+			blocksWalk, blocksSight := m.subTileBlocks(subTileX, subTileY)
+			m.walkMesh[index].Walkable = !blocksWalk
+
+			if m.sightBlocked != nil {
+				m.sightBlocked[index] = blocksSight
+			}
+		}
+	}
+
+	m.applyColliders(rect)
+	m.linkWalkMesh()
+}
+
+// subTileIndex returns the index of the sub tile in the walk mesh, if it is on the map.
+func (m *MapEngine) subTileIndex(subTileX, subTileY int) (int, bool) {
+	subTilesWide := m.size.Width * 5
+
+	if subTileX < 0 || subTileY < 0 || subTileX >= subTilesWide || subTileY >= m.size.Height*5 {
+		return 0, false
+	}
+
+	index := subTileX + subTileY*subTilesWide
+
+	return index, index < len(m.walkMesh)
+}
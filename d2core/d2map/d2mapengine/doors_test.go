@@ -0,0 +1,60 @@
+package d2mapengine
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+type testDoor struct {
+	testEntity
+	opened bool
+}
+
+func (d *testDoor) GetPositionF() (float64, float64) { return 0.5, 0.5 }
+func (d *testDoor) IsDoor() bool                     { return true }
+func (d *testDoor) IsOpened() bool                   { return d.opened }
+func (d *testDoor) MonstersOperate() bool            { return true }
+func (d *testDoor) Operate()                         { d.opened = true }
+func (d *testDoor) Close()                           { d.opened = false }
+func (d *testDoor) BlocksWalk() bool                 { return !d.opened }
+func (d *testDoor) BlocksSight() bool                { return !d.opened }
+
+// Footprint is the middle column of the tile, which a closed door walls off.
+func (d *testDoor) Footprint() d2common.Rectangle {
+	return d2common.Rectangle{Left: 2, Top: 0, Width: 1, Height: 5}
+}
+
+func TestDoorCollision(t *testing.T) {
+	assert := testify.New(t)
+
+	m := cornerMesh()
+	m.tiles = make([]d2ds1.TileRecord, 1)
+	m.sightBlocked = make([]bool, len(m.walkMesh))
+	d := &testDoor{}
+	m.AddEntity(d)
+
+	m.applyColliders(d2common.Rectangle{Width: 5, Height: 5})
+	m.linkWalkMesh()
+
+	_, _, found := m.findPath(&m.walkMesh[10], &m.walkMesh[14])
+	assert.False(found, "a closed door blocks the path")
+	assert.False(m.IsWalkable(0.5, 0.1))
+	assert.False(m.HasLineOfSight(0.1, 0.5, 0.9, 0.5), "a closed door blocks the sight")
+
+	m.setDoorOpen(d, true)
+	assert.True(d.opened)
+
+	_, _, found = m.findPath(&m.walkMesh[10], &m.walkMesh[14])
+	assert.True(found, "an open door lets paths through")
+	assert.True(m.IsWalkable(0.5, 0.1))
+	assert.True(m.HasLineOfSight(0.1, 0.5, 0.9, 0.5))
+
+	m.setDoorOpen(d, false)
+
+	_, _, found = m.findPath(&m.walkMesh[10], &m.walkMesh[14])
+	assert.False(found, "closing the door blocks the path again")
+}
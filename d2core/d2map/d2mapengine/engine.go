@@ -2,6 +2,7 @@ package d2mapengine
 
 import (
 	"log"
+	"math/rand"
 	"strings"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
@@ -27,9 +28,19 @@ type MapEngine struct {
 	levelType     d2datadict.LevelTypeRecord // Level type of this map
 	dt1TileData   []d2dt1.Tile               // DT1 tile data
 	walkMesh      []d2common.PathTile        // Sub tiles representing the walkable map area
+	sightBlocked  []bool                     // Sub tiles which can't be seen through, by walk mesh index
 	startSubTileX int                        // Starting X position
 	startSubTileY int                        // Starting Y position
 	dt1Files      []string                   // List of DS1 strings
+	cornerCutting CornerCutting              // Rule for paths moving diagonally past blocked sub tiles
+	meshVersion   int                        // Changes whenever the walk mesh is linked again
+	spawns        *spawnLimits               // Caps on the entities spawned while playing
+	tick          uint64                     // Ticks the map was advanced since it was reset
+	activeRadius  float64                    // Tiles from the players monsters are advanced within, 0 for everywhere
+	visibleRect   d2common.Rectangle         // Tiles on screen, where monsters are always advanced
+	levels        map[int]d2common.Rectangle // Tiles of the levels generated on the map, by levels.txt ID
+	rng           *rand.Rand                 // Rolls of the spawns, seeded with the map seed when the map is reset
+	pathCache     *PathCache                 // Paths shared by the entities heading to the same goals, nil until used
 }
 
 // CreateMapEngine creates a new instance of the map engine and
@@ -54,6 +65,10 @@ func (m *MapEngine) GetStartingPosition() (int, int) {
 // cached files.
 func (m *MapEngine) ResetMap(levelType d2enum.RegionIdType, width, height int) {
 	m.entities = make([]d2interface.MapEntity, 0)
+	m.spawnLimits().clear()
+	m.tick = 0
+	m.levels = nil
+	m.rng = rand.New(rand.NewSource(m.seed)) //nolint:gosec // spawn rolls don't need crypto rand
 	m.levelType = d2datadict.LevelTypes[levelType]
 	m.size = d2common.Size{Width: width, Height: height}
 	m.tiles = make([]d2ds1.TileRecord, width*height)
@@ -183,7 +198,9 @@ func (m *MapEngine) PlaceStamp(stamp *d2mapstamp.Stamp, tileOffsetX, tileOffsetY
 	}
 
 	// Copy over the entities
-	m.entities = append(m.entities, stamp.Entities(tileOffsetX, tileOffsetY)...)
+	for _, entity := range stamp.Entities(tileOffsetX, tileOffsetY, m.random(), m.subTileWalkable) {
+		m.AddEntity(entity)
+	}
 }
 
 // converts x,y tile coordinate into index in MapEngine.tiles
@@ -196,15 +213,20 @@ func (m *MapEngine) tileIndexToCoordinate(index int) (int, int) {
 	return (index % m.size.Width), (index / m.size.Width)
 }
 
-// TileAt returns a pointer to the data for the map tile at the given
-// x and y index.
-func (m *MapEngine) TileAt(tileX, tileY int) *d2ds1.TileRecord {
-	idx := m.tileCoordinateToIndex(tileX, tileY)
-	if idx < 0 || idx >= len(m.tiles) {
-		return nil
+// TileAt returns a pointer to the data for the map tile at the given x and y index. Returns false if the tile is
+// outside of the map, which positions can be for a moment while changing levels.
+func (m *MapEngine) TileAt(tileX, tileY int) (*d2ds1.TileRecord, bool) {
+	if !m.inBounds(tileX, tileY) {
+		return nil, false
 	}
 
-	return &m.tiles[idx]
+	return &m.tiles[m.tileCoordinateToIndex(tileX, tileY)], true
+}
+
+// inBounds returns true if the tile is within the map and its tiles are loaded.
+func (m *MapEngine) inBounds(tileX, tileY int) bool {
+	return tileX >= 0 && tileY >= 0 && tileX < m.size.Width && tileY < m.size.Height &&
+		m.tileCoordinateToIndex(tileX, tileY) < len(m.tiles)
 }
 
 // Entities returns a pointer a slice of all map entities.
@@ -212,22 +234,52 @@ func (m *MapEngine) Entities() *[]d2interface.MapEntity {
 	return &m.entities
 }
 
+// random returns the source of the spawn rolls, seeded with the map seed.
+func (m *MapEngine) random() *rand.Rand {
+	if m.rng == nil {
+		m.rng = rand.New(rand.NewSource(m.seed)) //nolint:gosec // spawn rolls don't need crypto rand
+	}
+
+	return m.rng
+}
+
 // Seed returns the map generation seed.
 func (m *MapEngine) Seed() int64 {
 	return m.seed
 }
 
-// AddEntity adds an entity to a slice containing all entities.
+// paletteEntity is implemented by the entities drawn with the palette of the level they are on.
+type paletteEntity interface {
+	SetPalette(palettePath string) error
+}
+
+// AddEntity adds an entity to a slice containing all entities. Entities which support it are switched to the palette
+// of the level.
 func (m *MapEngine) AddEntity(entity d2interface.MapEntity) {
+	if e, ok := entity.(paletteEntity); ok {
+		if err := e.SetPalette(m.levelType.PalettePath()); err != nil {
+			log.Printf("failed to set the palette of an entity: %v", err)
+		}
+	}
+
 	m.entities = append(m.entities, entity)
 }
 
-// RemoveEntity is not currently implemented.
+// RemoveEntity removes an entity from the map. The entities are copied to a new slice, so entities can be removed
+// while the map is advancing them.
 func (m *MapEngine) RemoveEntity(entity d2interface.MapEntity) {
 	if entity == nil {
 		return
 	}
-	// m.entities.Remove(entity)
+
+	m.removeSpawned(entity)
+
+	for idx := range m.entities {
+		if m.entities[idx] == entity {
+			m.entities = append(m.entities[:idx:idx], m.entities[idx+1:]...)
+			return
+		}
+	}
 }
 
 // GetTiles returns a slice of all tiles matching the given style,
@@ -275,17 +327,40 @@ func (m *MapEngine) GetCenterPosition() (float64, float64) {
 // Advance calls the Advance() method for all entities,
 // processing a single tick.
 func (m *MapEngine) Advance(tickTime float64) {
-	for idx := range m.entities {
-		m.entities[idx].Advance(tickTime)
+	entities := m.entities
+	players := m.playerPositions()
+
+	for idx := range entities {
+		if m.isActive(entities[idx], players) {
+			entities[idx].Advance(tickTime)
+		}
+	}
+
+	m.advanceSpawns()
+	m.advanceLeashes(tickTime)
+	m.advanceDoors()
+	m.advanceSeparation(tickTime)
+	m.recordSnapshots()
+	m.tick++
+}
+
+// snapshotRecorder is an entity keeping a history of snapshots, to be sampled between ticks.
+type snapshotRecorder interface {
+	RecordSnapshot(tick uint64)
+}
+
+// recordSnapshots adds the state of the entities at the end of the current tick to their histories.
+func (m *MapEngine) recordSnapshots() {
+	for _, entity := range m.entities {
+		if recorder, ok := entity.(snapshotRecorder); ok {
+			recorder.RecordSnapshot(m.tick)
+		}
 	}
 }
 
 // TileExists returns true if the tile at the given coordinates exists.
 func (m *MapEngine) TileExists(tileX, tileY int) bool {
-	tileIndex := m.tileCoordinateToIndex(tileX, tileY)
-
-	if valid := (tileIndex >= 0) && (tileIndex <= len(m.tiles)); valid {
-		tile := m.tiles[tileIndex]
+	if tile, ok := m.TileAt(tileX, tileY); ok {
 		numFeatures := len(tile.Floors)
 		numFeatures += len(tile.Shadows)
 		numFeatures += len(tile.Walls)
@@ -303,6 +378,10 @@ func (m *MapEngine) GenerateMap(regionType d2enum.RegionIdType, levelPreset int,
 	regionSize := region.Size()
 	m.ResetMap(regionType, regionSize.Width, regionSize.Height)
 	m.PlaceStamp(region, 0, 0)
+
+	if levelID := region.LevelPreset().LevelID; levelID > 0 {
+		m.SetLevelBounds(levelID, d2common.Rectangle{Width: regionSize.Width, Height: regionSize.Height})
+	}
 }
 
 // GetTileData returns the tile with the given style, sequence and tileType.
@@ -0,0 +1,66 @@
+package d2mapengine
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestTileAtBounds(t *testing.T) {
+	assert := testify.New(t)
+
+	m := &MapEngine{
+		size:  d2common.Size{Width: 3, Height: 2},
+		tiles: make([]d2ds1.TileRecord, 6),
+	}
+	m.tiles[5].Floors = make([]d2ds1.FloorShadowRecord, 1)
+
+	for _, corner := range [][2]int{{0, 0}, {2, 0}, {0, 1}, {2, 1}} {
+		tile, ok := m.TileAt(corner[0], corner[1])
+		assert.True(ok, "tile %v is on the map", corner)
+		assert.NotNil(tile)
+	}
+
+	for _, outside := range [][2]int{{-1, 0}, {0, -1}, {3, 0}, {0, 2}, {3, 1}, {100, 100}} {
+		tile, ok := m.TileAt(outside[0], outside[1])
+		assert.False(ok, "tile %v is outside of the map", outside)
+		assert.Nil(tile)
+	}
+
+	assert.True(m.TileExists(2, 1))
+	assert.False(m.TileExists(0, 0), "the tile has nothing on it")
+	assert.False(m.TileExists(0, 2), "the tile is past the last row")
+
+	m.tiles = m.tiles[:4]
+	_, ok := m.TileAt(2, 1)
+	assert.False(ok, "the tiles of the last row aren't loaded")
+}
+
+type recordingEntity struct {
+	testEntity
+	ticks []uint64
+}
+
+func (e *recordingEntity) Advance(float64) {}
+
+func (e *recordingEntity) RecordSnapshot(tick uint64) {
+	e.ticks = append(e.ticks, tick)
+}
+
+func TestAdvanceRecordsSnapshots(t *testing.T) {
+	assert := testify.New(t)
+
+	m := &MapEngine{}
+	entity := &recordingEntity{}
+	m.AddEntity(entity)
+
+	for tick := 0; tick < 3; tick++ {
+		m.Advance(0.04)
+	}
+
+	assert.Equal([]uint64{0, 1, 2}, entity.ticks, "each tick is recorded once the entities advanced")
+	assert.Equal(uint64(3), m.Tick())
+}
@@ -0,0 +1,52 @@
+package d2mapengine
+
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+// leashSightRange is how far, in tiles, a pursuing monster sees the players in its line of sight.
+const leashSightRange = 15.0
+
+// advanceLeashes sends the pursuing monsters which lost sight of the players for too long, or strayed too far from
+// where they were aggroed, back there along a path.
+func (m *MapEngine) advanceLeashes(tickTime float64) {
+	for _, entity := range m.entities {
+		npc, ok := entity.(*d2mapentity.NPC)
+		if !ok || !npc.IsAggroed() {
+			continue
+		}
+
+		if !npc.AdvanceLeash(tickTime, m.seesPlayer(npc)) {
+			continue
+		}
+
+		x, y := npc.GetPositionF()
+		originX, originY, _ := npc.AggroOrigin()
+		path, _, _ := m.CachedPathFind(x, y, originX, originY)
+
+		npc.ReturnToAggroOrigin(path)
+	}
+}
+
+// seesPlayer returns true if a player is within the sight range of the NPC, and in its line of sight.
+func (m *MapEngine) seesPlayer(npc *d2mapentity.NPC) bool {
+	npcX, npcY := npc.GetPositionF()
+
+	for _, entity := range m.entities {
+		player, ok := entity.(*d2mapentity.Player)
+		if !ok {
+			continue
+		}
+
+		playerX, playerY := player.GetPositionF()
+
+		if math.Hypot(playerX-npcX, playerY-npcY) <= leashSightRange &&
+			m.HasLineOfSight(npcX, npcY, playerX, playerY) {
+			return true
+		}
+	}
+
+	return false
+}
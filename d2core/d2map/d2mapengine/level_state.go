@@ -0,0 +1,207 @@
+package d2mapengine
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2object"
+)
+
+// LevelStateLifetime is how long a saved level state can be restored, as Diablo II games live on for a short while
+// after the last player leaves.
+const LevelStateLifetime = 5 * time.Minute
+
+// LevelState is the state of the entities of a map which aren't part of the character saves: the monsters' life and
+// position, the items on the ground and the objects opened. Everything else is generated again from the seed, so a
+// state can only be restored on a map generated with the same seed.
+type LevelState struct {
+	Seed     int64               `json:"seed"`
+	Region   d2enum.RegionIdType `json:"region"`
+	Saved    time.Time           `json:"saved"`
+	Monsters []MonsterState      `json:"monsters"`
+	Items    []ItemState         `json:"items"`
+	Objects  []ObjectState       `json:"objects"`
+}
+
+// MonsterState is the state of a monster, identified by the order the monsters were spawned in.
+type MonsterState struct {
+	Index int     `json:"index"`
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Life  int     `json:"life"`
+}
+
+// ItemState is an item lying on the ground.
+type ItemState struct {
+	ID      string             `json:"id"`
+	Code    string             `json:"code"`
+	Gold    int                `json:"gold"`
+	X       int                `json:"x"`
+	Y       int                `json:"y"`
+	Quality d2enum.ItemQuality `json:"quality"`
+	Level   int                `json:"level"`
+}
+
+// ObjectState is an object which was opened, identified by its position.
+type ObjectState struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Expired returns true if the state is too old to be restored.
+func (s *LevelState) Expired(now time.Time) bool {
+	return now.Sub(s.Saved) > LevelStateLifetime
+}
+
+// SaveState captures the state of the map's entities.
+func (m *MapEngine) SaveState() *LevelState {
+	state := &LevelState{Seed: m.seed, Region: d2enum.RegionIdType(m.levelType.ID), Saved: time.Now()}
+	monsterIndex := 0
+
+	for _, entity := range m.entities {
+		switch e := entity.(type) {
+		case *d2mapentity.NPC:
+			state.Monsters = append(state.Monsters, MonsterState{
+				Index: monsterIndex,
+				X:     e.Position.X(),
+				Y:     e.Position.Y(),
+				Life:  e.Life(),
+			})
+			monsterIndex++
+		case *d2mapentity.GroundItem:
+			state.Items = append(state.Items, ItemState{
+				ID:      e.ID(),
+				Code:    e.Record().Code,
+				Gold:    e.Gold(),
+				X:       int(e.Position.X()),
+				Y:       int(e.Position.Y()),
+				Quality: e.Quality(),
+				Level:   e.Level(),
+			})
+		case *d2object.Object:
+			if e.IsOpened() {
+				state.Objects = append(state.Objects, ObjectState{X: int(e.Position.X()), Y: int(e.Position.Y())})
+			}
+		}
+	}
+
+	return state
+}
+
+// RestoreState applies the saved state to the entities of the map, which must have just been generated with the
+// state's seed. Monsters walking a path, such as the town NPCs, keep to it.
+func (m *MapEngine) RestoreState(state *LevelState) error {
+	if state.Seed != m.seed {
+		return errors.New("the level state was saved on a map generated with a different seed")
+	}
+
+	monsters := make(map[int]MonsterState, len(state.Monsters))
+	for _, monster := range state.Monsters {
+		monsters[monster.Index] = monster
+	}
+
+	opened := make(map[ObjectState]bool, len(state.Objects))
+	for _, object := range state.Objects {
+		opened[object] = true
+	}
+
+	monsterIndex := 0
+
+	for _, entity := range m.entities {
+		switch e := entity.(type) {
+		case *d2mapentity.NPC:
+			if monster, ok := monsters[monsterIndex]; ok {
+				e.SetLife(monster.Life)
+
+				if !e.HasPaths {
+					e.SetPosition(monster.X, monster.Y)
+				}
+			}
+			monsterIndex++
+		case *d2mapentity.GroundItem:
+			m.RemoveEntity(e)
+		case *d2object.Object:
+			if opened[ObjectState{X: int(e.Position.X()), Y: int(e.Position.Y())}] {
+				e.Open()
+			}
+		}
+	}
+
+	for _, itemState := range state.Items {
+		item, err := restoreItem(itemState)
+		if err != nil {
+			log.Printf("failed to restore item %s: %v", itemState.Code, err)
+			continue
+		}
+
+		m.AddEntity(item)
+	}
+
+	return nil
+}
+
+func restoreItem(state ItemState) (*d2mapentity.GroundItem, error) {
+	var (
+		item *d2mapentity.GroundItem
+		err  error
+	)
+
+	if state.Gold > 0 {
+		item, err = d2mapentity.CreateGroundGold(state.X, state.Y, state.Gold)
+	} else {
+		item, err = d2mapentity.CreateGroundItem(state.X, state.Y, state.Code)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	item.SetID(state.ID)
+	item.SetQuality(state.Quality)
+	item.SetLevel(state.Level)
+
+	return item, nil
+}
+
+// SaveLevelState writes the level state to the file.
+func SaveLevelState(path string, state *LevelState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// LoadLevelState reads the level state from the file. Returns nil without an error if there is no state which can
+// still be restored.
+func LoadLevelState(path string) (*LevelState, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	state := &LevelState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+
+	if state.Expired(time.Now()) {
+		return nil, nil
+	}
+
+	return state, nil
+}
+
+// LevelStatePath returns the path of the level state saved along with the character save file.
+func LevelStatePath(saveFilePath string) string {
+	return saveFilePath + ".level"
+}
@@ -0,0 +1,60 @@
+package d2mapengine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestLevelStateFile(t *testing.T) {
+	assert := testify.New(t)
+
+	dir, err := ioutil.TempDir("", "levelstate")
+	assert.NoError(err)
+
+	defer os.RemoveAll(dir)
+
+	path := LevelStatePath(filepath.Join(dir, "hero.od2"))
+
+	state, err := LoadLevelState(path)
+	assert.NoError(err)
+	assert.Nil(state, "there is no state before one is saved")
+
+	saved := &LevelState{
+		Seed:     42,
+		Saved:    time.Now(),
+		Monsters: []MonsterState{{Index: 3, X: 10, Y: 12.5, Life: 0}},
+		Items:    []ItemState{{ID: "id", Code: "gld", Gold: 100, X: 5, Y: 6, Quality: d2enum.ItemQualityNormal}},
+		Objects:  []ObjectState{{X: 20, Y: 30}},
+	}
+	assert.NoError(SaveLevelState(path, saved))
+
+	state, err = LoadLevelState(path)
+	assert.NoError(err)
+	assert.Equal(saved.Monsters, state.Monsters)
+	assert.Equal(saved.Items, state.Items)
+	assert.Equal(saved.Objects, state.Objects)
+
+	saved.Saved = time.Now().Add(-LevelStateLifetime - time.Second)
+	assert.NoError(SaveLevelState(path, saved))
+
+	state, err = LoadLevelState(path)
+	assert.NoError(err)
+	assert.Nil(state, "an expired state can't be restored")
+}
+
+func TestRestoreStateSeed(t *testing.T) {
+	assert := testify.New(t)
+
+	engine := CreateMapEngine()
+	engine.SetSeed(1)
+
+	assert.Error(engine.RestoreState(&LevelState{Seed: 2}))
+	assert.NoError(engine.RestoreState(&LevelState{Seed: 1}))
+}
@@ -0,0 +1,23 @@
+package d2mapengine
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2common"
+
+// SetLevelBounds records the tiles the level with the levels.txt ID was generated on.
+func (m *MapEngine) SetLevelBounds(levelID int, bounds d2common.Rectangle) {
+	if m.levels == nil {
+		m.levels = make(map[int]d2common.Rectangle)
+	}
+
+	m.levels[levelID] = bounds
+}
+
+// LevelCenter returns the tile at the center of the level with the levels.txt ID, false if the level isn't part of
+// the map.
+func (m *MapEngine) LevelCenter(levelID int) (tileX, tileY float64, found bool) {
+	bounds, found := m.levels[levelID]
+	if !found {
+		return 0, 0, false
+	}
+
+	return float64(bounds.Left) + float64(bounds.Width)/2, float64(bounds.Top) + float64(bounds.Height)/2, true
+}
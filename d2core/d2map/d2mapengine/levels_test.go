@@ -0,0 +1,30 @@
+package d2mapengine
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestLevelCenter(t *testing.T) {
+	assert := testify.New(t)
+
+	m := &MapEngine{}
+	m.SetLevelBounds(1, d2common.Rectangle{Left: 0, Top: 0, Width: 40, Height: 20})
+	m.SetLevelBounds(2, d2common.Rectangle{Left: 40, Top: 10, Width: 60, Height: 30})
+
+	x, y, found := m.LevelCenter(1)
+	assert.True(found)
+	assert.Equal(20.0, x)
+	assert.Equal(10.0, y)
+
+	x, y, found = m.LevelCenter(2)
+	assert.True(found)
+	assert.Equal(70.0, x, "each level has its own position")
+	assert.Equal(25.0, y)
+
+	_, _, found = m.LevelCenter(3)
+	assert.False(found, "the level isn't part of the map")
+}
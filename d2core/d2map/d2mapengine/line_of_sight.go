@@ -0,0 +1,98 @@
+package d2mapengine
+
+import (
+	"math"
+)
+
+const (
+	// samplesPerSubTile is how many points of a line are checked for each sub tile it crosses.
+	samplesPerSubTile = 2
+
+	// soundRayOffset is how far the rays around the direct line between a sound and its listener are, in tiles.
+	soundRayOffset = 0.5
+
+	// occludedSoundVolume is the volume of a sound behind walls, as a fraction of its volume in the open.
+	occludedSoundVolume = 0.35
+
+	// occludedSoundRange is the distance in tiles past which a sound fully behind walls isn't played at all.
+	occludedSoundRange = 15.0
+)
+
+// HasLineOfSight returns true if no sub tile between the two world positions blocks the sight. The sub tiles of the
+// two positions themselves aren't checked.
+func (m *MapEngine) HasLineOfSight(fromX, fromY, toX, toY float64) bool {
+	if m.sightBlocked == nil {
+		return true
+	}
+
+	fromSubTileX, fromSubTileY := int(math.Floor(fromX*5)), int(math.Floor(fromY*5))
+	toSubTileX, toSubTileY := int(math.Floor(toX*5)), int(math.Floor(toY*5))
+
+	samples := int(math.Ceil(math.Hypot(toX-fromX, toY-fromY) * 5 * samplesPerSubTile))
+
+	for sample := 1; sample < samples; sample++ {
+		progress := float64(sample) / float64(samples)
+		subTileX := int(math.Floor((fromX + (toX-fromX)*progress) * 5))
+		subTileY := int(math.Floor((fromY + (toY-fromY)*progress) * 5))
+
+		if (subTileX == fromSubTileX && subTileY == fromSubTileY) || (subTileX == toSubTileX && subTileY == toSubTileY) {
+			continue
+		}
+
+		if m.blocksSight(subTileX, subTileY) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SoundOcclusion returns how much of a sound is blocked by walls on its way to the listener, from 0 for none of it to
+// 1 for all of it. Only a few rays are cast: the direct line, and one on each side of it.
+func (m *MapEngine) SoundOcclusion(emitterX, emitterY, listenerX, listenerY float64) float64 {
+	distance := math.Hypot(emitterX-listenerX, emitterY-listenerY)
+	if distance == 0 {
+		return 0
+	}
+
+	// perpendicular to the direct line, soundRayOffset long
+	offsetX := -(emitterY - listenerY) / distance * soundRayOffset
+	offsetY := (emitterX - listenerX) / distance * soundRayOffset
+
+	blocked := 0
+	rays := []float64{0, -1, 1}
+
+	for _, side := range rays {
+		if !m.HasLineOfSight(listenerX, listenerY, emitterX+offsetX*side, emitterY+offsetY*side) {
+			blocked++
+		}
+	}
+
+	return float64(blocked) / float64(len(rays))
+}
+
+// SoundVolume returns the volume of a sound at the emitter's position as heard by the listener, as a fraction of its
+// volume in the open, muffled by the walls between them. Returns false if the sound is fully behind walls and too far
+// away to be worth playing.
+func (m *MapEngine) SoundVolume(emitterX, emitterY, listenerX, listenerY float64) (volume float64, audible bool) {
+	occlusion := m.SoundOcclusion(emitterX, emitterY, listenerX, listenerY)
+
+	if occlusion == 1 && math.Hypot(emitterX-listenerX, emitterY-listenerY) > occludedSoundRange {
+		return 0, false
+	}
+
+	return 1 - occlusion*(1-occludedSoundVolume), true
+}
+
+// blocksSight returns true if the sub tile can't be seen through, or is off the map.
+func (m *MapEngine) blocksSight(subTileX, subTileY int) bool {
+	subTilesWide := m.size.Width * 5
+
+	if subTileX < 0 || subTileY < 0 || subTileX >= subTilesWide || subTileY >= m.size.Height*5 {
+		return true
+	}
+
+	index := subTileX + (subTileY * subTilesWide)
+
+	return index >= len(m.sightBlocked) || m.sightBlocked[index]
+}
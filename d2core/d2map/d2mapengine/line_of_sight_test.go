@@ -0,0 +1,59 @@
+package d2mapengine
+
+import (
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+// walledMap returns a map 6 tiles wide with a wall across the middle, but for the sub tiles from gapFrom down.
+func walledMap(gapFrom int) *MapEngine {
+	m := openMap(6, 6)
+	m.sightBlocked = make([]bool, len(m.walkMesh))
+
+	for y := 0; y < gapFrom; y++ {
+		m.sightBlocked[15+y*30] = true
+	}
+
+	return m
+}
+
+func TestHasLineOfSight(t *testing.T) {
+	assert := testify.New(t)
+
+	m := walledMap(30)
+
+	assert.True(m.HasLineOfSight(1, 1, 2.5, 5), "both on the same side of the wall")
+	assert.False(m.HasLineOfSight(1, 1, 5, 1), "across the wall")
+	assert.False(m.HasLineOfSight(1, 5, 5, 1), "diagonally across the wall")
+	assert.True(m.HasLineOfSight(3.1, 1, 5, 1), "from the wall itself")
+
+	m = walledMap(20)
+	assert.True(m.HasLineOfSight(1, 5, 5, 5), "through the gap")
+}
+
+func TestSoundVolume(t *testing.T) {
+	assert := testify.New(t)
+
+	m := walledMap(30)
+
+	volume, audible := m.SoundVolume(2, 3, 1, 3)
+	assert.True(audible)
+	assert.Equal(1.0, volume, "nothing between the sound and the listener")
+
+	volume, audible = m.SoundVolume(5, 3, 1, 3)
+	assert.True(audible)
+	assert.InDelta(occludedSoundVolume, volume, 1e-9, "muffled by the wall")
+
+	m = walledMap(27)
+	assert.InDelta(2.0/3, m.SoundOcclusion(5, 5.2, 1, 5.2), 1e-9, "one ray goes through the gap")
+
+	m = openMap(40, 1)
+	m.sightBlocked = make([]bool, len(m.walkMesh))
+	for y := 0; y < 5; y++ {
+		m.sightBlocked[50+y*200] = true
+	}
+
+	_, audible = m.SoundVolume(35, 0.5, 1, 0.5)
+	assert.False(audible, "too far behind the wall to be heard")
+}
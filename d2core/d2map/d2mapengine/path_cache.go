@@ -0,0 +1,144 @@
+package d2mapengine
+
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
+)
+
+const (
+	// DefaultPathRegionSize is the size of the regions sharing paths, in tiles.
+	DefaultPathRegionSize = 1.0
+
+	// DefaultPathGoalTolerance is how far a goal can move, in tiles, before the paths to it are found again.
+	DefaultPathGoalTolerance = 0.5
+
+	maxCachedPaths = 1024
+)
+
+// PathCache finds paths on a map and reuses them for entities starting from the same region of the map and heading
+// to the same goal, such as many monsters chasing a player standing still.
+//
+// A cached path is reused when the start is on the path or next to it, so entities following each other share one
+// path. A path is found again once the goal moves further than the tolerance from where it was, and every path is
+// found again once the walk mesh changes.
+type PathCache struct {
+	engine        *MapEngine
+	regionSize    float64 // size of the regions sharing paths, in tiles
+	goalTolerance float64 // how far the goal can move before the paths to it are found again, in tiles
+	meshVersion   int     // version of the walk mesh the paths were found on
+	paths         map[pathCacheKey]*cachedPath
+}
+
+type pathCacheKey struct {
+	startRegionX, startRegionY int
+	goalRegionX, goalRegionY   int
+}
+
+type cachedPath struct {
+	goalX, goalY float64
+	path         []d2astar.Pather // starting with the sub tile the path was found from
+	found        bool
+}
+
+// CreatePathCache creates a path cache for the map, with regions of regionSize tiles sharing paths to goals which
+// moved no more than goalTolerance tiles.
+func CreatePathCache(engine *MapEngine, regionSize, goalTolerance float64) *PathCache {
+	return &PathCache{
+		engine:        engine,
+		regionSize:    regionSize,
+		goalTolerance: goalTolerance,
+		meshVersion:   engine.meshVersion,
+		paths:         make(map[pathCacheKey]*cachedPath),
+	}
+}
+
+// PathFind finds a walkable path between two points like MapEngine.PathFind, reusing a cached path when possible.
+func (c *PathCache) PathFind(startX, startY, endX, endY float64) (path []d2astar.Pather, distance float64, found bool) {
+	if c.meshVersion != c.engine.meshVersion {
+		c.Clear()
+	}
+
+	startNode, ok := c.engine.pathTileAt(startX, startY)
+	if !ok {
+		return
+	}
+
+	key := pathCacheKey{c.region(startX), c.region(startY), c.region(endX), c.region(endY)}
+
+	if cached, ok := c.paths[key]; ok && math.Hypot(cached.goalX-endX, cached.goalY-endY) <= c.goalTolerance {
+		if path, ok := cached.from(startNode); ok {
+			return path, pathDistance(startNode, path), cached.found
+		}
+	}
+
+	endNode, ok := c.engine.pathTileAt(endX, endY)
+	if !ok {
+		return
+	}
+
+	fullPath, distance, found := c.engine.findPath(startNode, endNode)
+
+	if len(c.paths) >= maxCachedPaths {
+		c.Clear()
+	}
+
+	c.paths[key] = &cachedPath{goalX: endX, goalY: endY, path: fullPath, found: found}
+
+	return append([]d2astar.Pather(nil), fullPath[1:]...), distance, found
+}
+
+// CachedPathFind finds a walkable path between two points like PathFind, sharing the paths of the map's path cache
+// with the other entities starting from the same region of the map and heading to the same goal.
+func (m *MapEngine) CachedPathFind(startX, startY, endX, endY float64) (path []d2astar.Pather, distance float64,
+	found bool) {
+	if m.pathCache == nil {
+		m.pathCache = CreatePathCache(m, DefaultPathRegionSize, DefaultPathGoalTolerance)
+	}
+
+	return m.pathCache.PathFind(startX, startY, endX, endY)
+}
+
+// Clear forgets every cached path.
+func (c *PathCache) Clear() {
+	c.paths = make(map[pathCacheKey]*cachedPath)
+	c.meshVersion = c.engine.meshVersion
+}
+
+func (c *PathCache) region(position float64) int {
+	return int(math.Floor(position / c.regionSize))
+}
+
+// from returns the rest of the path for an entity standing on the start sub tile, if the sub tile is on the path or
+// next to it.
+func (p *cachedPath) from(start *d2common.PathTile) ([]d2astar.Pather, bool) {
+	neighbors := start.PathNeighbors()
+
+	// search from the goal back, to join the path as close to the goal as possible
+	for index := len(p.path) - 1; index >= 0; index-- {
+		if p.path[index] == start {
+			return append([]d2astar.Pather(nil), p.path[index+1:]...), true
+		}
+
+		for _, neighbor := range neighbors {
+			if p.path[index] == neighbor {
+				return append([]d2astar.Pather(nil), p.path[index:]...), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// pathDistance returns the cost of walking the path from the start.
+func pathDistance(start d2astar.Pather, path []d2astar.Pather) float64 {
+	distance := 0.0
+
+	for _, step := range path {
+		distance += start.PathNeighborCost(step)
+		start = step
+	}
+
+	return distance
+}
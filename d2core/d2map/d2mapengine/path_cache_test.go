@@ -0,0 +1,129 @@
+package d2mapengine
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+// openMap returns a map engine with every sub tile of the map walkable.
+func openMap(width, height int) *MapEngine {
+	m := &MapEngine{
+		size:     d2common.Size{Width: width, Height: height},
+		tiles:    make([]d2ds1.TileRecord, width*height),
+		walkMesh: make([]d2common.PathTile, width*height*25),
+	}
+
+	for index := range m.tiles {
+		m.tiles[index].Floors = make([]d2ds1.FloorShadowRecord, 1)
+	}
+
+	for index := range m.walkMesh {
+		m.walkMesh[index] = d2common.PathTile{
+			Walkable: true,
+			X:        float64(index%(width*5)) / 5,
+			Y:        float64(index/(width*5)) / 5,
+		}
+	}
+
+	m.linkWalkMesh()
+
+	return m
+}
+
+func TestPathCacheReusesPaths(t *testing.T) {
+	assert := testify.New(t)
+
+	m := openMap(10, 3)
+	cache := CreatePathCache(m, DefaultPathRegionSize, DefaultPathGoalTolerance)
+
+	path, _, found := cache.PathFind(0.1, 1.1, 8.1, 1.1)
+	assert.True(found)
+	assert.Len(path, 40)
+	assert.Len(cache.paths, 1)
+
+	// a pursuer one sub tile along the path joins it
+	followerPath, distance, found := cache.PathFind(0.3, 1.1, 8.1, 1.1)
+	assert.True(found)
+	assert.Len(followerPath, 39)
+	assert.Equal(float64(39), distance)
+	assert.Equal(path[1:], followerPath)
+	assert.Len(cache.paths, 1, "the path was reused")
+
+	_, _, found = cache.PathFind(0.1, 1.1, 8.3, 1.1)
+	assert.True(found)
+	assert.Len(cache.paths, 1, "the goal didn't move far enough to find the path again")
+	assert.Equal(8.1, cache.paths[pathCacheKey{0, 1, 8, 1}].goalX)
+
+	path, _, _ = cache.PathFind(0.1, 1.1, 8.9, 1.1)
+	assert.Len(path, 44)
+	assert.Equal(8.9, cache.paths[pathCacheKey{0, 1, 8, 1}].goalX, "the goal moved too far")
+
+	followerPath, _, _ = cache.PathFind(0.1, 1.1, 8.9, 1.1)
+	followerPath[0] = nil
+	assert.NotNil(cache.paths[pathCacheKey{0, 1, 8, 1}].path[1], "callers get a copy of the cached path")
+}
+
+func TestPathCacheWalkMeshChanges(t *testing.T) {
+	assert := testify.New(t)
+
+	m := openMap(3, 1)
+	cache := CreatePathCache(m, DefaultPathRegionSize, DefaultPathGoalTolerance)
+
+	path, _, _ := cache.PathFind(0.1, 0.1, 2.1, 0.1)
+	assert.Len(path, 10)
+
+	// wall off the middle of the map
+	for y := 0; y < 5; y++ {
+		m.walkMesh[7+y*15].Walkable = false
+	}
+
+	m.linkWalkMesh()
+
+	_, _, found := cache.PathFind(0.1, 0.1, 2.1, 0.1)
+	assert.False(found, "the cached path goes through the wall")
+}
+
+func TestCachedPathFind(t *testing.T) {
+	assert := testify.New(t)
+
+	m := openMap(10, 3)
+
+	path, _, found := m.CachedPathFind(0.1, 1.1, 8.1, 1.1)
+	assert.True(found)
+	assert.Len(path, 40)
+
+	followerPath, _, found := m.CachedPathFind(0.3, 1.1, 8.1, 1.1)
+	assert.True(found)
+	assert.Equal(path[1:], followerPath)
+	assert.Len(m.pathCache.paths, 1, "the map shares the path between its entities")
+}
+
+func BenchmarkPathFindPursuers(b *testing.B) {
+	m := openMap(20, 20)
+
+	for n := 0; n < b.N; n++ {
+		for pursuer := 0; pursuer < 200; pursuer++ {
+			m.PathFind(pursuerPosition(pursuer))
+		}
+	}
+}
+
+func BenchmarkPathCachePursuers(b *testing.B) {
+	m := openMap(20, 20)
+	cache := CreatePathCache(m, DefaultPathRegionSize, DefaultPathGoalTolerance)
+
+	for n := 0; n < b.N; n++ {
+		for pursuer := 0; pursuer < 200; pursuer++ {
+			cache.PathFind(pursuerPosition(pursuer))
+		}
+	}
+}
+
+// pursuerPosition returns the position of one of a pack of pursuers, and of the player they chase.
+func pursuerPosition(pursuer int) (startX, startY, endX, endY float64) {
+	return 2 + float64(pursuer%20)*0.2, 2 + float64(pursuer/20)*0.2, 12.5, 12.5
+}
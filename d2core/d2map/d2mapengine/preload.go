@@ -0,0 +1,78 @@
+package d2mapengine
+
+import (
+	"log"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+// Preload decodes the animations the map will need during the loading screen, so they don't hitch when they first
+// appear: the monsters placed on the map and those which spawn in its levels, and the missiles given, such as the
+// hero's skills. The tiles are already loaded with the map. The progress callback, if any, is given the ratio of the
+// assets preloaded so far. Assets which fail to load are logged and skipped.
+func (m *MapEngine) Preload(missiles []int, progress func(ratio float64)) {
+	monsters := m.expectedMonsters()
+	total := len(monsters) + len(missiles)
+
+	report := func(done int) {
+		if progress != nil && total > 0 {
+			progress(float64(done) / float64(total))
+		}
+	}
+
+	for idx, monstat := range monsters {
+		if err := d2mapentity.PreloadMonster(monstat); err != nil {
+			log.Printf("failed to preload monster %s: %v", monstat.Key, err)
+		}
+
+		report(idx + 1)
+	}
+
+	for idx, missileID := range missiles {
+		if missile, ok := d2datadict.Missiles[missileID]; ok {
+			if err := d2mapentity.PreloadMissile(missile); err != nil {
+				log.Printf("failed to preload missile %d: %v", missileID, err)
+			}
+		}
+
+		report(len(monsters) + idx + 1)
+	}
+}
+
+// expectedMonsters returns the monsters placed on the map, and those which spawn in the levels of its level type in
+// normal difficulty.
+func (m *MapEngine) expectedMonsters() []*d2datadict.MonStatsRecord {
+	keys := make([]string, 0)
+
+	for _, entity := range m.entities {
+		if npc, ok := entity.(*d2mapentity.NPC); ok {
+			keys = append(keys, npc.MonsterKey())
+		}
+	}
+
+	for _, level := range d2datadict.LevelDetails {
+		if level.LevelType != m.levelType.ID {
+			continue
+		}
+
+		keys = append(keys, level.MonsterID1Normal, level.MonsterID2Normal, level.MonsterID3Normal,
+			level.MonsterID4Normal, level.MonsterID5Normal, level.MonsterID6Normal, level.MonsterID7Normal,
+			level.MonsterID8Normal, level.MonsterID9Normal, level.MonsterID10Normal)
+	}
+
+	seen := make(map[string]bool)
+	monsters := make([]*d2datadict.MonStatsRecord, 0)
+
+	for _, key := range keys {
+		monstat, ok := d2datadict.MonStats[key]
+		if !ok || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		monsters = append(monsters, monstat)
+	}
+
+	return monsters
+}
@@ -0,0 +1,152 @@
+package d2mapengine
+
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+const (
+	// separationRate is the part of the overlap between two entities resolved per second, so they drift apart over a
+	// few ticks rather than snapping.
+	separationRate = 8.0
+
+	// maxSeparationSpeed caps how fast, in tiles per second, an entity is pushed, keeping a push below the movement
+	// sample step per tick so it can't skip over a wall.
+	maxSeparationSpeed = 2.0
+)
+
+// separable is an entity with a body which other separable entities are pushed out of.
+type separable interface {
+	d2interface.MapEntity
+	CollisionRadius() float64
+	Nudge(dx, dy float64)
+}
+
+// separatedEntity is an entity taking part in the separation of a tick, with the push accumulated for it.
+type separatedEntity struct {
+	entity       separable
+	x, y, radius float64
+	pushX, pushY float64
+	immovable    bool
+}
+
+// advanceSeparation pushes apart the monsters and players overlapping each other, so monsters don't stack on the
+// same spot. The pushes of a tick are accumulated before being applied, so the order of the entities doesn't matter,
+// and are clamped against the walk mesh. Players aren't pushed, the monsters they walk into make way instead.
+func (m *MapEngine) advanceSeparation(tickTime float64) {
+	entities, maxRadius := m.separatedEntities()
+	if len(entities) < 2 {
+		return
+	}
+
+	// the cells are at least as wide as the largest overlap distance, so overlapping entities are in neighbouring cells
+	cellSize := math.Max(1, maxRadius*2)
+	cells := make(map[[2]int][]int)
+
+	for idx := range entities {
+		cell := separationCell(&entities[idx], cellSize)
+		cells[cell] = append(cells[cell], idx)
+	}
+
+	resolved := math.Min(1, tickTime*separationRate)
+
+	for idx := range entities {
+		cell := separationCell(&entities[idx], cellSize)
+
+		for offsetY := -1; offsetY <= 1; offsetY++ {
+			for offsetX := -1; offsetX <= 1; offsetX++ {
+				for _, other := range cells[[2]int{cell[0] + offsetX, cell[1] + offsetY}] {
+					if other > idx {
+						separatePair(&entities[idx], &entities[other], resolved)
+					}
+				}
+			}
+		}
+	}
+
+	maxPush := maxSeparationSpeed * tickTime
+
+	for idx := range entities {
+		entity := &entities[idx]
+		if entity.pushX == 0 && entity.pushY == 0 {
+			continue
+		}
+
+		if length := math.Hypot(entity.pushX, entity.pushY); length > maxPush {
+			entity.pushX *= maxPush / length
+			entity.pushY *= maxPush / length
+		}
+
+		x, y := m.MoveWithCollision(entity.x, entity.y, entity.pushX, entity.pushY, true)
+		if x != entity.x || y != entity.y {
+			entity.entity.Nudge(x-entity.x, y-entity.y)
+		}
+	}
+}
+
+// separatedEntities returns the living entities on the map with a body, and the largest of their collision radii.
+func (m *MapEngine) separatedEntities() (entities []separatedEntity, maxRadius float64) {
+	for _, entity := range m.entities {
+		body, ok := entity.(separable)
+		if !ok {
+			continue
+		}
+
+		// corpses don't take up room
+		if npc, isNPC := entity.(*d2mapentity.NPC); isNPC && npc.IsDead() {
+			continue
+		}
+
+		_, isPlayer := entity.(*d2mapentity.Player)
+		x, y := body.GetPositionF()
+		radius := body.CollisionRadius()
+		maxRadius = math.Max(maxRadius, radius)
+
+		entities = append(entities, separatedEntity{entity: body, x: x, y: y, radius: radius, immovable: isPlayer})
+	}
+
+	return entities, maxRadius
+}
+
+// separationCell returns the grid cell the entity is in.
+func separationCell(entity *separatedEntity, cellSize float64) [2]int {
+	return [2]int{int(math.Floor(entity.x / cellSize)), int(math.Floor(entity.y / cellSize))}
+}
+
+// separatePair adds the pushes resolving the given part of the overlap between the two entities, shared between them
+// unless one of them can't be pushed. Entities on the exact same spot are pushed apart along the world X axis.
+func separatePair(first, second *separatedEntity, resolved float64) {
+	if first.immovable && second.immovable {
+		return
+	}
+
+	dx, dy := second.x-first.x, second.y-first.y
+	distance := math.Hypot(dx, dy)
+
+	overlap := first.radius + second.radius - distance
+	if overlap <= 0 {
+		return
+	}
+
+	dirX, dirY := 1.0, 0.0
+	if distance > 0 {
+		dirX, dirY = dx/distance, dy/distance
+	}
+
+	push := overlap * resolved
+	firstShare, secondShare := 0.5, 0.5
+
+	switch {
+	case first.immovable:
+		firstShare, secondShare = 0, 1
+	case second.immovable:
+		firstShare, secondShare = 1, 0
+	}
+
+	first.pushX -= dirX * push * firstShare
+	first.pushY -= dirY * push * firstShare
+	second.pushX += dirX * push * secondShare
+	second.pushY += dirY * push * secondShare
+}
@@ -0,0 +1,58 @@
+package d2mapengine
+
+import (
+	"math"
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+type testBody struct {
+	testEntity
+	x, y float64
+}
+
+func (b *testBody) GetPositionF() (float64, float64) { return b.x, b.y }
+func (b *testBody) CollisionRadius() float64         { return 0.2 }
+func (b *testBody) Nudge(dx, dy float64)             { b.x, b.y = b.x+dx, b.y+dy }
+
+func TestSeparation(t *testing.T) {
+	assert := testify.New(t)
+
+	m := cornerMesh()
+	first, second := &testBody{x: 0.5, y: 0.5}, &testBody{x: 0.5, y: 0.5}
+	m.AddEntity(first)
+	m.AddEntity(second)
+
+	m.advanceSeparation(0.04)
+	assert.Less(first.x, second.x, "bodies on the same spot are pushed apart")
+	assert.LessOrEqual(second.x-first.x, 2*maxSeparationSpeed*0.04+1e-9, "pushes are gradual")
+
+	for tick := 0; tick < 100; tick++ {
+		m.advanceSeparation(0.04)
+	}
+
+	assert.InDelta(0.4, math.Hypot(second.x-first.x, second.y-first.y), 0.01, "bodies end up at their radii")
+
+	x, y := second.x, second.y
+	m.advanceSeparation(0.04)
+	assert.InDelta(x, second.x, 0.001, "separated bodies stay put")
+	assert.InDelta(y, second.y, 0.001)
+}
+
+func TestSeparationWalls(t *testing.T) {
+	assert := testify.New(t)
+
+	// a wall along the right column of sub tiles
+	m := cornerMesh([2]int{4, 0}, [2]int{4, 1}, [2]int{4, 2}, [2]int{4, 3}, [2]int{4, 4})
+	first, second := &testBody{x: 0.7, y: 0.5}, &testBody{x: 0.75, y: 0.5}
+	m.AddEntity(first)
+	m.AddEntity(second)
+
+	for tick := 0; tick < 100; tick++ {
+		m.advanceSeparation(0.04)
+		assert.True(m.IsWalkable(second.x, second.y), "bodies aren't pushed into walls")
+	}
+
+	assert.Less(first.x, 0.5, "the body against the wall makes the other one give way")
+}
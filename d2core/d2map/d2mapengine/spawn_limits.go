@@ -0,0 +1,206 @@
+package d2mapengine
+
+import (
+	"log"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+// spawnCategory groups the spawned entities capped together.
+type spawnCategory int
+
+const (
+	spawnCategoryOther spawnCategory = iota
+	spawnCategoryMonster
+	spawnCategoryMissile
+)
+
+func (c spawnCategory) String() string {
+	switch c {
+	case spawnCategoryMonster:
+		return "monsters"
+	case spawnCategoryMissile:
+		return "missiles"
+	default:
+		return "entities"
+	}
+}
+
+func categoryOf(entity d2interface.MapEntity) spawnCategory {
+	switch entity.(type) {
+	case *d2mapentity.NPC:
+		return spawnCategoryMonster
+	case *d2mapentity.Missile:
+		return spawnCategoryMissile
+	default:
+		return spawnCategoryOther
+	}
+}
+
+// spawnLimits caps the entities spawned on the map while playing, keeping them in the order they were spawned.
+type spawnLimits struct {
+	limits     d2config.EntityLimits
+	difficulty d2enum.Difficulty
+	spawned    []d2interface.MapEntity
+	counts     map[spawnCategory]int
+	queued     []d2interface.MapEntity
+	throttling bool // whether throttling was logged since the spawns were last under the caps
+}
+
+// SetEntityLimits sets the caps on the entities spawned on the map, for the difficulty.
+func (m *MapEngine) SetEntityLimits(limits d2config.EntityLimits, difficulty d2enum.Difficulty) {
+	m.spawnLimits().limits = limits
+	m.spawnLimits().difficulty = difficulty
+}
+
+// Spawn adds an entity spawned while playing, such as a summon or a missile, to the map, unless the spawned entities
+// are at their caps. Past a cap, the entity is either queued until spawned entities are removed, or the oldest
+// spawned entity of the kind is removed to make room, depending on the limits. Queued entities past the cap of the
+// queue are dropped. Returns true if the entity was added to the map right away.
+func (m *MapEngine) Spawn(entity d2interface.MapEntity) bool {
+	limits := m.spawnLimits()
+	category := categoryOf(entity)
+
+	if limits.hasRoom(category) {
+		m.addSpawned(entity, category)
+		return true
+	}
+
+	if !limits.throttling {
+		log.Printf("too many %s spawned, throttling spawns", category)
+
+		limits.throttling = true
+	}
+
+	if limits.limits.GetThrottle() == d2config.SpawnThrottleDespawnOldest {
+		for !limits.hasRoom(category) {
+			m.RemoveEntity(limits.oldest(category))
+		}
+
+		m.addSpawned(entity, category)
+
+		return true
+	}
+
+	if len(limits.queued) < limits.cap(category) {
+		limits.queued = append(limits.queued, entity)
+	}
+
+	return false
+}
+
+// QueuedSpawns returns the number of spawned entities waiting for room on the map.
+func (m *MapEngine) QueuedSpawns() int {
+	return len(m.spawnLimits().queued)
+}
+
+// spawnLimits returns the caps on spawned entities, creating them with the defaults the first time.
+func (m *MapEngine) spawnLimits() *spawnLimits {
+	if m.spawns == nil {
+		m.spawns = &spawnLimits{counts: make(map[spawnCategory]int)}
+	}
+
+	return m.spawns
+}
+
+func (m *MapEngine) addSpawned(entity d2interface.MapEntity, category spawnCategory) {
+	m.spawns.spawned = append(m.spawns.spawned, entity)
+	m.spawns.counts[category]++
+	m.AddEntity(entity)
+}
+
+// removeSpawned forgets an entity removed from the map, if it was spawned or queued.
+func (m *MapEngine) removeSpawned(entity d2interface.MapEntity) {
+	if m.spawns == nil {
+		return
+	}
+
+	for idx := range m.spawns.spawned {
+		if m.spawns.spawned[idx] == entity {
+			m.spawns.spawned = append(m.spawns.spawned[:idx], m.spawns.spawned[idx+1:]...)
+			m.spawns.counts[categoryOf(entity)]--
+
+			return
+		}
+	}
+
+	for idx := range m.spawns.queued {
+		if m.spawns.queued[idx] == entity {
+			m.spawns.queued = append(m.spawns.queued[:idx], m.spawns.queued[idx+1:]...)
+			return
+		}
+	}
+}
+
+// advanceSpawns adds the queued entities there is room for again, in the order they were spawned.
+func (m *MapEngine) advanceSpawns() {
+	if m.spawns == nil {
+		return
+	}
+
+	for len(m.spawns.queued) > 0 {
+		entity := m.spawns.queued[0]
+		category := categoryOf(entity)
+
+		if !m.spawns.hasRoom(category) {
+			return
+		}
+
+		m.spawns.queued = m.spawns.queued[1:]
+		m.addSpawned(entity, category)
+	}
+
+	if m.spawns.throttling && m.spawns.hasRoom(spawnCategoryMonster) && m.spawns.hasRoom(spawnCategoryMissile) {
+		log.Print("spawns are under their caps again")
+
+		m.spawns.throttling = false
+	}
+}
+
+// clear forgets the spawned and queued entities, keeping the caps.
+func (s *spawnLimits) clear() {
+	s.spawned = nil
+	s.counts = make(map[spawnCategory]int)
+	s.queued = nil
+	s.throttling = false
+}
+
+// hasRoom returns true if another entity of the category can be spawned.
+func (s *spawnLimits) hasRoom(category spawnCategory) bool {
+	if len(s.spawned) >= s.limits.GetTotal(s.difficulty) {
+		return false
+	}
+
+	return category == spawnCategoryOther || s.counts[category] < s.cap(category)
+}
+
+// cap returns the cap on spawned entities of the category, which is the total cap for other entities.
+func (s *spawnLimits) cap(category spawnCategory) int {
+	switch category {
+	case spawnCategoryMonster:
+		return s.limits.GetMonsters(s.difficulty)
+	case spawnCategoryMissile:
+		return s.limits.GetMissiles(s.difficulty)
+	default:
+		return s.limits.GetTotal(s.difficulty)
+	}
+}
+
+// oldest returns the entity to despawn to make room for one of the category: the oldest of the category, unless the
+// category has room and the total cap was reached, in which case the oldest of any category.
+func (s *spawnLimits) oldest(category spawnCategory) d2interface.MapEntity {
+	if category == spawnCategoryOther || s.counts[category] < s.cap(category) {
+		return s.spawned[0]
+	}
+
+	for _, entity := range s.spawned {
+		if categoryOf(entity) == category {
+			return entity
+		}
+	}
+
+	return s.spawned[0]
+}
@@ -0,0 +1,76 @@
+package d2mapengine
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+type testEntity struct {
+	d2interface.MapEntity
+}
+
+func spawnEntities(m *MapEngine, count int) []d2interface.MapEntity {
+	entities := make([]d2interface.MapEntity, count)
+
+	for idx := range entities {
+		entities[idx] = &testEntity{}
+		m.Spawn(entities[idx])
+	}
+
+	return entities
+}
+
+func TestSpawnQueue(t *testing.T) {
+	assert := testify.New(t)
+
+	m := CreateMapEngine()
+	m.SetEntityLimits(d2config.EntityLimits{Total: 2}, d2enum.DifficultyNormal)
+
+	entities := spawnEntities(m, 4)
+	assert.Equal(entities[:2], m.entities)
+	assert.Equal(2, m.QueuedSpawns())
+
+	m.RemoveEntity(entities[0])
+	assert.Equal(entities[1:2], m.entities, "queued entities wait for the next tick")
+
+	m.advanceSpawns()
+	assert.Equal(entities[1:3], m.entities)
+	assert.Equal(1, m.QueuedSpawns())
+
+	m.RemoveEntity(entities[3])
+	assert.Equal(0, m.QueuedSpawns(), "removing a queued entity drops it from the queue")
+
+	spawnEntities(m, 3)
+	assert.Equal(2, m.QueuedSpawns(), "the queue is capped too")
+}
+
+func TestSpawnDespawnOldest(t *testing.T) {
+	assert := testify.New(t)
+
+	m := CreateMapEngine()
+	m.AddEntity(&testEntity{})
+
+	limits := d2config.EntityLimits{Total: 2}
+	assert.NoError(limits.SetThrottle(d2config.SpawnThrottleDespawnOldest))
+	m.SetEntityLimits(limits, d2enum.DifficultyNormal)
+
+	entities := spawnEntities(m, 4)
+	assert.Len(m.entities, 3, "entities placed with the level aren't counted")
+	assert.Equal(entities[2:], m.entities[1:])
+	assert.Equal(0, m.QueuedSpawns())
+}
+
+func TestSpawnLimitsDifficulty(t *testing.T) {
+	assert := testify.New(t)
+
+	m := CreateMapEngine()
+	m.SetEntityLimits(d2config.EntityLimits{Total: 4}, d2enum.DifficultyHell)
+
+	spawnEntities(m, 8)
+	assert.Len(m.entities, 6, "hell allows 50% more entities")
+}
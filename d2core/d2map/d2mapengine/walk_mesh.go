@@ -8,72 +8,160 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 )
 
-// RegenerateWalkPaths based on current tile data.
-func (m *MapEngine) RegenerateWalkPaths() {
-	for subTileY := 0; subTileY < m.size.Height*5; subTileY++ {
-		tileY := int(float64(subTileY) / 5.0)
+// CornerCutting is the rule deciding when a path can move diagonally past the corner of a blocked sub tile.
+//
+// The stricter the rule, the more natural paths look around walls, but the more tight spots, such as a gap between
+// two diagonally adjacent pillars, become impassable. The looser the rule, the more paths clip through the corners of
+// walls and objects.
+type CornerCutting int
 
-		for subTileX := 0; subTileX < m.size.Width*5; subTileX++ {
-			tileX := int(float64(subTileX) / 5.0)
-			tile := m.TileAt(tileX, tileY)
-			isBlocked := false
+// Corner cutting rules
+const (
+	// CornerCuttingRelaxed allows a diagonal step if at least one of the two sub tiles it passes between is walkable.
+	// Paths may clip the corner of a wall, but can't squeeze between two blocked sub tiles. This is how Diablo II
+	// behaves, and the default.
+	CornerCuttingRelaxed CornerCutting = iota
 
-			for _, floor := range tile.Floors {
-				tileData := m.GetTileData(int32(floor.Style), int32(floor.Sequence), d2enum.TileFloor)
-				if tileData == nil {
-					continue
-				}
+	// CornerCuttingStrict allows a diagonal step only if both sub tiles it passes between are walkable. Paths never
+	// touch the corner of a wall, at the cost of being longer around obstacles.
+	CornerCuttingStrict
 
-				tileSubAttributes := tileData.GetSubTileFlags(subTileX%5, subTileY%5)
+	// CornerCuttingFree allows any diagonal step onto a walkable sub tile. Paths are the shortest, but can slip
+	// through diagonal gaps in walls.
+	CornerCuttingFree
+)
 
-				isBlocked = isBlocked || tileSubAttributes.BlockWalk
-				if isBlocked {
-					break
-				}
-			}
+// allowsDiagonal returns true if the rule allows a diagonal step between two sub tiles, given whether the two sub
+// tiles beside the step are walkable.
+func (c CornerCutting) allowsDiagonal(sideAWalkable, sideBWalkable bool) bool {
+	switch c {
+	case CornerCuttingStrict:
+		return sideAWalkable && sideBWalkable
+	case CornerCuttingFree:
+		return true
+	default:
+		return sideAWalkable || sideBWalkable
+	}
+}
 
-			if !isBlocked {
-				for _, wall := range tile.Walls {
-					tileData := m.GetTileData(int32(wall.Style), int32(wall.Sequence), wall.Type)
-					if tileData == nil {
-						continue
-					}
+// SetCornerCutting sets the rule deciding when paths can move diagonally past blocked sub tiles, and relinks the walk
+// mesh with it.
+func (m *MapEngine) SetCornerCutting(cornerCutting CornerCutting) {
+	m.cornerCutting = cornerCutting
+	m.linkWalkMesh()
+}
 
-					tileSubAttributes := tileData.GetSubTileFlags(subTileX%5, subTileY%5)
+// CornerCutting returns the rule deciding when paths can move diagonally past blocked sub tiles.
+func (m *MapEngine) CornerCutting() CornerCutting {
+	return m.cornerCutting
+}
 
-					isBlocked = isBlocked || tileSubAttributes.BlockWalk
-					if isBlocked {
-						break
-					}
-				}
-			}
+// RegenerateWalkPaths based on current tile data, and the objects blocking the sub tiles they stand on.
+func (m *MapEngine) RegenerateWalkPaths() {
+	m.sightBlocked = make([]bool, len(m.walkMesh))
 
+	for subTileY := 0; subTileY < m.size.Height*5; subTileY++ {
+		for subTileX := 0; subTileX < m.size.Width*5; subTileX++ {
 			index := subTileX + (subTileY * m.size.Width * 5)
+			blocksWalk, blocksSight := m.subTileBlocks(subTileX, subTileY)
+
 			m.walkMesh[index] = d2common.PathTile{
-				Walkable: !isBlocked,
+				Walkable: !blocksWalk,
 				X:        float64(subTileX) / 5.0,
 				Y:        float64(subTileY) / 5.0,
 			}
+			m.sightBlocked[index] = blocksSight
+		}
+	}
+
+	m.applyColliders(d2common.Rectangle{Width: m.size.Width * 5, Height: m.size.Height * 5})
+	m.linkWalkMesh()
+}
+
+// subTileBlocks returns whether a floor or wall of the tile blocks walking on the sub tile, and seeing through it.
+func (m *MapEngine) subTileBlocks(subTileX, subTileY int) (blocksWalk, blocksSight bool) {
+	tile, ok := m.TileAt(subTileX/5, subTileY/5)
+	if !ok {
+		return true, true // tiles which aren't loaded can't be walked on
+	}
+
+	for _, floor := range tile.Floors {
+		if tileData := m.GetTileData(int32(floor.Style), int32(floor.Sequence), d2enum.TileFloor); tileData != nil {
+			flags := tileData.GetSubTileFlags(subTileX%5, subTileY%5)
+			blocksWalk = blocksWalk || flags.BlockWalk
+			blocksSight = blocksSight || flags.BlockLOS
+		}
+	}
+
+	for _, wall := range tile.Walls {
+		if tileData := m.GetTileData(int32(wall.Style), int32(wall.Sequence), wall.Type); tileData != nil {
+			flags := tileData.GetSubTileFlags(subTileX%5, subTileY%5)
+			blocksWalk = blocksWalk || flags.BlockWalk
+			blocksSight = blocksSight || flags.BlockLOS
+		}
+	}
+
+	return blocksWalk, blocksSight
+}
+
+// subTileWalkable returns true if no floor or wall of the tile blocks walking on the sub tile. Unlike IsWalkable, it
+// doesn't need the walk mesh, which is only built once the whole map is placed.
+func (m *MapEngine) subTileWalkable(subTileX, subTileY int) bool {
+	blocksWalk, _ := m.subTileBlocks(subTileX, subTileY)
+	return !blocksWalk
+}
+
+// linkWalkMesh links every walkable sub tile of the walk mesh to the walkable sub tiles around it, following the
+// corner cutting rule for the diagonals.
+func (m *MapEngine) linkWalkMesh() {
+	m.meshVersion++
+
+	subTilesWide := m.size.Width * 5
+	subTilesHigh := m.size.Height * 5
+
+	walkable := func(subTileX, subTileY int) bool {
+		if subTileX < 0 || subTileY < 0 || subTileX >= subTilesWide || subTileY >= subTilesHigh {
+			return false
+		}
+
+		return m.walkMesh[subTileX+(subTileY*subTilesWide)].Walkable
+	}
+
+	for index := range m.walkMesh {
+		tile := &m.walkMesh[index]
+		tile.Up, tile.Down, tile.Left, tile.Right = nil, nil, nil, nil
+		tile.UpLeft, tile.UpRight, tile.DownLeft, tile.DownRight = nil, nil, nil, nil
+	}
+
+	for subTileY := 0; subTileY < subTilesHigh; subTileY++ {
+		for subTileX := 0; subTileX < subTilesWide; subTileX++ {
+			if !walkable(subTileX, subTileY) {
+				continue
+			}
+
+			index := subTileX + (subTileY * subTilesWide)
+			tile := &m.walkMesh[index]
 
-			ySkew := m.size.Width * 5
-			if !isBlocked && subTileY > 0 && m.walkMesh[index-ySkew].Walkable {
-				m.walkMesh[index].Up = &m.walkMesh[index-ySkew]
-				m.walkMesh[index-ySkew].Down = &m.walkMesh[index]
+			if walkable(subTileX, subTileY-1) {
+				tile.Up = &m.walkMesh[index-subTilesWide]
+				tile.Up.Down = tile
 			}
 
-			if !isBlocked && subTileX > 0 && m.walkMesh[index-1].Walkable {
-				m.walkMesh[index].Left = &m.walkMesh[index-1]
-				m.walkMesh[index-1].Right = &m.walkMesh[index]
+			if walkable(subTileX-1, subTileY) {
+				tile.Left = &m.walkMesh[index-1]
+				tile.Left.Right = tile
 			}
 
-			if !isBlocked && subTileX > 0 && subTileY > 0 && m.walkMesh[(index-ySkew)-1].Walkable {
-				m.walkMesh[index].UpLeft = &m.walkMesh[(index-ySkew)-1]
-				m.walkMesh[(index-ySkew)-1].DownRight = &m.walkMesh[index]
+			if walkable(subTileX-1, subTileY-1) &&
+				m.cornerCutting.allowsDiagonal(walkable(subTileX-1, subTileY), walkable(subTileX, subTileY-1)) {
+				tile.UpLeft = &m.walkMesh[index-subTilesWide-1]
+				tile.UpLeft.DownRight = tile
 			}
 
-			if !isBlocked && subTileY > 0 && subTileX < (m.size.Width*5)-1 && m.walkMesh[(index-ySkew)+1].Walkable {
-				m.walkMesh[index].UpRight = &m.walkMesh[(index-ySkew)+1]
-				m.walkMesh[(index-ySkew)+1].DownLeft = &m.walkMesh[index]
+			if walkable(subTileX+1, subTileY-1) &&
+				m.cornerCutting.allowsDiagonal(walkable(subTileX+1, subTileY), walkable(subTileX, subTileY-1)) {
+				tile.UpRight = &m.walkMesh[index-subTilesWide+1]
+				tile.UpRight.DownLeft = tile
 			}
 		}
 	}
@@ -81,50 +169,110 @@ func (m *MapEngine) RegenerateWalkPaths() {
 
 // PathFind finds a walkable path between two points.
 func (m *MapEngine) PathFind(startX, startY, endX, endY float64) (path []d2astar.Pather, distance float64, found bool) {
-	startTileX := int(math.Floor(startX))
-	startTileY := int(math.Floor(startY))
-
-	if !m.TileExists(startTileX, startTileY) {
+	startNode, ok := m.pathTileAt(startX, startY)
+	if !ok {
 		return
 	}
 
-	startSubtileX := int((startX - float64(int(startX))) * 5)
-	startSubtileY := int((startY - float64(int(startY))) * 5)
-	startNodeIndex := ((startSubtileY + (startTileY * 5)) * m.size.Width * 5) + startSubtileX + ((startTileX) * 5)
-
-	if startNodeIndex < 0 || startNodeIndex >= len(m.walkMesh) {
+	endNode, ok := m.pathTileAt(endX, endY)
+	if !ok {
 		return
 	}
 
-	startNode := &m.walkMesh[startNodeIndex]
+	path, distance, found = m.findPath(startNode, endNode)
+	if path != nil {
+		path = path[1:]
+	}
 
-	endTileX := int(math.Floor(endX))
-	endTileY := int(math.Floor(endY))
+	return
+}
 
-	if !m.TileExists(endTileX, endTileY) {
-		return
+// pathTileAt returns the walk mesh sub tile containing the given world position, if it is on a tile of the map.
+func (m *MapEngine) pathTileAt(x, y float64) (*d2common.PathTile, bool) {
+	tileX := int(math.Floor(x))
+	tileY := int(math.Floor(y))
+
+	if !m.TileExists(tileX, tileY) {
+		return nil, false
 	}
 
-	endSubtileX := int((endX - float64(int(endX))) * 5)
-	endSubtileY := int((endY - float64(int(endY))) * 5)
+	subtileX := int((x - float64(int(x))) * 5)
+	subtileY := int((y - float64(int(y))) * 5)
+	nodeIndex := ((subtileY + (tileY * 5)) * m.size.Width * 5) + subtileX + ((tileX) * 5)
 
-	endNodeIndex := ((endSubtileY + (endTileY * 5)) * m.size.Width * 5) + endSubtileX + ((endTileX) * 5)
-	if endNodeIndex < 0 || endNodeIndex >= len(m.walkMesh) {
-		return
+	if nodeIndex < 0 || nodeIndex >= len(m.walkMesh) {
+		return nil, false
 	}
 
-	endNode := &m.walkMesh[endNodeIndex]
+	return &m.walkMesh[nodeIndex], true
+}
 
+// findPath finds a walkable path between two sub tiles, starting with the start sub tile. If the end can't be reached,
+// the path leads to the closest sub tile found instead.
+func (m *MapEngine) findPath(startNode, endNode *d2common.PathTile) (path []d2astar.Pather, distance float64,
+	found bool) {
 	path, distance, found = d2astar.Path(startNode, endNode, 80)
-	if path != nil {
-		// Reverse the path to fit what the game expects.
-		for i := len(path)/2 - 1; i >= 0; i-- {
-			opp := len(path) - 1 - i
-			path[i], path[opp] = path[opp], path[i]
-		}
 
-		path = path[1:]
+	// Reverse the path to fit what the game expects.
+	for i := len(path)/2 - 1; i >= 0; i-- {
+		opp := len(path) - 1 - i
+		path[i], path[opp] = path[opp], path[i]
 	}
 
-	return
+	return path, distance, found
+}
+
+// IsWalkable returns true if the sub tile containing the given world position exists and is not blocked.
+func (m *MapEngine) IsWalkable(x, y float64) bool {
+	subTileX := int(math.Floor(x * 5))
+	subTileY := int(math.Floor(y * 5))
+	subTilesWide := m.size.Width * 5
+
+	if subTileX < 0 || subTileY < 0 || subTileX >= subTilesWide || subTileY >= m.size.Height*5 {
+		return false
+	}
+
+	index := subTileX + (subTileY * subTilesWide)
+	if index >= len(m.walkMesh) {
+		return false
+	}
+
+	return m.walkMesh[index].Walkable
+}
+
+// NearestWalkable returns the center of the walkable sub tile closest to the given world position, which is first
+// clamped to the map bounds. Only sub tiles within maxRadius sub tiles are searched. Returns false if none is found.
+func (m *MapEngine) NearestWalkable(x, y float64, maxRadius int) (walkableX, walkableY float64, found bool) {
+	subTilesWide := m.size.Width * 5
+	subTilesHigh := m.size.Height * 5
+
+	if subTilesWide == 0 || subTilesHigh == 0 {
+		return 0, 0, false
+	}
+
+	originX := d2common.MaxInt(0, d2common.MinInt(subTilesWide-1, int(math.Floor(x*5))))
+	originY := d2common.MaxInt(0, d2common.MinInt(subTilesHigh-1, int(math.Floor(y*5))))
+
+	// Search square rings of increasing radius around the origin
+	for radius := 0; radius <= maxRadius; radius++ {
+		for offsetY := -radius; offsetY <= radius; offsetY++ {
+			for offsetX := -radius; offsetX <= radius; offsetX++ {
+				onRing := offsetX == -radius || offsetX == radius || offsetY == -radius || offsetY == radius
+				if !onRing {
+					continue
+				}
+
+				subTileX, subTileY := originX+offsetX, originY+offsetY
+				if subTileX < 0 || subTileY < 0 || subTileX >= subTilesWide || subTileY >= subTilesHigh {
+					continue
+				}
+
+				if m.walkMesh[subTileX+(subTileY*subTilesWide)].Walkable {
+					return (float64(subTileX) + 0.5) / 5, (float64(subTileY) + 0.5) / 5, true
+				}
+			}
+		}
+	}
+
+	return 0, 0, false
 }
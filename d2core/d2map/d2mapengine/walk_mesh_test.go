@@ -0,0 +1,95 @@
+package d2mapengine
+
+import (
+	"math"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+// cornerMesh returns a map engine with a single tile whose walk mesh has the given sub tiles blocked.
+func cornerMesh(blocked ...[2]int) *MapEngine {
+	m := &MapEngine{
+		size:     d2common.Size{Width: 1, Height: 1},
+		walkMesh: make([]d2common.PathTile, 25),
+	}
+
+	for index := range m.walkMesh {
+		m.walkMesh[index].Walkable = true
+	}
+
+	for _, subTile := range blocked {
+		m.walkMesh[subTile[0]+subTile[1]*5].Walkable = false
+	}
+
+	return m
+}
+
+func TestCornerCutting(t *testing.T) {
+	assert := testify.New(t)
+
+	oneCorner := cornerMesh([2]int{1, 0})
+	bothCorners := cornerMesh([2]int{1, 0}, [2]int{0, 1})
+
+	tests := []struct {
+		cornerCutting          CornerCutting
+		pastOneCorner, between bool
+	}{
+		{CornerCuttingStrict, false, false},
+		{CornerCuttingRelaxed, true, false},
+		{CornerCuttingFree, true, true},
+	}
+
+	for _, test := range tests {
+		oneCorner.SetCornerCutting(test.cornerCutting)
+		bothCorners.SetCornerCutting(test.cornerCutting)
+
+		rule := test.cornerCutting
+
+		assert.Equal(test.pastOneCorner, oneCorner.walkMesh[0].DownRight != nil, "rule %d, past one corner", rule)
+		assert.Equal(test.pastOneCorner, oneCorner.walkMesh[6].UpLeft != nil, "rule %d, past one corner", rule)
+		assert.Equal(test.between, bothCorners.walkMesh[0].DownRight != nil, "rule %d, between corners", rule)
+		assert.Equal(test.between, bothCorners.walkMesh[6].UpLeft != nil, "rule %d, between corners", rule)
+	}
+
+	assert.NotNil(bothCorners.walkMesh[6].Down, "straight steps don't depend on the rule")
+	assert.Nil(bothCorners.walkMesh[0].Right, "blocked sub tiles are never linked")
+	assert.Equal(CornerCuttingRelaxed, (&MapEngine{}).CornerCutting(), "the default rule is Diablo II's")
+}
+
+func TestPathFindPrefersStraightSteps(t *testing.T) {
+	assert := testify.New(t)
+
+	m := openMap(3, 1)
+
+	diagonalSteps := func(path []d2astar.Pather) int {
+		diagonals := 0
+		from := &m.walkMesh[0]
+
+		for _, step := range path {
+			to := step.(*d2common.PathTile)
+			if to.X != from.X && to.Y != from.Y {
+				diagonals++
+			}
+
+			from = to
+		}
+
+		return diagonals
+	}
+
+	path, distance, found := m.PathFind(0, 0, 2, 0)
+	assert.True(found)
+	assert.Len(path, 10)
+	assert.Equal(0, diagonalSteps(path), "a straight line doesn't zig-zag")
+	assert.Equal(10.0, distance)
+
+	path, distance, found = m.PathFind(0, 0, 2, 0.6)
+	assert.True(found)
+	assert.Len(path, 10)
+	assert.Equal(3, diagonalSteps(path), "only the diagonal steps needed to get level")
+	assert.InDelta(7+3*math.Sqrt2, distance, 1e-9)
+}
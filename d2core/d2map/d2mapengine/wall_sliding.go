@@ -0,0 +1,50 @@
+package d2mapengine
+
+import (
+	"math"
+)
+
+// moveSampleStep is the distance in tiles between the points of a movement checked for obstacles, half a sub tile so
+// a movement can't skip over a blocked sub tile.
+const moveSampleStep = 0.1
+
+// MoveWithCollision returns where a movement of the given world space vector from the position ends on the walk
+// mesh. A movement running into an obstacle stops where it is, unless slide is set, in which case it keeps the part of
+// the movement along the world axis which isn't blocked, sliding along walls rather than stopping at them.
+func (m *MapEngine) MoveWithCollision(x, y, dx, dy float64, slide bool) (float64, float64) {
+	if m.canMove(x, y, dx, dy) {
+		return x + dx, y + dy
+	}
+
+	if !slide {
+		return x, y
+	}
+
+	// try the axis with the most of the movement first, so a hero moving mostly along a wall keeps going along it
+	axes := [][2]float64{{dx, 0}, {0, dy}}
+	if math.Abs(dy) > math.Abs(dx) {
+		axes[0], axes[1] = axes[1], axes[0]
+	}
+
+	for _, axis := range axes {
+		if (axis[0] != 0 || axis[1] != 0) && m.canMove(x, y, axis[0], axis[1]) {
+			return x + axis[0], y + axis[1]
+		}
+	}
+
+	return x, y
+}
+
+// canMove returns true if every point along the movement from the position is walkable.
+func (m *MapEngine) canMove(x, y, dx, dy float64) bool {
+	samples := int(math.Ceil(math.Hypot(dx, dy) / moveSampleStep))
+
+	for sample := 1; sample <= samples; sample++ {
+		progress := float64(sample) / float64(samples)
+		if !m.IsWalkable(x+dx*progress, y+dy*progress) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,28 @@
+package d2mapengine
+
+import (
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestMoveWithCollision(t *testing.T) {
+	assert := testify.New(t)
+
+	// a wall along the right column of sub tiles
+	m := cornerMesh([2]int{4, 0}, [2]int{4, 1}, [2]int{4, 2}, [2]int{4, 3}, [2]int{4, 4})
+
+	x, y := m.MoveWithCollision(0.3, 0.3, 0.2, 0.1, false)
+	assert.InDelta(0.5, x, 1e-9, "movements in the open aren't changed")
+	assert.InDelta(0.4, y, 1e-9)
+
+	x, y = m.MoveWithCollision(0.7, 0.3, 0.2, 0.1, false)
+	assert.Equal([2]float64{0.7, 0.3}, [2]float64{x, y}, "without sliding, the hero stops at the wall")
+
+	x, y = m.MoveWithCollision(0.7, 0.3, 0.2, 0.1, true)
+	assert.InDelta(0.7, x, 1e-9, "the hero slides along the wall")
+	assert.InDelta(0.4, y, 1e-9)
+
+	x, y = m.MoveWithCollision(0.7, 0.3, 0.2, 0, true)
+	assert.Equal([2]float64{0.7, 0.3}, [2]float64{x, y}, "moving straight into the wall stops")
+}
@@ -0,0 +1,105 @@
+package d2mapengine
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"hash/fnv"
+	"sort"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+// WorldHash is a hash of the gameplay state of a map at one tick, by subsystem. The clients of a level compare their
+// hashes to find the first tick, and the subsystem, where their worlds diverged. The JSON names are short as the hash
+// is sent often.
+type WorldHash struct {
+	Tick      uint64 `json:"t"`
+	RNG       uint64 `json:"r"` // Seed of the map
+	Positions uint64 `json:"p"` // Names and positions of every entity
+	Combat    uint64 `json:"c"` // Life, mana and experience of the players, and life of the monsters
+	Inventory uint64 `json:"i"` // Equipment and gold of the players, and the items on the ground
+}
+
+// Diverged returns the name of the first subsystem whose hash differs from the other's, or an empty string if none
+// does. The subsystems are compared in the order a desync spreads through them: random rolls move entities, which
+// fight, which drop and pick up items. The ticks aren't compared.
+//
+// The state of the global math/rand source the game rolls from can't be read, so the RNG subsystem only covers the
+// seed of the map. A diverged roll shows up in the subsystems it affected instead.
+func (h WorldHash) Diverged(other WorldHash) string {
+	switch {
+	case h.RNG != other.RNG:
+		return "rng"
+	case h.Positions != other.Positions:
+		return "positions"
+	case h.Combat != other.Combat:
+		return "combat"
+	case h.Inventory != other.Inventory:
+		return "inventory"
+	default:
+		return ""
+	}
+}
+
+// Tick returns the number of ticks the map was advanced since it was reset.
+func (m *MapEngine) Tick() uint64 {
+	return m.tick
+}
+
+// WorldHash hashes the gameplay state of the map at the current tick. The hash doesn't depend on the order the
+// entities were added to the map in.
+func (m *MapEngine) WorldHash() WorldHash {
+	var positions, combat, inventory []uint64
+
+	for _, entity := range m.entities {
+		x, y := entity.GetPositionF()
+		positions = append(positions, hashValues(entity.Name(), x, y))
+
+		switch e := entity.(type) {
+		case *d2mapentity.Player:
+			stats := e.Stats
+			combat = append(combat, hashValues(e.Id, stats.Health, stats.MaxHealth, stats.Mana, stats.MaxMana,
+				stats.Level, stats.Experience))
+			inventory = append(inventory, hashValues(e.Id, stats.Gold, e.Equipment))
+		case *d2mapentity.NPC:
+			combat = append(combat, hashValues(e.Name(), x, y, e.Life(), e.MaxLife()))
+		case *d2mapentity.GroundItem:
+			inventory = append(inventory, hashValues(e.ID(), x, y))
+		}
+	}
+
+	return WorldHash{
+		Tick:      m.tick,
+		RNG:       hashValues(m.seed),
+		Positions: combineHashes(positions),
+		Combat:    combineHashes(combat),
+		Inventory: combineHashes(inventory),
+	}
+}
+
+// hashValues hashes the values written as JSON, so only their exported state counts.
+func hashValues(values ...interface{}) uint64 {
+	hash := fnv.New64a()
+	encoder := json.NewEncoder(hash)
+
+	for _, value := range values {
+		_ = encoder.Encode(value) // writing to a hash never fails, and values which can't be encoded are left out
+	}
+
+	return hash.Sum64()
+}
+
+// combineHashes hashes the hashes regardless of their order.
+func combineHashes(hashes []uint64) uint64 {
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	hash := fnv.New64a()
+	buffer := make([]byte, 8)
+
+	for _, value := range hashes {
+		binary.LittleEndian.PutUint64(buffer, value)
+		_, _ = hash.Write(buffer)
+	}
+
+	return hash.Sum64()
+}
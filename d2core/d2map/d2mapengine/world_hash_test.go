@@ -0,0 +1,78 @@
+package d2mapengine
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+type positionedEntity struct {
+	d2interface.MapEntity
+	name string
+	x, y float64
+}
+
+func (e *positionedEntity) Name() string {
+	return e.name
+}
+
+func (e *positionedEntity) GetPositionF() (float64, float64) {
+	return e.x, e.y
+}
+
+func (e *positionedEntity) Advance(_ float64) {
+	e.x++
+}
+
+func TestWorldHashOrder(t *testing.T) {
+	assert := testify.New(t)
+
+	first, second := CreateMapEngine(), CreateMapEngine()
+
+	first.AddEntity(&positionedEntity{name: "fallen", x: 10, y: 20})
+	first.AddEntity(&positionedEntity{name: "zombie", x: 30, y: 40})
+	second.AddEntity(&positionedEntity{name: "zombie", x: 30, y: 40})
+	second.AddEntity(&positionedEntity{name: "fallen", x: 10, y: 20})
+
+	assert.Equal(first.WorldHash(), second.WorldHash())
+	assert.Empty(first.WorldHash().Diverged(second.WorldHash()))
+}
+
+func TestWorldHashDiverged(t *testing.T) {
+	assert := testify.New(t)
+
+	first, second := CreateMapEngine(), CreateMapEngine()
+	moved := &positionedEntity{name: "fallen", x: 10, y: 20}
+
+	first.AddEntity(&positionedEntity{name: "fallen", x: 10, y: 20})
+	second.AddEntity(moved)
+
+	moved.y += 0.1
+
+	assert.Equal("positions", first.WorldHash().Diverged(second.WorldHash()))
+
+	second.SetSeed(7)
+
+	assert.Equal("rng", first.WorldHash().Diverged(second.WorldHash()), "the first subsystem to diverge is reported")
+}
+
+func TestWorldHashTick(t *testing.T) {
+	assert := testify.New(t)
+
+	m := CreateMapEngine()
+	m.AddEntity(&positionedEntity{name: "fallen"})
+
+	before := m.WorldHash()
+
+	m.Advance(0.04)
+	m.Advance(0.04)
+
+	after := m.WorldHash()
+
+	assert.Equal(uint64(0), before.Tick)
+	assert.Equal(uint64(2), after.Tick)
+	assert.Equal(uint64(2), m.Tick())
+	assert.NotEqual(before.Positions, after.Positions)
+}
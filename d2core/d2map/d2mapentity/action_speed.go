@@ -0,0 +1,85 @@
+package d2mapentity
+
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+const (
+	// actionDiminishingFactor makes increased attack speed and faster cast rate have diminishing returns, like FHR.
+	actionDiminishingFactor = 120
+
+	// maxActionSpeedBonus caps how much faster, in percent, an attack or cast animation can play.
+	maxActionSpeedBonus = 75
+)
+
+// actionSpeedBonus returns the speed bonus the animation mode plays faster with: faster cast rate for the cast
+// animation, and increased attack speed for the attack, throw, kick and skill animations. Other modes aren't sped up.
+func actionSpeedBonus(mode d2enum.PlayerAnimationMode, attackSpeed, castSpeed int) int {
+	switch mode {
+	case d2enum.PlayerAnimationModeCast:
+		return castSpeed
+	case d2enum.PlayerAnimationModeAttack1, d2enum.PlayerAnimationModeAttack2, d2enum.PlayerAnimationModeThrow,
+		d2enum.PlayerAnimationModeKick, d2enum.PlayerAnimationModeSkill1, d2enum.PlayerAnimationModeSkill2,
+		d2enum.PlayerAnimationModeSkill3, d2enum.PlayerAnimationModeSkill4:
+		return attackSpeed
+	default:
+		return 0
+	}
+}
+
+// skillAnimationMode returns the animation mode of a skills.txt anim column, the cast animation if it isn't an attack
+// or cast animation.
+func skillAnimationMode(anim string) d2enum.PlayerAnimationMode {
+	for mode := d2enum.PlayerAnimationModeAttack1; mode <= d2enum.PlayerAnimationModeSkill4; mode++ {
+		if actionSpeedBonus(mode, 1, 1) != 0 && mode.String() == anim {
+			return mode
+		}
+	}
+
+	return d2enum.PlayerAnimationModeCast
+}
+
+// actionSpeed returns the speed an attack or cast animation with the base animation speed plays at, in 256ths of a
+// frame per frame, given the increased attack speed or faster cast rate. The bonus diminishes the same way as Diablo
+// II: floor(120 * bonus / (120 + bonus)), capped at +75%.
+func actionSpeed(animationSpeed, speedBonus int) int {
+	if animationSpeed <= 0 {
+		animationSpeed = animationSpeedBase
+	}
+
+	if speedBonus < 0 {
+		speedBonus = 0
+	}
+
+	effectiveBonus := actionDiminishingFactor * speedBonus / (actionDiminishingFactor + speedBonus)
+	if effectiveBonus > maxActionSpeedBonus {
+		effectiveBonus = maxActionSpeedBonus
+	}
+
+	return animationSpeed * (percent + effectiveBonus) / percent
+}
+
+// ActionFrames returns the number of frames an attack or cast animation with baseFrames frames lasts, played at the
+// base animation speed from the animation data, given the increased attack speed or faster cast rate. Like Diablo II
+// the animation skips frames, so only some bonuses (the breakpoints) shorten it.
+func ActionFrames(baseFrames, animationSpeed, speedBonus int) int {
+	if baseFrames <= 0 {
+		return 0
+	}
+
+	return speedUpFrames(baseFrames, actionSpeed(animationSpeed, speedBonus))
+}
+
+// ActionFrame returns the frame, counted from the start of the sped up animation, the action frame of the base
+// animation is reached on: when the attack hits or the missile is fired.
+func ActionFrame(baseActionFrame, animationSpeed, speedBonus int) int {
+	if baseActionFrame <= 0 {
+		return 0
+	}
+
+	speed := actionSpeed(animationSpeed, speedBonus)
+
+	return int(math.Ceil(float64(animationSpeedBase*baseActionFrame) / float64(speed)))
+}
@@ -0,0 +1,47 @@
+package d2mapentity
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestActionSpeedBonus(t *testing.T) {
+	assert := testify.New(t)
+
+	assert.Equal(20, actionSpeedBonus(d2enum.PlayerAnimationModeAttack1, 20, 40))
+	assert.Equal(20, actionSpeedBonus(d2enum.PlayerAnimationModeThrow, 20, 40))
+	assert.Equal(20, actionSpeedBonus(d2enum.PlayerAnimationModeSkill1, 20, 40))
+	assert.Equal(40, actionSpeedBonus(d2enum.PlayerAnimationModeCast, 20, 40))
+	assert.Zero(actionSpeedBonus(d2enum.PlayerAnimationModeWalk, 20, 40))
+}
+
+func TestSkillAnimationMode(t *testing.T) {
+	assert := testify.New(t)
+
+	assert.Equal(d2enum.PlayerAnimationModeAttack1, skillAnimationMode("A1"))
+	assert.Equal(d2enum.PlayerAnimationModeKick, skillAnimationMode("KK"))
+	assert.Equal(d2enum.PlayerAnimationModeCast, skillAnimationMode("SC"))
+	assert.Equal(d2enum.PlayerAnimationModeCast, skillAnimationMode("BL"), "blocking isn't a skill animation")
+	assert.Equal(d2enum.PlayerAnimationModeCast, skillAnimationMode(""))
+}
+
+func TestAttackSpeedBreakpoint(t *testing.T) {
+	assert := testify.New(t)
+
+	attackFrames := func(attackSpeed int) int {
+		return ActionFrames(16, animationSpeedBase, actionSpeedBonus(d2enum.PlayerAnimationModeAttack1, attackSpeed, 0))
+	}
+
+	assert.Equal(15, attackFrames(0))
+	assert.Equal(13, attackFrames(29), "below the breakpoint")
+	assert.Equal(12, attackFrames(30), "at the breakpoint")
+	assert.Equal(12, attackFrames(47), "below the next breakpoint")
+	assert.Equal(15, ActionFrames(16, animationSpeedBase,
+		actionSpeedBonus(d2enum.PlayerAnimationModeCast, 30, 0)), "attack speed doesn't speed up casting")
+
+	assert.Equal(9, ActionFrame(9, animationSpeedBase, 0))
+	assert.Equal(8, ActionFrame(9, animationSpeedBase, 30), "the action frame comes sooner")
+}
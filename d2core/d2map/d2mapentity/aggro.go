@@ -0,0 +1,96 @@
+package d2mapentity
+
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
+)
+
+const (
+	// leashTimeout is how many seconds a pursuing monster goes without seeing a target before it gives up.
+	leashTimeout = 5.0
+
+	// leashDistance is how far, in tiles, a pursuing monster strays from where it was aggroed before it gives up.
+	leashDistance = 25.0
+)
+
+// aggroState is the pursuit of an NPC, from where it was aggroed until it is back there.
+type aggroState struct {
+	originX, originY float64 // tile position the NPC was aggroed at
+	unseen           float64 // seconds since the NPC last saw a target
+	returning        bool    // whether the NPC gave up and walks back to the origin
+}
+
+// Aggro makes the NPC pursue its targets, remembering where it started from so it can be leashed back there. Aggroing
+// an NPC walking back to where it was aggroed does nothing until it is back.
+func (v *NPC) Aggro() {
+	if v.aggro != nil {
+		if !v.aggro.returning {
+			v.aggro.unseen = 0
+		}
+
+		return
+	}
+
+	x, y := v.GetPositionF()
+	v.aggro = &aggroState{originX: x, originY: y}
+}
+
+// IsAggroed returns true if the NPC is pursuing its targets.
+func (v *NPC) IsAggroed() bool {
+	return v.aggro != nil && !v.aggro.returning
+}
+
+// IsReturning returns true if the NPC gave up its pursuit and is walking back to where it was aggroed.
+func (v *NPC) IsReturning() bool {
+	return v.aggro != nil && v.aggro.returning
+}
+
+// AggroOrigin returns the tile position the NPC was aggroed at, or false if it isn't aggroed.
+func (v *NPC) AggroOrigin() (x, y float64, ok bool) {
+	if v.aggro == nil {
+		return 0, 0, false
+	}
+
+	return v.aggro.originX, v.aggro.originY, true
+}
+
+// AdvanceLeash counts the time the pursuing NPC went without seeing a target. Returns true once it hasn't seen one
+// for too long, or strayed too far from where it was aggroed, in which case it should be sent back with
+// ReturnToAggroOrigin.
+func (v *NPC) AdvanceLeash(tickTime float64, targetSeen bool) bool {
+	if !v.IsAggroed() {
+		return false
+	}
+
+	if targetSeen {
+		v.aggro.unseen = 0
+	} else {
+		v.aggro.unseen += tickTime
+	}
+
+	x, y := v.GetPositionF()
+
+	return v.aggro.unseen >= leashTimeout || math.Hypot(x-v.aggro.originX, y-v.aggro.originY) > leashDistance
+}
+
+// ReturnToAggroOrigin makes the NPC give up its pursuit and walk the path back to where it was aggroed, or straight
+// there if the path is empty. The NPC is idle again once it is back.
+func (v *NPC) ReturnToAggroOrigin(path []d2astar.Pather) {
+	if v.aggro == nil {
+		return
+	}
+
+	v.aggro.returning = true
+
+	backHome := func() {
+		v.aggro = nil
+	}
+
+	if len(path) > 0 {
+		v.SetPath(path, backHome)
+		return
+	}
+
+	v.SetTarget(v.aggro.originX*5, v.aggro.originY*5, backHome)
+}
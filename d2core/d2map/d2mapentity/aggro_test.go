@@ -0,0 +1,51 @@
+package d2mapentity
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestLeashDistance(t *testing.T) {
+	assert := testify.New(t)
+
+	npc := &NPC{}
+	npc.Position = d2vector.NewPosition(50, 50)
+	npc.Aggro()
+
+	npc.Position = d2vector.NewPosition(50+leashDistance*5, 50)
+	assert.False(npc.AdvanceLeash(0.04, false), "monsters at the leash distance keep pursuing")
+
+	npc.Position = d2vector.NewPosition(50+leashDistance*5+1, 50)
+	assert.True(npc.AdvanceLeash(0.04, true), "monsters past the leash distance give up, even seeing a target")
+
+	npc.ReturnToAggroOrigin(nil)
+	assert.True(npc.IsReturning())
+	assert.False(npc.AdvanceLeash(0.04, false), "returning monsters aren't leashed again")
+
+	npc.Aggro()
+	assert.True(npc.IsReturning(), "returning monsters can't be aggroed until they are back")
+
+	x, y, ok := npc.AggroOrigin()
+	assert.True(ok)
+	assert.Equal([2]float64{10, 10}, [2]float64{x, y}, "the origin is in tiles")
+}
+
+func TestLeashTimeout(t *testing.T) {
+	assert := testify.New(t)
+
+	npc := &NPC{}
+	npc.Position = d2vector.NewPosition(50, 50)
+	assert.False(npc.AdvanceLeash(leashTimeout, false), "idle monsters aren't leashed")
+
+	npc.Aggro()
+	assert.False(npc.AdvanceLeash(leashTimeout-1, false))
+	assert.False(npc.AdvanceLeash(1, true), "seeing a target resets the timeout")
+	assert.False(npc.AdvanceLeash(leashTimeout-1, false))
+
+	npc.Aggro()
+	assert.False(npc.AdvanceLeash(leashTimeout-1, false), "aggroing again resets the timeout")
+	assert.True(npc.AdvanceLeash(1, false), "monsters give up once they didn't see a target for the timeout")
+}
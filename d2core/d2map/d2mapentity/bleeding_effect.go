@@ -0,0 +1,40 @@
+package d2mapentity
+
+import "math"
+
+// IsBleeding returns true while open wounds deal damage to the entity.
+func (m *mapEntity) IsBleeding() bool {
+	return m.bleeding > 0
+}
+
+// Bleed makes the entity bleed the damage per second for the duration in seconds, as open wounds do. New wounds
+// restart the bleeding, keeping the highest damage.
+func (m *mapEntity) Bleed(rate, duration float64) {
+	if rate <= 0 || duration <= 0 {
+		return
+	}
+
+	m.bleedRate = math.Max(m.bleedRate, rate)
+	m.bleeding = duration
+}
+
+// advanceBleeding counts down the bleeding duration and returns the damage dealt over the tick. Unlike poison,
+// bleeding can kill.
+func (m *mapEntity) advanceBleeding(tickTime float64) int {
+	if !m.IsBleeding() {
+		return 0
+	}
+
+	elapsed := math.Min(tickTime, m.bleeding)
+	m.bleeding -= elapsed
+	m.bleedDamage += m.bleedRate * elapsed
+
+	damage := int(m.bleedDamage)
+	m.bleedDamage -= float64(damage)
+
+	if !m.IsBleeding() {
+		m.bleedRate, m.bleedDamage = 0, 0
+	}
+
+	return damage
+}
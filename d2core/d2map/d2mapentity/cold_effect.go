@@ -0,0 +1,129 @@
+package d2mapentity
+
+import (
+	"image/color"
+	"math"
+)
+
+const (
+	// defaultColdEffect is the percent speed change of a chilled entity, the same -50% Diablo II applies to players.
+	defaultColdEffect = -50
+)
+
+var (
+	chillColorMod  = color.RGBA{R: 0x80, G: 0x80, B: 0xff, A: 0xff}
+	freezeColorMod = color.RGBA{R: 0x50, G: 0x70, B: 0xff, A: 0xff}
+)
+
+// SetColdEffect sets the percent by which chill changes the entity's movement, animation and hit recovery speed, the
+// same as the monstats.txt coldeffect column. A cold effect of 0 makes the entity immune to chill and freeze.
+func (m *mapEntity) SetColdEffect(coldEffect int) {
+	m.coldEffect = coldEffect
+}
+
+// IsChilled returns true while the entity is slowed by cold damage.
+func (m *mapEntity) IsChilled() bool {
+	return m.chilled > 0
+}
+
+// IsFrozen returns true while the entity is frozen solid.
+func (m *mapEntity) IsFrozen() bool {
+	return m.frozen > 0
+}
+
+// Chill slows the entity for the given duration in seconds. Cold effects do not stack, a longer chill replaces the
+// current one and a shorter one is ignored. Returns false if the entity can't be chilled.
+func (m *mapEntity) Chill(duration float64) bool {
+	if m.coldEffect == 0 || duration <= 0 {
+		return false
+	}
+
+	if duration > m.chilled {
+		m.chilled = duration
+	}
+
+	m.updateTint()
+
+	return true
+}
+
+// Freeze stops the entity in its current pose for the given duration in seconds, interrupting its movement. A frozen
+// entity is chilled for the same duration, and taking damage doesn't break the freeze. Returns false if the entity
+// can't be frozen.
+func (m *mapEntity) Freeze(duration float64) bool {
+	if !m.Chill(duration) {
+		return false
+	}
+
+	if duration > m.frozen {
+		m.frozen = duration
+	}
+
+	m.hitRecovery = 0
+	m.path = nil
+	m.done = nil
+	m.Target.Copy(&m.Position.Vector)
+	m.updateTint()
+
+	return true
+}
+
+// coldSpeedMultiplier returns the factor applied to the entity's movement and animation speed by cold effects.
+func (m *mapEntity) coldSpeedMultiplier() float64 {
+	if m.IsFrozen() {
+		return 0
+	}
+
+	if !m.IsChilled() {
+		return 1
+	}
+
+	multiplier := float64(percent+m.coldEffect) / percent
+	if multiplier < 0 {
+		return 0
+	}
+
+	return multiplier
+}
+
+// coldColorMod returns the color the entity is tinted with by cold effects, or nil if it isn't chilled.
+func (m *mapEntity) coldColorMod() color.Color {
+	switch {
+	case m.IsFrozen():
+		return freezeColorMod
+	case m.IsChilled():
+		return chillColorMod
+	default:
+		return nil
+	}
+}
+
+// updateTint passes the tint of the hit flash, or else of the cold effects, to the entity's tinter.
+func (m *mapEntity) updateTint() {
+	if m.tinter == nil {
+		return
+	}
+
+	if m.IsFlashing() {
+		m.tinter(m.hitFlashColor)
+		return
+	}
+
+	m.tinter(m.coldColorMod())
+}
+
+// advanceColdEffects counts down the freeze and chill durations, removing the tint once they wear off. It returns true
+// on the tick the entity thaws.
+func (m *mapEntity) advanceColdEffects(tickTime float64) bool {
+	if !m.IsChilled() && !m.IsFrozen() {
+		return false
+	}
+
+	wasFrozen := m.IsFrozen()
+	m.frozen = math.Max(m.frozen-tickTime, 0)
+	m.chilled = math.Max(m.chilled-tickTime, 0)
+
+	m.updateTint()
+
+	return wasFrozen && !m.IsFrozen()
+}
@@ -0,0 +1,24 @@
+package d2mapentity
+
+const (
+	// playerCollisionRadius is the collision radius of the players in tiles, a body two sub tiles wide.
+	playerCollisionRadius = 0.2
+
+	// minCollisionRadius is the smallest collision radius of a monster in tiles, for monsters without a size.
+	minCollisionRadius = 0.1
+)
+
+// CollisionRadius returns the radius, in tiles, the NPC keeps other monsters and players at, from its size in sub
+// tiles.
+func (v *NPC) CollisionRadius() float64 {
+	if v.monstatEx == nil || v.monstatEx.SizeX <= 0 {
+		return minCollisionRadius
+	}
+
+	return float64(v.monstatEx.SizeX) / 5 / 2
+}
+
+// CollisionRadius returns the radius, in tiles, the player keeps monsters at.
+func (v *Player) CollisionRadius() float64 {
+	return playerCollisionRadius
+}
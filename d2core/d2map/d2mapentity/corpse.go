@@ -0,0 +1,92 @@
+package d2mapentity
+
+import (
+	"math"
+)
+
+const (
+	corpsePushDistance = 0.6 // how far, in tiles, a corpse slides
+	corpsePushTime     = 0.3 // how long, in seconds, a corpse slides before it settles
+	corpsePushStep     = 0.1 // distance in tiles between the points of the slide checked for obstacles
+
+	radiansPerDirection = 2 * math.Pi / 64 // the angle between two of the 64 entity directions
+	directionOffset     = 8                // the increments Vector.DirectionTo takes off the angle of a direction
+)
+
+// corpsePush is the slide of a corpse, from where the NPC died to where it settles, in sub tiles.
+type corpsePush struct {
+	fromX, fromY float64
+	toX, toY     float64
+	elapsed      float64 // seconds since the NPC died
+}
+
+// IsDead returns true once the NPC has no life left.
+func (v *NPC) IsDead() bool {
+	return v.maxLife > 0 && v.life == 0
+}
+
+// FacingVector returns the world space direction the NPC faces, of length 1.
+func (v *NPC) FacingVector() (x, y float64) {
+	angle := float64(v.composite.GetDirection()+directionOffset) * radiansPerDirection
+
+	return math.Cos(angle), math.Sin(angle)
+}
+
+// PushCorpse slides the dead NPC a short distance along the given world space direction, easing out until it settles.
+// The slide stops short of the first point along it which isn't walkable. Corpses pushed along no direction stay put.
+func (v *NPC) PushCorpse(dirX, dirY float64, isWalkable WalkableFunc) {
+	length := math.Hypot(dirX, dirY)
+	if length == 0 || isWalkable == nil {
+		return
+	}
+
+	dirX, dirY = dirX/length, dirY/length
+	x, y := v.GetPositionF()
+	distance := 0.0
+
+	for step := corpsePushStep; step <= corpsePushDistance+1e-9; step += corpsePushStep {
+		if !isWalkable(x+dirX*step, y+dirY*step) {
+			break
+		}
+
+		distance = step
+	}
+
+	if distance == 0 {
+		return
+	}
+
+	v.mapEntity.path = nil
+	v.done = nil
+	v.corpsePush = &corpsePush{
+		fromX: v.Position.X(),
+		fromY: v.Position.Y(),
+		toX:   v.Position.X() + dirX*distance*subTilesPerTile,
+		toY:   v.Position.Y() + dirY*distance*subTilesPerTile,
+	}
+}
+
+// IsCorpseSliding returns true while the corpse of the NPC slides before it settles.
+func (v *NPC) IsCorpseSliding() bool {
+	return v.corpsePush != nil
+}
+
+// advanceCorpsePush moves the sliding corpse along its slide.
+func (v *NPC) advanceCorpsePush(tickTime float64) {
+	push := v.corpsePush
+	if push == nil {
+		return
+	}
+
+	push.elapsed += tickTime
+	progress := math.Min(1, push.elapsed/corpsePushTime)
+	eased := 1 - (1-progress)*(1-progress)
+
+	x, y := push.fromX+(push.toX-push.fromX)*eased, push.fromY+(push.toY-push.fromY)*eased
+	v.Position.Set(x, y)
+	v.Target.Set(x, y)
+
+	if progress == 1 {
+		v.corpsePush = nil
+	}
+}
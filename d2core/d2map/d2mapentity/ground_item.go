@@ -0,0 +1,175 @@
+package d2mapentity
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+)
+
+// goldItemCode is the misc.txt code of gold.
+const goldItemCode = "gld"
+
+// GroundItem is an item, or a pile of gold, lying on the ground which the player can pick up.
+type GroundItem struct {
+	*AnimatedEntity
+	record  *d2datadict.ItemCommonRecord
+	gold    int
+	quality d2enum.ItemQuality
+	level   int
+
+	id        string  // ID the server gave the item, empty for items only this client knows
+	owner     string  // ID of the player the item is allocated to, empty if anyone can pick it up
+	allocated float64 // Seconds of allocation remaining
+}
+
+// CreateGroundItem drops the item with the armor.txt, weapons.txt or misc.txt code at the given sub tile position.
+func CreateGroundItem(x, y int, code string) (*GroundItem, error) {
+	record := d2datadict.CommonItems[code]
+	if record == nil {
+		return nil, fmt.Errorf("unknown item code %s", code)
+	}
+
+	animation, err := loadFlippyAnimation(record, d2resource.PaletteUnits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GroundItem{
+		AnimatedEntity: CreateAnimatedEntity(x, y, animation),
+		record:         record,
+		quality:        d2enum.ItemQualityNormal,
+		level:          record.Level,
+	}, nil
+}
+
+// CreateGroundGold drops a pile of the amount of gold at the given sub tile position.
+func CreateGroundGold(x, y, amount int) (*GroundItem, error) {
+	item, err := CreateGroundItem(x, y, goldItemCode)
+	if err != nil {
+		return nil, err
+	}
+
+	item.gold = amount
+
+	return item, nil
+}
+
+// loadFlippyAnimation loads the animation of the item falling to the ground, which stops on its last frame.
+func loadFlippyAnimation(record *d2datadict.ItemCommonRecord, palettePath string) (d2interface.Animation, error) {
+	animation, err := d2asset.LoadAnimation(fmt.Sprintf("/data/global/items/%s.dc6", record.FlippyFile), palettePath)
+	if err != nil {
+		return nil, err
+	}
+
+	animation.SetPlayLoop(false)
+	animation.PlayForward()
+
+	return animation, nil
+}
+
+// SetPalette reloads the item's animation with the palette of the level it is on.
+func (g *GroundItem) SetPalette(palettePath string) error {
+	animation, err := loadFlippyAnimation(g.record, palettePath)
+	if err != nil {
+		return err
+	}
+
+	g.animation = animation
+
+	return nil
+}
+
+// Record returns the armor.txt, weapons.txt or misc.txt record of the item.
+func (g *GroundItem) Record() *d2datadict.ItemCommonRecord {
+	return g.record
+}
+
+// Quality returns the quality of the item, normal unless it was changed.
+func (g *GroundItem) Quality() d2enum.ItemQuality {
+	return g.quality
+}
+
+// SetQuality changes the quality of the item.
+func (g *GroundItem) SetQuality(quality d2enum.ItemQuality) {
+	g.quality = quality
+}
+
+// Level returns the item level, the base level of the item type unless it was changed.
+func (g *GroundItem) Level() int {
+	return g.level
+}
+
+// SetLevel changes the item level.
+func (g *GroundItem) SetLevel(level int) {
+	g.level = level
+}
+
+// IsGold returns true if the item is a pile of gold.
+func (g *GroundItem) IsGold() bool {
+	return g.record.Code == goldItemCode
+}
+
+// Gold returns the amount of gold in the pile, 0 if the item isn't gold.
+func (g *GroundItem) Gold() int {
+	return g.gold
+}
+
+// Name returns the item's in-game name, or the amount of gold for gold.
+func (g *GroundItem) Name() string {
+	if g.IsGold() {
+		return fmt.Sprintf("%d Gold", g.gold)
+	}
+
+	return d2common.TranslateString(g.record.NameString)
+}
+
+// Selectable returns true, items can be picked up.
+func (g *GroundItem) Selectable() bool {
+	return true
+}
+
+// ID returns the ID the server gave the item, empty for items only this client knows about.
+func (g *GroundItem) ID() string {
+	return g.id
+}
+
+// SetID sets the ID the server gave the item.
+func (g *GroundItem) SetID(id string) {
+	g.id = id
+}
+
+// Allocate gives the item to the player for the number of seconds, during which no one else can pick it up.
+func (g *GroundItem) Allocate(playerID string, duration float64) {
+	g.owner = playerID
+	g.allocated = duration
+}
+
+// Owner returns the ID of the player the item is allocated to, empty if anyone can pick it up.
+func (g *GroundItem) Owner() string {
+	return g.owner
+}
+
+// CanPickUp returns true if the item isn't allocated to another player. The server has the final say.
+func (g *GroundItem) CanPickUp(playerID string) bool {
+	return g.owner == "" || g.owner == playerID
+}
+
+// Advance plays the item's animation and counts down its allocation.
+func (g *GroundItem) Advance(elapsed float64) {
+	g.AnimatedEntity.Advance(elapsed)
+
+	if g.owner == "" {
+		return
+	}
+
+	g.allocated -= elapsed
+	if g.allocated <= 0 {
+		g.owner = ""
+		g.allocated = 0
+	}
+}
@@ -0,0 +1,97 @@
+package d2mapentity
+
+// snapshotHistoryLength is the number of ticks of snapshots an entity keeps, bounding the memory of its history.
+const snapshotHistoryLength = 64
+
+// Snapshot is the state of an entity at the end of a tick, kept to interpolate the entity between ticks and to replay
+// what it did.
+type Snapshot struct {
+	Tick  uint64
+	X, Y  float64 // world position, in tiles
+	State string  // animation mode, such as WL for walking, empty if the entity has none
+}
+
+// snapshotHistory is a ring buffer of the latest snapshots of an entity, oldest first.
+type snapshotHistory struct {
+	snapshots [snapshotHistoryLength]Snapshot
+	start     int
+	count     int
+}
+
+// RecordSnapshot keeps the entity's current position and state as its snapshot of the tick, replacing the snapshot of
+// the oldest tick once the history is full. Recording a tick again replaces its snapshot, and recording an earlier
+// tick than the latest one, as when the map is reset, starts the history over.
+func (m *mapEntity) RecordSnapshot(tick uint64) {
+	if m.history == nil {
+		m.history = &snapshotHistory{}
+	}
+
+	snapshot := Snapshot{Tick: tick}
+	snapshot.X, snapshot.Y = m.GetPositionF()
+
+	if m.stater != nil {
+		snapshot.State = m.stater()
+	}
+
+	m.history.record(snapshot)
+}
+
+// SampleAt returns the snapshot of the entity at time t, in ticks. Between two snapshots, the position is interpolated
+// and the tick and state are those of the earlier snapshot. Before the oldest snapshot or after the latest one, that
+// snapshot is returned. Returns false if no snapshot was recorded.
+func (m *mapEntity) SampleAt(t float64) (Snapshot, bool) {
+	if m.history == nil || m.history.count == 0 {
+		return Snapshot{}, false
+	}
+
+	return m.history.sample(t), true
+}
+
+// at returns the snapshot at the index from the oldest one.
+func (h *snapshotHistory) at(index int) *Snapshot {
+	return &h.snapshots[(h.start+index)%snapshotHistoryLength]
+}
+
+func (h *snapshotHistory) record(snapshot Snapshot) {
+	if h.count > 0 {
+		latest := h.at(h.count - 1)
+
+		switch {
+		case snapshot.Tick == latest.Tick:
+			*latest = snapshot
+			return
+		case snapshot.Tick < latest.Tick:
+			h.start, h.count = 0, 0
+		}
+	}
+
+	if h.count < snapshotHistoryLength {
+		h.count++
+	} else {
+		h.start = (h.start + 1) % snapshotHistoryLength
+	}
+
+	*h.at(h.count - 1) = snapshot
+}
+
+func (h *snapshotHistory) sample(t float64) Snapshot {
+	if oldest := h.at(0); t <= float64(oldest.Tick) {
+		return *oldest
+	}
+
+	for index := 1; index < h.count; index++ {
+		next := h.at(index)
+		if t >= float64(next.Tick) {
+			continue
+		}
+
+		previous := *h.at(index - 1)
+		progress := (t - float64(previous.Tick)) / float64(next.Tick-previous.Tick)
+		previous.X += (next.X - previous.X) * progress
+		previous.Y += (next.Y - previous.Y) * progress
+
+		return previous
+	}
+
+	return *h.at(h.count - 1)
+}
@@ -0,0 +1,52 @@
+package d2mapentity
+
+import (
+	"image/color"
+	"math"
+)
+
+// hitFlashDuration is how long, in seconds, an entity flashes when hit: three frames.
+const hitFlashDuration = 3 / framesPerSecond
+
+//nolint:gochecknoglobals // constant flash color
+var hitFlashColorMod = color.RGBA{R: 0xff, G: 0x50, B: 0x50, A: 0xff}
+
+// HitFlash makes the entity flash red for a few frames, as feedback for a hit, at the given intensity from 0 for no
+// flash to 1 for full strength. The flash overrides the cold effect tint while it lasts, which comes back once it ends.
+func (m *mapEntity) HitFlash(intensity float64) {
+	if intensity <= 0 {
+		return
+	}
+
+	intensity = math.Min(intensity, 1)
+	fade := func(component uint8) uint8 {
+		return uint8(math.Round(0xff - float64(0xff-component)*intensity))
+	}
+
+	m.hitFlash = hitFlashDuration
+	m.hitFlashColor = color.RGBA{
+		R: fade(hitFlashColorMod.R),
+		G: fade(hitFlashColorMod.G),
+		B: fade(hitFlashColorMod.B),
+		A: hitFlashColorMod.A,
+	}
+	m.updateTint()
+}
+
+// IsFlashing returns true while the entity flashes from a hit.
+func (m *mapEntity) IsFlashing() bool {
+	return m.hitFlash > 0
+}
+
+// advanceHitFlash counts down the hit flash, restoring the tint once it ends.
+func (m *mapEntity) advanceHitFlash(tickTime float64) {
+	if !m.IsFlashing() {
+		return
+	}
+
+	m.hitFlash = math.Max(m.hitFlash-tickTime, 0)
+
+	if !m.IsFlashing() {
+		m.updateTint()
+	}
+}
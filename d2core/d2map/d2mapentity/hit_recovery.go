@@ -0,0 +1,118 @@
+package d2mapentity
+
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
+)
+
+const (
+	// framesPerSecond is the rate at which Diablo II advances animation frames.
+	framesPerSecond = 25.0
+
+	// A player recovers from hits dealing more than 1/12 of their max life, a monster from more than 1/8.
+	playerHitRecoveryDivisor  = 12
+	monsterHitRecoveryDivisor = 8
+
+	fhrDiminishingFactor = 120 // faster hit recovery has diminishing returns, see HitRecoveryFrames
+	animationSpeedBase   = 256
+	percent              = 100
+
+	subTilesPerTile   = 5.0
+	knockbackDistance = subTilesPerTile // one tile, in sub tiles
+)
+
+// WalkableFunc reports whether the given world position (where 1 = one map tile) can be occupied by an entity.
+type WalkableFunc func(x, y float64) bool
+
+// HitRecoveryFrames returns the number of frames an entity is stunned by a got-hit animation with baseFrames frames,
+// given its faster hit recovery. The effective FHR diminishes the same way as Diablo II:
+// EFHR = floor(120 * FHR / (120 + FHR)).
+func HitRecoveryFrames(baseFrames, fasterHitRecovery int) int {
+	if baseFrames <= 0 {
+		return 0
+	}
+
+	if fasterHitRecovery < 0 {
+		fasterHitRecovery = 0
+	}
+
+	effectiveFHR := fhrDiminishingFactor * fasterHitRecovery / (fhrDiminishingFactor + fasterHitRecovery)
+
+	return speedUpFrames(baseFrames, animationSpeedBase*(percent+effectiveFHR)/percent)
+}
+
+// speedUpFrames returns the number of frames an animation with baseFrames frames lasts when played at the speed, in
+// 256ths of a frame per frame. At least 1.
+func speedUpFrames(baseFrames, speed int) int {
+	frames := int(math.Ceil(float64(animationSpeedBase*baseFrames)/float64(speed))) - 1
+
+	if frames < 1 {
+		return 1
+	}
+
+	return frames
+}
+
+// exceedsHitRecoveryThreshold returns true if the damage is high enough to put the entity into hit recovery.
+func exceedsHitRecoveryThreshold(damage, maxLife, divisor int) bool {
+	return maxLife > 0 && damage*divisor > maxLife
+}
+
+// SetFasterHitRecovery sets the faster hit recovery stat used to shorten the got-hit stun.
+func (m *mapEntity) SetFasterHitRecovery(fasterHitRecovery int) {
+	m.fasterHitRecovery = fasterHitRecovery
+}
+
+// IsRecoveringFromHit returns true while the entity is stunned by a got-hit animation.
+func (m *mapEntity) IsRecoveringFromHit() bool {
+	return m.hitRecovery > 0
+}
+
+// startHitRecovery interrupts the current movement and stuns the entity for the length of the got-hit animation.
+func (m *mapEntity) startHitRecovery(baseFrames int) {
+	m.hitRecovery = float64(HitRecoveryFrames(baseFrames, m.fasterHitRecovery)) / framesPerSecond
+	m.path = nil
+	m.done = nil
+	m.Target.Copy(&m.Position.Vector)
+}
+
+// advanceHitRecovery counts down the hit recovery stun. It returns true on the tick the entity recovers.
+func (m *mapEntity) advanceHitRecovery(tickTime float64) bool {
+	if m.hitRecovery <= 0 {
+		return false
+	}
+
+	m.hitRecovery -= tickTime
+
+	if m.hitRecovery <= 0 {
+		m.hitRecovery = 0
+		return true
+	}
+
+	return false
+}
+
+// knockback pushes the entity one tile directly away from the given sub tile position, provided the destination is
+// walkable.
+func (m *mapEntity) knockback(sourceX, sourceY float64, isWalkable WalkableFunc) bool {
+	direction := d2vector.NewVector(m.Position.X()-sourceX, m.Position.Y()-sourceY)
+
+	if direction.IsZero() {
+		return false
+	}
+
+	direction.SetLength(knockbackDistance)
+
+	destination := m.Position.Vector.Clone()
+	destination.Add(&direction)
+
+	if isWalkable == nil || !isWalkable(destination.X()/subTilesPerTile, destination.Y()/subTilesPerTile) {
+		return false
+	}
+
+	m.Position.Set(destination.X(), destination.Y())
+	m.Target.Set(destination.X(), destination.Y())
+
+	return true
+}
@@ -0,0 +1,52 @@
+package d2mapentity
+
+import (
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestHitRecoveryFrames(t *testing.T) {
+	assert := testify.New(t)
+
+	tests := []struct {
+		name              string
+		baseFrames        int
+		fasterHitRecovery int
+		frames            int
+	}{
+		{"no animation", 0, 0, 0},
+		{"no faster hit recovery", 8, 0, 7},
+		{"negative faster hit recovery", 8, -20, 7},
+		{"20% faster hit recovery", 8, 20, 6},   // 17% effective
+		{"120% faster hit recovery", 8, 120, 5}, // 60% effective
+		{"single frame", 1, 0, 1},               // the stun lasts at least a frame
+		{"single frame, fast recovery", 1, 200, 1},
+	}
+
+	for _, test := range tests {
+		assert.Equal(test.frames, HitRecoveryFrames(test.baseFrames, test.fasterHitRecovery), test.name)
+	}
+}
+
+func TestKnockback(t *testing.T) {
+	assert := testify.New(t)
+
+	walkable := func(x, y float64) bool { return x < 12 }
+
+	entity := createMapEntity(50, 50)
+
+	assert.True(entity.knockback(45, 50, walkable))
+	assert.Equal([2]float64{55, 50}, [2]float64{entity.Position.X(), entity.Position.Y()},
+		"the entity is pushed a tile directly away from the attacker")
+	assert.Equal(entity.Position.Vector, entity.Target.Vector, "the entity doesn't walk back")
+
+	assert.False(entity.knockback(55, 50, walkable), "an attacker on the entity has no direction to push it in")
+
+	entity = createMapEntity(58, 50)
+	assert.False(entity.knockback(53, 50, walkable), "the entity can't be pushed into an unwalkable tile")
+	assert.Equal(58.0, entity.Position.X())
+
+	assert.False(entity.knockback(63, 50, nil), "without a walkability check, the entity isn't pushed")
+	assert.Equal(58.0, entity.Position.X())
+}
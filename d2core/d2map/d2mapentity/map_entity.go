@@ -1,6 +1,8 @@
 package d2mapentity
 
 import (
+	"image/color"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
@@ -18,6 +20,33 @@ type mapEntity struct {
 
 	done        func()
 	directioner func(direction int)
+	tinter      func(colorMod color.Color)
+	stater      func() string // returns the animation mode kept in the snapshots
+
+	hitRecovery       float64 // Seconds of got-hit stun remaining
+	fasterHitRecovery int
+
+	chilled    float64 // Seconds of chill remaining
+	frozen     float64 // Seconds of freeze remaining
+	coldEffect int     // Percent speed change while chilled
+
+	hitFlash      float64    // Seconds of hit flash remaining
+	hitFlashColor color.RGBA // Tint of the hit flash, toned down by its intensity
+
+	poisoned     float64 // Seconds of poison remaining
+	poisonRate   float64 // Poison damage per second
+	poisonDamage float64 // Poison damage dealt which hasn't been taken off the life yet
+
+	bleeding    float64 // Seconds of open wounds bleeding remaining
+	bleedRate   float64 // Bleeding damage per second
+	bleedDamage float64 // Bleeding damage dealt which hasn't been taken off the life yet
+
+	highlighted   bool
+	isQuestTarget bool
+
+	states map[string]bool // Persistent states, such as active auras
+
+	history *snapshotHistory // Latest snapshots, nil until the first one is recorded
 }
 
 // createMapEntity creates an instance of mapEntity
@@ -25,14 +54,40 @@ func createMapEntity(x, y int) mapEntity {
 	locX, locY := float64(x), float64(y)
 
 	return mapEntity{
-		Position:  d2vector.NewPosition(locX, locY),
-		Target:    d2vector.NewPosition(locX, locY),
-		Speed:     6,
-		drawLayer: 0,
-		path:      []d2astar.Pather{},
+		Position:   d2vector.NewPosition(locX, locY),
+		Target:     d2vector.NewPosition(locX, locY),
+		Speed:      6,
+		drawLayer:  0,
+		path:       []d2astar.Pather{},
+		coldEffect: defaultColdEffect,
 	}
 }
 
+// renderOffset returns the screen offset of the entity from the origin of the tile it is on.
+func (m *mapEntity) renderOffset() (x, y int) {
+	renderOffset := m.Position.RenderOffset()
+
+	return int((renderOffset.X() - renderOffset.Y()) * 16), int((renderOffset.X() + renderOffset.Y()) * 8)
+}
+
+// SetPosition moves the entity to the given sub tile position instantly, stopping any movement.
+func (m *mapEntity) SetPosition(x, y float64) {
+	m.path = nil
+	m.done = nil
+	m.Position.Set(x, y)
+	m.Target.Set(x, y)
+}
+
+// Nudge moves the entity by the given world space offset, where 1 is one map tile, without stopping its movement. An
+// entity standing still stays where it is nudged to rather than walking back.
+func (m *mapEntity) Nudge(dx, dy float64) {
+	if m.IsAtTarget() {
+		m.Target.Set(m.Target.X()+dx*5, m.Target.Y()+dy*5)
+	}
+
+	m.Position.Set(m.Position.X()+dx*5, m.Position.Y()+dy*5)
+}
+
 // GetLayer returns the draw layer for this entity.
 func (m *mapEntity) GetLayer() int {
 	return m.drawLayer
@@ -67,6 +122,10 @@ func (m *mapEntity) IsAtTarget() bool {
 
 // Step moves the entity along it's path by one tick. If the path is complete it calls entity.done() then returns.
 func (m *mapEntity) Step(tickTime float64) {
+	if m.IsRecoveringFromHit() || m.IsFrozen() {
+		return
+	}
+
 	if m.IsAtTarget() {
 		if m.done != nil {
 			m.done()
@@ -107,7 +166,7 @@ func (m *mapEntity) Step(tickTime float64) {
 
 // velocity returns a vector describing the change in position this tick.
 func (m *mapEntity) velocity(tickTime float64) d2vector.Vector {
-	length := tickTime * m.Speed
+	length := tickTime * m.Speed * m.coldSpeedMultiplier()
 	v := m.Target.Vector.Clone()
 	v.Subtract(&m.Position.Vector)
 	v.SetLength(length)
@@ -183,8 +242,9 @@ func (m *mapEntity) Name() string {
 	return ""
 }
 
-// Highlight is not currently implemented.
+// Highlight outlines the entity the next time it is rendered.
 func (m *mapEntity) Highlight() {
+	m.highlighted = true
 }
 
 // Selectable returns true if the object can be highlighted/selected.
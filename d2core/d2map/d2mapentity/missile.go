@@ -6,6 +6,7 @@ import (
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
 )
@@ -19,9 +20,26 @@ type Missile struct {
 
 // CreateMissile creates a new Missile and initializes it's animation.
 func CreateMissile(x, y int, record *d2datadict.MissileRecord) (*Missile, error) {
+	animation, err := loadMissileAnimation(record, d2resource.PaletteUnits)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := CreateAnimatedEntity(x, y, animation)
+
+	result := &Missile{
+		AnimatedEntity: entity,
+		record:         record,
+	}
+	result.Speed = float64(record.Velocity)
+
+	return result, nil
+}
+
+func loadMissileAnimation(record *d2datadict.MissileRecord, palettePath string) (d2interface.Animation, error) {
 	animation, err := d2asset.LoadAnimation(
 		fmt.Sprintf("%s/%s.dcc", d2resource.MissileData, record.Animation.CelFileName),
-		d2resource.PaletteUnits,
+		palettePath,
 	)
 	if err != nil {
 		return nil, err
@@ -35,15 +53,24 @@ func CreateMissile(x, y int, record *d2datadict.MissileRecord) (*Missile, error)
 	// animation.SetPlaySpeed(float64(record.Animation.AnimationSpeed))
 	animation.SetPlayLoop(record.Animation.LoopAnimation)
 	animation.PlayForward()
-	entity := CreateAnimatedEntity(x, y, animation)
 
-	result := &Missile{
-		AnimatedEntity: entity,
-		record:         record,
+	return animation, nil
+}
+
+// SetPalette reloads the missile's animation with the palette of the level it is on.
+func (m *Missile) SetPalette(palettePath string) error {
+	animation, err := loadMissileAnimation(m.record, palettePath)
+	if err != nil {
+		return err
 	}
-	result.Speed = float64(record.Velocity)
 
-	return result, nil
+	if err := animation.SetDirection(m.direction); err != nil {
+		return err
+	}
+
+	m.animation = animation
+
+	return nil
 }
 
 // SetRadians adjusts the entity target based on it's range, rotating it's
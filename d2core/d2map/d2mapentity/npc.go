@@ -1,6 +1,8 @@
 package d2mapentity
 
 import (
+	"image/color"
+	"math"
 	"math/rand"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
@@ -10,6 +12,7 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2combat"
 )
 
 // NPC is a passive complex entity with which the player can interact.
@@ -26,6 +29,19 @@ type NPC struct {
 	monstatRecord *d2datadict.MonStatsRecord
 	monstatEx     *d2datadict.MonStats2Record
 	name          string
+	life          int
+	maxLife       int
+	isBoss        bool
+	players       int
+
+	resistanceModifiers  map[d2enum.DamageElement]int
+	resistanceReductions map[d2enum.DamageElement]int
+	reflectPercent       int
+	reflectFlat          int
+
+	aggro *aggroState // pursuit of the NPC's targets, nil while it is idle
+
+	corpsePush *corpsePush // slide of the corpse after death, nil unless it slides
 }
 
 // CreateNPC creates a new NPC and returns a pointer to it.
@@ -52,13 +68,19 @@ func CreateNPC(x, y int, monstat *d2datadict.MonStatsRecord, direction int) *NPC
 
 	result.SetSpeed(float64(monstat.SpeedBase))
 	result.mapEntity.directioner = result.rotate
+	result.mapEntity.tinter = composite.SetColorMod
+	result.mapEntity.stater = composite.GetAnimationMode
+	result.SetColdEffect(monstat.ColdSensitivityNormal)
 
 	result.composite.SetDirection(direction)
 
-	if result.monstatRecord != nil && result.monstatRecord.IsInteractable {
+	if result.monstatRecord != nil && (result.monstatRecord.IsInteractable || result.IsHostile()) {
 		result.name = d2common.TranslateString(result.monstatRecord.NameStringTableKey)
 	}
 
+	result.maxLife = rollLife(monstat.MinHPNormal, monstat.MaxHPNormal)
+	result.life = result.maxLife
+
 	return result
 }
 
@@ -72,16 +94,202 @@ func selectEquip(slice []string) string {
 
 // Render renders this entity's animated composite.
 func (v *NPC) Render(target d2interface.Surface) {
-	renderOffset := v.Position.RenderOffset()
-	target.PushTranslation(
-		int((renderOffset.X()-renderOffset.Y())*16),
-		int(((renderOffset.X() + renderOffset.Y()) * 8)),
-	)
+	target.PushTranslation(v.renderOffset())
 
 	defer target.Pop()
+
+	if outlineColor := v.takeOutlineColor(v.hoverColor()); outlineColor != nil {
+		v.composite.RenderOutline(target, outlineColor)
+	}
+
 	v.composite.Render(target)
 }
 
+// BatchComposite returns the composite the NPC is drawn with and its offset from the tile it is on, so identical NPCs
+// can be drawn in one batch. The composite is nil if the NPC is outlined and must be drawn on its own.
+func (v *NPC) BatchComposite() (composite *d2asset.Composite, offsetX, offsetY int) {
+	offsetX, offsetY = v.renderOffset()
+
+	if v.highlighted || v.isQuestTarget {
+		return nil, offsetX, offsetY
+	}
+
+	return v.composite, offsetX, offsetY
+}
+
+// hoverColor returns the outline color of the NPC under the cursor, green for town folk and red for monsters.
+func (v *NPC) hoverColor() color.Color {
+	if v.monstatRecord != nil && (v.monstatRecord.IsNpc || v.monstatRecord.IsInteractable) {
+		return outlineColorFriend
+	}
+
+	return outlineColorFoe
+}
+
+// SetPalette reloads the NPC's animations with the palette of the level it is on.
+func (v *NPC) SetPalette(palettePath string) error {
+	return v.composite.SetPalette(palettePath)
+}
+
+// IsHostile returns true if the NPC is a monster the player can attack.
+func (v *NPC) IsHostile() bool {
+	return v.monstatRecord != nil && v.monstatRecord.IsKillable && !v.monstatRecord.IsNpc &&
+		!v.monstatRecord.IsInteractable
+}
+
+// MonsterKey returns the ID of the NPC's monster in monstats.txt, such as warriv1.
+func (v *NPC) MonsterKey() string {
+	if v.monstatRecord == nil {
+		return ""
+	}
+
+	return v.monstatRecord.Key
+}
+
+// MaxLife returns the life the NPC started with.
+func (v *NPC) MaxLife() int {
+	return v.maxLife
+}
+
+// Life returns the NPC's remaining life.
+func (v *NPC) Life() int {
+	return v.life
+}
+
+// SetLife changes the NPC's remaining life, between 0 and its maximum life.
+func (v *NPC) SetLife(life int) {
+	switch {
+	case life < 0:
+		life = 0
+	case life > v.maxLife:
+		life = v.maxLife
+	}
+
+	v.life = life
+}
+
+// TakeDamage takes the damage off the NPC's life, which never drops below 0.
+func (v *NPC) TakeDamage(damage int) {
+	v.life -= damage
+
+	if v.life < 0 {
+		v.life = 0
+	}
+}
+
+// SetPlayers scales the NPC's life and experience for a game with the given number of players. Each player past the
+// first adds half of the base life and experience, as with the /players command of Diablo II. The NPC keeps the same
+// share of its life, so wounded monsters stay wounded and dead ones stay dead.
+func (v *NPC) SetPlayers(players int) {
+	scale := playersMultiplier(players) / playersMultiplier(v.players)
+	v.maxLife = int(math.Round(float64(v.maxLife) * scale))
+	v.life = int(math.Round(float64(v.life) * scale))
+	v.players = players
+}
+
+// Resistance returns the NPC's resistance to the damage element, in percent. Like its life, the base resistance is the
+// normal difficulty's, as difficulties can't be chosen yet.
+func (v *NPC) Resistance(element d2enum.DamageElement) int {
+	resistance := v.resistanceModifiers[element]
+
+	if v.monstatRecord != nil {
+		resistance += v.monstatRecord.Resistance(element, d2enum.DifficultyNormal)
+	}
+
+	return resistance
+}
+
+// ModifyResistance adds to the NPC's resistance to the damage element, as auras and boss modifiers do.
+func (v *NPC) ModifyResistance(element d2enum.DamageElement, amount int) {
+	if v.resistanceModifiers == nil {
+		v.resistanceModifiers = make(map[d2enum.DamageElement]int)
+	}
+
+	v.resistanceModifiers[element] += amount
+}
+
+// ResistanceReduction returns how much the NPC's resistance to the damage element is lowered, in percent.
+func (v *NPC) ResistanceReduction(element d2enum.DamageElement) int {
+	return v.resistanceReductions[element]
+}
+
+// ReduceResistance lowers the NPC's resistance to the damage element, as Lower Resist and Conviction do. Unlike the
+// modifiers, the reductions only partly apply to immunities.
+func (v *NPC) ReduceResistance(element d2enum.DamageElement, amount int) {
+	if v.resistanceReductions == nil {
+		v.resistanceReductions = make(map[d2enum.DamageElement]int)
+	}
+
+	v.resistanceReductions[element] += amount
+}
+
+// Kind returns the kind of defender the NPC is for on-hit effects, super uniques are bosses.
+func (v *NPC) Kind() d2combat.DefenderKind {
+	if v.isBoss {
+		return d2combat.DefenderBoss
+	}
+
+	return d2combat.DefenderMonster
+}
+
+// LeechSensitivity returns the percent of its attackers' life and mana steal which applies to the NPC.
+func (v *NPC) LeechSensitivity() int {
+	if v.monstatRecord == nil {
+		return 100 //nolint:gomnd // percent
+	}
+
+	return v.monstatRecord.LeechSensitivity(d2enum.DifficultyNormal)
+}
+
+// Reflection returns the percent of the physical melee damage taken, and the flat damage, the NPC's attackers take.
+func (v *NPC) Reflection() (percent, flat int) {
+	return v.reflectPercent, v.reflectFlat
+}
+
+// ModifyReflection adds to the damage the NPC's melee attackers take, as the Thorns aura and boss modifiers do.
+func (v *NPC) ModifyReflection(percent, flat int) {
+	v.reflectPercent += percent
+	v.reflectFlat += flat
+}
+
+// Experience returns the experience the NPC grants when killed.
+func (v *NPC) Experience() int {
+	if v.monstatRecord == nil {
+		return 0
+	}
+
+	return int(float64(v.monstatRecord.ExperienceNormal) * playersMultiplier(v.players))
+}
+
+// playersMultiplier returns the factor monster life and experience are multiplied by for the number of players.
+func playersMultiplier(players int) float64 {
+	if players < 1 {
+		players = 1
+	}
+
+	return float64(players+1) / 2 //nolint:gomnd // every player past the first adds 50%
+}
+
+// SetSuperUnique makes the NPC the super unique boss with the given name string table key.
+func (v *NPC) SetSuperUnique(nameKey string) {
+	v.isBoss = true
+	v.name = d2common.TranslateString(nameKey)
+}
+
+// IsBoss returns true if the NPC is a super unique boss.
+func (v *NPC) IsBoss() bool {
+	return v.isBoss
+}
+
+// rollLife returns a random life between the monstats.txt minimum and maximum hit points.
+func rollLife(minLife, maxLife int) int {
+	if maxLife <= minLife {
+		return minLife
+	}
+
+	return minLife + rand.Intn(maxLife-minLife+1)
+}
+
 // Path returns the current part of the entity's path.
 func (v *NPC) Path() d2common.Path {
 	return v.Paths[v.path]
@@ -109,10 +317,21 @@ func (v *NPC) SetPaths(paths []d2common.Path) {
 // Advance is called once per frame and processes a
 // single game tick.
 func (v *NPC) Advance(tickTime float64) {
+	thawed := v.advanceColdEffects(tickTime)
+	v.advanceHitFlash(tickTime)
+	animationTime := tickTime * v.coldSpeedMultiplier()
+
+	v.TakeDamage(v.advancePoison(tickTime, v.life))
+	v.TakeDamage(v.advanceBleeding(tickTime))
 	v.Step(tickTime)
-	v.composite.Advance(tickTime)
+	v.advanceCorpsePush(tickTime)
+	v.composite.Advance(animationTime)
 
-	if v.HasPaths && v.wait() {
+	if v.advanceHitRecovery(animationTime) || thawed {
+		v.rotate(v.composite.GetDirection())
+	}
+
+	if v.HasPaths && v.aggro == nil && v.wait() {
 		// If at the target, set target to the next path.
 		v.isDone = false
 		path := v.NextPath()
@@ -126,6 +345,27 @@ func (v *NPC) Advance(tickTime float64) {
 	}
 }
 
+// GetHit plays the got-hit animation and stuns the NPC if the damage exceeds its hit recovery threshold, interrupting
+// its current action. If knockback is true, the NPC is also pushed a tile away from the attacker's sub tile position
+// when the destination is walkable. A frozen NPC can't be stunned. Returns true if the NPC went into hit recovery.
+func (v *NPC) GetHit(damage, maxLife int, sourceX, sourceY float64, knockback bool, isWalkable WalkableFunc) bool {
+	if v.IsFrozen() || !exceedsHitRecoveryThreshold(damage, maxLife, monsterHitRecoveryDivisor) {
+		return false
+	}
+
+	if err := v.composite.SetMode(d2enum.MonsterAnimationModeGetHit, v.composite.GetWeaponClass()); err != nil {
+		return false
+	}
+
+	v.startHitRecovery(v.composite.GetFrameCount())
+
+	if knockback {
+		v.knockback(sourceX, sourceY, isWalkable)
+	}
+
+	return true
+}
+
 // If an npc has a path to pause at each location.
 // Waits for animation to end and all repetitions to be exhausted.
 func (v *NPC) wait() bool {
@@ -0,0 +1,39 @@
+package d2mapentity
+
+import (
+	"image/color"
+)
+
+//nolint:gochecknoglobals // constant outline colors
+var (
+	outlineColorFoe    = color.RGBA{R: 0xff, G: 0x30, B: 0x30, A: 0xff}
+	outlineColorFriend = color.RGBA{R: 0x40, G: 0xff, B: 0x40, A: 0xff}
+	outlineColorQuest  = color.RGBA{R: 0xff, G: 0xd7, B: 0x00, A: 0xff}
+)
+
+// SetQuestTarget sets whether the entity is always outlined as the target of a quest.
+func (m *mapEntity) SetQuestTarget(isQuestTarget bool) {
+	m.isQuestTarget = isQuestTarget
+}
+
+// IsQuestTarget returns true if the entity is always outlined as the target of a quest.
+func (m *mapEntity) IsQuestTarget() bool {
+	return m.isQuestTarget
+}
+
+// takeOutlineColor returns the color to outline the entity with this frame, or nil if it shouldn't be outlined.
+// A highlighted entity uses hoverColor, otherwise quest targets are outlined in gold. The highlight is cleared, the
+// entity under the cursor highlights itself again every frame.
+func (m *mapEntity) takeOutlineColor(hoverColor color.Color) color.Color {
+	highlighted := m.highlighted
+	m.highlighted = false
+
+	switch {
+	case highlighted:
+		return hoverColor
+	case m.isQuestTarget:
+		return outlineColorQuest
+	default:
+		return nil
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2combat"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
 )
@@ -17,6 +18,8 @@ type Player struct {
 	composite *d2asset.Composite
 	Equipment d2inventory.CharacterEquipment
 	Stats     d2hero.HeroStatsState
+	Quests    *d2hero.QuestLog
+	Skills    *d2hero.SkillTree
 	Class     d2enum.Hero
 	Id        string
 	name      string
@@ -27,24 +30,27 @@ type Player struct {
 	isRunToggled  bool
 	isRunning     bool
 	isCasting     bool
+	actionDelay   float64 // Seconds until the action frame of the cast
+	onAction      func()  // Called on the action frame of the cast
+	isNoClip      bool
+	noClipSpeed   float64
+	cooldowns     *d2hero.SkillCooldowns
+	channeling    *d2datadict.SkillRecord
+	channelMana   float64 // Mana drained by the channeled skill which hasn't been taken from Stats yet
+	regenLife     float64 // Life regenerated which hasn't been added to Stats yet
+	regenMana     float64 // Mana regenerated which hasn't been added to Stats yet
+
+	actionMode d2enum.PlayerAnimationMode // Animation mode of the cast or attack
 }
 
 // run speed should be walkspeed * 1.5, since in the original game it is 6 yards walk and 9 yards run.
 var baseWalkSpeed = 6.0
 var baseRunSpeed = 9.0
+var baseNoClipSpeed = baseRunSpeed * 2
 
 // CreatePlayer creates a new player entity and returns a pointer to it.
 func CreatePlayer(id, name string, x, y int, direction int, heroType d2enum.Hero, stats d2hero.HeroStatsState, equipment d2inventory.CharacterEquipment) *Player {
-	layerEquipment := &[d2enum.CompositeTypeMax]string{
-		d2enum.CompositeTypeHead:      equipment.Head.GetArmorClass(),
-		d2enum.CompositeTypeTorso:     equipment.Torso.GetArmorClass(),
-		d2enum.CompositeTypeLegs:      equipment.Legs.GetArmorClass(),
-		d2enum.CompositeTypeRightArm:  equipment.RightArm.GetArmorClass(),
-		d2enum.CompositeTypeLeftArm:   equipment.LeftArm.GetArmorClass(),
-		d2enum.CompositeTypeRightHand: equipment.RightHand.GetItemCode(),
-		d2enum.CompositeTypeLeftHand:  equipment.LeftHand.GetItemCode(),
-		d2enum.CompositeTypeShield:    equipment.Shield.GetItemCode(),
-	}
+	layerEquipment := equipmentLayers(&equipment)
 
 	composite, err := d2asset.LoadComposite(d2enum.ObjectTypePlayer, heroType.GetToken(),
 		d2resource.PaletteUnits)
@@ -61,15 +67,21 @@ func CreatePlayer(id, name string, x, y int, direction int, heroType d2enum.Hero
 		composite: composite,
 		Equipment: equipment,
 		Stats:     stats,
+		Quests:    d2hero.NewQuestLog(),
+		Skills:    d2hero.NewSkillTree(heroType),
 		name:      name,
 		Class:     heroType,
 		//nameLabel:    d2ui.CreateLabel(d2resource.FontFormal11, d2resource.PaletteStatic),
 		isRunToggled: true,
 		isInTown:     true,
 		isRunning:    true,
+		noClipSpeed:  baseNoClipSpeed,
+		cooldowns:    d2hero.NewSkillCooldowns(),
 	}
 	result.SetSpeed(baseRunSpeed)
 	result.mapEntity.directioner = result.rotate
+	result.mapEntity.tinter = composite.SetColorMod
+	result.mapEntity.stater = composite.GetAnimationMode
 	//result.nameLabel.Alignment = d2ui.LabelAlignCenter
 	//result.nameLabel.SetText(name)
 	//result.nameLabel.Color = color.White
@@ -84,6 +96,35 @@ func CreatePlayer(id, name string, x, y int, direction int, heroType d2enum.Hero
 	return result
 }
 
+// equipmentLayers returns the composite layer of each equipped item.
+func equipmentLayers(equipment *d2inventory.CharacterEquipment) *[d2enum.CompositeTypeMax]string {
+	return &[d2enum.CompositeTypeMax]string{
+		d2enum.CompositeTypeHead:      equipment.Head.GetArmorClass(),
+		d2enum.CompositeTypeTorso:     equipment.Torso.GetArmorClass(),
+		d2enum.CompositeTypeLegs:      equipment.Legs.GetArmorClass(),
+		d2enum.CompositeTypeRightArm:  equipment.RightArm.GetArmorClass(),
+		d2enum.CompositeTypeLeftArm:   equipment.LeftArm.GetArmorClass(),
+		d2enum.CompositeTypeRightHand: equipment.RightHand.GetItemCode(),
+		d2enum.CompositeTypeLeftHand:  equipment.LeftHand.GetItemCode(),
+		d2enum.CompositeTypeShield:    equipment.Shield.GetItemCode(),
+	}
+}
+
+// UpdateEquipment reloads the composite after the player's equipment changed, so the player is drawn with the new
+// items and the animations of the weapon they now hold.
+func (v *Player) UpdateEquipment() error {
+	if err := v.composite.Equip(equipmentLayers(&v.Equipment)); err != nil {
+		return err
+	}
+
+	return v.composite.SetMode(v.GetAnimationMode(), v.Equipment.RightHand.GetWeaponClass())
+}
+
+// SetPalette reloads the player's animations with the palette of the level they are on.
+func (v *Player) SetPalette(palettePath string) error {
+	return v.composite.SetPalette(palettePath)
+}
+
 // SetIsInTown sets a flag indicating that the player is in town.
 func (p *Player) SetIsInTown(isInTown bool) {
 	p.isInTown = isInTown
@@ -110,6 +151,10 @@ func (p *Player) IsRunning() bool {
 func (p *Player) SetIsRunning(isRunning bool) {
 	p.isRunning = isRunning
 
+	if p.isNoClip {
+		return
+	}
+
 	if isRunning {
 		p.SetSpeed(baseRunSpeed)
 	} else {
@@ -117,6 +162,36 @@ func (p *Player) SetIsRunning(isRunning bool) {
 	}
 }
 
+// IsNoClip returns true if the player moves straight to their destination, ignoring the collision grid.
+func (p *Player) IsNoClip() bool {
+	return p.isNoClip
+}
+
+// SetNoClip turns the no-clip debug movement mode on or off. While it is on, the player moves at the no-clip speed.
+func (p *Player) SetNoClip(isNoClip bool) {
+	p.isNoClip = isNoClip
+
+	if isNoClip {
+		p.SetSpeed(p.noClipSpeed)
+	} else {
+		p.SetIsRunning(p.isRunning)
+	}
+}
+
+// GetNoClipSpeed returns the player movement speed in no-clip mode.
+func (p *Player) GetNoClipSpeed() float64 {
+	return p.noClipSpeed
+}
+
+// SetNoClipSpeed sets the player movement speed in no-clip mode.
+func (p *Player) SetNoClipSpeed(speed float64) {
+	p.noClipSpeed = speed
+
+	if p.isNoClip {
+		p.SetSpeed(speed)
+	}
+}
+
 // IsInTown returns true if the player is currently in town.
 func (p Player) IsInTown() bool {
 	return p.isInTown
@@ -125,13 +200,28 @@ func (p Player) IsInTown() bool {
 // Advance is called once per frame and processes a
 // single game tick.
 func (v *Player) Advance(tickTime float64) {
+	thawed := v.advanceColdEffects(tickTime)
+	animationTime := tickTime * v.coldSpeedMultiplier()
+
+	v.advanceHitFlash(tickTime)
+	v.cooldowns.Advance(tickTime)
+	v.advanceChanneling(tickTime)
+	v.TakeDamage(v.advancePoison(tickTime, v.Stats.Health))
+	v.TakeDamage(v.advanceBleeding(tickTime))
+	v.advanceRegeneration(tickTime)
 	v.Step(tickTime)
+	v.advanceAction(animationTime)
 
 	if v.IsCasting() && v.composite.GetPlayedCount() >= 1 {
+		v.triggerAction()
 		v.isCasting = false
 		v.SetAnimationMode(v.GetAnimationMode())
 	}
-	v.composite.Advance(tickTime)
+	v.composite.Advance(animationTime)
+
+	if v.advanceHitRecovery(animationTime) || thawed {
+		v.SetAnimationMode(v.GetAnimationMode())
+	}
 
 	if v.lastPathSize != len(v.path) {
 		v.lastPathSize = len(v.path)
@@ -151,6 +241,11 @@ func (v *Player) Render(target d2interface.Surface) {
 	)
 
 	defer target.Pop()
+
+	if outlineColor := v.takeOutlineColor(outlineColorFriend); outlineColor != nil {
+		v.composite.RenderOutline(target, outlineColor)
+	}
+
 	v.composite.Render(target)
 	// v.nameLabel.X = v.offsetX
 	// v.nameLabel.Y = v.offsetY - 100
@@ -176,7 +271,7 @@ func (v *Player) GetAnimationMode() d2enum.PlayerAnimationMode {
 	}
 
 	if v.IsCasting() {
-		return d2enum.PlayerAnimationModeCast
+		return v.actionMode
 	}
 
 	return d2enum.PlayerAnimationModeNeutral
@@ -213,8 +308,211 @@ func (v *Player) IsCasting() bool {
 // SetCasting sets a flag indicating the player is casting a skill and
 // sets the animation mode to the casting animation.
 func (v *Player) SetCasting() {
+	v.startAction(d2enum.PlayerAnimationModeCast)
+}
+
+// SetSkillAnimation sets a flag indicating the player is using the skill and plays the animation of its skills.txt
+// anim column, the attack animations sped up by increased attack speed and the cast animation by faster cast rate.
+// Skills without an attack animation are cast.
+func (v *Player) SetSkillAnimation(skill *d2datadict.SkillRecord) {
+	mode := d2enum.PlayerAnimationModeCast
+	if skill != nil {
+		mode = skillAnimationMode(skill.Anim)
+	}
+
+	v.startAction(mode)
+}
+
+// startAction plays the cast or attack animation mode, falling back to casting if the composite doesn't have it.
+func (v *Player) startAction(mode d2enum.PlayerAnimationMode) {
+	v.triggerAction()
 	v.isCasting = true
-	v.SetAnimationMode(d2enum.PlayerAnimationModeCast)
+
+	if err := v.SetAnimationMode(mode); err != nil {
+		mode = d2enum.PlayerAnimationModeCast
+		v.SetAnimationMode(mode)
+	}
+
+	v.actionMode = mode
+	v.speedUpAction(actionSpeedBonus(mode, v.Stats.AttackSpeed, v.Stats.CastSpeed))
+}
+
+// OnActionFrame calls the callback once the cast reaches its action frame, to fire the missile when the hero's hands
+// release it. The callback is called right away if the player isn't casting.
+func (v *Player) OnActionFrame(callback func()) {
+	if !v.IsCasting() {
+		callback()
+		return
+	}
+
+	v.onAction = callback
+}
+
+// speedUpAction plays the attack or cast animation which just started faster for the increased attack speed or faster
+// cast rate, and times its action frame accordingly.
+func (v *Player) speedUpAction(speedBonus int) {
+	baseFrames := v.composite.GetFrameCount()
+	baseSpeed := v.composite.GetBaseAnimationSpeed()
+
+	frames := ActionFrames(baseFrames, baseSpeed, speedBonus)
+	if frames == 0 {
+		return
+	}
+
+	v.composite.SetPlayFPS(framesPerSecond * float64(baseFrames) / float64(frames))
+	v.actionDelay = float64(ActionFrame(v.composite.GetActionFrame(), baseSpeed, speedBonus)) / framesPerSecond
+}
+
+// advanceAction counts down to the action frame of the cast, calling the action callback once it is reached.
+func (v *Player) advanceAction(tickTime float64) {
+	if v.onAction == nil {
+		return
+	}
+
+	v.actionDelay -= tickTime
+
+	if v.actionDelay <= 0 {
+		v.triggerAction()
+	}
+}
+
+// triggerAction calls the action callback waiting for the action frame, if any. An interrupted cast still fires.
+func (v *Player) triggerAction() {
+	onAction := v.onAction
+	v.onAction = nil
+	v.actionDelay = 0
+
+	if onAction != nil {
+		onAction()
+	}
+}
+
+// GetHit plays the got-hit animation and stuns the player if the damage exceeds their hit recovery threshold,
+// interrupting any movement or cast. The stun is shortened by the faster hit recovery of their stats. If knockback is
+// true, the player is also pushed a tile away from the attacker's sub tile position when the destination is walkable. A
+// frozen player, or one holding a channeled skill skills.txt doesn't mark as interrupted by hits such as whirlwind,
+// can't be stunned. Returns true if the player went into hit recovery.
+func (v *Player) GetHit(damage int, sourceX, sourceY float64, knockback bool, isWalkable WalkableFunc) bool {
+	if v.IsFrozen() || !exceedsHitRecoveryThreshold(damage, v.Stats.MaxHealth, playerHitRecoveryDivisor) {
+		return false
+	}
+
+	if v.channeling != nil && !v.channeling.Interrupt {
+		return false
+	}
+
+	if err := v.SetAnimationMode(d2enum.PlayerAnimationModeGetHit); err != nil {
+		return false
+	}
+
+	v.triggerAction()
+	v.isCasting = false
+	v.SetFasterHitRecovery(v.Stats.HitRecovery)
+	v.startHitRecovery(v.composite.GetFrameCount())
+
+	if knockback {
+		v.knockback(sourceX, sourceY, isWalkable)
+	}
+
+	return true
+}
+
+// Resistance returns the player's resistance to the damage element, in percent. Physical and magic damage are only
+// resisted by items, which aren't accounted for yet.
+func (v *Player) Resistance(element d2enum.DamageElement) int {
+	switch element {
+	case d2enum.DamageFire:
+		return v.Stats.FireResistance
+	case d2enum.DamageLightning:
+		return v.Stats.LightningResistance
+	case d2enum.DamageCold:
+		return v.Stats.ColdResistance
+	case d2enum.DamagePoison:
+		return v.Stats.PoisonResistance
+	default:
+		return 0
+	}
+}
+
+// ResistanceReduction returns how much the player's resistance to the damage element is lowered, which monster curses
+// would do.
+func (v *Player) ResistanceReduction(d2enum.DamageElement) int {
+	return 0
+}
+
+// TakeDamage takes the damage off the player's life, which never drops below 0.
+func (v *Player) TakeDamage(damage int) {
+	v.Stats.Health -= damage
+
+	if v.Stats.Health < 0 {
+		v.Stats.Health = 0
+	}
+}
+
+// Leech returns the percent of the physical damage the player deals stolen as life, and as mana.
+func (v *Player) Leech() (life, mana int) {
+	return v.Stats.LifeSteal, v.Stats.ManaSteal
+}
+
+// advanceRegeneration regenerates the player's life and mana over the tick. Poison stops life from regenerating, and
+// dead players don't regenerate at all.
+func (v *Player) advanceRegeneration(tickTime float64) {
+	if v.Stats.Health <= 0 {
+		v.regenLife, v.regenMana = 0, 0
+		return
+	}
+
+	if v.IsPoisoned() {
+		v.regenLife = 0
+	} else {
+		v.regenLife += v.Stats.LifeRegenRate() * tickTime
+	}
+
+	v.regenMana += v.Stats.ManaRegenRate() * tickTime
+
+	life, mana := int(v.regenLife), int(v.regenMana)
+	v.regenLife -= float64(life)
+	v.regenMana -= float64(mana)
+
+	v.Restore(life, mana)
+}
+
+// Life returns the player's remaining life.
+func (v *Player) Life() int {
+	return v.Stats.Health
+}
+
+// Level returns the player's character level.
+func (v *Player) Level() int {
+	return v.Stats.Level
+}
+
+// Kind returns the kind of defender the player is for on-hit effects.
+func (v *Player) Kind() d2combat.DefenderKind {
+	return d2combat.DefenderPlayer
+}
+
+// OnHitChances returns the player's percent chances of crushing blow and of open wounds.
+func (v *Player) OnHitChances() (crushingBlow, openWounds int) {
+	return v.Stats.CrushingBlow, v.Stats.OpenWounds
+}
+
+// FreezeLength returns how long the player's hits freeze their targets in frames, from items.
+func (v *Player) FreezeLength() int {
+	return v.Stats.FreezeLength
+}
+
+// Restore gives the player life and mana, capped at their maximum life and mana.
+func (v *Player) Restore(life, mana int) {
+	v.Stats.Health += life
+	if v.Stats.Health > v.Stats.MaxHealth {
+		v.Stats.Health = v.Stats.MaxHealth
+	}
+
+	v.Stats.Mana += mana
+	if v.Stats.Mana > v.Stats.MaxMana {
+		v.Stats.Mana = v.Stats.MaxMana
+	}
 }
 
 // Selectable returns true if the player is in town.
@@ -0,0 +1,117 @@
+package d2mapentity
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+)
+
+// minSkillLevel is the level skills the player hasn't put skill points into are used at.
+const minSkillLevel = 1
+
+// SkillLevel returns the level the player uses the skill at.
+func (v *Player) SkillLevel(skill *d2datadict.SkillRecord) int {
+	if level := v.Skills.Level(skill); level > minSkillLevel {
+		return level
+	}
+
+	return minSkillLevel
+}
+
+// SkillManaCost returns the mana the player needs to use the skill.
+func (v *Player) SkillManaCost(skill *d2datadict.SkillRecord) int {
+	return skill.ManaCost(v.SkillLevel(skill))
+}
+
+// CanAffordSkill returns true if the player has enough mana to use the skill.
+func (v *Player) CanAffordSkill(skill *d2datadict.SkillRecord) bool {
+	return v.Stats.Mana >= v.SkillManaCost(skill)
+}
+
+// SkillCooldown returns the seconds left until the player can use the skill again.
+func (v *Player) SkillCooldown(skill *d2datadict.SkillRecord) float64 {
+	return v.cooldowns.Remaining(skill)
+}
+
+// CanUseSkill returns true if the skill is off cooldown and the player has enough mana to use it.
+func (v *Player) CanUseSkill(skill *d2datadict.SkillRecord) bool {
+	return v.cooldowns.IsReady(skill) && v.CanAffordSkill(skill)
+}
+
+// UseSkill spends the mana cost of the skill and puts it on cooldown. It returns false, changing nothing, if the
+// skill can't be used right now.
+func (v *Player) UseSkill(skill *d2datadict.SkillRecord) bool {
+	if !v.CanUseSkill(skill) {
+		return false
+	}
+
+	v.Stats.Mana -= v.SkillManaCost(skill)
+	v.cooldowns.Start(skill)
+
+	return true
+}
+
+// ToggleSkill turns a toggled skill on, paying its mana cost and putting its state on the player, or turns it off
+// again. It returns false, changing nothing, if the skill can't be turned on right now.
+func (v *Player) ToggleSkill(skill *d2datadict.SkillRecord) bool {
+	state := skill.StateName()
+
+	if v.HasState(state) {
+		v.RemoveState(state)
+		return true
+	}
+
+	if !v.UseSkill(skill) {
+		return false
+	}
+
+	v.AddState(state)
+
+	return true
+}
+
+// IsSkillToggled returns true if the toggled skill is turned on.
+func (v *Player) IsSkillToggled(skill *d2datadict.SkillRecord) bool {
+	return v.HasState(skill.StateName())
+}
+
+// StartChanneling starts holding a channeled skill, paying its mana cost up front. While it is held the skill keeps
+// draining its mana cost every second, until it is stopped or the player runs out of mana. It returns false if the
+// skill can't be used right now.
+func (v *Player) StartChanneling(skill *d2datadict.SkillRecord) bool {
+	if !v.UseSkill(skill) {
+		return false
+	}
+
+	v.channeling = skill
+	v.channelMana = 0
+
+	return true
+}
+
+// StopChanneling stops the channeled skill being held, if any.
+func (v *Player) StopChanneling() {
+	v.channeling = nil
+	v.channelMana = 0
+}
+
+// IsChanneling returns true while the player is holding the given channeled skill.
+func (v *Player) IsChanneling(skill *d2datadict.SkillRecord) bool {
+	return v.channeling != nil && v.channeling == skill
+}
+
+// advanceChanneling drains the mana of the channeled skill being held, stopping it once the player can't pay.
+func (v *Player) advanceChanneling(tickTime float64) {
+	if v.channeling == nil {
+		return
+	}
+
+	v.channelMana += float64(v.SkillManaCost(v.channeling)) * tickTime
+	drained := int(v.channelMana)
+
+	if drained > v.Stats.Mana {
+		v.StopChanneling()
+		return
+	}
+
+	v.Stats.Mana -= drained
+	v.channelMana -= float64(drained)
+}
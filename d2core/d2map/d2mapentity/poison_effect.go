@@ -0,0 +1,55 @@
+package d2mapentity
+
+import "math"
+
+// IsPoisoned returns true while poison deals damage to the entity.
+func (m *mapEntity) IsPoisoned() bool {
+	return m.poisoned > 0
+}
+
+// Poison deals the damage to the entity over the duration in seconds. Poisons don't stack, as in Diablo II: the new
+// poison replaces the current one if it deals more damage than the current one has left to deal, whether it is
+// stronger or longer, and is ignored otherwise. Returns false if the new poison was ignored.
+func (m *mapEntity) Poison(damage int, duration float64) bool {
+	if damage <= 0 || duration <= 0 {
+		return false
+	}
+
+	if float64(damage) <= m.poisonRate*m.poisoned {
+		return false
+	}
+
+	m.poisonRate = float64(damage) / duration
+	m.poisoned = duration
+
+	return true
+}
+
+// advancePoison counts down the poison duration and returns the poison damage dealt over the tick. Poison can't kill,
+// the damage never takes the entity's life below 1.
+func (m *mapEntity) advancePoison(tickTime float64, life int) int {
+	if !m.IsPoisoned() {
+		return 0
+	}
+
+	elapsed := math.Min(tickTime, m.poisoned)
+	m.poisoned -= elapsed
+	m.poisonDamage += m.poisonRate * elapsed
+
+	damage := int(m.poisonDamage)
+	m.poisonDamage -= float64(damage)
+
+	if !m.IsPoisoned() {
+		m.poisonRate, m.poisonDamage = 0, 0
+	}
+
+	if damage >= life {
+		damage = life - 1
+	}
+
+	if damage < 0 {
+		return 0
+	}
+
+	return damage
+}
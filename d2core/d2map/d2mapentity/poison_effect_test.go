@@ -0,0 +1,62 @@
+package d2mapentity
+
+import (
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestPoisonDoesNotStack(t *testing.T) {
+	assert := testify.New(t)
+
+	tests := []struct {
+		name     string
+		damage   int
+		duration float64
+		replaced bool
+		rate     float64 // damage per second of the poison in effect afterward
+	}{
+		{"weaker", 40, 1, false, 25},             // stronger per second, but less in total
+		{"as strong", 50, 1, false, 25},          // the same total isn't enough
+		{"stronger and longer", 60, 10, true, 6}, // weaker per second, but more in total
+		{"stronger and shorter", 80, 1, true, 80},
+		{"no damage", 0, 1, false, 25},
+		{"no duration", 100, 0, false, 25},
+	}
+
+	for _, test := range tests {
+		entity := createMapEntity(0, 0)
+		assert.True(entity.Poison(100, 4), test.name)
+
+		// 50 damage left to deal over the last 2 seconds
+		entity.advancePoison(2, 1000)
+
+		assert.Equal(test.replaced, entity.Poison(test.damage, test.duration), test.name)
+		assert.Equal(test.rate, entity.poisonRate, test.name)
+	}
+}
+
+func TestPoisonCannotKill(t *testing.T) {
+	assert := testify.New(t)
+
+	tests := []struct {
+		name   string
+		life   int
+		damage int
+	}{
+		{"healthy", 100, 25},
+		{"nearly dead", 10, 9},
+		{"at 1 life", 1, 0},
+		{"dead", 0, 0},
+	}
+
+	for _, test := range tests {
+		entity := createMapEntity(0, 0)
+		entity.Poison(100, 4)
+
+		assert.Equal(test.damage, entity.advancePoison(1, test.life), test.name)
+	}
+
+	entity := createMapEntity(0, 0)
+	assert.Zero(entity.advancePoison(1, 100), "without poison, no damage is dealt")
+}
@@ -0,0 +1,77 @@
+package d2mapentity
+
+import (
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+)
+
+// monsterPreloadModes are the animation modes a monster is seen in soon after it appears.
+//nolint:gochecknoglobals // constant list
+var monsterPreloadModes = []d2enum.MonsterAnimationMode{
+	d2enum.MonsterAnimationModeNeutral,
+	d2enum.MonsterAnimationModeWalk,
+	d2enum.MonsterAnimationModeAttack1,
+	d2enum.MonsterAnimationModeGetHit,
+	d2enum.MonsterAnimationModeDeath,
+	d2enum.MonsterAnimationModeDead,
+}
+
+// PreloadMonster decodes the animations of the monster, with each of its equipment options, so NPCs created for it
+// don't hitch the first time they are drawn.
+func PreloadMonster(monstat *d2datadict.MonStatsRecord) error {
+	monstatEx := d2datadict.MonStats2[monstat.ExtraDataKey]
+	if monstatEx == nil {
+		return fmt.Errorf("monster %s has no monstats2.txt record", monstat.Key)
+	}
+
+	composite, err := d2asset.LoadComposite(d2enum.ObjectTypeCharacter, monstat.AnimationDirectoryToken,
+		d2resource.PaletteUnits)
+	if err != nil {
+		return err
+	}
+
+	// each pass equips the next option of every component, until all the options were loaded once
+	for option := 0; ; option++ {
+		var equipment [d2enum.CompositeTypeMax]string
+
+		remaining := false
+
+		for compType, opts := range monstatEx.EquipmentOptions {
+			if len(opts) == 0 {
+				continue
+			}
+
+			if option < len(opts)-1 {
+				remaining = true
+			}
+
+			equipment[compType] = opts[d2common.MinInt(option, len(opts)-1)]
+		}
+
+		if err := composite.Equip(&equipment); err != nil {
+			return err
+		}
+
+		for _, mode := range monsterPreloadModes {
+			if err := composite.PreloadModes(monstatEx.BaseWeaponClass, mode); err != nil {
+				return err
+			}
+		}
+
+		if !remaining {
+			return nil
+		}
+	}
+}
+
+// PreloadMissile decodes the animation of the missile, so missiles created for it don't hitch the first time they are
+// drawn.
+func PreloadMissile(record *d2datadict.MissileRecord) error {
+	return d2asset.PreloadAnimation(fmt.Sprintf("%s/%s.dcc", d2resource.MissileData, record.Animation.CelFileName),
+		d2resource.PaletteUnits)
+}
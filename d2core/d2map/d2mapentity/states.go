@@ -0,0 +1,35 @@
+package d2mapentity
+
+import "sort"
+
+// AddState puts the named state, such as an aura or a toggled skill, on the entity. It stays until it is removed.
+func (m *mapEntity) AddState(name string) {
+	if m.states == nil {
+		m.states = make(map[string]bool)
+	}
+
+	m.states[name] = true
+}
+
+// RemoveState takes the named state off the entity.
+func (m *mapEntity) RemoveState(name string) {
+	delete(m.states, name)
+}
+
+// HasState returns true if the entity has the named state.
+func (m *mapEntity) HasState(name string) bool {
+	return m.states[name]
+}
+
+// States returns the names of all states on the entity, sorted.
+func (m *mapEntity) States() []string {
+	names := make([]string, 0, len(m.states))
+
+	for name := range m.states {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
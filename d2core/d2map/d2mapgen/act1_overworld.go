@@ -24,6 +24,15 @@ func loadPreset(mapEngine *d2mapengine.MapEngine, id, index int) *d2mapstamp.Sta
 	return d2mapstamp.LoadStamp(d2enum.RegionAct1Wilderness, id, index)
 }
 
+// placeLevelStamp places the stamp of a whole level and records where the level is on the map.
+func placeLevelStamp(mapEngine *d2mapengine.MapEngine, stamp *d2mapstamp.Stamp, x, y int) {
+	mapEngine.PlaceStamp(stamp, x, y)
+
+	size := stamp.Size()
+	bounds := d2common.Rectangle{Left: x, Top: y, Width: size.Width, Height: size.Height}
+	mapEngine.SetLevelBounds(stamp.LevelPreset().LevelID, bounds)
+}
+
 // GenerateAct1Overworld generates the map and entities for the first town and surrounding area.
 func GenerateAct1Overworld(mapEngine *d2mapengine.MapEngine) {
 	rand.Seed(mapEngine.Seed())
@@ -41,11 +50,11 @@ func GenerateAct1Overworld(mapEngine *d2mapengine.MapEngine) {
 	log.Printf("Region Path: %s", townStamp.RegionPath())
 	if strings.Contains(townStamp.RegionPath(), "E1") {
 		// East Exit
-		mapEngine.PlaceStamp(townStamp, 0, 0)
+		placeLevelStamp(mapEngine, townStamp, 0, 0)
 		generateWilderness1TownEast(mapEngine, townSize.Width, 0)
 	} else if strings.Contains(townStamp.RegionPath(), "S1") {
 		// South Exit
-		mapEngine.PlaceStamp(townStamp, mapWidth-townSize.Width, 0)
+		placeLevelStamp(mapEngine, townStamp, mapWidth-townSize.Width, 0)
 
 		// Generate the river running along the edge of the map
 		rightWaterBorderStamp := loadPreset(mapEngine, d2wilderness.WaterBorderEast, 0)
@@ -58,12 +67,12 @@ func GenerateAct1Overworld(mapEngine *d2mapengine.MapEngine) {
 		generateWilderness1TownSouth(mapEngine, mapWidth-wilderness1Details.SizeXNormal-14, townSize.Height)
 	} else if strings.Contains(townStamp.RegionPath(), "W1") {
 		// West Exit
-		mapEngine.PlaceStamp(townStamp, mapWidth-townSize.Width, mapHeight-townSize.Height)
+		placeLevelStamp(mapEngine, townStamp, mapWidth-townSize.Width, mapHeight-townSize.Height)
 
 		generateWilderness1TownWest(mapEngine, mapWidth-townSize.Width-wilderness1Details.SizeXNormal, mapHeight-wilderness1Details.SizeYNormal)
 	} else {
 		// North Exit
-		mapEngine.PlaceStamp(townStamp, mapWidth-townSize.Width, mapHeight-townSize.Height)
+		placeLevelStamp(mapEngine, townStamp, mapWidth-townSize.Width, mapHeight-townSize.Height)
 	}
 
 	mapEngine.RegenerateWalkPaths()
@@ -290,6 +299,7 @@ func generateWilderness1Contents(mapEngine *d2mapengine.MapEngine, rect d2common
 		loadPreset(mapEngine, d2wilderness.SwampFill2, 0),
 	}
 
+	mapEngine.SetLevelBounds(levelDetails.Id, rect)
 	mapEngine.PlaceStamp(denOfEvil, denOfEvilLoc.X, denOfEvilLoc.Y)
 
 	numPlaced := 0
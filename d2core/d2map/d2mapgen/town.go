@@ -0,0 +1,69 @@
+package d2mapgen
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+)
+
+// townRegions are the regions of the towns of each act.
+//nolint:gochecknoglobals // constant lookup table
+var townRegions = [...]d2enum.RegionIdType{
+	d2enum.RegionAct1Town,
+	d2enum.RegionAct2Town,
+	d2enum.RegionAct3Town,
+	d2enum.RegionAct4Town,
+	d2enum.RegonAct5Town,
+}
+
+// townPresets are the level presets of the towns of acts II to V, which are stamped whole. The act I town is
+// generated along with its wilderness.
+//nolint:gochecknoglobals // constant lookup table
+var townPresets = map[d2enum.RegionIdType]int{
+	d2enum.RegionAct2Town: 301,
+	d2enum.RegionAct3Town: 529,
+	d2enum.RegionAct4Town: 797,
+	d2enum.RegonAct5Town:  863,
+}
+
+// TownRegion returns the region of the town of the act, 1 based. Returns RegionNone if there is no such act.
+func TownRegion(act int) d2enum.RegionIdType {
+	if act < 1 || act > len(townRegions) {
+		return d2enum.RegionNone
+	}
+
+	return townRegions[act-1]
+}
+
+// TownAct returns the act of the town region, 0 if the region isn't a town.
+func TownAct(region d2enum.RegionIdType) int {
+	for idx, town := range townRegions {
+		if town == region {
+			return idx + 1
+		}
+	}
+
+	return 0
+}
+
+// GenerateTown generates the map and entities of the town of the act.
+func GenerateTown(mapEngine *d2mapengine.MapEngine, act int) error {
+	region := TownRegion(act)
+
+	if region == d2enum.RegionAct1Town {
+		GenerateAct1Overworld(mapEngine)
+		return nil
+	}
+
+	preset, ok := townPresets[region]
+	if !ok {
+		return fmt.Errorf("there is no town in act %d", act)
+	}
+
+	rand.Seed(mapEngine.Seed())
+	mapEngine.GenerateMap(region, preset, 0, false)
+
+	return nil
+}
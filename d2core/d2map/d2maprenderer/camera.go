@@ -1,25 +1,269 @@
 package d2maprenderer
 
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
 // Camera is the position of the camera perspective in orthogonal world space. See viewport.go.
 // TODO: Has a coordinate (issue #456)
 type Camera struct {
-	x float64
-	y float64
+	x    float64
+	y    float64
+	zoom float64 // 0 until set, for a zoom of 1
+	pan  *cameraPan
+
+	shakes         []cameraShake
+	shakeX, shakeY float64    // offset of the shakes for the current frame
+	shakeRand      *rand.Rand // nil until the first shake, unless seeded
+
+	bounds     *d2common.Rectangle // world space area the view is kept within, nil to move freely
+	viewWidth  int                 // orthogonal size of the view, set by the viewport
+	viewHeight int
+
+	halfTileWidth, halfTileHeight float64 // orthogonal size of half a tile, set by the viewport, 0 for the default
+
+	follow *cameraFollow // nil unless following a target
+
+	offsetX, offsetY float64     // offset of the view from the camera position, in orthogonal pixels
+	lead             *cameraLead // nil unless the view leads the movement of the camera
+
+	velocityX, velocityY float64 // velocity of the gliding camera, in orthogonal pixels per second
+	friction             float64 // 0 until set, for the default friction
+
+	moveListeners  []*cameraMoveListener // callbacks registered with OnMove
+	movedX, movedY float64               // position the callbacks were last called with
+	notifyingMove  bool                  // whether the callbacks are being called, to ignore moves they make
+
+	rotation int // quarter turns the view of the map is rotated by
+
+	timestep    time.Duration // duration of the steps Advance moves the camera in, 0 for the elapsed time of each call
+	accumulated time.Duration // time elapsed which wasn't stepped yet, less than the timestep
+}
+
+// CameraEasing is how the camera speeds up and slows down when it pans to a position.
+type CameraEasing int
+
+// Camera easings
+const (
+	CameraEasingLinear  CameraEasing = iota // constant speed
+	CameraEasingEaseOut                     // fast at first, slowing down to a stop
+)
+
+// cameraPan is the camera easing from one position to another.
+type cameraPan struct {
+	fromX, fromY float64
+	toX, toY     float64
+	duration     time.Duration
+	elapsed      time.Duration
+	ease         EaseFunc
+}
+
+// progress returns how far along the pan the camera is, from 0 to 1, after easing.
+func (p *cameraPan) progress() float64 {
+	return p.ease(math.Min(1, float64(p.elapsed)/float64(p.duration)))
+}
+
+const (
+	minZoom = 0.25
+	maxZoom = 4.0
+)
+
+// SetZoom sets the zoom factor of the camera, clamped between 0.25 and 4. Above 1 the map is zoomed in. The factor is
+// rounded so the tiles are a whole number of pixels, as tiles drawn at fractions of pixels leave seams between them.
+func (c *Camera) SetZoom(factor float64) {
+	c.zoom = c.snapZoom(math.Max(minZoom, math.Min(maxZoom, factor)))
+}
+
+// snapZoom returns the zoom factor rounded so half a tile is a whole number of pixels, unless half a tile isn't a
+// whole number of pixels at a zoom of 1 either.
+func (c *Camera) snapZoom(factor float64) float64 {
+	halfWidth, halfHeight := c.halfTileWidth, c.halfTileHeight
+	if halfWidth == 0 || halfHeight == 0 {
+		halfWidth, halfHeight = tileHalfWidth, tileHalfHeight
+	}
+
+	if halfWidth != math.Trunc(halfWidth) || halfHeight != math.Trunc(halfHeight) {
+		return factor
+	}
+
+	// the zoom factors at which both halves are whole are the multiples of 1 / their greatest common divisor
+	steps := float64(gcd(int(halfWidth), int(halfHeight)))
+
+	return math.Max(1, math.Round(factor*steps)) / steps
+}
+
+// gcd returns the greatest common divisor of two positive numbers.
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
 }
 
-// MoveTo sets the position of the camera to the given x and y coordinates.
-func (c *Camera) MoveTo(x, y float64) {
-	c.x = x
-	c.y = y
+// GetZoom returns the zoom factor of the camera.
+func (c *Camera) GetZoom() float64 {
+	if c.zoom == 0 {
+		return 1
+	}
+
+	return c.zoom
 }
 
-// MoveBy adds the given vector to the current position of the camera.
+// MoveTo pans the camera from its current position to the given x and y coordinates over the duration, moving it
+// there right away if the duration is 0. Panning again before the camera arrives starts over from where it is.
+func (c *Camera) MoveTo(x, y float64, duration time.Duration, easing CameraEasing) {
+	c.stopGlide()
+
+	if duration <= 0 {
+		c.x, c.y = x, y
+		c.pan = nil
+
+		return
+	}
+
+	c.pan = &cameraPan{fromX: c.x, fromY: c.y, toX: x, toY: y, duration: duration, ease: easing.easeFunc()}
+}
+
+// MoveBy adds the given vector to the current position of the camera, and to the position it is panning to.
 func (c *Camera) MoveBy(x, y float64) {
 	c.x += x
 	c.y += y
+
+	if c.pan != nil {
+		c.pan.fromX += x
+		c.pan.fromY += y
+		c.pan.toX += x
+		c.pan.toY += y
+	}
 }
 
-// GetPosition returns the camera x and y position.
+// Advance moves the camera along the pan it is making, toward the target it follows, or by the velocity it glides
+// with, shakes it, and moves the view ahead of its movement. With a fixed timestep, the camera moves in as many steps
+// as the elapsed time adds up to. The callbacks registered with OnMove are then called if the position changed.
+func (c *Camera) Advance(elapsed time.Duration) {
+	defer c.notifyMove()
+
+	if c.timestep <= 0 {
+		c.step(elapsed)
+		return
+	}
+
+	c.accumulated += elapsed
+
+	for c.accumulated >= c.timestep {
+		c.accumulated -= c.timestep
+		c.step(c.timestep)
+	}
+}
+
+// step moves the camera by one step of the given duration.
+func (c *Camera) step(elapsed time.Duration) {
+	c.advanceShakes(elapsed)
+	c.advanceLead(elapsed)
+	c.advanceGlide(elapsed)
+
+	if c.pan == nil {
+		c.advanceFollow(elapsed)
+		return
+	}
+
+	c.pan.elapsed += elapsed
+	progress := c.pan.progress()
+
+	c.x = c.pan.fromX + (c.pan.toX-c.pan.fromX)*progress
+	c.y = c.pan.fromY + (c.pan.toY-c.pan.fromY)*progress
+
+	if c.pan.elapsed >= c.pan.duration {
+		c.x, c.y = c.pan.toX, c.pan.toY
+		c.pan = nil
+	}
+}
+
+// IsMoving returns true while the camera is panning to a position.
+func (c *Camera) IsMoving() bool {
+	return c.pan != nil
+}
+
+// GetPosition returns the x and y position of the center of the view: the camera position moved by the offset and the
+// lead of the view, clamped so the view stays within the bounds if there are any.
 func (c *Camera) GetPosition() (float64, float64) {
-	return c.x, c.y
+	offsetX, offsetY := c.viewOffset()
+
+	return c.clampToBounds(c.x+offsetX, c.y+offsetY)
+}
+
+// clampToBounds returns the position of the center of the view, clamped so the view stays within the bounds if there
+// are any.
+func (c *Camera) clampToBounds(x, y float64) (float64, float64) {
+	if c.bounds == nil {
+		return x, y
+	}
+
+	halfWidth, halfHeight := c.tileHalfSize()
+
+	boundsLeft, boundsTop, boundsRight, boundsBottom := rotateWorldRect(float64(c.bounds.Left), float64(c.bounds.Top),
+		float64(c.bounds.Right()), float64(c.bounds.Bottom()), c.rotation)
+
+	// the orthogonal box around the world space bounds, which are a diamond on screen
+	left := (boundsLeft - boundsBottom) * halfWidth
+	right := (boundsRight - boundsTop) * halfWidth
+	top := (boundsLeft + boundsTop) * halfHeight
+	bottom := (boundsRight + boundsBottom) * halfHeight
+
+	return clampView(x, left, right, float64(c.viewWidth)), clampView(y, top, bottom, float64(c.viewHeight))
+}
+
+// SetBounds keeps the view of the camera within the given world space area. Along an axis where the area is smaller
+// than the view, the view is centered on the area instead.
+func (c *Camera) SetBounds(rect d2common.Rectangle) {
+	c.bounds = &rect
+}
+
+// ClearBounds lets the camera move freely again.
+func (c *Camera) ClearBounds() {
+	c.bounds = nil
+}
+
+// setViewSize sets the orthogonal size of the view the bounds are applied to. The position of the camera is at the
+// center of the view.
+func (c *Camera) setViewSize(width, height int) {
+	c.viewWidth = width
+	c.viewHeight = height
+}
+
+// setTileSize sets the orthogonal size of half a tile at a zoom of 1, which the bounds are projected with and the zoom
+// is rounded to.
+func (c *Camera) setTileSize(halfWidth, halfHeight float64) {
+	c.halfTileWidth = halfWidth
+	c.halfTileHeight = halfHeight
+
+	if c.zoom != 0 {
+		c.zoom = c.snapZoom(c.zoom)
+	}
+}
+
+// tileHalfSize returns the orthogonal size of half a tile at the zoom of the camera.
+func (c *Camera) tileHalfSize() (float64, float64) {
+	halfWidth, halfHeight := c.halfTileWidth, c.halfTileHeight
+	if halfWidth == 0 || halfHeight == 0 {
+		halfWidth, halfHeight = tileHalfWidth, tileHalfHeight
+	}
+
+	zoom := c.GetZoom()
+
+	return halfWidth * zoom, halfHeight * zoom
+}
+
+// clampView returns the center of a view of the size along an axis, clamped to keep the view between min and max.
+func clampView(center, min, max, size float64) float64 {
+	if max-min <= size {
+		return (min + max) / 2
+	}
+
+	return math.Max(min+size/2, math.Min(max-size/2, center))
 }
@@ -0,0 +1,57 @@
+package d2maprenderer
+
+import "time"
+
+// EaseFunc maps the normalized time of a camera pan, from 0 to 1, to how far along the pan the camera is, from 0 at
+// the start to 1 at the target.
+type EaseFunc func(t float64) float64
+
+// EaseLinear moves the camera at a constant speed.
+func EaseLinear(t float64) float64 {
+	return t
+}
+
+// EaseInOutQuad speeds the camera up over the first half of the pan and slows it down over the second half.
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 { //nolint:gomnd // half of the pan
+		return 2 * t * t
+	}
+
+	return 1 - 2*(1-t)*(1-t)
+}
+
+// EaseOutCubic starts the camera fast and slows it down to a stop, more sharply than CameraEasingEaseOut.
+func EaseOutCubic(t float64) float64 {
+	return 1 - (1-t)*(1-t)*(1-t)
+}
+
+// easeOutQuad starts the camera fast and slows it down to a stop, for CameraEasingEaseOut.
+func easeOutQuad(t float64) float64 {
+	return 1 - (1-t)*(1-t)
+}
+
+// easeFunc returns the function of the camera easing.
+func (e CameraEasing) easeFunc() EaseFunc {
+	if e == CameraEasingEaseOut {
+		return easeOutQuad
+	}
+
+	return EaseLinear
+}
+
+// PanTo pans the camera from its current position to the given x and y coordinates over the duration, easing its
+// progress with the function, for example to script the camera of a cinematic. The camera stops following its target,
+// and lands exactly on the position once the duration is over. A duration of 0 moves the camera there right away.
+func (c *Camera) PanTo(x, y float64, duration time.Duration, ease EaseFunc) {
+	c.follow = nil
+	c.stopGlide()
+
+	if duration <= 0 {
+		c.x, c.y = x, y
+		c.pan = nil
+
+		return
+	}
+
+	c.pan = &cameraPan{fromX: c.x, fromY: c.y, toX: x, toY: y, duration: duration, ease: ease}
+}
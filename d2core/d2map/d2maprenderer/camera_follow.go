@@ -0,0 +1,78 @@
+package d2maprenderer
+
+import (
+	"math"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+const (
+	// followEasing is the time constant of the camera easing back onto a followed target: after it, the camera has
+	// closed about two thirds of the distance.
+	followEasing = 150 * time.Millisecond
+
+	// followSnapDistance is how close to the target, in orthogonal pixels, the camera snaps onto it.
+	followSnapDistance = 0.5
+)
+
+// cameraFollow is the target the camera follows, and the deadzone it can move in without the camera moving.
+type cameraFollow struct {
+	target     func() (float64, float64)
+	deadzone   d2common.Rectangle
+	recentered bool // false while the camera eases back onto the target after it left the deadzone
+}
+
+// Follow makes the camera follow the orthogonal position the target returns. The camera stays put while the target
+// is within the deadzone, a rectangle of the deadzone's size in screen pixels centered on the view, and once the
+// target leaves it, eases to center on the target again. Only the size of the deadzone is used. The camera doesn't
+// follow while MoveTo pans it to a position, and stays within its bounds if it has any. Following a target cancels
+// the pan in progress. A nil target stops following.
+func (c *Camera) Follow(target func() (float64, float64), deadzone d2common.Rectangle) {
+	if target == nil {
+		c.follow = nil
+		return
+	}
+
+	c.pan = nil
+	c.stopGlide()
+	c.follow = &cameraFollow{target: target, deadzone: deadzone, recentered: true}
+}
+
+// IsFollowing returns true while the camera follows a target.
+func (c *Camera) IsFollowing() bool {
+	return c.follow != nil
+}
+
+// advanceFollow moves the camera toward the followed target, if it left the deadzone since the camera last centered
+// on it.
+func (c *Camera) advanceFollow(elapsed time.Duration) {
+	if c.follow == nil {
+		return
+	}
+
+	targetX, targetY := c.follow.target()
+	offsetX, offsetY := targetX-c.x, targetY-c.y
+
+	if c.follow.recentered {
+		outsideX := math.Abs(offsetX) > float64(c.follow.deadzone.Width)/2
+		outsideY := math.Abs(offsetY) > float64(c.follow.deadzone.Height)/2
+
+		if !outsideX && !outsideY {
+			return
+		}
+
+		c.follow.recentered = false
+	}
+
+	if math.Hypot(offsetX, offsetY) <= followSnapDistance {
+		c.x, c.y = targetX, targetY
+		c.follow.recentered = true
+
+		return
+	}
+
+	closed := 1 - math.Exp(-float64(elapsed)/float64(followEasing))
+	c.x += offsetX * closed
+	c.y += offsetY * closed
+}
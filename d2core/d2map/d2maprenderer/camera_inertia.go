@@ -0,0 +1,83 @@
+package d2maprenderer
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// defaultFriction is how fast a gliding camera slows down, as the rate of its velocity lost per second.
+	defaultFriction = 4.0
+
+	// minGlideSpeed is the speed, in orthogonal pixels per second, under which a gliding camera stops.
+	minGlideSpeed = 1.0
+)
+
+// ApplyImpulse sets the velocity of the camera, in orthogonal pixels per second, so it keeps gliding after keyboard
+// or drag panning stops, slowing down with the friction until it comes to rest. The glide stops the pan in progress
+// and the target the camera follows, and stops along an axis where the view hits the bounds.
+func (c *Camera) ApplyImpulse(vx, vy float64) {
+	c.pan = nil
+	c.follow = nil
+	c.velocityX, c.velocityY = vx, vy
+}
+
+// SetFriction sets how fast a gliding camera slows down, as the rate of its velocity lost per second. Higher values
+// stop the camera sooner. A friction of 0 or less restores the default.
+func (c *Camera) SetFriction(f float64) {
+	c.friction = math.Max(0, f)
+}
+
+// GetVelocity returns the velocity of the gliding camera, in orthogonal pixels per second.
+func (c *Camera) GetVelocity() (float64, float64) {
+	return c.velocityX, c.velocityY
+}
+
+// stopGlide stops the camera gliding.
+func (c *Camera) stopGlide() {
+	c.velocityX, c.velocityY = 0, 0
+}
+
+// advanceGlide moves the gliding camera by its velocity, slows it down with the friction, and stops it once it is
+// slow enough or along the axes where the view hit the bounds.
+func (c *Camera) advanceGlide(elapsed time.Duration) {
+	if (c.velocityX == 0 && c.velocityY == 0) || elapsed <= 0 {
+		return
+	}
+
+	seconds := elapsed.Seconds()
+	c.x += c.velocityX * seconds
+	c.y += c.velocityY * seconds
+
+	friction := c.friction
+	if friction == 0 {
+		friction = defaultFriction
+	}
+
+	decay := math.Exp(-friction * seconds)
+	c.velocityX *= decay
+	c.velocityY *= decay
+
+	if math.Hypot(c.velocityX, c.velocityY) < minGlideSpeed {
+		c.stopGlide()
+	}
+
+	if c.bounds == nil {
+		return
+	}
+
+	// keep the camera against the wall it hit rather than past it, so it moves away from the wall right away
+	offsetX, offsetY := c.viewOffset()
+	viewX, viewY := c.x+offsetX, c.y+offsetY
+	clampedX, clampedY := c.clampToBounds(viewX, viewY)
+
+	if clampedX != viewX {
+		c.x += clampedX - viewX
+		c.velocityX = 0
+	}
+
+	if clampedY != viewY {
+		c.y += clampedY - viewY
+		c.velocityY = 0
+	}
+}
@@ -0,0 +1,130 @@
+package d2maprenderer
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// leadEasing is the time constant of the camera easing toward the lead of the current movement, so the view
+	// doesn't jerk when the movement starts, stops or turns.
+	leadEasing = 300 * time.Millisecond
+
+	// leadWindow is how far back the positions the view leads are kept to estimate their velocity, smoothing out the
+	// turns of the movement so the view doesn't jitter.
+	leadWindow = 250 * time.Millisecond
+
+	// maxLeadDistance is the most orthogonal pixels the view leads the movement by, at a zoom of 1.
+	maxLeadDistance = 160.0
+)
+
+// cameraLead moves the view ahead of the camera, in the direction the target it follows moves, or else the camera.
+type cameraLead struct {
+	seconds    float64      // how far ahead the view looks, in seconds of movement
+	samples    []leadSample // recent positions the view leads, oldest first
+	following  bool         // whether the samples are positions of the followed target rather than of the camera
+	followOnly bool         // whether the view only leads a followed target, and centers on the camera otherwise
+	x, y       float64      // current offset of the view, in orthogonal pixels
+}
+
+// leadSample is a position the view leads, and how long ago it was there.
+type leadSample struct {
+	x, y float64
+	age  time.Duration
+}
+
+// SetOffset moves the view away from the camera position by the given orthogonal pixels, for example to show more of
+// the map above the hero. GetPosition returns the position of the view.
+func (c *Camera) SetOffset(x, y float64) {
+	c.offsetX, c.offsetY = x, y
+}
+
+// SetLead makes the view look ahead by the given seconds of movement of the target the camera follows, or of the camera
+// when it follows nothing. The velocity is estimated from the positions over the last quarter of a second and the view
+// eases toward it, so it doesn't jitter when the movement turns. GetPosition returns the position of the view, which
+// stays within the bounds of the camera. A lead of 0 centers the view on the camera again.
+func (c *Camera) SetLead(seconds float64) {
+	c.setLead(seconds, false)
+}
+
+// SetLookAhead makes the view look ahead by the given seconds of movement of the target the camera follows, like
+// SetLead, but centers the view on the camera while it follows nothing. A look-ahead of 0 centers the view on the
+// followed target again.
+func (c *Camera) SetLookAhead(seconds float64) {
+	c.setLead(seconds, true)
+}
+
+func (c *Camera) setLead(seconds float64, followOnly bool) {
+	if seconds <= 0 {
+		c.lead = nil
+		return
+	}
+
+	if c.lead == nil {
+		c.lead = &cameraLead{}
+	}
+
+	c.lead.seconds = seconds
+	c.lead.followOnly = followOnly
+}
+
+// advanceLead samples the position the view leads, and eases the lead toward its velocity over the lead window.
+func (c *Camera) advanceLead(elapsed time.Duration) {
+	if c.lead == nil || elapsed <= 0 {
+		return
+	}
+
+	lead := c.lead
+
+	if following := c.follow != nil; following != lead.following {
+		lead.samples = lead.samples[:0]
+		lead.following = following
+	}
+
+	x, y := c.x, c.y
+	if lead.following {
+		x, y = c.follow.target()
+	}
+
+	for index := range lead.samples {
+		lead.samples[index].age += elapsed
+	}
+
+	lead.samples = append(lead.samples, leadSample{x: x, y: y})
+
+	// the oldest sample kept is the last one at least a window old, so long frames still have a velocity
+	expired := 0
+	for expired+1 < len(lead.samples) && lead.samples[expired+1].age >= leadWindow {
+		expired++
+	}
+
+	lead.samples = append(lead.samples[:0], lead.samples[expired:]...)
+
+	var targetX, targetY float64
+
+	if oldest := lead.samples[0]; oldest.age > 0 && (lead.following || !lead.followOnly) {
+		targetX = (x - oldest.x) / oldest.age.Seconds() * lead.seconds
+		targetY = (y - oldest.y) / oldest.age.Seconds() * lead.seconds
+	}
+
+	if distance, maxDistance := math.Hypot(targetX, targetY), maxLeadDistance*c.GetZoom(); distance > maxDistance {
+		targetX *= maxDistance / distance
+		targetY *= maxDistance / distance
+	}
+
+	closed := 1 - math.Exp(-float64(elapsed)/float64(leadEasing))
+	lead.x += (targetX - lead.x) * closed
+	lead.y += (targetY - lead.y) * closed
+}
+
+// viewOffset returns the offset of the view from the camera position, in orthogonal pixels.
+func (c *Camera) viewOffset() (x, y float64) {
+	x, y = c.offsetX, c.offsetY
+
+	if c.lead != nil {
+		x += c.lead.x
+		y += c.lead.y
+	}
+
+	return x, y
+}
@@ -0,0 +1,52 @@
+package d2maprenderer
+
+// cameraMoveListener is a callback registered with OnMove.
+type cameraMoveListener struct {
+	fn func(x, y float64)
+}
+
+// OnMove registers a callback called with the position GetPosition returns whenever it changes, whether the camera
+// follows a target, pans, glides or was moved directly, and returns a function which unregisters it. The callbacks run
+// synchronously inside Advance, once the camera has moved, in the order they were registered, and aren't called when
+// the position is the same as on the previous tick. A callback moving the camera is called again on the next tick
+// rather than right away.
+func (c *Camera) OnMove(fn func(x, y float64)) (unsubscribe func()) {
+	if len(c.moveListeners) == 0 {
+		c.movedX, c.movedY = c.GetPosition()
+	}
+
+	listener := &cameraMoveListener{fn: fn}
+	c.moveListeners = append(c.moveListeners, listener)
+
+	return func() {
+		for index, registered := range c.moveListeners {
+			if registered == listener {
+				c.moveListeners = append(c.moveListeners[:index], c.moveListeners[index+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// notifyMove calls the callbacks registered with OnMove if the position changed since they were last called.
+func (c *Camera) notifyMove() {
+	if len(c.moveListeners) == 0 || c.notifyingMove {
+		return
+	}
+
+	x, y := c.GetPosition()
+	if x == c.movedX && y == c.movedY {
+		return
+	}
+
+	c.movedX, c.movedY = x, y
+	c.notifyingMove = true
+
+	defer func() { c.notifyingMove = false }()
+
+	// callbacks may unsubscribe while the others are called
+	listeners := append([]*cameraMoveListener(nil), c.moveListeners...)
+	for _, listener := range listeners {
+		listener.fn(x, y)
+	}
+}
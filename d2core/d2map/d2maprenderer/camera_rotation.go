@@ -0,0 +1,49 @@
+package d2maprenderer
+
+import (
+	"math"
+)
+
+// quartersPerTurn is the number of quarter turns the camera can be rotated by.
+const quartersPerTurn = 4
+
+// SetRotation turns the view of the map by quarter turns around the vertical axis, 0 to 3, clockwise from the
+// orientation of the game. Other values are wrapped. The world is rotated around its origin before it is projected, so
+// the camera must be moved for the view to stay on the same point, which MapRenderer.SetCameraRotation does.
+func (c *Camera) SetRotation(quarter int) {
+	c.rotation = ((quarter % quartersPerTurn) + quartersPerTurn) % quartersPerTurn
+}
+
+// GetRotation returns the quarter turns the view of the map is rotated by, 0 to 3.
+func (c *Camera) GetRotation() int {
+	return c.rotation
+}
+
+// rotateWorld turns the world position by the quarter turns around the origin of the world. Quarter turns only swap
+// and negate the coordinates, so they are undone exactly.
+func rotateWorld(x, y float64, quarter int) (float64, float64) {
+	switch quarter {
+	case 1:
+		return -y, x
+	case 2:
+		return -x, -y
+	case 3:
+		return y, -x
+	default:
+		return x, y
+	}
+}
+
+// unrotateWorld undoes rotateWorld.
+func unrotateWorld(x, y float64, quarter int) (float64, float64) {
+	return rotateWorld(x, y, (quartersPerTurn-quarter)%quartersPerTurn)
+}
+
+// rotateWorldRect returns the rectangle around the world space rectangle turned by the quarter turns.
+func rotateWorldRect(left, top, right, bottom float64, quarter int) (rotatedLeft, rotatedTop, rotatedRight,
+	rotatedBottom float64) {
+	x1, y1 := rotateWorld(left, top, quarter)
+	x2, y2 := rotateWorld(right, bottom, quarter)
+
+	return math.Min(x1, x2), math.Min(y1, y2), math.Max(x1, x2), math.Max(y1, y2)
+}
@@ -0,0 +1,78 @@
+package d2maprenderer
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// cameraShake is a random offset of the camera, dying down over its duration.
+type cameraShake struct {
+	magnitude float64 // largest offset, in orthogonal pixels
+	duration  time.Duration
+	elapsed   time.Duration
+}
+
+// amplitude returns the largest offset of the shake at this point of it, easing down to 0 at the end.
+func (s *cameraShake) amplitude() float64 {
+	remaining := 1 - math.Min(1, float64(s.elapsed)/float64(s.duration))
+
+	return s.magnitude * remaining * remaining
+}
+
+// Shake shakes the view of the camera by up to magnitude orthogonal pixels, dying down over the duration. The shake
+// adds to any shakes already going on. It only moves the render position, not the position of the camera.
+func (c *Camera) Shake(magnitude float64, duration time.Duration) {
+	if magnitude <= 0 || duration <= 0 {
+		return
+	}
+
+	c.shakes = append(c.shakes, cameraShake{magnitude: magnitude, duration: duration})
+}
+
+// SetShakeSeed seeds the random offsets of the shakes, to repeat them.
+func (c *Camera) SetShakeSeed(seed int64) {
+	c.shakeRand = rand.New(rand.NewSource(seed)) //nolint:gosec // shakes are cosmetic
+}
+
+// IsShaking returns true while any shake is going on.
+func (c *Camera) IsShaking() bool {
+	return len(c.shakes) > 0
+}
+
+// GetRenderPosition returns the position the map is rendered from, which is the position of the camera offset by
+// the shakes going on.
+func (c *Camera) GetRenderPosition() (float64, float64) {
+	x, y := c.GetPosition()
+
+	return x + c.shakeX, y + c.shakeY
+}
+
+// advanceShakes moves the shakes along and picks the offset for the next frame.
+func (c *Camera) advanceShakes(elapsed time.Duration) {
+	c.shakeX, c.shakeY = 0, 0
+
+	if len(c.shakes) == 0 {
+		return
+	}
+
+	if c.shakeRand == nil {
+		c.SetShakeSeed(time.Now().UnixNano())
+	}
+
+	amplitude := 0.0
+	shaking := c.shakes[:0]
+
+	for _, shake := range c.shakes {
+		shake.elapsed += elapsed
+		amplitude += shake.amplitude()
+
+		if shake.elapsed < shake.duration {
+			shaking = append(shaking, shake)
+		}
+	}
+
+	c.shakes = shaking
+	c.shakeX = amplitude * (c.shakeRand.Float64()*2 - 1)
+	c.shakeY = amplitude * (c.shakeRand.Float64()*2 - 1)
+}
@@ -0,0 +1,517 @@
+package d2maprenderer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestCameraBounds(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+
+	// a 20x20 tile map is 3200x1600 orthogonally, from -1600 to 1600 and 0 to 1600
+	camera.SetBounds(d2common.Rectangle{Width: 20, Height: 20})
+
+	camera.MoveTo(0, 800, 0, CameraEasingLinear)
+	x, y := camera.GetPosition()
+	assert.Equal(0.0, x, "the view is within the bounds")
+	assert.Equal(800.0, y)
+
+	camera.MoveTo(-1590, 10, 0, CameraEasingLinear)
+	x, y = camera.GetPosition()
+	assert.Equal(-1200.0, x, "the left edge of the view stops at the left of the map")
+	assert.Equal(300.0, y, "the top edge of the view stops at the top of the map")
+
+	screenX, screenY := viewport.WorldToScreen(0, 20)
+	assert.Equal(0, screenX, "the left corner of the map is at the left of the screen")
+	assert.Equal(800, screenY)
+
+	camera.SetZoom(0.25)
+	x, y = camera.GetPosition()
+	assert.Equal(0.0, x, "the map is smaller than the view, so the view is centered on it")
+	assert.Equal(200.0, y)
+
+	camera.ClearBounds()
+	x, y = camera.GetPosition()
+	assert.Equal(-1590.0, x)
+	assert.Equal(10.0, y)
+}
+
+func TestCameraShake(t *testing.T) {
+	assert := testify.New(t)
+
+	shakeOffsets := func(seed int64) []float64 {
+		camera := &Camera{}
+		camera.MoveTo(100, 50, 0, CameraEasingLinear)
+		camera.SetShakeSeed(seed)
+		camera.Shake(10, 100*time.Millisecond)
+
+		offsets := make([]float64, 0)
+
+		for frame := 0; frame < 5; frame++ {
+			camera.Advance(20 * time.Millisecond)
+
+			x, y := camera.GetPosition()
+			assert.Equal(100.0, x, "shakes don't move the camera")
+			assert.Equal(50.0, y)
+
+			renderX, renderY := camera.GetRenderPosition()
+			offsets = append(offsets, renderX-x, renderY-y)
+		}
+
+		assert.False(camera.IsShaking())
+
+		return offsets
+	}
+
+	offsets := shakeOffsets(1)
+	assert.Equal(offsets, shakeOffsets(1), "seeded shakes repeat")
+
+	for frame := 0; frame < 4; frame++ {
+		limit := 10 * (0.8 - 0.2*float64(frame)) * (0.8 - 0.2*float64(frame))
+		assert.LessOrEqual(math.Abs(offsets[frame*2]), limit+1e-9, "the shake dies down")
+		assert.LessOrEqual(math.Abs(offsets[frame*2+1]), limit+1e-9)
+	}
+
+	assert.Equal([]float64{0, 0}, offsets[8:], "the shake ends without an offset")
+
+	camera := &Camera{}
+	camera.SetShakeSeed(1)
+	camera.Shake(1, time.Second)
+	camera.Shake(1, time.Second)
+	camera.Advance(0)
+
+	assert.Len(camera.shakes, 2, "shakes add up")
+}
+
+func TestCameraFollow(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	targetX, targetY := 0.0, 0.0
+	target := func() (float64, float64) { return targetX, targetY }
+
+	camera.Follow(target, d2common.Rectangle{Width: 100, Height: 60})
+	assert.True(camera.IsFollowing())
+
+	targetX, targetY = 40, -20
+	camera.Advance(100 * time.Millisecond)
+
+	x, y := camera.GetPosition()
+	assert.Equal(0.0, x, "the target is within the deadzone")
+	assert.Equal(0.0, y)
+
+	targetX = 60
+	camera.Advance(100 * time.Millisecond)
+
+	x, y = camera.GetPosition()
+	assert.True(x > 0 && x < 60, "the camera eases toward the target once it leaves the deadzone")
+	assert.True(y < 0 && y > -20, "the camera eases toward the target on both axes")
+
+	for frame := 0; frame < 60; frame++ {
+		camera.Advance(20 * time.Millisecond)
+	}
+
+	x, y = camera.GetPosition()
+	assert.Equal(60.0, x, "the camera centers on the target again")
+	assert.Equal(-20.0, y)
+
+	targetX = 100
+	camera.Advance(100 * time.Millisecond)
+
+	x, _ = camera.GetPosition()
+	assert.Equal(60.0, x, "the deadzone is centered on the camera again")
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+	camera.SetBounds(d2common.Rectangle{Width: 20, Height: 20})
+
+	targetX, targetY = 5000, 800
+
+	for frame := 0; frame < 200; frame++ {
+		camera.Advance(20 * time.Millisecond)
+	}
+
+	x, y = camera.GetPosition()
+	assert.Equal(1200.0, x, "the followed camera stays within the bounds")
+	assert.Equal(800.0, y)
+
+	camera.Follow(nil, d2common.Rectangle{})
+	assert.False(camera.IsFollowing())
+
+	targetX = 0
+	camera.Advance(time.Second)
+
+	x, _ = camera.GetPosition()
+	assert.Equal(1200.0, x, "the camera stopped following")
+}
+
+func TestCameraOffsetAndLead(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.SetOffset(0, -50)
+
+	x, y := camera.GetPosition()
+	assert.Equal(0.0, x)
+	assert.Equal(-50.0, y, "the view is offset from the camera")
+
+	camera.SetOffset(0, 0)
+	camera.SetLead(0.5)
+
+	// move right at 100 pixels a second
+	for frame := 0; frame < 100; frame++ {
+		camera.MoveBy(2, 0)
+		camera.Advance(20 * time.Millisecond)
+	}
+
+	x, y = camera.GetPosition()
+	assert.InDelta(camera.x+50, x, 0.1, "the view leads the movement by half a second")
+	assert.InDelta(0.0, y, 1e-9)
+
+	// move much faster than the lead can keep up with
+	for frame := 0; frame < 100; frame++ {
+		camera.MoveBy(100, 0)
+		camera.Advance(20 * time.Millisecond)
+	}
+
+	x, _ = camera.GetPosition()
+	assert.InDelta(camera.x+maxLeadDistance, x, 0.5, "the lead is capped")
+
+	camera.Advance(20 * time.Millisecond)
+
+	x, _ = camera.GetPosition()
+	assert.True(x > camera.x && x < camera.x+maxLeadDistance, "the lead eases back once the movement stops")
+
+	for frame := 0; frame < 200; frame++ {
+		camera.Advance(20 * time.Millisecond)
+	}
+
+	x, _ = camera.GetPosition()
+	assert.InDelta(camera.x, x, 0.1, "the view centers on the camera again")
+
+	camera.SetLead(0)
+
+	x, _ = camera.GetPosition()
+	assert.Equal(camera.x, x)
+}
+
+func TestEaseFuncs(t *testing.T) {
+	assert := testify.New(t)
+
+	eases := map[string]EaseFunc{"linear": EaseLinear, "in-out quad": EaseInOutQuad, "out cubic": EaseOutCubic}
+
+	for name, ease := range eases {
+		assert.Equal(0.0, ease(0), "%s starts at 0", name)
+		assert.Equal(1.0, ease(1), "%s ends at 1", name)
+	}
+
+	assert.Equal(0.5, EaseInOutQuad(0.5))
+	assert.Equal(0.125, EaseInOutQuad(0.25), "slow at first")
+	assert.Equal(0.875, EaseOutCubic(0.5), "fast at first")
+}
+
+func TestCameraPanTo(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.MoveTo(0.1, 0.2, 0, CameraEasingLinear)
+
+	camera.PanTo(1234.567, -89.01, 100*time.Millisecond, EaseInOutQuad)
+	assert.True(camera.IsMoving())
+
+	camera.Advance(0)
+
+	x, y := camera.GetPosition()
+	assert.Equal(0.1, x, "the pan starts exactly where the camera was")
+	assert.Equal(0.2, y)
+
+	camera.Advance(50 * time.Millisecond)
+
+	x, _ = camera.GetPosition()
+	assert.InDelta(0.1+(1234.567-0.1)/2, x, 1e-9, "half way at half the duration")
+
+	camera.Advance(50 * time.Millisecond)
+
+	x, y = camera.GetPosition()
+	assert.Equal(1234.567, x, "the pan lands exactly on the target")
+	assert.Equal(-89.01, y)
+	assert.False(camera.IsMoving())
+
+	target := func() (float64, float64) { return 0, 0 }
+
+	camera.Follow(target, d2common.Rectangle{})
+	camera.PanTo(500, 500, time.Second, EaseOutCubic)
+	assert.False(camera.IsFollowing(), "panning cancels following")
+
+	camera.Follow(target, d2common.Rectangle{})
+	assert.False(camera.IsMoving(), "following cancels the pan")
+}
+
+func TestCameraImpulse(t *testing.T) {
+	assert := testify.New(t)
+
+	const (
+		tick     = 16 * time.Millisecond
+		maxTicks = 200
+	)
+
+	camera := &Camera{}
+	camera.ApplyImpulse(1000, -500)
+
+	ticks := 0
+
+	for vx, vy := camera.GetVelocity(); vx != 0 || vy != 0; vx, vy = camera.GetVelocity() {
+		if ticks == maxTicks {
+			t.Fatalf("the camera still glides at %f, %f after %d ticks", vx, vy, maxTicks)
+		}
+
+		lastX, _ := camera.GetPosition()
+		camera.Advance(tick)
+
+		x, _ := camera.GetPosition()
+		assert.Greater(x, lastX, "the camera glides in the direction of the impulse")
+
+		ticks++
+	}
+
+	x, y := camera.GetPosition()
+	assert.InDelta(1000/defaultFriction, x, 10, "the camera glides about velocity / friction")
+	assert.InDelta(-500/defaultFriction, y, 5)
+
+	// a 20x20 tile map is 3200x1600 orthogonally, so the center of the view stops at 1200
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+	camera.SetBounds(d2common.Rectangle{Width: 20, Height: 20})
+	camera.MoveTo(1000, 800, 0, CameraEasingLinear)
+	camera.ApplyImpulse(2000, 100)
+	camera.Advance(200 * time.Millisecond)
+
+	x, _ = camera.GetPosition()
+	vx, vy := camera.GetVelocity()
+	assert.Equal(1200.0, x, "the glide stops at the bounds")
+	assert.Equal(0.0, vx, "the velocity toward the wall is zeroed")
+	assert.Greater(vy, 0.0, "the velocity along the wall is kept")
+
+	camera.Advance(tick)
+
+	x, _ = camera.GetPosition()
+	assert.Equal(1200.0, x)
+
+	camera.SetFriction(100)
+
+	for i := 0; i < 3; i++ {
+		camera.Advance(tick)
+	}
+
+	_, vy = camera.GetVelocity()
+	assert.Equal(0.0, vy, "a higher friction stops the camera within a few ticks")
+}
+
+func TestCameraOnMove(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.MoveTo(10, 20, 0, CameraEasingLinear)
+
+	var moves [][2]float64
+
+	unsubscribe := camera.OnMove(func(x, y float64) {
+		moves = append(moves, [2]float64{x, y})
+	})
+
+	camera.Advance(time.Millisecond)
+	assert.Empty(moves, "no callback while the camera stays put")
+
+	camera.MoveTo(30, 40, 0, CameraEasingLinear)
+	camera.Advance(time.Millisecond)
+	camera.Advance(time.Millisecond)
+	assert.Equal([][2]float64{{30, 40}}, moves, "one callback per change")
+
+	camera.MoveTo(50, 40, 2*time.Millisecond, CameraEasingLinear)
+	camera.Advance(time.Millisecond)
+	camera.Advance(time.Millisecond)
+	assert.Equal([][2]float64{{30, 40}, {40, 40}, {50, 40}}, moves, "pans call back every tick")
+
+	// a callback moving the camera isn't called again until the next tick
+	moveAgain := camera.OnMove(func(x, y float64) {
+		camera.MoveTo(x+1, y, 0, CameraEasingLinear)
+		camera.Advance(0)
+	})
+
+	camera.MoveTo(60, 40, 0, CameraEasingLinear)
+	camera.Advance(time.Millisecond)
+	assert.Len(moves, 4)
+
+	moveAgain()
+	camera.Advance(time.Millisecond)
+	assert.Equal([2]float64{61, 40}, moves[4], "the move made by the callback is reported on the next tick")
+
+	unsubscribe()
+	camera.MoveTo(0, 0, 0, CameraEasingLinear)
+	camera.Advance(time.Millisecond)
+	assert.Len(moves, 5, "no callback once unsubscribed")
+}
+
+func TestCameraRotation(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	assert.Equal(0, camera.GetRotation())
+
+	camera.SetRotation(5)
+	assert.Equal(1, camera.GetRotation(), "rotations wrap around")
+
+	camera.SetRotation(-1)
+	assert.Equal(3, camera.GetRotation())
+}
+
+func TestCameraLeadFollowsTarget(t *testing.T) {
+	assert := testify.New(t)
+
+	const tick = 40 * time.Millisecond
+
+	targetX, targetY := 0.0, 0.0
+	camera := &Camera{}
+	camera.Follow(func() (float64, float64) { return targetX, targetY }, d2common.Rectangle{})
+
+	// the target moves right at 100 pixels per second
+	for i := 0; i < 20; i++ {
+		camera.Advance(tick)
+		targetX += 4
+	}
+
+	x, y := camera.GetPosition()
+	assert.Equal(camera.x, x, "without a lead, the view is on the camera following the target")
+	assert.Equal(0.0, y)
+
+	camera.SetLead(0.5)
+
+	for i := 0; i < 60; i++ {
+		camera.Advance(tick)
+		targetX += 4
+	}
+
+	x, _ = camera.GetPosition()
+	assert.InDelta(50, x-camera.x, 0.1, "the view looks half a second of movement ahead of the target")
+
+	// turning around doesn't flip the view the same tick
+	targetX -= 8
+	camera.Advance(tick)
+
+	x, _ = camera.GetPosition()
+	assert.Greater(x-camera.x, 0.0, "the velocity is smoothed over recent positions")
+
+	// the lead of the followed target replaces the lead of the camera, the view doesn't look ahead twice
+	for i := 0; i < 60; i++ {
+		camera.Advance(tick)
+		targetX += 4
+	}
+
+	x, _ = camera.GetPosition()
+	assert.InDelta(50, x-camera.x, 0.1, "the view leads once")
+
+	camera.SetBounds(d2common.Rectangle{Left: 0, Top: 0, Width: 100, Height: 100})
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+
+	for i := 0; i < 100; i++ {
+		targetX += 200
+		camera.Advance(tick)
+	}
+
+	x, _ = camera.GetPosition()
+	assert.LessOrEqual(x, 8000.0-400, "the lead is clamped to the bounds")
+
+	camera.SetLead(0)
+	assert.Nil(camera.lead)
+}
+
+func TestCameraLookAhead(t *testing.T) {
+	assert := testify.New(t)
+
+	const tick = 40 * time.Millisecond
+
+	targetX := 0.0
+	camera := &Camera{}
+	camera.SetLookAhead(0.5)
+	camera.Follow(func() (float64, float64) { return targetX, 0 }, d2common.Rectangle{})
+
+	// the target moves right at 100 pixels per second
+	for i := 0; i < 60; i++ {
+		camera.Advance(tick)
+		targetX += 4
+	}
+
+	x, _ := camera.GetPosition()
+	assert.InDelta(50, x-camera.x, 0.1, "the view looks half a second of movement ahead of the target")
+
+	// the camera keeps moving once it follows nothing, but the view centers on it again
+	camera.Follow(nil, d2common.Rectangle{})
+
+	for i := 0; i < 60; i++ {
+		camera.MoveBy(4, 0)
+		camera.Advance(tick)
+	}
+
+	x, _ = camera.GetPosition()
+	assert.InDelta(0, x-camera.x, 0.1, "the view only looks ahead of a followed target")
+
+	camera.SetLookAhead(0)
+	assert.Nil(camera.lead)
+}
+
+func TestCameraFixedTimestep(t *testing.T) {
+	assert := testify.New(t)
+
+	pan := func(ticks int, tick time.Duration) *Camera {
+		camera := &Camera{}
+		camera.SetFixedTimestep(DefaultCameraTimestep)
+		camera.SetLead(0.25)
+		camera.PanTo(300, -120, 150*time.Millisecond, EaseOutCubic)
+
+		for i := 0; i < ticks; i++ {
+			camera.Advance(tick)
+		}
+
+		return camera
+	}
+
+	once, split := pan(1, 100*time.Millisecond), pan(10, 10*time.Millisecond)
+
+	x, y := once.GetPosition()
+	splitX, splitY := split.GetPosition()
+	assert.Greater(x, 0.0)
+	assert.Equal(x, splitX, "the frame rate doesn't change where the camera is")
+	assert.Equal(y, splitY)
+	assert.Equal(once.accumulated, split.accumulated, "the time left over is carried to the next call")
+	assert.True(once.accumulated < DefaultCameraTimestep)
+
+	camera := &Camera{}
+	camera.SetFixedTimestep(10 * time.Millisecond)
+	assert.Equal(10*time.Millisecond, camera.GetFixedTimestep())
+	camera.PanTo(100, 0, 100*time.Millisecond, EaseLinear)
+
+	camera.Advance(5 * time.Millisecond)
+	x, _ = camera.GetPosition()
+	assert.Equal(0.0, x, "the camera doesn't move before a whole step elapsed")
+
+	camera.Advance(5 * time.Millisecond)
+	x, _ = camera.GetPosition()
+	assert.InDelta(10.0, x, 1e-9)
+
+	camera.SetFixedTimestep(-time.Second)
+	assert.Equal(time.Duration(0), camera.GetFixedTimestep())
+
+	camera.Advance(5 * time.Millisecond)
+	x, _ = camera.GetPosition()
+	assert.InDelta(15.0, x, 1e-9, "without a fixed timestep, the camera moves by the elapsed time")
+}
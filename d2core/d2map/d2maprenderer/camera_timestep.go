@@ -0,0 +1,24 @@
+package d2maprenderer
+
+import "time"
+
+// DefaultCameraTimestep is the fixed timestep the camera of the map renderer moves in, 60 steps per second.
+const DefaultCameraTimestep = time.Second / 60
+
+// SetFixedTimestep makes Advance move the camera in steps of the given duration, carrying the time left over to the
+// next call, so the camera moves the same whatever the frame rate, and replays move it the same as the game did. A
+// timestep of 0 or less moves the camera by the elapsed time of each call instead.
+func (c *Camera) SetFixedTimestep(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+
+	c.timestep = d
+	c.accumulated = 0
+}
+
+// GetFixedTimestep returns the duration of the steps the camera moves in, or 0 if it moves by the elapsed time of each
+// call to Advance.
+func (c *Camera) GetFixedTimestep() time.Duration {
+	return c.timestep
+}
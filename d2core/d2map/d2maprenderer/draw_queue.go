@@ -0,0 +1,141 @@
+package d2maprenderer
+
+import (
+	"image"
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+)
+
+// batchedEntity is implemented by the entities which can be drawn in one batch with identical entities.
+type batchedEntity interface {
+	BatchComposite() (composite *d2asset.Composite, offsetX, offsetY int)
+}
+
+// drawItem is an entity or a wall to draw, or a batch of entities showing the same frame of the same composite.
+type drawItem struct {
+	entity    d2interface.MapEntity
+	surface   d2interface.Surface
+	composite *d2asset.Composite
+	x         int
+	y         int
+	offsets   []image.Point
+	bounds    []image.Rectangle // Screen areas of the batched entities or the wall, nil if unknown
+	scale     float64           // Scale the entity or wall is drawn at
+}
+
+// overlaps returns true if the item could be drawn over the given screen area.
+func (i *drawItem) overlaps(rect image.Rectangle) bool {
+	if i.bounds == nil {
+		return true
+	}
+
+	for _, bounds := range i.bounds {
+		if bounds.Overlaps(rect) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (i *drawItem) render(target d2interface.Surface) {
+	if i.composite != nil && len(i.offsets) > 1 {
+		_ = i.composite.RenderBatch(target, i.offsets)
+		return
+	}
+
+	target.PushTranslation(i.x, i.y)
+	defer target.Pop()
+
+	if i.scale != 1 {
+		target.PushScale(i.scale)
+		defer target.Pop()
+	}
+
+	if i.surface != nil {
+		_ = renderTileSurface(target, i.surface)
+		return
+	}
+
+	i.entity.Render(target)
+}
+
+// drawQueue holds the entities and walls of a render pass in depth order, so entities showing the same frame of the
+// same composite can be drawn in one batch. An entity only joins the batch of an earlier one if nothing queued between
+// them overlaps it, anything else is drawn on its own in its place. Entities aren't batched while the map is drawn
+// scaled, as the batches are drawn at their original size.
+type drawQueue struct {
+	items []*drawItem
+	scale float64 // Scale the map is drawn at, set by the map renderer each frame
+}
+
+// addEntity queues the entity on the tile drawn at the given screen position.
+func (q *drawQueue) addEntity(entity d2interface.MapEntity, x, y int) {
+	item := &drawItem{entity: entity, x: x, y: y, scale: q.drawScale()}
+
+	if batched, ok := entity.(batchedEntity); ok && item.scale == 1 {
+		if composite, offsetX, offsetY := batched.BatchComposite(); composite != nil {
+			offset := image.Pt(x+offsetX, y+offsetY)
+			bounds := composite.Bounds().Add(offset)
+
+			if batch := q.findBatch(composite, bounds); batch != nil {
+				batch.offsets = append(batch.offsets, offset)
+				batch.bounds = append(batch.bounds, bounds)
+
+				return
+			}
+
+			item.composite = composite
+			item.offsets = []image.Point{offset}
+			item.bounds = []image.Rectangle{bounds}
+		}
+	}
+
+	q.items = append(q.items, item)
+}
+
+// addSurface queues the cached image of a wall drawn at the given screen position.
+func (q *drawQueue) addSurface(surface d2interface.Surface, x, y int) {
+	scale := q.drawScale()
+	width, height := tileSize(surface)
+	bounds := image.Rect(x, y, x+int(math.Ceil(float64(width)*scale)), y+int(math.Ceil(float64(height)*scale)))
+
+	q.items = append(q.items, &drawItem{surface: surface, x: x, y: y, bounds: []image.Rectangle{bounds}, scale: scale})
+}
+
+// drawScale returns the scale the map is drawn at, 1 until the map renderer sets it.
+func (q *drawQueue) drawScale() float64 {
+	if q.scale == 0 {
+		return 1
+	}
+
+	return q.scale
+}
+
+// findBatch returns the batch the composite drawn in the given screen area can join, or nil if there is none.
+func (q *drawQueue) findBatch(composite *d2asset.Composite, bounds image.Rectangle) *drawItem {
+	for i := len(q.items) - 1; i >= 0; i-- {
+		item := q.items[i]
+
+		if item.composite != nil && item.composite.SameFrame(composite) {
+			return item
+		}
+
+		if item.overlaps(bounds) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// render draws the queued items in order and empties the queue.
+func (q *drawQueue) render(target d2interface.Surface) {
+	for _, item := range q.items {
+		item.render(target)
+	}
+
+	q.items = q.items[:0]
+}
@@ -0,0 +1,75 @@
+package d2maprenderer
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// entityIndex is the spatial index of the entities of the map engine, with the ids it knows them by.
+type entityIndex struct {
+	index    *SpatialIndex
+	ids      map[d2interface.MapEntity]int
+	entities map[int]d2interface.MapEntity
+	nextID   int
+}
+
+func newEntityIndex() *entityIndex {
+	return &entityIndex{
+		index:    NewSpatialIndex(),
+		ids:      make(map[d2interface.MapEntity]int),
+		entities: make(map[int]d2interface.MapEntity),
+	}
+}
+
+// UpdateEntityIndex moves the entities of the map engine to their current positions in the spatial index, adding the
+// new ones and removing the ones no longer on the map. It should be called once the entities moved, before looking
+// them up with EntitiesNear.
+func (mr *MapRenderer) UpdateEntityIndex() {
+	if mr.entityIndex == nil {
+		mr.entityIndex = newEntityIndex()
+	}
+
+	index := mr.entityIndex
+	present := make(map[int]struct{}, len(index.ids))
+
+	for _, entity := range *mr.mapEngine.Entities() {
+		id, ok := index.ids[entity]
+		if !ok {
+			id = index.nextID
+			index.nextID++
+			index.ids[entity] = id
+			index.entities[id] = entity
+		}
+
+		x, y := entity.GetPositionF()
+		index.index.Update(id, x, y)
+
+		present[id] = struct{}{}
+	}
+
+	for id, entity := range index.entities {
+		if _, ok := present[id]; ok {
+			continue
+		}
+
+		index.index.Remove(id)
+		delete(index.ids, entity)
+		delete(index.entities, id)
+	}
+}
+
+// EntitiesNear returns the entities within the radius, in tiles, of the world position, as of the last
+// UpdateEntityIndex, in the order they were added to the map.
+func (mr *MapRenderer) EntitiesNear(x, y, radius float64) []d2interface.MapEntity {
+	if mr.entityIndex == nil {
+		return nil
+	}
+
+	ids := mr.entityIndex.index.QueryRadius(x, y, radius)
+	entities := make([]d2interface.MapEntity, len(ids))
+
+	for i, id := range ids {
+		entities[i] = mr.entityIndex.entities[id]
+	}
+
+	return entities
+}
@@ -1,16 +1,14 @@
 package d2maprenderer
 
 import (
-	"errors"
 	"image/color"
 	"log"
-	"math"
+	"time"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
-	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
-	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
 )
 
@@ -19,11 +17,17 @@ type MapRenderer struct {
 	renderer      d2interface.Renderer   // Used for drawing operations
 	mapEngine     *d2mapengine.MapEngine // The map engine that is being rendered
 	palette       d2interface.Palette    // The palette used for this map
+	drawQueue     drawQueue              // Entities and walls of the current render pass, drawn in batches
 	viewport      *Viewport              // Used for rendering offsets
 	camera        Camera                 // Used to determine where on the map we are rendering
 	debugVisLevel int                    // Debug visibility index (0=none, 1=tiles, 2=sub-tiles)
 	lastFrameTime float64                // The last time the map was rendered
 	currentFrame  int                    // Current render frame (for animations)
+
+	zoomedOutFilter d2enum.Filter       // Sampling of the map below 1x zoom, nearest-neighbor if default
+	ambientLight    color.Color         // Tint of the ambient light of the area, nil for the lighting of the game
+	clipSurface     d2interface.Surface // Offscreen surface the map is drawn to when the viewport clips it, nil until then
+	entityIndex     *entityIndex        // Spatial index of the entities of the map engine, nil until first updated
 }
 
 // CreateMapRenderer creates a new MapRenderer, sets the required fields and returns a pointer to it.
@@ -34,6 +38,7 @@ func CreateMapRenderer(renderer d2interface.Renderer, mapEngine *d2mapengine.Map
 		viewport:  NewViewport(0, 0, 800, 600),
 	}
 
+	result.camera.SetFixedTimestep(DefaultCameraTimestep)
 	result.viewport.SetCamera(&result.camera)
 
 	term.BindAction("mapdebugvis", "set map debug visualization level", func(level int) {
@@ -68,17 +73,80 @@ func (mr *MapRenderer) SetMapEngine(mapEngine *d2mapengine.MapEngine) {
 // Pass 3: Upper wall tiles and entities above walls.
 //
 // Pass 4: Roof tiles.
+//
+// The map is clipped to the clip rect of the viewport.
 func (mr *MapRenderer) Render(target d2interface.Surface) {
+	mr.renderClipped(target, mr.renderMap)
+}
+
+// renderClipped calls render with the target, or with an offscreen surface drawn to the target afterward if the clip
+// rect of the viewport doesn't cover the whole target, so nothing is drawn outside of it. The offscreen surface is
+// translated so render draws at the same screen positions either way.
+func (mr *MapRenderer) renderClipped(target d2interface.Surface, render func(target d2interface.Surface)) {
+	clip := mr.viewport.GetClipRect()
+	width, height := target.GetSize()
+
+	if clip.Left <= 0 && clip.Top <= 0 && clip.Right() >= width && clip.Bottom() >= height {
+		render(target)
+		return
+	}
+
+	if clip.Width <= 0 || clip.Height <= 0 {
+		return
+	}
+
+	if mr.clipSurface == nil {
+		mr.clipSurface, _ = mr.renderer.NewSurface(clip.Width, clip.Height, d2enum.FilterNearest)
+	} else if clipWidth, clipHeight := mr.clipSurface.GetSize(); clipWidth != clip.Width || clipHeight != clip.Height {
+		mr.clipSurface, _ = mr.renderer.NewSurface(clip.Width, clip.Height, d2enum.FilterNearest)
+	}
+
+	if mr.clipSurface == nil {
+		log.Printf("failed to create a %dx%d surface to clip the map to", clip.Width, clip.Height)
+		return
+	}
+
+	if err := mr.clipSurface.Clear(color.Transparent); err != nil {
+		log.Print(err)
+	}
+
+	mr.clipSurface.PushTranslation(-clip.Left, -clip.Top)
+	render(mr.clipSurface)
+	mr.clipSurface.Pop()
+
+	target.PushTranslation(clip.Left, clip.Top)
+	defer target.Pop()
+
+	if err := target.Render(mr.clipSurface); err != nil {
+		log.Print(err)
+	}
+}
+
+// renderMap draws the four render passes of the visible tiles to the target.
+func (mr *MapRenderer) renderMap(target d2interface.Surface) {
+	mr.viewport.BeginFrame()
+	defer mr.viewport.EndFrame()
+
+	mr.drawQueue.scale = mr.viewport.drawScale()
+
+	if mr.pushZoomFilter(target) {
+		defer target.Pop()
+	}
+
+	if mr.ambientLight != nil {
+		target.PushColor(mr.ambientLight)
+		defer target.Pop()
+	}
+
 	mapSize := mr.mapEngine.Size()
 
-	stxf, styf := mr.viewport.ScreenToWorld(400, -200)
-	etxf, etyf := mr.viewport.ScreenToWorld(400, 1050)
+	minX, minY, maxX, maxY := mr.viewport.GetVisibleTileRange()
 
-	startX := int(math.Max(0, math.Floor(stxf)))
-	startY := int(math.Max(0, math.Floor(styf)))
+	startX := d2common.MaxInt(0, minX)
+	startY := d2common.MaxInt(0, minY)
 
-	endX := int(math.Min(float64(mapSize.Width), math.Ceil(etxf)))
-	endY := int(math.Min(float64(mapSize.Height), math.Ceil(etyf)))
+	endX := d2common.MinInt(mapSize.Width, maxX+1)
+	endY := d2common.MinInt(mapSize.Height, maxY+1)
 
 	mr.renderPass1(target, startX, startY, endX, endY)
 	mr.renderPass2(target, startX, startY, endX, endY)
@@ -91,14 +159,112 @@ func (mr *MapRenderer) Render(target d2interface.Surface) {
 	mr.renderPass4(target, startX, startY, endX, endY)
 }
 
+// pushZoomFilter pushes the filter the map is sampled with while zoomed out on the target, and returns false if it
+// isn't zoomed out or uses the default filter.
+func (mr *MapRenderer) pushZoomFilter(target d2interface.Surface) bool {
+	// smooth filtering blurs the pixel art at 1x and above, so it is only used zoomed out
+	if mr.zoomedOutFilter == d2enum.FilterDefault || mr.camera.GetZoom() >= 1 {
+		return false
+	}
+
+	target.PushFilter(mr.zoomedOutFilter)
+
+	return true
+}
+
 // MoveCameraTo sets the position of the camera to the given x and y coordinates.
 func (mr *MapRenderer) MoveCameraTo(x, y float64) {
-	mr.camera.MoveTo(x, y)
+	mr.camera.MoveTo(x, y, 0, CameraEasingLinear)
+	mr.viewport.cameraMoved()
+}
+
+// PanCameraTo pans the camera to the given x and y coordinates over the duration.
+func (mr *MapRenderer) PanCameraTo(x, y float64, duration time.Duration, easing CameraEasing) {
+	mr.camera.MoveTo(x, y, duration, easing)
+	mr.viewport.cameraMoved()
+}
+
+// IsCameraMoving returns true while the camera is panning to a position.
+func (mr *MapRenderer) IsCameraMoving() bool {
+	return mr.camera.IsMoving()
 }
 
 // MoveCameraBy adds the given vector to the current position of the camera.
 func (mr *MapRenderer) MoveCameraBy(x, y float64) {
 	mr.camera.MoveBy(x, y)
+	mr.viewport.cameraMoved()
+}
+
+// SetCameraZoom sets the zoom factor of the camera, clamped between 0.25 and 4, keeping the camera on the same point
+// of the world.
+func (mr *MapRenderer) SetCameraZoom(factor float64) {
+	worldX, worldY := mr.viewport.OrthoToWorld(mr.camera.x, mr.camera.y)
+	mr.camera.SetZoom(factor)
+
+	orthoX, orthoY := mr.viewport.WorldToOrtho(worldX, worldY)
+	mr.MoveCameraTo(orthoX, orthoY)
+}
+
+// GetCameraZoom returns the zoom factor of the camera.
+func (mr *MapRenderer) GetCameraZoom() float64 {
+	return mr.camera.GetZoom()
+}
+
+// SetCameraRotation turns the view of the map by quarter turns around the vertical axis, 0 to 3, keeping the camera on
+// the same point of the world.
+func (mr *MapRenderer) SetCameraRotation(quarter int) {
+	worldX, worldY := mr.viewport.OrthoToWorld(mr.camera.x, mr.camera.y)
+	mr.camera.SetRotation(quarter)
+
+	orthoX, orthoY := mr.viewport.WorldToOrtho(worldX, worldY)
+	mr.MoveCameraTo(orthoX, orthoY)
+}
+
+// SetZoomedOutFilter sets the filter the map is sampled with while the camera is zoomed out below 1x. The map is
+// always drawn with nearest-neighbor sampling at 1x and above.
+func (mr *MapRenderer) SetZoomedOutFilter(filter d2enum.Filter) {
+	mr.zoomedOutFilter = filter
+}
+
+// SetAmbientLight tints everything drawn on the map with the color, multiplying the lighting of the game, so the
+// light around the hero dims and colors with the rest of the area. A nil color restores the lighting of the game.
+func (mr *MapRenderer) SetAmbientLight(tint color.Color) {
+	mr.ambientLight = tint
+}
+
+// SetCameraBounds keeps the view of the camera within the given area of the map, in world space.
+func (mr *MapRenderer) SetCameraBounds(rect d2common.Rectangle) {
+	mr.camera.SetBounds(rect)
+	mr.viewport.cameraMoved()
+}
+
+// ClearCameraBounds lets the camera move freely again.
+func (mr *MapRenderer) ClearCameraBounds() {
+	mr.camera.ClearBounds()
+	mr.viewport.cameraMoved()
+}
+
+// FollowWithCamera makes the camera follow the orthogonal position the target returns, once it leaves the deadzone
+// centered on the view. A nil target stops following.
+func (mr *MapRenderer) FollowWithCamera(target func() (float64, float64), deadzone d2common.Rectangle) {
+	mr.camera.Follow(target, deadzone)
+}
+
+// SetCameraOffset moves the view away from the camera position by the given screen pixels.
+func (mr *MapRenderer) SetCameraOffset(x, y float64) {
+	mr.camera.SetOffset(x, y)
+	mr.viewport.cameraMoved()
+}
+
+// SetCameraLead makes the view look ahead of the camera by the given seconds of its movement, 0 to center it.
+func (mr *MapRenderer) SetCameraLead(seconds float64) {
+	mr.camera.SetLead(seconds)
+	mr.viewport.cameraMoved()
+}
+
+// ShakeCamera shakes the view by up to magnitude pixels, dying down over the duration.
+func (mr *MapRenderer) ShakeCamera(magnitude float64, duration time.Duration) {
+	mr.camera.Shake(magnitude, duration)
 }
 
 // ScreenToWorld returns the world position for the given screen (pixel) position.
@@ -106,6 +272,11 @@ func (mr *MapRenderer) ScreenToWorld(x, y int) (float64, float64) {
 	return mr.viewport.ScreenToWorld(x, y)
 }
 
+// ScreenToTile returns the tile under the given screen (pixel) position, rounding down on both axes.
+func (mr *MapRenderer) ScreenToTile(x, y int) (tileX, tileY int) {
+	return mr.viewport.ScreenToTile(x, y)
+}
+
 // ScreenToOrtho returns the orthogonal position, without accounting for the isometric angle, for the given screen
 // (pixel) position.
 func (mr *MapRenderer) ScreenToOrtho(x, y int) (float64, float64) {
@@ -121,10 +292,14 @@ func (mr *MapRenderer) WorldToOrtho(x, y float64) (float64, float64) {
 func (mr *MapRenderer) renderPass1(target d2interface.Surface, startX, startY, endX, endY int) {
 	for tileY := startY; tileY < endY; tileY++ {
 		for tileX := startX; tileX < endX; tileX++ {
-			tile := mr.mapEngine.TileAt(tileX, tileY)
+			tile, ok := mr.mapEngine.TileAt(tileX, tileY)
+			if !ok {
+				continue
+			}
+
 			mr.viewport.PushTranslationWorld(float64(tileX), float64(tileY))
 			mr.renderTilePass1(tile, target)
-			mr.viewport.PopTranslation()
+			popTranslation(mr.viewport)
 		}
 	}
 }
@@ -147,23 +322,28 @@ func (mr *MapRenderer) renderPass2(target d2interface.Surface, startX, startY, e
 					continue
 				}
 
-				target.PushTranslation(mr.viewport.GetTranslationScreen())
-				mapEntity.Render(target)
-				target.Pop()
+				x, y := mr.viewport.GetTranslationScreen()
+				mr.drawQueue.addEntity(mapEntity, x, y)
 			}
 
-			mr.viewport.PopTranslation()
+			popTranslation(mr.viewport)
 		}
 	}
+
+	mr.drawQueue.render(target)
 }
 
 // Upper wall tiles and entities above walls.
 func (mr *MapRenderer) renderPass3(target d2interface.Surface, startX, startY, endX, endY int) {
 	for tileY := startY; tileY < endY; tileY++ {
 		for tileX := startX; tileX < endX; tileX++ {
-			tile := mr.mapEngine.TileAt(tileX, tileY)
+			tile, ok := mr.mapEngine.TileAt(tileX, tileY)
+			if !ok {
+				continue
+			}
+
 			mr.viewport.PushTranslationWorld(float64(tileX), float64(tileY))
-			mr.renderTilePass2(tile, target)
+			mr.queueTilePass2(tile)
 
 			// TODO: Do not loop over every entity every frame
 			for _, mapEntity := range *mr.mapEngine.Entities() {
@@ -177,24 +357,29 @@ func (mr *MapRenderer) renderPass3(target d2interface.Surface, startX, startY, e
 					continue
 				}
 
-				target.PushTranslation(mr.viewport.GetTranslationScreen())
-				mapEntity.Render(target)
-				target.Pop()
+				x, y := mr.viewport.GetTranslationScreen()
+				mr.drawQueue.addEntity(mapEntity, x, y)
 			}
 
-			mr.viewport.PopTranslation()
+			popTranslation(mr.viewport)
 		}
 	}
+
+	mr.drawQueue.render(target)
 }
 
 // Roof tiles.
 func (mr *MapRenderer) renderPass4(target d2interface.Surface, startX, startY, endX, endY int) {
 	for tileY := startY; tileY < endY; tileY++ {
 		for tileX := startX; tileX < endX; tileX++ {
-			tile := mr.mapEngine.TileAt(tileX, tileY)
+			tile, ok := mr.mapEngine.TileAt(tileX, tileY)
+			if !ok {
+				continue
+			}
+
 			mr.viewport.PushTranslationWorld(float64(tileX), float64(tileY))
 			mr.renderTilePass3(tile, target)
-			mr.viewport.PopTranslation()
+			popTranslation(mr.viewport)
 		}
 	}
 }
@@ -219,10 +404,13 @@ func (mr *MapRenderer) renderTilePass1(tile *d2ds1.TileRecord, target d2interfac
 	}
 }
 
-func (mr *MapRenderer) renderTilePass2(tile *d2ds1.TileRecord, target d2interface.Surface) {
+// queueTilePass2 queues the upper walls of the tile, to be drawn in order with the entities around them.
+func (mr *MapRenderer) queueTilePass2(tile *d2ds1.TileRecord) {
 	for _, wall := range tile.Walls {
 		if !wall.Hidden && wall.Type.UpperWall() {
-			mr.renderWall(wall, mr.viewport, target)
+			if img, x, y := mr.wallImage(wall, mr.viewport); img != nil {
+				mr.drawQueue.addSurface(img, x, y)
+			}
 		}
 	}
 }
@@ -248,29 +436,36 @@ func (mr *MapRenderer) renderFloor(tile d2ds1.FloorShadowRecord, target d2interf
 		return
 	}
 
-	mr.viewport.PushTranslationOrtho(-80, float64(tile.YAdjust))
-	defer mr.viewport.PopTranslation()
+	pushTileArtOffset(mr.viewport, tile.YAdjust)
+	defer popTranslation(mr.viewport)
 
-	target.PushTranslation(mr.viewport.GetTranslationScreen())
-	defer target.Pop()
-
-	target.Render(img)
+	x, y := mr.viewport.GetTranslationScreen()
+	renderMapImage(target, img, x, y, mr.viewport.drawScale())
 }
 
 func (mr *MapRenderer) renderWall(tile d2ds1.WallRecord, viewport *Viewport, target d2interface.Surface) {
-	img := mr.getImageCacheRecord(tile.Style, tile.Sequence, tile.Type, tile.RandomIndex)
+	img, x, y := mr.wallImage(tile, viewport)
 	if img == nil {
-		log.Printf("Render called on uncached wall {%v,%v,%v}", tile.Style, tile.Sequence, tile.Type)
 		return
 	}
 
-	viewport.PushTranslationOrtho(-80, float64(tile.YAdjust))
-	defer viewport.PopTranslation()
+	renderMapImage(target, img, x, y, viewport.drawScale())
+}
 
-	target.PushTranslation(viewport.GetTranslationScreen())
-	defer target.Pop()
+// wallImage returns the image of the wall and the screen position it is drawn at, or nil if it isn't cached.
+func (mr *MapRenderer) wallImage(tile d2ds1.WallRecord, viewport *Viewport) (img d2interface.Surface, x, y int) {
+	img = mr.getImageCacheRecord(tile.Style, tile.Sequence, tile.Type, tile.RandomIndex)
+	if img == nil {
+		log.Printf("Render called on uncached wall {%v,%v,%v}", tile.Style, tile.Sequence, tile.Type)
+		return nil, 0, 0
+	}
+
+	pushTileArtOffset(viewport, tile.YAdjust)
+	defer popTranslation(viewport)
+
+	x, y = viewport.GetTranslationScreen()
 
-	target.Render(img)
+	return img, x, y
 }
 
 func (mr *MapRenderer) renderShadow(tile d2ds1.FloorShadowRecord, target d2interface.Surface) {
@@ -280,14 +475,35 @@ func (mr *MapRenderer) renderShadow(tile d2ds1.FloorShadowRecord, target d2inter
 		return
 	}
 
-	defer mr.viewport.PushTranslationOrtho(-80, float64(tile.YAdjust)).PopTranslation()
+	pushTileArtOffset(mr.viewport, tile.YAdjust)
+	defer popTranslation(mr.viewport)
 
-	target.PushTranslation(mr.viewport.GetTranslationScreen())
 	target.PushColor(color.RGBA{R: 255, G: 255, B: 255, A: 160})
+	defer target.Pop()
+
+	x, y := mr.viewport.GetTranslationScreen()
+	renderMapImage(target, img, x, y, mr.viewport.drawScale())
+}
+
+// pushTileArtOffset pushes the offset of the image of a tile from the top corner of the tile, half a tile to the left,
+// scaled by the zoom of the camera.
+func pushTileArtOffset(viewport *Viewport, yAdjust int) {
+	halfTileWidth, _ := viewport.tileHalfSize()
+	viewport.PushTranslationOrtho(-halfTileWidth, float64(yAdjust)*viewport.zoom())
+}
+
+// renderMapImage draws the cached image of a tile at the screen position, scaled by the given draw scale of the
+// viewport.
+func renderMapImage(target, img d2interface.Surface, x, y int, scale float64) {
+	target.PushTranslation(x, y)
+	defer target.Pop()
 
-	defer target.PopN(2)
+	if scale != 1 {
+		target.PushScale(scale)
+		defer target.Pop()
+	}
 
-	target.Render(img)
+	_ = renderTileSurface(target, img)
 }
 
 func (mr *MapRenderer) renderDebug(debugVisLevel int, target d2interface.Surface, startX, startY, endX, endY int) {
@@ -295,11 +511,19 @@ func (mr *MapRenderer) renderDebug(debugVisLevel int, target d2interface.Surface
 		for tileX := startX; tileX < endX; tileX++ {
 			mr.viewport.PushTranslationWorld(float64(tileX), float64(tileY))
 			mr.renderTileDebug(tileX, tileY, debugVisLevel, target)
-			mr.viewport.PopTranslation()
+			popTranslation(mr.viewport)
 		}
 	}
 }
 
+// popTranslation pops a translation of the viewport, logging unbalanced pushes and pops instead of taking down the
+// renderer.
+func popTranslation(viewport *Viewport) {
+	if err := viewport.TryPopTranslation(); err != nil {
+		log.Print(err)
+	}
+}
+
 // WorldToScreen returns the screen (pixel) position for the given isometric world position as two ints.
 func (mr *MapRenderer) WorldToScreen(x, y float64) (int, int) {
 	return mr.viewport.WorldToScreen(x, y)
@@ -342,7 +566,10 @@ func (mr *MapRenderer) renderTileDebug(ax, ay int, debugVisLevel int, target d2i
 			target.Pop()
 		}
 
-		tile := mr.mapEngine.TileAt(ax, ay)
+		tile, ok := mr.mapEngine.TileAt(ax, ay)
+		if !ok {
+			return
+		}
 
 		/*for i, floor := range tile.Floors {
 			target.PushTranslation(-20, 10+(i+1)*14)
@@ -377,6 +604,9 @@ func (mr *MapRenderer) renderTileDebug(ax, ay int, debugVisLevel int, target d2i
 
 // Advance is called once per frame and maintains the MapRenderer's record previous render timestamp and current frame.
 func (mr *MapRenderer) Advance(elapsed float64) {
+	mr.camera.Advance(time.Duration(elapsed * float64(time.Second)))
+	mr.viewport.cameraMoved()
+
 	frameLength := 0.1
 
 	mr.lastFrameTime += elapsed
@@ -389,43 +619,22 @@ func (mr *MapRenderer) Advance(elapsed float64) {
 	}
 }
 
-func loadPaletteForAct(levelType d2enum.RegionIdType) (d2interface.Palette, error) {
-	var palettePath string
-
-	switch levelType {
-	case d2enum.RegionAct1Town, d2enum.RegionAct1Wilderness, d2enum.RegionAct1Cave, d2enum.RegionAct1Crypt,
-		d2enum.RegionAct1Monestary, d2enum.RegionAct1Courtyard, d2enum.RegionAct1Barracks,
-		d2enum.RegionAct1Jail, d2enum.RegionAct1Cathedral, d2enum.RegionAct1Catacombs, d2enum.RegionAct1Tristram:
-		palettePath = d2resource.PaletteAct1
-	case d2enum.RegionAct2Town, d2enum.RegionAct2Sewer, d2enum.RegionAct2Harem, d2enum.RegionAct2Basement,
-		d2enum.RegionAct2Desert, d2enum.RegionAct2Tomb, d2enum.RegionAct2Lair, d2enum.RegionAct2Arcane:
-		palettePath = d2resource.PaletteAct2
-	case d2enum.RegionAct3Town, d2enum.RegionAct3Jungle, d2enum.RegionAct3Kurast, d2enum.RegionAct3Spider,
-		d2enum.RegionAct3Dungeon, d2enum.RegionAct3Sewer:
-		palettePath = d2resource.PaletteAct3
-	case d2enum.RegionAct4Town, d2enum.RegionAct4Mesa, d2enum.RegionAct4Lava, d2enum.RegionAct5Lava:
-		palettePath = d2resource.PaletteAct4
-	case d2enum.RegonAct5Town, d2enum.RegionAct5Siege, d2enum.RegionAct5Barricade, d2enum.RegionAct5Temple,
-		d2enum.RegionAct5IceCaves, d2enum.RegionAct5Baal:
-		palettePath = d2resource.PaletteAct5
-	default:
-		return nil, errors.New("failed to find palette for region")
-	}
-
-	return d2asset.LoadPalette(palettePath)
-}
-
 // ViewportToLeft moves the viewport to the left.
 func (mr *MapRenderer) ViewportToLeft() {
-	mr.viewport.toLeft()
+	mr.viewport.SetAlignment(AlignLeft)
 }
 
 // ViewportToRight moves the viewport to the right.
 func (mr *MapRenderer) ViewportToRight() {
-	mr.viewport.toRight()
+	mr.viewport.SetAlignment(AlignRight)
 }
 
 // ViewportDefault resets the viewport to it's default position.
 func (mr *MapRenderer) ViewportDefault() {
-	mr.viewport.resetAlign()
+	mr.viewport.SetAlignment(AlignCenter)
+}
+
+// Viewport returns the viewport converting between screen, orthogonal and world space.
+func (mr *MapRenderer) Viewport() *Viewport {
+	return mr.viewport
 }
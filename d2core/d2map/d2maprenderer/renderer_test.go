@@ -0,0 +1,115 @@
+package d2maprenderer
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2render/headless"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestZoomedOutFilter(t *testing.T) {
+	assert := testify.New(t)
+
+	config := d2config.Config
+	d2config.Config = &d2config.Configuration{}
+
+	defer func() { d2config.Config = config }()
+
+	renderer, err := headless.CreateRenderer()
+	assert.NoError(err)
+
+	mr := &MapRenderer{renderer: renderer, viewport: NewViewport(0, 0, 800, 600), zoomedOutFilter: d2enum.FilterLinear}
+	mr.viewport.SetCamera(&mr.camera)
+
+	// a tile of alternating black and white columns, which the linear filter blends to gray when drawn at half size
+	tile, err := mr.newTileSurface(4, 1, []byte{
+		0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff,
+	})
+	assert.NoError(err)
+
+	draw := func() color.RGBA {
+		target, _ := renderer.NewSurface(4, 1, d2enum.FilterNearest)
+
+		if mr.pushZoomFilter(target) {
+			defer target.Pop()
+		}
+
+		renderMapImage(target, tile, 0, 0, mr.viewport.drawScale())
+
+		return target.Screenshot().RGBAAt(0, 0)
+	}
+
+	assert.Equal(color.RGBA{A: 0xff}, draw(), "the map isn't filtered at 1x")
+
+	mr.SetCameraZoom(0.5)
+
+	pixel := draw()
+	assert.True(pixel.R > 0 && pixel.R < 0xff, "the linear filter blends the columns zoomed out, got %v", pixel)
+
+	mr.SetZoomedOutFilter(d2enum.FilterNearest)
+
+	pixel = draw()
+	assert.True(pixel.R == 0 || pixel.R == 0xff, "the nearest filter keeps the columns crisp, got %v", pixel)
+
+	target, _ := renderer.NewSurface(4, 1, d2enum.FilterNearest)
+	renderMapImage(target, tile, 0, 0, mr.viewport.drawScale())
+	assert.Equal(color.RGBA{}, target.Screenshot().RGBAAt(2, 0), "zoomed out tiles are drawn at half size")
+}
+
+func TestRenderClipped(t *testing.T) {
+	assert := testify.New(t)
+
+	config := d2config.Config
+	d2config.Config = &d2config.Configuration{}
+
+	defer func() { d2config.Config = config }()
+
+	renderer, err := headless.CreateRenderer()
+	assert.NoError(err)
+
+	mr := &MapRenderer{renderer: renderer, viewport: NewViewport(0, 0, 800, 600)}
+	mr.viewport.SetCamera(&mr.camera)
+
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+
+	// draws to the whole screen, past the clip rect of the viewport
+	fill := func(target d2interface.Surface) {
+		target.DrawRect(800, 600, white)
+	}
+
+	target, _ := renderer.NewSurface(800, 600, d2enum.FilterNearest)
+	mr.renderClipped(target, fill)
+	assert.Equal(white, target.Screenshot().RGBAAt(600, 300))
+	assert.Nil(mr.clipSurface, "a viewport covering the whole screen draws to it directly")
+
+	// the map is drawn to the left half of the screen
+	mr.viewport.SetAlignment(AlignRight)
+
+	target, _ = renderer.NewSurface(800, 600, d2enum.FilterNearest)
+	mr.renderClipped(target, fill)
+
+	screenshot := target.Screenshot()
+	assert.Equal(white, screenshot.RGBAAt(0, 0))
+	assert.Equal(white, screenshot.RGBAAt(399, 599))
+	assert.Equal(color.RGBA{}, screenshot.RGBAAt(400, 0), "nothing is drawn right of the clip rect")
+	assert.Equal(color.RGBA{}, screenshot.RGBAAt(799, 599))
+
+	// the map is drawn to the right half of the screen, at the same screen positions
+	mr.viewport.SetAlignment(AlignLeft)
+
+	target, _ = renderer.NewSurface(800, 600, d2enum.FilterNearest)
+	mr.renderClipped(target, func(target d2interface.Surface) {
+		target.PushTranslation(500, 100)
+		target.DrawRect(1, 1, white)
+		target.Pop()
+	})
+
+	screenshot = target.Screenshot()
+	assert.Equal(white, screenshot.RGBAAt(500, 100), "the clipped map is drawn where it would be unclipped")
+	assert.Equal(color.RGBA{}, screenshot.RGBAAt(100, 100))
+}
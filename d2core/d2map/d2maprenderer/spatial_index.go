@@ -0,0 +1,132 @@
+package d2maprenderer
+
+import (
+	"math"
+	"sort"
+)
+
+// spatialCellSize is the number of tiles along each side of a cell of the spatial index.
+const spatialCellSize = 8
+
+// spatialCell is the coordinate of a cell of the spatial index, the tile coordinate divided by the cell size.
+type spatialCell struct {
+	x, y int
+}
+
+// spatialEntry is the world position of an entity of the spatial index, and the cell it is in.
+type spatialEntry struct {
+	x, y float64
+	cell spatialCell
+}
+
+// SpatialIndex is a uniform grid of the world positions of entities, to find the entities on screen without testing
+// every entity of the level against the viewport.
+type SpatialIndex struct {
+	entries map[int]spatialEntry
+	cells   map[spatialCell]map[int]struct{}
+}
+
+// NewSpatialIndex creates an empty spatial index.
+func NewSpatialIndex() *SpatialIndex {
+	return &SpatialIndex{
+		entries: make(map[int]spatialEntry),
+		cells:   make(map[spatialCell]map[int]struct{}),
+	}
+}
+
+// Insert adds the entity at the world position, moving it there if it is already in the index.
+func (s *SpatialIndex) Insert(id int, x, y float64) {
+	s.Update(id, x, y)
+}
+
+// Update moves the entity to the world position, adding it if it isn't in the index.
+func (s *SpatialIndex) Update(id int, x, y float64) {
+	cell := cellAt(x, y)
+
+	if entry, ok := s.entries[id]; ok && entry.cell != cell {
+		s.removeFromCell(id, entry.cell)
+	}
+
+	if s.cells[cell] == nil {
+		s.cells[cell] = make(map[int]struct{})
+	}
+
+	s.cells[cell][id] = struct{}{}
+	s.entries[id] = spatialEntry{x: x, y: y, cell: cell}
+}
+
+// Remove removes the entity from the index, if it is in it.
+func (s *SpatialIndex) Remove(id int) {
+	if entry, ok := s.entries[id]; ok {
+		s.removeFromCell(id, entry.cell)
+		delete(s.entries, id)
+	}
+}
+
+// Len returns the number of entities in the index.
+func (s *SpatialIndex) Len() int {
+	return len(s.entries)
+}
+
+// QueryVisible returns the ids of the entities whose tiles the viewport shows, the same entities IsTileVisible is true
+// for, in increasing order. Only the cells within the visible tile range of the viewport are looked at.
+func (s *SpatialIndex) QueryVisible(v *Viewport) []int {
+	minX, minY, maxX, maxY := v.GetVisibleTileRange()
+	minCell, maxCell := cellAt(float64(minX), float64(minY)), cellAt(float64(maxX), float64(maxY))
+
+	var ids []int
+
+	for cellY := minCell.y; cellY <= maxCell.y; cellY++ {
+		for cellX := minCell.x; cellX <= maxCell.x; cellX++ {
+			for id := range s.cells[spatialCell{x: cellX, y: cellY}] {
+				// the cells at the edges of the range are partly off screen
+				if entry := s.entries[id]; v.IsTileVisible(entry.x, entry.y) {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+// QueryRadius returns the ids of the entities within the radius, in tiles, of the world position, in increasing order.
+// Only the cells overlapping the square around the radius are looked at.
+func (s *SpatialIndex) QueryRadius(x, y, radius float64) []int {
+	minCell, maxCell := cellAt(x-radius, y-radius), cellAt(x+radius, y+radius)
+
+	var ids []int
+
+	for cellY := minCell.y; cellY <= maxCell.y; cellY++ {
+		for cellX := minCell.x; cellX <= maxCell.x; cellX++ {
+			for id := range s.cells[spatialCell{x: cellX, y: cellY}] {
+				// the corners of the square are outside of the radius
+				if entry := s.entries[id]; math.Hypot(entry.x-x, entry.y-y) <= radius {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+func (s *SpatialIndex) removeFromCell(id int, cell spatialCell) {
+	delete(s.cells[cell], id)
+
+	if len(s.cells[cell]) == 0 {
+		delete(s.cells, cell)
+	}
+}
+
+// cellAt returns the cell of the tile at the world position.
+func cellAt(x, y float64) spatialCell {
+	return spatialCell{
+		x: int(math.Floor(math.Floor(x) / spatialCellSize)),
+		y: int(math.Floor(math.Floor(y) / spatialCellSize)),
+	}
+}
@@ -0,0 +1,177 @@
+package d2maprenderer
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+)
+
+const (
+	spatialTestEntities = 10000
+	spatialTestMapSize  = 200
+)
+
+// spatialTestScene returns a viewport looking at the middle of a map of randomly placed entities, and their positions.
+func spatialTestScene() (*Viewport, [][2]float64) {
+	random := rand.New(rand.NewSource(1))
+	positions := make([][2]float64, spatialTestEntities)
+
+	for id := range positions {
+		positions[id] = [2]float64{random.Float64() * spatialTestMapSize, random.Float64() * spatialTestMapSize}
+	}
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(&Camera{})
+
+	centerX, centerY := viewport.WorldToOrtho(spatialTestMapSize/2, spatialTestMapSize/2)
+	viewport.camera.MoveTo(centerX, centerY, 0, CameraEasingLinear)
+
+	return viewport, positions
+}
+
+func naiveVisible(viewport *Viewport, positions [][2]float64) []int {
+	var ids []int
+
+	for id, position := range positions {
+		if viewport.IsTileVisible(position[0], position[1]) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func TestSpatialIndexQueryVisible(t *testing.T) {
+	assert := testify.New(t)
+
+	viewport, positions := spatialTestScene()
+	index := NewSpatialIndex()
+
+	for id, position := range positions {
+		index.Insert(id, position[0], position[1])
+	}
+
+	want := naiveVisible(viewport, positions)
+	assert.NotEmpty(want)
+	assert.Equal(want, index.QueryVisible(viewport), "the index finds the entities IsTileVisible is true for")
+
+	// move a visible entity off screen and an off screen entity on screen, and drop another visible one
+	moved, removed, shown := want[0], want[1], 0
+
+	for viewport.IsTileVisible(positions[shown][0], positions[shown][1]) {
+		shown++
+	}
+
+	positions[shown], positions[moved] = positions[moved], [2]float64{0, 0}
+
+	index.Update(moved, 0, 0)
+	index.Update(shown, positions[shown][0], positions[shown][1])
+	index.Remove(removed)
+
+	want = naiveVisible(viewport, positions)
+	for i, id := range want {
+		if id == removed {
+			want = append(want[:i], want[i+1:]...)
+			break
+		}
+	}
+
+	assert.Equal(want, index.QueryVisible(viewport))
+	assert.Equal(spatialTestEntities-1, index.Len())
+}
+
+func TestSpatialIndexQueryRadius(t *testing.T) {
+	assert := testify.New(t)
+
+	_, positions := spatialTestScene()
+	index := NewSpatialIndex()
+
+	for id, position := range positions {
+		index.Insert(id, position[0], position[1])
+	}
+
+	const x, y, radius = 100.5, 99.5, 15
+
+	var want []int
+
+	for id, position := range positions {
+		if math.Hypot(position[0]-x, position[1]-y) <= radius {
+			want = append(want, id)
+		}
+	}
+
+	assert.NotEmpty(want)
+	assert.Equal(want, index.QueryRadius(x, y, radius), "the index finds the entities within the radius")
+	assert.Empty(index.QueryRadius(-100, -100, radius))
+}
+
+// testEntity is a map entity which is only a position.
+type testEntity struct {
+	d2interface.MapEntity
+	x, y float64
+}
+
+func (e *testEntity) GetPositionF() (float64, float64) {
+	return e.x, e.y
+}
+
+func TestEntitiesNear(t *testing.T) {
+	assert := testify.New(t)
+
+	config := d2config.Config
+	d2config.Config = &d2config.Configuration{}
+
+	defer func() { d2config.Config = config }()
+
+	mapEngine := d2mapengine.CreateMapEngine()
+	mr := &MapRenderer{mapEngine: mapEngine}
+
+	near, far, added := &testEntity{x: 10, y: 10}, &testEntity{x: 40, y: 10}, &testEntity{x: 12, y: 8}
+	mapEngine.AddEntity(near)
+	mapEngine.AddEntity(far)
+
+	assert.Empty(mr.EntitiesNear(10, 10, 5), "the entities aren't indexed until the index is updated")
+
+	mr.UpdateEntityIndex()
+	assert.Equal([]d2interface.MapEntity{near}, mr.EntitiesNear(10, 10, 5))
+
+	// the far entity walks over, another is added and the near one leaves the map
+	far.x = 11
+	mapEngine.AddEntity(added)
+	mapEngine.RemoveEntity(near)
+
+	mr.UpdateEntityIndex()
+	assert.Equal([]d2interface.MapEntity{far, added}, mr.EntitiesNear(10, 10, 5))
+	assert.Equal(2, mr.entityIndex.index.Len())
+}
+
+func BenchmarkSpatialIndexQueryVisible(b *testing.B) {
+	viewport, positions := spatialTestScene()
+	index := NewSpatialIndex()
+
+	for id, position := range positions {
+		index.Insert(id, position[0], position[1])
+	}
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		index.QueryVisible(viewport)
+	}
+}
+
+func BenchmarkNaiveQueryVisible(b *testing.B) {
+	viewport, positions := spatialTestScene()
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		naiveVisible(viewport, positions)
+	}
+}
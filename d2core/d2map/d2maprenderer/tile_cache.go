@@ -4,14 +4,13 @@ import (
 	"log"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
-	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
 )
 
 func (mr *MapRenderer) generateTileCache() {
-	mr.palette, _ = loadPaletteForAct(d2enum.RegionIdType(mr.mapEngine.LevelType().ID))
+	mr.palette, _ = d2asset.LoadPalette(mr.mapEngine.LevelType().PalettePath())
 	mapEngineSize := mr.mapEngine.Size()
 
 	for idx, tile := range *mr.mapEngine.Tiles() {
@@ -84,12 +83,11 @@ func (mr *MapRenderer) generateFloorCache(tile *d2ds1.FloorShadowRecord, tileX,
 
 		tileYOffset := d2common.AbsInt32(tileYMinimum)
 		tileHeight := d2common.AbsInt32(tileData[i].Height)
-		image, _ := mr.renderer.NewSurface(int(tileData[i].Width), int(tileHeight), d2enum.FilterNearest)
 		indexData := make([]byte, tileData[i].Width*tileHeight)
 		mr.decodeTileGfxData(tileData[i].Blocks, &indexData, tileYOffset, tileData[i].Width)
 		pixels := d2asset.ImgIndexToRGBA(indexData, mr.palette)
 
-		_ = image.ReplacePixels(pixels)
+		image, _ := mr.newTileSurface(int(tileData[i].Width), int(tileHeight), pixels)
 		mr.setImageCacheRecord(tile.Style, tile.Sequence, 0, tileIndex, image)
 	}
 }
@@ -130,11 +128,10 @@ func (mr *MapRenderer) generateShadowCache(tile *d2ds1.FloorShadowRecord, tileX,
 		return
 	}
 
-	image, _ := mr.renderer.NewSurface(int(tileData.Width), tileHeight, d2enum.FilterNearest)
 	indexData := make([]byte, tileData.Width*int32(tileHeight))
 	mr.decodeTileGfxData(tileData.Blocks, &indexData, tileYOffset, tileData.Width)
 	pixels := d2asset.ImgIndexToRGBA(indexData, mr.palette)
-	_ = image.ReplacePixels(pixels)
+	image, _ := mr.newTileSurface(int(tileData.Width), tileHeight, pixels)
 	mr.setImageCacheRecord(tile.Style, tile.Sequence, 13, tileIndex, image)
 }
 
@@ -196,7 +193,6 @@ func (mr *MapRenderer) generateWallCache(tile *d2ds1.WallRecord, tileX, tileY in
 		return
 	}
 
-	image, _ := mr.renderer.NewSurface(160, int(realHeight), d2enum.FilterNearest)
 	indexData := make([]byte, 160*realHeight)
 
 	mr.decodeTileGfxData(tileData.Blocks, &indexData, tileYOffset, 160)
@@ -207,7 +203,8 @@ func (mr *MapRenderer) generateWallCache(tile *d2ds1.WallRecord, tileX, tileY in
 
 	pixels := d2asset.ImgIndexToRGBA(indexData, mr.palette)
 
-	if err := image.ReplacePixels(pixels); err != nil {
+	image, err := mr.newTileSurface(160, int(realHeight), pixels)
+	if err != nil {
 		log.Panicf(err.Error())
 	}
 
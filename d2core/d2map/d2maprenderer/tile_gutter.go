@@ -0,0 +1,65 @@
+package d2maprenderer
+
+import (
+	"image"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// tileGutter is the width, in pixels, of the border around the cached tile images which repeats their edge pixels.
+// Filtering samples the pixels around the one drawn, so without it the edges of a scaled tile blend with whatever is
+// next to the image, showing seams between the tiles.
+const tileGutter = 1
+
+// newTileSurface returns a surface with the RGBA pixels of a tile of the given size, inside a gutter repeating its
+// edge pixels. It is drawn with renderTileSurface.
+func (mr *MapRenderer) newTileSurface(width, height int, pixels []byte) (d2interface.Surface, error) {
+	surface, err := mr.renderer.NewSurface(width+tileGutter*2, height+tileGutter*2, d2enum.FilterNearest)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := surface.ReplacePixels(padTilePixels(pixels, width, height)); err != nil {
+		return nil, err
+	}
+
+	return surface, nil
+}
+
+// padTilePixels returns the RGBA pixels of the image of the given size inside a gutter repeating its edge pixels.
+func padTilePixels(pixels []byte, width, height int) []byte {
+	const bytesPerPixel = 4
+
+	paddedWidth, paddedHeight := width+tileGutter*2, height+tileGutter*2
+	padded := make([]byte, paddedWidth*paddedHeight*bytesPerPixel)
+
+	for y := 0; y < paddedHeight; y++ {
+		srcY := d2common.MaxInt(0, d2common.MinInt(y-tileGutter, height-1))
+
+		for x := 0; x < paddedWidth; x++ {
+			srcX := d2common.MaxInt(0, d2common.MinInt(x-tileGutter, width-1))
+			src := (srcY*width + srcX) * bytesPerPixel
+			dst := (y*paddedWidth + x) * bytesPerPixel
+
+			copy(padded[dst:dst+bytesPerPixel], pixels[src:src+bytesPerPixel])
+		}
+	}
+
+	return padded
+}
+
+// tileSize returns the size of the tile image of the surface, without its gutter.
+func tileSize(surface d2interface.Surface) (width, height int) {
+	width, height = surface.GetSize()
+	return width - tileGutter*2, height - tileGutter*2
+}
+
+// renderTileSurface draws the tile image of the surface made by newTileSurface at the current translation of the
+// target, sampling the gutter around it but not drawing it.
+func renderTileSurface(target, surface d2interface.Surface) error {
+	width, height := tileSize(surface)
+
+	return target.RenderSection(surface, image.Rect(tileGutter, tileGutter, tileGutter+width, tileGutter+height))
+}
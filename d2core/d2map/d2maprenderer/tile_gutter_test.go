@@ -0,0 +1,94 @@
+package d2maprenderer
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2render/headless"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestPadTilePixels(t *testing.T) {
+	assert := testify.New(t)
+
+	red, blue := []byte{0xff, 0, 0, 0xff}, []byte{0, 0, 0xff, 0xff}
+	pixels := append(append([]byte{}, red...), blue...)
+
+	padded := padTilePixels(pixels, 2, 1)
+	assert.Len(padded, 4*3*4)
+
+	// every row repeats the single row of the image, with its first and last pixels repeated on each side
+	for y := 0; y < 3; y++ {
+		row := padded[y*16 : (y+1)*16]
+		assert.Equal(red, row[0:4], "row %d", y)
+		assert.Equal(red, row[4:8], "row %d", y)
+		assert.Equal(blue, row[8:12], "row %d", y)
+		assert.Equal(blue, row[12:16], "row %d", y)
+	}
+}
+
+// TestTileSeams draws a row of opaque floor tiles side by side at several zoom levels, with the linear filter, and
+// checks no pixel along the row lets the background through.
+func TestTileSeams(t *testing.T) {
+	assert := testify.New(t)
+
+	config := d2config.Config
+	d2config.Config = &d2config.Configuration{}
+
+	defer func() { d2config.Config = config }()
+	defer InvalidateImageCache()
+
+	renderer, err := headless.CreateRenderer()
+	assert.NoError(err)
+
+	mr := &MapRenderer{renderer: renderer, viewport: NewViewport(0, 0, 800, 600), zoomedOutFilter: d2enum.FilterLinear}
+	mr.viewport.SetCamera(&mr.camera)
+	mr.camera.MoveTo(0.37, 0.61, 0, CameraEasingLinear)
+
+	// the tiles alternate between two colors, so both sides of each seam come from different images
+	for idx, color := range [2][4]byte{{0xff, 0, 0, 0xff}, {0, 0, 0xff, 0xff}} {
+		pixels := make([]byte, 0, 160*80*4)
+		for i := 0; i < 160*80; i++ {
+			pixels = append(pixels, color[:]...)
+		}
+
+		tile, tileErr := mr.newTileSurface(160, 80, pixels)
+		assert.NoError(tileErr)
+		mr.setImageCacheRecord(0, 0, 0, byte(idx), tile)
+	}
+
+	const tiles = 2 // on each side of the center of the screen
+
+	for _, zoom := range []float64{0.25, 0.3, 0.3275, 0.33, 0.41, 0.5, 0.6, 0.7, 0.75, 0.87, 1} {
+		mr.SetCameraZoom(zoom)
+		zoom = mr.camera.GetZoom()
+
+		screen, _ := renderer.NewSurface(800, 600, d2enum.FilterNearest)
+
+		mr.pushZoomFilter(screen)
+
+		// tiles at (k, -k) are side by side along the screen x axis
+		for k := -tiles; k <= tiles; k++ {
+			mr.viewport.PushTranslationWorld(float64(k), float64(-k))
+			mr.renderFloor(d2ds1.FloorShadowRecord{RandomIndex: byte((k + tiles) % 2)}, screen)
+			popTranslation(mr.viewport)
+		}
+
+		pixels := screen.Screenshot()
+		left, y := mr.viewport.WorldToScreen(-tiles, tiles)
+		left -= int(80 * zoom)
+		y += int(40 * zoom)
+		right, _ := mr.viewport.WorldToScreen(tiles, -tiles)
+		right += int(80 * zoom)
+
+		for x := left + 1; x < right-1; x++ {
+			if pixel := pixels.RGBAAt(x, y); pixel.A != 0xff {
+				t.Errorf("seam at zoom %g, x %d: %v", zoom, x, pixel)
+				break
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package d2maprenderer
 
 import (
+	"errors"
 	"math"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
@@ -11,10 +12,48 @@ type worldTrans struct {
 	y float64
 }
 
+// ViewportAlignment is the part of the screen a viewport renders the map to, leaving the rest to the HUD panels or to
+// the viewport of another player.
+type ViewportAlignment int
+
+// Viewport alignments
+const (
+	// AlignCenter renders the map to the whole screen.
+	AlignCenter ViewportAlignment = iota
+
+	// AlignLeft renders the map to the right half of the screen, shifting the view right to make room for a panel on
+	// the left.
+	AlignLeft
+
+	// AlignRight renders the map to the left half of the screen, shifting the view left to make room for a panel on
+	// the right.
+	AlignRight
+)
+
+// ProjectionMode is how a viewport projects the world onto the screen.
+type ProjectionMode int
+
+// Projection modes
+const (
+	// ProjectionIso draws the world isometrically, as the game does.
+	ProjectionIso ProjectionMode = iota
+
+	// ProjectionOrtho draws the world from straight above, with tiles as squares as wide as half an isometric tile,
+	// to debug the geometry of levels.
+	ProjectionOrtho
+)
+
+// visibleTileMargin is the number of tiles around the screen which could still be visible. IsTileVisible counts
+// tiles up to 3 tiles off screen as visible, for the walls drawn above them.
+const visibleTileMargin = 3
+
+// subCellsPerTile is the number of sub cells along each side of a tile, used for pathing and object placement.
+const subCellsPerTile = 5
+
+// The orthogonal size of half a tile at a zoom of 1.
 const (
-	center = 0
-	left   = 1
-	right  = 2
+	tileHalfWidth  = 80
+	tileHalfHeight = 40
 )
 
 // Viewport is used for converting vectors between screen (pixel), orthogonal (camera) and world (isometric) space.
@@ -25,12 +64,34 @@ type Viewport struct {
 	transStack        []worldTrans
 	transCurrent      worldTrans
 	camera            *Camera
-	align             int
+	align             ViewportAlignment
+	projection        ProjectionMode
+	savedCamera       *cameraState // Camera position restored by UnmarshalState, until the camera is set
+
+	framing      bool       // whether a frame began and the camera offset is cached
+	offsetDirty  bool       // whether the cached camera offset must be computed again
+	cameraOffset worldTrans // camera offset cached for the frame
+
+	scale float64 // screen pixels per orthogonal pixel, 0 until set for 1
+
+	halfTileWidth, halfTileHeight float64 // orthogonal size of half a tile at a zoom of 1
+
+	dirtyRect d2common.Rectangle // screen space bounds of the world marked dirty since last consumed
+	dirty     bool               // whether anything was marked dirty since last consumed
 }
 
-// NewViewport creates a new Viewport with the given parameters and returns a pointer to it.
+// NewViewport creates a new Viewport with the given parameters and returns a pointer to it. The tiles are the
+// 160 by 80 pixel tiles of Diablo II.
 func NewViewport(x, y, width, height int) *Viewport {
+	return NewViewportWithTileSize(x, y, width, height, tileHalfWidth*2, tileHalfHeight*2)
+}
+
+// NewViewportWithTileSize creates a new Viewport projecting tiles of the given orthogonal size in pixels at a zoom of
+// 1, for mods with art at another tile resolution than Diablo II.
+func NewViewportWithTileSize(x, y, width, height, tileWidth, tileHeight int) *Viewport {
 	return &Viewport{
+		halfTileWidth:  float64(tileWidth) / 2,
+		halfTileHeight: float64(tileHeight) / 2,
 		screenRect: d2common.Rectangle{
 			Left:   x,
 			Top:    y,
@@ -46,14 +107,75 @@ func NewViewport(x, y, width, height int) *Viewport {
 	}
 }
 
-// SetCamera sets the current camera to the given value.
+// SetCamera sets the current camera to the given value. If UnmarshalState restored a camera position while the
+// viewport had no camera, the camera is moved there.
 func (v *Viewport) SetCamera(camera *Camera) {
 	v.camera = camera
+	v.offsetDirty = true
+
+	if v.savedCamera != nil {
+		v.savedCamera.restore(camera)
+		v.savedCamera = nil
+	}
+
+	v.updateCameraView()
+}
+
+// SetScaleFactor sets the number of screen pixels per orthogonal pixel, the DPI scale of the display, for a framebuffer
+// larger than the window. The screen positions of the conversions, the screen rect and the visibility checks are in
+// framebuffer pixels. Scales of 0 or less restore the default of 1.
+func (v *Viewport) SetScaleFactor(scale float64) {
+	v.scale = math.Max(0, scale)
+	v.offsetDirty = true
+	v.updateCameraView()
+}
+
+// GetScaleFactor returns the number of screen pixels per orthogonal pixel.
+func (v *Viewport) GetScaleFactor() float64 {
+	if v.scale == 0 {
+		return 1
+	}
+
+	return v.scale
+}
+
+// updateCameraView sets the orthogonal size of the view of the camera, if any, and of its tiles to those of the
+// viewport.
+func (v *Viewport) updateCameraView() {
+	if v.camera == nil {
+		return
+	}
+
+	v.camera.setTileSize(v.halfTileWidth, v.halfTileHeight)
+
+	scale := v.GetScaleFactor()
+	v.camera.setViewSize(int(math.Round(float64(v.screenRect.Width)/scale)),
+		int(math.Round(float64(v.screenRect.Height)/scale)))
+}
+
+// Clone returns a copy of the viewport with its own translation stack, so translations pushed onto the copy, as when
+// projecting an off-screen render, don't affect the original. The copy shares the camera of the original.
+func (v *Viewport) Clone() *Viewport {
+	clone := *v
+	clone.transStack = append([]worldTrans(nil), v.transStack...)
+
+	if v.savedCamera != nil {
+		savedCamera := *v.savedCamera
+		clone.savedCamera = &savedCamera
+	}
+
+	return &clone
 }
 
 // WorldToScreen returns the screen space for the given world coordinates as two integers.
 func (v *Viewport) WorldToScreen(x, y float64) (int, int) {
-	return v.OrthoToScreen(v.WorldToOrtho(x, y))
+	screen := v.WorldToScreenV(d2common.Vector2{X: x, Y: y})
+	return screen.X, screen.Y
+}
+
+// WorldToScreenV returns the screen space for the given world position.
+func (v *Viewport) WorldToScreenV(world d2common.Vector2) d2common.Vector2Int {
+	return v.OrthoToScreenV(v.WorldToOrthoV(world))
 }
 
 // WorldToScreenF returns the screen space for the given world coordinates as two float64s.
@@ -61,70 +183,369 @@ func (v *Viewport) WorldToScreenF(x, y float64) (float64, float64) {
 	return v.OrthoToScreenF(v.WorldToOrtho(x, y))
 }
 
+// WorldToScreenBatch converts the world positions of the points to screen space into out, like WorldToScreen, but
+// looks up the camera offset and zoom once for all of them. It converts as many points as out has room for and
+// returns the number converted.
+func (v *Viewport) WorldToScreenBatch(points []d2common.Vector2, out []d2common.Vector2Int) int {
+	halfWidth, halfHeight := v.tileHalfSize()
+	camX, camY := v.getCameraOffset()
+	left, top := float64(v.screenRect.Left), float64(v.screenRect.Top)
+	scale := v.GetScaleFactor()
+	isometric := v.projection == ProjectionIso
+	rotation := v.rotation()
+
+	count := len(points)
+	if len(out) < count {
+		count = len(out)
+	}
+
+	for index := 0; index < count; index++ {
+		worldX, worldY := rotateWorld(points[index].X, points[index].Y, rotation)
+		orthoX, orthoY := worldX*halfWidth, worldY*halfWidth
+
+		if isometric {
+			orthoX = (worldX - worldY) * halfWidth
+			orthoY = (worldX + worldY) * halfHeight
+		}
+
+		out[index].X = int(math.Floor((orthoX-camX)*scale + left))
+		out[index].Y = int(math.Floor((orthoY-camY)*scale + top))
+	}
+
+	return count
+}
+
 // ScreenToWorld returns the world position for the given screen coordinates.
 func (v *Viewport) ScreenToWorld(x, y int) (float64, float64) {
-	return v.OrthoToWorld(v.ScreenToOrtho(x, y))
+	world := v.ScreenToWorldV(d2common.Vector2Int{X: x, Y: y})
+	return world.X, world.Y
+}
+
+// ScreenToWorldV returns the world position for the given screen position.
+func (v *Viewport) ScreenToWorldV(screen d2common.Vector2Int) d2common.Vector2 {
+	return v.OrthoToWorldV(v.ScreenToOrthoV(screen))
+}
+
+// ScreenToTile returns the tile under the screen position. Positions above or left of the origin of the world land in
+// negative tiles, so a position at world (-0.3, 2.7) is in tile (-1, 2).
+func (v *Viewport) ScreenToTile(x, y int) (tileX, tileY int) {
+	_, tileX, tileY = v.ScreenToGround(x, y)
+	return tileX, tileY
+}
+
+// ScreenToGround returns where the screen position lands on the ground, both as a world position and as the tile it
+// is in, converting the position once. As with ScreenToTile, positions above or left of the origin of the world land
+// in negative tiles.
+func (v *Viewport) ScreenToGround(x, y int) (world d2common.Vector2, tileX, tileY int) {
+	world = v.ScreenToWorldV(d2common.Vector2Int{X: x, Y: y})
+	tile := world.Floor()
+
+	return world, tile.X, tile.Y
+}
+
+// ScreenToSubCell returns the tile the screen position lands in, and the sub cell of the tile from 0 to 4 on each
+// axis. Positions above or left of the origin of the world land in negative tiles.
+func (v *Viewport) ScreenToSubCell(x, y int) (tileX, tileY, subX, subY int) {
+	subCell := v.ScreenToWorldV(d2common.Vector2Int{X: x, Y: y}).Scale(subCellsPerTile).Floor()
+
+	tileX = floorDiv(subCell.X, subCellsPerTile)
+	tileY = floorDiv(subCell.Y, subCellsPerTile)
+
+	return tileX, tileY, subCell.X - tileX*subCellsPerTile, subCell.Y - tileY*subCellsPerTile
+}
+
+// floorDiv divides a by b rounding down, rather than toward zero, for a positive b.
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+
+	return a / b
 }
 
 // OrthoToWorld returns the world position for the given orthogonal coordinates.
 func (v *Viewport) OrthoToWorld(x, y float64) (float64, float64) {
-	worldX := (x/80 + y/40) / 2
-	worldY := (y/40 - x/80) / 2
+	world := v.OrthoToWorldV(d2common.Vector2{X: x, Y: y})
+	return world.X, world.Y
+}
+
+// OrthoToWorldV returns the world position for the given orthogonal position.
+func (v *Viewport) OrthoToWorldV(ortho d2common.Vector2) d2common.Vector2 {
+	halfWidth, halfHeight := v.tileHalfSize()
+
+	var world d2common.Vector2
+
+	if v.projection == ProjectionOrtho {
+		world = ortho.Scale(1 / halfWidth)
+	} else {
+		world = d2common.Vector2{
+			X: (ortho.X/halfWidth + ortho.Y/halfHeight) / 2,
+			Y: (ortho.Y/halfHeight - ortho.X/halfWidth) / 2,
+		}
+	}
+
+	world.X, world.Y = unrotateWorld(world.X, world.Y, v.rotation())
 
-	return worldX, worldY
+	return world
 }
 
 // WorldToOrtho returns the orthogonal position for the given world coordinates.
 func (v *Viewport) WorldToOrtho(x, y float64) (float64, float64) {
-	orthoX := (x - y) * 80
-	orthoY := (x + y) * 40
+	ortho := v.WorldToOrthoV(d2common.Vector2{X: x, Y: y})
+	return ortho.X, ortho.Y
+}
+
+// WorldToOrthoV returns the orthogonal position for the given world position, turned by the rotation of the camera.
+func (v *Viewport) WorldToOrthoV(world d2common.Vector2) d2common.Vector2 {
+	halfWidth, halfHeight := v.tileHalfSize()
+	world.X, world.Y = rotateWorld(world.X, world.Y, v.rotation())
+
+	if v.projection == ProjectionOrtho {
+		return world.Scale(halfWidth)
+	}
 
-	return orthoX, orthoY
+	return d2common.Vector2{
+		X: (world.X - world.Y) * halfWidth,
+		Y: (world.X + world.Y) * halfHeight,
+	}
 }
 
 // ScreenToOrtho returns the orthogonal position for the given screen coordinates.
 func (v *Viewport) ScreenToOrtho(x, y int) (float64, float64) {
+	ortho := v.ScreenToOrthoV(d2common.Vector2Int{X: x, Y: y})
+	return ortho.X, ortho.Y
+}
+
+// ScreenToOrthoV returns the orthogonal position for the given screen position.
+func (v *Viewport) ScreenToOrthoV(screen d2common.Vector2Int) d2common.Vector2 {
 	camX, camY := v.getCameraOffset()
-	screenX := float64(x) + camX - float64(v.screenRect.Left)
-	screenY := float64(y) + camY - float64(v.screenRect.Top)
+	scale := v.GetScaleFactor()
 
-	return screenX, screenY
+	return d2common.Vector2{
+		X: float64(screen.X-v.screenRect.Left)/scale + camX,
+		Y: float64(screen.Y-v.screenRect.Top)/scale + camY,
+	}
 }
 
 // OrthoToScreen returns the screen position for the given orthogonal coordinates as two ints.
 func (v *Viewport) OrthoToScreen(x, y float64) (int, int) {
-	camOrthoX, camOrthoY := v.getCameraOffset()
-	orthoX := int(math.Floor(x - camOrthoX + float64(v.screenRect.Left)))
-	orthoY := int(math.Floor(y - camOrthoY + float64(v.screenRect.Top)))
+	screen := v.OrthoToScreenV(d2common.Vector2{X: x, Y: y})
+	return screen.X, screen.Y
+}
 
-	return orthoX, orthoY
+// OrthoToScreenV returns the screen position for the given orthogonal position.
+func (v *Viewport) OrthoToScreenV(ortho d2common.Vector2) d2common.Vector2Int {
+	return v.orthoToScreenV(ortho).Floor()
 }
 
 // OrthoToScreenF returns the screen position for the given orthogonal coordinates as two float64s.
 func (v *Viewport) OrthoToScreenF(x, y float64) (float64, float64) {
-	camOrthoX, camOrthoY := v.getCameraOffset()
-	orthoX := x - camOrthoX + float64(v.screenRect.Left)
-	orthoY := y - camOrthoY + float64(v.screenRect.Top)
+	screen := v.orthoToScreenV(d2common.Vector2{X: x, Y: y})
+	return screen.X, screen.Y
+}
 
-	return orthoX, orthoY
+// orthoToScreenV returns the screen position for the given orthogonal position, without rounding it.
+func (v *Viewport) orthoToScreenV(ortho d2common.Vector2) d2common.Vector2 {
+	camX, camY := v.getCameraOffset()
+	scale := v.GetScaleFactor()
+
+	return d2common.Vector2{
+		X: (ortho.X-camX)*scale + float64(v.screenRect.Left),
+		Y: (ortho.Y-camY)*scale + float64(v.screenRect.Top),
+	}
+}
+
+// GetVisibleWorldBounds returns the smallest rectangle of whole tiles in world space which encloses the area of the
+// map on screen. When the map is drawn isometrically, the rectangle also encloses tiles off the corners of the screen.
+func (v *Viewport) GetVisibleWorldBounds() d2common.Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+
+	corners := [][2]int{
+		{v.screenRect.Left, v.screenRect.Top},
+		{v.screenRect.Right(), v.screenRect.Top},
+		{v.screenRect.Left, v.screenRect.Bottom()},
+		{v.screenRect.Right(), v.screenRect.Bottom()},
+	}
+
+	for _, corner := range corners {
+		worldX, worldY := v.ScreenToWorld(corner[0], corner[1])
+		minX, maxX = math.Min(minX, worldX), math.Max(maxX, worldX)
+		minY, maxY = math.Min(minY, worldY), math.Max(maxY, worldY)
+	}
+
+	left, top := int(math.Floor(minX)), int(math.Floor(minY))
+
+	return d2common.Rectangle{
+		Left:   left,
+		Top:    top,
+		Width:  int(math.Ceil(maxX)) - left,
+		Height: int(math.Ceil(maxY)) - top,
+	}
+}
+
+// WorldRectToScreenBounds returns the smallest rectangle in screen space which encloses the world space rectangle. The
+// isometric projection turns the rectangle into a diamond, so all four of its corners are projected, and the bounds are
+// rounded outward to whole pixels.
+func (v *Viewport) WorldRectToScreenBounds(rect d2common.Rectangle) d2common.Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+
+	corners := [][2]int{
+		{rect.Left, rect.Top},
+		{rect.Right(), rect.Top},
+		{rect.Left, rect.Bottom()},
+		{rect.Right(), rect.Bottom()},
+	}
+
+	for _, corner := range corners {
+		screenX, screenY := v.WorldToScreenF(float64(corner[0]), float64(corner[1]))
+		minX, maxX = math.Min(minX, screenX), math.Max(maxX, screenX)
+		minY, maxY = math.Min(minY, screenY), math.Max(maxY, screenY)
+	}
+
+	left, top := int(math.Floor(minX)), int(math.Floor(minY))
+
+	return d2common.Rectangle{
+		Left:   left,
+		Top:    top,
+		Width:  int(math.Ceil(maxX)) - left,
+		Height: int(math.Ceil(maxY)) - top,
+	}
+}
+
+// GetVisibleRadius returns the distance in world space, in tiles, from the point the camera centers on to the furthest
+// tile which could be on screen, counting the tiles IsTileVisible counts as visible off the screen.
+func (v *Viewport) GetVisibleRadius() float64 {
+	center := v.ScreenToWorldV(d2common.Vector2Int{
+		X: v.screenRect.Left + v.screenRect.Width/2,
+		Y: v.screenRect.Top + v.screenRect.Height/2,
+	})
+
+	corners := []d2common.Vector2Int{
+		{X: v.screenRect.Left, Y: v.screenRect.Top},
+		{X: v.screenRect.Right(), Y: v.screenRect.Top},
+		{X: v.screenRect.Left, Y: v.screenRect.Bottom()},
+		{X: v.screenRect.Right(), Y: v.screenRect.Bottom()},
+	}
+
+	radius := 0.0
+
+	for _, corner := range corners {
+		offset := v.ScreenToWorldV(corner).Sub(center)
+		radius = math.Max(radius, math.Hypot(offset.X, offset.Y))
+	}
+
+	return radius + visibleTileMargin
+}
+
+// GetVisibleTileRange returns the inclusive range of tiles which could be on screen: the visible world bounds with
+// a margin on each side, so no tile partially on screen, or counted as visible by IsTileVisible, is left out.
+func (v *Viewport) GetVisibleTileRange() (minX, minY, maxX, maxY int) {
+	bounds := v.GetVisibleWorldBounds()
+
+	return bounds.Left - visibleTileMargin, bounds.Top - visibleTileMargin,
+		bounds.Right() + visibleTileMargin, bounds.Bottom() + visibleTileMargin
 }
 
 // IsTileVisible returns false if no part of the tile is within the game screen.
 func (v *Viewport) IsTileVisible(x, y float64) bool {
-	orthoX1, orthoY1 := v.WorldToOrtho(x-3, y)
-	orthoX2, orthoY2 := v.WorldToOrtho(x+3, y)
+	return v.IsTileVisibleWithMargin(x, y, 0)
+}
+
+// IsTileVisibleWithMargin returns false if no part of the tile is within the game screen grown by the margin, in
+// tiles, on every side. The margin is added around the tile in world space, so it follows the projection.
+func (v *Viewport) IsTileVisibleWithMargin(x, y float64, marginTiles int) bool {
+	margin := float64(marginTiles)
 
-	return v.IsOrthoRectVisible(orthoX1, orthoY1, orthoX2, orthoY2)
+	// isometric tiles reach visibleTileMargin tiles along x for the walls drawn above them
+	reachY := 0.0
+	if v.projection == ProjectionOrtho {
+		reachY = visibleTileMargin
+	}
+
+	return v.isWorldRectVisible(x-visibleTileMargin-margin, y-reachY-margin, x+visibleTileMargin+margin, y+reachY+margin)
 }
 
 // IsTileRectVisible returns false if none of the tiles rects are within the game screen.
 func (v *Viewport) IsTileRectVisible(rect d2common.Rectangle) bool {
-	left := float64((rect.Left - rect.Bottom()) * 80)
-	top := float64((rect.Left + rect.Top) * 40)
-	right := float64((rect.Right() - rect.Top) * 80)
-	bottom := float64((rect.Right() + rect.Bottom()) * 40)
+	return v.IsTileRectVisibleWithMargin(rect, 0)
+}
 
-	return v.IsOrthoRectVisible(left, top, right, bottom)
+// IsTileRectVisibleWithMargin returns false if none of the tiles of the rect are within the game screen grown by the
+// margin, in tiles, on every side. The margin is added around the rect in world space, so it follows the projection.
+func (v *Viewport) IsTileRectVisibleWithMargin(rect d2common.Rectangle, marginTiles int) bool {
+	return v.isWorldRectVisible(float64(rect.Left-marginTiles), float64(rect.Top-marginTiles),
+		float64(rect.Right()+marginTiles), float64(rect.Bottom()+marginTiles))
+}
+
+// GridLines returns the screen space start and end points of the edges of the tiles within the inclusive tile range
+// which are on screen, to draw the tile grid over the map. An edge shared by two tiles is returned once. The points
+// are the WorldToScreen positions of the corners of the tiles, so the grid lines up with the tiles drawn.
+func (v *Viewport) GridLines(minTileX, minTileY, maxTileX, maxTileY int) [][2]d2common.Vector2Int {
+	var lines [][2]d2common.Vector2Int
+
+	visible := func(x, y int) bool {
+		return x >= minTileX && x <= maxTileX && y >= minTileY && y <= maxTileY &&
+			v.IsTileRectVisible(d2common.Rectangle{Left: x, Top: y, Width: 1, Height: 1})
+	}
+
+	edge := func(x1, y1, x2, y2 int) {
+		lines = append(lines, [2]d2common.Vector2Int{
+			v.WorldToScreenV(d2common.Vector2{X: float64(x1), Y: float64(y1)}),
+			v.WorldToScreenV(d2common.Vector2{X: float64(x2), Y: float64(y2)}),
+		})
+	}
+
+	for y := minTileY; y <= maxTileY; y++ {
+		for x := minTileX; x <= maxTileX; x++ {
+			if !visible(x, y) {
+				continue
+			}
+
+			edge(x, y, x+1, y)
+			edge(x, y, x, y+1)
+
+			// the right and bottom edges are the left and top edges of the next tiles, unless those aren't returned
+			if !visible(x+1, y) {
+				edge(x+1, y, x+1, y+1)
+			}
+
+			if !visible(x, y+1) {
+				edge(x, y+1, x+1, y+1)
+			}
+		}
+	}
+
+	return lines
+}
+
+// TileHighlightPoints returns the screen space corners of the tile, clockwise from the top one on an unrotated
+// screen, to draw a closed polygon around the tile. The corners are the WorldToScreen positions of the corners of the
+// tile, so the polygon lines up with the tile drawn.
+func (v *Viewport) TileHighlightPoints(tileX, tileY int) [4]d2common.Vector2Int {
+	x, y := float64(tileX), float64(tileY)
+
+	return [4]d2common.Vector2Int{
+		v.WorldToScreenV(d2common.Vector2{X: x, Y: y}),
+		v.WorldToScreenV(d2common.Vector2{X: x + 1, Y: y}),
+		v.WorldToScreenV(d2common.Vector2{X: x + 1, Y: y + 1}),
+		v.WorldToScreenV(d2common.Vector2{X: x, Y: y + 1}),
+	}
+}
+
+// isWorldRectVisible returns false if the orthogonal box around the world space rectangle is outside the game screen.
+func (v *Viewport) isWorldRectVisible(left, top, right, bottom float64) bool {
+	halfWidth, halfHeight := v.tileHalfSize()
+	left, top, right, bottom = rotateWorldRect(left, top, right, bottom, v.rotation())
+
+	if v.projection == ProjectionOrtho {
+		return v.IsOrthoRectVisible(left*halfWidth, top*halfWidth, right*halfWidth, bottom*halfWidth)
+	}
+
+	// the corners of the rectangle are a diamond on screen
+	return v.IsOrthoRectVisible((left-bottom)*halfWidth, (left+top)*halfHeight, (right-top)*halfWidth,
+		(right+bottom)*halfHeight)
 }
 
 // IsOrthoRectVisible returns false if the given orthogonal position is outside the game screen.
@@ -164,54 +585,216 @@ func (v *Viewport) PushTranslationScreen(x, y int) {
 	v.PushTranslationOrtho(v.ScreenToOrtho(x, y))
 }
 
-// PopTranslation pops a translation from the stack.
+// WithTranslationOrtho pushes the orthogonal translation, calls the function, then restores the translation stack to
+// the depth it had before the push, even if the function panics or leaves translations of its own pushed.
+func (v *Viewport) WithTranslationOrtho(x, y float64, fn func()) {
+	defer v.restoreTransStackDepth(v.GetTransStackDepth())
+
+	v.PushTranslationOrtho(x, y)
+	fn()
+}
+
+// WithTranslationWorld is WithTranslationOrtho with a translation in world space.
+func (v *Viewport) WithTranslationWorld(x, y float64, fn func()) {
+	defer v.restoreTransStackDepth(v.GetTransStackDepth())
+
+	v.PushTranslationWorld(x, y)
+	fn()
+}
+
+// WithTranslationScreen is WithTranslationOrtho with a translation in screen space.
+func (v *Viewport) WithTranslationScreen(x, y int, fn func()) {
+	defer v.restoreTransStackDepth(v.GetTransStackDepth())
+
+	v.PushTranslationScreen(x, y)
+	fn()
+}
+
+// restoreTransStackDepth pops the translations pushed past the given depth.
+func (v *Viewport) restoreTransStackDepth(depth int) {
+	if len(v.transStack) > depth {
+		v.transCurrent = v.transStack[depth]
+		v.transStack = v.transStack[:depth]
+	}
+}
+
+// errEmptyTranslationStack is returned when popping more translations than were pushed.
+var errEmptyTranslationStack = errors.New("no viewport translation to pop, a translation was popped without a push")
+
+// PopTranslation pops a translation from the stack. Panics if the stack is empty, see TryPopTranslation.
 func (v *Viewport) PopTranslation() {
+	if err := v.TryPopTranslation(); err != nil {
+		panic(err)
+	}
+}
+
+// TryPopTranslation pops a translation from the stack. Returns an error if the stack is empty.
+func (v *Viewport) TryPopTranslation() error {
 	count := len(v.transStack)
 	if count == 0 {
-		panic("empty stack")
+		return errEmptyTranslationStack
 	}
 
 	v.transCurrent = v.transStack[count-1]
 	v.transStack = v.transStack[:count-1]
+
+	return nil
+}
+
+// GetTransStackDepth returns the number of translations pushed and not popped yet.
+func (v *Viewport) GetTransStackDepth() int {
+	return len(v.transStack)
+}
+
+// rotation returns the quarter turns the camera rotates the view by, none without a camera.
+func (v *Viewport) rotation() int {
+	if v.camera == nil {
+		return 0
+	}
+
+	return v.camera.GetRotation()
+}
+
+// tileHalfSize returns the orthogonal size of half a tile at the zoom of the camera.
+func (v *Viewport) tileHalfSize() (float64, float64) {
+	zoom := v.zoom()
+
+	return v.halfTileWidth * zoom, v.halfTileHeight * zoom
+}
+
+// zoom returns the zoom of the camera, 1 without a camera.
+func (v *Viewport) zoom() float64 {
+	if v.camera == nil {
+		return 1
+	}
+
+	return v.camera.GetZoom()
+}
+
+// drawScale returns the scale the images of the map are drawn at, so they stay the size of the tiles on screen.
+func (v *Viewport) drawScale() float64 {
+	return v.zoom() * v.GetScaleFactor()
+}
+
+// BeginFrame caches the camera offset, so the conversions made while rendering the frame don't look up the camera
+// each time. Moving the camera through the map renderer, or changing the camera or alignment of the viewport, before
+// EndFrame computes the offset again. The translations don't change the offset.
+func (v *Viewport) BeginFrame() {
+	v.framing = true
+	v.offsetDirty = true
+}
+
+// EndFrame stops caching the camera offset, for the conversions made between frames while the camera moves.
+func (v *Viewport) EndFrame() {
+	v.framing = false
+}
+
+// cameraMoved makes the next conversion of the frame compute the camera offset again.
+func (v *Viewport) cameraMoved() {
+	v.offsetDirty = true
 }
 
 func (v *Viewport) getCameraOffset() (float64, float64) {
+	if !v.framing {
+		return v.computeCameraOffset()
+	}
+
+	if v.offsetDirty {
+		v.cameraOffset.x, v.cameraOffset.y = v.computeCameraOffset()
+		v.offsetDirty = false
+	}
+
+	return v.cameraOffset.x, v.cameraOffset.y
+}
+
+func (v *Viewport) computeCameraOffset() (float64, float64) {
 	var camX, camY float64
 	if v.camera != nil {
-		camX, camY = v.camera.GetPosition()
+		camX, camY = v.camera.GetRenderPosition()
 	}
 
-	camX -= float64(v.screenRect.Width / 2)
-	camY -= float64(v.screenRect.Height / 2)
+	// the camera is at the center of the view, in orthogonal pixels
+	scale := v.GetScaleFactor()
+	camX -= float64(v.screenRect.Width) / 2 / scale
+	camY -= float64(v.screenRect.Height) / 2 / scale
 
 	return camX, camY
 }
 
-func (v *Viewport) toLeft() {
-	if v.align == left {
-		return
-	}
+// GetClipRect returns the part of the screen the viewport renders the map to, in screen pixels, following the
+// alignment. Drawing through the viewport should be clipped to it, so the map of one viewport doesn't bleed into
+// another's during split screen.
+func (v *Viewport) GetClipRect() d2common.Rectangle {
+	return v.screenRect
+}
 
-	v.screenRect.Width = v.defaultScreenRect.Width / 2
-	v.screenRect.Left = v.defaultScreenRect.Left + v.defaultScreenRect.Width/2
-	v.align = left
+// GetFillRect returns the part of the screen the viewport owns, in screen pixels, to fill with the background before
+// drawing the map. The halves of a split screen own adjacent rectangles, so filling both leaves no seam between them.
+func (v *Viewport) GetFillRect() d2common.Rectangle {
+	return v.screenRect
 }
 
-func (v *Viewport) toRight() {
-	if v.align == right {
-		return
-	}
+// ComputeSplitViewports splits the rectangle into a left and a right half, adjacent and together covering all of it.
+// When the width is odd, the right half gets the extra pixel.
+func ComputeSplitViewports(total d2common.Rectangle) (left, right d2common.Rectangle) {
+	left, right = total, total
+	left.Width = total.Width / 2
+	right.Left = left.Right()
+	right.Width = total.Width - left.Width
 
-	v.screenRect.Width = v.defaultScreenRect.Width / 2
-	v.align = right
+	return left, right
 }
 
-func (v *Viewport) resetAlign() {
-	if v.align == center {
-		return
+// SetAlignment sets the part of the screen the viewport renders the map to. Conversions to and from screen space
+// follow the alignment, so picking works in either half.
+func (v *Viewport) SetAlignment(align ViewportAlignment) {
+	v.screenRect.Left = v.defaultScreenRect.Left
+	v.screenRect.Width = v.defaultScreenRect.Width
+
+	left, right := ComputeSplitViewports(v.defaultScreenRect)
+
+	switch align {
+	case AlignLeft:
+		v.screenRect.Left, v.screenRect.Width = right.Left, right.Width
+	case AlignRight:
+		v.screenRect.Left, v.screenRect.Width = left.Left, left.Width
+	default:
+		align = AlignCenter
 	}
 
-	v.screenRect.Width = v.defaultScreenRect.Width
-	v.screenRect.Left = v.defaultScreenRect.Left
-	v.align = center
+	v.align = align
+	v.offsetDirty = true
+
+	v.updateCameraView()
+}
+
+// Resize changes the size of the viewport, such as when the window is resized, keeping its alignment, camera and
+// translations. The camera stays centered on the same point of the world.
+func (v *Viewport) Resize(width, height int) {
+	v.place(v.defaultScreenRect.Left, v.defaultScreenRect.Top, width, height)
+}
+
+// place moves the viewport to the given part of the screen, keeping its alignment within it.
+func (v *Viewport) place(x, y, width, height int) {
+	v.defaultScreenRect = d2common.Rectangle{Left: x, Top: y, Width: width, Height: height}
+	v.screenRect.Top = y
+	v.screenRect.Height = height
+
+	v.SetAlignment(v.align)
+}
+
+// GetAlignment returns the part of the screen the viewport renders the map to.
+func (v *Viewport) GetAlignment() ViewportAlignment {
+	return v.align
+}
+
+// SetProjection sets how the viewport projects the world onto the screen. Every conversion between world and
+// orthogonal or screen space follows the projection.
+func (v *Viewport) SetProjection(mode ProjectionMode) {
+	v.projection = mode
+}
+
+// GetProjection returns how the viewport projects the world onto the screen.
+func (v *Viewport) GetProjection() ProjectionMode {
+	return v.projection
 }
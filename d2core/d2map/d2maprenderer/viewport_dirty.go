@@ -0,0 +1,39 @@
+package d2maprenderer
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+// MarkDirtyWorld marks the screen space bounds of the world space rectangle as needing to be drawn again, merging them
+// with the bounds already marked into the smallest rectangle enclosing both.
+func (v *Viewport) MarkDirtyWorld(rect d2common.Rectangle) {
+	bounds := v.WorldRectToScreenBounds(rect)
+
+	if !v.dirty {
+		v.dirtyRect, v.dirty = bounds, true
+		return
+	}
+
+	v.dirtyRect = unionRect(v.dirtyRect, bounds)
+}
+
+// ConsumeDirtyRect returns the screen space rectangle enclosing everything marked dirty since it was last consumed,
+// and false if nothing was, then clears it.
+func (v *Viewport) ConsumeDirtyRect() (d2common.Rectangle, bool) {
+	rect, dirty := v.dirtyRect, v.dirty
+	v.dirtyRect, v.dirty = d2common.Rectangle{}, false
+
+	return rect, dirty
+}
+
+// unionRect returns the smallest rectangle enclosing both rectangles.
+func unionRect(a, b d2common.Rectangle) d2common.Rectangle {
+	left, top := d2common.MinInt(a.Left, b.Left), d2common.MinInt(a.Top, b.Top)
+
+	return d2common.Rectangle{
+		Left:   left,
+		Top:    top,
+		Width:  d2common.MaxInt(a.Right(), b.Right()) - left,
+		Height: d2common.MaxInt(a.Bottom(), b.Bottom()) - top,
+	}
+}
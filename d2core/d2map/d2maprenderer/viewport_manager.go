@@ -0,0 +1,106 @@
+package d2maprenderer
+
+import (
+	"math"
+)
+
+// ViewportManager holds the named viewports drawn to one window, such as the halves of a split screen and a minimap,
+// and lays them out again when the window is resized.
+type ViewportManager struct {
+	width, height int // size of the window, in screen pixels
+	entries       []*managedViewport
+}
+
+// managedViewport is a viewport of the manager, with its edges as fractions of the size of the window.
+type managedViewport struct {
+	name                     string
+	viewport                 *Viewport
+	left, top, right, bottom float64
+}
+
+// NewViewportManager creates a manager of the viewports drawn to a window of the given size.
+func NewViewportManager(width, height int) *ViewportManager {
+	return &ViewportManager{width: width, height: height}
+}
+
+// Add adds a viewport under the name, replacing the viewport of the same name, if any, in the same place of the
+// order. The part of the window it covers when added is the part ResizeAll keeps it covering.
+func (m *ViewportManager) Add(name string, v *Viewport) {
+	rect := v.defaultScreenRect
+	entry := &managedViewport{
+		name:     name,
+		viewport: v,
+		left:     float64(rect.Left) / float64(m.width),
+		top:      float64(rect.Top) / float64(m.height),
+		right:    float64(rect.Right()) / float64(m.width),
+		bottom:   float64(rect.Bottom()) / float64(m.height),
+	}
+
+	if index := m.indexOf(name); index >= 0 {
+		m.entries[index] = entry
+		return
+	}
+
+	m.entries = append(m.entries, entry)
+}
+
+// Get returns the viewport added under the name, or nil if there is none.
+func (m *ViewportManager) Get(name string) *Viewport {
+	if index := m.indexOf(name); index >= 0 {
+		return m.entries[index].viewport
+	}
+
+	return nil
+}
+
+// Remove removes the viewport added under the name, if any.
+func (m *ViewportManager) Remove(name string) {
+	if index := m.indexOf(name); index >= 0 {
+		m.entries = append(m.entries[:index], m.entries[index+1:]...)
+	}
+}
+
+// Names returns the names of the viewports, in the order they were added, which is the order to render them in.
+func (m *ViewportManager) Names() []string {
+	names := make([]string, len(m.entries))
+
+	for index, entry := range m.entries {
+		names[index] = entry.name
+	}
+
+	return names
+}
+
+// Each calls the function with every viewport and its name, in the order they were added.
+func (m *ViewportManager) Each(fn func(name string, v *Viewport)) {
+	for _, entry := range m.entries {
+		fn(entry.name, entry.viewport)
+	}
+}
+
+// ResizeAll resizes every viewport to cover the same part of the window at its new size. Viewports sharing an edge
+// keep sharing it.
+func (m *ViewportManager) ResizeAll(w, h int) {
+	m.width, m.height = w, h
+
+	for _, entry := range m.entries {
+		left, top := scaleEdge(entry.left, w), scaleEdge(entry.top, h)
+		entry.viewport.place(left, top, scaleEdge(entry.right, w)-left, scaleEdge(entry.bottom, h)-top)
+	}
+}
+
+// indexOf returns the index of the viewport added under the name, or -1 if there is none.
+func (m *ViewportManager) indexOf(name string) int {
+	for index, entry := range m.entries {
+		if entry.name == name {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// scaleEdge returns the screen position of an edge at a fraction of the size of the window.
+func scaleEdge(fraction float64, size int) int {
+	return int(math.Round(fraction * float64(size)))
+}
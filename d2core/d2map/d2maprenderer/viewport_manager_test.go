@@ -0,0 +1,49 @@
+package d2maprenderer
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestViewportManager(t *testing.T) {
+	assert := testify.New(t)
+
+	manager := NewViewportManager(800, 600)
+	left := NewViewport(0, 0, 400, 600)
+	right := NewViewport(400, 0, 400, 600)
+	minimap := NewViewport(600, 0, 200, 150)
+
+	manager.Add("left", left)
+	manager.Add("right", right)
+	manager.Add("minimap", minimap)
+
+	assert.Equal([]string{"left", "right", "minimap"}, manager.Names())
+	assert.Same(right, manager.Get("right"))
+	assert.Nil(manager.Get("missing"))
+
+	manager.ResizeAll(1001, 700)
+
+	assert.Equal(d2common.Rectangle{Left: 0, Top: 0, Width: 501, Height: 700}, left.GetClipRect())
+	assert.Equal(d2common.Rectangle{Left: 501, Top: 0, Width: 500, Height: 700}, right.GetClipRect(),
+		"the halves still share their edge")
+	assert.Equal(d2common.Rectangle{Left: 751, Top: 0, Width: 250, Height: 175}, minimap.GetClipRect())
+
+	manager.ResizeAll(800, 600)
+	assert.Equal(d2common.Rectangle{Left: 600, Top: 0, Width: 200, Height: 150}, minimap.GetClipRect(),
+		"resizing back restores the layout")
+
+	replacement := NewViewport(0, 0, 800, 600)
+	manager.Add("left", replacement)
+	manager.Remove("right")
+
+	var visited []*Viewport
+
+	manager.Each(func(name string, v *Viewport) {
+		visited = append(visited, v)
+	})
+
+	assert.Equal([]*Viewport{replacement, minimap}, visited, "a replaced viewport keeps its place in the order")
+}
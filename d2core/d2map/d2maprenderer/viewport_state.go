@@ -0,0 +1,125 @@
+package d2maprenderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+// viewportState is the state of a viewport and its camera, as saved by MarshalState. JSON round trips the floats
+// exactly.
+type viewportState struct {
+	DefaultScreenRect d2common.Rectangle `json:"defaultScreenRect"`
+	ScreenRect        d2common.Rectangle `json:"screenRect"`
+	Align             ViewportAlignment  `json:"align"`
+	TransStack        [][2]float64       `json:"transStack"`
+	TransCurrent      [2]float64         `json:"transCurrent"`
+	Camera            *cameraState       `json:"camera,omitempty"`
+	Projection        ProjectionMode     `json:"projection"`
+	Scale             float64            `json:"scale"`
+	HalfTileWidth     float64            `json:"halfTileWidth,omitempty"`
+	HalfTileHeight    float64            `json:"halfTileHeight,omitempty"`
+}
+
+// cameraState is the position and rotation of a camera, as saved with its viewport.
+type cameraState struct {
+	X      float64             `json:"x"`
+	Y      float64             `json:"y"`
+	Zoom   float64             `json:"zoom"`
+	Bounds *d2common.Rectangle `json:"bounds,omitempty"`
+
+	Rotation int `json:"rotation"`
+}
+
+// MarshalState encodes the screen area, alignment, translations, projection, scale factor and tile size of the
+// viewport, along with the position, zoom, bounds and rotation of its camera, so the view can be restored with
+// UnmarshalState. Camera pans, shakes and the target it follows aren't saved.
+func (v *Viewport) MarshalState() ([]byte, error) {
+	state := viewportState{
+		DefaultScreenRect: v.defaultScreenRect,
+		ScreenRect:        v.screenRect,
+		Align:             v.align,
+		TransStack:        make([][2]float64, len(v.transStack)),
+		TransCurrent:      [2]float64{v.transCurrent.x, v.transCurrent.y},
+		Projection:        v.projection,
+		Scale:             v.scale,
+		HalfTileWidth:     v.halfTileWidth,
+		HalfTileHeight:    v.halfTileHeight,
+	}
+
+	for index, trans := range v.transStack {
+		state.TransStack[index] = [2]float64{trans.x, trans.y}
+	}
+
+	if v.camera != nil {
+		state.Camera = &cameraState{
+			X:        v.camera.x,
+			Y:        v.camera.y,
+			Zoom:     v.camera.zoom,
+			Bounds:   v.camera.bounds,
+			Rotation: v.camera.rotation,
+		}
+	}
+
+	return json.Marshal(state)
+}
+
+// UnmarshalState restores the view saved by MarshalState. If the viewport has no camera yet, the saved camera
+// position is kept until one is set with SetCamera, so a restored viewport can be linked to its camera afterwards.
+func (v *Viewport) UnmarshalState(data []byte) error {
+	var state viewportState
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	if state.Align < AlignCenter || state.Align > AlignRight {
+		return fmt.Errorf("invalid viewport alignment %d", state.Align)
+	}
+
+	if state.Projection < ProjectionIso || state.Projection > ProjectionOrtho {
+		return fmt.Errorf("invalid viewport projection %d", state.Projection)
+	}
+
+	v.defaultScreenRect = state.DefaultScreenRect
+	v.screenRect = state.ScreenRect
+	v.align = state.Align
+	v.projection = state.Projection
+	v.scale = math.Max(0, state.Scale)
+	v.offsetDirty = true
+
+	// States saved before the tile size could be set don't have one, the viewport's own is kept
+	if state.HalfTileWidth > 0 && state.HalfTileHeight > 0 {
+		v.halfTileWidth, v.halfTileHeight = state.HalfTileWidth, state.HalfTileHeight
+	}
+	v.transCurrent = worldTrans{x: state.TransCurrent[0], y: state.TransCurrent[1]}
+	v.transStack = make([]worldTrans, len(state.TransStack))
+
+	for index, trans := range state.TransStack {
+		v.transStack[index] = worldTrans{x: trans[0], y: trans[1]}
+	}
+
+	v.savedCamera = state.Camera
+
+	if v.camera != nil {
+		v.SetCamera(v.camera)
+	}
+
+	return nil
+}
+
+// restore moves the camera to the saved position, zoom, bounds and rotation.
+func (s *cameraState) restore(camera *Camera) {
+	camera.x, camera.y = s.X, s.Y
+	camera.zoom = s.Zoom
+	camera.SetRotation(s.Rotation)
+	camera.pan = nil
+	camera.bounds = nil
+
+	if s.Bounds != nil {
+		bounds := *s.Bounds
+		camera.bounds = &bounds
+	}
+}
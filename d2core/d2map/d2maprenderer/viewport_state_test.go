@@ -0,0 +1,60 @@
+package d2maprenderer
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestViewportStateRoundTrip(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.MoveTo(123.456789, -98.7654321, 0, CameraEasingLinear)
+	camera.SetZoom(1.7)
+	camera.SetBounds(d2common.Rectangle{Left: -5, Top: -5, Width: 200, Height: 200})
+	camera.SetRotation(3)
+
+	viewport := NewViewportWithTileSize(10, 20, 800, 600, 64, 32)
+	viewport.SetCamera(camera)
+	viewport.SetAlignment(AlignRight)
+	viewport.SetProjection(ProjectionOrtho)
+	viewport.SetScaleFactor(1.5)
+	viewport.PushTranslationWorld(1.1, 2.2)
+	viewport.PushTranslationWorld(-0.3, 0.7)
+
+	data, err := viewport.MarshalState()
+	assert.NoError(err)
+
+	restored := NewViewport(0, 0, 1, 1)
+	assert.NoError(restored.UnmarshalState(data))
+
+	// the camera is linked after the state is restored
+	restored.SetCamera(&Camera{})
+
+	for _, point := range batchPoints(50) {
+		screenX, screenY := viewport.WorldToScreenF(point.X, point.Y)
+		restoredX, restoredY := restored.WorldToScreenF(point.X, point.Y)
+
+		assert.Equal(screenX, restoredX, "point %v", point)
+		assert.Equal(screenY, restoredY, "point %v", point)
+	}
+
+	assert.Equal(AlignRight, restored.GetAlignment())
+	assert.Equal(ProjectionOrtho, restored.GetProjection())
+	assert.Equal(1.5, restored.GetScaleFactor())
+	assert.Equal(3, restored.camera.GetRotation())
+	assert.Equal(32.0, restored.halfTileWidth)
+	assert.Equal(16.0, restored.halfTileHeight)
+	assert.Equal(viewport.GetTransStackDepth(), restored.GetTransStackDepth())
+
+	again, err := restored.MarshalState()
+	assert.NoError(err)
+	assert.Equal(string(data), string(again), "the state round trips exactly")
+
+	assert.Error(restored.UnmarshalState([]byte(`{"align":7}`)))
+	assert.Error(restored.UnmarshalState([]byte(`{"projection":2}`)))
+	assert.Error(restored.UnmarshalState([]byte(`not json`)))
+}
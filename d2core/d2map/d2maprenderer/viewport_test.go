@@ -0,0 +1,847 @@
+package d2maprenderer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestViewportAlignmentPicking(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.MoveTo(160, 80, 0, CameraEasingLinear)
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+
+	// the camera's position is at the center of the viewport
+	worldX, worldY := viewport.ScreenToWorld(400, 300)
+	assert.InDelta(2.0, worldX, 1e-9)
+	assert.InDelta(0.0, worldY, 1e-9)
+
+	viewport.SetAlignment(AlignLeft)
+	assert.Equal(AlignLeft, viewport.GetAlignment())
+
+	// the map is rendered to the right half of the screen, so its center moves to 3/4 of the screen's width
+	worldX, worldY = viewport.ScreenToWorld(600, 300)
+	assert.InDelta(2.0, worldX, 1e-9)
+	assert.InDelta(0.0, worldY, 1e-9)
+
+	worldX, worldY = viewport.ScreenToWorld(680, 340)
+	assert.InDelta(3.0, worldX, 1e-9, "one tile right of the camera")
+	assert.InDelta(0.0, worldY, 1e-9)
+
+	screenX, screenY := viewport.WorldToScreen(3, 0)
+	assert.Equal(680, screenX)
+	assert.Equal(340, screenY)
+
+	viewport.SetAlignment(AlignRight)
+	worldX, _ = viewport.ScreenToWorld(200, 300)
+	assert.InDelta(2.0, worldX, 1e-9, "switching sides moves the map to the left half")
+
+	viewport.SetAlignment(AlignCenter)
+	assert.Equal(AlignCenter, viewport.GetAlignment())
+	worldX, _ = viewport.ScreenToWorld(400, 300)
+	assert.InDelta(2.0, worldX, 1e-9)
+}
+
+func TestGetClipRect(t *testing.T) {
+	assert := testify.New(t)
+
+	viewport := NewViewport(10, 20, 800, 600)
+
+	clipRects := map[ViewportAlignment]d2common.Rectangle{
+		AlignCenter: {Left: 10, Top: 20, Width: 800, Height: 600},
+		AlignLeft:   {Left: 410, Top: 20, Width: 400, Height: 600}, // renders to the right half
+		AlignRight:  {Left: 10, Top: 20, Width: 400, Height: 600},
+	}
+
+	for _, align := range []ViewportAlignment{AlignLeft, AlignCenter, AlignRight, AlignLeft} {
+		viewport.SetAlignment(align)
+		assert.Equal(clipRects[align], viewport.GetClipRect(), "alignment %d", align)
+	}
+}
+
+func TestViewportResize(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.MoveTo(160, 80, 0, CameraEasingLinear)
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+	viewport.SetAlignment(AlignLeft)
+	viewport.PushTranslationOrtho(10, 20)
+
+	screenX, screenY := viewport.WorldToScreen(2, 0)
+	assert.Equal(600, screenX, "the camera is at 3/4 of the screen's width")
+	assert.Equal(300, screenY)
+
+	viewport.Resize(1280, 720)
+
+	screenX, screenY = viewport.WorldToScreen(2, 0)
+	assert.Equal(960, screenX, "the camera stays at 3/4 of the screen's width")
+	assert.Equal(360, screenY)
+
+	assert.Equal(AlignLeft, viewport.GetAlignment())
+	assert.Equal(d2common.Rectangle{Left: 640, Top: 0, Width: 640, Height: 720}, viewport.GetClipRect())
+	assert.Equal(1, viewport.GetTransStackDepth(), "the translations are kept")
+	assert.Equal(640, camera.viewWidth, "the camera's view is resized")
+	assert.Equal(720, camera.viewHeight)
+
+	viewport.SetAlignment(AlignCenter)
+
+	screenX, screenY = viewport.WorldToScreen(2, 0)
+	assert.Equal(640, screenX)
+	assert.Equal(360, screenY)
+}
+
+func TestViewportFrameCache(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.MoveTo(123.25, -45.5, 0, CameraEasingLinear)
+	camera.SetZoom(1.5)
+
+	viewport := NewViewport(10, 20, 800, 600)
+	viewport.SetCamera(camera)
+
+	points := batchPoints(3000)
+	uncached := make([]d2common.Point, len(points))
+
+	for index, point := range points {
+		uncached[index].X, uncached[index].Y = viewport.WorldToScreen(point.X, point.Y)
+	}
+
+	viewport.BeginFrame()
+
+	for index, point := range points {
+		screenX, screenY := viewport.WorldToScreen(point.X, point.Y)
+		assert.Equal(uncached[index], d2common.Point{X: screenX, Y: screenY}, "point %d", index)
+	}
+
+	// the camera moving mid-frame is seen by the next conversions
+	camera.MoveBy(100, 0)
+	viewport.cameraMoved()
+
+	screenX, _ := viewport.WorldToScreen(points[0].X, points[0].Y)
+	assert.Equal(uncached[0].X-100, screenX)
+
+	viewport.SetAlignment(AlignLeft)
+	screenX, _ = viewport.WorldToScreen(points[0].X, points[0].Y)
+	viewport.EndFrame()
+
+	uncachedX, _ := viewport.WorldToScreen(points[0].X, points[0].Y)
+	assert.Equal(uncachedX, screenX, "changing the alignment mid-frame is seen by the next conversions")
+}
+
+func TestScreenToSubCell(t *testing.T) {
+	assert := testify.New(t)
+
+	// the camera at the world origin, at the center of the screen
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(&Camera{})
+
+	tests := []struct {
+		worldX, worldY           float64
+		tileX, tileY, subX, subY int
+	}{
+		{0.1, 0.1, 0, 0, 0, 0},
+		{0.5, 0.5, 0, 0, 2, 2},
+		{0.9, 0.9, 0, 0, 4, 4},
+		{1.1, 2.3, 1, 2, 0, 1},
+		{-0.1, 0.1, -1, 0, 4, 0},
+		{-0.9, -0.9, -1, -1, 0, 0},
+		{-1.1, -2.5, -2, -3, 4, 2},
+	}
+
+	for _, test := range tests {
+		// the centers of sub cells land on whole screen pixels, away from the sub cell boundaries
+		screenX, screenY := viewport.WorldToScreen(test.worldX, test.worldY)
+		tileX, tileY, subX, subY := viewport.ScreenToSubCell(screenX, screenY)
+
+		assert.Equal([]int{test.tileX, test.tileY, test.subX, test.subY}, []int{tileX, tileY, subX, subY},
+			"world %.1f, %.1f", test.worldX, test.worldY)
+	}
+}
+
+func TestScreenToTile(t *testing.T) {
+	assert := testify.New(t)
+
+	// the camera at the world origin, at the center of the screen
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(&Camera{})
+
+	tests := []struct {
+		name           string
+		worldX, worldY float64
+		tileX, tileY   int
+	}{
+		{"positive x and y", 0.3, 0.7, 0, 0},
+		{"negative x", -0.3, 2.7, -1, 2},
+		{"negative x and y", -0.3, -0.7, -1, -1},
+		{"negative y", 2.7, -0.3, 2, -1},
+		{"past a whole negative tile", -1.3, -2.7, -2, -3},
+	}
+
+	for _, test := range tests {
+		tileX, tileY := viewport.ScreenToTile(viewport.WorldToScreen(test.worldX, test.worldY))
+
+		assert.Equal([]int{test.tileX, test.tileY}, []int{tileX, tileY}, test.name)
+	}
+}
+
+func TestScreenToGround(t *testing.T) {
+	assert := testify.New(t)
+
+	// the camera at the world origin, at the center of the screen
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(&Camera{})
+
+	tests := []struct {
+		name           string
+		worldX, worldY float64
+		tileX, tileY   int
+	}{
+		{"positive x and y", 0.3, 0.7, 0, 0},
+		{"negative x", -0.3, 2.7, -1, 2},
+		{"negative x and y", -0.3, -0.7, -1, -1},
+		{"negative y", 2.7, -0.3, 2, -1},
+	}
+
+	for _, test := range tests {
+		screenX, screenY := viewport.WorldToScreen(test.worldX, test.worldY)
+		world, tileX, tileY := viewport.ScreenToGround(screenX, screenY)
+
+		worldX, worldY := viewport.ScreenToWorld(screenX, screenY)
+		assert.Equal(d2common.Vector2{X: worldX, Y: worldY}, world, test.name)
+		assert.Equal([]int{test.tileX, test.tileY}, []int{tileX, tileY}, test.name)
+	}
+}
+
+func TestViewportOrthoProjection(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.MoveTo(160, 80, 0, CameraEasingLinear)
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+	assert.Equal(ProjectionIso, viewport.GetProjection())
+
+	viewport.SetProjection(ProjectionOrtho)
+	assert.Equal(ProjectionOrtho, viewport.GetProjection())
+
+	// tiles are 80 pixel squares, without rotation
+	orthoX, orthoY := viewport.WorldToOrtho(2, 1)
+	assert.InDelta(160.0, orthoX, 1e-9)
+	assert.InDelta(80.0, orthoY, 1e-9)
+
+	screenX, screenY := viewport.WorldToScreen(3, 1)
+	assert.Equal(480, screenX, "one tile right of the camera")
+	assert.Equal(300, screenY)
+
+	for _, world := range []d2common.Vector2{{X: 2, Y: 1}, {X: -3.5, Y: 7.25}, {X: 10.2, Y: -4.6}} {
+		worldX, worldY := viewport.ScreenToWorld(viewport.WorldToScreen(world.X, world.Y))
+		assert.InDelta(world.X, worldX, 1.0/80, "round trip of %v", world)
+		assert.InDelta(world.Y, worldY, 1.0/80, "round trip of %v", world)
+
+		worldX, worldY = viewport.OrthoToWorld(viewport.WorldToOrtho(world.X, world.Y))
+		assert.InDelta(world.X, worldX, 1e-9)
+		assert.InDelta(world.Y, worldY, 1e-9)
+	}
+
+	points := []d2common.Vector2{{X: 3, Y: 1}}
+	out := make([]d2common.Vector2Int, 1)
+	viewport.WorldToScreenBatch(points, out)
+	assert.Equal(d2common.Vector2Int{X: 480, Y: 300}, out[0])
+
+	// the screen shows 10 by 7.5 tiles around the camera
+	assert.True(viewport.IsTileVisible(2, 1))
+	assert.True(viewport.IsTileVisible(-1, 1))
+	assert.False(viewport.IsTileVisible(2, 10))
+	assert.True(viewport.IsTileRectVisible(d2common.Rectangle{Left: 6, Top: 0, Width: 2, Height: 2}))
+	assert.False(viewport.IsTileRectVisible(d2common.Rectangle{Left: 8, Top: 0, Width: 2, Height: 2}))
+
+	viewport.SetProjection(ProjectionIso)
+	worldX, worldY := viewport.ScreenToWorld(400, 300)
+	assert.InDelta(2.0, worldX, 1e-9)
+	assert.InDelta(0.0, worldY, 1e-9)
+}
+
+func TestWorldToScreenBatch(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.MoveTo(123.4, -56.7, 0, CameraEasingLinear)
+	camera.SetZoom(1.3)
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+	viewport.SetAlignment(AlignLeft)
+
+	points := batchPoints(100)
+	out := make([]d2common.Vector2Int, len(points))
+
+	assert.Equal(len(points), viewport.WorldToScreenBatch(points, out))
+
+	for index, point := range points {
+		assert.Equal(viewport.WorldToScreenV(point), out[index], "point %v", point)
+	}
+
+	assert.Equal(10, viewport.WorldToScreenBatch(points, out[:10]), "only as many points as there is room for")
+}
+
+func TestViewportVectorConversions(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.MoveTo(123.4, -56.7, 0, CameraEasingLinear)
+	camera.SetZoom(1.3)
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+
+	world := d2common.Vector2{X: 12.3, Y: 4.5}
+
+	screenX, screenY := viewport.WorldToScreen(world.X, world.Y)
+	assert.Equal(d2common.Vector2Int{X: screenX, Y: screenY}, viewport.WorldToScreenV(world))
+
+	orthoX, orthoY := viewport.WorldToOrtho(world.X, world.Y)
+	assert.Equal(d2common.Vector2{X: orthoX, Y: orthoY}, viewport.WorldToOrthoV(world))
+
+	back := viewport.OrthoToWorldV(viewport.WorldToOrthoV(world))
+	assert.InDelta(world.X, back.X, 1e-9)
+	assert.InDelta(world.Y, back.Y, 1e-9)
+
+	worldX, worldY := viewport.ScreenToWorld(screenX, screenY)
+	screen := d2common.Vector2Int{X: screenX, Y: screenY}
+	assert.Equal(d2common.Vector2{X: worldX, Y: worldY}, viewport.ScreenToWorldV(screen))
+}
+
+func BenchmarkWorldToScreen(b *testing.B) {
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(&Camera{})
+
+	points := batchPoints(1000)
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		for _, point := range points {
+			viewport.WorldToScreen(point.X, point.Y)
+		}
+	}
+}
+
+func BenchmarkWorldToScreenFrame(b *testing.B) {
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(&Camera{})
+
+	points := batchPoints(1000)
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		viewport.BeginFrame()
+
+		for _, point := range points {
+			viewport.WorldToScreen(point.X, point.Y)
+		}
+
+		viewport.EndFrame()
+	}
+}
+
+func BenchmarkWorldToScreenBatch(b *testing.B) {
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(&Camera{})
+
+	points := batchPoints(1000)
+	out := make([]d2common.Vector2Int, len(points))
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		viewport.WorldToScreenBatch(points, out)
+	}
+}
+
+// batchPoints returns count world positions spread over a map.
+func batchPoints(count int) []d2common.Vector2 {
+	points := make([]d2common.Vector2, count)
+
+	for index := range points {
+		points[index] = d2common.Vector2{X: float64(index%37) * 0.37, Y: float64(index%23) * 1.1}
+	}
+
+	return points
+}
+
+func TestViewportOddSizeCenter(t *testing.T) {
+	assert := testify.New(t)
+
+	viewport := NewViewport(0, 0, 1281, 721)
+	viewport.SetCamera(&Camera{})
+
+	screenX, screenY := viewport.OrthoToScreenF(0, 0)
+	assert.Equal(640.5, screenX, "the camera is at the true center of the viewport")
+	assert.Equal(360.5, screenY)
+
+	intX, intY := viewport.OrthoToScreen(0, 0)
+	assert.Equal(640, intX)
+	assert.Equal(360, intY)
+
+	orthoX, orthoY := viewport.ScreenToOrtho(640, 360)
+	assert.Equal(-0.5, orthoX)
+	assert.Equal(-0.5, orthoY)
+}
+
+func TestGetVisibleWorldBounds(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+
+	// the corners of the screen are 400 and 300 pixels from the camera, or 2.5 and 3.75 tiles along each axis
+	assert.Equal(d2common.Rectangle{Left: -7, Top: -7, Width: 14, Height: 14}, viewport.GetVisibleWorldBounds())
+
+	camera.MoveTo(160, 80, 0, CameraEasingLinear)
+	assert.Equal(d2common.Rectangle{Left: -5, Top: -7, Width: 14, Height: 14}, viewport.GetVisibleWorldBounds(),
+		"the bounds follow the camera")
+
+	camera.SetZoom(2)
+	assert.Equal(d2common.Rectangle{Left: -3, Top: -4, Width: 8, Height: 8}, viewport.GetVisibleWorldBounds(),
+		"zooming in shows less of the map")
+
+	camera.SetZoom(1)
+	viewport.SetAlignment(AlignLeft)
+	assert.Equal(d2common.Rectangle{Left: -3, Top: -5, Width: 10, Height: 10}, viewport.GetVisibleWorldBounds(),
+		"half of the screen shows less of the map")
+}
+
+func TestGetVisibleRadius(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.MoveTo(800, 400, 0, CameraEasingLinear)
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+
+	// the corners of the screen are 6.25 tiles along one axis and 1.25 along the other from the center
+	assert.InDelta(math.Hypot(6.25, 1.25)+visibleTileMargin, viewport.GetVisibleRadius(), 1e-9)
+
+	camera.SetZoom(0.5)
+	assert.InDelta(math.Hypot(12.5, 2.5)+visibleTileMargin, viewport.GetVisibleRadius(), 1e-9,
+		"zooming out shows more of the map")
+}
+
+func TestTileVisibilityMargin(t *testing.T) {
+	assert := testify.New(t)
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(&Camera{})
+
+	// the tile is just below the bottom of the screen
+	assert.False(viewport.IsTileVisible(6, 6))
+	assert.False(viewport.IsTileVisibleWithMargin(6, 6, 0))
+	assert.True(viewport.IsTileVisibleWithMargin(6, 6, 1))
+
+	rect := d2common.Rectangle{Left: 6, Top: 6, Width: 1, Height: 1}
+	assert.False(viewport.IsTileRectVisible(rect))
+	assert.False(viewport.IsTileRectVisibleWithMargin(rect, 2))
+	assert.True(viewport.IsTileRectVisibleWithMargin(rect, 3), "three tiles of margin reach the rect")
+
+	viewport.SetProjection(ProjectionOrtho)
+	assert.False(viewport.IsTileVisible(2, 8))
+	assert.False(viewport.IsTileVisibleWithMargin(2, 8, 1))
+	assert.True(viewport.IsTileVisibleWithMargin(2, 8, 2), "the margin also grows the tile along y")
+}
+
+func TestTryPopTranslation(t *testing.T) {
+	assert := testify.New(t)
+
+	viewport := NewViewport(0, 0, 800, 600)
+
+	viewport.PushTranslationOrtho(10, 20)
+	viewport.PushTranslationWorld(1, 0)
+	assert.Equal(2, viewport.GetTransStackDepth())
+
+	x, y := viewport.GetTranslationOrtho()
+	assert.Equal(90.0, x)
+	assert.Equal(60.0, y)
+
+	assert.NoError(viewport.TryPopTranslation())
+	assert.NoError(viewport.TryPopTranslation())
+	assert.Equal(0, viewport.GetTransStackDepth())
+
+	x, y = viewport.GetTranslationOrtho()
+	assert.Equal(0.0, x)
+	assert.Equal(0.0, y)
+
+	assert.Error(viewport.TryPopTranslation(), "more pops than pushes")
+	assert.Panics(viewport.PopTranslation)
+}
+
+func TestViewportClone(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+	viewport.SetAlignment(AlignLeft)
+	viewport.PushTranslationOrtho(10, 20)
+
+	clone := viewport.Clone()
+	assert.Equal(viewport.GetTransStackDepth(), clone.GetTransStackDepth())
+	assert.Equal(viewport.GetAlignment(), clone.GetAlignment())
+	assert.Same(camera, clone.camera, "the clone shares the camera")
+
+	clone.PushTranslationWorld(1, 0)
+	clone.PushTranslationOrtho(5, 5)
+	assert.Equal(3, clone.GetTransStackDepth())
+	assert.Equal(1, viewport.GetTransStackDepth(), "the original keeps its translation stack")
+
+	x, y := viewport.GetTranslationOrtho()
+	assert.Equal(10.0, x)
+	assert.Equal(20.0, y)
+
+	clone.PopTranslation()
+	clone.PopTranslation()
+	clone.PopTranslation()
+	viewport.PushTranslationOrtho(1, 1)
+	assert.Equal(0, clone.GetTransStackDepth(), "the clone keeps its translation stack")
+}
+
+func TestViewportScaleFactor(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	viewport := NewViewport(0, 0, 1600, 1200)
+	viewport.SetCamera(camera)
+	camera.MoveTo(160, 80, 0, CameraEasingLinear)
+
+	screenX, screenY := viewport.WorldToScreen(3, 1)
+
+	viewport.SetScaleFactor(1)
+	x, y := viewport.WorldToScreen(3, 1)
+	assert.Equal([2]int{screenX, screenY}, [2]int{x, y}, "a scale of 1 changes nothing")
+	assert.True(viewport.IsTileVisible(8, 8))
+
+	viewport.SetScaleFactor(2)
+	assert.True(viewport.IsTileVisible(3, 1))
+	assert.False(viewport.IsTileVisible(8, 8), "the tiles in the framebuffer at a scale of 1 are off screen at 2")
+
+	x, y = viewport.WorldToScreen(3, 1)
+	assert.Equal(800+(160-160)*2, x, "the screen position is twice as far from the center")
+	assert.Equal(600+(160-80)*2, y)
+
+	for _, world := range [][2]float64{{0, 0}, {3, 1}, {-2, 5}, {2.5, 0.25}} {
+		x, y = viewport.WorldToScreen(world[0], world[1])
+		worldX, worldY := viewport.ScreenToWorld(x, y)
+		assert.Equal(world, [2]float64{worldX, worldY}, "ScreenToWorld undoes WorldToScreen")
+	}
+
+	// the framebuffer is 1600x1200 screen pixels, which show 800x600 orthogonal pixels around the camera
+	assert.True(viewport.IsOrthoRectVisible(160+390, 80, 160+390, 80))
+	assert.False(viewport.IsOrthoRectVisible(160+410, 80, 160+410, 80))
+
+	viewport.SetScaleFactor(0)
+	assert.Equal(1.0, viewport.GetScaleFactor())
+}
+
+func TestViewportRotation(t *testing.T) {
+	assert := testify.New(t)
+
+	for quarter := 0; quarter < 4; quarter++ {
+		camera := &Camera{}
+		camera.SetRotation(quarter)
+
+		viewport := NewViewport(0, 0, 800, 600)
+		viewport.SetCamera(camera)
+
+		centerX, centerY := viewport.WorldToOrtho(20, 10)
+		camera.MoveTo(centerX, centerY, 0, CameraEasingLinear)
+
+		for _, world := range [][2]float64{{0, 0}, {3, 1}, {-2, 5}, {20.5, 10.25}} {
+			orthoX, orthoY := viewport.WorldToOrtho(world[0], world[1])
+			worldX, worldY := viewport.OrthoToWorld(orthoX, orthoY)
+			assert.Equal(world, [2]float64{worldX, worldY}, "rotation %d, OrthoToWorld undoes WorldToOrtho", quarter)
+
+			screenX, screenY := viewport.WorldToScreen(world[0], world[1])
+			worldX, worldY = viewport.ScreenToWorld(screenX, screenY)
+			assert.Equal(world, [2]float64{worldX, worldY}, "rotation %d, ScreenToWorld undoes WorldToScreen", quarter)
+		}
+
+		// every tile whose center is on screen is within the visible bounds and isn't culled
+		bounds := viewport.GetVisibleWorldBounds()
+		minX, minY, maxX, maxY := viewport.GetVisibleTileRange()
+
+		for tileY := -10; tileY < 30; tileY++ {
+			for tileX := 0; tileX < 40; tileX++ {
+				screenX, screenY := viewport.WorldToScreen(float64(tileX)+0.5, float64(tileY)+0.5)
+				if screenX < 0 || screenY < 0 || screenX >= 800 || screenY >= 600 {
+					continue
+				}
+
+				assert.True(bounds.Left <= tileX && tileX < bounds.Right() && bounds.Top <= tileY &&
+					tileY < bounds.Bottom(), "rotation %d, tile %d,%d is within the bounds", quarter, tileX, tileY)
+				assert.True(minX <= tileX && tileX <= maxX && minY <= tileY && tileY <= maxY)
+				assert.True(viewport.IsTileVisible(float64(tileX), float64(tileY)), "rotation %d, tile %d,%d is visible",
+					quarter, tileX, tileY)
+			}
+		}
+
+		assert.False(viewport.IsTileVisible(40, 10), "rotation %d, far tiles are culled", quarter)
+	}
+}
+
+func TestGetVisibleTileRange(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.MoveTo(1234.5, 567.25, 0, CameraEasingLinear)
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+
+	for _, zoom := range []float64{0.5, 1, 2} {
+		camera.SetZoom(zoom)
+
+		minX, minY, maxX, maxY := viewport.GetVisibleTileRange()
+
+		for tileY := minY - 20; tileY <= maxY+20; tileY++ {
+			for tileX := minX - 20; tileX <= maxX+20; tileX++ {
+				if !viewport.IsTileVisible(float64(tileX), float64(tileY)) {
+					continue
+				}
+
+				inRange := tileX >= minX && tileX <= maxX && tileY >= minY && tileY <= maxY
+				assert.True(inRange, "visible tile %d,%d at zoom %v is in the range", tileX, tileY, zoom)
+			}
+		}
+	}
+}
+
+func TestWorldRectToScreenBounds(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+
+	// the leftmost and rightmost corners of the diamond are the bottom left and top right corners of the rectangle
+	rect := d2common.Rectangle{Left: 1, Top: 2, Width: 3, Height: 1}
+	assert.Equal(d2common.Rectangle{Left: 240, Top: 420, Width: 320, Height: 160}, viewport.WorldRectToScreenBounds(rect))
+
+	camera.MoveTo(0.5, 0.25, 0, CameraEasingLinear)
+	assert.Equal(d2common.Rectangle{Left: 239, Top: 419, Width: 321, Height: 161}, viewport.WorldRectToScreenBounds(rect),
+		"the bounds are rounded outward")
+}
+
+func TestGridLines(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+
+	// 2x2 tiles have 3 lines along each axis, of 2 edges each
+	lines := viewport.GridLines(0, 0, 1, 1)
+	assert.Len(lines, 12)
+
+	corner := func(x, y int) d2common.Vector2Int {
+		screenX, screenY := viewport.WorldToScreen(float64(x), float64(y))
+		return d2common.Vector2Int{X: screenX, Y: screenY}
+	}
+
+	assert.Contains(lines, [2]d2common.Vector2Int{corner(0, 0), corner(1, 0)})
+	assert.Contains(lines, [2]d2common.Vector2Int{corner(2, 1), corner(2, 2)}, "the right edge of the range")
+	assert.Contains(lines, [2]d2common.Vector2Int{corner(1, 2), corner(2, 2)}, "the bottom edge of the range")
+
+	assert.Empty(viewport.GridLines(100, 100, 102, 102), "tiles off screen have no lines")
+
+	// the first tile is off the top of the screen once the camera moves down, but not the tiles below it
+	camera.MoveTo(0, 390, 0, CameraEasingLinear)
+
+	lines = viewport.GridLines(0, 0, 1, 1)
+	assert.NotContains(lines, [2]d2common.Vector2Int{corner(0, 0), corner(1, 0)})
+	assert.Contains(lines, [2]d2common.Vector2Int{corner(0, 1), corner(1, 1)}, "the top edge of the tile below it")
+	assert.Len(lines, 10)
+}
+
+func TestTileHighlightPoints(t *testing.T) {
+	assert := testify.New(t)
+
+	camera := &Camera{}
+	camera.MoveTo(123, -45, 0, CameraEasingLinear)
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(camera)
+
+	for _, alignment := range []ViewportAlignment{AlignCenter, AlignLeft, AlignRight} {
+		viewport.SetAlignment(alignment)
+
+		points := viewport.TileHighlightPoints(3, -2)
+		top, right, bottom, left := points[0], points[1], points[2], points[3]
+
+		// the top and bottom corners are on a vertical line, the left and right ones on a horizontal one
+		assert.Equal(top.X, bottom.X)
+		assert.Equal(left.Y, right.Y)
+		assert.Equal(160, right.X-left.X)
+		assert.Equal(80, bottom.Y-top.Y)
+
+		centerX, centerY := viewport.WorldToScreen(3.5, -1.5)
+		assert.Equal(centerX, top.X, "the center of the diamond is the center of the tile")
+		assert.Equal(centerY, left.Y)
+	}
+}
+
+func TestViewportDirtyRect(t *testing.T) {
+	assert := testify.New(t)
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(&Camera{})
+
+	_, dirty := viewport.ConsumeDirtyRect()
+	assert.False(dirty, "nothing is dirty until marked")
+
+	first := d2common.Rectangle{Left: 0, Top: 0, Width: 1, Height: 1}
+	second := d2common.Rectangle{Left: 3, Top: 2, Width: 2, Height: 1}
+	firstBounds := viewport.WorldRectToScreenBounds(first)
+	secondBounds := viewport.WorldRectToScreenBounds(second)
+
+	viewport.MarkDirtyWorld(first)
+	rect, dirty := viewport.ConsumeDirtyRect()
+	assert.True(dirty)
+	assert.Equal(firstBounds, rect, "a single mark is the screen bounds of the world rect")
+
+	_, dirty = viewport.ConsumeDirtyRect()
+	assert.False(dirty, "consuming clears the dirty rect")
+
+	viewport.MarkDirtyWorld(second)
+	viewport.MarkDirtyWorld(first)
+	viewport.MarkDirtyWorld(first)
+	rect, _ = viewport.ConsumeDirtyRect()
+
+	left := d2common.MinInt(firstBounds.Left, secondBounds.Left)
+	top := d2common.MinInt(firstBounds.Top, secondBounds.Top)
+	assert.Equal(d2common.Rectangle{
+		Left:   left,
+		Top:    top,
+		Width:  d2common.MaxInt(firstBounds.Right(), secondBounds.Right()) - left,
+		Height: d2common.MaxInt(firstBounds.Bottom(), secondBounds.Bottom()) - top,
+	}, rect, "marks are merged into the smallest enclosing rect")
+}
+
+func TestComputeSplitViewports(t *testing.T) {
+	assert := testify.New(t)
+
+	for _, width := range []int{800, 801} {
+		total := d2common.Rectangle{Left: 10, Top: 20, Width: width, Height: 600}
+		left, right := ComputeSplitViewports(total)
+
+		assert.Equal(total.Left, left.Left, "width %d", width)
+		assert.Equal(left.Right(), right.Left, "the halves are adjacent, width %d", width)
+		assert.Equal(total.Right(), right.Right(), "the halves cover the whole width %d", width)
+		assert.Equal(total.Width, left.Width+right.Width, "width %d", width)
+		assert.LessOrEqual(right.Width-left.Width, 1, "width %d", width)
+		assert.Equal([]int{total.Top, total.Height}, []int{left.Top, left.Height})
+		assert.Equal([]int{total.Top, total.Height}, []int{right.Top, right.Height})
+
+		// the viewport rendering to the left half is aligned right of the panel on the right
+		leftHalf, rightHalf := NewViewport(10, 20, width, 600), NewViewport(10, 20, width, 600)
+		leftHalf.SetAlignment(AlignRight)
+		rightHalf.SetAlignment(AlignLeft)
+		assert.Equal(left, leftHalf.GetFillRect(), "width %d", width)
+		assert.Equal(right, rightHalf.GetFillRect(), "width %d", width)
+	}
+}
+
+func TestViewportWithTranslation(t *testing.T) {
+	assert := testify.New(t)
+
+	viewport := NewViewport(0, 0, 800, 600)
+	viewport.SetCamera(&Camera{})
+	viewport.PushTranslationOrtho(10, 20)
+
+	viewport.WithTranslationOrtho(5, 5, func() {
+		x, y := viewport.GetTranslationOrtho()
+		assert.Equal([2]float64{15, 25}, [2]float64{x, y}, "the translation applies within the callback")
+
+		viewport.WithTranslationWorld(1, 0, func() {
+			assert.Equal(3, viewport.GetTransStackDepth())
+		})
+
+		viewport.WithTranslationScreen(400, 300, func() {
+			assert.Equal(3, viewport.GetTransStackDepth())
+		})
+	})
+
+	assert.Equal(1, viewport.GetTransStackDepth())
+
+	func() {
+		defer func() {
+			assert.NotNil(recover(), "the panic goes through")
+		}()
+
+		viewport.WithTranslationOrtho(5, 5, func() {
+			viewport.PushTranslationOrtho(1, 1)
+			panic("render failed")
+		})
+	}()
+
+	assert.Equal(1, viewport.GetTransStackDepth(), "the stack is restored after a panic")
+
+	x, y := viewport.GetTranslationOrtho()
+	assert.Equal([2]float64{10, 20}, [2]float64{x, y})
+}
+
+func TestViewportTileSize(t *testing.T) {
+	assert := testify.New(t)
+
+	viewport := NewViewportWithTileSize(0, 0, 800, 600, 64, 32)
+	viewport.SetCamera(&Camera{})
+
+	orthoX, orthoY := viewport.WorldToOrtho(1, 0)
+	assert.InDelta(32.0, orthoX, 1e-9)
+	assert.InDelta(16.0, orthoY, 1e-9)
+
+	screenX, screenY := viewport.WorldToScreen(2, 1)
+	assert.Equal(432, screenX)
+	assert.Equal(348, screenY)
+
+	worldX, worldY := viewport.ScreenToWorld(432, 348)
+	assert.InDelta(2.0, worldX, 1e-9)
+	assert.InDelta(1.0, worldY, 1e-9)
+
+	// the tops of the tiles, walls included, are 16 pixels lower for every tile along x and y
+	assert.True(viewport.IsTileVisible(6, 6), "hidden with the default tile size")
+	assert.True(viewport.IsTileVisible(10, 11))
+	assert.False(viewport.IsTileVisible(11, 11))
+	assert.True(viewport.IsTileRectVisible(d2common.Rectangle{Left: 9, Top: 9, Width: 1, Height: 1}))
+	assert.False(viewport.IsTileRectVisible(d2common.Rectangle{Left: 10, Top: 10, Width: 1, Height: 1}))
+
+	// the art of a tile starts half a tile left of its top corner
+	viewport.camera.SetZoom(2)
+	cornerX, cornerY := viewport.GetTranslationScreen()
+	pushTileArtOffset(viewport, 4)
+	artX, artY := viewport.GetTranslationScreen()
+	popTranslation(viewport)
+	assert.Equal(-64, artX-cornerX)
+	assert.Equal(8, artY-cornerY)
+	viewport.camera.SetZoom(1)
+
+	viewport.SetProjection(ProjectionOrtho)
+	screenX, screenY = viewport.WorldToScreen(2, 1)
+	assert.Equal(464, screenX, "tiles are 32 pixel squares")
+	assert.Equal(332, screenY)
+	assert.True(viewport.IsTileVisible(2, 9))
+	assert.False(viewport.IsTileVisible(2, 13))
+}
@@ -0,0 +1,38 @@
+package d2mapstamp
+
+import "sync"
+
+// forcedPresets are the files picked for level presets instead of a random one, by level preset ID, to generate the
+// same layouts from one run to the next.
+//nolint:gochecknoglobals // shared by every map generated
+var forcedPresets = struct {
+	sync.RWMutex
+	files map[int]int
+}{files: make(map[int]int)}
+
+// ForcePresetFile makes the stamps of the level preset always load the file with the index, among the preset's files,
+// instead of a random one.
+func ForcePresetFile(levelPreset, fileIndex int) {
+	forcedPresets.Lock()
+	defer forcedPresets.Unlock()
+
+	forcedPresets.files[levelPreset] = fileIndex
+}
+
+// ClearForcedPresetFiles lets the stamps of every level preset load a random file again.
+func ClearForcedPresetFiles() {
+	forcedPresets.Lock()
+	defer forcedPresets.Unlock()
+
+	forcedPresets.files = make(map[int]int)
+}
+
+// forcedPresetFile returns the file index forced for the level preset, if any.
+func forcedPresetFile(levelPreset int) (int, bool) {
+	forcedPresets.RLock()
+	defer forcedPresets.RUnlock()
+
+	fileIndex, ok := forcedPresets.files[levelPreset]
+
+	return fileIndex, ok
+}
@@ -0,0 +1,159 @@
+package d2mapstamp
+
+import (
+	"math/rand"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2logger"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+// minionOffsets are the sub tile offsets, relative to their leader, at which the minions of a preset group are placed
+// when they can be walked on.
+// The leader stands on the DS1 position so quest monsters are always exactly where the map expects them.
+//nolint:gochecknoglobals // constant lookup table
+var minionOffsets = [][2]int{
+	{5, 0}, {0, 5}, {-5, 0}, {0, -5},
+	{5, 5}, {-5, 5}, {-5, -5}, {5, -5},
+	{10, 0}, {0, 10}, {-10, 0}, {0, -10},
+	{10, 5}, {5, 10}, {-5, 10}, {-10, 5},
+	{-10, -5}, {-5, -10}, {5, -10}, {10, -5},
+}
+
+// spawnArea is where the monsters of a stamp spawn: the seeded rolls of the map, and the sub tiles monsters can stand
+// on.
+type spawnArea struct {
+	rng      *rand.Rand
+	walkable func(subTileX, subTileY int) bool
+}
+
+// presetMonsters creates the monsters of a DS1 preset monster entry at the given sub tile position. The place is
+// either a monstats.txt Id, which spawns that single monster, or a superuniques.txt Superunique, which spawns the boss
+// with its group of minions. monplace.txt codes (place_*) only mark where random density spawns may go and yield nothing.
+// A density above 1 adds copies around common single monsters, but bosses, quest monsters and super unique groups
+// spawn exactly as placed. The monsters are scaled for the players count.
+func (a spawnArea) presetMonsters(place string, x, y int, scaling d2config.MonsterScaling) []*d2mapentity.NPC {
+	group := a.spawnPresetMonsters(place, x, y, scaling.GetDensity())
+
+	for _, npc := range group {
+		npc.SetPlayers(scaling.GetPlayers())
+	}
+
+	return group
+}
+
+func (a spawnArea) spawnPresetMonsters(place string, x, y int, density float64) []*d2mapentity.NPC {
+	if monstat, found := d2datadict.MonStats[place]; found && monstat != nil {
+		// The DS1 monster is always kept, a higher density adds copies of common monsters around
+		group := []*d2mapentity.NPC{d2mapentity.CreateNPC(x, y, monstat, 0)}
+		if isSpecialMonster(monstat) {
+			return group
+		}
+
+		copies := a.densityCount(1, density) - 1
+
+		for _, position := range a.minionPositions(x, y, copies) {
+			group = append(group, d2mapentity.CreateNPC(position[0], position[1], monstat, 0))
+		}
+
+		return group
+	}
+
+	superUnique, found := d2datadict.SuperUniques[place]
+	if !found || superUnique == nil {
+		return nil
+	}
+
+	monstat := d2datadict.MonStats[superUnique.Class]
+	if monstat == nil {
+		d2logger.Warningf(d2logger.CategoryAsset, "super unique %s has unknown monster class %s", place, superUnique.Class)
+		return nil
+	}
+
+	minionCount := superUnique.MinGrp
+	if superUnique.MaxGrp > superUnique.MinGrp {
+		minionCount += a.rng.Intn(superUnique.MaxGrp - superUnique.MinGrp + 1)
+	}
+
+	minionTypes := minionMonStats(monstat)
+
+	group := make([]*d2mapentity.NPC, 0, minionCount+1)
+	leader := d2mapentity.CreateNPC(x, y, monstat, 0)
+	leader.SetSuperUnique(superUnique.Name)
+	group = append(group, leader)
+
+	for _, position := range a.minionPositions(x, y, minionCount) {
+		minion := minionTypes[a.rng.Intn(len(minionTypes))]
+		group = append(group, d2mapentity.CreateNPC(position[0], position[1], minion, 0))
+	}
+
+	return group
+}
+
+// minionMonStats returns the monsters the minions of the boss are picked from: the minion1 and minion2 columns of its
+// monstats.txt row, or the boss's own monster when it has none.
+func minionMonStats(boss *d2datadict.MonStatsRecord) []*d2datadict.MonStatsRecord {
+	var minions []*d2datadict.MonStatsRecord
+
+	for _, id := range []string{boss.MinionId1, boss.MinionId2} {
+		if minion := d2datadict.MonStats[id]; id != "" && minion != nil {
+			minions = append(minions, minion)
+		}
+	}
+
+	if len(minions) == 0 {
+		return []*d2datadict.MonStatsRecord{boss}
+	}
+
+	return minions
+}
+
+// isSpecialMonster returns true for the bosses, NPCs and monsters quests interact with, which must spawn exactly as
+// the DS1 places them.
+func isSpecialMonster(monstat *d2datadict.MonStatsRecord) bool {
+	return monstat.IsSpecialBoss || monstat.IsActBoss || monstat.IsNpc || monstat.IsInteractable
+}
+
+// minionPositions returns the sub tile positions of at most count minions around their leader, at the minion offsets
+// which can be walked on.
+func (a spawnArea) minionPositions(x, y, count int) [][2]int {
+	if count <= 0 {
+		return nil
+	}
+
+	positions := make([][2]int, 0, count)
+
+	for _, offset := range minionOffsets {
+		if len(positions) == count {
+			break
+		}
+
+		if a.walkable(x+offset[0], y+offset[1]) {
+			positions = append(positions, [2]int{x + offset[0], y + offset[1]})
+		}
+	}
+
+	return positions
+}
+
+// densityCount returns the number of monsters to spawn in place of count monsters with the density multiplier, at most
+// one per minion offset. The fraction is spawned with its probability, so the average count matches the density.
+func (a spawnArea) densityCount(count int, density float64) int {
+	scaled := float64(count) * density
+	result := int(scaled)
+
+	if a.rng.Float64() < scaled-float64(result) {
+		result++
+	}
+
+	if result < 0 {
+		return 0
+	}
+
+	if result > len(minionOffsets) {
+		return len(minionOffsets)
+	}
+
+	return result
+}
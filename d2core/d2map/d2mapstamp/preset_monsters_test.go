@@ -0,0 +1,25 @@
+package d2mapstamp
+
+import (
+	"math/rand"
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestMinionPositions(t *testing.T) {
+	assert := testify.New(t)
+
+	area := spawnArea{
+		rng:      rand.New(rand.NewSource(1)), //nolint:gosec // test rolls
+		walkable: func(x, y int) bool { return x >= 0 },
+	}
+
+	for i := 0; i < 10; i++ {
+		copies := area.densityCount(1, 0.25) - 1
+		assert.Empty(area.minionPositions(0, 0, copies), "densities below 1 add no copies")
+	}
+
+	positions := area.minionPositions(0, 0, 4)
+	assert.Equal([][2]int{{5, 0}, {0, 5}, {0, -5}, {5, 5}}, positions, "minions skip the offsets which can't be walked on")
+}
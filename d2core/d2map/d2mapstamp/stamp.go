@@ -4,7 +4,6 @@ import (
 	"math"
 	"math/rand"
 
-	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2object"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
@@ -13,8 +12,8 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2dt1"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
-	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
 )
 
 // Stamp represents a pre-fabricated map stamp that can be placed on a map.
@@ -26,7 +25,8 @@ type Stamp struct {
 	ds1         *d2ds1.DS1                   // The backing DS1 file for this stamp
 }
 
-// LoadStamp loads the Stamp data from file.
+// LoadStamp loads the Stamp data from file. A negative fileIndex picks the file forced for the level preset, or a
+// random one among the preset's files.
 func LoadStamp(levelType d2enum.RegionIdType, levelPreset int, fileIndex int) *Stamp {
 	stamp := &Stamp{
 		levelType:   d2datadict.LevelTypes[levelType],
@@ -54,6 +54,12 @@ func LoadStamp(levelType d2enum.RegionIdType, levelPreset int, fileIndex int) *S
 		}
 	}
 
+	if fileIndex < 0 {
+		if forced, ok := forcedPresetFile(levelPreset); ok {
+			fileIndex = forced
+		}
+	}
+
 	levelIndex := int(math.Round(float64(len(levelFilesToPick)-1) * rand.Float64()))
 	if fileIndex >= 0 && fileIndex < len(levelFilesToPick) {
 		levelIndex = fileIndex
@@ -118,19 +124,25 @@ func (mr *Stamp) TileData(style int32, sequence int32, tileType d2enum.TileType)
 	return nil
 }
 
-// Entities spawns all entities and objects in this tile on the map.
-func (mr *Stamp) Entities(tileOffsetX, tileOffsetY int) []d2interface.MapEntity {
+// Entities spawns all entities and objects in this tile on the map. The monster spawns are rolled with the map's seeded
+// source, and the monsters added around the DS1 positions are only placed on sub tiles which can be walked on.
+func (mr *Stamp) Entities(tileOffsetX, tileOffsetY int, rng *rand.Rand,
+	walkable func(subTileX, subTileY int) bool) []d2interface.MapEntity {
 	entities := make([]d2interface.MapEntity, 0)
+	scaling := d2config.Config.Monsters
+	area := spawnArea{rng: rng, walkable: walkable}
 
 	for _, object := range mr.ds1.Objects {
 		if object.Type == int(d2enum.ObjectTypeCharacter) {
-			monstat := d2datadict.MonStats[d2datadict.MonPresets[mr.ds1.Act][object.Id]]
-			// If monstat is nil here it is a place_ type object, idk how to handle those yet.
-			// (See monpreset and monplace txts for reference)
-			if monstat != nil {
-				// Temorary use of Lookup.
-				npc := d2mapentity.CreateNPC((tileOffsetX*5)+object.X, (tileOffsetY*5)+object.Y, monstat, 0)
-				npc.SetPaths(convertPaths(tileOffsetX, tileOffsetY, object.Paths))
+			place := d2datadict.MonPresetPlace(mr.ds1.Act, object.Id)
+			group := area.presetMonsters(place, (tileOffsetX*5)+object.X, (tileOffsetY*5)+object.Y, scaling)
+
+			// Only the group leader follows the DS1 path, the minions stay where they were placed
+			if len(group) > 0 {
+				group[0].SetPaths(convertPaths(tileOffsetX, tileOffsetY, object.Paths))
+			}
+
+			for _, npc := range group {
 				entities = append(entities, npc)
 			}
 		}
@@ -148,7 +160,7 @@ func (mr *Stamp) Entities(tileOffsetX, tileOffsetY int) []d2interface.MapEntity
 
 			if objectRecord != nil {
 				entity, err := d2object.CreateObject((tileOffsetX*5)+object.X,
-					(tileOffsetY*5)+object.Y, objectRecord, d2resource.PaletteUnits)
+					(tileOffsetY*5)+object.Y, objectRecord, mr.levelType.PalettePath())
 
 				if err != nil {
 					panic(err)
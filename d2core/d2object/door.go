@@ -0,0 +1,49 @@
+package d2object
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// IsDoor returns true if the object is a door, which blocks walking while closed.
+func (ob *Object) IsDoor() bool {
+	return ob.objectRecord.IsDoor
+}
+
+// MonstersOperate returns true if monsters walking up to the object open it, as they do with most doors.
+func (ob *Object) MonstersOperate() bool {
+	return ob.objectRecord.MonsterOk
+}
+
+// BlocksWalk returns true if the object blocks walking on its footprint. An object opening or closing blocks walking
+// as its destination idle animation does, so doors stop blocking as soon as they start opening.
+func (ob *Object) BlocksWalk() bool {
+	return ob.objectRecord.HasCollision[ob.idleMode()]
+}
+
+// BlocksSight returns true if the object blocks seeing through its footprint, which only closed doors do.
+func (ob *Object) BlocksSight() bool {
+	return ob.objectRecord.IsDoor && ob.objectRecord.BlockVisibility && ob.idleMode() == d2enum.ObjectAnimationModeNeutral
+}
+
+// Footprint returns the sub tiles the object stands on, centered on its position.
+func (ob *Object) Footprint() d2common.Rectangle {
+	width := d2common.MaxInt(1, ob.objectRecord.SizeX)
+	height := d2common.MaxInt(1, ob.objectRecord.SizeY)
+
+	return d2common.Rectangle{
+		Left:   int(ob.Position.X()) - width/2,
+		Top:    int(ob.Position.Y()) - height/2,
+		Width:  width,
+		Height: height,
+	}
+}
+
+// idleMode returns the idle animation the object is in, or is opening or closing toward.
+func (ob *Object) idleMode() d2enum.ObjectAnimationMode {
+	if ob.IsOpened() {
+		return d2enum.ObjectAnimationModeOpened
+	}
+
+	return d2enum.ObjectAnimationModeNeutral
+}
@@ -2,6 +2,7 @@
 package d2object
 
 import (
+	"image/color"
 	"math/rand"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
@@ -9,10 +10,13 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math/d2vector"
-	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
 )
 
+// outlineColorItem is the color of the outline around the object under the cursor
+//nolint:gochecknoglobals // constant outline color
+var outlineColorItem = color.RGBA{R: 0x60, G: 0x90, B: 0xff, A: 0xff}
+
 // Object represents a composite of animations that can be projected onto the map.
 type Object struct {
 	Position  d2vector.Position
@@ -22,6 +26,10 @@ type Object struct {
 	objectRecord *d2datadict.ObjectRecord
 	drawLayer    int
 	name         string
+	state        State
+
+	transitionPlays int     // times the operating animation had played when the object started opening
+	transitionTime  float64 // seconds toward the next frame of the object closing
 }
 
 // CreateObject creates an instance of AnimatedComposite
@@ -34,8 +42,7 @@ func CreateObject(x, y int, objectRec *d2datadict.ObjectRecord, palettePath stri
 	}
 	objectType := &d2datadict.ObjectTypes[objectRec.Index]
 
-	composite, err := d2asset.LoadComposite(d2enum.ObjectTypeItem, objectType.Token,
-		d2resource.PaletteUnits)
+	composite, err := d2asset.LoadComposite(d2enum.ObjectTypeItem, objectType.Token, palettePath)
 	if err != nil {
 		return nil, err
 	}
@@ -84,6 +91,17 @@ func (ob *Object) setMode(animationMode d2enum.ObjectAnimationMode, direction in
 	return err
 }
 
+// Open opens the object, such as a chest or a door, right away, settling it in its opened idle animation without
+// playing the operating animation, as when restoring the state of a level.
+func (ob *Object) Open() {
+	ob.settle(StateOpened)
+}
+
+// IsOpened returns true if the object is opened or opening.
+func (ob *Object) IsOpened() bool {
+	return ob.state == StateOpened || ob.state == StateOpening
+}
+
 // Highlight sets the entity highlighted flag to true.
 func (ob *Object) Highlight() {
 	ob.highlight = true
@@ -102,19 +120,19 @@ func (ob *Object) Render(target d2interface.Surface) {
 		int(((renderOffset.X() + renderOffset.Y()) * 8)),
 	)
 
+	defer target.Pop()
+
 	if ob.highlight {
-		target.PushBrightness(2)
-		defer target.Pop()
+		ob.composite.RenderOutline(target, outlineColorItem)
 	}
 
-	defer target.Pop()
 	ob.composite.Render(target)
 	ob.highlight = false
 }
 
-// Advance updates the animation
+// Advance updates the animation, and the state of the object opening or closing
 func (ob *Object) Advance(elapsed float64) {
-	ob.composite.Advance(elapsed)
+	ob.advanceState(elapsed)
 }
 
 // GetLayer returns which layer of the map the object is drawn
@@ -0,0 +1,140 @@
+package d2object
+
+import (
+	"log"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// State is where an object, such as a chest or a door, is in its chain of animations from closed to opened.
+type State int
+
+// Object states
+const (
+	// StateClosed is the neutral idle animation of the object.
+	StateClosed State = iota
+
+	// StateOpening plays the operating animation once, then settles in StateOpened.
+	StateOpening
+
+	// StateOpened is the opened idle animation of the object.
+	StateOpened
+
+	// StateClosing plays the operating animation backward once, then settles in StateClosed.
+	StateClosing
+)
+
+// defaultFrameDelta is the animation rate of the objects whose record doesn't set one, 256 for 25 frames per second.
+const defaultFrameDelta = 256
+
+// framesPerSecondAtDelta is the number of frames played per second at a frame delta of 256.
+const framesPerSecondAtDelta = 25.0
+
+// State returns where the object is in its chain of animations from closed to opened.
+func (ob *Object) State() State {
+	return ob.state
+}
+
+// Operate opens the object, playing its operating animation before it settles in its opened idle animation. An object
+// closing turns around from the frame it is at. Objects without an operating animation open right away.
+func (ob *Object) Operate() {
+	switch ob.state {
+	case StateOpened, StateOpening:
+		return
+	case StateClosed:
+		if !ob.objectRecord.HasAnimationMode[d2enum.ObjectAnimationModeOperating] {
+			ob.Open()
+			return
+		}
+
+		ob.setTransitionMode()
+	}
+
+	ob.state = StateOpening
+	ob.transitionPlays = ob.composite.GetPlayedCount()
+}
+
+// Close closes the opened object, playing its operating animation backward before it settles in its neutral idle
+// animation. An object opening turns around from the frame it is at. Objects without an operating animation close
+// right away.
+func (ob *Object) Close() {
+	switch ob.state {
+	case StateClosed, StateClosing:
+		return
+	case StateOpened:
+		if !ob.objectRecord.HasAnimationMode[d2enum.ObjectAnimationModeOperating] {
+			ob.settle(StateClosed)
+			return
+		}
+
+		ob.setTransitionMode()
+		ob.composite.SetCurrentFrame(ob.composite.GetFrameCount() - 1)
+	}
+
+	ob.state = StateClosing
+	ob.transitionTime = 0
+}
+
+// setTransitionMode sets the operating animation, played once whichever way the object turns.
+func (ob *Object) setTransitionMode() {
+	if err := ob.setMode(d2enum.ObjectAnimationModeOperating, 0, false); err != nil {
+		log.Printf("failed to operate object %s: %v", ob.name, err)
+	}
+
+	ob.composite.SetPlayLoop(false)
+}
+
+// advanceState plays the transition of the object, and settles it in the idle animation at the end of the transition.
+func (ob *Object) advanceState(elapsed float64) {
+	switch ob.state {
+	case StateOpening:
+		ob.composite.Advance(elapsed)
+
+		if ob.composite.GetPlayedCount() > ob.transitionPlays {
+			ob.settle(StateOpened)
+		}
+	case StateClosing:
+		ob.transitionTime += elapsed
+		frameDuration := ob.frameDuration()
+		frames := int(ob.transitionTime / frameDuration)
+		ob.transitionTime -= float64(frames) * frameDuration
+
+		frame := ob.composite.GetCurrentFrame() - frames
+		if frame < 0 {
+			ob.settle(StateClosed)
+			return
+		}
+
+		ob.composite.SetCurrentFrame(frame)
+	default:
+		ob.composite.Advance(elapsed)
+	}
+}
+
+// settle sets the idle animation of the state, which is StateClosed or StateOpened.
+func (ob *Object) settle(state State) {
+	ob.state = state
+	mode := d2enum.ObjectAnimationModeNeutral
+
+	if state == StateOpened {
+		if !ob.objectRecord.HasAnimationMode[d2enum.ObjectAnimationModeOpened] {
+			return
+		}
+
+		mode = d2enum.ObjectAnimationModeOpened
+	}
+
+	if err := ob.setMode(mode, 0, false); err != nil {
+		log.Printf("failed to settle object %s: %v", ob.name, err)
+	}
+}
+
+// frameDuration returns the seconds each frame of the operating animation is shown.
+func (ob *Object) frameDuration() float64 {
+	delta := ob.objectRecord.FrameDelta[d2enum.ObjectAnimationModeOperating]
+	if delta <= 0 {
+		delta = defaultFrameDelta
+	}
+
+	return defaultFrameDelta / (float64(delta) * framesPerSecondAtDelta)
+}
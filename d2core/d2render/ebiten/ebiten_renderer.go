@@ -12,13 +12,21 @@ import (
 
 type Renderer struct {
 	renderCallback func(surface d2interface.Surface) error
+	ticksPerSecond int
+	frameCap       int
+	drawCalls      int
 }
 
 func (r *Renderer) Update(screen *ebiten.Image) error {
+	drawCalls = 0
+
 	err := r.renderCallback(createEbitenSurface(screen))
 	if err != nil {
 		return err
 	}
+
+	r.drawCalls = drawCalls
+
 	return nil
 }
 
@@ -27,14 +35,14 @@ func (r *Renderer) Layout(outsideWidth, outsideHeight int) (screenWidth, screenH
 }
 
 func CreateRenderer() (*Renderer, error) {
-	result := &Renderer{}
-
 	config := d2config.Config
+	result := &Renderer{ticksPerSecond: config.TicksPerSecond}
+
 	ebiten.SetCursorMode(ebiten.CursorModeHidden)
 	ebiten.SetFullscreen(config.FullScreen)
 	ebiten.SetRunnableOnUnfocused(config.RunInBackground)
 	ebiten.SetVsyncEnabled(config.VsyncEnabled)
-	ebiten.SetMaxTPS(config.TicksPerSecond)
+	result.SetFrameCap(config.FpsCap)
 
 	return result, nil
 }
@@ -99,6 +107,27 @@ func (r *Renderer) GetVSyncEnabled() bool {
 	return ebiten.IsVsyncEnabled()
 }
 
+// SetFrameCap limits the number of frames drawn per second, 0 or less removes the limit. Ebiten draws a frame on
+// every update, so the cap is applied to its tick rate; the game advances by the time elapsed between updates.
+func (r *Renderer) SetFrameCap(fps int) {
+	r.frameCap = fps
+
+	if fps > 0 {
+		ebiten.SetMaxTPS(fps)
+		return
+	}
+
+	ebiten.SetMaxTPS(r.ticksPerSecond)
+}
+
+func (r *Renderer) GetFrameCap() int {
+	if r.frameCap < 0 {
+		return 0
+	}
+
+	return r.frameCap
+}
+
 func (r *Renderer) GetCursorPos() (int, int) {
 	return ebiten.CursorPosition()
 }
@@ -106,3 +135,8 @@ func (r *Renderer) GetCursorPos() (int, int) {
 func (r *Renderer) CurrentFPS() float64 {
 	return ebiten.CurrentFPS()
 }
+
+// CurrentDrawCalls returns the number of draw calls made to render the last frame.
+func (r *Renderer) CurrentDrawCalls() int {
+	return r.drawCalls
+}
@@ -9,12 +9,16 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2DebugUtil"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math"
 	"github.com/hajimehoshi/ebiten"
 	"github.com/hajimehoshi/ebiten/ebitenutil"
 )
 
 const cacheLimit = 512
 
+// drawCalls counts the draw calls made on every surface since the start of the frame
+var drawCalls int //nolint:gochecknoglobals // shared by all surfaces, reset by the renderer every frame
+
 type colorMCacheKey uint32
 
 type colorMCacheEntry struct {
@@ -45,8 +49,18 @@ func createEbitenSurface(img *ebiten.Image, currentState ...surfaceState) *ebite
 
 func (s *ebitenSurface) PushTranslation(x, y int) {
 	s.stateStack = append(s.stateStack, s.stateCurrent)
-	s.stateCurrent.x += x
-	s.stateCurrent.y += y
+	s.stateCurrent.x += s.scaled(x)
+	s.stateCurrent.y += s.scaled(y)
+}
+
+func (s *ebitenSurface) PushScale(scale float64) {
+	s.stateStack = append(s.stateStack, s.stateCurrent)
+	s.stateCurrent.scale = s.stateCurrent.scaleFactor() * scale
+}
+
+// scaled returns the length scaled by the current scale factor.
+func (s *ebitenSurface) scaled(length int) int {
+	return int(math.Round(float64(length) * s.stateCurrent.scaleFactor()))
 }
 
 func (s *ebitenSurface) PushEffect(effect d2enum.DrawEffect) {
@@ -69,6 +83,23 @@ func (s *ebitenSurface) PushBrightness(brightness float64) {
 	s.stateCurrent.brightness = brightness
 }
 
+func (s *ebitenSurface) PushSilhouette(color color.Color) {
+	s.stateStack = append(s.stateStack, s.stateCurrent)
+	s.stateCurrent.silhouette = color
+}
+
+// PushColorMatrix transforms the colors of everything drawn, on top of the current color, brightness, silhouette and
+// effect.
+func (s *ebitenSurface) PushColorMatrix(matrix d2math.ColorMatrix) {
+	s.stateStack = append(s.stateStack, s.stateCurrent)
+
+	if s.stateCurrent.colorMatrix != nil {
+		matrix = s.stateCurrent.colorMatrix.Concat(matrix)
+	}
+
+	s.stateCurrent.colorMatrix = &matrix
+}
+
 func (s *ebitenSurface) Pop() {
 	count := len(s.stateStack)
 	if count == 0 {
@@ -87,76 +118,129 @@ func (s *ebitenSurface) PopN(n int) {
 
 func (s *ebitenSurface) Render(sfc d2interface.Surface) error {
 	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(s.stateCurrent.scaleFactor(), s.stateCurrent.scaleFactor())
 	opts.GeoM.Translate(float64(s.stateCurrent.x), float64(s.stateCurrent.y))
 	opts.Filter = s.stateCurrent.filter
 
+	opts.ColorM, opts.CompositeMode = s.drawState()
+
+	var img = sfc.(*ebitenSurface).image
+
+	drawCalls++
+
+	return s.image.DrawImage(img, opts)
+}
+
+// RenderBatch renders the surface at each of the offsets from the current translation with a single draw call, as
+// long as the offsets fit in the vertex indices of one call.
+func (s *ebitenSurface) RenderBatch(sfc d2interface.Surface, offsets []image.Point) error {
+	const (
+		verticesPerQuad = 4
+		indicesPerQuad  = 6
+		maxQuads        = ebiten.MaxIndicesNum / indicesPerQuad
+	)
+
+	img := sfc.(*ebitenSurface).image
+	bounds := img.Bounds()
+	scale := s.stateCurrent.scaleFactor()
+	width, height := float32(float64(bounds.Dx())*scale), float32(float64(bounds.Dy())*scale)
+
+	opts := &ebiten.DrawTrianglesOptions{Filter: s.stateCurrent.filter}
+	opts.ColorM, opts.CompositeMode = s.drawState()
+
+	for start := 0; start < len(offsets); start += maxQuads {
+		end := start + maxQuads
+		if end > len(offsets) {
+			end = len(offsets)
+		}
+
+		vertices := make([]ebiten.Vertex, 0, (end-start)*verticesPerQuad)
+		indices := make([]uint16, 0, (end-start)*indicesPerQuad)
+
+		for _, offset := range offsets[start:end] {
+			x := float32(s.stateCurrent.x + s.scaled(offset.X))
+			y := float32(s.stateCurrent.y + s.scaled(offset.Y))
+			first := uint16(len(vertices))
+
+			vertices = append(vertices,
+				batchVertex(x, y, bounds.Min.X, bounds.Min.Y),
+				batchVertex(x+width, y, bounds.Max.X, bounds.Min.Y),
+				batchVertex(x, y+height, bounds.Min.X, bounds.Max.Y),
+				batchVertex(x+width, y+height, bounds.Max.X, bounds.Max.Y),
+			)
+			indices = append(indices, first, first+1, first+2, first+1, first+3, first+2)
+		}
+
+		drawCalls++
+
+		s.image.DrawTriangles(vertices, indices, img, opts)
+	}
+
+	return nil
+}
+
+// batchVertex returns an untinted vertex drawing the source pixel srcX, srcY at x, y.
+func batchVertex(x, y float32, srcX, srcY int) ebiten.Vertex {
+	return ebiten.Vertex{
+		DstX: x, DstY: y,
+		SrcX: float32(srcX), SrcY: float32(srcY),
+		ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1,
+	}
+}
+
+// drawState returns the color matrix and composite mode of the current color, brightness, silhouette and effect.
+func (s *ebitenSurface) drawState() (colorM ebiten.ColorM, compositeMode ebiten.CompositeMode) {
 	if s.stateCurrent.color != nil {
-		opts.ColorM = s.colorToColorM(s.stateCurrent.color)
+		colorM = s.colorToColorM(s.stateCurrent.color)
 	}
 
 	if s.stateCurrent.brightness != 0 {
-		opts.ColorM.ChangeHSV(0, 1, s.stateCurrent.brightness)
+		colorM.ChangeHSV(0, 1, s.stateCurrent.brightness)
+	}
+
+	if s.stateCurrent.silhouette != nil {
+		colorM = silhouetteToColorM(s.stateCurrent.silhouette)
 	}
 
 	// Are these correct? who even knows
 	switch s.stateCurrent.effect {
 	case d2enum.DrawEffectPctTransparency25:
-		opts.ColorM.Translate(0, 0, 0, -0.25)
+		colorM.Translate(0, 0, 0, -0.25)
 	case d2enum.DrawEffectPctTransparency50:
-		opts.ColorM.Translate(0, 0, 0, -0.50)
+		colorM.Translate(0, 0, 0, -0.50)
 	case d2enum.DrawEffectPctTransparency75:
-		opts.ColorM.Translate(0, 0, 0, -0.75)
+		colorM.Translate(0, 0, 0, -0.75)
 	case d2enum.DrawEffectModulate:
-		opts.CompositeMode = ebiten.CompositeModeLighter
+		compositeMode = ebiten.CompositeModeLighter
 	// TODO: idk what to do when ebiten doesn't exactly match, pick closest?
 	case d2enum.DrawEffectBurn:
 	case d2enum.DrawEffectNormal:
 	case d2enum.DrawEffectMod2XTrans:
 	case d2enum.DrawEffectMod2X:
 	case d2enum.DrawEffectNone:
-		opts.CompositeMode = ebiten.CompositeModeSourceOver
+		compositeMode = ebiten.CompositeModeSourceOver
 	}
 
-	var img = sfc.(*ebitenSurface).image
+	if s.stateCurrent.colorMatrix != nil {
+		colorM.Concat(matrixToColorM(*s.stateCurrent.colorMatrix))
+	}
 
-	return s.image.DrawImage(img, opts)
+	return colorM, compositeMode
 }
 
 // Renders the section of the animation frame enclosed by bounds
 func (s *ebitenSurface) RenderSection(sfc d2interface.Surface, bound image.Rectangle) error {
 	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM.Scale(s.stateCurrent.scaleFactor(), s.stateCurrent.scaleFactor())
 	opts.GeoM.Translate(float64(s.stateCurrent.x), float64(s.stateCurrent.y))
 	opts.Filter = s.stateCurrent.filter
 
-	if s.stateCurrent.color != nil {
-		opts.ColorM = s.colorToColorM(s.stateCurrent.color)
-	}
-
-	if s.stateCurrent.brightness != 0 {
-		opts.ColorM.ChangeHSV(0, 1, s.stateCurrent.brightness)
-	}
-
-	// Are these correct? who even knows
-	switch s.stateCurrent.effect {
-	case d2enum.DrawEffectPctTransparency25:
-		opts.ColorM.Translate(0, 0, 0, -0.25)
-	case d2enum.DrawEffectPctTransparency50:
-		opts.ColorM.Translate(0, 0, 0, -0.50)
-	case d2enum.DrawEffectPctTransparency75:
-		opts.ColorM.Translate(0, 0, 0, -0.75)
-	case d2enum.DrawEffectModulate:
-		opts.CompositeMode = ebiten.CompositeModeLighter
-	// TODO: idk what to do when ebiten doesn't exactly match, pick closest?
-	case d2enum.DrawEffectBurn:
-	case d2enum.DrawEffectNormal:
-	case d2enum.DrawEffectMod2XTrans:
-	case d2enum.DrawEffectMod2X:
-	case d2enum.DrawEffectNone:
-		opts.CompositeMode = ebiten.CompositeModeSourceOver
-	}
+	opts.ColorM, opts.CompositeMode = s.drawState()
 
 	var img = sfc.(*ebitenSurface).image
 
+	drawCalls++
+
 	return s.image.DrawImage(img.SubImage(bound).(*ebiten.Image), opts)
 }
 
@@ -165,23 +249,27 @@ func (s *ebitenSurface) DrawText(format string, params ...interface{}) {
 }
 
 func (s *ebitenSurface) DrawLine(x, y int, color color.Color) {
+	drawCalls++
+
 	ebitenutil.DrawLine(
 		s.image,
 		float64(s.stateCurrent.x),
 		float64(s.stateCurrent.y),
-		float64(s.stateCurrent.x+x),
-		float64(s.stateCurrent.y+y),
+		float64(s.stateCurrent.x+s.scaled(x)),
+		float64(s.stateCurrent.y+s.scaled(y)),
 		color,
 	)
 }
 
 func (s *ebitenSurface) DrawRect(width, height int, color color.Color) {
+	drawCalls++
+
 	ebitenutil.DrawRect(
 		s.image,
 		float64(s.stateCurrent.x),
 		float64(s.stateCurrent.y),
-		float64(width),
-		float64(height),
+		float64(s.scaled(width)),
+		float64(s.scaled(height)),
 		color,
 	)
 }
@@ -221,6 +309,30 @@ func (s *ebitenSurface) now() int64 {
 	return s.monotonicClock
 }
 
+// matrixToColorM converts a color matrix to an ebiten color matrix
+func matrixToColorM(matrix d2math.ColorMatrix) ebiten.ColorM {
+	cm := ebiten.ColorM{}
+
+	for row := range matrix {
+		for col, value := range matrix[row] {
+			cm.SetElement(row, col, value)
+		}
+	}
+
+	return cm
+}
+
+// silhouetteToColorM returns a color matrix that replaces every color with the given one, keeping the alpha
+func silhouetteToColorM(clr color.Color) ebiten.ColorM {
+	cr, cg, cb, ca := clr.RGBA()
+
+	cm := ebiten.ColorM{}
+	cm.Scale(0, 0, 0, float64(ca)/0xffff)
+	cm.Translate(float64(cr)/0xffff, float64(cg)/0xffff, float64(cb)/0xffff, 0)
+
+	return cm
+}
+
 // colorToColorM converts a normal color to a color matrix
 func (s *ebitenSurface) colorToColorM(clr color.Color) ebiten.ColorM {
 	// RGBA() is in [0 - 0xffff]. Adjust them in [0 - 0xff].
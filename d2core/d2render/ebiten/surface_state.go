@@ -4,6 +4,7 @@ import (
 	"image/color"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math"
 	"github.com/hajimehoshi/ebiten"
 )
 
@@ -14,4 +15,17 @@ type surfaceState struct {
 	color      color.Color
 	brightness float64
 	effect     d2enum.DrawEffect
+	silhouette color.Color
+	scale      float64 // 0 is the original size
+
+	colorMatrix *d2math.ColorMatrix // transform of the colors drawn, nil for none
+}
+
+// scaleFactor returns the factor everything drawn with this state is scaled by.
+func (s *surfaceState) scaleFactor() float64 {
+	if s.scale == 0 {
+		return 1
+	}
+
+	return s.scale
 }
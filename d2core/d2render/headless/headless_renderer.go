@@ -0,0 +1,154 @@
+// Package headless implements a renderer which draws to images in memory, for running the game without a GPU, such as
+// on servers and in tests.
+package headless
+
+import (
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+)
+
+// defaultFrameRate is the number of frames drawn per second without a frame cap, the rate the screens advance at.
+const defaultFrameRate = 25
+
+// Renderer draws the frames to an image in memory at a fixed rate instead of showing them in a window.
+type Renderer struct {
+	fullScreen bool
+	vsync      bool
+	frameCap   int
+	fps        float64
+	drawCalls  int
+	stats      *frameStats
+}
+
+// frameStats is shared by the surfaces of a renderer to count the draw calls of the current frame.
+type frameStats struct {
+	drawCalls int
+}
+
+var _ d2interface.Renderer = &Renderer{}
+
+// CreateRenderer creates a headless renderer with the display settings of the configuration.
+func CreateRenderer() (*Renderer, error) {
+	config := d2config.Config
+
+	return &Renderer{
+		fullScreen: config.FullScreen,
+		vsync:      config.VsyncEnabled,
+		frameCap:   config.FpsCap,
+		stats:      &frameStats{},
+	}, nil
+}
+
+// GetRendererName returns the name of the renderer.
+func (*Renderer) GetRendererName() string {
+	return "Headless"
+}
+
+// SetWindowIcon does nothing, there is no window.
+func (*Renderer) SetWindowIcon(string) {}
+
+// Run calls f with a screen of the given size for every frame until it returns an error.
+func (r *Renderer) Run(f func(d2interface.Surface) error, width, height int, _ string) error {
+	screen := r.newSurface(width, height)
+	frames := 0
+	second := time.Now()
+
+	for {
+		start := time.Now()
+		r.stats.drawCalls = 0
+
+		if err := f(screen); err != nil {
+			return err
+		}
+
+		r.drawCalls = r.stats.drawCalls
+		frames++
+
+		if elapsed := time.Since(second); elapsed >= time.Second {
+			r.fps = float64(frames) / elapsed.Seconds()
+			frames = 0
+			second = time.Now()
+		}
+
+		time.Sleep(time.Second/time.Duration(r.frameRate()) - time.Since(start))
+	}
+}
+
+func (r *Renderer) frameRate() int {
+	if r.frameCap > 0 {
+		return r.frameCap
+	}
+
+	return defaultFrameRate
+}
+
+// IsDrawingSkipped always returns false, every frame is drawn.
+func (*Renderer) IsDrawingSkipped() bool {
+	return false
+}
+
+// CreateSurface returns a new surface drawing to the same image as the given one.
+func (r *Renderer) CreateSurface(surface d2interface.Surface) (d2interface.Surface, error) {
+	return &headlessSurface{image: surface.(*headlessSurface).image, stats: r.stats}, nil
+}
+
+// NewSurface returns a new surface of the given size. The filter is ignored.
+func (r *Renderer) NewSurface(width, height int, _ d2enum.Filter) (d2interface.Surface, error) {
+	return r.newSurface(width, height), nil
+}
+
+func (r *Renderer) newSurface(width, height int) *headlessSurface {
+	return createHeadlessSurface(width, height, r.stats)
+}
+
+// IsFullScreen returns the full screen setting, which has no effect.
+func (r *Renderer) IsFullScreen() bool {
+	return r.fullScreen
+}
+
+// SetFullScreen changes the full screen setting, which has no effect.
+func (r *Renderer) SetFullScreen(fullScreen bool) {
+	r.fullScreen = fullScreen
+}
+
+// SetVSyncEnabled changes the vsync setting, which has no effect.
+func (r *Renderer) SetVSyncEnabled(vsync bool) {
+	r.vsync = vsync
+}
+
+// GetVSyncEnabled returns the vsync setting.
+func (r *Renderer) GetVSyncEnabled() bool {
+	return r.vsync
+}
+
+// SetFrameCap sets the number of frames drawn per second, 0 or less draws defaultFrameRate frames per second.
+func (r *Renderer) SetFrameCap(fps int) {
+	r.frameCap = fps
+}
+
+// GetFrameCap returns the frame cap, 0 if there is none.
+func (r *Renderer) GetFrameCap() int {
+	if r.frameCap < 0 {
+		return 0
+	}
+
+	return r.frameCap
+}
+
+// GetCursorPos always returns the top left corner, there is no cursor.
+func (*Renderer) GetCursorPos() (x, y int) {
+	return 0, 0
+}
+
+// CurrentFPS returns the number of frames drawn in the last second.
+func (r *Renderer) CurrentFPS() float64 {
+	return r.fps
+}
+
+// CurrentDrawCalls returns the number of draw calls made to render the last frame.
+func (r *Renderer) CurrentDrawCalls() int {
+	return r.drawCalls
+}
@@ -0,0 +1,221 @@
+package headless
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2math"
+)
+
+type surfaceState struct {
+	x      int
+	y      int
+	scale  float64       // 0 is the original size
+	filter d2enum.Filter // sampling of the scaled images, nearest-neighbor unless linear
+}
+
+// scaleFactor returns the factor everything drawn with this state is scaled by.
+func (s *surfaceState) scaleFactor() float64 {
+	if s.scale == 0 {
+		return 1
+	}
+
+	return s.scale
+}
+
+// headlessSurface draws to an RGBA image in memory. Translations, scales and filters are applied, colors, effects and
+// text are ignored.
+type headlessSurface struct {
+	stateStack   []surfaceState
+	stateCurrent surfaceState
+	image        *image.RGBA
+	stats        *frameStats
+}
+
+var _ d2interface.Surface = &headlessSurface{}
+
+func createHeadlessSurface(width, height int, stats *frameStats) *headlessSurface {
+	return &headlessSurface{image: image.NewRGBA(image.Rect(0, 0, width, height)), stats: stats}
+}
+
+func (s *headlessSurface) push() {
+	s.stateStack = append(s.stateStack, s.stateCurrent)
+}
+
+func (s *headlessSurface) PushTranslation(x, y int) {
+	s.push()
+	s.stateCurrent.x += s.scaled(x)
+	s.stateCurrent.y += s.scaled(y)
+}
+
+func (s *headlessSurface) PushScale(scale float64) {
+	s.push()
+	s.stateCurrent.scale = s.stateCurrent.scaleFactor() * scale
+}
+
+// scaled returns the length scaled by the current scale factor.
+func (s *headlessSurface) scaled(length int) int {
+	return int(math.Round(float64(length) * s.stateCurrent.scaleFactor()))
+}
+
+func (s *headlessSurface) PushEffect(d2enum.DrawEffect) {
+	s.push()
+}
+
+func (s *headlessSurface) PushFilter(filter d2enum.Filter) {
+	s.push()
+	s.stateCurrent.filter = filter
+}
+
+func (s *headlessSurface) PushColor(color.Color) {
+	s.push()
+}
+
+func (s *headlessSurface) PushBrightness(float64) {
+	s.push()
+}
+
+func (s *headlessSurface) PushSilhouette(color.Color) {
+	s.push()
+}
+
+func (s *headlessSurface) PushColorMatrix(d2math.ColorMatrix) {
+	s.push()
+}
+
+func (s *headlessSurface) Pop() {
+	count := len(s.stateStack)
+	if count == 0 {
+		panic("empty stack")
+	}
+
+	s.stateCurrent = s.stateStack[count-1]
+	s.stateStack = s.stateStack[:count-1]
+}
+
+func (s *headlessSurface) PopN(n int) {
+	for i := 0; i < n; i++ {
+		s.Pop()
+	}
+}
+
+func (s *headlessSurface) Render(sfc d2interface.Surface) error {
+	img := sfc.(*headlessSurface).image
+
+	s.countDrawCall()
+	s.drawImage(img, img.Bounds(), image.Point{})
+
+	return nil
+}
+
+func (s *headlessSurface) RenderSection(sfc d2interface.Surface, bound image.Rectangle) error {
+	img := sfc.(*headlessSurface).image
+
+	s.countDrawCall()
+	s.drawImage(img, bound.Intersect(img.Bounds()), image.Point{})
+
+	return nil
+}
+
+func (s *headlessSurface) RenderBatch(sfc d2interface.Surface, offsets []image.Point) error {
+	img := sfc.(*headlessSurface).image
+
+	s.countDrawCall()
+
+	for _, offset := range offsets {
+		s.drawImage(img, img.Bounds(), offset)
+	}
+
+	return nil
+}
+
+// drawImage draws the section of the image at the current translation moved by offset, scaled by the current scale
+// with the current filter.
+func (s *headlessSurface) drawImage(img *image.RGBA, section image.Rectangle, offset image.Point) {
+	x := s.stateCurrent.x + s.scaled(offset.X)
+	y := s.stateCurrent.y + s.scaled(offset.Y)
+	target := image.Rect(x, y, x+s.scaled(section.Dx()), y+s.scaled(section.Dy()))
+
+	if s.stateCurrent.scaleFactor() == 1 {
+		draw.Draw(s.image, target, img, section.Min, draw.Over)
+		return
+	}
+
+	if s.stateCurrent.filter == d2enum.FilterLinear {
+		xdraw.ApproxBiLinear.Scale(s.image, target, img, section, draw.Over, nil)
+		return
+	}
+
+	xdraw.NearestNeighbor.Scale(s.image, target, img, section, draw.Over, nil)
+}
+
+func (s *headlessSurface) DrawText(string, ...interface{}) {
+	s.countDrawCall()
+}
+
+func (s *headlessSurface) DrawLine(x, y int, clr color.Color) {
+	s.countDrawCall()
+
+	dx, dy := s.scaled(x), s.scaled(y)
+	steps := math.Max(math.Abs(float64(dx)), math.Abs(float64(dy)))
+
+	for i := 0.0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = i / steps
+		}
+
+		s.image.Set(s.stateCurrent.x+int(math.Round(float64(dx)*t)), s.stateCurrent.y+int(math.Round(float64(dy)*t)), clr)
+	}
+}
+
+func (s *headlessSurface) DrawRect(width, height int, clr color.Color) {
+	s.countDrawCall()
+
+	rect := image.Rect(0, 0, s.scaled(width), s.scaled(height)).Add(image.Pt(s.stateCurrent.x, s.stateCurrent.y))
+	draw.Draw(s.image, rect, image.NewUniform(clr), image.Point{}, draw.Over)
+}
+
+func (s *headlessSurface) Clear(clr color.Color) error {
+	draw.Draw(s.image, s.image.Bounds(), image.NewUniform(clr), image.Point{}, draw.Src)
+	return nil
+}
+
+func (s *headlessSurface) GetSize() (width, height int) {
+	size := s.image.Bounds().Size()
+	return size.X, size.Y
+}
+
+func (s *headlessSurface) GetDepth() int {
+	return len(s.stateStack)
+}
+
+func (s *headlessSurface) ReplacePixels(pixels []byte) error {
+	if len(pixels) != len(s.image.Pix) {
+		return errors.New("the number of pixels doesn't match the size of the surface")
+	}
+
+	copy(s.image.Pix, pixels)
+
+	return nil
+}
+
+func (s *headlessSurface) Screenshot() *image.RGBA {
+	screenshot := image.NewRGBA(s.image.Bounds())
+	copy(screenshot.Pix, s.image.Pix)
+
+	return screenshot
+}
+
+func (s *headlessSurface) countDrawCall() {
+	if s.stats != nil {
+		s.stats.drawCalls++
+	}
+}
@@ -0,0 +1,56 @@
+package headless
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestSurfaceRenderTranslatedAndScaled(t *testing.T) {
+	assert := testify.New(t)
+
+	renderer := &Renderer{stats: &frameStats{}}
+	screen, _ := renderer.NewSurface(20, 20, d2enum.FilterNearest)
+	sprite, _ := renderer.NewSurface(2, 2, d2enum.FilterNearest)
+
+	red := color.RGBA{R: 0xff, A: 0xff}
+	assert.NoError(sprite.Clear(red))
+
+	screen.PushTranslation(4, 6)
+	screen.PushScale(2)
+	assert.NoError(screen.Render(sprite))
+	screen.PopN(2)
+
+	assert.Equal(0, screen.GetDepth())
+
+	pixels := screen.Screenshot()
+	assert.Equal(color.RGBA{}, pixels.RGBAAt(3, 6))
+	assert.Equal(red, pixels.RGBAAt(4, 6))
+	assert.Equal(red, pixels.RGBAAt(7, 9))
+	assert.Equal(color.RGBA{}, pixels.RGBAAt(8, 10))
+	assert.Equal(1, renderer.stats.drawCalls)
+}
+
+func TestSurfaceRenderBatch(t *testing.T) {
+	assert := testify.New(t)
+
+	renderer := &Renderer{stats: &frameStats{}}
+	screen, _ := renderer.NewSurface(20, 20, d2enum.FilterNearest)
+	sprite, _ := renderer.NewSurface(1, 1, d2enum.FilterNearest)
+
+	blue := color.RGBA{B: 0xff, A: 0xff}
+	assert.NoError(sprite.Clear(blue))
+
+	screen.PushTranslation(1, 1)
+	assert.NoError(screen.RenderBatch(sprite, []image.Point{{X: 0, Y: 0}, {X: 5, Y: 2}}))
+	screen.Pop()
+
+	pixels := screen.Screenshot()
+	assert.Equal(blue, pixels.RGBAAt(1, 1))
+	assert.Equal(blue, pixels.RGBAAt(6, 3))
+	assert.Equal(1, renderer.stats.drawCalls)
+}
@@ -35,6 +35,21 @@ type ScreenAdvanceHandler interface {
 	Advance(elapsed float64) error
 }
 
+// ScreenCrashHandler is implemented by screens holding game state that should be reported and saved if the game crashes
+type ScreenCrashHandler interface {
+	// CrashState describes the game state for a crash report, such as the current level, seed and character.
+	CrashState() string
+	// EmergencySave attempts to save the player's progress before the game exits.
+	EmergencySave() error
+}
+
+// ScreenPlayersHandler is implemented by screens running a game whose monsters can be scaled for the players count
+type ScreenPlayersHandler interface {
+	// SetPlayers rescales the monsters of the game for the number of players. It returns false if the game is hosted
+	// by someone else, whose setting applies.
+	SetPlayers(players int) bool
+}
+
 var singleton struct {
 	nextScreen    Screen
 	loadingScreen Screen
@@ -42,6 +57,11 @@ var singleton struct {
 	currentScreen Screen
 }
 
+// CurrentScreen returns the screen currently being shown
+func CurrentScreen() Screen {
+	return singleton.currentScreen
+}
+
 // SetNextScreen is about to set a given screen as next
 func SetNextScreen(screen Screen) {
 	singleton.nextScreen = screen
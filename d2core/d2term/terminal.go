@@ -279,7 +279,11 @@ func (t *terminal) Execute(command string) error {
 		return errors.New("action is not a function")
 	}
 
-	if len(actionParams) != actionType.NumIn() {
+	if actionType.IsVariadic() {
+		if len(actionParams) < actionType.NumIn()-1 {
+			return errors.New("action requires more arguments")
+		}
+	} else if len(actionParams) != actionType.NumIn() {
 		return errors.New("action requires different argument count")
 	}
 
@@ -305,46 +309,58 @@ func (t *terminal) Execute(command string) error {
 func parseActionParams(actionType reflect.Type, actionParams []string) ([]reflect.Value, error) {
 	var paramValues []reflect.Value
 
-	for i := 0; i < actionType.NumIn(); i++ {
-		actionParam := actionParams[i]
+	for i, actionParam := range actionParams {
+		paramType := actionType.In(i)
+		if actionType.IsVariadic() && i >= actionType.NumIn()-1 {
+			paramType = actionType.In(actionType.NumIn() - 1).Elem()
+		}
 
-		switch actionType.In(i).Kind() {
-		case reflect.String:
-			paramValues = append(paramValues, reflect.ValueOf(actionParam))
-		case reflect.Int:
-			value, err := strconv.ParseInt(actionParam, 10, 64)
-			if err != nil {
-				return nil, err
-			}
+		paramValue, err := parseActionParam(paramType.Kind(), actionParam)
+		if err != nil {
+			return nil, err
+		}
 
-			paramValues = append(paramValues, reflect.ValueOf(int(value)))
-		case reflect.Uint:
-			value, err := strconv.ParseUint(actionParam, 10, 64)
-			if err != nil {
-				return nil, err
-			}
+		paramValues = append(paramValues, paramValue)
+	}
 
-			paramValues = append(paramValues, reflect.ValueOf(uint(value)))
-		case reflect.Float64:
-			value, err := strconv.ParseFloat(actionParam, 64)
-			if err != nil {
-				return nil, err
-			}
+	return paramValues, nil
+}
 
-			paramValues = append(paramValues, reflect.ValueOf(value))
-		case reflect.Bool:
-			value, err := strconv.ParseBool(actionParam)
-			if err != nil {
-				return nil, err
-			}
+func parseActionParam(kind reflect.Kind, actionParam string) (reflect.Value, error) {
+	switch kind {
+	case reflect.String:
+		return reflect.ValueOf(actionParam), nil
+	case reflect.Int:
+		value, err := strconv.ParseInt(actionParam, 10, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
 
-			paramValues = append(paramValues, reflect.ValueOf(value))
-		default:
-			return nil, errors.New("action has unsupported arguments")
+		return reflect.ValueOf(int(value)), nil
+	case reflect.Uint:
+		value, err := strconv.ParseUint(actionParam, 10, 64)
+		if err != nil {
+			return reflect.Value{}, err
 		}
-	}
 
-	return paramValues, nil
+		return reflect.ValueOf(uint(value)), nil
+	case reflect.Float64:
+		value, err := strconv.ParseFloat(actionParam, 64)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(value), nil
+	case reflect.Bool:
+		value, err := strconv.ParseBool(actionParam)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(value), nil
+	default:
+		return reflect.Value{}, errors.New("action has unsupported arguments")
+	}
 }
 
 func (t *terminal) OutputRaw(text string, category d2enum.TermCategory) {
@@ -413,7 +429,12 @@ func (t *terminal) BindAction(name, description string, action interface{}) erro
 	}
 
 	for i := 0; i < actionType.NumIn(); i++ {
-		switch actionType.In(i).Kind() {
+		paramType := actionType.In(i)
+		if actionType.IsVariadic() && i == actionType.NumIn()-1 {
+			paramType = paramType.Elem()
+		}
+
+		switch paramType.Kind() {
 		case reflect.String:
 		case reflect.Int:
 		case reflect.Uint:
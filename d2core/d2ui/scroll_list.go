@@ -0,0 +1,151 @@
+package d2ui
+
+import (
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// noSelection is the selected index of a scroll list without a selected item
+const noSelection = -1
+
+// ScrollList is a list of text rows of which only a few are visible at a time. The visible rows follow the selection.
+type ScrollList struct {
+	x, y          int
+	width         int
+	rowHeight     int
+	visibleRows   int
+	items         []string
+	selectable    []bool
+	selected      int
+	offset        int
+	label         Label
+	ItemColor     color.Color
+	SelectedColor color.Color
+	HeaderColor   color.Color
+}
+
+// CreateScrollList creates a list at the given position showing visibleRows rows of the given height.
+func CreateScrollList(x, y, width, rowHeight, visibleRows int, fontPath, palettePath string) ScrollList {
+	return ScrollList{
+		x:             x,
+		y:             y,
+		width:         width,
+		rowHeight:     rowHeight,
+		visibleRows:   visibleRows,
+		selected:      noSelection,
+		label:         CreateLabel(fontPath, palettePath),
+		ItemColor:     color.White,
+		SelectedColor: color.RGBA{R: 255, G: 215, B: 0, A: 255},
+		HeaderColor:   color.RGBA{R: 150, G: 150, B: 150, A: 255},
+	}
+}
+
+// Clear removes all rows from the list.
+func (v *ScrollList) Clear() {
+	v.items = nil
+	v.selectable = nil
+	v.selected = noSelection
+	v.offset = 0
+}
+
+// AddHeader adds a row which can't be selected, such as the title of a group of items.
+func (v *ScrollList) AddHeader(text string) {
+	v.items = append(v.items, text)
+	v.selectable = append(v.selectable, false)
+}
+
+// AddItem adds a row which can be selected and returns its index.
+func (v *ScrollList) AddItem(text string) int {
+	v.items = append(v.items, text)
+	v.selectable = append(v.selectable, true)
+
+	return len(v.items) - 1
+}
+
+// GetSelected returns the index of the selected row, or -1 if no row is selected.
+func (v *ScrollList) GetSelected() int {
+	return v.selected
+}
+
+// SetSelected selects the row with the given index, scrolling it into view. Rows which can't be selected are ignored.
+func (v *ScrollList) SetSelected(index int) {
+	if index < 0 || index >= len(v.items) || !v.selectable[index] {
+		return
+	}
+
+	v.selected = index
+
+	if index < v.offset {
+		v.offset = index
+	} else if index >= v.offset+v.visibleRows {
+		v.offset = index - v.visibleRows + 1
+	}
+}
+
+// SelectNext selects the next selectable row.
+func (v *ScrollList) SelectNext() {
+	for i := v.selected + 1; i < len(v.items); i++ {
+		if v.selectable[i] {
+			v.SetSelected(i)
+			return
+		}
+	}
+}
+
+// SelectPrevious selects the previous selectable row.
+func (v *ScrollList) SelectPrevious() {
+	for i := v.selected - 1; i >= 0; i-- {
+		if v.selectable[i] {
+			v.SetSelected(i)
+			return
+		}
+	}
+}
+
+// Scroll moves the visible rows by the given number of rows.
+func (v *ScrollList) Scroll(rows int) {
+	v.offset += rows
+
+	if maxOffset := len(v.items) - v.visibleRows; v.offset > maxOffset {
+		v.offset = maxOffset
+	}
+
+	if v.offset < 0 {
+		v.offset = 0
+	}
+}
+
+// RowAt returns the index of the row at the given screen position, or -1 if there is none.
+func (v *ScrollList) RowAt(x, y int) int {
+	if x < v.x || x >= v.x+v.width || y < v.y || y >= v.y+v.visibleRows*v.rowHeight {
+		return noSelection
+	}
+
+	index := v.offset + (y-v.y)/v.rowHeight
+	if index >= len(v.items) {
+		return noSelection
+	}
+
+	return index
+}
+
+// Render draws the visible rows.
+func (v *ScrollList) Render(target d2interface.Surface) {
+	for row := 0; row < v.visibleRows && v.offset+row < len(v.items); row++ {
+		index := v.offset + row
+
+		switch {
+		case index == v.selected:
+			v.label.Color = v.SelectedColor
+		case !v.selectable[index]:
+			v.label.Color = v.HeaderColor
+		default:
+			v.label.Color = v.ItemColor
+		}
+
+		v.label.SetText(v.items[index])
+		v.label.SetPosition(v.x, v.y+row*v.rowHeight)
+		v.label.Render(target)
+	}
+}
@@ -0,0 +1,245 @@
+package d2gamescreen
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2maprenderer"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2screen"
+)
+
+const (
+	// benchmarkSeed seeds the map generation and the spawns, so every run of the benchmark is the same
+	benchmarkSeed = 1
+
+	benchmarkRegion    = d2enum.RegionAct1Wilderness
+	benchmarkPreset    = 4
+	benchmarkMissileID = 59
+
+	// benchmarkSpawnRadius is how far from the map center, in sub tiles, monsters and missiles are spawned
+	benchmarkSpawnRadius = 60
+
+	// benchmarkPatrolLength is how far, in sub tiles, the monsters walk back and forth
+	benchmarkPatrolLength = 25
+)
+
+// benchmarkMonsters are the monstats.txt Ids the monsters of the benchmark are picked from.
+//nolint:gochecknoglobals // constant lookup table
+var benchmarkMonsters = []string{"zombie1", "fallen1", "quillrat1", "skeleton1"}
+
+// benchmarkStats accumulates the frame and tick timings of a benchmark run
+type benchmarkStats struct {
+	elapsed       float64
+	frames        int
+	ticks         int
+	tickTime      time.Duration
+	renderTime    time.Duration
+	maxRenderTime time.Duration
+	drawCalls     int
+}
+
+func (s *benchmarkStats) addTick(elapsed float64, tickTime time.Duration) {
+	s.elapsed += elapsed
+	s.ticks++
+	s.tickTime += tickTime
+}
+
+func (s *benchmarkStats) addFrame(renderTime time.Duration, drawCalls int) {
+	s.frames++
+	s.renderTime += renderTime
+	s.drawCalls += drawCalls
+
+	if renderTime > s.maxRenderTime {
+		s.maxRenderTime = renderTime
+	}
+}
+
+// String returns the average frame rate, tick time, render time and draw calls per frame of the run.
+func (s *benchmarkStats) String() string {
+	if s.elapsed <= 0 || s.frames == 0 || s.ticks == 0 {
+		return "no frames rendered"
+	}
+
+	return fmt.Sprintf("%d frames in %.1fs: %.1f FPS, tick %.2fms, render %.2fms (max %.2fms), %d draw calls",
+		s.frames, s.elapsed, float64(s.frames)/s.elapsed,
+		durationMilliseconds(s.tickTime/time.Duration(s.ticks)),
+		durationMilliseconds(s.renderTime/time.Duration(s.frames)),
+		durationMilliseconds(s.maxRenderTime), s.drawCalls/s.frames)
+}
+
+func durationMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Benchmark is a stress scene which renders a fixed map crowded with monsters and missiles for a set duration, then
+// reports its frame rate and timings to the terminal.
+type Benchmark struct {
+	renderer     d2interface.Renderer
+	terminal     d2interface.Terminal
+	mapEngine    *d2mapengine.MapEngine
+	mapRenderer  *d2maprenderer.MapRenderer
+	rand         *rand.Rand
+	monsterCount int
+	missileCount int
+	missiles     int // missiles currently flying
+	duration     float64
+	centerX      int
+	centerY      int
+	stats        benchmarkStats
+	isDone       bool
+}
+
+// CreateBenchmark creates a benchmark screen spawning the given number of monsters and missiles, which runs for the
+// given number of seconds.
+func CreateBenchmark(monsters, missiles int, seconds float64, renderer d2interface.Renderer,
+	term d2interface.Terminal) *Benchmark {
+	return &Benchmark{
+		renderer:     renderer,
+		terminal:     term,
+		rand:         rand.New(rand.NewSource(benchmarkSeed)), //nolint:gosec // the benchmark must be repeatable
+		monsterCount: monsters,
+		missileCount: missiles,
+		duration:     seconds,
+	}
+}
+
+// OnLoad generates the map and spawns the monsters
+func (b *Benchmark) OnLoad(loading d2screen.LoadingState) {
+	// Map generation and monster creation roll with the global source
+	rand.Seed(benchmarkSeed)
+
+	b.mapEngine = d2mapengine.CreateMapEngine()
+	b.mapEngine.SetSeed(benchmarkSeed)
+	b.mapEngine.GenerateMap(benchmarkRegion, benchmarkPreset, 0, true)
+	b.mapEngine.RegenerateWalkPaths()
+
+	loading.Progress(0.5)
+
+	b.mapRenderer = d2maprenderer.CreateMapRenderer(b.renderer, b.mapEngine, b.terminal)
+
+	centerX, centerY := b.mapEngine.GetCenterPosition()
+	b.centerX, b.centerY = int(centerX*5), int(centerY*5)
+	b.mapRenderer.MoveCameraTo(b.mapRenderer.WorldToOrtho(centerX, centerY))
+
+	loading.Progress(0.7)
+
+	for i := 0; i < b.monsterCount; i++ {
+		b.spawnMonster()
+	}
+
+	b.refillMissiles()
+
+	log.Printf("benchmark started: %d monsters, %d missiles, %.0fs", b.monsterCount, b.missileCount, b.duration)
+}
+
+// spawnPosition returns a random sub tile position around the map center.
+func (b *Benchmark) spawnPosition() (x, y int) {
+	return b.centerX + b.rand.Intn(benchmarkSpawnRadius*2+1) - benchmarkSpawnRadius,
+		b.centerY + b.rand.Intn(benchmarkSpawnRadius*2+1) - benchmarkSpawnRadius
+}
+
+// spawnMonster adds a monster patrolling back and forth near the map center.
+func (b *Benchmark) spawnMonster() {
+	monstat := d2datadict.MonStats[benchmarkMonsters[b.rand.Intn(len(benchmarkMonsters))]]
+	if monstat == nil {
+		return
+	}
+
+	x, y := b.spawnPosition()
+	npc := d2mapentity.CreateNPC(x, y, monstat, 0)
+	npc.SetPaths([]d2common.Path{
+		{X: x + benchmarkPatrolLength, Y: y},
+		{X: x, Y: y},
+	})
+
+	b.mapEngine.AddEntity(npc)
+}
+
+// refillMissiles fires missiles until the configured number of them are in flight.
+func (b *Benchmark) refillMissiles() {
+	for b.missiles < b.missileCount {
+		if !b.spawnMissile() {
+			return
+		}
+	}
+}
+
+// spawnMissile fires a missile from near the map center in a random direction. A new one is fired when it lands, so
+// the number of missiles in flight stays the same. Returns false if the missile couldn't be created.
+func (b *Benchmark) spawnMissile() bool {
+	record := d2datadict.Missiles[benchmarkMissileID]
+	if record == nil {
+		return false
+	}
+
+	x, y := b.spawnPosition()
+
+	missile, err := d2mapentity.CreateMissile(x, y, record)
+	if err != nil {
+		log.Printf("failed to create benchmark missile: %v", err)
+		return false
+	}
+
+	b.missiles++
+
+	missile.SetRadians(b.rand.Float64()*2*math.Pi, func() {
+		b.mapEngine.RemoveEntity(missile)
+		b.missiles--
+	})
+
+	b.mapEngine.AddEntity(missile)
+
+	return true
+}
+
+// Advance moves the monsters and missiles, timing the tick, and reports the results once the duration is over
+func (b *Benchmark) Advance(elapsed float64) error {
+	if b.isDone {
+		return nil
+	}
+
+	start := time.Now()
+
+	b.mapEngine.Advance(elapsed)
+	b.mapRenderer.Advance(elapsed)
+
+	b.refillMissiles()
+
+	b.stats.addTick(elapsed, time.Since(start))
+
+	if b.stats.elapsed >= b.duration {
+		b.isDone = true
+		log.Printf("benchmark done: %s", &b.stats)
+		b.terminal.OutputInfof("benchmark: %s", &b.stats)
+	}
+
+	return nil
+}
+
+// Render draws the scene, timing the map rendering
+func (b *Benchmark) Render(screen d2interface.Surface) error {
+	start := time.Now()
+
+	b.mapRenderer.Render(screen)
+
+	// The renderer counts the draw calls of the previous frame, which drew the same scene
+	if !b.isDone {
+		b.stats.addFrame(time.Since(start), b.renderer.CurrentDrawCalls())
+	}
+
+	screen.PushTranslation(5, 5)
+	screen.DrawText("Benchmark: %d monsters, %d missiles, %d entities\n%s",
+		b.monsterCount, b.missileCount, len(*b.mapEngine.Entities()), &b.stats)
+	screen.Pop()
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package d2gamescreen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBenchmarkStats(t *testing.T) {
+	var stats benchmarkStats
+
+	if got := stats.String(); got != "no frames rendered" {
+		t.Errorf("empty stats = %q", got)
+	}
+
+	for i := 0; i < 4; i++ {
+		stats.addTick(0.5, 2*time.Millisecond)
+		stats.addFrame(time.Duration(i+1)*time.Millisecond, 100*(i+1))
+	}
+
+	want := "4 frames in 2.0s: 2.0 FPS, tick 2.00ms, render 2.50ms (max 4.00ms), 250 draw calls"
+	if got := stats.String(); got != want {
+		t.Errorf("stats = %q, want %q", got, want)
+	}
+}
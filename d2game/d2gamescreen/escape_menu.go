@@ -2,15 +2,22 @@ package d2gamescreen
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2gui"
 )
 
 // TODO: fix pentagram
 
+// flashIntensitySteps is the number of steps of the flash intensity option, going down in quarters of full strength.
+const flashIntensitySteps = 4
+
 type (
 	layoutID int
 	optionID int
@@ -23,6 +30,8 @@ const (
 	pentSize       = 52
 	menuSize       = 500
 
+	frameCapOff = "OFF"
+
 	// layouts
 	noLayoutID layoutID = iota - 2
 	saveLayoutID
@@ -32,6 +41,7 @@ const (
 	videoOptionsLayoutID
 	automapOptionsLayoutID
 	configureControlsLayoutID
+	accessibilityOptionsLayoutID
 
 	// audio
 	optAudioSoundVolume optionID = iota
@@ -47,12 +57,18 @@ const (
 	optVideoPerspective
 	optVideoGamma
 	optVideoContrast
+	optVideoVsync
+	optVideoFrameCap
 	// automap
 	optAutomapSize
 	optAutomapFade
 	optAutomapCenterWhenCleared
 	optAutomapShowParty
 	optAutomapShowNames
+	// accessibility
+	optAccessibilityScreenFlashes
+	optAccessibilityHitFlashes
+	optAccessibilityFlashIntensity
 )
 
 // EscapeMenu represents the in-game menu that shows up when the esc key is pressed
@@ -130,12 +146,13 @@ func NewEscapeMenu(navigator Navigator, renderer d2interface.Renderer, audioProv
 	}
 
 	m.layouts = []*layout{
-		mainLayoutID:              m.newMainLayout(),
-		optionsLayoutID:           m.newOptionsLayout(),
-		soundOptionsLayoutID:      m.newSoundOptionsLayout(),
-		videoOptionsLayoutID:      m.newVideoOptionsLayout(),
-		automapOptionsLayoutID:    m.newAutomapOptionsLayout(),
-		configureControlsLayoutID: m.newConfigureControlsLayout(),
+		mainLayoutID:                 m.newMainLayout(),
+		optionsLayoutID:              m.newOptionsLayout(),
+		soundOptionsLayoutID:         m.newSoundOptionsLayout(),
+		videoOptionsLayoutID:         m.newVideoOptionsLayout(),
+		automapOptionsLayoutID:       m.newAutomapOptionsLayout(),
+		configureControlsLayoutID:    m.newConfigureControlsLayout(),
+		accessibilityOptionsLayoutID: m.newAccessibilityOptionsLayout(),
 	}
 
 	return m
@@ -151,6 +168,7 @@ func (m *EscapeMenu) newMainLayout() *layout {
 
 func (m *EscapeMenu) newOptionsLayout() *layout {
 	return m.wrapLayout(func(l *layout) {
+		m.addBigSelectionLabel(l, "ACCESSIBILITY OPTIONS", accessibilityOptionsLayoutID)
 		m.addBigSelectionLabel(l, "SOUND OPTIONS", soundOptionsLayoutID)
 		m.addBigSelectionLabel(l, "VIDEO OPTIONS", videoOptionsLayoutID)
 		m.addBigSelectionLabel(l, "AUTOMAP OPTIONS", automapOptionsLayoutID)
@@ -181,10 +199,45 @@ func (m *EscapeMenu) newVideoOptionsLayout() *layout {
 		m.addEnumLabel(l, optVideoPerspective, "PERSPECTIVE", []string{"ON", "OFF"})
 		m.addEnumLabel(l, optVideoGamma, "GAMMA", []string{"TODO"})
 		m.addEnumLabel(l, optVideoContrast, "CONTRAST", []string{"TODO"})
+		m.addVsyncLabel(l)
+		m.addFrameCapLabel(l)
 		m.addPreviousMenuLabel(l)
 	})
 }
 
+func (m *EscapeMenu) addVsyncLabel(l *layout) {
+	current := 1
+	if m.renderer.GetVSyncEnabled() {
+		current = 0
+	}
+
+	m.addEnumLabelAt(l, optVideoVsync, "VSYNC", []string{"ON", "OFF"}, current)
+}
+
+// addFrameCapLabel adds the frame cap option, with the current cap added to the choices if it was configured to
+// another value.
+func (m *EscapeMenu) addFrameCapLabel(l *layout) {
+	values := []string{frameCapOff, "30", "60", "120"}
+	current := 0
+
+	if frameCap := m.renderer.GetFrameCap(); frameCap > 0 {
+		value := strconv.Itoa(frameCap)
+		current = len(values)
+
+		for i := range values {
+			if values[i] == value {
+				current = i
+			}
+		}
+
+		if current == len(values) {
+			values = append(values, value)
+		}
+	}
+
+	m.addEnumLabelAt(l, optVideoFrameCap, "FRAME CAP", values, current)
+}
+
 func (m *EscapeMenu) newAutomapOptionsLayout() *layout {
 	return m.wrapLayout(func(l *layout) {
 		m.addTitle(l, "AUTOMAP OPTIONS")
@@ -197,6 +250,45 @@ func (m *EscapeMenu) newAutomapOptionsLayout() *layout {
 	})
 }
 
+// newAccessibilityOptionsLayout returns the options of the flashing effects, for players sensitive to flashing lights.
+func (m *EscapeMenu) newAccessibilityOptionsLayout() *layout {
+	return m.wrapLayout(func(l *layout) {
+		flashing := &d2config.Config.Flashing
+
+		m.addTitle(l, "ACCESSIBILITY OPTIONS")
+		m.addEnumLabelAt(l, optAccessibilityScreenFlashes, "SCREEN FLASHES", []string{"ON", "OFF"},
+			boolOptionIndex(!flashing.NoScreenFlashes))
+		m.addEnumLabelAt(l, optAccessibilityHitFlashes, "HIT FLASHES", []string{"ON", "OFF"},
+			boolOptionIndex(d2config.Config.HitFlash.IsEnabled()))
+		m.addFlashIntensityLabel(l)
+		m.addPreviousMenuLabel(l)
+	})
+}
+
+// addFlashIntensityLabel adds the flash intensity option, in quarters of the full strength, showing the configured
+// intensity rounded to the closest quarter.
+func (m *EscapeMenu) addFlashIntensityLabel(l *layout) {
+	values := make([]string, 0, flashIntensitySteps+1)
+
+	for step := flashIntensitySteps; step >= 0; step-- {
+		values = append(values, strconv.Itoa(step*100/flashIntensitySteps)+"%")
+	}
+
+	intensity := d2config.Config.Flashing.GetIntensity()
+	current := flashIntensitySteps - int(math.Round(intensity*flashIntensitySteps))
+
+	m.addEnumLabelAt(l, optAccessibilityFlashIntensity, "FLASH INTENSITY", values, current)
+}
+
+// boolOptionIndex returns the index of the value of an ON/OFF option.
+func boolOptionIndex(on bool) int {
+	if on {
+		return 0
+	}
+
+	return 1
+}
+
 func (m *EscapeMenu) newConfigureControlsLayout() *layout {
 	return m.wrapLayout(func(l *layout) {
 		m.addTitle(l, "CONFIGURE CONTROLS")
@@ -284,6 +376,11 @@ func (m *EscapeMenu) addPreviousMenuLabel(l *layout) {
 }
 
 func (m *EscapeMenu) addEnumLabel(l *layout, optID optionID, text string, values []string) {
+	m.addEnumLabelAt(l, optID, text, values, 0)
+}
+
+// addEnumLabelAt adds an option showing the value at the given index of values first.
+func (m *EscapeMenu) addEnumLabelAt(l *layout, optID optionID, text string, values []string, current int) {
 	guiLayout := l.AddLayout(d2gui.PositionTypeHorizontal)
 	layout := &layout{Layout: guiLayout}
 	layout.SetSize(menuSize, 0)
@@ -299,13 +396,13 @@ func (m *EscapeMenu) addEnumLabel(l *layout, optID optionID, text string, values
 		m.onHoverElement(elID)
 	})
 	layout.AddSpacerDynamic()
-	guiLabel, _ := layout.AddLabel(values[0], d2gui.FontStyle30Units)
+	guiLabel, _ := layout.AddLabel(values[current], d2gui.FontStyle30Units)
 	label := &enumLabel{
 		Layout:            guiLayout,
 		textChangingLabel: guiLabel,
 		optionID:          optID,
 		values:            values,
-		current:           0,
+		current:           current,
 		playSound:         m.playSound,
 		updateValue:       m.onUpdateValue,
 	}
@@ -334,7 +431,8 @@ func (m *EscapeMenu) onEscKey() {
 	case soundOptionsLayoutID,
 		videoOptionsLayoutID,
 		automapOptionsLayoutID,
-		configureControlsLayoutID:
+		configureControlsLayoutID,
+		accessibilityOptionsLayoutID:
 		m.setLayout(optionsLayoutID)
 		return
 	}
@@ -384,7 +482,38 @@ func (m *EscapeMenu) onHoverElement(id int) {
 }
 
 func (m *EscapeMenu) onUpdateValue(optID optionID, value string) {
-	fmt.Printf("updating value %d with %s\n", optID, value)
+	switch optID {
+	case optVideoVsync:
+		vsync := value == "ON"
+		m.renderer.SetVSyncEnabled(vsync)
+		d2config.Config.VsyncEnabled = vsync
+	case optVideoFrameCap:
+		frameCap := 0
+		if value != frameCapOff {
+			frameCap, _ = strconv.Atoi(value)
+		}
+
+		m.renderer.SetFrameCap(frameCap)
+		d2config.Config.FpsCap = frameCap
+	case optAccessibilityScreenFlashes:
+		d2config.Config.Flashing.NoScreenFlashes = value == "OFF"
+	case optAccessibilityHitFlashes:
+		d2config.Config.HitFlash.Disabled = value == "OFF"
+	case optAccessibilityFlashIntensity:
+		percent, _ := strconv.Atoi(strings.TrimSuffix(value, "%"))
+
+		if err := d2config.Config.Flashing.SetIntensity(float64(percent) / 100); err != nil {
+			fmt.Printf("could not set the flash intensity: %v\n", err)
+			return
+		}
+	default:
+		fmt.Printf("updating value %d with %s\n", optID, value)
+		return
+	}
+
+	if err := d2config.Config.Save(); err != nil {
+		fmt.Printf("could not save the configuration: %v\n", err)
+	}
 }
 
 func (m *EscapeMenu) setLayout(id layoutID) {
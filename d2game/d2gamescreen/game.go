@@ -1,14 +1,21 @@
 package d2gamescreen
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"image/color"
+	"strings"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2gui"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapgen"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2maprenderer"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2screen"
 	"github.com/OpenDiablo2/OpenDiablo2/d2game/d2player"
@@ -27,6 +34,7 @@ type Game struct {
 	lastRegionType       d2enum.RegionIdType
 	ticksSinceLevelCheck float64
 	escapeMenu           *EscapeMenu
+	travelling           bool // Waiting for the server to move the player to another act
 
 	renderer      d2interface.Renderer
 	inputManager  d2interface.InputManager
@@ -50,6 +58,17 @@ func CreateGame(navigator Navigator, renderer d2interface.Renderer, inputManager
 		terminal:             term,
 	}
 	result.escapeMenu.onLoad()
+	result.mapRenderer.SetCameraOffset(d2config.Config.Camera.OffsetX, d2config.Config.Camera.OffsetY)
+	result.mapRenderer.SetCameraLead(d2config.Config.Camera.Lead)
+	gameClient.SetItemListener(result)
+
+	err := term.BindAction("desynccheck", "sends a hash of the world to the server every <n> ticks to find desyncs, "+
+		"0 to stop", func(ticks int) {
+		gameClient.SetDesyncCheck(ticks)
+	})
+	if err != nil {
+		fmt.Println("failed to bind the desynccheck command")
+	}
 
 	if err := inputManager.BindHandler(result.escapeMenu); err != nil {
 		fmt.Println("failed to add gameplay screen as event handler")
@@ -58,9 +77,16 @@ func CreateGame(navigator Navigator, renderer d2interface.Renderer, inputManager
 	return result
 }
 
-// OnLoad loads the resources for the Gameplay screen
-func (v *Game) OnLoad(_ d2screen.LoadingState) {
+// OnLoad loads the resources for the Gameplay screen, and preloads the animations of the level the player starts in
+func (v *Game) OnLoad(loading d2screen.LoadingState) {
 	v.audioProvider.PlayBGM("")
+	v.preloadLevel(loading.Progress)
+}
+
+// preloadLevel decodes the animations the level will need while the loading screen is shown, so they don't hitch when
+// they first appear
+func (v *Game) preloadLevel(progress func(ratio float64)) {
+	v.gameClient.MapEngine.Preload([]int{d2player.RightClickMissile()}, progress)
 }
 
 // OnUnload releases the resources of Gameplay screen
@@ -73,6 +99,10 @@ func (v *Game) OnUnload() error {
 		return err
 	}
 
+	if err := v.gameClient.SaveLevelState(); err != nil {
+		fmt.Printf("failed to save the level state: %s\n", err)
+	}
+
 	if err := v.gameClient.Close(); err != nil {
 		return err
 	}
@@ -85,12 +115,26 @@ func (v *Game) Render(screen d2interface.Surface) error {
 	if v.gameClient.RegenMap {
 		v.gameClient.RegenMap = false
 		v.mapRenderer.RegenerateTileCache()
+
+		if v.travelling && v.gameControls != nil {
+			v.preloadLevel(nil)
+			v.travelling = false
+			v.lastRegionType = d2enum.RegionNone
+			v.gameControls.OnActEntered(d2mapgen.TownAct(v.gameClient.Region))
+			d2gui.HideLoadScreen()
+		}
 	}
 
 	if err := screen.Clear(color.Black); err != nil {
 		return err
 	}
 
+	if transform, ok := d2asset.PaletteEffect(); ok {
+		screen.PushColorMatrix(transform)
+		defer screen.Pop()
+	}
+
+	v.mapRenderer.SetZoomedOutFilter(d2config.Config.Filtering.ZoomedOutFilter())
 	v.mapRenderer.Render(screen)
 
 	if v.gameControls != nil {
@@ -103,9 +147,21 @@ func (v *Game) Render(screen d2interface.Surface) error {
 // Advance runs the update logic on the Gameplay screen
 func (v *Game) Advance(tickTime float64) error {
 	if (v.escapeMenu != nil && !v.escapeMenu.isOpen) || len(v.gameClient.Players) != 1 {
+		// monsters on screen always act, whatever the configured radius
+		minX, minY, maxX, maxY := v.mapRenderer.Viewport().GetVisibleTileRange()
+		v.gameClient.MapEngine.SetVisibleRect(d2common.Rectangle{
+			Left: minX, Top: minY, Width: maxX - minX + 1, Height: maxY - minY + 1,
+		})
+		v.gameClient.MapEngine.SetActiveRadius(d2config.Config.Simulation.GetActiveRadius())
 		v.gameClient.MapEngine.Advance(tickTime) // TODO: Hack
+		v.gameClient.CheckDesync()
 	}
 
+	// the game controls look up the entities near the player in the index
+	v.mapRenderer.UpdateEntityIndex()
+
+	d2asset.AdvancePaletteEffects(tickTime)
+
 	if v.gameControls != nil {
 		if err := v.gameControls.Advance(tickTime); err != nil {
 			return err
@@ -117,9 +173,9 @@ func (v *Game) Advance(tickTime float64) error {
 		v.ticksSinceLevelCheck = 0
 		if v.localPlayer != nil {
 			tilePosition := v.localPlayer.Position.Tile()
-			tile := v.gameClient.MapEngine.TileAt(int(tilePosition.X()), int(tilePosition.Y()))
+			tile, ok := v.gameClient.MapEngine.TileAt(int(tilePosition.X()), int(tilePosition.Y()))
 
-			if tile != nil {
+			if ok {
 				musicInfo := d2common.GetMusicDef(tile.RegionType)
 				v.audioProvider.PlayBGM(musicInfo.MusicFile)
 
@@ -132,6 +188,7 @@ func (v *Game) Advance(tickTime float64) error {
 				}
 
 				v.lastRegionType = tile.RegionType
+				v.applyAreaLighting()
 			}
 		}
 	}
@@ -148,9 +205,23 @@ func (v *Game) Advance(tickTime float64) error {
 		v.mapRenderer.MoveCameraTo(rx, ry)
 	}
 
+	// leads, shakes, follows and glides the camera
+	v.mapRenderer.Advance(tickTime)
+
 	return nil
 }
 
+// applyAreaLighting tints the map with the ambient light a mod set for the area the player is in, if any.
+func (v *Game) applyAreaLighting() {
+	// TODO: like the zone change text, this uses the region type as the ID of the level
+	if lighting, ok := d2datadict.AreaLighting[int(v.lastRegionType)]; ok {
+		v.mapRenderer.SetAmbientLight(lighting.Tint())
+		return
+	}
+
+	v.mapRenderer.SetAmbientLight(nil)
+}
+
 func (v *Game) bindGameControls() {
 	for _, player := range v.gameClient.Players {
 		if player.Id != v.gameClient.PlayerId {
@@ -158,8 +229,10 @@ func (v *Game) bindGameControls() {
 		}
 
 		v.localPlayer = player
-		v.gameControls = d2player.NewGameControls(v.renderer, player, v.gameClient.MapEngine, v.mapRenderer, v, v.terminal)
+		v.gameControls = d2player.NewGameControls(v.renderer, player, v.gameClient.MapEngine, v.mapRenderer, v, v.terminal,
+			v.audioProvider)
 		v.gameControls.Load()
+		v.gameControls.SetCheatsEnabled(d2config.Config.Cheats && v.gameClient.IsSinglePlayer())
 
 		if err := v.inputManager.BindHandler(v.gameControls); err != nil {
 			fmt.Printf("failed to add gameControls as input handler for player: %s\n", player.Id)
@@ -169,6 +242,22 @@ func (v *Game) bindGameControls() {
 	}
 }
 
+// SetPlayers rescales the life and experience of the monsters on the map for the number of players, monsters spawned
+// later read the setting from the configuration. Only the host can rescale the monsters.
+func (v *Game) SetPlayers(players int) bool {
+	if !v.gameClient.IsHost() {
+		return false
+	}
+
+	for _, entity := range *v.gameClient.MapEngine.Entities() {
+		if npc, ok := entity.(*d2mapentity.NPC); ok {
+			npc.SetPlayers(players)
+		}
+	}
+
+	return true
+}
+
 // OnPlayerMove sends the player move action to the server
 func (v *Game) OnPlayerMove(x, y float64) {
 	worldPosition := v.localPlayer.Position.World()
@@ -189,3 +278,101 @@ func (v *Game) OnPlayerCast(missileID int, targetX, targetY float64) {
 		)
 	}
 }
+
+// OnPlayerDropItem asks the server to drop the item, or the gold, at the given sub tile position
+func (v *Game) OnPlayerDropItem(code string, gold, x, y int) {
+	err := v.gameClient.SendPacketToServer(d2netpacket.CreateDropItemPacket(v.gameClient.PlayerId, code, gold, x, y))
+	if err != nil {
+		fmt.Printf("failed to send DropItem packet to the server, playerId: %s, code: %s, gold: %d\n",
+			v.gameClient.PlayerId, code, gold)
+	}
+}
+
+// OnPlayerPickUpItem asks the server to let the player pick up the item
+func (v *Game) OnPlayerPickUpItem(itemID string) {
+	err := v.gameClient.SendPacketToServer(d2netpacket.CreatePickUpItemPacket(v.gameClient.PlayerId, itemID))
+	if err != nil {
+		fmt.Printf("failed to send PickUpItem packet to the server, playerId: %s, itemId: %s\n",
+			v.gameClient.PlayerId, itemID)
+	}
+}
+
+// OnItemPickedUp puts the item the server let the player pick up in the inventory
+func (v *Game) OnItemPickedUp(item *d2mapentity.GroundItem) {
+	if v.gameControls != nil {
+		v.gameControls.PickUp(item)
+	}
+}
+
+// OnPlayerTravel asks the server to move the player to the town of the act, showing the loading screen until the map
+// is generated
+func (v *Game) OnPlayerTravel(act int) {
+	gameState := v.gameClient.GameState
+	if gameState == nil || v.localPlayer == nil || v.travelling {
+		return
+	}
+
+	cheats := d2config.Config.Cheats && v.gameClient.IsSinglePlayer()
+	if err := gameState.CanTravelTo(act, v.localPlayer.Quests, cheats); err != nil {
+		v.terminal.OutputErrorf("travel: %s", err)
+		return
+	}
+
+	// A local server generates the map before SendPacketToServer returns
+	v.travelling = true
+	d2gui.ShowLoadScreen(0)
+
+	packet := d2netpacket.CreateTravelToActPacket(v.gameClient.PlayerId, act)
+
+	if err := v.gameClient.SendPacketToServer(packet); err != nil {
+		fmt.Printf("failed to send TravelToAct packet to the server, playerId: %s, act: %d\n", v.gameClient.PlayerId, act)
+
+		v.travelling = false
+		d2gui.HideLoadScreen()
+	}
+}
+
+// CrashState describes the current level, map seed and local character for a crash report
+func (v *Game) CrashState() string {
+	var state strings.Builder
+
+	fmt.Fprintf(&state, "Seed: %d\n", v.gameClient.Seed)
+	fmt.Fprintf(&state, "Player ID: %s\n", v.gameClient.PlayerId)
+
+	if v.lastRegionType != d2enum.RegionNone {
+		fmt.Fprintf(&state, "Region: %d\n", v.lastRegionType)
+	}
+
+	if v.localPlayer == nil {
+		return state.String()
+	}
+
+	worldPosition := v.localPlayer.Position.World()
+	fmt.Fprintf(&state, "Character: %s (%s)\n", v.localPlayer.Name(), v.localPlayer.Class)
+	fmt.Fprintf(&state, "Position: %g, %g\n", worldPosition.X(), worldPosition.Y())
+	fmt.Fprintf(&state, "Animation mode: %s\n", v.localPlayer.GetAnimationMode())
+
+	if stats, err := json.MarshalIndent(v.localPlayer.Stats, "", "   "); err == nil {
+		fmt.Fprintf(&state, "Stats: %s\n", stats)
+	}
+
+	return state.String()
+}
+
+// EmergencySave writes the local character's stats and position to their save file
+func (v *Game) EmergencySave() error {
+	gameState := v.gameClient.GameState
+	if gameState == nil || v.localPlayer == nil {
+		return errors.New("no character to save")
+	}
+
+	stats := v.localPlayer.Stats
+	worldPosition := v.localPlayer.Position.World()
+
+	gameState.Stats = &stats
+	gameState.X = worldPosition.X()
+	gameState.Y = worldPosition.Y()
+	gameState.Save()
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package d2gamescreen
+
+import (
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2maprenderer"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2client"
+)
+
+// testTerminal is a terminal which ignores the commands bound to it.
+type testTerminal struct {
+	d2interface.Terminal
+}
+
+func (testTerminal) BindAction(name, description string, action interface{}) error {
+	return nil
+}
+
+func TestGameAdvanceMovesCameraLead(t *testing.T) {
+	assert := testify.New(t)
+
+	config := d2config.Config
+	defer func() { d2config.Config = config }()
+
+	d2config.Config = &d2config.Configuration{}
+	if err := d2asset.Initialize(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	mapEngine := d2mapengine.CreateMapEngine()
+	game := &Game{
+		gameClient:  &d2client.GameClient{MapEngine: mapEngine},
+		mapRenderer: d2maprenderer.CreateMapRenderer(nil, mapEngine, testTerminal{}),
+	}
+	game.mapRenderer.SetCameraLead(0.5)
+
+	// the camera moves right at 100 pixels per second
+	cameraX := 0.0
+
+	for i := 0; i < 60; i++ {
+		game.mapRenderer.MoveCameraTo(cameraX, 0)
+		assert.NoError(game.Advance(0.04))
+
+		cameraX += 4
+	}
+
+	viewX, viewY := game.mapRenderer.ScreenToOrtho(400, 300)
+	assert.InDelta(50, viewX-(cameraX-4), 1, "the view looks half a second of movement ahead of the camera")
+	assert.InDelta(0, viewY, 1)
+}
@@ -310,7 +310,7 @@ func (met *MapEngineTest) OnMouseButtonDown(event d2interface.MouseEvent) bool {
 		px, py := met.mapRenderer.ScreenToWorld(met.lastMouseX, met.lastMouseY)
 		met.selX = int(px)
 		met.selY = int(py)
-		met.selectedTile = met.mapEngine.TileAt(int(px), int(py))
+		met.selectedTile, _ = met.mapEngine.TileAt(int(px), int(py))
 
 		return true
 	}
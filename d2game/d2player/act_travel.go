@@ -0,0 +1,133 @@
+package d2player
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+const (
+	// actTravelRange is how close, in tiles, the hero has to get to a caravan NPC to travel with them.
+	actTravelRange = 3
+
+	// actTextSeconds is how long the name of the act the hero arrived in is shown.
+	actTextSeconds = 2
+)
+
+// actTravelNPCs are the monstats.txt IDs of the NPCs taking the hero to another act, and the act they go to. Acts IV
+// and V are reached through portals, which can't be opened yet, so the travel command stands in for them.
+//nolint:gochecknoglobals // constant lookup table
+var actTravelNPCs = map[string]int{
+	"warriv1": 2,
+	"warriv2": 1,
+	"meshif1": 3,
+	"meshif2": 2,
+}
+
+// highestAct returns the highest act the hero reached. Characters saved before acts could be traveled to have
+// neither act.
+func (v *PlayerState) highestAct() int {
+	act := v.MaxAct
+	if v.Act > act {
+		act = v.Act
+	}
+
+	if act < 1 {
+		act = 1
+	}
+
+	return act
+}
+
+// CanTravelTo returns an error if the hero can't travel to the act: they can go back to any act they reached, and on
+// to the next act once they completed the last quest of the highest one. With cheats they can go to any act.
+func (v *PlayerState) CanTravelTo(act int, quests *d2hero.QuestLog, cheats bool) error {
+	highest := v.highestAct()
+
+	switch {
+	case act < 1 || act > d2hero.NumActs:
+		return fmt.Errorf("there is no act %d", act)
+	case act == v.Act:
+		return errors.New("the hero is already in this act")
+	case act <= highest || cheats:
+		return nil
+	case act == highest+1 && quests.ActCompleted(highest):
+		return nil
+	}
+
+	return fmt.Errorf("complete %s to travel to %s", actNames[highest-1], actNames[act-1])
+}
+
+// EnterAct records the hero is in the act and saves the character.
+func (v *PlayerState) EnterAct(act int) {
+	v.Act = act
+	v.MaxAct = v.highestAct()
+	v.Save()
+}
+
+// travelNPCAt returns the caravan NPC under the mouse, and the act they go to.
+func (g *GameControls) travelNPCAt() (*d2mapentity.NPC, int) {
+	for _, entity := range *g.mapEngine.Entities() {
+		npc, ok := entity.(*d2mapentity.NPC)
+		if !ok {
+			continue
+		}
+
+		if act, ok := actTravelNPCs[npc.MonsterKey()]; ok && g.isHovered(npc) {
+			return npc, act
+		}
+	}
+
+	return nil, 0
+}
+
+// onTravelNPCClick makes the hero walk to the caravan NPC under the mouse, to travel with them once there. Returns
+// false if there is no such NPC.
+func (g *GameControls) onTravelNPCClick() bool {
+	npc, _ := g.travelNPCAt()
+	g.travelNPC = npc
+
+	if npc == nil {
+		return false
+	}
+
+	position := npc.Position.World()
+	g.inputListener.OnPlayerMove(position.X(), position.Y())
+
+	return true
+}
+
+// advanceActTravel travels with the caravan NPC the hero walked to once they are close enough.
+func (g *GameControls) advanceActTravel() {
+	if g.travelNPC == nil {
+		return
+	}
+
+	heroPosition := g.hero.Position.World()
+	if heroPosition.Distance(*g.travelNPC.Position.World()) > actTravelRange {
+		return
+	}
+
+	act := actTravelNPCs[g.travelNPC.MonsterKey()]
+	g.travelNPC = nil
+	g.inputListener.OnPlayerTravel(act)
+}
+
+// OnActEntered tells the hero which act they arrived in, and shows its quests in the quest log. If the act was loaded
+// to go to one of its levels, the hero is moved there.
+func (g *GameControls) OnActEntered(act int) {
+	g.questLogPanel.SelectAct(act)
+	g.SetZoneChangeText(fmt.Sprintf("Entering %s", actNames[act-1]))
+	g.ShowZoneChangeText()
+	g.HideZoneChangeTextAfter(actTextSeconds)
+
+	if level := g.gotoLevel; level != 0 {
+		g.gotoLevel = 0
+
+		if err := g.teleport(level); err != nil {
+			g.terminal.OutputErrorf("goto: %s", err)
+		}
+	}
+}
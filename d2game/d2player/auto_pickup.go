@@ -0,0 +1,119 @@
+package d2player
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+const (
+	// autoPickupRadius is how close, in tiles, an item must be to the player to be picked up automatically.
+	autoPickupRadius = 1.0
+
+	// goldPerLevel is the gold the player can carry per character level, as in Diablo II.
+	goldPerLevel = 10000
+
+	// pickUpRetryDelay is how long to wait for the server to answer a pick up request before asking again.
+	pickUpRetryDelay = time.Second
+)
+
+// equipmentItemTypes are the itemtypes.txt codes of the misc.txt items which are worn, and so are never picked up
+// automatically.
+//nolint:gochecknoglobals // constant lookup table
+var equipmentItemTypes = map[string]bool{"ring": true, "amul": true}
+
+// advanceAutoPickup asks the server to pick up the gold and the items of the configured types near the player when
+// auto-pickup is enabled, as long as they fit in the inventory and aren't allocated to another player.
+func (g *GameControls) advanceAutoPickup() {
+	settings := &d2config.Config.AutoPickup
+	if !settings.Enabled {
+		return
+	}
+
+	heroPosition := g.hero.Position.World()
+
+	for _, entity := range *g.mapEngine.Entities() {
+		item, ok := entity.(*d2mapentity.GroundItem)
+		if !ok || !item.CanPickUp(g.hero.Id) {
+			continue
+		}
+
+		x, y := item.GetPositionF()
+		if math.Hypot(x-heroPosition.X(), y-heroPosition.Y()) > autoPickupRadius {
+			continue
+		}
+
+		if g.canAutoPickup(item, settings.GetItemTypes()) {
+			g.requestPickUp(item)
+		}
+	}
+}
+
+// requestPickUp asks the server to pick up the item, unless it was asked recently.
+func (g *GameControls) requestPickUp(item *d2mapentity.GroundItem) {
+	if requested, ok := g.pickUpRequests[item.ID()]; ok && time.Since(requested) < pickUpRetryDelay {
+		return
+	}
+
+	g.pickUpRequests[item.ID()] = time.Now()
+	g.inputListener.OnPlayerPickUpItem(item.ID())
+}
+
+// PickUp puts the item the server let the player pick up in the inventory. Gold is added to the stats by the
+// client as the server shares it out. An item which no longer fits is dropped again.
+func (g *GameControls) PickUp(item *d2mapentity.GroundItem) {
+	delete(g.pickUpRequests, item.ID())
+
+	if item.IsGold() {
+		return
+	}
+
+	if _, err := g.inventory.grid.Add(inventoryItem(item.Record())); err != nil {
+		log.Printf("failed to put %s in the inventory: %s", item.Name(), err)
+
+		x, y := item.Position.X(), item.Position.Y()
+		g.inputListener.OnPlayerDropItem(item.Record().Code, 0, int(x), int(y))
+	}
+}
+
+// canAutoPickup returns true if the item should be picked up automatically and fits in the inventory.
+func (g *GameControls) canAutoPickup(item *d2mapentity.GroundItem, itemTypes []string) bool {
+	if item.IsGold() {
+		return g.hero.Stats.Gold+item.Gold() <= g.hero.Stats.Level*goldPerLevel
+	}
+
+	return autoPickupMatches(item.Record(), itemTypes) && g.inventory.grid.Fits(inventoryItem(item.Record()))
+}
+
+// autoPickupMatches returns true if the item is one of the types, and not equipment.
+func autoPickupMatches(record *d2datadict.ItemCommonRecord, itemTypes []string) bool {
+	if record.Source != d2enum.InventoryItemTypeItem || equipmentItemTypes[record.Type] {
+		return false
+	}
+
+	for _, itemType := range itemTypes {
+		if itemType == record.Type || itemType == record.Type2 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inventoryItem returns the inventory item of the armor.txt, weapons.txt or misc.txt record.
+func inventoryItem(record *d2datadict.ItemCommonRecord) InventoryItem {
+	switch record.Source {
+	case d2enum.InventoryItemTypeWeapon:
+		return d2inventory.GetWeaponItemByCode(record.Code)
+	case d2enum.InventoryItemTypeArmor:
+		return d2inventory.GetArmorItemByCode(record.Code)
+	default:
+		return d2inventory.GetMiscItemByCode(record.Code)
+	}
+}
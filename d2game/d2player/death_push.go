@@ -0,0 +1,24 @@
+package d2player
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+// pushCorpse slides the corpse of the monster the hero just killed, in the direction the death push setting picks.
+func (g *GameControls) pushCorpse(npc *d2mapentity.NPC) {
+	var dirX, dirY float64
+
+	switch d2config.Config.DeathPush.GetMode() {
+	case d2config.DeathPushFacing:
+		dirX, dirY = npc.FacingVector()
+	case d2config.DeathPushAway:
+		heroPosition := g.hero.Position.World()
+		x, y := npc.GetPositionF()
+		dirX, dirY = x-heroPosition.X(), y-heroPosition.Y()
+	default:
+		return
+	}
+
+	npc.PushCorpse(dirX, dirY, g.mapEngine.IsWalkable)
+}
@@ -3,6 +3,7 @@ package d2player
 import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
 )
 
 // EquipmentSlot represents an equipment slot for a player
@@ -44,3 +45,91 @@ func genEquipmentSlotsMap(record *d2datadict.InventoryRecord) map[d2enum.Equippe
 
 	return slotMap
 }
+
+// equippedItem returns the item of the hero's equipment shown in the given slot, or nil if the slot is empty. The
+// left arm slot holds the weapon and the right arm slot the shield or off hand weapon.
+func equippedItem(equipment *d2inventory.CharacterEquipment, slot d2enum.EquippedSlot) InventoryItem {
+	switch slot {
+	case d2enum.EquippedSlotHead:
+		return armorItem(equipment.Head)
+	case d2enum.EquippedSlotNeck:
+		return miscItem(equipment.Neck)
+	case d2enum.EquippedSlotTorso:
+		return armorItem(equipment.Torso)
+	case d2enum.EquippedSlotLeftArm:
+		return weaponItem(equipment.RightHand)
+	case d2enum.EquippedSlotRightArm:
+		if equipment.LeftHand != nil {
+			return equipment.LeftHand
+		}
+
+		return armorItem(equipment.Shield)
+	case d2enum.EquippedSlotLeftHand:
+		return miscItem(equipment.LeftRing)
+	case d2enum.EquippedSlotRightHand:
+		return miscItem(equipment.RightRing)
+	case d2enum.EquippedSlotGloves:
+		return armorItem(equipment.Gloves)
+	case d2enum.EquippedSlotBelt:
+		return armorItem(equipment.Belt)
+	case d2enum.EquippedSlotLegs:
+		return armorItem(equipment.Feet)
+	}
+
+	return nil
+}
+
+// setEquippedItem puts the item in the hero's equipment for the given slot, a nil item empties the slot.
+func setEquippedItem(equipment *d2inventory.CharacterEquipment, slot d2enum.EquippedSlot, item InventoryItem) {
+	armor, _ := item.(*d2inventory.InventoryItemArmor)
+	weapon, _ := item.(*d2inventory.InventoryItemWeapon)
+	misc, _ := item.(*d2inventory.InventoryItemMisc)
+
+	switch slot {
+	case d2enum.EquippedSlotHead:
+		equipment.Head = armor
+	case d2enum.EquippedSlotNeck:
+		equipment.Neck = misc
+	case d2enum.EquippedSlotTorso:
+		equipment.Torso = armor
+	case d2enum.EquippedSlotLeftArm:
+		equipment.RightHand = weapon
+	case d2enum.EquippedSlotRightArm:
+		equipment.LeftHand, equipment.Shield = weapon, armor
+	case d2enum.EquippedSlotLeftHand:
+		equipment.LeftRing = misc
+	case d2enum.EquippedSlotRightHand:
+		equipment.RightRing = misc
+	case d2enum.EquippedSlotGloves:
+		equipment.Gloves = armor
+	case d2enum.EquippedSlotBelt:
+		equipment.Belt = armor
+	case d2enum.EquippedSlotLegs:
+		equipment.Feet = armor
+	}
+}
+
+// armorItem, weaponItem and miscItem keep empty equipment fields from becoming non-nil InventoryItems.
+func armorItem(item *d2inventory.InventoryItemArmor) InventoryItem {
+	if item == nil {
+		return nil
+	}
+
+	return item
+}
+
+func weaponItem(item *d2inventory.InventoryItemWeapon) InventoryItem {
+	if item == nil {
+		return nil
+	}
+
+	return item
+}
+
+func miscItem(item *d2inventory.InventoryItemMisc) InventoryItem {
+	if item == nil {
+		return nil
+	}
+
+	return item
+}
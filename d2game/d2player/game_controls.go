@@ -1,12 +1,15 @@
 package d2player
 
 import (
+	"errors"
+	"fmt"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2gui"
 	"image"
 	"image/color"
 	"log"
 	"math"
+	"strconv"
 	"time"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
@@ -15,6 +18,7 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2maprenderer"
@@ -30,6 +34,9 @@ type Panel interface {
 
 // ID of missile to create when user right clicks.
 var missileID = 59
+
+// ID of skill to use when user right clicks, noSkillID to use the skill firing missileID.
+var rightSkillID = noSkillID
 var expBarWidth = 120.0
 var staminaBarWidth = 102.0
 var globeHeight = 80
@@ -39,6 +46,15 @@ var leftMenuRect = d2common.Rectangle{Left: 0, Top: 0, Width: 400, Height: 600}
 var rightMenuRect = d2common.Rectangle{Left: 400, Top: 0, Width: 400, Height: 600}
 var bottomMenuRect = d2common.Rectangle{Left: 0, Top: 550, Width: 800, Height: 50}
 
+// Overlays drawn on a skill icon which can't be used right now.
+var skillCooldownColor = color.RGBA{R: 0, G: 0, B: 0, A: 160}
+var skillNoManaColor = color.RGBA{R: 160, G: 0, B: 0, A: 128}
+
+const (
+	runButtonX = 255
+	runButtonY = 570
+)
+
 type GameControls struct {
 	renderer       d2interface.Renderer // TODO: This shouldn't be a dependency
 	hero           *d2mapentity.Player
@@ -46,8 +62,13 @@ type GameControls struct {
 	mapRenderer    *d2maprenderer.MapRenderer
 	inventory      *Inventory
 	heroStatsPanel *HeroStatsPanel
+	questLogPanel  *QuestLogPanel
+	skillTreePanel *SkillTreePanel
 	inputListener  InputCallbackListener
+	audioProvider  d2interface.AudioProvider
+	terminal       d2interface.Terminal
 	FreeCam        bool
+	cheatsEnabled  bool
 	lastMouseX     int
 	lastMouseY     int
 
@@ -62,12 +83,24 @@ type GameControls struct {
 	runButton          d2ui.Button
 	isZoneTextShown    bool
 	actionableRegions  []ActionableRegion
+	hudLayout          hudLayout
+	hudScaling         uiScaling
+	whirlwind          *whirlwindState
+	tabTarget          *d2mapentity.NPC // monster selected with the tab target key
+	lootFilter         *d2config.LootFilter
+	itemLabel          d2ui.Label
+	itemLabelLarge     d2ui.Label
+	pickUpRequests     map[string]time.Time // items the server was asked to let the hero pick up
+	travelNPC          *d2mapentity.NPC     // caravan NPC the hero walks to, to travel to another act
+	gotoLevel          int                  // levels.txt ID of the level the hero goes to once its act is entered
+	itemLabelsShown    bool                 // whether the labels of the items on the ground are drawn
 }
 
 type ActionableType int
 type ActionableRegion struct {
 	ActionableTypeId ActionableType
 	Rect             d2common.Rectangle
+	anchor           hudAnchor
 }
 
 const (
@@ -83,11 +116,22 @@ const (
 )
 
 func NewGameControls(renderer d2interface.Renderer, hero *d2mapentity.Player, mapEngine *d2mapengine.MapEngine,
-	mapRenderer *d2maprenderer.MapRenderer, inputListener InputCallbackListener, term d2interface.Terminal) *GameControls {
+	mapRenderer *d2maprenderer.MapRenderer, inputListener InputCallbackListener, term d2interface.Terminal,
+	audioProvider d2interface.AudioProvider) *GameControls {
 	term.BindAction("setmissile", "set missile id to summon on right click", func(id int) {
 		missileID = id
+		rightSkillID = noSkillID
 	})
 
+	term.BindAction("setskill", "set skill id to use on right click", func(id int) {
+		rightSkillID = id
+	})
+
+	term.BindAction("uiscale", "set the scale of the hud, menus or tooltips, 1 is the original size",
+		func(group string, factor float64) {
+			setUIScale(term, group, factor)
+		})
+
 	zoneLabel := d2ui.CreateLabel(d2resource.Font30, d2resource.PaletteUnits)
 	zoneLabel.Color = color.RGBA{R: 255, G: 88, B: 82, A: 255}
 	zoneLabel.Alignment = d2gui.HorizontalAlignCenter
@@ -120,25 +164,46 @@ func NewGameControls(renderer d2interface.Renderer, hero *d2mapentity.Player, ma
 	
 	inventoryRecord := d2datadict.Inventory[inventoryRecordKey]
 
+	lootFilter, err := d2config.LoadLootFilter()
+	if err != nil {
+		log.Printf("failed to load the loot filter: %v", err)
+	}
+
+	itemLabel := d2ui.CreateLabel(d2resource.FontFormal11, d2resource.PaletteStatic)
+	itemLabel.Alignment = d2gui.HorizontalAlignCenter
+
+	itemLabelLarge := d2ui.CreateLabel(d2resource.Font16, d2resource.PaletteStatic)
+	itemLabelLarge.Alignment = d2gui.HorizontalAlignCenter
+
 	gc := &GameControls{
 		renderer:       renderer,
 		hero:           hero,
 		mapEngine:      mapEngine,
 		inputListener:  inputListener,
+		audioProvider:  audioProvider,
+		terminal:       term,
 		mapRenderer:    mapRenderer,
-		inventory:      NewInventory(inventoryRecord),
-		heroStatsPanel: NewHeroStatsPanel(renderer, hero.Name(), hero.Class, hero.Stats),
+		inventory:      NewInventory(inventoryRecord, hero),
+		heroStatsPanel: NewHeroStatsPanel(renderer, hero.Name(), hero.Class, &hero.Stats, &hero.Equipment),
+		questLogPanel:  NewQuestLogPanel(hero.Quests),
+		skillTreePanel: NewSkillTreePanel(hero.Skills, &hero.Stats),
 		nameLabel:      &nameLabel,
+		lootFilter:     lootFilter,
+		itemLabel:      itemLabel,
+		itemLabelLarge: itemLabelLarge,
+		pickUpRequests: make(map[string]time.Time),
 		zoneChangeText: &zoneLabel,
+		hudLayout:      hudLayout{screenWidth: hudBaseWidth},
+		hudScaling:     hudScalingFor(bottomMenuRect.Top + bottomMenuRect.Height),
 		actionableRegions: []ActionableRegion{
-			{leftSkill, d2common.Rectangle{Left: 115, Top: 550, Width: 50, Height: 50}},
-			{leftSelec, d2common.Rectangle{Left: 206, Top: 563, Width: 30, Height: 30}},
-			{xp, d2common.Rectangle{Left: 253, Top: 560, Width: 125, Height: 5}},
-			{walkRun, d2common.Rectangle{Left: 255, Top: 573, Width: 17, Height: 20}},
-			{stamina, d2common.Rectangle{Left: 273, Top: 573, Width: 105, Height: 20}},
-			{miniPanel, d2common.Rectangle{Left: 393, Top: 563, Width: 12, Height: 23}},
-			{rightSelec, d2common.Rectangle{Left: 562, Top: 563, Width: 30, Height: 30}},
-			{rightSkill, d2common.Rectangle{Left: 634, Top: 550, Width: 50, Height: 50}},
+			{leftSkill, d2common.Rectangle{Left: 115, Top: 550, Width: 50, Height: 50}, hudAnchorLeft},
+			{leftSelec, d2common.Rectangle{Left: 206, Top: 563, Width: 30, Height: 30}, hudAnchorLeft},
+			{xp, d2common.Rectangle{Left: 253, Top: 560, Width: 125, Height: 5}, hudAnchorCenter},
+			{walkRun, d2common.Rectangle{Left: 255, Top: 573, Width: 17, Height: 20}, hudAnchorCenter},
+			{stamina, d2common.Rectangle{Left: 273, Top: 573, Width: 105, Height: 20}, hudAnchorCenter},
+			{miniPanel, d2common.Rectangle{Left: 393, Top: 563, Width: 12, Height: 23}, hudAnchorCenter},
+			{rightSelec, d2common.Rectangle{Left: 562, Top: 563, Width: 30, Height: 30}, hudAnchorRight},
+			{rightSkill, d2common.Rectangle{Left: 634, Top: 550, Width: 50, Height: 50}, hudAnchorRight},
 		},
 	}
 
@@ -146,9 +211,167 @@ func NewGameControls(renderer d2interface.Renderer, hero *d2mapentity.Player, ma
 		gc.FreeCam = !gc.FreeCam
 	})
 
+	term.BindAction("zoom", "zoom the map in or out, 1 is the original size (0.25 to 4)", func(factor float64) {
+		if factor <= 0 {
+			term.OutputErrorf("invalid zoom factor")
+			return
+		}
+
+		gc.mapRenderer.SetCameraZoom(factor)
+		term.OutputInfof("zoom is now: %g", gc.mapRenderer.GetCameraZoom())
+	})
+
+	term.BindAction("noclip", "toggle moving through walls (cheat, single player only)", func() {
+		if !gc.toggleNoClip() {
+			term.OutputErrorf("noclip is only available in single player games with cheats enabled")
+		}
+	})
+
+	term.BindAction("noclipspeed", "set the no-clip movement speed (cheat, single player only)", func(speed float64) {
+		if !gc.cheatsEnabled {
+			term.OutputErrorf("noclipspeed is only available in single player games with cheats enabled")
+			return
+		}
+
+		if speed <= 0 {
+			term.OutputErrorf("invalid no-clip speed")
+			return
+		}
+
+		gc.hero.SetNoClipSpeed(speed)
+		term.OutputInfof("no-clip speed is now: %g", speed)
+	})
+
+	term.BindAction("goto", "teleport to a tile (goto <x> <y>) or level (goto <levelid>) (cheat, single player only)",
+		func(args ...int) {
+			if err := gc.teleport(args...); err != nil {
+				term.OutputErrorf("goto: %s", err)
+			}
+		})
+
+	term.BindAction("lootfilter", "reload the loot filter file (lootfilter reload) or use a preset (default, minimal, strict)",
+		func(preset string) {
+			gc.setLootFilter(term, preset)
+		})
+
+	term.BindAction("travel", "travel to the town of an act (travel <act>), in place of the portals to acts IV and V",
+		func(act int) {
+			gc.inputListener.OnPlayerTravel(act)
+		})
+
+	term.BindAction("dropitem", "drop an item by its code next to the player (cheat, single player only)",
+		func(code string) {
+			if err := gc.dropItem(code, 0); err != nil {
+				term.OutputErrorf("dropitem: %s", err)
+			}
+		})
+
+	term.BindAction("dropgold", "drop a pile of gold next to the player (cheat, single player only)",
+		func(amount int) {
+			if amount <= 0 {
+				term.OutputErrorf("dropgold: invalid amount")
+				return
+			}
+
+			if err := gc.dropItem("", amount); err != nil {
+				term.OutputErrorf("dropgold: %s", err)
+			}
+		})
+
 	return gc
 }
 
+// SetCheatsEnabled allows or forbids debug cheats such as no-clip. Cheats should only be enabled in single player
+// games, disabling them also turns no-clip off.
+func (g *GameControls) SetCheatsEnabled(enabled bool) {
+	g.cheatsEnabled = enabled
+
+	if !enabled && g.hero.IsNoClip() {
+		g.hero.SetNoClip(false)
+	}
+}
+
+// dropItem asks the server to drop the item with the code, or the amount of gold if it isn't 0, a couple of tiles
+// away from the hero.
+func (g *GameControls) dropItem(code string, gold int) error {
+	if !g.cheatsEnabled {
+		return errors.New("only available in single player games with cheats enabled")
+	}
+
+	if gold == 0 && d2datadict.CommonItems[code] == nil {
+		return fmt.Errorf("unknown item code %s", code)
+	}
+
+	x, y := int(g.hero.Position.X())+dropItemOffset, int(g.hero.Position.Y())
+	g.inputListener.OnPlayerDropItem(code, gold, x, y)
+
+	return nil
+}
+
+// teleport moves the hero to the walkable tile closest to the given tile coordinates, or to the center of the given
+// level id when a single argument is passed. Levels of another act are loaded first.
+func (g *GameControls) teleport(args ...int) error {
+	if !g.cheatsEnabled {
+		return errors.New("only available in single player games with cheats enabled")
+	}
+
+	var x, y float64
+
+	switch len(args) {
+	case 1:
+		level := d2datadict.GetLevelDetails(args[0])
+		if level == nil {
+			return fmt.Errorf("unknown level %d", args[0])
+		}
+
+		var found bool
+
+		x, y, found = g.mapEngine.LevelCenter(level.Id)
+		if !found {
+			return g.gotoLevelAct(level)
+		}
+	case 2:
+		x, y = float64(args[0]), float64(args[1])
+	default:
+		return errors.New("usage: goto <x> <y> or goto <levelid>")
+	}
+
+	x, y, found := g.mapEngine.NearestWalkable(x+0.5, y+0.5, maxTeleportSearchRadius)
+	if !found {
+		return errors.New("no walkable tile near the destination")
+	}
+
+	g.hero.SetPosition(x*5, y*5)
+	g.inputListener.OnPlayerMove(x, y)
+
+	return nil
+}
+
+// gotoLevelAct travels to the act of a level which isn't part of the current map, the hero going to the level once the
+// act is entered. Only the towns and the act 1 overworld can be generated so far.
+func (g *GameControls) gotoLevelAct(level *d2datadict.LevelDetailsRecord) error {
+	act := level.Act + 1
+	if act == g.mapEngine.LevelType().Act {
+		return fmt.Errorf("level %s can't be generated yet", level.Name)
+	}
+
+	g.gotoLevel = level.Id
+	g.inputListener.OnPlayerTravel(act)
+
+	return nil
+}
+
+// toggleNoClip turns no-clip movement on or off. Returns false if cheats are disabled.
+func (g *GameControls) toggleNoClip() bool {
+	if !g.cheatsEnabled {
+		return false
+	}
+
+	g.hero.SetNoClip(!g.hero.IsNoClip())
+
+	return true
+}
+
 func (g *GameControls) OnKeyRepeat(event d2interface.KeyEvent) bool {
 	if g.FreeCam {
 		var moveSpeed float64 = 8
@@ -183,30 +406,84 @@ func (g *GameControls) OnKeyRepeat(event d2interface.KeyEvent) bool {
 func (g *GameControls) OnKeyDown(event d2interface.KeyEvent) bool {
 	switch event.Key() {
 	case d2enum.KeyEscape:
-		if g.inventory.IsOpen() || g.heroStatsPanel.IsOpen() {
+		if g.isLeftPanelOpen() || g.isRightPanelOpen() {
 			g.inventory.Close()
+			g.skillTreePanel.Close()
 			g.heroStatsPanel.Close()
+			g.questLogPanel.Close()
 			g.updateLayout()
 			break
 		}
 	case d2enum.KeyI:
+		g.skillTreePanel.Close()
 		g.inventory.Toggle()
 		g.updateLayout()
+	case d2enum.KeyT:
+		g.inventory.Close()
+		g.skillTreePanel.Toggle()
+		g.updateLayout()
 	case d2enum.KeyC:
+		g.questLogPanel.Close()
 		g.heroStatsPanel.Toggle()
 		g.updateLayout()
+	case d2enum.KeyQ:
+		g.heroStatsPanel.Close()
+		g.questLogPanel.Toggle()
+		g.updateLayout()
+	case d2enum.KeyUp:
+		if g.questLogPanel.IsOpen() {
+			g.questLogPanel.SelectPrevious()
+			return true
+		}
+
+		return false
+	case d2enum.KeyDown:
+		if g.questLogPanel.IsOpen() {
+			g.questLogPanel.SelectNext()
+			return true
+		}
+
+		return false
+	case d2enum.KeyTab:
+		g.cycleTarget()
+		return true
 	case d2enum.KeyR:
 		g.onToggleRunButton()
+	case d2enum.KeyN:
+		if event.KeyMod() == d2enum.KeyModControl {
+			g.toggleNoClip()
+		}
+	case d2enum.KeyAlt:
+		if d2config.Config.ItemLabels.GetMode() == d2config.ItemLabelsToggle {
+			g.itemLabelsShown = !g.itemLabelsShown
+		} else {
+			g.itemLabelsShown = true
+		}
 	default:
 		return false
 	}
 	return false
 }
 
+// OnKeyUp hides the labels of the items on the ground when Alt is released, unless they are toggled.
+func (g *GameControls) OnKeyUp(event d2interface.KeyEvent) bool {
+	if event.Key() == d2enum.KeyAlt && d2config.Config.ItemLabels.GetMode() == d2config.ItemLabelsHold {
+		g.itemLabelsShown = false
+	}
+
+	return false
+}
+
 var lastLeftBtnActionTime float64 = 0
 var lastRightBtnActionTime float64 = 0
 var mouseBtnActionsTreshhold = 0.25
 
+// maxTeleportSearchRadius is how far, in sub tiles, a teleport looks for a walkable spot around its destination.
+const maxTeleportSearchRadius = 50
+
+// dropItemOffset is how far, in sub tiles, from the hero dropped items land, out of the auto-pickup radius.
+const dropItemOffset = 10
+
 func (g *GameControls) OnMouseButtonRepeat(event d2interface.MouseEvent) bool {
 	px, py := g.mapRenderer.ScreenToWorld(event.X(), event.Y())
 	px = float64(int(px*10)) / 10.0
@@ -219,32 +496,42 @@ func (g *GameControls) OnMouseButtonRepeat(event d2interface.MouseEvent) bool {
 	lastLeft:= now-lastLeftBtnActionTime
 	lastRight:= now-lastRightBtnActionTime
 	inRect := !g.isInActiveMenusRect(event.X(), event.Y())
-	shouldDoLeft  := lastLeft >= mouseBtnActionsTreshhold
+	shouldDoLeft  := lastLeft >= moveRepeatInterval()
 	shouldDoRight  := lastRight >= mouseBtnActionsTreshhold
 
 	if isLeft && shouldDoLeft && inRect {
 		lastLeftBtnActionTime = now
-		g.inputListener.OnPlayerMove(px, py)
+		g.inputListener.OnPlayerMove(g.moveDestination(px, py))
 		return true
 	}
 
 	if isRight && shouldDoRight && inRect {
 		lastRightBtnActionTime = now
-		g.inputListener.OnPlayerCast(missileID, px, py)
+		g.onSkillInput(skillSlotRight, skillInputHold, px, py)
 		return true
 	}
 
 	return true
 }
 
+func (g *GameControls) OnMouseButtonUp(event d2interface.MouseEvent) bool {
+	if event.Button() == d2enum.MouseButtonRight {
+		px, py := g.mapRenderer.ScreenToWorld(event.X(), event.Y())
+		g.onSkillInput(skillSlotRight, skillInputRelease, px, py)
+	}
+
+	return false
+}
+
 func (g *GameControls) OnMouseMove(event d2interface.MouseMoveEvent) bool {
 	mx, my := event.X(), event.Y()
 	g.lastMouseX = mx
 	g.lastMouseY = my
+	hx, hy := g.hudCursor(mx, my)
 
 	for i := range g.actionableRegions {
 		// Mouse over a game control element
-		if g.actionableRegions[i].Rect.IsInRect(mx, my) {
+		if rect := g.actionableRegionRect(i); rect.IsInRect(hx, hy) {
 			g.onHoverActionable(g.actionableRegions[i].ActionableTypeId)
 		}
 	}
@@ -254,33 +541,62 @@ func (g *GameControls) OnMouseMove(event d2interface.MouseMoveEvent) bool {
 
 func (g *GameControls) OnMouseButtonDown(event d2interface.MouseEvent) bool {
 	mx, my := event.X(), event.Y()
+	hx, hy := g.hudCursor(mx, my)
+
 	for i := range g.actionableRegions {
 		// If click is on a game control element
-		if g.actionableRegions[i].Rect.IsInRect(mx, my) {
+		if rect := g.actionableRegionRect(i); rect.IsInRect(hx, hy) {
 			g.onClickActionable(g.actionableRegions[i].ActionableTypeId)
 			return false
 		}
 	}
 
+	if event.Button() == d2enum.MouseButtonLeft && g.onPanelClick(mx, my) {
+		return true
+	}
+
 	px, py := g.mapRenderer.ScreenToWorld(mx, my)
 	px = float64(int(px*10)) / 10.0
 	py = float64(int(py*10)) / 10.0
 
 	if event.Button() == d2enum.MouseButtonLeft && !g.isInActiveMenusRect(mx, my) {
 		lastLeftBtnActionTime = d2common.Now()
-		g.inputListener.OnPlayerMove(px, py)
+
+		if !g.onTravelNPCClick() {
+			g.inputListener.OnPlayerMove(g.moveDestination(px, py))
+		}
+
 		return true
 	}
 
 	if event.Button() == d2enum.MouseButtonRight && !g.isInActiveMenusRect(mx, my) {
 		lastRightBtnActionTime = d2common.Now()
-		g.inputListener.OnPlayerCast(missileID, px, py)
+		g.onSkillInput(skillSlotRight, skillInputPress, px, py)
 		return true
 	}
 
 	return false
 }
 
+// renderSkillUnavailable darkens the skill icon at the given position if the skill is on cooldown, or tints it red if
+// the player doesn't have the mana to use it.
+func (g *GameControls) renderSkillUnavailable(target d2interface.Surface, skill *d2datadict.SkillRecord, x, y int) {
+	if skill == nil || g.hero.CanUseSkill(skill) || g.hero.IsSkillToggled(skill) {
+		return
+	}
+
+	overlayColor := skillCooldownColor
+	if !g.hero.CanAffordSkill(skill) {
+		overlayColor = skillNoManaColor
+	}
+
+	w, h := g.skillIcon.GetCurrentFrameSize()
+
+	target.PushTranslation(x, y-h)
+	target.DrawRect(w, h, overlayColor)
+	target.Pop()
+}
+
 func (g *GameControls) Load() {
 	animation, _ := d2asset.LoadAnimation(d2resource.GameGlobeOverlap, d2resource.PaletteSky)
 	g.globeSprite, _ = d2ui.LoadSprite(animation)
@@ -301,12 +617,14 @@ func (g *GameControls) Load() {
 
 	g.inventory.Load()
 	g.heroStatsPanel.Load()
+	g.questLogPanel.Load()
+	g.skillTreePanel.Load()
 }
 
 func (g *GameControls) loadUIButtons() {
 	// Run button
 	g.runButton = d2ui.CreateButton(g.renderer, d2ui.ButtonTypeRun, "")
-	g.runButton.SetPosition(255, 570)
+	g.runButton.SetPosition(runButtonX, runButtonY)
 	g.runButton.OnActivated(func() { g.onToggleRunButton() })
 	if g.hero.IsRunToggled() {
 		g.runButton.Toggle()
@@ -323,6 +641,11 @@ func (g *GameControls) onToggleRunButton() {
 
 // ScreenAdvanceHandler
 func (g *GameControls) Advance(elapsed float64) error {
+	g.advanceWhirlwind(elapsed)
+	g.advanceAutoPickup()
+	g.advanceActTravel()
+	g.validateTarget()
+
 	return nil
 }
 
@@ -340,59 +663,104 @@ func (g *GameControls) updateLayout() {
 }
 
 func (g *GameControls) isLeftPanelOpen() bool {
-	// TODO: add quest log panel
-	return g.heroStatsPanel.IsOpen()
+	return g.heroStatsPanel.IsOpen() || g.questLogPanel.IsOpen()
 }
 
 func (g *GameControls) isRightPanelOpen() bool {
-	// TODO: add skills tree panel
-	return g.inventory.IsOpen()
+	return g.inventory.IsOpen() || g.skillTreePanel.IsOpen()
+}
+
+// actionableRegionRect returns the screen area of the actionable region with the given index.
+func (g *GameControls) actionableRegionRect(index int) d2common.Rectangle {
+	region := &g.actionableRegions[index]
+	return g.hudLayout.anchorRect(region.Rect, region.anchor)
+}
+
+// hudCursor converts a screen position to the coordinates of the scaled HUD.
+func (g *GameControls) hudCursor(mx, my int) (x, y int) {
+	return g.hudScaling.toLocal(mx, my)
+}
+
+// onPanelClick passes a left click to the open panels. Returns true if one of them handled it.
+func (g *GameControls) onPanelClick(mx, my int) bool {
+	lx, ly := leftMenuScaling().toLocal(mx, my)
+	rx, ry := rightMenuScaling().toLocal(mx, my)
+
+	return g.heroStatsPanel.OnClick(lx, ly) || g.questLogPanel.OnClick(lx, ly) || g.skillTreePanel.OnClick(rx, ry) ||
+		g.inventory.OnClick(rx, ry)
 }
 
 func (g *GameControls) isInActiveMenusRect(px int, py int) bool {
-	if bottomMenuRect.IsInRect(px, py) {
+	if bottomRect := g.hudLayout.bottomRect(); bottomRect.IsInRect(g.hudCursor(px, py)) {
 		return true
 	}
 
-	if g.isLeftPanelOpen() && leftMenuRect.IsInRect(px, py) {
+	if g.isLeftPanelOpen() && leftMenuRect.IsInRect(leftMenuScaling().toLocal(px, py)) {
 		return true
 	}
 
-	if g.isRightPanelOpen() && rightMenuRect.IsInRect(px, py) {
+	if g.isRightPanelOpen() && rightMenuRect.IsInRect(rightMenuScaling().toLocal(px, py)) {
 		return true
 	}
 
 	return false
 }
 
+// isHovered returns true if the mouse is over the entity.
+func (g *GameControls) isHovered(entity d2interface.MapEntity) bool {
+	entScreenXf, entScreenYf := g.mapRenderer.WorldToScreenF(entity.GetPositionF())
+	entScreenX := int(math.Floor(entScreenXf))
+	entScreenY := int(math.Floor(entScreenYf))
+
+	return ((entScreenX - 20) <= g.lastMouseX) && ((entScreenX + 20) >= g.lastMouseX) &&
+		((entScreenY - 80) <= g.lastMouseY) && (entScreenY >= g.lastMouseY)
+}
+
 // TODO: consider caching the panels to single image that is reused.
 func (g *GameControls) Render(target d2interface.Surface) {
+	g.renderGroundItemLabels(target)
+
+	hasTabTarget := g.renderTabTarget(target)
+
 	for entityIdx := range *g.mapEngine.Entities() {
 		entity := (*g.mapEngine.Entities())[entityIdx]
-		if !entity.Selectable() {
+		if !entity.Selectable() || (hasTabTarget && entity == d2interface.MapEntity(g.tabTarget)) {
 			continue
 		}
 
-		entScreenXf, entScreenYf := g.mapRenderer.WorldToScreenF(entity.GetPositionF())
-		entScreenX := int(math.Floor(entScreenXf))
-		entScreenY := int(math.Floor(entScreenYf))
-
-		if ((entScreenX - 20) <= g.lastMouseX) && ((entScreenX + 20) >= g.lastMouseX) &&
-			((entScreenY - 80) <= g.lastMouseY) && (entScreenY >= g.lastMouseY) {
-			g.nameLabel.SetText(entity.Name())
-			g.nameLabel.SetPosition(entScreenX, entScreenY-100)
-			g.nameLabel.Render(target)
+		if g.isHovered(entity) {
+			entScreenXf, entScreenYf := g.mapRenderer.WorldToScreenF(entity.GetPositionF())
+			g.renderNameplate(target, entity, int(math.Floor(entScreenXf)), int(math.Floor(entScreenYf)))
 			entity.Highlight()
+
 			break
 		}
 	}
 
-	g.inventory.Render(target)
+	g.renderReticle(target, g.skillForSlot(skillSlotRight))
+
+	leftMenuScaling().push(target)
 	g.heroStatsPanel.Render(target)
+	g.questLogPanel.Render(target)
+	target.PopN(uiScalingDepth)
+
+	rightMenuScaling().push(target)
+	g.inventory.Render(target)
+	g.skillTreePanel.Render(target)
+	target.PopN(uiScalingDepth)
 
-	width, height := target.GetSize()
+	screenWidth, screenHeight := target.GetSize()
+	g.hudScaling = hudScalingFor(screenHeight)
+	g.hudScaling.push(target)
+
+	// The HUD is laid out on a screen as wide as the scaled HUD fits in
+	width, height := g.hudScaling.toLocal(screenWidth, screenHeight)
 	offset := 0
 
+	g.hudLayout = hudLayout{screenWidth: width}
+	centerShift := g.hudLayout.shift(hudAnchorCenter)
+	rightShift := g.hudLayout.shift(hudAnchorRight)
+
 	// Left globe holder
 	g.mainPanel.SetCurrentFrame(0)
 	w, _ := g.mainPanel.GetCurrentFrameSize()
@@ -427,6 +795,10 @@ func (g *GameControls) Render(target d2interface.Surface) {
 	g.mainPanel.Render(target)
 	offset += w
 
+	// Center skill bar, centered on the screen
+	leftEnd := offset
+	offset += centerShift
+
 	// Stamina
 	g.mainPanel.SetCurrentFrame(2)
 	w, _ = g.mainPanel.GetCurrentFrameSize()
@@ -435,14 +807,14 @@ func (g *GameControls) Render(target d2interface.Surface) {
 	offset += w
 
 	// Stamina status bar
-	target.PushTranslation(273, 572)
+	target.PushTranslation(273+centerShift, 572)
 	target.PushEffect(d2enum.DrawEffectModulate)
 	staminaPercent := float64(g.hero.Stats.Stamina) / float64(g.hero.Stats.MaxStamina)
 	target.DrawRect(int(staminaPercent*staminaBarWidth), 19, color.RGBA{R: 175, G: 136, B: 72, A: 200})
 	target.PopN(2)
 
 	// Experience status bar
-	target.PushTranslation(256, 561)
+	target.PushTranslation(256+centerShift, 561)
 	expPercent := float64(g.hero.Stats.Experience) / float64(g.hero.Stats.NextLevelExp)
 	target.DrawRect(int(expPercent*expBarWidth), 2, color.RGBA{R: 255, G: 255, B: 255, A: 255})
 	target.Pop()
@@ -460,6 +832,10 @@ func (g *GameControls) Render(target d2interface.Surface) {
 	g.mainPanel.Render(target)
 	offset += w
 
+	// Right orb cluster, anchored to the right edge of the screen
+	centerEnd := offset
+	offset += rightShift - centerShift
+
 	// Right skill selector
 	g.mainPanel.SetCurrentFrame(4)
 	w, _ = g.mainPanel.GetCurrentFrameSize()
@@ -472,6 +848,7 @@ func (g *GameControls) Render(target d2interface.Surface) {
 	w, _ = g.skillIcon.GetCurrentFrameSize()
 	g.skillIcon.SetPosition(offset, height)
 	g.skillIcon.Render(target)
+	g.renderSkillUnavailable(target, g.skillForSlot(skillSlotRight), offset, height)
 	offset += w
 
 	// Right globe holder
@@ -493,11 +870,32 @@ func (g *GameControls) Render(target d2interface.Surface) {
 	g.globeSprite.Render(target)
 	g.globeSprite.Render(target)
 
+	// Fill the gaps between the segments on screens wider than the HUD art
+	if err := renderFiller(target, g.mainPanel, leftEnd, leftEnd+centerShift, height); err != nil {
+		log.Print(err)
+	}
+
+	if err := renderFiller(target, g.mainPanel, centerEnd, centerEnd+rightShift-centerShift, height); err != nil {
+		log.Print(err)
+	}
+
+	target.PopN(uiScalingDepth)
+
+	// The run button is drawn by d2ui, unscaled, at the screen position of its spot on the HUD
+	g.runButton.SetPosition(g.hudScaling.toScreen(runButtonX+centerShift, runButtonY))
+
 	if g.isZoneTextShown {
-		g.zoneChangeText.SetPosition(width/2, height/4)
+		g.zoneChangeText.SetPosition(screenWidth/2, screenHeight/4)
 		g.zoneChangeText.Render(target)
 	}
 
+	if g.hero.IsNoClip() {
+		target.PushTranslation(screenWidth/2-60, 5)
+		target.DrawRect(120, 16, color.RGBA{R: 192, G: 0, B: 0, A: 192})
+		target.DrawText(" NO-CLIP speed:" + strconv.FormatFloat(g.hero.GetNoClipSpeed(), 'f', 1, 64))
+		target.Pop()
+	}
+
 }
 
 func (g *GameControls) SetZoneChangeText(text string) {
@@ -0,0 +1,147 @@
+package d2player
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+const (
+	// groundLabelOffsetY is the height above an item's position at which its label is drawn.
+	groundLabelOffsetY = 20
+
+	// groundLabelPadding is the space between a label's text and the edge of its background.
+	groundLabelPadding = 2
+)
+
+var groundLabelBackgroundColor = color.RGBA{R: 0, G: 0, B: 0, A: 160}
+
+// itemQualityColors are the colors of the item names of each quality, as in Diablo II.
+//nolint:gochecknoglobals // constant lookup table
+var itemQualityColors = map[d2enum.ItemQuality]color.Color{
+	d2enum.ItemQualityLow:      color.RGBA{R: 0x69, G: 0x69, B: 0x69, A: 0xff},
+	d2enum.ItemQualityNormal:   color.White,
+	d2enum.ItemQualitySuperior: color.White,
+	d2enum.ItemQualityMagic:    color.RGBA{R: 0x69, G: 0x69, B: 0xff, A: 0xff},
+	d2enum.ItemQualitySet:      color.RGBA{R: 0x00, G: 0xff, B: 0x00, A: 0xff},
+	d2enum.ItemQualityRare:     color.RGBA{R: 0xff, G: 0xff, B: 0x64, A: 0xff},
+	d2enum.ItemQualityUnique:   color.RGBA{R: 0xc7, G: 0xb3, B: 0x77, A: 0xff},
+	d2enum.ItemQualityCrafted:  color.RGBA{R: 0xff, G: 0xa8, B: 0x00, A: 0xff},
+}
+
+//nolint:gochecknoglobals // constant lookup table
+var itemCategories = map[d2enum.InventoryItemType]string{
+	d2enum.InventoryItemTypeWeapon: "weapon",
+	d2enum.InventoryItemTypeArmor:  "armor",
+	d2enum.InventoryItemTypeItem:   "misc",
+}
+
+// renderGroundItemLabels draws the names of the items on the screen under them, styled by the loot filter, while
+// Alt shows them.
+func (g *GameControls) renderGroundItemLabels(target d2interface.Surface) {
+	if !g.itemLabelsShown {
+		return
+	}
+
+	width, height := target.GetSize()
+
+	for _, entity := range *g.mapEngine.Entities() {
+		item, ok := entity.(*d2mapentity.GroundItem)
+		if !ok {
+			continue
+		}
+
+		screenXf, screenYf := g.mapRenderer.WorldToScreenF(item.GetPositionF())
+		screenX, screenY := int(math.Floor(screenXf)), int(math.Floor(screenYf))-groundLabelOffsetY
+
+		if screenX < 0 || screenY < 0 || screenX > width || screenY > height {
+			continue
+		}
+
+		rule := g.lootFilter.Match(lootItem(item))
+		if rule != nil && rule.Hide {
+			continue
+		}
+
+		g.renderGroundItemLabel(target, item, rule, screenX, screenY)
+	}
+}
+
+// renderGroundItemLabel draws the item's name on a dark background centered on the position, in the color and size
+// of the rule if there is one.
+func (g *GameControls) renderGroundItemLabel(target d2interface.Surface, item *d2mapentity.GroundItem,
+	rule *d2config.LootRule, x, y int) {
+	label := &g.itemLabel
+	label.Color = itemQualityColors[item.Quality()]
+
+	if item.IsGold() {
+		label.Color = color.White
+	}
+
+	if rule != nil {
+		if rule.Large {
+			label = &g.itemLabelLarge
+			label.Color = g.itemLabel.Color
+		}
+
+		if ruleColor, ok := rule.LabelColor(); ok {
+			label.Color = ruleColor
+		}
+	}
+
+	label.SetText(item.Name())
+	labelWidth, labelHeight := label.GetSize()
+
+	target.PushTranslation(x-labelWidth/2-groundLabelPadding, y-groundLabelPadding)
+	target.DrawRect(labelWidth+groundLabelPadding*2, labelHeight+groundLabelPadding*2, groundLabelBackgroundColor)
+	target.Pop()
+
+	label.SetPosition(x, y)
+	label.Render(target)
+}
+
+// lootItem returns what the loot filter rules match the ground item on.
+func lootItem(item *d2mapentity.GroundItem) *d2config.LootItem {
+	record := item.Record()
+
+	return &d2config.LootItem{
+		Codes:    []string{record.Code, record.Type, record.Type2},
+		Category: itemCategories[record.Source],
+		Quality:  item.Quality().String(),
+		Level:    item.Level(),
+	}
+}
+
+// setLootFilter reloads the loot filter from its file, or switches it to a built-in preset.
+func (g *GameControls) setLootFilter(term d2interface.Terminal, preset string) {
+	if preset == "reload" {
+		filter, err := d2config.LoadLootFilter()
+		if err != nil {
+			term.OutputErrorf("failed to load the loot filter: %s", err)
+			return
+		}
+
+		g.lootFilter = filter
+		term.OutputInfof("loot filter reloaded, preset %s with %d rules", filter.Preset, len(filter.Rules))
+
+		return
+	}
+
+	if _, found := d2config.LootFilterPresets[preset]; !found {
+		term.OutputErrorf("unknown loot filter preset %s, expected reload, %s, %s or %s", preset,
+			d2config.LootFilterDefault, d2config.LootFilterMinimal, d2config.LootFilterStrict)
+
+		return
+	}
+
+	g.lootFilter.Preset = preset
+	term.OutputInfof("loot filter preset is now: %s", preset)
+
+	if err := g.lootFilter.Save(); err != nil {
+		term.OutputErrorf("failed to save the loot filter: %s", err)
+	}
+}
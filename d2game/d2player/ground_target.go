@@ -0,0 +1,61 @@
+package d2player
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+const (
+	// groundTargetRange is the farthest, in tiles, a ground targeted skill can be cast.
+	groundTargetRange = 15
+
+	// reticleSize is the width of the targeting reticle, in tiles.
+	reticleSize = 1.0
+)
+
+var reticleColorInRange = color.RGBA{R: 64, G: 255, B: 64, A: 192}
+var reticleColorOutOfRange = color.RGBA{R: 255, G: 64, B: 64, A: 192}
+
+// snapToSubTile returns the center of the sub tile containing the given world position.
+func snapToSubTile(x, y float64) (float64, float64) {
+	return (math.Floor(x*5) + 0.5) / 5, (math.Floor(y*5) + 0.5) / 5
+}
+
+// isInGroundTargetRange returns true if the given world position is close enough to the player to cast a ground
+// targeted skill at.
+func (g *GameControls) isInGroundTargetRange(x, y float64) bool {
+	position := g.hero.Position.World()
+
+	return math.Hypot(x-position.X(), y-position.Y()) <= groundTargetRange
+}
+
+// renderReticle draws the targeting reticle of a ground targeted skill on the sub tile under the cursor, green when it
+// is in range and red when it isn't.
+func (g *GameControls) renderReticle(target d2interface.Surface, skill *d2datadict.SkillRecord) {
+	if skill == nil || !skill.IsGroundTargeted() || g.isInActiveMenusRect(g.lastMouseX, g.lastMouseY) {
+		return
+	}
+
+	x, y := snapToSubTile(g.mapRenderer.ScreenToWorld(g.lastMouseX, g.lastMouseY))
+
+	reticleColor := reticleColorInRange
+	if !g.isInGroundTargetRange(x, y) {
+		reticleColor = reticleColorOutOfRange
+	}
+
+	half := reticleSize / 2
+	corners := [...][2]float64{{x - half, y - half}, {x + half, y - half}, {x + half, y + half}, {x - half, y + half}}
+
+	for i, corner := range corners {
+		next := corners[(i+1)%len(corners)]
+		startX, startY := g.mapRenderer.WorldToScreen(corner[0], corner[1])
+		endX, endY := g.mapRenderer.WorldToScreen(next[0], next[1])
+
+		target.PushTranslation(startX, startY)
+		target.DrawLine(endX-startX, endY-startY, reticleColor)
+		target.Pop()
+	}
+}
@@ -0,0 +1,138 @@
+package d2player
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+)
+
+const (
+	statButtonSize   = 20
+	tooltipOffset    = 12
+	tooltipPadding   = 4
+	unarmedMinDamage = 1
+	unarmedMaxDamage = 2
+)
+
+var statButtonColor = color.RGBA{R: 80, G: 60, B: 30, A: 255}
+var tooltipBackgroundColor = color.RGBA{R: 0, G: 0, B: 0, A: 220}
+
+// attributeRow is an attribute on the character panel with the area of its value and of its stat point button.
+type attributeRow struct {
+	attribute d2hero.HeroAttribute
+	name      string
+	value     d2common.Rectangle
+	button    d2common.Rectangle
+}
+
+//nolint:gochecknoglobals // constant lookup table
+var attributeRows = []attributeRow{
+	{d2hero.AttributeStrength, "Strength", d2common.Rectangle{Left: 150, Top: 142, Width: 50, Height: 20},
+		d2common.Rectangle{Left: 206, Top: 142, Width: statButtonSize, Height: statButtonSize}},
+	{d2hero.AttributeDexterity, "Dexterity", d2common.Rectangle{Left: 150, Top: 202, Width: 50, Height: 20},
+		d2common.Rectangle{Left: 206, Top: 202, Width: statButtonSize, Height: statButtonSize}},
+	{d2hero.AttributeVitality, "Vitality", d2common.Rectangle{Left: 150, Top: 290, Width: 50, Height: 20},
+		d2common.Rectangle{Left: 206, Top: 290, Width: statButtonSize, Height: statButtonSize}},
+	{d2hero.AttributeEnergy, "Energy", d2common.Rectangle{Left: 150, Top: 350, Width: 50, Height: 20},
+		d2common.Rectangle{Left: 206, Top: 350, Width: statButtonSize, Height: statButtonSize}},
+}
+
+var lifeValueRect = d2common.Rectangle{Left: 310, Top: 315, Width: 80, Height: 20}
+var manaValueRect = d2common.Rectangle{Left: 310, Top: 350, Width: 80, Height: 20}
+
+// OnClick spends a stat point if the given screen position is on the button of an attribute. Returns true if a point
+// was spent.
+func (s *HeroStatsPanel) OnClick(x, y int) bool {
+	if !s.isOpen || s.heroState.StatPoints <= 0 {
+		return false
+	}
+
+	for i := range attributeRows {
+		if attributeRows[i].button.IsInRect(x, y) {
+			return s.heroState.AllocateStatPoint(attributeRows[i].attribute, d2datadict.CharStats[s.heroClass])
+		}
+	}
+
+	return false
+}
+
+// renderStatButtons draws the stat point buttons next to the attributes while there are stat points to spend.
+func (s *HeroStatsPanel) renderStatButtons(target d2interface.Surface) {
+	if s.heroState.StatPoints <= 0 {
+		return
+	}
+
+	for _, row := range attributeRows {
+		target.PushTranslation(row.button.Left, row.button.Top)
+		target.DrawRect(row.button.Width, row.button.Height, statButtonColor)
+		target.Pop()
+
+		s.labels.StatButton.SetPosition(row.button.Left+row.button.Width/2, row.button.Top+2)
+		s.labels.StatButton.Render(target)
+	}
+}
+
+// renderTooltip draws the breakdown of the stat under the cursor.
+func (s *HeroStatsPanel) renderTooltip(target d2interface.Surface) {
+	mx, my := leftMenuScaling().cursorPosition()
+	classStats := d2datadict.CharStats[s.heroClass]
+
+	if classStats == nil {
+		return
+	}
+
+	var title string
+
+	var contributions []d2hero.StatContribution
+
+	for i := range attributeRows {
+		if attributeRows[i].value.IsInRect(mx, my) {
+			row := &attributeRows[i]
+			title = fmt.Sprintf("%s: %d", row.name, s.heroState.Attribute(row.attribute))
+			contributions = s.heroState.AttributeBreakdown(row.attribute, classStats)
+		}
+	}
+
+	switch {
+	case lifeValueRect.IsInRect(mx, my):
+		title = fmt.Sprintf("Life: %d", s.heroState.MaxHealth)
+		contributions = s.heroState.LifeBreakdown(classStats)
+	case manaValueRect.IsInRect(mx, my):
+		title = fmt.Sprintf("Mana: %d", s.heroState.MaxMana)
+		contributions = s.heroState.ManaBreakdown(classStats)
+	}
+
+	if title == "" {
+		return
+	}
+
+	lines := []string{title}
+	for _, contribution := range contributions {
+		lines = append(lines, fmt.Sprintf("%s: %+d", contribution.Source, contribution.Value))
+	}
+
+	s.labels.Tooltip.SetText(strings.Join(lines, "\n"))
+	renderTooltipBox(target, &s.labels.Tooltip, mx+tooltipOffset, my+tooltipOffset, leftMenuScaling())
+}
+
+// damage returns the damage range of the weapon in the right hand, raised by the strength and dexterity bonuses of
+// the weapon the same way as Diablo II.
+func (s *HeroStatsPanel) damage() (minDamage, maxDamage int) {
+	if s.equipment == nil || s.equipment.RightHand == nil {
+		return unarmedMinDamage, unarmedMaxDamage
+	}
+
+	weapon := d2datadict.Weapons[s.equipment.RightHand.GetItemCode()]
+	if weapon == nil {
+		return unarmedMinDamage, unarmedMaxDamage
+	}
+
+	bonus := 100 + (s.heroState.Strength*weapon.StrengthBonus+s.heroState.Dexterity*weapon.DexterityBonus)/100
+
+	return weapon.MinDamage * bonus / 100, weapon.MaxDamage * bonus / 100
+}
@@ -1,9 +1,12 @@
 package d2player
 
 import (
-	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2gui"
+	"fmt"
 	"strconv"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2gui"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
@@ -36,6 +39,15 @@ type StatsPanelLabels struct {
 	MaxMana      d2ui.Label
 	MaxStamina   d2ui.Label
 	Stamina      d2ui.Label
+	Damage       d2ui.Label
+	Defense      d2ui.Label
+	FireRes      d2ui.Label
+	ColdRes      d2ui.Label
+	LightningRes d2ui.Label
+	PoisonRes    d2ui.Label
+	StatPoints   d2ui.Label
+	StatButton   d2ui.Label
+	Tooltip      d2ui.Label
 }
 
 var StaticTextLabels = []PanelText{
@@ -46,6 +58,7 @@ var StaticTextLabels = []PanelText{
 	{X: 100, Y: 213, Text: "Dexterity", Font: d2resource.Font6},
 	{X: 100, Y: 300, Text: "Vitality", Font: d2resource.Font6},
 	{X: 100, Y: 360, Text: "Energy", Font: d2resource.Font6},
+	{X: 250, Y: 150, Text: "Damage", Font: d2resource.Font6},
 	{X: 280, Y: 260, Text: "Defense", Font: d2resource.Font6},
 	{X: 280, Y: 300, Text: "Stamina", Font: d2resource.Font6, AlignCenter: true},
 	{X: 280, Y: 322, Text: "Life", Font: d2resource.Font6, AlignCenter: true},
@@ -72,6 +85,7 @@ type HeroStatsPanel struct {
 	frame                *d2ui.Sprite
 	panel                *d2ui.Sprite
 	heroState            *d2hero.HeroStatsState
+	equipment            *d2inventory.CharacterEquipment
 	heroName             string
 	heroClass            d2enum.Hero
 	renderer             d2interface.Renderer
@@ -84,7 +98,7 @@ type HeroStatsPanel struct {
 }
 
 func NewHeroStatsPanel(renderer d2interface.Renderer, heroName string, heroClass d2enum.Hero,
-	heroState d2hero.HeroStatsState) *HeroStatsPanel {
+	heroState *d2hero.HeroStatsState, equipment *d2inventory.CharacterEquipment) *HeroStatsPanel {
 	originX := 0
 	originY := 0
 
@@ -92,7 +106,8 @@ func NewHeroStatsPanel(renderer d2interface.Renderer, heroName string, heroClass
 		renderer:  renderer,
 		originX:   originX,
 		originY:   originY,
-		heroState: &heroState,
+		heroState: heroState,
+		equipment: equipment,
 		heroName:  heroName,
 		heroClass: heroClass,
 		labels:    &StatsPanelLabels{},
@@ -141,6 +156,8 @@ func (s *HeroStatsPanel) Render(target d2interface.Surface) {
 	}
 	target.Render(*s.staticMenuImageCache)
 	s.renderStatValues(target)
+	s.renderStatButtons(target)
+	s.renderTooltip(target)
 }
 
 func (s *HeroStatsPanel) renderStaticMenu(target d2interface.Surface) {
@@ -244,6 +261,18 @@ func (s *HeroStatsPanel) initStatValueLabels() {
 
 	s.labels.MaxMana = s.createStatValueLabel(s.heroState.MaxMana, 330, 355)
 	s.labels.Mana = s.createStatValueLabel(s.heroState.Mana, 370, 355)
+
+	s.labels.Damage = s.createStatValueLabel(0, 355, 147)
+	s.labels.Defense = s.createStatValueLabel(s.heroState.DefenseRating, 370, 255)
+
+	s.labels.FireRes = s.createStatValueLabel(s.heroState.FireResistance, 370, 395)
+	s.labels.ColdRes = s.createStatValueLabel(s.heroState.ColdResistance, 370, 420)
+	s.labels.LightningRes = s.createStatValueLabel(s.heroState.LightningResistance, 370, 445)
+	s.labels.PoisonRes = s.createStatValueLabel(s.heroState.PoisonResistance, 370, 470)
+
+	s.labels.StatPoints = s.createTextLabel(PanelText{X: 100, Y: 395, Font: d2resource.Font6})
+	s.labels.StatButton = s.createTextLabel(PanelText{Text: "+", Font: d2resource.Font16, AlignCenter: true})
+	s.labels.Tooltip = s.createTextLabel(PanelText{Font: d2resource.Font16})
 }
 
 func (s *HeroStatsPanel) renderStatValues(target d2interface.Surface) {
@@ -264,6 +293,21 @@ func (s *HeroStatsPanel) renderStatValues(target d2interface.Surface) {
 
 	s.renderStatValueNum(s.labels.MaxMana, s.heroState.MaxMana, target)
 	s.renderStatValueNum(s.labels.Mana, s.heroState.Mana, target)
+
+	minDamage, maxDamage := s.damage()
+	s.labels.Damage.SetText(fmt.Sprintf("%d-%d", minDamage, maxDamage))
+	s.labels.Damage.Render(target)
+	s.renderStatValueNum(s.labels.Defense, s.heroState.DefenseRating, target)
+
+	s.renderStatValueNum(s.labels.FireRes, s.heroState.FireResistance, target)
+	s.renderStatValueNum(s.labels.ColdRes, s.heroState.ColdResistance, target)
+	s.renderStatValueNum(s.labels.LightningRes, s.heroState.LightningResistance, target)
+	s.renderStatValueNum(s.labels.PoisonRes, s.heroState.PoisonResistance, target)
+
+	if s.heroState.StatPoints > 0 {
+		s.labels.StatPoints.SetText(fmt.Sprintf("Stat Points Remaining: %d", s.heroState.StatPoints))
+		s.labels.StatPoints.Render(target)
+	}
 }
 
 func (s *HeroStatsPanel) renderStatValueNum(label d2ui.Label, value int, target d2interface.Surface) {
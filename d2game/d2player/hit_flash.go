@@ -0,0 +1,18 @@
+package d2player
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+)
+
+// hitFlasher is an entity flashing for a few frames when hit.
+type hitFlasher interface {
+	HitFlash(intensity float64)
+}
+
+// flashHit flashes the entity which was just hit, toned down by the accessibility settings, unless hit flashes are
+// turned off.
+func (g *GameControls) flashHit(entity hitFlasher) {
+	if d2config.Config.HitFlash.IsEnabled() {
+		entity.HitFlash(d2config.Config.Flashing.GetIntensity())
+	}
+}
@@ -0,0 +1,89 @@
+package d2player
+
+import (
+	"image"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+)
+
+const (
+	// hudBaseWidth is the screen width the bottom HUD art was made for
+	hudBaseWidth = 800
+
+	// The gaps between the HUD segments on wide screens are filled with a repeating strip of the stamina panel
+	hudFillerFrame      = 2
+	hudFillerStripLeft  = 0
+	hudFillerStripWidth = 16
+)
+
+// hudAnchor is the part of the screen a segment of the bottom HUD is attached to
+type hudAnchor int
+
+const (
+	hudAnchorLeft hudAnchor = iota
+	hudAnchorCenter
+	hudAnchorRight
+)
+
+// hudLayout positions the three segments of the bottom HUD for a screen width. The left orb cluster is anchored to the
+// left edge, the center skill bar to the middle and the right orb cluster to the right edge of the screen, each laid
+// out exactly as in the original 800 pixel wide HUD.
+type hudLayout struct {
+	screenWidth int
+}
+
+// shift returns how far a segment with the given anchor is moved from its position in the original HUD.
+func (l hudLayout) shift(anchor hudAnchor) int {
+	extraWidth := l.screenWidth - hudBaseWidth
+
+	switch anchor {
+	case hudAnchorCenter:
+		return extraWidth / 2
+	case hudAnchorRight:
+		return extraWidth
+	default:
+		return 0
+	}
+}
+
+// anchorRect moves a rectangle of the original HUD to its position on the current screen.
+func (l hudLayout) anchorRect(rect d2common.Rectangle, anchor hudAnchor) d2common.Rectangle {
+	rect.Left += l.shift(anchor)
+	return rect
+}
+
+// bottomRect returns the screen area covered by the bottom HUD.
+func (l hudLayout) bottomRect() d2common.Rectangle {
+	rect := bottomMenuRect
+	rect.Width = d2common.MaxInt(l.screenWidth, hudBaseWidth)
+
+	return rect
+}
+
+// renderFiller fills the gap from left to right with a repeating strip of the main panel.
+func renderFiller(target d2interface.Surface, panel *d2ui.Sprite, left, right, bottom int) error {
+	if right <= left {
+		return nil
+	}
+
+	if err := panel.SetCurrentFrame(hudFillerFrame); err != nil {
+		return err
+	}
+
+	_, frameHeight := panel.GetCurrentFrameSize()
+
+	for x := left; x < right; x += hudFillerStripWidth {
+		stripWidth := d2common.MinInt(hudFillerStripWidth, right-x)
+		bound := image.Rect(hudFillerStripLeft, 0, hudFillerStripLeft+stripWidth, frameHeight)
+
+		panel.SetPosition(x, bottom)
+
+		if err := panel.RenderSection(target, bound); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
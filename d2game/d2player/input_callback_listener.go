@@ -3,4 +3,7 @@ package d2player
 type InputCallbackListener interface {
 	OnPlayerMove(x, y float64)
 	OnPlayerCast(skillID int, x, y float64)
+	OnPlayerDropItem(code string, gold, x, y int)
+	OnPlayerPickUpItem(itemID string)
+	OnPlayerTravel(act int)
 }
@@ -1,27 +1,41 @@
 package d2player
 
 import (
+	"fmt"
+	"log"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
 )
 
+// The gold amount is shown next to the gold coin at the bottom of the panel.
+const (
+	goldLabelOffsetX = 110
+	goldLabelOffsetY = 455
+)
+
 type Inventory struct {
-	frame   *d2ui.Sprite
-	panel   *d2ui.Sprite
-	grid    *ItemGrid
-	originX int
-	originY int
-	isOpen  bool
+	frame     *d2ui.Sprite
+	panel     *d2ui.Sprite
+	grid      *ItemGrid
+	hero      *d2mapentity.Player
+	heldItem  InventoryItem // item picked up with the cursor
+	goldLabel d2ui.Label
+	originX   int
+	originY   int
+	isOpen    bool
 }
 
-func NewInventory(record *d2datadict.InventoryRecord) *Inventory {
+func NewInventory(record *d2datadict.InventoryRecord, hero *d2mapentity.Player) *Inventory {
 	return &Inventory{
 		grid:    NewItemGrid(record),
+		hero:    hero,
 		originX: record.Panel.Left,
 		// originY: record.Panel.Top,
 		originY: 0, // expansion data has these all offset by +60 ...
@@ -33,15 +47,28 @@ func (g *Inventory) IsOpen() bool {
 }
 
 func (g *Inventory) Toggle() {
-	g.isOpen = !g.isOpen
+	if g.isOpen {
+		g.Close()
+	} else {
+		g.Open()
+	}
 }
 
 func (g *Inventory) Open() {
 	g.isOpen = true
 }
 
+// Close hides the panel, putting the item held by the cursor back into the backpack.
 func (g *Inventory) Close() {
 	g.isOpen = false
+
+	if g.heldItem != nil {
+		if _, err := g.grid.Add(g.heldItem); err != nil {
+			log.Printf("no room in the inventory for held item (%s)", g.heldItem.GetItemCode())
+		}
+
+		g.heldItem = nil
+	}
 }
 
 func (g *Inventory) Load() {
@@ -50,28 +77,83 @@ func (g *Inventory) Load() {
 
 	animation, _ = d2asset.LoadAnimation(d2resource.InventoryCharacterPanel, d2resource.PaletteSky)
 	g.panel, _ = d2ui.LoadSprite(animation)
+
+	g.goldLabel = d2ui.CreateLabel(d2resource.Font16, d2resource.PaletteStatic)
+	g.goldLabel.SetPosition(g.originX+goldLabelOffsetX, g.originY+goldLabelOffsetY)
+
 	items := []InventoryItem{
 		d2inventory.GetWeaponItemByCode("wnd"),
 		d2inventory.GetWeaponItemByCode("sst"),
 		d2inventory.GetWeaponItemByCode("jav"),
 		d2inventory.GetArmorItemByCode("buc"),
 		d2inventory.GetWeaponItemByCode("clb"),
+		d2inventory.GetArmorItemByCode("crn"),
+		d2inventory.GetArmorItemByCode("lgl"),
+		d2inventory.GetMiscItemByCode("rin"),
+		d2inventory.GetMiscItemByCode("amu"),
 		// TODO: Load the player's actual items
 	}
-	g.grid.ChangeEquippedSlot(d2enum.EquippedSlotLeftArm, d2inventory.GetWeaponItemByCode("wnd"))
-	g.grid.ChangeEquippedSlot(d2enum.EquippedSlotRightArm, d2inventory.GetArmorItemByCode("buc"))
-	g.grid.ChangeEquippedSlot(d2enum.EquippedSlotHead, d2inventory.GetArmorItemByCode("crn"))
-	g.grid.ChangeEquippedSlot(d2enum.EquippedSlotTorso, d2inventory.GetArmorItemByCode("plt"))
-	g.grid.ChangeEquippedSlot(d2enum.EquippedSlotLegs, d2inventory.GetArmorItemByCode("vbt"))
-	g.grid.ChangeEquippedSlot(d2enum.EquippedSlotBelt, d2inventory.GetArmorItemByCode("vbl"))
-	g.grid.ChangeEquippedSlot(d2enum.EquippedSlotGloves, d2inventory.GetArmorItemByCode("lgl"))
-	g.grid.ChangeEquippedSlot(d2enum.EquippedSlotLeftHand, d2inventory.GetMiscItemByCode("rin"))
-	g.grid.ChangeEquippedSlot(d2enum.EquippedSlotRightHand, d2inventory.GetMiscItemByCode("rin"))
-	g.grid.ChangeEquippedSlot(d2enum.EquippedSlotNeck, d2inventory.GetMiscItemByCode("amu"))
-	// TODO: Load the player's actual items
+
+	for slot := range g.grid.equipmentSlots {
+		g.grid.ChangeEquippedSlot(slot, equippedItem(&g.hero.Equipment, slot))
+	}
+
 	g.grid.Add(items...)
 }
 
+// OnClick picks up the item at the given screen position, or puts down the item held by the cursor. Items can only
+// be put in equipment slots of their type, replacing and picking up the item equipped there. Returns true if the
+// position is on the panel.
+func (g *Inventory) OnClick(x, y int) bool {
+	if !g.isOpen {
+		return false
+	}
+
+	if slot, ok := g.grid.EquipmentSlotAt(x, y); ok {
+		g.onEquipmentSlotClick(slot)
+	} else if g.grid.IsInGrid(x, y) {
+		g.onGridClick(g.grid.ScreenToSlot(x, y))
+	}
+
+	return rightMenuRect.IsInRect(x, y)
+}
+
+// onEquipmentSlotClick swaps the held item with the item in the equipment slot and updates the hero's equipment.
+func (g *Inventory) onEquipmentSlotClick(slot d2enum.EquippedSlot) {
+	if g.heldItem != nil && !d2inventory.CanEquip(g.heldItem.GetItemCode(), slot) {
+		return
+	}
+
+	equipped := g.grid.GetEquippedSlot(slot)
+	if g.heldItem == nil && equipped == nil {
+		return
+	}
+
+	g.grid.ChangeEquippedSlot(slot, g.heldItem)
+	setEquippedItem(&g.hero.Equipment, slot, g.heldItem)
+	g.heldItem = equipped
+
+	if err := g.hero.UpdateEquipment(); err != nil {
+		log.Printf("failed to update hero equipment: %v", err)
+	}
+}
+
+// onGridClick puts the held item down with its top left corner on the given cell, or picks up the item on the cell.
+func (g *Inventory) onGridClick(slotX, slotY int) {
+	if g.heldItem != nil {
+		if err := g.grid.Set(slotX, slotY, g.heldItem); err == nil {
+			g.heldItem = nil
+		}
+
+		return
+	}
+
+	if item := g.grid.GetSlot(slotX, slotY); item != nil {
+		g.grid.Remove(item)
+		g.heldItem = item
+	}
+}
+
 func (g *Inventory) Render(target d2interface.Surface) {
 	if !g.isOpen {
 		return
@@ -146,4 +228,25 @@ func (g *Inventory) Render(target d2interface.Surface) {
 	g.panel.Render(target)
 
 	g.grid.Render(target)
+
+	g.goldLabel.SetText(fmt.Sprintf("%d", g.hero.Stats.Gold))
+	g.goldLabel.Render(target)
+
+	g.renderHeldItem(target)
+}
+
+// renderHeldItem draws the item held by the cursor, centered on it.
+func (g *Inventory) renderHeldItem(target d2interface.Surface) {
+	if g.heldItem == nil {
+		return
+	}
+
+	itemSprite := g.grid.sprites[g.heldItem.GetItemCode()]
+	if itemSprite == nil {
+		return
+	}
+
+	mx, my := rightMenuScaling().cursorPosition()
+	w, h := itemSprite.GetCurrentFrameSize()
+	g.grid.renderItem(g.heldItem, target, mx-w/2, my+h/2)
 }
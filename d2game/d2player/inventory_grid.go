@@ -86,6 +86,29 @@ func (g *ItemGrid) ChangeEquippedSlot(slot d2enum.EquippedSlot, item InventoryIt
 	g.equipmentSlots[slot] = curItem
 }
 
+// GetEquippedSlot returns the item in the given equipment slot, or nil if it is empty.
+func (g *ItemGrid) GetEquippedSlot(slot d2enum.EquippedSlot) InventoryItem {
+	return g.equipmentSlots[slot].item
+}
+
+// EquipmentSlotAt returns the equipment slot at the given screen position, if there is one.
+func (g *ItemGrid) EquipmentSlotAt(screenX, screenY int) (d2enum.EquippedSlot, bool) {
+	for slot, eq := range g.equipmentSlots {
+		// The slot position is its bottom left corner, like the item sprites drawn in it
+		if screenX >= eq.x && screenX < eq.x+eq.width && screenY >= eq.y-eq.height && screenY < eq.y {
+			return slot, true
+		}
+	}
+
+	return 0, false
+}
+
+// IsInGrid returns true if the screen position is on one of the grid cells.
+func (g *ItemGrid) IsInGrid(screenX, screenY int) bool {
+	return screenX >= g.originX && screenX < g.originX+g.width*g.slotSize &&
+		screenY >= g.originY && screenY < g.originY+g.height*g.slotSize
+}
+
 // Add places a given set of items into the first available slots.
 // Returns a count of the number of items which could be inserted.
 func (g *ItemGrid) Add(items ...InventoryItem) (int, error) {
@@ -142,18 +165,31 @@ func (g *ItemGrid) Load(items ...InventoryItem) {
 // Walk from top left to bottom right until a position large enough to hold the item is found.
 // This is inefficient but simplifies the storage.  At most a hundred or so cells will be looped, so impact is minimal.
 func (g *ItemGrid) add(item InventoryItem) bool {
+	x, y, ok := g.freeSlot(item)
+	if ok {
+		g.set(x, y, item)
+	}
+
+	return ok
+}
+
+// Fits returns true if there is room in the grid for the item.
+func (g *ItemGrid) Fits(item InventoryItem) bool {
+	_, _, ok := g.freeSlot(item)
+	return ok
+}
+
+// freeSlot returns the first position, from top left to bottom right, where the item fits.
+func (g *ItemGrid) freeSlot(item InventoryItem) (slotX, slotY int, ok bool) {
 	for y := 0; y < g.height; y++ {
 		for x := 0; x < g.width; x++ {
-			if !g.canFit(x, y, item) {
-				continue
+			if g.canFit(x, y, item) {
+				return x, y, true
 			}
-
-			g.set(x, y, item)
-			return true
 		}
 	}
 
-	return false
+	return 0, 0, false
 }
 
 // canFit loops over all items to determine if any other items would overlap the given position.
@@ -167,9 +203,9 @@ func (g *ItemGrid) canFit(x int, y int, item InventoryItem) bool {
 		slotX, slotY := compItem.InventoryGridSlot()
 		compWidth, compHeight := compItem.InventoryGridSize()
 
-		if x+insertWidth >= slotX &&
+		if x+insertWidth > slotX &&
 			x < slotX+compWidth &&
-			y+insertHeight >= slotY &&
+			y+insertHeight > slotY &&
 			y < slotY+compHeight {
 			return false
 		}
@@ -0,0 +1,42 @@
+package d2player
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+)
+
+const (
+	// classicMoveRepeat is the seconds between the destinations sent while the left button is held, in classic mode.
+	classicMoveRepeat = 0.25
+
+	// responsiveMoveRepeat is the seconds between the destinations sent while the left button is held, in responsive
+	// mode.
+	responsiveMoveRepeat = 0.05
+
+	// responsiveDestinationSearch is how far, in sub tiles, responsive mode looks for a walkable spot around a click on
+	// an obstacle.
+	responsiveDestinationSearch = 10
+)
+
+// moveRepeatInterval returns the seconds between the destinations sent while the left button is held.
+func moveRepeatInterval() float64 {
+	if d2config.Config.Movement.GetMode() == d2config.MovementResponsive {
+		return responsiveMoveRepeat
+	}
+
+	return classicMoveRepeat
+}
+
+// moveDestination returns where the hero walks to when the map is clicked at the world position. In classic mode,
+// that is the click itself, which the path stops short of when it is on an obstacle. In responsive mode, a click on an
+// obstacle walks the hero to the nearest walkable spot instead.
+func (g *GameControls) moveDestination(x, y float64) (destX, destY float64) {
+	if d2config.Config.Movement.GetMode() != d2config.MovementResponsive || g.mapEngine.IsWalkable(x, y) {
+		return x, y
+	}
+
+	if walkableX, walkableY, found := g.mapEngine.NearestWalkable(x, y, responsiveDestinationSearch); found {
+		return walkableX, walkableY
+	}
+
+	return x, y
+}
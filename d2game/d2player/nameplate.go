@@ -0,0 +1,70 @@
+package d2player
+
+import (
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+const (
+	// nameplateOffsetY is the height above an entity's feet at which its name is drawn, clearing the sprite's top.
+	nameplateOffsetY = 100
+
+	healthBarWidth  = 60
+	healthBarHeight = 4
+	healthBarGap    = 2
+
+	bossHealthBarWidth  = 200
+	bossHealthBarHeight = 10
+	bossHealthBarTop    = 30
+)
+
+var healthBarBackgroundColor = color.RGBA{R: 0, G: 0, B: 0, A: 160}
+var healthBarColor = color.RGBA{R: 192, G: 0, B: 0, A: 224}
+
+// renderNameplate draws the name of the hovered entity above it. Monsters get a health bar below their name, except
+// for bosses whose name and health bar are anchored to the top of the screen.
+func (g *GameControls) renderNameplate(target d2interface.Surface, entity d2interface.MapEntity, screenX,
+	screenY int) {
+	g.nameLabel.SetText(entity.Name())
+
+	npc, isNPC := entity.(*d2mapentity.NPC)
+	if !isNPC || !npc.IsHostile() {
+		g.nameLabel.SetPosition(screenX, screenY-nameplateOffsetY)
+		g.nameLabel.Render(target)
+
+		return
+	}
+
+	width, _ := target.GetSize()
+
+	if npc.IsBoss() {
+		g.nameLabel.SetPosition(width/2, bossHealthBarTop-bossHealthBarHeight-healthBarGap*2)
+		g.nameLabel.Render(target)
+		renderHealthBar(target, npc, width/2-bossHealthBarWidth/2, bossHealthBarTop, bossHealthBarWidth,
+			bossHealthBarHeight)
+
+		return
+	}
+
+	g.nameLabel.SetPosition(screenX, screenY-nameplateOffsetY)
+	g.nameLabel.Render(target)
+
+	_, labelHeight := g.nameLabel.GetSize()
+	renderHealthBar(target, npc, screenX-healthBarWidth/2, screenY-nameplateOffsetY+labelHeight+healthBarGap,
+		healthBarWidth, healthBarHeight)
+}
+
+// renderHealthBar draws the remaining life of the NPC as a bar with its top left corner at the given position.
+func renderHealthBar(target d2interface.Surface, npc *d2mapentity.NPC, x, y, width, height int) {
+	if npc.MaxLife() <= 0 {
+		return
+	}
+
+	target.PushTranslation(x, y)
+	defer target.Pop()
+
+	target.DrawRect(width, height, healthBarBackgroundColor)
+	target.DrawRect(width*npc.Life()/npc.MaxLife(), height, healthBarColor)
+}
@@ -20,6 +20,7 @@ type PlayerState struct {
 	HeroType  d2enum.Hero                    `json:"heroType"`
 	HeroLevel int                            `json:"heroLevel"`
 	Act       int                            `json:"act"`
+	MaxAct    int                            `json:"maxAct"`
 	FilePath  string                         `json:"-"`
 	Equipment d2inventory.CharacterEquipment `json:"equipment"`
 	Stats     *d2hero.HeroStatsState          `json:"stats"`
@@ -84,6 +85,7 @@ func CreatePlayerState(heroName string, hero d2enum.Hero, classStats *d2datadict
 		HeroName:  heroName,
 		HeroType:  hero,
 		Act:       1,
+		MaxAct:    1,
 		Stats: d2hero.CreateHeroStatsState(hero, classStats),
 		Equipment: d2inventory.HeroObjects[hero],
 		FilePath:  "",
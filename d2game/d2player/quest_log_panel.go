@@ -0,0 +1,238 @@
+package d2player
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2gui"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+)
+
+const (
+	questLogX           = 80
+	questLogY           = 64
+	questLogWidth       = 240
+	questLogHeight      = 380
+	questLogRowHeight   = 16
+	questLogVisibleRows = 14
+	questLogDetailY     = questLogY + 40 + questLogRowHeight*questLogVisibleRows + 10
+)
+
+var questLogBackgroundColor = color.RGBA{R: 0, G: 0, B: 0, A: 200}
+
+//nolint:gochecknoglobals // constant lookup table
+var actNames = [d2hero.NumActs]string{"Act I", "Act II", "Act III", "Act IV", "Act V"}
+
+// QuestLogPanel lists the quests the hero has started or completed, grouped by act, and shows the current objective of
+// the selected quest.
+type QuestLogPanel struct {
+	frame      *d2ui.Sprite
+	questLog   *d2hero.QuestLog
+	title      d2ui.Label
+	detail     d2ui.Label
+	list       d2ui.ScrollList
+	rowToQuest map[int]*d2hero.QuestState
+	isOpen     bool
+	hasLoaded  bool
+}
+
+// NewQuestLogPanel creates a quest log panel showing the given quest log.
+func NewQuestLogPanel(questLog *d2hero.QuestLog) *QuestLogPanel {
+	return &QuestLogPanel{
+		questLog:   questLog,
+		rowToQuest: make(map[int]*d2hero.QuestState),
+	}
+}
+
+// Load loads the panel graphics and fonts.
+func (s *QuestLogPanel) Load() {
+	animation, _ := d2asset.LoadAnimation(d2resource.Frame, d2resource.PaletteSky)
+	s.frame, _ = d2ui.LoadSprite(animation)
+
+	s.title = d2ui.CreateLabel(d2resource.Font30, d2resource.PaletteUnits)
+	s.title.Alignment = d2gui.HorizontalAlignCenter
+	s.title.SetText("Quests")
+	s.title.SetPosition(questLogX+questLogWidth/2, questLogY+4)
+
+	s.detail = d2ui.CreateLabel(d2resource.Font16, d2resource.PaletteStatic)
+	s.detail.SetPosition(questLogX+10, questLogDetailY)
+
+	s.list = d2ui.CreateScrollList(questLogX+10, questLogY+40, questLogWidth-20, questLogRowHeight,
+		questLogVisibleRows, d2resource.Font16, d2resource.PaletteStatic)
+	s.hasLoaded = true
+}
+
+// IsOpen returns true if the panel is shown.
+func (s *QuestLogPanel) IsOpen() bool {
+	return s.isOpen
+}
+
+// Toggle opens the panel if it is closed and closes it if it is open.
+func (s *QuestLogPanel) Toggle() {
+	if s.isOpen {
+		s.Close()
+	} else {
+		s.Open()
+	}
+}
+
+// Open shows the panel with the current state of the quest log.
+func (s *QuestLogPanel) Open() {
+	s.isOpen = true
+	s.refresh()
+}
+
+// Close hides the panel.
+func (s *QuestLogPanel) Close() {
+	s.isOpen = false
+}
+
+// refresh rebuilds the list from the quest log, keeping the selected quest selected.
+func (s *QuestLogPanel) refresh() {
+	if !s.hasLoaded {
+		return
+	}
+
+	selected := s.rowToQuest[s.list.GetSelected()]
+
+	s.list.Clear()
+	s.rowToQuest = make(map[int]*d2hero.QuestState)
+
+	act := 0
+
+	for _, quest := range s.questLog.Visible() {
+		if quest.Act != act {
+			act = quest.Act
+			s.list.AddHeader(actNames[act-1])
+		}
+
+		title := d2common.TranslateString(quest.TitleKey())
+		if quest.Status == d2hero.QuestCompleted {
+			title = fmt.Sprintf("%s (completed)", title)
+		}
+
+		row := s.list.AddItem("  " + title)
+		s.rowToQuest[row] = quest
+
+		if selected == nil || (selected.Act == quest.Act && selected.Quest == quest.Quest) {
+			selected = quest
+			s.list.SetSelected(row)
+		}
+	}
+}
+
+// SelectAct selects the first quest of the act shown in the list.
+func (s *QuestLogPanel) SelectAct(act int) {
+	s.refresh()
+
+	first := -1
+
+	for row, quest := range s.rowToQuest {
+		if quest.Act == act && (first < 0 || row < first) {
+			first = row
+		}
+	}
+
+	if first >= 0 {
+		s.list.SetSelected(first)
+	}
+}
+
+// SelectNext selects the next quest in the list.
+func (s *QuestLogPanel) SelectNext() {
+	s.list.SelectNext()
+}
+
+// SelectPrevious selects the previous quest in the list.
+func (s *QuestLogPanel) SelectPrevious() {
+	s.list.SelectPrevious()
+}
+
+// OnClick selects the quest at the given screen position. Returns true if the position is on the panel.
+func (s *QuestLogPanel) OnClick(x, y int) bool {
+	if !s.isOpen {
+		return false
+	}
+
+	s.list.SetSelected(s.list.RowAt(x, y))
+
+	return x >= questLogX && x < questLogX+questLogWidth && y >= questLogY && y < questLogY+questLogHeight
+}
+
+// Render draws the panel, if it is open.
+func (s *QuestLogPanel) Render(target d2interface.Surface) {
+	if !s.isOpen {
+		return
+	}
+
+	s.renderFrame(target)
+
+	target.PushTranslation(questLogX, questLogY)
+	target.DrawRect(questLogWidth, questLogHeight, questLogBackgroundColor)
+	target.Pop()
+
+	s.title.Render(target)
+
+	quest, hasSelection := s.rowToQuest[s.list.GetSelected()]
+	if !hasSelection {
+		s.detail.SetText("No quests yet")
+		s.detail.Render(target)
+
+		return
+	}
+
+	s.list.Render(target)
+
+	detail := d2common.TranslateString(quest.ObjectiveKey())
+	if quest.Status == d2hero.QuestCompleted {
+		detail = "Quest completed"
+	}
+
+	s.detail.SetText(detail)
+	s.detail.Render(target)
+}
+
+// renderFrame draws the left half of the panel border, the same way as the character panel.
+func (s *QuestLogPanel) renderFrame(target d2interface.Surface) {
+	x, y := 0, 0
+
+	// Top left
+	s.frame.SetCurrentFrame(0)
+	w, h := s.frame.GetCurrentFrameSize()
+	s.frame.SetPosition(x, y+h)
+	s.frame.Render(target)
+	x += w
+	y += h
+
+	// Top right
+	s.frame.SetCurrentFrame(1)
+	_, h = s.frame.GetCurrentFrameSize()
+	s.frame.SetPosition(x, h)
+	s.frame.Render(target)
+	x = 0
+
+	// Right
+	s.frame.SetCurrentFrame(2)
+	_, h = s.frame.GetCurrentFrameSize()
+	s.frame.SetPosition(x, y+h)
+	s.frame.Render(target)
+	y += h
+
+	// Bottom left
+	s.frame.SetCurrentFrame(3)
+	w, h = s.frame.GetCurrentFrameSize()
+	s.frame.SetPosition(x, y+h)
+	s.frame.Render(target)
+	x += w
+
+	// Bottom right
+	s.frame.SetCurrentFrame(4)
+	_, h = s.frame.GetCurrentFrameSize()
+	s.frame.SetPosition(x, y+h)
+	s.frame.Render(target)
+}
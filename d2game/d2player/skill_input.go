@@ -0,0 +1,114 @@
+package d2player
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// skillSlot is one of the two skills the player can use with the mouse buttons
+type skillSlot int
+
+const (
+	skillSlotLeft skillSlot = iota
+	skillSlotRight
+)
+
+// skillInputPhase is the state of the input bound to a skill slot
+type skillInputPhase int
+
+const (
+	skillInputPress skillInputPhase = iota
+	skillInputHold
+	skillInputRelease
+)
+
+// noSkillID is the skill id of a skill slot without a skill assigned.
+const noSkillID = -1
+
+// RightClickMissile returns the ID of the missile fired by right clicking without a skill set.
+func RightClickMissile() int {
+	return missileID
+}
+
+// skillForSlot returns the skill in the given slot, or nil if the slot has no skill. The left slot walks until skills
+// can be assigned to it, the right slot holds the skill set with the setskill command, or else the skill which fires
+// the right click missile.
+func (g *GameControls) skillForSlot(slot skillSlot) *d2datadict.SkillRecord {
+	if slot != skillSlotRight {
+		return nil
+	}
+
+	if rightSkillID != noSkillID {
+		return d2datadict.SkillDetails[rightSkillID]
+	}
+
+	missile, ok := d2datadict.Missiles[missileID]
+	if !ok {
+		return nil
+	}
+
+	return d2datadict.GetSkillByMissile(missile.Name)
+}
+
+// onSkillInput uses the skill in the slot according to its activation mode: instant skills are used on every press
+// and hold, channeled skills are held until the input is released, and toggled skills switch on and off with each
+// press. Missiles which aren't fired by a skill are cast for free. Skills are aimed at the tab target, if one is
+// selected.
+func (g *GameControls) onSkillInput(slot skillSlot, phase skillInputPhase, px, py float64) {
+	skill := g.skillForSlot(slot)
+	px, py = g.targetPosition(px, py)
+
+	if skill == nil {
+		if slot == skillSlotRight && phase != skillInputRelease {
+			g.inputListener.OnPlayerCast(missileID, px, py)
+		}
+
+		return
+	}
+
+	if skill.IsGroundTargeted() {
+		px, py = snapToSubTile(px, py)
+
+		if phase != skillInputRelease && !g.isInGroundTargetRange(px, py) {
+			return
+		}
+	}
+
+	if isWhirlwindSkill(skill) {
+		g.onWhirlwindInput(skill, phase, px, py)
+		return
+	}
+
+	switch skill.ActivationMode() {
+	case d2enum.SkillActivationToggle:
+		if phase == skillInputPress {
+			g.hero.ToggleSkill(skill)
+		}
+	case d2enum.SkillActivationChanneled:
+		switch phase {
+		case skillInputPress:
+			if g.hero.StartChanneling(skill) {
+				g.inputListener.OnPlayerCast(missileID, px, py)
+			}
+		case skillInputHold:
+			if g.hero.IsChanneling(skill) {
+				g.inputListener.OnPlayerCast(missileID, px, py)
+			}
+		case skillInputRelease:
+			g.hero.StopChanneling()
+		}
+	default:
+		if phase == skillInputRelease {
+			return
+		}
+
+		if isTeleportSkill(skill) {
+			g.castTeleport(skill, px, py)
+			return
+		}
+
+		if g.hero.UseSkill(skill) {
+			g.inputListener.OnPlayerCast(missileID, px, py)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package d2player
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2gui"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+)
+
+const (
+	skillTreeX = 400
+	skillTreeY = 64
+
+	// Skill icons are laid out on a grid of 3 columns and 6 rows, placed by skilldesc.txt.
+	skillIconX     = skillTreeX + 15
+	skillIconY     = skillTreeY + 59
+	skillIconDistX = 69
+	skillIconDistY = 68
+	skillIconSize  = 48
+
+	// The tab buttons are stacked on the right edge of the panel.
+	skillTabX      = skillTreeX + 322
+	skillTabY      = skillTreeY + 20
+	skillTabWidth  = 78
+	skillTabHeight = 108
+
+	// skillPanelSegments is the number of frames, in a 2x2 grid, of each tab in the skill tree background file.
+	skillPanelSegments = 4
+)
+
+var skillUnavailableColor = color.RGBA{R: 0, G: 0, B: 0, A: 160}
+
+// skillTreeResources are the background and icon files of each class' skill tree.
+//nolint:gochecknoglobals // constant lookup table
+var skillTreeResources = map[d2enum.Hero][2]string{
+	d2enum.HeroAmazon:      {d2resource.SkillsPanelAmazon, d2resource.AmazonSkills},
+	d2enum.HeroAssassin:    {d2resource.SkillsPanelAssassin, d2resource.AssassinSkills},
+	d2enum.HeroBarbarian:   {d2resource.SkillsPanelBarbarian, d2resource.BarbarianSkills},
+	d2enum.HeroDruid:       {d2resource.SkillsPanelDruid, d2resource.DruidSkills},
+	d2enum.HeroNecromancer: {d2resource.SkillsPanelNecromancer, d2resource.NecromancerSkills},
+	d2enum.HeroPaladin:     {d2resource.SkillsPanelPaladin, d2resource.PaladinSkills},
+	d2enum.HeroSorceress:   {d2resource.SkillsPanelSorcerer, d2resource.SorcererSkills},
+}
+
+// SkillTreePanel shows the three skill tree tabs of the hero's class and lets the player spend skill points.
+type SkillTreePanel struct {
+	background *d2ui.Sprite
+	icons      *d2ui.Sprite
+	skillTree  *d2hero.SkillTree
+	heroStats  *d2hero.HeroStatsState
+	tab        int
+	levelLabel d2ui.Label
+	pointLabel d2ui.Label
+	tooltip    d2ui.Label
+	isOpen     bool
+}
+
+// NewSkillTreePanel creates a skill tree panel for the given skill tree. The hero stats provide the character level
+// skill requirements are checked against.
+func NewSkillTreePanel(skillTree *d2hero.SkillTree, heroStats *d2hero.HeroStatsState) *SkillTreePanel {
+	return &SkillTreePanel{skillTree: skillTree, heroStats: heroStats, tab: 1}
+}
+
+// Load loads the class skill tree graphics and fonts.
+func (s *SkillTreePanel) Load() {
+	resources := skillTreeResources[s.skillTree.Class]
+
+	if animation, err := d2asset.LoadAnimation(resources[0], d2resource.PaletteSky); err == nil {
+		s.background, _ = d2ui.LoadSprite(animation)
+	}
+
+	if animation, err := d2asset.LoadAnimation(resources[1], d2resource.PaletteSky); err == nil {
+		s.icons, _ = d2ui.LoadSprite(animation)
+	}
+
+	s.levelLabel = d2ui.CreateLabel(d2resource.Font16, d2resource.PaletteStatic)
+	s.levelLabel.Alignment = d2gui.HorizontalAlignCenter
+	s.pointLabel = d2ui.CreateLabel(d2resource.Font16, d2resource.PaletteStatic)
+	s.pointLabel.SetPosition(skillTabX+4, skillTreeY+4)
+	s.tooltip = d2ui.CreateLabel(d2resource.Font16, d2resource.PaletteStatic)
+}
+
+// IsOpen returns true if the panel is shown.
+func (s *SkillTreePanel) IsOpen() bool {
+	return s.isOpen
+}
+
+// Toggle opens the panel if it is closed and closes it if it is open.
+func (s *SkillTreePanel) Toggle() {
+	s.isOpen = !s.isOpen
+}
+
+// Open shows the panel.
+func (s *SkillTreePanel) Open() {
+	s.isOpen = true
+}
+
+// Close hides the panel.
+func (s *SkillTreePanel) Close() {
+	s.isOpen = false
+}
+
+// skillIconRect returns the screen area of the icon of a skill in the current tab.
+func skillIconRect(desc *d2datadict.SkillDescriptionRecord) d2common.Rectangle {
+	return d2common.Rectangle{
+		Left:   skillIconX + (desc.SkillColumn-1)*skillIconDistX,
+		Top:    skillIconY + (desc.SkillRow-1)*skillIconDistY,
+		Width:  skillIconSize,
+		Height: skillIconSize,
+	}
+}
+
+// skillTabRect returns the screen area of the button of the given tab, 1 to 3.
+func skillTabRect(tab int) d2common.Rectangle {
+	return d2common.Rectangle{
+		Left:   skillTabX,
+		Top:    skillTabY + (tab-1)*skillTabHeight,
+		Width:  skillTabWidth,
+		Height: skillTabHeight,
+	}
+}
+
+// skillAt returns the skill of the current tab at the given screen position, or nil if there is none.
+func (s *SkillTreePanel) skillAt(x, y int) *d2datadict.SkillRecord {
+	for _, skill := range s.skillTree.Skills(s.tab) {
+		if rect := skillIconRect(d2hero.SkillDescription(skill)); rect.IsInRect(x, y) {
+			return skill
+		}
+	}
+
+	return nil
+}
+
+// OnClick switches tabs, or spends a skill point on the skill at the given screen position if the skill tree allows
+// it. Returns true if the position is on the panel.
+func (s *SkillTreePanel) OnClick(x, y int) bool {
+	if !s.isOpen {
+		return false
+	}
+
+	for tab := 1; tab <= d2hero.NumSkillTabs; tab++ {
+		if rect := skillTabRect(tab); rect.IsInRect(x, y) {
+			s.tab = tab
+			return true
+		}
+	}
+
+	if skill := s.skillAt(x, y); skill != nil {
+		_ = s.skillTree.Allocate(skill, s.heroStats.Level)
+	}
+
+	return rightMenuRect.IsInRect(x, y)
+}
+
+// Render draws the current tab, if the panel is open, with the description of the skill under the cursor.
+func (s *SkillTreePanel) Render(target d2interface.Surface) {
+	if !s.isOpen {
+		return
+	}
+
+	if s.background != nil {
+		s.background.SetPosition(skillTreeX, skillTreeY)
+		_ = s.background.RenderSegmented(target, 2, 2, (s.tab-1)*skillPanelSegments)
+	}
+
+	s.pointLabel.SetText(fmt.Sprintf("Points: %d", s.skillTree.SkillPoints))
+	s.pointLabel.Render(target)
+
+	for _, skill := range s.skillTree.Skills(s.tab) {
+		s.renderSkill(target, skill)
+	}
+
+	mx, my := rightMenuScaling().cursorPosition()
+	if skill := s.skillAt(mx, my); skill != nil {
+		s.renderTooltip(target, skill, mx, my)
+	}
+}
+
+// renderSkill draws the icon of the skill with its level, darkened if no points can be spent on it.
+func (s *SkillTreePanel) renderSkill(target d2interface.Surface, skill *d2datadict.SkillRecord) {
+	desc := d2hero.SkillDescription(skill)
+	rect := skillIconRect(desc)
+	level := s.skillTree.Level(skill)
+
+	if s.icons != nil {
+		_ = s.icons.SetCurrentFrame(desc.IconCel)
+		s.icons.SetPosition(rect.Left, rect.Top+rect.Height)
+		_ = s.icons.Render(target)
+	}
+
+	if level == 0 && s.skillTree.CanAllocate(skill, s.heroStats.Level) != nil {
+		target.PushTranslation(rect.Left, rect.Top)
+		target.DrawRect(rect.Width, rect.Height, skillUnavailableColor)
+		target.Pop()
+	}
+
+	s.levelLabel.SetText(fmt.Sprintf("%d", level))
+	s.levelLabel.SetPosition(rect.Left+rect.Width+8, rect.Top+rect.Height-8)
+	s.levelLabel.Render(target)
+}
+
+// renderTooltip draws the name and description of the skill with the values of its current and next level.
+func (s *SkillTreePanel) renderTooltip(target d2interface.Surface, skill *d2datadict.SkillRecord, x, y int) {
+	desc := d2hero.SkillDescription(skill)
+	level := s.skillTree.Level(skill)
+
+	lines := []string{
+		d2common.TranslateString(desc.NameKey),
+		d2common.TranslateString(desc.LongKey),
+		fmt.Sprintf("Required level: %d", skill.Reqlevel),
+	}
+
+	if level > 0 {
+		lines = append(lines, fmt.Sprintf("Current level: %d", level),
+			fmt.Sprintf("Mana cost: %d", skill.ManaCost(level)))
+	}
+
+	lines = append(lines, fmt.Sprintf("Next level: %d", level+1),
+		fmt.Sprintf("Mana cost: %d", skill.ManaCost(level+1)))
+
+	if err := s.skillTree.CanAllocate(skill, s.heroStats.Level); err != nil {
+		lines = append(lines, err.Error())
+	}
+
+	s.tooltip.SetText(strings.Join(lines, "\n"))
+	w, _ := s.tooltip.GetSize()
+	scaling := rightMenuScaling()
+
+	// Keep the tooltip on the panel, left of the cursor
+	x -= int(float64(w+tooltipPadding*2)*tooltipScale(scaling)) + tooltipOffset
+
+	renderTooltipBox(target, &s.tooltip, x, y, scaling)
+}
@@ -0,0 +1,96 @@
+package d2player
+
+import (
+	"math"
+	"sort"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+// tabTargetRange is how far, in tiles, a monster can be from the player to be selected with the tab target key.
+const tabTargetRange = 15.0
+
+// tabTargets returns the living hostile monsters within tab target range, nearest first.
+func (g *GameControls) tabTargets() []*d2mapentity.NPC {
+	heroPosition := g.hero.Position.World()
+	distances := make(map[*d2mapentity.NPC]float64)
+
+	var targets []*d2mapentity.NPC
+
+	for _, entity := range g.mapRenderer.EntitiesNear(heroPosition.X(), heroPosition.Y(), tabTargetRange) {
+		npc, ok := entity.(*d2mapentity.NPC)
+		if !ok || !npc.IsHostile() || npc.Life() <= 0 {
+			continue
+		}
+
+		x, y := npc.GetPositionF()
+		distances[npc] = math.Hypot(x-heroPosition.X(), y-heroPosition.Y())
+		targets = append(targets, npc)
+	}
+
+	sort.SliceStable(targets, func(i, j int) bool {
+		return distances[targets[i]] < distances[targets[j]]
+	})
+
+	return targets
+}
+
+// cycleTarget selects the nearest monster, or the next nearest one after the current target on repeated presses,
+// wrapping around to the nearest.
+func (g *GameControls) cycleTarget() {
+	targets := g.tabTargets()
+	if len(targets) == 0 {
+		g.tabTarget = nil
+		return
+	}
+
+	next := 0
+
+	for i, npc := range targets {
+		if npc == g.tabTarget {
+			next = (i + 1) % len(targets)
+			break
+		}
+	}
+
+	g.tabTarget = targets[next]
+}
+
+// validateTarget clears the selected target once it died or went out of range.
+func (g *GameControls) validateTarget() {
+	if g.tabTarget == nil {
+		return
+	}
+
+	for _, npc := range g.tabTargets() {
+		if npc == g.tabTarget {
+			return
+		}
+	}
+
+	g.tabTarget = nil
+}
+
+// targetPosition returns the world position of the selected target, or the given position if there is none. Skills
+// are aimed at the selected target rather than the cursor.
+func (g *GameControls) targetPosition(px, py float64) (x, y float64) {
+	if g.tabTarget == nil {
+		return px, py
+	}
+
+	return g.tabTarget.GetPositionF()
+}
+
+// renderTabTarget outlines the selected target and draws its nameplate. Returns true if there is a target.
+func (g *GameControls) renderTabTarget(target d2interface.Surface) bool {
+	if g.tabTarget == nil {
+		return false
+	}
+
+	screenX, screenY := g.mapRenderer.WorldToScreenF(g.tabTarget.GetPositionF())
+	g.renderNameplate(target, g.tabTarget, int(math.Floor(screenX)), int(math.Floor(screenY)))
+	g.tabTarget.Highlight()
+
+	return true
+}
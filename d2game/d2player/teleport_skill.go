@@ -0,0 +1,61 @@
+package d2player
+
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+)
+
+const (
+	// teleportSkillName is the skills.txt name of the sorceress teleport skill.
+	teleportSkillName = "Teleport"
+
+	// teleportSkillRange is the farthest, in tiles, the player can teleport. Like Diablo II, the path there doesn't
+	// matter, the player passes through walls.
+	teleportSkillRange = 15
+)
+
+func isTeleportSkill(skill *d2datadict.SkillRecord) bool {
+	return skill.Skill == teleportSkillName
+}
+
+// canTeleportTo returns true if the given world position is within teleport range of the player and walkable.
+func (g *GameControls) canTeleportTo(x, y float64) bool {
+	position := g.hero.Position.World()
+
+	if math.Hypot(x-position.X(), y-position.Y()) > teleportSkillRange {
+		return false
+	}
+
+	return g.mapEngine.IsWalkable(x, y)
+}
+
+// castTeleport moves the player to the given world position, playing the teleport cast animation and sound. Nothing
+// happens, and no mana is spent, if the destination is out of range or not walkable.
+func (g *GameControls) castTeleport(skill *d2datadict.SkillRecord, x, y float64) bool {
+	if !g.canTeleportTo(x, y) || !g.hero.UseSkill(skill) {
+		return false
+	}
+
+	g.hero.SetCasting()
+	g.playSkillSound(skill)
+	g.hero.SetPosition(x*5, y*5)
+	g.inputListener.OnPlayerMove(x, y)
+
+	return true
+}
+
+// playSkillSound plays the start sound of the skill, if it has one.
+func (g *GameControls) playSkillSound(skill *d2datadict.SkillRecord) {
+	if g.audioProvider == nil {
+		return
+	}
+
+	if _, ok := d2datadict.Sounds[skill.Stsound]; !ok {
+		return
+	}
+
+	if sfx, err := g.audioProvider.LoadSoundEffect(skill.Stsound); err == nil {
+		sfx.Play()
+	}
+}
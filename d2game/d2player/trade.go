@@ -0,0 +1,10 @@
+package d2player
+
+// TradeItem is an item of a trade, at its slot in the inventory of the player giving or receiving it. There is no
+// trade window yet: the server only checks the steps of a trade, as it doesn't keep the inventories it would swap the
+// items between.
+type TradeItem struct {
+	Code string `json:"code"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+}
@@ -0,0 +1,117 @@
+package d2player
+
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2ui"
+)
+
+// uiScalingDepth is the number of surface states pushed by uiScaling.push.
+const uiScalingDepth = 2
+
+// uiScale returns the configured scale factor of the group of UI elements.
+func uiScale(group d2config.UIScaleGroup) float64 {
+	if d2config.Config == nil {
+		return 1
+	}
+
+	return d2config.Config.UIScale.Get(group)
+}
+
+// setUIScale changes the scale factor of the named UI group and saves it in the configuration.
+func setUIScale(term d2interface.Terminal, groupName string, factor float64) {
+	group, err := d2config.ParseUIScaleGroup(groupName)
+	if err != nil {
+		term.OutputErrorf("%v", err)
+		return
+	}
+
+	if err := d2config.Config.UIScale.Set(group, factor); err != nil {
+		term.OutputErrorf("%v", err)
+		return
+	}
+
+	if err := d2config.Config.Save(); err != nil {
+		term.OutputErrorf("failed to save the UI scale: %v", err)
+		return
+	}
+
+	term.OutputInfof("%s scale set to %v", groupName, factor)
+}
+
+// uiScaling scales a group of UI elements about an origin, the corner of the screen the group is attached to. The
+// elements keep being laid out in the coordinates of the original 800x600 UI.
+type uiScaling struct {
+	originX int
+	originY int
+	factor  float64
+}
+
+// hudScalingFor scales the bottom HUD about the bottom left corner of a screen of the given height.
+func hudScalingFor(screenHeight int) uiScaling {
+	return uiScaling{originX: 0, originY: screenHeight, factor: uiScale(d2config.UIScaleHUD)}
+}
+
+// leftMenuScaling scales the panels on the left of the screen about its top left corner.
+func leftMenuScaling() uiScaling {
+	return uiScaling{originX: leftMenuRect.Left, originY: leftMenuRect.Top, factor: uiScale(d2config.UIScaleMenus)}
+}
+
+// rightMenuScaling scales the panels on the right of the screen about the top right corner of the original UI.
+func rightMenuScaling() uiScaling {
+	return uiScaling{
+		originX: rightMenuRect.Left + rightMenuRect.Width,
+		originY: rightMenuRect.Top,
+		factor:  uiScale(d2config.UIScaleMenus),
+	}
+}
+
+// push makes everything drawn on the target afterwards scaled. Pop uiScalingDepth states to undo it.
+func (s uiScaling) push(target d2interface.Surface) {
+	x, y := s.toScreen(0, 0)
+	target.PushTranslation(x, y)
+	target.PushScale(s.factor)
+}
+
+// toScreen converts a position in the coordinates of the scaled elements to a screen position.
+func (s uiScaling) toScreen(x, y int) (screenX, screenY int) {
+	screenX = s.originX + int(math.Round(float64(x-s.originX)*s.factor))
+	screenY = s.originY + int(math.Round(float64(y-s.originY)*s.factor))
+
+	return screenX, screenY
+}
+
+// toLocal converts a screen position, such as the cursor's, to the coordinates of the scaled elements.
+func (s uiScaling) toLocal(screenX, screenY int) (x, y int) {
+	x = s.originX + int(math.Floor(float64(screenX-s.originX)/s.factor))
+	y = s.originY + int(math.Floor(float64(screenY-s.originY)/s.factor))
+
+	return x, y
+}
+
+// cursorPosition returns the cursor position in the coordinates of the scaled elements.
+func (s uiScaling) cursorPosition() (x, y int) {
+	return s.toLocal(d2ui.CursorPosition())
+}
+
+// renderTooltipBox draws the label on a tooltip background with its top left corner at the given position. Tooltips
+// have their own scale factor, whatever the scale of the panel they're drawn on.
+func renderTooltipBox(target d2interface.Surface, label *d2ui.Label, x, y int, panelScaling uiScaling) {
+	w, h := label.GetSize()
+
+	target.PushTranslation(x, y)
+	target.PushScale(tooltipScale(panelScaling))
+	target.DrawRect(w+tooltipPadding*2, h+tooltipPadding*2, tooltipBackgroundColor)
+
+	label.SetPosition(tooltipPadding, tooltipPadding)
+	label.Render(target)
+
+	target.PopN(uiScalingDepth)
+}
+
+// tooltipScale returns the factor tooltips drawn on a panel are scaled by, relative to the panel.
+func tooltipScale(panelScaling uiScaling) float64 {
+	return uiScale(d2config.UIScaleTooltips) / panelScaling.factor
+}
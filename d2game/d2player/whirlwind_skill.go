@@ -0,0 +1,225 @@
+package d2player
+
+import (
+	"math/rand"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2combat"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+const (
+	// whirlwindSkillName is the skills.txt name of the barbarian whirlwind skill.
+	whirlwindSkillName = "Whirlwind"
+
+	// whirlwindStrikeInterval is the time between two strikes of a whirlwind, 4 frames like Diablo II.
+	whirlwindStrikeInterval = 4.0 / 25.0
+
+	// skillFramesPerSecond converts the skills.txt durations, in frames, to seconds.
+	skillFramesPerSecond = 25.0
+
+	// whirlwindStrikeRadius is how close, in tiles, a monster must be to the player to be struck by the whirlwind.
+	whirlwindStrikeRadius = 2.0
+)
+
+// whirlwindState is the whirlwind the player is holding.
+type whirlwindState struct {
+	skill       *d2datadict.SkillRecord
+	strikeTimer float64
+}
+
+func isWhirlwindSkill(skill *d2datadict.SkillRecord) bool {
+	return skill.Skill == whirlwindSkillName
+}
+
+// onWhirlwindInput starts the whirlwind towards the cursor when the skill slot is pressed, steers it while the slot is
+// held and stops striking once it is released. The whirlwind drains mana like a channeled skill.
+func (g *GameControls) onWhirlwindInput(skill *d2datadict.SkillRecord, phase skillInputPhase, px, py float64) {
+	switch phase {
+	case skillInputPress:
+		if !g.hero.StartChanneling(skill) {
+			return
+		}
+
+		g.whirlwind = &whirlwindState{skill: skill}
+		g.inputListener.OnPlayerMove(px, py)
+	case skillInputHold:
+		if g.whirlwind != nil {
+			g.inputListener.OnPlayerMove(px, py)
+		}
+	case skillInputRelease:
+		g.stopWhirlwind()
+	}
+}
+
+// stopWhirlwind ends the whirlwind, if any. The player finishes the current move without striking.
+func (g *GameControls) stopWhirlwind() {
+	if g.whirlwind == nil {
+		return
+	}
+
+	g.hero.StopChanneling()
+	g.whirlwind = nil
+}
+
+// advanceWhirlwind strikes the monsters around the player at every whirlwind interval, and ends the whirlwind once the
+// player runs out of mana.
+func (g *GameControls) advanceWhirlwind(elapsed float64) {
+	if g.whirlwind == nil {
+		return
+	}
+
+	if !g.hero.IsChanneling(g.whirlwind.skill) {
+		g.stopWhirlwind()
+		return
+	}
+
+	g.whirlwind.strikeTimer -= elapsed
+
+	for g.whirlwind.strikeTimer <= 0 {
+		g.whirlwind.strikeTimer += whirlwindStrikeInterval
+		g.whirlwindStrike(g.whirlwind.skill)
+	}
+}
+
+// whirlwindStrike hits every hostile monster within the whirlwind radius of the player once, granting the experience of
+// the monsters it kills.
+func (g *GameControls) whirlwindStrike(skill *d2datadict.SkillRecord) {
+	heroPosition := g.hero.Position.World()
+
+	for _, entity := range g.mapRenderer.EntitiesNear(heroPosition.X(), heroPosition.Y(), whirlwindStrikeRadius) {
+		npc, ok := entity.(*d2mapentity.NPC)
+		if !ok || !npc.IsHostile() {
+			continue
+		}
+
+		damage, reflected := g.strikeDamage(npc, skill)
+		if reflected > 0 {
+			g.hero.TakeDamage(reflected)
+			g.flashHit(g.hero)
+			g.hero.GetHit(reflected, npc.Position.X(), npc.Position.Y(), false, g.mapEngine.IsWalkable)
+		}
+
+		if damage == 0 {
+			continue
+		}
+
+		wasAlive := npc.Life() > 0
+		npc.TakeDamage(damage)
+		g.flashHit(npc)
+		npc.GetHit(damage, npc.MaxLife(), g.hero.Position.X(), g.hero.Position.Y(), skillKnocksBack(skill),
+			g.mapEngine.IsWalkable)
+
+		if npc.IsBoss() && npc.Life() > 0 && !npc.IsAggroed() && !npc.IsReturning() {
+			g.playBossStinger()
+		}
+
+		npc.Aggro()
+
+		if wasAlive && npc.Life() == 0 {
+			g.hero.Stats.Experience += npc.Experience()
+			g.pushCorpse(npc)
+		}
+	}
+}
+
+// strikeDamage rolls the physical and elemental damage of the skill and returns the damage the monster takes once its
+// resistances and defenses reduced them, and the damage its thorns reflect back to the hero. The hero leeches the
+// physical damage dealt, which may crush, open wounds and freeze, cold damage chills for the skill's elemental length
+// and poison damage is dealt over time instead.
+func (g *GameControls) strikeDamage(npc *d2mapentity.NPC, skill *d2datadict.SkillRecord) (damage, reflected int) {
+	attack := d2combat.Attack{
+		Attacker: g.hero,
+		Defender: npc,
+		Element:  d2enum.DamagePhysical,
+		Damage:   rollSkillDamage(skill),
+		Melee:    true,
+	}
+
+	hit := attack.Resolve()
+	damage = hit.Damage + attack.CrushingBlow(hit)
+	attack.Freeze(hit, d2enum.DifficultyNormal)
+
+	if bleeding, ok := attack.OpenWounds(hit); ok {
+		npc.Bleed(bleeding, d2combat.OpenWoundsDuration)
+	}
+
+	// Difficulties can't be chosen yet
+	g.hero.Restore(attack.Leech(hit, d2enum.DifficultyNormal))
+
+	if reflection, ok := attack.Reflection(hit); ok {
+		reflected = reflection.Resolve().Damage
+	}
+
+	if element, ok := d2combat.SkillElement(skill); ok {
+		elemental := d2combat.Attack{
+			Attacker: g.hero,
+			Defender: npc,
+			Element:  element,
+			Damage:   rollDamage(skill.EMin, skill.EMax),
+			Melee:    true,
+		}
+		elementalHit := elemental.Resolve()
+
+		if element == d2enum.DamagePoison {
+			npc.Poison(elementalHit.Damage, float64(skill.ELen)/skillFramesPerSecond)
+		} else {
+			damage += elementalHit.Damage
+			elemental.Chill(elementalHit, skill.ELen, d2enum.DifficultyNormal)
+		}
+	}
+
+	return damage, reflected
+}
+
+// skillKnocksBack rolls the knockback chance of the skill's server missile in missiles.txt, as skills.txt has no
+// knockback column of its own. Skills without a server missile, such as whirlwind, never knock back.
+func skillKnocksBack(skill *d2datadict.SkillRecord) bool {
+	if skill.Srvmissile == "" {
+		return false
+	}
+
+	for _, missile := range d2datadict.Missiles {
+		if missile.Name == skill.Srvmissile {
+			return rand.Intn(100) < missile.KnockbackPercent //nolint:gosec // knockback rolls don't need crypto rand
+		}
+	}
+
+	return false
+}
+
+// rollSkillDamage returns a random amount of damage between the skill's minimum and maximum damage, at least 1.
+func rollSkillDamage(skill *d2datadict.SkillRecord) int {
+	minDamage := skill.MinDam
+
+	if minDamage < 1 {
+		minDamage = 1
+	}
+
+	return rollDamage(minDamage, skill.MaxDam)
+}
+
+// rollDamage returns a random amount of damage between minDamage and maxDamage.
+func rollDamage(minDamage, maxDamage int) int {
+	if maxDamage < minDamage {
+		return minDamage
+	}
+
+	return minDamage + rand.Intn(maxDamage-minDamage+1) //nolint:gosec // damage rolls don't need crypto rand
+}
+
+// playBossStinger plays the stinger of the region the hero is in over the music, as a boss engages the hero.
+func (g *GameControls) playBossStinger() {
+	heroTile := g.hero.Position.Tile()
+
+	tile, ok := g.mapEngine.TileAt(int(heroTile.X()), int(heroTile.Y()))
+	if !ok {
+		return
+	}
+
+	if stinger := d2common.GetBossStinger(tile.RegionType); stinger != "" {
+		g.audioProvider.PlayStinger(stinger)
+	}
+}
@@ -1,6 +1,9 @@
 package d2localclient
 
 import (
+	"log"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
 	"github.com/OpenDiablo2/OpenDiablo2/d2game/d2player"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2client/d2clientconnectiontype"
@@ -45,10 +48,17 @@ func Create(openNetworkServer bool) *LocalClientConnection {
 	return result
 }
 
-// Open creates a new GameServer, runs the server and connects this client to it.
+// Open creates a new GameServer, runs the server and connects this client to it. The game resumes the level state
+// saved with the character, if it can still be restored.
 func (l *LocalClientConnection) Open(_ string, saveFilePath string) error {
 	l.SetPlayerState(d2player.LoadPlayerState(saveFilePath))
-	d2server.Create(l.openNetworkServer)
+
+	levelState, err := d2mapengine.LoadLevelState(d2mapengine.LevelStatePath(saveFilePath))
+	if err != nil {
+		log.Printf("failed to load the level state: %v", err)
+	}
+
+	d2server.Create(l.openNetworkServer, levelState)
 
 	go d2server.Run()
 	d2server.OnClientConnected(l)
@@ -262,6 +262,46 @@ func (r *RemoteClientConnection) decodeToPacket(t d2netpackettype.NetPacketType,
 
 		np = d2netpacket.NetPacket{PacketType: t, PacketData: p}
 
+	case d2netpackettype.DropItem:
+		var p d2netpacket.DropItemPacket
+		if err = json.Unmarshal([]byte(data), &p); err != nil {
+			break
+		}
+
+		np = d2netpacket.NetPacket{PacketType: t, PacketData: p}
+
+	case d2netpackettype.ItemPickedUp:
+		var p d2netpacket.ItemPickedUpPacket
+		if err = json.Unmarshal([]byte(data), &p); err != nil {
+			break
+		}
+
+		np = d2netpacket.NetPacket{PacketType: t, PacketData: p}
+
+	case d2netpackettype.Trade:
+		var p d2netpacket.TradePacket
+		if err = json.Unmarshal([]byte(data), &p); err != nil {
+			break
+		}
+
+		np = d2netpacket.NetPacket{PacketType: t, PacketData: p}
+
+	case d2netpackettype.LevelState:
+		var p d2netpacket.LevelStatePacket
+		if err = json.Unmarshal([]byte(data), &p); err != nil {
+			break
+		}
+
+		np = d2netpacket.NetPacket{PacketType: t, PacketData: p}
+
+	case d2netpackettype.RemovePlayer:
+		var p d2netpacket.RemovePlayerPacket
+		if err = json.Unmarshal([]byte(data), &p); err != nil {
+			break
+		}
+
+		np = d2netpacket.NetPacket{PacketType: t, PacketData: p}
+
 	default:
 		err = fmt.Errorf("RemoteClientConnection: unrecognized packet type: %v", t)
 	}
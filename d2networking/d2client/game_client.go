@@ -8,6 +8,8 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2logger"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapgen"
@@ -31,7 +33,15 @@ type GameClient struct {
 	PlayerId         string                         // ID of the local player
 	Players          map[string]*d2mapentity.Player // IDs of the other players
 	Seed             int64                          // Map seed
+	Region           d2enum.RegionIdType            // Region of the generated map
 	RegenMap         bool                           // Regenerate tile cache on render (map has changed)
+	itemListener     ItemListener
+	desyncCheckTicks uint64 // Ticks between the world hashes sent to the server, 0 to send none
+}
+
+// ItemListener is told about the items the local player picked up from the ground.
+type ItemListener interface {
+	OnItemPickedUp(item *d2mapentity.GroundItem)
 }
 
 // Create constructs a new GameClient and returns a pointer to it.
@@ -54,9 +64,25 @@ func Create(connectionType d2clientconnectiontype.ClientConnectionType, scriptEn
 		return nil, fmt.Errorf("unknown client connection type specified: %d", connectionType)
 	}
 	result.clientConnection.SetClientListener(result)
+
+	if d2config.Config != nil {
+		// TODO: the difficulty of the game, once it can be chosen
+		result.MapEngine.SetEntityLimits(d2config.Config.EntityLimits, d2enum.DifficultyNormal)
+	}
+
 	return result, nil
 }
 
+// IsSinglePlayer returns true if the client is playing a local game that nobody else can join.
+func (g *GameClient) IsSinglePlayer() bool {
+	return g.connectionType == d2clientconnectiontype.Local
+}
+
+// IsHost returns true if the client runs the game server, for a local game or one other players can join.
+func (g *GameClient) IsHost() bool {
+	return g.connectionType != d2clientconnectiontype.LANClient
+}
+
 // Open creates the server and connects to it if the client is local.
 // If the client is remote it sends a PlayerConnectionRequestPacket to the
 // server (see d2netpacket).
@@ -65,6 +91,9 @@ func (g *GameClient) Open(connectionString string, saveFilePath string) error {
 	case d2clientconnectiontype.LANServer, d2clientconnectiontype.Local:
 		g.scriptEngine.AllowEval()
 	}
+
+	g.GameState = d2player.LoadPlayerState(saveFilePath)
+
 	return g.clientConnection.Open(connectionString, saveFilePath)
 }
 
@@ -83,62 +112,88 @@ func (g *GameClient) Destroy() error {
 	return g.Close()
 }
 
+// SetItemListener sets the listener told about the items the local player picks up.
+func (g *GameClient) SetItemListener(listener ItemListener) {
+	g.itemListener = listener
+}
+
 // OnPacketReceived is called by the ClientConection and processes incoming
 // packets.
 func (g *GameClient) OnPacketReceived(packet d2netpacket.NetPacket) error {
 	switch packet.PacketType {
 	case d2netpackettype.GenerateMap:
-		mapData := packet.PacketData.(d2netpacket.GenerateMapPacket)
-		switch mapData.RegionType {
-		case d2enum.RegionAct1Town:
-			d2mapgen.GenerateAct1Overworld(g.MapEngine)
+		if err := g.generateMap(packet.PacketData.(d2netpacket.GenerateMapPacket).RegionType); err != nil {
+			return err
 		}
-		g.RegenMap = true
 	case d2netpackettype.UpdateServerInfo:
 		serverInfo := packet.PacketData.(d2netpacket.UpdateServerInfoPacket)
 		g.MapEngine.SetSeed(serverInfo.Seed)
 		g.PlayerId = serverInfo.PlayerId
 		g.Seed = serverInfo.Seed
-		log.Printf("Player id set to %s", serverInfo.PlayerId)
+		d2logger.Infof(d2logger.CategoryNet, "Player id set to %s", serverInfo.PlayerId)
 	case d2netpackettype.AddPlayer:
 		player := packet.PacketData.(d2netpacket.AddPlayerPacket)
+		if existing, ok := g.Players[player.Id]; ok {
+			// The local player entered another level, their entity is kept as the game controls are bound to it
+			existing.SetPosition(float64(player.X), float64(player.Y))
+			g.MapEngine.AddEntity(existing)
+
+			break
+		}
+
 		newPlayer := d2mapentity.CreatePlayer(player.Id, player.Name, player.X, player.Y, 0, player.HeroType, player.Stats, player.Equipment)
 		g.Players[newPlayer.Id] = newPlayer
 		g.MapEngine.AddEntity(newPlayer)
 	case d2netpackettype.MovePlayer:
 		movePlayer := packet.PacketData.(d2netpacket.MovePlayerPacket)
 		player := g.Players[movePlayer.PlayerId]
-		path, _, _ := g.MapEngine.PathFind(movePlayer.StartX, movePlayer.StartY, movePlayer.DestX, movePlayer.DestY)
+		if player == nil {
+			// The player left the level before the move arrived
+			break
+		}
+
+		done := func() {
+			tilePosition := player.Position.Tile()
+			tile, ok := g.MapEngine.TileAt(int(tilePosition.X()), int(tilePosition.Y()))
+			if !ok {
+				return
+			}
+
+			regionType := tile.RegionType
+			if regionType == d2enum.RegionAct1Town {
+				player.SetIsInTown(true)
+			} else {
+				player.SetIsInTown(false)
+			}
+			err := player.SetAnimationMode(player.GetAnimationMode())
+			if err != nil {
+				d2logger.Errorf(d2logger.CategoryNet, "GameClient: error setting animation mode for player %s: %s", player.Id, err)
+			}
+		}
+
+		// No-clip movement goes straight to the destination, through anything in the way
+		if player.IsNoClip() {
+			player.ClearPath()
+			player.SetTarget(movePlayer.DestX*5, movePlayer.DestY*5, done)
+			break
+		}
+
+		path, _, _ := g.MapEngine.CachedPathFind(movePlayer.StartX, movePlayer.StartY, movePlayer.DestX,
+			movePlayer.DestY)
 		if len(path) > 0 {
-			player.SetPath(path, func() {
-				tilePosition := player.Position.Tile()
-				tile := g.MapEngine.TileAt(int(tilePosition.X()), int(tilePosition.Y()))
-				if tile == nil {
-					return
-				}
-
-				regionType := tile.RegionType
-				if regionType == d2enum.RegionAct1Town {
-					player.SetIsInTown(true)
-				} else {
-					player.SetIsInTown(false)
-				}
-				err := player.SetAnimationMode(player.GetAnimationMode())
-				if err != nil {
-					log.Printf("GameClient: error setting animation mode for player %s: %s", player.Id, err)
-				}
-			})
+			player.SetPath(path, done)
 		}
 	case d2netpackettype.CastSkill:
 		playerCast := packet.PacketData.(d2netpacket.CastPacket)
 		player := g.Players[playerCast.SourceEntityID]
-		player.SetCasting()
-		player.ClearPath()
 		// currently hardcoded to missile skill
+		missileRecord := d2datadict.Missiles[playerCast.SkillID]
+		player.SetSkillAnimation(d2datadict.GetSkillByMissile(missileRecord.Name))
+		player.ClearPath()
 		missile, err := d2mapentity.CreateMissile(
 			int(player.Position.X()),
 			int(player.Position.Y()),
-			d2datadict.Missiles[playerCast.SkillID],
+			missileRecord,
 		)
 		if err != nil {
 			return err
@@ -155,18 +210,38 @@ func (g *GameClient) OnPacketReceived(packet d2netpacket.NetPacket) error {
 			g.MapEngine.RemoveEntity(missile)
 		})
 
-		g.MapEngine.AddEntity(missile)
+		// the missile is fired on the action frame of the cast, which comes sooner with faster cast rate
+		player.OnActionFrame(func() {
+			g.MapEngine.Spawn(missile)
+		})
+	case d2netpackettype.DropItem:
+		return g.handleDropItem(packet.PacketData.(d2netpacket.DropItemPacket))
+	case d2netpackettype.ItemPickedUp:
+		g.handleItemPickedUp(packet.PacketData.(d2netpacket.ItemPickedUpPacket))
+	case d2netpackettype.LevelState:
+		state := packet.PacketData.(d2netpacket.LevelStatePacket).State
+		if err := g.MapEngine.RestoreState(state); err != nil {
+			d2logger.Errorf(d2logger.CategoryNet, "GameClient: error restoring the level state: %s", err)
+		}
+	case d2netpackettype.RemovePlayer:
+		id := packet.PacketData.(d2netpacket.RemovePlayerPacket).PlayerID
+		if player, ok := g.Players[id]; ok {
+			g.MapEngine.RemoveEntity(player)
+			delete(g.Players, id)
+		}
+	case d2netpackettype.Trade:
+		// There is no trade window until the server keeps the inventories it could swap the items between
 	case d2netpackettype.Ping:
 		err := g.clientConnection.SendPacketToServer(d2netpacket.CreatePongPacket(g.PlayerId))
 		if err != nil {
-			log.Printf("GameClient: error responding to server ping: %s", err)
+			d2logger.Errorf(d2logger.CategoryNet, "GameClient: error responding to server ping: %s", err)
 		}
 	case d2netpackettype.PlayerDisconnectionNotification:
 		// Not implemented
-		log.Printf("RemoteClientConnection: received disconnect: %s", packet.PacketData)
+		d2logger.Infof(d2logger.CategoryNet, "RemoteClientConnection: received disconnect: %s", packet.PacketData)
 	case d2netpackettype.ServerClosed:
 		// TODO: Need to be tied into a character save and exit
-		log.Print("Server has been closed")
+		d2logger.Infof(d2logger.CategoryNet, "Server has been closed")
 		os.Exit(0)
 	default:
 		log.Fatalf("Invalid packet type: %d", packet.PacketType)
@@ -174,6 +249,117 @@ func (g *GameClient) OnPacketReceived(packet d2netpacket.NetPacket) error {
 	return nil
 }
 
+// generateMap generates the map of the region the server moved the local player to, keeping only the local player,
+// and records the act the hero is now in.
+func (g *GameClient) generateMap(region d2enum.RegionIdType) error {
+	act := d2mapgen.TownAct(region)
+	if act == 0 {
+		return fmt.Errorf("GameClient: can't generate region %d", region)
+	}
+
+	if err := d2mapgen.GenerateTown(g.MapEngine, act); err != nil {
+		return err
+	}
+
+	for id := range g.Players {
+		if id != g.PlayerId {
+			delete(g.Players, id)
+		}
+	}
+
+	if g.GameState != nil && g.GameState.Act != act {
+		g.GameState.EnterAct(act)
+	}
+
+	g.Region = region
+	g.RegenMap = true
+
+	return nil
+}
+
+// handleDropItem adds the item the server dropped to the map.
+func (g *GameClient) handleDropItem(packet d2netpacket.DropItemPacket) error {
+	var (
+		item *d2mapentity.GroundItem
+		err  error
+	)
+
+	if packet.Gold > 0 {
+		item, err = d2mapentity.CreateGroundGold(packet.X, packet.Y, packet.Gold)
+	} else {
+		item, err = d2mapentity.CreateGroundItem(packet.X, packet.Y, packet.Code)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	item.SetID(packet.ItemID)
+
+	if packet.OwnerID != "" {
+		item.Allocate(packet.OwnerID, packet.AllocatedFor)
+	}
+
+	g.MapEngine.AddEntity(item)
+
+	return nil
+}
+
+// handleItemPickedUp removes the item a player picked up from the map, and gives the local player its share of gold.
+func (g *GameClient) handleItemPickedUp(packet d2netpacket.ItemPickedUpPacket) {
+	if player, ok := g.Players[g.PlayerId]; ok {
+		player.Stats.Gold += packet.GoldShares[g.PlayerId]
+	}
+
+	for _, entity := range *g.MapEngine.Entities() {
+		item, ok := entity.(*d2mapentity.GroundItem)
+		if !ok || item.ID() != packet.ItemID {
+			continue
+		}
+
+		g.MapEngine.RemoveEntity(item)
+
+		if packet.PlayerID == g.PlayerId && g.itemListener != nil {
+			g.itemListener.OnItemPickedUp(item)
+		}
+
+		return
+	}
+}
+
+// SaveLevelState saves the state of the map's entities along with the character, so the game can be resumed for
+// a short while. Only the host saves it, the other players' maps aren't the authority.
+func (g *GameClient) SaveLevelState() error {
+	if !g.IsHost() || g.GameState == nil || g.GameState.FilePath == "" {
+		return nil
+	}
+
+	return d2mapengine.SaveLevelState(d2mapengine.LevelStatePath(g.GameState.FilePath), g.MapEngine.SaveState())
+}
+
+// SetDesyncCheck makes the client send a hash of its world to the server every given number of ticks, so the server
+// logs the first tick where the worlds of the players of a level diverge. 0 stops sending them.
+func (g *GameClient) SetDesyncCheck(ticks int) {
+	if ticks < 0 {
+		ticks = 0
+	}
+
+	g.desyncCheckTicks = uint64(ticks)
+}
+
+// CheckDesync sends the hash of the world to the server, if the desync check is on and the map was advanced the
+// number of ticks between checks since the last one.
+func (g *GameClient) CheckDesync() {
+	tick := g.MapEngine.Tick()
+	if g.desyncCheckTicks == 0 || tick%g.desyncCheckTicks != 0 {
+		return
+	}
+
+	if err := g.SendPacketToServer(d2netpacket.CreateWorldHashPacket(g.PlayerId, g.MapEngine.WorldHash())); err != nil {
+		d2logger.Errorf(d2logger.CategoryNet, "GameClient: error sending the world hash at tick %d: %s", tick, err)
+	}
+}
+
 // SendPacketToServer calls server.OnPacketReceived if the client is local.
 // If it is remote the NetPacket sent over a UDP connection to the server.
 func (g *GameClient) SendPacketToServer(packet d2netpacket.NetPacket) error {
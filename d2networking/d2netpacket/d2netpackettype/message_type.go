@@ -25,6 +25,14 @@ const (
 	Pong                                                 // Responds to a Ping packet
 	ServerClosed                                         // Sent by the local host when it has closed the server
 	CastSkill                                            // Sent by client or server, indicates entity casting skill
+	DropItem                                             // Sent by client or server, drops an item on the ground
+	PickUpItem                                           // Sent by the client, asks to pick up an item from the ground
+	ItemPickedUp                                         // Sent by the server, removes an item picked up from the ground
+	Trade                                                // Sent by client or server, a step of a trade between players
+	LevelState                                           // Sent by the server, client restores the saved map entities
+	TravelToAct                                          // Sent by the client, asks to move the player to another act
+	RemovePlayer                                         // Sent by the server, client removes a player who left the level
+	WorldHash                                            // Sent by the client, a hash of its world to find desyncs
 )
 
 func (n NetPacketType) String() string {
@@ -39,6 +47,14 @@ func (n NetPacketType) String() string {
 		Pong:                            "Pong",
 		ServerClosed:                    "ServerClosed",
 		CastSkill:                       "CastSkill",
+		DropItem:                        "DropItem",
+		PickUpItem:                      "PickUpItem",
+		ItemPickedUp:                    "ItemPickedUp",
+		Trade:                           "Trade",
+		LevelState:                      "LevelState",
+		TravelToAct:                     "TravelToAct",
+		RemovePlayer:                    "RemovePlayer",
+		WorldHash:                       "WorldHash",
 	}
 
 	return strings[n]
@@ -0,0 +1,34 @@
+package d2netpacket
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+
+// DropItemPacket contains an item dropped on the ground. It is sent by a
+// client to ask the server to drop the item for the player, and by the server
+// to add the item, with its ID and allocation, to the map of every client.
+// TODO: Need to handle being on different maps
+type DropItemPacket struct {
+	ItemID       string  `json:"itemId"`
+	PlayerID     string  `json:"playerId"`
+	Code         string  `json:"code"`
+	Gold         int     `json:"gold"`
+	X            int     `json:"x"`
+	Y            int     `json:"y"`
+	OwnerID      string  `json:"ownerId"`
+	AllocatedFor float64 `json:"allocatedFor"`
+}
+
+// CreateDropItemPacket returns a NetPacket which asks the server to drop the
+// item with the code, or the amount of gold if it isn't 0, at the given sub
+// tile position for the player.
+func CreateDropItemPacket(playerID, code string, gold, x, y int) NetPacket {
+	return NetPacket{
+		PacketType: d2netpackettype.DropItem,
+		PacketData: DropItemPacket{
+			PlayerID: playerID,
+			Code:     code,
+			Gold:     gold,
+			X:        x,
+			Y:        y,
+		},
+	}
+}
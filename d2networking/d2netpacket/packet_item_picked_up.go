@@ -0,0 +1,26 @@
+package d2netpacket
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+
+// ItemPickedUpPacket is sent by the server to every client when a player has
+// picked up an item. The clients remove the item from the ground, and each
+// player receives its share of gold.
+type ItemPickedUpPacket struct {
+	ItemID     string         `json:"itemId"`
+	PlayerID   string         `json:"playerId"`
+	GoldShares map[string]int `json:"goldShares"`
+}
+
+// CreateItemPickedUpPacket returns a NetPacket which declares an
+// ItemPickedUpPacket with the player who picked up the item and the gold each
+// player receives.
+func CreateItemPickedUpPacket(itemID, playerID string, goldShares map[string]int) NetPacket {
+	return NetPacket{
+		PacketType: d2netpackettype.ItemPickedUp,
+		PacketData: ItemPickedUpPacket{
+			ItemID:     itemID,
+			PlayerID:   playerID,
+			GoldShares: goldShares,
+		},
+	}
+}
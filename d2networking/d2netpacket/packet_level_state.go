@@ -0,0 +1,24 @@
+package d2netpacket
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// LevelStatePacket contains the saved state of the entities of the map. It is
+// sent by the server after GenerateMap when the game resumes a saved one, so
+// the client restores the monsters, items and objects as they were.
+type LevelStatePacket struct {
+	State *d2mapengine.LevelState `json:"state"`
+}
+
+// CreateLevelStatePacket returns a NetPacket which declares a
+// LevelStatePacket with the given state.
+func CreateLevelStatePacket(state *d2mapengine.LevelState) NetPacket {
+	return NetPacket{
+		PacketType: d2netpackettype.LevelState,
+		PacketData: LevelStatePacket{
+			State: state,
+		},
+	}
+}
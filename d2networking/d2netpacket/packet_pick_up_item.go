@@ -0,0 +1,22 @@
+package d2netpacket
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+
+// PickUpItemPacket is sent by a client to ask the server to let the player
+// pick up the item from the ground.
+type PickUpItemPacket struct {
+	PlayerID string `json:"playerId"`
+	ItemID   string `json:"itemId"`
+}
+
+// CreatePickUpItemPacket returns a NetPacket which declares a
+// PickUpItemPacket for the given player and item.
+func CreatePickUpItemPacket(playerID, itemID string) NetPacket {
+	return NetPacket{
+		PacketType: d2netpackettype.PickUpItem,
+		PacketData: PickUpItemPacket{
+			PlayerID: playerID,
+			ItemID:   itemID,
+		},
+	}
+}
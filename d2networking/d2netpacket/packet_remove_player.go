@@ -0,0 +1,20 @@
+package d2netpacket
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+
+// RemovePlayerPacket is sent by the server to the clients of a level
+// instance when a player leaves it.
+type RemovePlayerPacket struct {
+	PlayerID string `json:"playerId"`
+}
+
+// CreateRemovePlayerPacket returns a NetPacket which declares a
+// RemovePlayerPacket for the given player.
+func CreateRemovePlayerPacket(playerID string) NetPacket {
+	return NetPacket{
+		PacketType: d2netpackettype.RemovePlayer,
+		PacketData: RemovePlayerPacket{
+			PlayerID: playerID,
+		},
+	}
+}
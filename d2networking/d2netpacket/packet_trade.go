@@ -0,0 +1,67 @@
+package d2netpacket
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2game/d2player"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// TradePacket contains a step of a trade between two players. Clients send
+// it to the server with their own ID as PlayerID, the server sends it to the
+// players of the trade with the other player as PartnerID. Nothing changes
+// hands: the server can't complete trades until it keeps the inventories of
+// the players, so it cancels the trades both players confirmed.
+type TradePacket struct {
+	Action    d2enum.TradeAction `json:"action"`
+	PlayerID  string             `json:"playerId"`
+	PartnerID string             `json:"partnerId"`
+
+	// The items and gold given, with TradeOffer
+	Items []d2player.TradeItem `json:"items"`
+	Gold  int                  `json:"gold"`
+
+	Reason string `json:"reason"` // Why the trade was cancelled, with TradeCancel
+}
+
+// CreateTradePacket returns a NetPacket which declares a TradePacket with the
+// action between the player and the partner, for the actions without any
+// other data: TradeRequest, TradeAccept, TradeConfirm and TradeCancel.
+func CreateTradePacket(action d2enum.TradeAction, playerID, partnerID string) NetPacket {
+	return NetPacket{
+		PacketType: d2netpackettype.Trade,
+		PacketData: TradePacket{
+			Action:    action,
+			PlayerID:  playerID,
+			PartnerID: partnerID,
+		},
+	}
+}
+
+// CreateTradeOfferPacket returns a NetPacket which declares a TradePacket with
+// the items and gold the player gives.
+func CreateTradeOfferPacket(playerID, partnerID string, items []d2player.TradeItem, gold int) NetPacket {
+	return NetPacket{
+		PacketType: d2netpackettype.Trade,
+		PacketData: TradePacket{
+			Action:    d2enum.TradeOffer,
+			PlayerID:  playerID,
+			PartnerID: partnerID,
+			Items:     items,
+			Gold:      gold,
+		},
+	}
+}
+
+// CreateTradeCancelPacket returns a NetPacket which tells the player the
+// trade with the partner was cancelled, and why.
+func CreateTradeCancelPacket(playerID, partnerID, reason string) NetPacket {
+	return NetPacket{
+		PacketType: d2netpackettype.Trade,
+		PacketData: TradePacket{
+			Action:    d2enum.TradeCancel,
+			PlayerID:  playerID,
+			PartnerID: partnerID,
+			Reason:    reason,
+		},
+	}
+}
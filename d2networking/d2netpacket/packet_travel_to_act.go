@@ -0,0 +1,22 @@
+package d2netpacket
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+
+// TravelToActPacket is sent by a client to ask the server to move the
+// player to the town of another act.
+type TravelToActPacket struct {
+	PlayerID string `json:"playerId"`
+	Act      int    `json:"act"`
+}
+
+// CreateTravelToActPacket returns a NetPacket which declares a
+// TravelToActPacket for the given player and act.
+func CreateTravelToActPacket(playerID string, act int) NetPacket {
+	return NetPacket{
+		PacketType: d2netpackettype.TravelToAct,
+		PacketData: TravelToActPacket{
+			PlayerID: playerID,
+			Act:      act,
+		},
+	}
+}
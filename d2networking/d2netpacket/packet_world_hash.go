@@ -0,0 +1,26 @@
+package d2netpacket
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+)
+
+// WorldHashPacket contains the hash of a client's world at one tick. It is
+// sent by clients checking for desyncs, so the server can compare the
+// worlds of the clients in the same level.
+type WorldHashPacket struct {
+	PlayerID string                `json:"playerId"`
+	Hash     d2mapengine.WorldHash `json:"hash"`
+}
+
+// CreateWorldHashPacket returns a NetPacket which declares a
+// WorldHashPacket with the given player's world hash.
+func CreateWorldHashPacket(playerID string, hash d2mapengine.WorldHash) NetPacket {
+	return NetPacket{
+		PacketType: d2netpackettype.WorldHash,
+		PacketData: WorldHashPacket{
+			PlayerID: playerID,
+			Hash:     hash,
+		},
+	}
+}
@@ -42,6 +42,7 @@ func (c *ConnectionManager) Run() {
 	log.Print("Starting connection manager...")
 	for {
 		c.checkPeers()
+		c.gameServer.instances.prune(time.Now())
 		time.Sleep(c.interval)
 	}
 }
@@ -75,7 +76,7 @@ func (c *ConnectionManager) Recv(id string) {
 func (c *ConnectionManager) Drop(id string) {
 	c.gameServer.RWMutex.Lock()
 	defer c.gameServer.RWMutex.Unlock()
-	delete(c.gameServer.clientConnections, id)
+	removeClient(id)
 	log.Printf("%s has been disconnected...", id)
 }
 
@@ -0,0 +1,92 @@
+package d2server
+
+import (
+	"log"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+// maxHashedTicks is how many ticks of world hashes are kept, waiting for the other players of the level to report
+// theirs.
+const maxHashedTicks = 256
+
+// reportedHash is the world hash a player reported for a tick.
+type reportedHash struct {
+	player string
+	hash   d2mapengine.WorldHash
+}
+
+// desync is where a player's world diverged from another player's.
+type desync struct {
+	region    d2enum.RegionIdType
+	tick      uint64
+	subsystem string
+	player    string
+	other     string
+}
+
+// desyncDetector compares the world hashes the players of a level instance report for the same tick. Only the first
+// desync is found, the later ones follow from it.
+type desyncDetector struct {
+	hashes   map[uint64]reportedHash
+	diverged bool
+}
+
+func createDesyncDetector() *desyncDetector {
+	return &desyncDetector{hashes: make(map[uint64]reportedHash)}
+}
+
+// report records the player's world hash, and returns the desync if it differs from the hash another player reported
+// for the same tick.
+func (d *desyncDetector) report(player string, hash d2mapengine.WorldHash) (desync, bool) {
+	if d.diverged {
+		return desync{}, false
+	}
+
+	reported, ok := d.hashes[hash.Tick]
+	if !ok {
+		d.hashes[hash.Tick] = reportedHash{player: player, hash: hash}
+		d.prune(hash.Tick)
+
+		return desync{}, false
+	}
+
+	if reported.player == player {
+		return desync{}, false
+	}
+
+	subsystem := reported.hash.Diverged(hash)
+	if subsystem == "" {
+		return desync{}, false
+	}
+
+	d.diverged = true
+
+	return desync{tick: hash.Tick, subsystem: subsystem, player: player, other: reported.player}, true
+}
+
+// prune forgets the hashes more than maxHashedTicks older than the tick.
+func (d *desyncDetector) prune(tick uint64) {
+	if len(d.hashes) <= maxHashedTicks {
+		return
+	}
+
+	for reportedTick := range d.hashes {
+		if reportedTick+maxHashedTicks < tick {
+			delete(d.hashes, reportedTick)
+		}
+	}
+}
+
+// onWorldHash compares the client's world hash with the other players' of their level, and logs the first desync.
+func onWorldHash(client ClientConnection, packet d2netpacket.WorldHashPacket) {
+	found, ok := singletonServer.instances.reportWorldHash(client.GetUniqueId(), packet.Hash)
+	if !ok {
+		return
+	}
+
+	log.Printf("GameServer: desync in region %d at tick %d: the %s of player %s diverged from player %s's",
+		found.region, found.tick, found.subsystem, found.player, found.other)
+}
@@ -0,0 +1,54 @@
+package d2server
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestDesyncDetector(t *testing.T) {
+	assert := testify.New(t)
+
+	generated := 0
+	manager := createTestInstanceManager(&generated)
+
+	for _, player := range []string{"a", "b"} {
+		_, err := manager.enter(player, d2enum.RegionAct1Town)
+		assert.NoError(err)
+	}
+
+	same := d2mapengine.WorldHash{Tick: 1, Positions: 1, Combat: 2, Inventory: 3}
+
+	_, found := manager.reportWorldHash("a", same)
+	assert.False(found)
+
+	_, found = manager.reportWorldHash("b", same)
+	assert.False(found, "the worlds match")
+
+	_, found = manager.reportWorldHash("a", d2mapengine.WorldHash{Tick: 2, Positions: 1, Combat: 2, Inventory: 3})
+	assert.False(found, "nobody else reported the tick yet")
+
+	diverged, found := manager.reportWorldHash("b", d2mapengine.WorldHash{Tick: 2, Positions: 1, Combat: 5, Inventory: 6})
+	assert.True(found)
+	assert.Equal(desync{region: d2enum.RegionAct1Town, tick: 2, subsystem: "combat", player: "b", other: "a"},
+		diverged)
+
+	manager.reportWorldHash("a", d2mapengine.WorldHash{Tick: 3, Positions: 1})
+	_, found = manager.reportWorldHash("b", d2mapengine.WorldHash{Tick: 3, Positions: 2})
+	assert.False(found, "only the first desync is found")
+}
+
+func TestDesyncDetectorPrune(t *testing.T) {
+	assert := testify.New(t)
+
+	detector := createDesyncDetector()
+
+	for tick := uint64(0); tick < maxHashedTicks*2; tick++ {
+		detector.report("a", d2mapengine.WorldHash{Tick: tick})
+	}
+
+	assert.LessOrEqual(len(detector.hashes), maxHashedTicks+1)
+}
@@ -12,11 +12,11 @@ import (
 	"sync"
 	"time"
 
-	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapgen"
-
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapgen"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2logger"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
 	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2server/d2udpclientconnection"
@@ -31,8 +31,10 @@ type GameServer struct {
 	sync.RWMutex
 	clientConnections map[string]ClientConnection
 	manager           *ConnectionManager
-	mapEngines        []*d2mapengine.MapEngine
+	instances         *instanceManager
 	scriptEngine      *d2script.ScriptEngine
+	loot              *lootTable
+	trades            *tradeTable
 	udpConnection     *net.UDPConn
 	seed              int64
 	running           bool
@@ -40,12 +42,24 @@ type GameServer struct {
 
 var singletonServer *GameServer
 
-// Create constructs a new GameServer and assigns it as a singleton. It
-// also generates the initial map and entities for the server.
+// pinnedSeed is the map seed of the games created, 0 to roll a new seed for each game.
+var pinnedSeed int64
+
+// PinSeed makes the games created from now on generate their maps with the
+// seed, so every level is laid out the same from one run to the next. A
+// seed of 0 rolls a new seed for each game again.
+func PinSeed(seed int64) {
+	pinnedSeed = seed
+}
+
+// Create constructs a new GameServer and assigns it as a singleton. The
+// maps and entities of the levels are generated as players enter them.
 //
 // If openNetworkServer is true, the GameServer starts listening for UDP
-// packets.
-func Create(openNetworkServer bool) {
+// packets. If levelState isn't nil, the game resumes the saved one: the maps
+// are generated with the same seed and the first client to enter the level
+// restores the saved entities.
+func Create(openNetworkServer bool, levelState *d2mapengine.LevelState) {
 	log.Print("Creating GameServer")
 	if singletonServer != nil {
 		return
@@ -53,21 +67,43 @@ func Create(openNetworkServer bool) {
 
 	singletonServer = &GameServer{
 		clientConnections: make(map[string]ClientConnection),
-		mapEngines:        make([]*d2mapengine.MapEngine, 0),
 		scriptEngine:      d2script.CreateScriptEngine(),
+		loot:              createLootTable(),
+		trades:            createTradeTable(),
 		seed:              time.Now().UnixNano(),
 	}
 
-	singletonServer.manager = CreateConnectionManager(singletonServer)
+	if pinnedSeed != 0 {
+		singletonServer.seed = pinnedSeed
+	}
+
+	if levelState != nil && pinnedSeed != 0 && levelState.Seed != pinnedSeed {
+		log.Printf("GameServer: the saved level state isn't restored, it wasn't generated with the pinned seed")
+
+		levelState = nil
+	}
+
+	if levelState != nil {
+		singletonServer.seed = levelState.Seed
+		singletonServer.loot.restore(levelState.Items)
+	}
+
+	singletonServer.instances = createInstanceManager(singletonServer.seed, generateLevel)
+
+	if levelState != nil {
+		// Level states saved before the region was recorded are all of the act I town
+		region := levelState.Region
+		if region == d2enum.RegionNone {
+			region = d2enum.RegionAct1Town
+		}
+
+		singletonServer.instances.restore(region, levelState)
+	}
 
-	mapEngine := d2mapengine.CreateMapEngine()
-	mapEngine.SetSeed(singletonServer.seed)
-	mapEngine.ResetMap(d2enum.RegionAct1Town, 100, 100) // TODO: Mapgen - Needs levels.txt stuff
-	d2mapgen.GenerateAct1Overworld(mapEngine)
-	singletonServer.mapEngines = append(singletonServer.mapEngines, mapEngine)
+	singletonServer.manager = CreateConnectionManager(singletonServer)
 
 	singletonServer.scriptEngine.AddFunction("getMapEngines", func(call otto.FunctionCall) otto.Value {
-		val, err := singletonServer.scriptEngine.ToValue(singletonServer.mapEngines)
+		val, err := singletonServer.scriptEngine.ToValue(singletonServer.instances.mapEngines())
 		if err != nil {
 			fmt.Print(err.Error())
 		}
@@ -125,6 +161,11 @@ func runNetworkServer() {
 		}
 
 		stringData := sb.String()
+
+		if d2logger.Enabled(d2logger.LevelDebug, d2logger.CategoryNet) {
+			d2logger.Debugf(d2logger.CategoryNet, "GameServer: received %v packet from %s: %s", packetType, addr, stringData)
+		}
+
 		switch packetType {
 		case d2netpackettype.PlayerConnectionRequest:
 			packetData := d2netpacket.PlayerConnectionRequestPacket{}
@@ -148,12 +189,7 @@ func runNetworkServer() {
 				PacketData: packetData,
 			}
 
-			for _, player := range singletonServer.clientConnections {
-				err = player.SendPacketToClient(netPacket)
-				if err != nil {
-					log.Printf("GameServer: error sending %T to client %s: %s", packetData, player.GetUniqueId(), err)
-				}
-			}
+			sendToInstance(packetData.PlayerId, netPacket)
 		case d2netpackettype.Pong:
 			packetData := d2netpacket.PlayerConnectionRequestPacket{}
 			err := json.Unmarshal([]byte(stringData), &packetData)
@@ -172,6 +208,56 @@ func runNetworkServer() {
 				continue
 			}
 			log.Printf("Received disconnect: %s", packet.Id)
+		case d2netpackettype.DropItem:
+			var packet d2netpacket.DropItemPacket
+			err := json.Unmarshal([]byte(stringData), &packet)
+			if err != nil {
+				log.Printf("GameServer: error unmarshalling packet of type %T: %s", packet, err)
+				continue
+			}
+			if client, ok := singletonServer.clientConnections[packet.PlayerID]; ok {
+				onDropItem(client, packet)
+			}
+		case d2netpackettype.PickUpItem:
+			var packet d2netpacket.PickUpItemPacket
+			err := json.Unmarshal([]byte(stringData), &packet)
+			if err != nil {
+				log.Printf("GameServer: error unmarshalling packet of type %T: %s", packet, err)
+				continue
+			}
+			if client, ok := singletonServer.clientConnections[packet.PlayerID]; ok {
+				onPickUpItem(client, packet)
+			}
+		case d2netpackettype.Trade:
+			var packet d2netpacket.TradePacket
+			err := json.Unmarshal([]byte(stringData), &packet)
+			if err != nil {
+				log.Printf("GameServer: error unmarshalling packet of type %T: %s", packet, err)
+				continue
+			}
+			if client, ok := singletonServer.clientConnections[packet.PlayerID]; ok {
+				onTrade(client, packet)
+			}
+		case d2netpackettype.TravelToAct:
+			var packet d2netpacket.TravelToActPacket
+			err := json.Unmarshal([]byte(stringData), &packet)
+			if err != nil {
+				log.Printf("GameServer: error unmarshalling packet of type %T: %s", packet, err)
+				continue
+			}
+			if client, ok := singletonServer.clientConnections[packet.PlayerID]; ok {
+				onTravelToAct(client, packet)
+			}
+		case d2netpackettype.WorldHash:
+			var packet d2netpacket.WorldHashPacket
+			err := json.Unmarshal([]byte(stringData), &packet)
+			if err != nil {
+				log.Printf("GameServer: error unmarshalling packet of type %T: %s", packet, err)
+				continue
+			}
+			if client, ok := singletonServer.clientConnections[packet.PlayerID]; ok {
+				onWorldHash(client, packet)
+			}
 		}
 	}
 }
@@ -213,60 +299,50 @@ func Destroy() {
 	Stop()
 }
 
-// OnClientConnected initializes the given ClientConnection. It sends the
+// OnClientConnected initializes the given ClientConnection and enters its
+// player in the level instance of the town of their act. It sends the
 // following packets to the newly connected client: UpdateServerInfoPacket,
 // GenerateMapPacket, AddPlayerPacket.
 //
-// It also sends AddPlayerPackets for each other player entity to the new
-// player and vice versa, so all player entities exist on all clients.
+// It also sends AddPlayerPackets for each other player entity of the
+// instance to the new player and vice versa, so all player entities exist
+// on all clients of the instance.
 //
 // For more information, see d2networking.d2netpacket.
 func OnClientConnected(client ClientConnection) {
-	// Temporary position hack --------------------------------------------
-	sx, sy := singletonServer.mapEngines[0].GetStartPosition() // TODO: Another temporary hack
-	clientPlayerState := client.GetPlayerState()
-	clientPlayerState.X = sx
-	clientPlayerState.Y = sy
-	// --------------------------------------------------------------------
-
 	log.Printf("Client connected with an id of %s", client.GetUniqueId())
 	singletonServer.clientConnections[client.GetUniqueId()] = client
 	err := client.SendPacketToClient(d2netpacket.CreateUpdateServerInfoPacket(singletonServer.seed, client.GetUniqueId()))
 	if err != nil {
 		log.Printf("GameServer: error sending UpdateServerInfoPacket to client %s: %s", client.GetUniqueId(), err)
 	}
-	err = client.SendPacketToClient(d2netpacket.CreateGenerateMapPacket(d2enum.RegionAct1Town))
-	if err != nil {
-		log.Printf("GameServer: error sending GenerateMapPacket to client %s: %s", client.GetUniqueId(), err)
-	}
 
-	playerState := client.GetPlayerState()
-	createPlayerPacket := d2netpacket.CreateAddPlayerPacket(client.GetUniqueId(), playerState.HeroName, int(sx*5)+3, int(sy*5)+3,
-		playerState.HeroType, *playerState.Stats, playerState.Equipment)
-	for _, connection := range singletonServer.clientConnections {
-		err := connection.SendPacketToClient(createPlayerPacket)
-		if err != nil {
-			log.Printf("GameServer: error sending %T to client %s: %s", createPlayerPacket, connection.GetUniqueId(), err)
-		}
-		if connection.GetUniqueId() == client.GetUniqueId() {
-			continue
-		}
-
-		conPlayerState := connection.GetPlayerState()
-		err = client.SendPacketToClient(d2netpacket.CreateAddPlayerPacket(connection.GetUniqueId(), conPlayerState.HeroName,
-			int(conPlayerState.X*5)+3, int(conPlayerState.Y*5)+3, conPlayerState.HeroType, *conPlayerState.Stats, conPlayerState.Equipment))
-		if err != nil {
-			log.Printf("GameServer: error sending CreateAddPlayerPacket to client %s: %s", connection.GetUniqueId(), err)
-		}
+	// Characters saved before acts could be traveled to have no act
+	region := d2mapgen.TownRegion(client.GetPlayerState().Act)
+	if region == d2enum.RegionNone {
+		region = d2enum.RegionAct1Town
 	}
 
+	if err := enterLevel(client, region); err != nil {
+		log.Printf("GameServer: error entering client %s in region %d: %s", client.GetUniqueId(), region, err)
+	}
 }
 
 // OnClientDisconnected removes the given client from the list
 // of client connections.
 func OnClientDisconnected(client ClientConnection) {
 	log.Printf("Client disconnected with an id of %s", client.GetUniqueId())
-	delete(singletonServer.clientConnections, client.GetUniqueId())
+	removeClient(client.GetUniqueId())
+}
+
+// removeClient removes the player from the game: from the client
+// connections, their level instance, the loot allocated to them and
+// their trade.
+func removeClient(id string) {
+	delete(singletonServer.clientConnections, id)
+	singletonServer.instances.leave(id)
+	singletonServer.loot.release(id)
+	cancelTrade(id)
 }
 
 // OnPacketReceived is called by the local client to 'send' a packet to the server.
@@ -280,19 +356,19 @@ func OnPacketReceived(client ClientConnection, packet d2netpacket.NetPacket) err
 		playerState.X = packet.PacketData.(d2netpacket.MovePlayerPacket).DestX
 		playerState.Y = packet.PacketData.(d2netpacket.MovePlayerPacket).DestY
 		// ----------------------------------------------------------------
-		for _, player := range singletonServer.clientConnections {
-			err := player.SendPacketToClient(packet)
-			if err != nil {
-				log.Printf("GameServer: error sending %T to client %s: %s", packet, player.GetUniqueId(), err)
-			}
-		}
+		sendToInstance(client.GetUniqueId(), packet)
 	case d2netpackettype.CastSkill:
-		for _, player := range singletonServer.clientConnections {
-			err := player.SendPacketToClient(packet)
-			if err != nil {
-				log.Printf("GameServer: error sending %T to client %s: %s", packet, player.GetUniqueId(), err)
-			}
-		}
+		sendToInstance(client.GetUniqueId(), packet)
+	case d2netpackettype.DropItem:
+		onDropItem(client, packet.PacketData.(d2netpacket.DropItemPacket))
+	case d2netpackettype.PickUpItem:
+		onPickUpItem(client, packet.PacketData.(d2netpacket.PickUpItemPacket))
+	case d2netpackettype.Trade:
+		onTrade(client, packet.PacketData.(d2netpacket.TradePacket))
+	case d2netpackettype.TravelToAct:
+		onTravelToAct(client, packet.PacketData.(d2netpacket.TravelToActPacket))
+	case d2netpackettype.WorldHash:
+		onWorldHash(client, packet.PacketData.(d2netpacket.WorldHashPacket))
 	}
 	return nil
 }
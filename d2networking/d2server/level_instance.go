@@ -0,0 +1,269 @@
+package d2server
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapgen"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+// instanceLinger is how long an empty level instance is kept before it is disposed, so a player coming straight
+// back, or reconnecting, finds it as they left it.
+const instanceLinger = 30 * time.Second
+
+// levelGenerator generates the map and entities of a region in the map engine.
+type levelGenerator func(region d2enum.RegionIdType, mapEngine *d2mapengine.MapEngine) error
+
+// generateLevel generates the regions which can be played so far: the towns of each act, act I's with its wilderness.
+func generateLevel(region d2enum.RegionIdType, mapEngine *d2mapengine.MapEngine) error {
+	act := d2mapgen.TownAct(region)
+	if act == 0 {
+		return fmt.Errorf("no map generator for region %d", region)
+	}
+
+	return d2mapgen.GenerateTown(mapEngine, act)
+}
+
+// levelInstance is a generated level and the players in it.
+type levelInstance struct {
+	region     d2enum.RegionIdType
+	mapEngine  *d2mapengine.MapEngine
+	players    map[string]bool
+	emptySince time.Time
+	levelState *d2mapengine.LevelState // Saved state sent to the first player entering, nil once sent
+	desyncs    *desyncDetector
+}
+
+// instanceManager is the authority for the level instances of the game and the players in each of them. An instance
+// is generated when the first player enters its region, and disposed once it has been empty for instanceLinger.
+type instanceManager struct {
+	sync.Mutex
+	seed        int64
+	generate    levelGenerator
+	instances   map[d2enum.RegionIdType]*levelInstance
+	locations   map[string]*levelInstance // Instance of each player
+	levelStates map[d2enum.RegionIdType]*d2mapengine.LevelState
+}
+
+func createInstanceManager(seed int64, generate levelGenerator) *instanceManager {
+	return &instanceManager{
+		seed:        seed,
+		generate:    generate,
+		instances:   make(map[d2enum.RegionIdType]*levelInstance),
+		locations:   make(map[string]*levelInstance),
+		levelStates: make(map[d2enum.RegionIdType]*d2mapengine.LevelState),
+	}
+}
+
+// restore makes the next instance of the region resume the saved state.
+func (m *instanceManager) restore(region d2enum.RegionIdType, state *d2mapengine.LevelState) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.levelStates[region] = state
+}
+
+// enter moves the player to the instance of the region, generating it if nobody is there yet.
+func (m *instanceManager) enter(player string, region d2enum.RegionIdType) (*levelInstance, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	instance := m.instances[region]
+	if instance == nil {
+		mapEngine := d2mapengine.CreateMapEngine()
+		mapEngine.SetSeed(m.seed)
+
+		if err := m.generate(region, mapEngine); err != nil {
+			return nil, err
+		}
+
+		instance = &levelInstance{
+			region:     region,
+			mapEngine:  mapEngine,
+			players:    make(map[string]bool),
+			levelState: m.levelStates[region],
+			desyncs:    createDesyncDetector(),
+		}
+
+		delete(m.levelStates, region)
+		m.instances[region] = instance
+
+		log.Printf("GameServer: created the level instance of region %d", region)
+	}
+
+	m.leaveInstance(player, time.Now())
+
+	instance.players[player] = true
+	m.locations[player] = instance
+
+	return instance, nil
+}
+
+// leave takes the player out of their instance, which starts lingering if it is now empty.
+func (m *instanceManager) leave(player string) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.leaveInstance(player, time.Now())
+}
+
+func (m *instanceManager) leaveInstance(player string, now time.Time) {
+	instance := m.locations[player]
+	if instance == nil {
+		return
+	}
+
+	delete(instance.players, player)
+	delete(m.locations, player)
+
+	if len(instance.players) == 0 {
+		instance.emptySince = now
+	}
+}
+
+// playersWith returns the IDs of the players in the same instance as the player, the player included.
+func (m *instanceManager) playersWith(player string) []string {
+	m.Lock()
+	defer m.Unlock()
+
+	instance := m.locations[player]
+	if instance == nil {
+		return nil
+	}
+
+	players := make([]string, 0, len(instance.players))
+	for id := range instance.players {
+		players = append(players, id)
+	}
+
+	return players
+}
+
+// reportWorldHash records the player's world hash in their instance, and returns the desync if it differs from the
+// hash another player of the instance reported for the same tick.
+func (m *instanceManager) reportWorldHash(player string, hash d2mapengine.WorldHash) (desync, bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	instance := m.locations[player]
+	if instance == nil {
+		return desync{}, false
+	}
+
+	found, ok := instance.desyncs.report(player, hash)
+	found.region = instance.region
+
+	return found, ok
+}
+
+// prune disposes the instances which have been empty for longer than instanceLinger.
+func (m *instanceManager) prune(now time.Time) {
+	m.Lock()
+	defer m.Unlock()
+
+	for region, instance := range m.instances {
+		if len(instance.players) == 0 && now.Sub(instance.emptySince) > instanceLinger {
+			delete(m.instances, region)
+			log.Printf("GameServer: disposed the level instance of region %d", region)
+		}
+	}
+}
+
+// mapEngines returns the map engines of the instances.
+func (m *instanceManager) mapEngines() []*d2mapengine.MapEngine {
+	m.Lock()
+	defer m.Unlock()
+
+	engines := make([]*d2mapengine.MapEngine, 0, len(m.instances))
+	for _, instance := range m.instances {
+		engines = append(engines, instance.mapEngine)
+	}
+
+	return engines
+}
+
+// takeLevelState returns the saved state the instance resumes, only once.
+func (i *levelInstance) takeLevelState() *d2mapengine.LevelState {
+	state := i.levelState
+	i.levelState = nil
+
+	return state
+}
+
+// sendToInstance sends the packet to the players in the same level instance as the player.
+func sendToInstance(player string, packet d2netpacket.NetPacket) {
+	for _, id := range singletonServer.instances.playersWith(player) {
+		sendToClient(id, packet)
+	}
+}
+
+// enterLevel moves the client's player to the level instance of the region and sends them its map and players. The
+// players of the instance are told about the new player, and the players of the instance they left that they are gone.
+func enterLevel(client ClientConnection, region d2enum.RegionIdType) error {
+	id := client.GetUniqueId()
+	leftBehind := singletonServer.instances.playersWith(id)
+
+	instance, err := singletonServer.instances.enter(id, region)
+	if err != nil {
+		return err
+	}
+
+	for _, player := range leftBehind {
+		if player != id {
+			sendToClient(player, d2netpacket.CreateRemovePlayerPacket(id))
+		}
+	}
+
+	// Temporary position hack --------------------------------------------
+	sx, sy := instance.mapEngine.GetStartPosition() // TODO: Another temporary hack
+	playerState := client.GetPlayerState()
+	playerState.X = sx
+	playerState.Y = sy
+	// --------------------------------------------------------------------
+
+	sendToClient(id, d2netpacket.CreateGenerateMapPacket(instance.region))
+
+	if levelState := instance.takeLevelState(); levelState != nil {
+		sendToClient(id, d2netpacket.CreateLevelStatePacket(levelState))
+	}
+
+	createPlayerPacket := d2netpacket.CreateAddPlayerPacket(id, playerState.HeroName, int(sx*5)+3, int(sy*5)+3,
+		playerState.HeroType, *playerState.Stats, playerState.Equipment)
+
+	for _, player := range singletonServer.instances.playersWith(id) {
+		sendToClient(player, createPlayerPacket)
+
+		connection, ok := singletonServer.clientConnections[player]
+		if !ok || player == id {
+			continue
+		}
+
+		state := connection.GetPlayerState()
+		sendToClient(id, d2netpacket.CreateAddPlayerPacket(player, state.HeroName, int(state.X*5)+3, int(state.Y*5)+3,
+			state.HeroType, *state.Stats, state.Equipment))
+	}
+
+	return nil
+}
+
+// onTravelToAct moves the client's player to the town of the act they asked for. The client checked the hero may
+// travel there, the quest progression isn't known to the server.
+func onTravelToAct(client ClientConnection, packet d2netpacket.TravelToActPacket) {
+	region := d2mapgen.TownRegion(packet.Act)
+	if region == d2enum.RegionNone {
+		log.Printf("GameServer: client %s can't travel to act %d", client.GetUniqueId(), packet.Act)
+		return
+	}
+
+	if err := enterLevel(client, region); err != nil {
+		log.Printf("GameServer: error moving client %s to act %d: %s", client.GetUniqueId(), packet.Act, err)
+		return
+	}
+
+	client.GetPlayerState().Act = packet.Act
+}
@@ -0,0 +1,63 @@
+package d2server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func createTestInstanceManager(generated *int) *instanceManager {
+	return createInstanceManager(42, func(region d2enum.RegionIdType, _ *d2mapengine.MapEngine) error {
+		if region != d2enum.RegionAct1Town {
+			return errors.New("no generator")
+		}
+
+		*generated++
+
+		return nil
+	})
+}
+
+func TestInstanceLifecycle(t *testing.T) {
+	assert := testify.New(t)
+
+	generated := 0
+	manager := createTestInstanceManager(&generated)
+	state := &d2mapengine.LevelState{Seed: 42}
+	manager.restore(d2enum.RegionAct1Town, state)
+
+	town, err := manager.enter("a", d2enum.RegionAct1Town)
+	assert.NoError(err)
+	assert.Equal(1, generated)
+	assert.Equal(state, town.takeLevelState())
+	assert.Nil(town.takeLevelState(), "the saved state is only restored by the first player")
+
+	same, err := manager.enter("b", d2enum.RegionAct1Town)
+	assert.NoError(err)
+	assert.Equal(town, same, "players share the instance of a region")
+	assert.Equal(1, generated)
+	assert.ElementsMatch([]string{"a", "b"}, manager.playersWith("a"))
+
+	_, err = manager.enter("b", d2enum.RegionAct2Town)
+	assert.Error(err)
+	assert.ElementsMatch([]string{"a", "b"}, manager.playersWith("a"), "a failed entry doesn't move the player")
+
+	manager.leave("a")
+	manager.leave("b")
+	assert.Nil(manager.playersWith("a"))
+
+	manager.prune(time.Now())
+	assert.Len(manager.mapEngines(), 1, "an empty instance lingers")
+
+	manager.prune(time.Now().Add(instanceLinger + time.Second))
+	assert.Empty(manager.mapEngines())
+
+	_, err = manager.enter("a", d2enum.RegionAct1Town)
+	assert.NoError(err)
+	assert.Equal(2, generated, "a disposed instance is generated again")
+}
@@ -0,0 +1,144 @@
+package d2server
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket/d2netpackettype"
+	uuid "github.com/satori/go.uuid"
+)
+
+// groundLoot is an item lying on the ground, as the server knows it.
+type groundLoot struct {
+	gold   int
+	owner  string    // ID of the player the item is allocated to, empty if anyone can pick it up
+	freeAt time.Time // When the allocation ends
+}
+
+// lootTable keeps track of the items on the ground and who may pick them up, so the clients can't pick up an item
+// twice or take another player's allocated drop.
+type lootTable struct {
+	sync.Mutex
+	items map[string]*groundLoot
+}
+
+func createLootTable() *lootTable {
+	return &lootTable{items: make(map[string]*groundLoot)}
+}
+
+// drop adds the item a client asked to drop to the table. In allocated mode it belongs to the player who asked for
+// the drop until the allocation ends. Returns the packet to send to every client.
+func (t *lootTable) drop(packet d2netpacket.DropItemPacket, settings *d2config.Loot,
+	now time.Time) d2netpacket.NetPacket {
+	t.Lock()
+	defer t.Unlock()
+
+	packet.ItemID = uuid.NewV4().String()
+	item := &groundLoot{gold: packet.Gold}
+
+	if settings.GetMode() == d2config.LootModeAllocated {
+		allocation := settings.GetAllocation()
+		item.owner = packet.PlayerID
+		item.freeAt = now.Add(time.Duration(allocation * float64(time.Second)))
+		packet.OwnerID = packet.PlayerID
+		packet.AllocatedFor = allocation
+	}
+
+	t.items[packet.ItemID] = item
+
+	return d2netpacket.NetPacket{PacketType: d2netpackettype.DropItem, PacketData: packet}
+}
+
+// restore adds the items of a saved game to the table, free for anyone to pick up.
+func (t *lootTable) restore(items []d2mapengine.ItemState) {
+	t.Lock()
+	defer t.Unlock()
+
+	for _, item := range items {
+		t.items[item.ID] = &groundLoot{gold: item.Gold}
+	}
+}
+
+// pickUp removes the item from the table if the player may pick it up, and returns the gold it holds.
+func (t *lootTable) pickUp(itemID, playerID string, now time.Time) (int, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	item, ok := t.items[itemID]
+	if !ok {
+		return 0, errors.New("the item isn't on the ground")
+	}
+
+	if item.owner != "" && item.owner != playerID && now.Before(item.freeAt) {
+		return 0, errors.New("the item is allocated to another player")
+	}
+
+	delete(t.items, itemID)
+
+	return item.gold, nil
+}
+
+// release frees the items allocated to a player who left the game.
+func (t *lootTable) release(playerID string) {
+	t.Lock()
+	defer t.Unlock()
+
+	for _, item := range t.items {
+		if item.owner == playerID {
+			item.owner = ""
+		}
+	}
+}
+
+// splitGold shares the gold evenly among the players, the remainder going to the player who picked it up. Without
+// splitting, all of it goes to the player who picked it up.
+func splitGold(gold int, pickedUpBy string, players []string, split bool) map[string]int {
+	if gold <= 0 {
+		return nil
+	}
+
+	if !split || len(players) == 0 {
+		return map[string]int{pickedUpBy: gold}
+	}
+
+	share := gold / len(players)
+	shares := make(map[string]int, len(players))
+
+	for _, player := range players {
+		shares[player] = share
+	}
+
+	shares[pickedUpBy] += gold - share*len(players)
+
+	return shares
+}
+
+// onDropItem drops the item a client asked for on the ground of every client in the same level instance.
+func onDropItem(client ClientConnection, packet d2netpacket.DropItemPacket) {
+	packet.PlayerID = client.GetUniqueId()
+	dropPacket := singletonServer.loot.drop(packet, &d2config.Config.Loot, time.Now())
+
+	sendToInstance(client.GetUniqueId(), dropPacket)
+}
+
+// onPickUpItem lets the client's player pick up the item if it is still on the ground and isn't allocated to another
+// player, and removes it from the ground of every client in the same level instance, whose players share the gold.
+// Requests which aren't allowed are ignored.
+func onPickUpItem(client ClientConnection, packet d2netpacket.PickUpItemPacket) {
+	gold, err := singletonServer.loot.pickUp(packet.ItemID, client.GetUniqueId(), time.Now())
+	if err != nil {
+		log.Printf("GameServer: client %s can't pick up item %s: %s", client.GetUniqueId(), packet.ItemID, err)
+		return
+	}
+
+	players := singletonServer.instances.playersWith(client.GetUniqueId())
+	goldShares := splitGold(gold, client.GetUniqueId(), players, d2config.Config.Loot.SplitGold)
+	pickedUpPacket := d2netpacket.CreateItemPickedUpPacket(packet.ItemID, client.GetUniqueId(), goldShares)
+
+	sendToInstance(client.GetUniqueId(), pickedUpPacket)
+}
@@ -0,0 +1,68 @@
+package d2server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func dropTestItem(t *lootTable, settings *d2config.Loot, now time.Time) d2netpacket.DropItemPacket {
+	packet := d2netpacket.DropItemPacket{PlayerID: "owner", Gold: 100}
+	return t.drop(packet, settings, now).PacketData.(d2netpacket.DropItemPacket)
+}
+
+func TestLootFreeForAll(t *testing.T) {
+	assert := testify.New(t)
+
+	table := createLootTable()
+	now := time.Now()
+	item := dropTestItem(table, &d2config.Loot{}, now)
+
+	assert.NotEmpty(item.ItemID)
+	assert.Empty(item.OwnerID)
+
+	gold, err := table.pickUp(item.ItemID, "other", now)
+	assert.NoError(err)
+	assert.Equal(100, gold)
+
+	_, err = table.pickUp(item.ItemID, "owner", now)
+	assert.Error(err, "an item can only be picked up once")
+}
+
+func TestLootAllocated(t *testing.T) {
+	assert := testify.New(t)
+
+	table := createLootTable()
+	now := time.Now()
+	settings := &d2config.Loot{Mode: d2config.LootModeAllocated, Allocation: 5}
+	item := dropTestItem(table, settings, now)
+
+	assert.Equal("owner", item.OwnerID)
+	assert.Equal(5.0, item.AllocatedFor)
+
+	_, err := table.pickUp(item.ItemID, "other", now.Add(4*time.Second))
+	assert.Error(err)
+
+	_, err = table.pickUp(item.ItemID, "other", now.Add(5*time.Second))
+	assert.NoError(err, "the item is free once the allocation ends")
+
+	item = dropTestItem(table, settings, now)
+	table.release("owner")
+
+	_, err = table.pickUp(item.ItemID, "other", now)
+	assert.NoError(err, "the items of a player who left are free")
+}
+
+func TestSplitGold(t *testing.T) {
+	assert := testify.New(t)
+
+	players := []string{"a", "b", "c"}
+
+	assert.Equal(map[string]int{"a": 100}, splitGold(100, "a", players, false))
+	assert.Equal(map[string]int{"a": 33, "b": 34, "c": 33}, splitGold(100, "b", players, true))
+	assert.Nil(splitGold(0, "a", players, true))
+}
@@ -0,0 +1,218 @@
+package d2server
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2game/d2player"
+	"github.com/OpenDiablo2/OpenDiablo2/d2networking/d2netpacket"
+)
+
+// errTradeUnavailable cancels the trades both players confirmed. The server doesn't keep the inventories and gold of
+// the players yet, only their clients do, so it has nothing to check a trade against and can't swap the items and
+// gold without trusting the clients.
+var errTradeUnavailable = errors.New("trades can't be completed until the server keeps the inventories")
+
+// tradeOffer is what a player gives in a trade.
+type tradeOffer struct {
+	items     []d2player.TradeItem
+	gold      int
+	confirmed bool
+}
+
+// tradeSession is a trade between two players, from the request until the swap or a cancellation.
+type tradeSession struct {
+	players  [2]string // The player who asked for the trade, then the player asked
+	accepted bool
+	offers   [2]tradeOffer
+}
+
+// index returns 0 for the player who asked for the trade and 1 for the player asked.
+func (s *tradeSession) index(player string) int {
+	if s.players[0] == player {
+		return 0
+	}
+
+	return 1
+}
+
+// partner returns the ID of the other player of the trade.
+func (s *tradeSession) partner(player string) string {
+	return s.players[1-s.index(player)]
+}
+
+// tradeTable keeps the trades in progress, from the request until both players confirmed the same offers or one of
+// them cancelled.
+type tradeTable struct {
+	sync.Mutex
+	sessions map[string]*tradeSession // Trades by the ID of each of their players
+}
+
+func createTradeTable() *tradeTable {
+	return &tradeTable{sessions: make(map[string]*tradeSession)}
+}
+
+// request starts a trade between the player and the partner, which the partner must accept.
+func (t *tradeTable) request(player, partner string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	switch {
+	case player == partner:
+		return errors.New("you can't trade with yourself")
+	case t.sessions[player] != nil:
+		return errors.New("you are already trading")
+	case t.sessions[partner] != nil:
+		return errors.New("the player is busy trading")
+	}
+
+	session := &tradeSession{players: [2]string{player, partner}}
+	t.sessions[player] = session
+	t.sessions[partner] = session
+
+	return nil
+}
+
+// accept opens the trade the player was asked for, and returns the partner.
+func (t *tradeTable) accept(player string) (string, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	session := t.sessions[player]
+	if session == nil || session.index(player) != 1 || session.accepted {
+		return "", errors.New("no one asked you to trade")
+	}
+
+	session.accepted = true
+
+	return session.partner(player), nil
+}
+
+// offer changes what the player gives, and returns the partner. Both players must confirm again.
+func (t *tradeTable) offer(player string, items []d2player.TradeItem, gold int) (string, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	session := t.sessions[player]
+	if session == nil || !session.accepted {
+		return "", errors.New("you aren't trading")
+	}
+
+	if gold < 0 {
+		return "", errors.New("invalid amount of gold")
+	}
+
+	session.offers[session.index(player)] = tradeOffer{items: items, gold: gold}
+	session.offers[1-session.index(player)].confirmed = false
+
+	return session.partner(player), nil
+}
+
+// confirm records that the player agrees to the trade as offered, and returns the partner. Once both players have
+// confirmed, the trade ends with errTradeUnavailable.
+func (t *tradeTable) confirm(player string) (string, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	session := t.sessions[player]
+	if session == nil || !session.accepted {
+		return "", errors.New("you aren't trading")
+	}
+
+	partner := session.partner(player)
+	session.offers[session.index(player)].confirmed = true
+
+	if !session.offers[0].confirmed || !session.offers[1].confirmed {
+		return partner, nil
+	}
+
+	delete(t.sessions, session.players[0])
+	delete(t.sessions, session.players[1])
+
+	return partner, errTradeUnavailable
+}
+
+// cancel ends the player's trade, and returns the partner. Returns false if the player wasn't trading.
+func (t *tradeTable) cancel(player string) (string, bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	session := t.sessions[player]
+	if session == nil {
+		return "", false
+	}
+
+	delete(t.sessions, session.players[0])
+	delete(t.sessions, session.players[1])
+
+	return session.partner(player), true
+}
+
+// onTrade handles a step of a trade asked for by the client's player, telling the players of the trade about it.
+func onTrade(client ClientConnection, packet d2netpacket.TradePacket) {
+	player := client.GetUniqueId()
+
+	var (
+		partner string
+		err     error
+	)
+
+	switch packet.Action {
+	case d2enum.TradeRequest:
+		partner = packet.PartnerID
+		if _, ok := singletonServer.clientConnections[partner]; !ok {
+			err = errors.New("the player isn't in the game")
+			break
+		}
+
+		if err = singletonServer.trades.request(player, partner); err == nil {
+			sendToClient(partner, d2netpacket.CreateTradePacket(d2enum.TradeRequest, partner, player))
+		}
+	case d2enum.TradeAccept:
+		if partner, err = singletonServer.trades.accept(player); err == nil {
+			sendToClient(player, d2netpacket.CreateTradePacket(d2enum.TradeAccept, player, partner))
+			sendToClient(partner, d2netpacket.CreateTradePacket(d2enum.TradeAccept, partner, player))
+		}
+	case d2enum.TradeOffer:
+		if partner, err = singletonServer.trades.offer(player, packet.Items, packet.Gold); err == nil {
+			sendToClient(partner, d2netpacket.CreateTradeOfferPacket(partner, player, packet.Items, packet.Gold))
+		}
+	case d2enum.TradeConfirm:
+		partner, err = singletonServer.trades.confirm(player)
+		if err == nil {
+			sendToClient(partner, d2netpacket.CreateTradePacket(d2enum.TradeConfirm, partner, player))
+		} else if partner != "" {
+			sendToClient(partner, d2netpacket.CreateTradeCancelPacket(partner, player, err.Error()))
+		}
+	case d2enum.TradeCancel:
+		if partner, ok := singletonServer.trades.cancel(player); ok {
+			sendToClient(partner, d2netpacket.CreateTradeCancelPacket(partner, player, "the trade was cancelled"))
+			sendToClient(player, d2netpacket.CreateTradeCancelPacket(player, partner, "the trade was cancelled"))
+		}
+	}
+
+	if err != nil {
+		sendToClient(player, d2netpacket.CreateTradeCancelPacket(player, partner, err.Error()))
+	}
+}
+
+// cancelTrade ends the trade of a player who left the game, telling their partner.
+func cancelTrade(player string) {
+	if partner, ok := singletonServer.trades.cancel(player); ok {
+		sendToClient(partner, d2netpacket.CreateTradeCancelPacket(partner, player, "the player left the game"))
+	}
+}
+
+// sendToClient sends the packet to the player if they are still connected.
+func sendToClient(player string, packet d2netpacket.NetPacket) {
+	connection, ok := singletonServer.clientConnections[player]
+	if !ok {
+		return
+	}
+
+	if err := connection.SendPacketToClient(packet); err != nil {
+		log.Printf("GameServer: error sending %T to client %s: %s", packet.PacketData, player, err)
+	}
+}
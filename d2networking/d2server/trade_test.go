@@ -0,0 +1,66 @@
+package d2server
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2game/d2player"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func openTestTrade(assert *testify.Assertions) *tradeTable {
+	table := createTradeTable()
+
+	assert.NoError(table.request("a", "b"))
+	assert.Error(table.request("c", "b"), "a player can only trade with one player at a time")
+
+	_, err := table.accept("a")
+	assert.Error(err, "only the player asked can accept")
+
+	partner, err := table.accept("b")
+	assert.NoError(err)
+	assert.Equal("a", partner)
+
+	return table
+}
+
+func TestTradeConfirm(t *testing.T) {
+	assert := testify.New(t)
+
+	table := openTestTrade(assert)
+	gem := d2player.TradeItem{Code: "gem", X: 0, Y: 0}
+
+	_, err := table.offer("a", []d2player.TradeItem{gem}, 0)
+	assert.NoError(err)
+	_, err = table.offer("b", nil, 50)
+	assert.NoError(err)
+
+	partner, err := table.confirm("a")
+	assert.NoError(err, "the trade waits for both players")
+	assert.Equal("b", partner)
+
+	partner, err = table.confirm("b")
+	assert.Equal(errTradeUnavailable, err, "the server can't swap what it doesn't keep")
+	assert.Equal("a", partner)
+
+	_, ok := table.cancel("a")
+	assert.False(ok, "the trade is over")
+}
+
+func TestTradeOfferClearsConfirmation(t *testing.T) {
+	assert := testify.New(t)
+
+	table := openTestTrade(assert)
+
+	_, err := table.confirm("a")
+	assert.NoError(err)
+
+	_, err = table.offer("b", nil, 10)
+	assert.NoError(err)
+
+	_, err = table.confirm("b")
+	assert.NoError(err, "a must confirm the new offer")
+
+	_, err = table.offer("a", nil, -1)
+	assert.Error(err, "invalid amount of gold")
+}
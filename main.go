@@ -2,12 +2,16 @@ package main
 
 import (
 	"log"
+	"strings"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2app"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2logger"
 	ebiten2 "github.com/OpenDiablo2/OpenDiablo2/d2core/d2audio/ebiten"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2input"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2render/ebiten"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2render/headless"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2term"
 	"github.com/OpenDiablo2/OpenDiablo2/d2script"
 )
@@ -28,8 +32,25 @@ func main() {
 		panic(err)
 	}
 
+	logLevel, err := d2logger.ParseLevel(d2config.Config.LogLevel)
+	if err != nil {
+		log.Print(err)
+	}
+
+	logCategories := make([]d2logger.Category, len(d2config.Config.LogCategories))
+	for idx, category := range d2config.Config.LogCategories {
+		logCategories[idx] = d2logger.Category(category)
+	}
+
+	logFile, err := d2logger.Init(logLevel, logCategories, d2config.Config.LogFile)
+	if err != nil {
+		log.Printf("failed to open log file: %s", err)
+	} else {
+		defer logFile.Close()
+	}
+
 	// Initialize our providers
-	renderer, err := ebiten.CreateRenderer()
+	renderer, err := createRenderer(d2config.Config.Backend)
 	if err != nil {
 		panic(err)
 	}
@@ -52,3 +73,13 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// createRenderer creates the renderer of the configured backend. The headless backend draws in memory, without a GPU.
+func createRenderer(backend string) (d2interface.Renderer, error) {
+	if strings.EqualFold(backend, "headless") {
+		log.Println("using the headless renderer")
+		return headless.CreateRenderer()
+	}
+
+	return ebiten.CreateRenderer()
+}